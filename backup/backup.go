@@ -0,0 +1,229 @@
+// Package backup archives the data of a server, e.g., the DB snapshot,
+// config files and artwork manifests, into a single tar.gz file with the
+// checksums of all files, which can be restored onto a new host.
+package backup
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// FormatVersion is the version of the archive format.
+const FormatVersion = 1
+
+// ManifestName is the path of the manifest in archives, which is written
+// after all the files.
+const ManifestName = "manifest.json"
+
+// Manifest describes the archive.
+type Manifest struct {
+	FormatVersion int `json:"format_version"`
+	// Version is the server version that made the archive.
+	Version string `json:"version"`
+	// Migration is the last DB migration applied.
+	Migration string    `json:"migration,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Files     []*File   `json:"files"`
+}
+
+// File is a file of the archive, or of an artwork manifest.
+type File struct {
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Entry is a local file to be archived.
+type Entry struct {
+	// Path is the slash separated path in the archive.
+	Path string
+	// Name is the local file name.
+	Name string
+}
+
+// Write archives the entries with the manifest, whose files are filled.
+func Write(w io.Writer, m *Manifest, entries ...*Entry) error {
+	gw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gw)
+	m.FormatVersion = FormatVersion
+	m.Files = nil
+	for _, entry := range entries {
+		if !validPath(entry.Path) || entry.Path == ManifestName {
+			return fmt.Errorf("invalid archive path: %s", entry.Path)
+		}
+		f, err := writeFile(tw, entry)
+		if err != nil {
+			return err
+		}
+		m.Files = append(m.Files, f)
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = tw.WriteHeader(&tar.Header{
+		Name:    ManifestName,
+		Mode:    0o644,
+		Size:    int64(len(data)),
+		ModTime: m.CreatedAt,
+	}); err != nil {
+		return err
+	}
+	if _, err = tw.Write(data); err != nil {
+		return err
+	}
+	if err = tw.Close(); err != nil {
+		return err
+	}
+	return gw.Close()
+}
+
+func writeFile(tw *tar.Writer, entry *Entry) (*File, error) {
+	f, err := os.Open(entry.Name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if err = tw.WriteHeader(&tar.Header{
+		Name:    entry.Path,
+		Mode:    0o644,
+		Size:    fi.Size(),
+		ModTime: fi.ModTime(),
+	}); err != nil {
+		return nil, err
+	}
+	h := sha256.New()
+	if _, err = io.Copy(io.MultiWriter(tw, h), f); err != nil {
+		return nil, err
+	}
+	return &File{Path: entry.Path, Size: fi.Size(), SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+// Extract extracts the archive into the directory, and verifies all the
+// files with the manifest. The directory is left partially extracted if
+// the archive is broken, so extract into an empty temporary directory.
+func Extract(r io.Reader, dir string) (*Manifest, error) {
+	gr, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		tr    = tar.NewReader(gr)
+		m     *Manifest
+		files = make(map[string]*File)
+	)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag != tar.TypeReg || !validPath(hdr.Name) {
+			return nil, fmt.Errorf("invalid archive file: %s", hdr.Name)
+		}
+		if hdr.Name == ManifestName {
+			m = &Manifest{}
+			if err = json.NewDecoder(tr).Decode(m); err != nil {
+				return nil, fmt.Errorf("invalid manifest: %w", err)
+			}
+			continue
+		}
+		if files[hdr.Name], err = extractFile(tr, filepath.Join(dir, filepath.FromSlash(hdr.Name))); err != nil {
+			return nil, err
+		}
+		files[hdr.Name].Path = hdr.Name
+	}
+	if m == nil {
+		return nil, errors.New("manifest not found")
+	}
+	if m.FormatVersion != FormatVersion {
+		return nil, fmt.Errorf("unsupported archive format: %d", m.FormatVersion)
+	}
+	for _, want := range m.Files {
+		got, ok := files[want.Path]
+		if !ok {
+			return nil, fmt.Errorf("missing archive file: %s", want.Path)
+		}
+		if *got != *want {
+			return nil, fmt.Errorf("checksum mismatch: %s", want.Path)
+		}
+		delete(files, want.Path)
+	}
+	for name := range files {
+		return nil, fmt.Errorf("unknown archive file: %s", name)
+	}
+	return m, nil
+}
+
+func extractFile(r io.Reader, name string) (*File, error) {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return nil, err
+	}
+	f, err := os.Create(name)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(io.MultiWriter(f, h), r)
+	if err != nil {
+		return nil, err
+	}
+	return &File{Size: n, SHA256: hex.EncodeToString(h.Sum(nil))}, f.Close()
+}
+
+// ListFiles returns the files under the directory with checksums, e.g.,
+// as the manifest of cached artwork, paths are relative and slash
+// separated.
+func ListFiles(dir string) (files []*File, err error) {
+	err = filepath.WalkDir(dir, func(name string, d fs.DirEntry, err error) error {
+		if err != nil || !d.Type().IsRegular() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, name)
+		if err != nil {
+			return err
+		}
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		h := sha256.New()
+		n, err := io.Copy(h, f)
+		if err != nil {
+			return err
+		}
+		files = append(files, &File{
+			Path:   filepath.ToSlash(rel),
+			Size:   n,
+			SHA256: hex.EncodeToString(h.Sum(nil)),
+		})
+		return nil
+	})
+	return
+}
+
+// validPath reports whether the archive path is relative and clean, so
+// that files are never extracted out of the directory.
+func validPath(p string) bool {
+	return p != "" && p == path.Clean(p) && !path.IsAbs(p) &&
+		p != ".." && !strings.HasPrefix(p, "../") && !strings.Contains(p, `\`)
+}
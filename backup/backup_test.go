@@ -0,0 +1,82 @@
+package backup
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteExtract(t *testing.T) {
+	src := t.TempDir()
+	for name, data := range map[string]string{
+		"metatube.db":        "db",
+		"patches.json":       `{"JavBus":[]}`,
+		"images/ab/abcd.jpg": "jpg",
+	} {
+		name = filepath.Join(src, name)
+		assert.NoError(t, os.MkdirAll(filepath.Dir(name), 0o755))
+		assert.NoError(t, os.WriteFile(name, []byte(data), 0o644))
+	}
+
+	files, err := ListFiles(filepath.Join(src, "images"))
+	if assert.NoError(t, err) && assert.Len(t, files, 1) {
+		assert.Equal(t, "ab/abcd.jpg", files[0].Path)
+		assert.Equal(t, int64(3), files[0].Size)
+	}
+
+	buf := &bytes.Buffer{}
+	m := &Manifest{Version: "v1.0.0", Migration: "0001", CreatedAt: time.Now().UTC()}
+	assert.NoError(t, Write(buf, m,
+		&Entry{Path: "metatube.db", Name: filepath.Join(src, "metatube.db")},
+		&Entry{Path: "config/selector-patches.json", Name: filepath.Join(src, "patches.json")}))
+	assert.Len(t, m.Files, 2)
+	assert.Error(t, Write(&bytes.Buffer{}, &Manifest{}, &Entry{Path: "../x", Name: filepath.Join(src, "metatube.db")}))
+
+	dst := t.TempDir()
+	got, err := Extract(bytes.NewReader(buf.Bytes()), dst)
+	if assert.NoError(t, err) {
+		assert.Equal(t, "v1.0.0", got.Version)
+		assert.Equal(t, m.Files, got.Files)
+		data, _ := os.ReadFile(filepath.Join(dst, "config", "selector-patches.json"))
+		assert.Equal(t, `{"JavBus":[]}`, string(data))
+	}
+}
+
+func TestExtractCorrupted(t *testing.T) {
+	archive := func(files map[string]string, order ...string) *bytes.Buffer {
+		buf := &bytes.Buffer{}
+		gw := gzip.NewWriter(buf)
+		tw := tar.NewWriter(gw)
+		for _, name := range order {
+			_ = tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(files[name]))})
+			_, _ = tw.Write([]byte(files[name]))
+		}
+		_ = tw.Close()
+		_ = gw.Close()
+		return buf
+	}
+	for _, unit := range []struct {
+		files map[string]string
+		order []string
+	}{
+		// no manifest.
+		{map[string]string{"a": "a"}, []string{"a"}},
+		// checksum mismatch.
+		{map[string]string{"a": "b", ManifestName: `{"format_version":1,"files":[{"path":"a","size":1,"sha256":"00"}]}`}, []string{"a", ManifestName}},
+		// missing file.
+		{map[string]string{ManifestName: `{"format_version":1,"files":[{"path":"a","size":1,"sha256":"00"}]}`}, []string{ManifestName}},
+		// unknown format.
+		{map[string]string{ManifestName: `{"format_version":99}`}, []string{ManifestName}},
+		// path traversal.
+		{map[string]string{"../a": "a"}, []string{"../a"}},
+	} {
+		_, err := Extract(archive(unit.files, unit.order...), t.TempDir())
+		assert.Error(t, err, unit.order)
+	}
+}
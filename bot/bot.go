@@ -0,0 +1,184 @@
+// Package bot answers chat queries with metadata from the engine, a query
+// is a movie or actor URL, a movie number, or an actor name.
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+const (
+	// maxPreviewLinks limits preview image links of a reply.
+	maxPreviewLinks = 3
+	// maxCandidates limits candidates listed of an ambiguous actor name.
+	maxCandidates = 5
+)
+
+// Engine is the part of *engine.Engine used by bots.
+type Engine interface {
+	GetMovieInfoByURL(rawURL string, lazy bool) (*model.MovieInfo, error)
+	GetActorInfoByURL(rawURL string, lazy bool) (*model.ActorInfo, error)
+	SearchMovieAll(keyword string, fallback bool) ([]*model.MovieSearchResult, error)
+	GetMovieInfoByProviderID(name, id string, lazy bool) (*model.MovieInfo, error)
+	GetActorInfoByName(name string, lazy bool) (*model.ActorInfo, error)
+}
+
+var _ Engine = (*engine.Engine)(nil)
+
+// Reply is the answer of a query, Text is in the Telegram HTML subset.
+type Reply struct {
+	Text     string
+	ImageURL string
+}
+
+// Answer resolves the query by the engine.
+func Answer(app Engine, query string) *Reply {
+	query = strings.TrimSpace(query)
+	switch {
+	case query == "" || query == "/start" || query == "/help":
+		return &Reply{Text: "Send a movie number, a movie or actor URL, or an actor name."}
+	case strings.HasPrefix(query, "http://") || strings.HasPrefix(query, "https://"):
+		if info, err := app.GetMovieInfoByURL(query, true); err == nil {
+			return movieReply(info)
+		}
+		info, err := app.GetActorInfoByURL(query, true)
+		if err != nil {
+			return errorReply(err)
+		}
+		return actorReply(info)
+	case isNumber(query):
+		results, err := app.SearchMovieAll(query, true)
+		if err != nil {
+			return errorReply(err)
+		}
+		if len(results) == 0 {
+			return errorReply(mt.ErrInfoNotFound)
+		}
+		info, err := app.GetMovieInfoByProviderID(results[0].Provider, results[0].ID, true)
+		if err != nil {
+			return errorReply(err)
+		}
+		return movieReply(info)
+	default:
+		info, err := app.GetActorInfoByName(query, true)
+		if err != nil {
+			return errorReply(err)
+		}
+		return actorReply(info)
+	}
+}
+
+// isNumber reports whether the query looks like a movie number, i.e.,
+// it contains digits, which actor names don't.
+func isNumber(query string) bool {
+	return strings.ContainsFunc(query, unicode.IsDigit)
+}
+
+func movieReply(info *model.MovieInfo) *Reply {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<b>%s</b> %s\n", esc(info.Number), esc(info.Title))
+	field(&b, "Release", formatDate(time.Time(info.ReleaseDate)))
+	if info.Runtime > 0 {
+		field(&b, "Runtime", fmt.Sprintf("%d min", info.Runtime))
+	}
+	field(&b, "Actors", strings.Join(info.Actors, ", "))
+	field(&b, "Maker", info.Maker)
+	field(&b, "Series", info.Series)
+	field(&b, "Genres", strings.Join(info.Genres, ", "))
+	if info.Score > 0 {
+		field(&b, "Score", fmt.Sprintf("%.1f", info.Score))
+	}
+
+	var links []string
+	for i, u := range info.PreviewImages {
+		if i == maxPreviewLinks {
+			break
+		}
+		links = append(links, link(u, fmt.Sprintf("Preview %d", i+1)))
+	}
+	if info.PreviewVideoURL != "" {
+		links = append(links, link(info.PreviewVideoURL, "Trailer"))
+	}
+	links = append(links, link(info.Homepage, info.Provider))
+	b.WriteString(strings.Join(links, " | "))
+
+	return &Reply{Text: b.String(), ImageURL: firstNonEmpty(info.BigCoverURL, info.CoverURL)}
+}
+
+func actorReply(info *model.ActorInfo) *Reply {
+	var b strings.Builder
+	fmt.Fprintf(&b, "<b>%s</b>\n", esc(info.Name))
+	field(&b, "Aliases", strings.Join(info.Aliases, ", "))
+	field(&b, "Birthday", formatDate(time.Time(info.Birthday)))
+	field(&b, "Nationality", info.Nationality)
+	field(&b, "Height", formatHeight(info.Height))
+	field(&b, "Measurements", info.Measurements)
+	b.WriteString(link(info.Homepage, info.Provider))
+
+	var image string
+	if len(info.Images) > 0 {
+		image = info.Images[0]
+	}
+	return &Reply{Text: b.String(), ImageURL: image}
+}
+
+func errorReply(err error) *Reply {
+	var ambiguous *engine.AmbiguousActorError
+	if errors.As(err, &ambiguous) {
+		var b strings.Builder
+		b.WriteString("Which one?\n")
+		for i, c := range ambiguous.Candidates {
+			if i == maxCandidates {
+				break
+			}
+			fmt.Fprintf(&b, "• %s\n", link(c.Homepage, c.Name+" ("+c.Provider+")"))
+		}
+		return &Reply{Text: strings.TrimSuffix(b.String(), "\n")}
+	}
+	return &Reply{Text: "Not found: " + esc(err.Error())}
+}
+
+func field(b *strings.Builder, name, value string) {
+	if value != "" {
+		fmt.Fprintf(b, "%s: %s\n", name, esc(value))
+	}
+}
+
+func link(u, text string) string {
+	return fmt.Sprintf(`<a href="%s">%s</a>`, esc(u), esc(text))
+}
+
+func esc(s string) string {
+	return html.EscapeString(s)
+}
+
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+func formatHeight(height int) string {
+	if height <= 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d cm", height)
+}
+
+func firstNonEmpty(s ...string) string {
+	for _, v := range s {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,162 @@
+package bot
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+type fakeEngine struct{}
+
+var testMovie = &model.MovieInfo{
+	ID:            "abp030",
+	Number:        "ABP-030",
+	Title:         "Title <1>",
+	Provider:      "FANZA",
+	Homepage:      "https://example.com/abp030",
+	CoverURL:      "https://example.com/abp030.jpg",
+	Actors:        []string{"A", "B"},
+	PreviewImages: []string{"https://example.com/1.jpg", "https://example.com/2.jpg"},
+	ReleaseDate:   datatypes.Date(time.Date(2013, 4, 1, 0, 0, 0, 0, time.UTC)),
+}
+
+func (fakeEngine) GetMovieInfoByURL(rawURL string, _ bool) (*model.MovieInfo, error) {
+	if rawURL == testMovie.Homepage {
+		return testMovie, nil
+	}
+	return nil, mt.ErrInfoNotFound
+}
+
+func (fakeEngine) GetActorInfoByURL(string, bool) (*model.ActorInfo, error) {
+	return nil, mt.ErrInfoNotFound
+}
+
+func (fakeEngine) SearchMovieAll(keyword string, _ bool) ([]*model.MovieSearchResult, error) {
+	if strings.EqualFold(keyword, testMovie.Number) {
+		return []*model.MovieSearchResult{testMovie.ToSearchResult()}, nil
+	}
+	return nil, nil
+}
+
+func (fakeEngine) GetMovieInfoByProviderID(_, _ string, _ bool) (*model.MovieInfo, error) {
+	return testMovie, nil
+}
+
+func (fakeEngine) GetActorInfoByName(name string, _ bool) (*model.ActorInfo, error) {
+	if name == "Namesake" {
+		return nil, &engine.AmbiguousActorError{Name: name, Candidates: []*engine.ActorMatch{
+			{ActorSearchResult: &model.ActorSearchResult{Name: "Namesake", Provider: "P", Homepage: "https://example.com/1"}},
+			{ActorSearchResult: &model.ActorSearchResult{Name: "Namesake", Provider: "P", Homepage: "https://example.com/2"}},
+		}}
+	}
+	return &model.ActorInfo{
+		Name:     name,
+		Provider: "P",
+		Homepage: "https://example.com/actor",
+		Height:   160,
+		Images:   []string{"https://example.com/actor.jpg"},
+	}, nil
+}
+
+func TestAnswer(t *testing.T) {
+	for _, unit := range []struct {
+		query    string
+		contains []string
+		image    string
+	}{
+		{"abp-030", []string{"<b>ABP-030</b> Title &lt;1&gt;", "Release: 2013-04-01", "Actors: A, B", `<a href="https://example.com/2.jpg">Preview 2</a>`}, testMovie.CoverURL},
+		{testMovie.Homepage, []string{"<b>ABP-030</b>"}, testMovie.CoverURL},
+		{"https://example.com/unknown", []string{"Not found"}, ""},
+		{"xyz-999", []string{"Not found"}, ""},
+		{"Someone", []string{"<b>Someone</b>", "Height: 160 cm"}, "https://example.com/actor.jpg"},
+		{"Namesake", []string{"Which one?", "https://example.com/2"}, ""},
+		{"/start", []string{"Send a movie number"}, ""},
+	} {
+		r := Answer(fakeEngine{}, unit.query)
+		for _, s := range unit.contains {
+			assert.Contains(t, r.Text, s, unit.query)
+		}
+		assert.Equal(t, unit.image, r.ImageURL, unit.query)
+	}
+}
+
+func TestTelegram(t *testing.T) {
+	var (
+		methods []string
+		sent    []map[string]any
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		method := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		methods = append(methods, method)
+		switch method {
+		case "getUpdates":
+			_, _ = w.Write([]byte(`{"ok":true,"result":[
+				{"update_id":1,"message":{"chat":{"id":100},"text":"ABP-030"}},
+				{"update_id":2,"message":{"chat":{"id":200},"text":"ABP-030"}}]}`))
+		default:
+			sent = append(sent, body)
+			_, _ = w.Write([]byte(`{"ok":true,"result":{}}`))
+		}
+	}))
+	defer srv.Close()
+
+	b, err := NewTelegram(fakeEngine{}, "123:token", 100)
+	if !assert.NoError(t, err) {
+		return
+	}
+	b.APIURL = srv.URL
+	assert.NoError(t, b.Poll(0))
+	assert.Equal(t, []string{"getUpdates", "sendPhoto"}, methods)
+	if assert.Len(t, sent, 1) {
+		assert.Equal(t, "100", sent[0]["chat_id"])
+		assert.Equal(t, testMovie.CoverURL, sent[0]["photo"])
+		assert.Equal(t, "HTML", sent[0]["parse_mode"])
+	}
+	assert.Equal(t, int64(3), b.offset)
+
+	// allowed chats are required, unless public.
+	_, err = NewTelegram(fakeEngine{}, "123:token")
+	assert.Error(t, err)
+	methods, sent = nil, nil
+	b, err = NewPublicTelegram(fakeEngine{}, "123:token")
+	if !assert.NoError(t, err) {
+		return
+	}
+	b.APIURL = srv.URL
+	assert.NoError(t, b.Poll(0))
+	assert.Len(t, sent, 2)
+}
+
+// failingTransport fails every request, e.g., of network outages.
+type failingTransport struct{}
+
+func (failingTransport) RoundTrip(*http.Request) (*http.Response, error) {
+	return nil, errors.New("connection refused")
+}
+
+func TestTelegram_Error(t *testing.T) {
+	b, err := NewTelegram(fakeEngine{}, "123:token", 100)
+	if !assert.NoError(t, err) {
+		return
+	}
+	b.fetcher = fetch.New(&http.Client{Transport: failingTransport{}}, &fetch.Config{})
+	err = b.Poll(0)
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "connection refused")
+		assert.NotContains(t, err.Error(), "123:token", "token leaked")
+	}
+}
@@ -0,0 +1,185 @@
+package bot
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"go.uber.org/zap"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/redact"
+)
+
+const (
+	telegramAPIURL = "https://api.telegram.org"
+	// telegramPollTimeout is the long polling timeout in seconds.
+	telegramPollTimeout = 30
+	// maxTelegramCaption is the caption limit of photos in characters.
+	maxTelegramCaption = 1024
+	// telegramRetryInterval is the initial interval to poll again after
+	// errors, which is doubled on consecutive errors up to the max.
+	telegramRetryInterval    = 5 * time.Second
+	maxTelegramRetryInterval = 5 * time.Minute
+)
+
+// Telegram is a bot polling updates from the Bot API, and replying to
+// text messages with answers of the engine.
+type Telegram struct {
+	// APIURL is the root of the Bot API, for self-hosted servers.
+	APIURL string
+	// Logger logs errors of polling, which are retried with backoff.
+	Logger  *zap.SugaredLogger
+	token   string
+	app     Engine
+	public  bool
+	allowed map[int64]struct{}
+	fetcher *fetch.Fetcher
+	offset  int64
+}
+
+// NewTelegram returns a bot of the token, which only answers the allowed
+// chats, at least one of which is required, see NewPublicTelegram.
+func NewTelegram(app Engine, token string, allowedChats ...int64) (*Telegram, error) {
+	if len(allowedChats) == 0 {
+		return nil, errors.New("telegram: allowed chats required")
+	}
+	return newTelegram(app, token, false, allowedChats)
+}
+
+// NewPublicTelegram returns a bot of the token, which answers everyone,
+// i.e., anyone on Telegram can query the library.
+func NewPublicTelegram(app Engine, token string) (*Telegram, error) {
+	return newTelegram(app, token, true, nil)
+}
+
+func newTelegram(app Engine, token string, public bool, allowedChats []int64) (*Telegram, error) {
+	if token == "" {
+		return nil, errors.New("telegram: bot token required")
+	}
+	allowed := make(map[int64]struct{}, len(allowedChats))
+	for _, id := range allowedChats {
+		allowed[id] = struct{}{}
+	}
+	logger, _ := zap.NewProduction()
+	return &Telegram{
+		APIURL:  telegramAPIURL,
+		Logger:  logger.Sugar(),
+		token:   token,
+		app:     app,
+		public:  public,
+		allowed: allowed,
+		fetcher: fetch.Default(nil),
+	}, nil
+}
+
+type telegramUpdate struct {
+	UpdateID int64 `json:"update_id"`
+	Message  *struct {
+		Chat struct {
+			ID int64 `json:"id"`
+		} `json:"chat"`
+		Text string `json:"text"`
+	} `json:"message"`
+}
+
+// Run polls and handles updates until the context is done. Errors of
+// polling, e.g., of invalid tokens or network outages, are logged, and
+// retried with backoff.
+func (t *Telegram) Run(ctx context.Context) error {
+	backoff := telegramRetryInterval
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+		err := t.Poll(telegramPollTimeout)
+		if err == nil {
+			backoff = telegramRetryInterval
+			continue
+		}
+		t.Logger.Warnw("telegram poll failed", "error", err, "retry_in", backoff)
+		select {
+		case <-ctx.Done():
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, maxTelegramRetryInterval)
+	}
+}
+
+// Poll gets pending updates with long polling of the timeout in seconds,
+// and replies to them.
+func (t *Telegram) Poll(timeout int) error {
+	var updates []*telegramUpdate
+	if err := t.call("getUpdates", map[string]any{
+		"offset":          t.offset,
+		"timeout":         timeout,
+		"allowed_updates": []string{"message"},
+	}, &updates); err != nil {
+		return err
+	}
+	for _, update := range updates {
+		t.offset = update.UpdateID + 1
+		if update.Message == nil || update.Message.Text == "" {
+			continue
+		}
+		chatID := update.Message.Chat.ID
+		if _, ok := t.allowed[chatID]; !t.public && !ok {
+			continue
+		}
+		// replies are best effort, one failure shouldn't stop the others.
+		_ = t.reply(chatID, Answer(t.app, update.Message.Text))
+	}
+	return nil
+}
+
+func (t *Telegram) reply(chatID int64, r *Reply) error {
+	body := map[string]any{
+		"chat_id":    strconv.FormatInt(chatID, 10),
+		"parse_mode": "HTML",
+	}
+	if r.ImageURL != "" && utf8.RuneCountInString(r.Text) <= maxTelegramCaption {
+		body["photo"], body["caption"] = r.ImageURL, r.Text
+		if t.call("sendPhoto", body, nil) == nil {
+			return nil
+		}
+		// fallback to text, e.g., the image is not accessible to Telegram.
+		delete(body, "photo")
+		delete(body, "caption")
+	}
+	body["text"] = r.Text
+	body["link_preview_options"] = map[string]any{"is_disabled": true}
+	return t.call("sendMessage", body, nil)
+}
+
+// call calls the method of the Bot API, and decodes the result into v.
+func (t *Telegram) call(method string, params map[string]any, v any) error {
+	resp, err := t.fetcher.Post(fmt.Sprintf("%s/bot%s/%s", t.APIURL, t.token, method),
+		fetch.WithJSONBody(params), fetch.WithHeader("Content-Type", "application/json"),
+		fetch.WithRaiseForStatus(false))
+	if err != nil {
+		// the request URL in errors has the token.
+		return fmt.Errorf("telegram: %w", redact.Error(err, t.token))
+	}
+	defer resp.Body.Close()
+	var result struct {
+		OK          bool            `json:"ok"`
+		Description string          `json:"description"`
+		Result      json.RawMessage `json:"result"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("telegram: %w", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram: %s: %s", method, result.Description)
+	}
+	if v == nil {
+		return nil
+	}
+	return json.Unmarshal(result.Result, v)
+}
@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	goerr "errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/backup"
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	V "github.com/metatube-community/metatube-sdk-go/internal/version"
+)
+
+const (
+	restoreCommand = "restore"
+	// backupArtworkManifest is the manifest of cached artwork, images
+	// themselves are fetched again on demand.
+	backupArtworkManifest = "artwork.json"
+	backupConfigDir       = "config"
+)
+
+// backupConfigs returns the config files by flag names.
+func backupConfigs() map[string]string {
+	return map[string]string{
+		"selector-patches": opts.patchesFile,
+		"browser-profiles": opts.profilesFile,
+		"host-overrides":   opts.hostsFile,
+		"post-process":     opts.postProcess,
+		"filters":          opts.filtersFile,
+	}
+}
+
+// runBackup runs the backup command with args:
+//
+//	backup <archive>  archive the DB, config files and artwork manifest
+//
+// Only the embedded SQLite DB is archived, external DBs should be backed
+// up by their own tools.
+func runBackup(app *engine.Engine, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: backup <archive>")
+	}
+	tmp, err := os.MkdirTemp("", "metatube-backup-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+
+	var entries []*backup.Entry
+	if err = app.SnapshotDB(filepath.Join(tmp, dataDBName)); err == nil {
+		entries = append(entries, &backup.Entry{Path: dataDBName, Name: filepath.Join(tmp, dataDBName)})
+	} else if goerr.Is(err, engine.ErrSnapshotUnsupported) {
+		fmt.Fprintln(os.Stderr, "warning: external DB is not archived")
+	} else {
+		return err
+	}
+	for name, file := range backupConfigs() {
+		if file != "" {
+			entries = append(entries, &backup.Entry{Path: path.Join(backupConfigDir, name+".json"), Name: file})
+		}
+	}
+	if opts.data != "" {
+		files, err := backup.ListFiles(filepath.Join(opts.data, dataImageCacheDir))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		if len(files) > 0 {
+			data, err := json.MarshalIndent(files, "", "  ")
+			if err != nil {
+				return err
+			}
+			name := filepath.Join(tmp, backupArtworkManifest)
+			if err = os.WriteFile(name, data, 0o644); err != nil {
+				return err
+			}
+			entries = append(entries, &backup.Entry{Path: backupArtworkManifest, Name: name})
+		}
+	}
+
+	m := &backup.Manifest{Version: V.VersionString(), CreatedAt: time.Now().UTC()}
+	if status, err := app.Migrator().Status(); err == nil {
+		for _, s := range status {
+			if s.AppliedAt != nil {
+				m.Migration = s.ID
+			}
+		}
+	}
+	f, err := os.OpenFile(args[0], os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if err = backup.Write(f, m, entries...); err == nil {
+		err = f.Close()
+	}
+	if err != nil {
+		_ = f.Close()
+		_ = os.Remove(args[0])
+		return err
+	}
+	for _, file := range m.Files {
+		fmt.Printf("%s\t%d bytes\n", file.Path, file.Size)
+	}
+	return nil
+}
+
+// runRestore runs the restore command with args, which is run before
+// the DB is opened:
+//
+//	restore <archive>  restore the archive into the empty data directory
+//
+// Config files are restored into the config directory of the data
+// directory, the flags of them must be set accordingly.
+func runRestore(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: restore <archive>")
+	}
+	if opts.data == "" {
+		return fmt.Errorf("data directory is required to restore")
+	}
+	f, err := os.Open(args[0])
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	tmp, err := os.MkdirTemp(opts.data, ".restore-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(tmp)
+	m, err := backup.Extract(f, tmp)
+	if err != nil {
+		return err
+	}
+	// never overwrite existing data.
+	for _, file := range m.Files {
+		if _, err = os.Stat(filepath.Join(opts.data, filepath.FromSlash(file.Path))); err == nil {
+			return fmt.Errorf("file already exists: %s", file.Path)
+		}
+	}
+	for _, file := range m.Files {
+		dst := filepath.Join(opts.data, filepath.FromSlash(file.Path))
+		if err = os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return err
+		}
+		if err = os.Rename(filepath.Join(tmp, filepath.FromSlash(file.Path)), dst); err != nil {
+			return err
+		}
+		fmt.Printf("restored %s\n", dst)
+	}
+	if m.Version != V.VersionString() {
+		fmt.Printf("archived by %s, pending migrations are applied on start\n", m.Version)
+	}
+	for name := range backupConfigs() {
+		if config := filepath.Join(opts.data, backupConfigDir, name+".json"); fileExists(config) {
+			fmt.Printf("set -%s %s\n", name, config)
+		}
+	}
+	return nil
+}
+
+func fileExists(name string) bool {
+	_, err := os.Stat(name)
+	return err == nil
+}
@@ -0,0 +1,69 @@
+package main
+
+import (
+	"context"
+	"errors"
+	goflag "flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/metatube-community/metatube-sdk-go/bot"
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+// runTelegramBot runs the telegram-bot command with args:
+//
+//	telegram-bot [-token <token>] [-chats <id,...>] [-public]
+//
+// The bot answers movie numbers, movie or actor URLs and actor names sent
+// to it, the token defaults to the TELEGRAM_BOT_TOKEN environment. Only
+// the chats listed are answered, which are required unless -public, as
+// everyone on Telegram could query the library otherwise.
+func runTelegramBot(app *engine.Engine, args []string) error {
+	fs := goflag.NewFlagSet("telegram-bot", goflag.ContinueOnError)
+	var (
+		token  = fs.String("token", os.Getenv("TELEGRAM_BOT_TOKEN"), "Telegram bot token")
+		chats  = fs.String("chats", os.Getenv("TELEGRAM_BOT_CHATS"), "Comma-separated chat ids allowed")
+		public = fs.Bool("public", false, "Answer every chat, i.e., open the library to everyone")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	secretToken, err := secrets.Resolve(*token)
+	if err != nil {
+		return fmt.Errorf("token: %w", err)
+	}
+	var allowed []int64
+	for _, s := range strings.Split(*chats, ",") {
+		if s = strings.TrimSpace(s); s == "" {
+			continue
+		}
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid chat id: %s", s)
+		}
+		allowed = append(allowed, id)
+	}
+	var b *bot.Telegram
+	switch {
+	case *public && len(allowed) > 0:
+		return errors.New("-public and -chats are exclusive")
+	case *public:
+		b, err = bot.NewPublicTelegram(app, secretToken)
+	default:
+		b, err = bot.NewTelegram(app, secretToken, allowed...)
+	}
+	if err != nil {
+		return err
+	}
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	if err = b.Run(ctx); err != context.Canceled {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/lib/pq"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+const maxCompareValueWidth = 40
+
+// runCompare runs the compare command with args:
+//
+//	compare <number>  print infos of the number from all providers
+//
+// Fields on which providers disagree are marked with `*`.
+func runCompare(app *engine.Engine, args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: compare <number>")
+	}
+	c, err := app.CompareMovieInfos(args[0])
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "%s\t%s\n", c.Number, strings.Join(c.Providers, "\t"))
+	for _, field := range c.Fields {
+		name := field.Field
+		if !field.Agreed {
+			name = "*" + name
+		}
+		values := make([]string, 0, len(c.Providers))
+		for _, provider := range c.Providers {
+			values = append(values, formatCompareValue(field.Values[provider]))
+		}
+		fmt.Fprintf(w, "%s\t%s\n", name, strings.Join(values, "\t"))
+	}
+	return w.Flush()
+}
+
+func formatCompareValue(v any) string {
+	var s string
+	switch v := v.(type) {
+	case pq.StringArray:
+		s = strings.Join(v, ", ")
+	default:
+		s = fmt.Sprint(v)
+	}
+	s = strings.Join(strings.Fields(s), " ")
+	if r := []rune(s); len(r) > maxCompareValueWidth {
+		s = string(r[:maxCompareValueWidth-1]) + "…"
+	}
+	return s
+}
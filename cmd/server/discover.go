@@ -0,0 +1,93 @@
+package main
+
+import (
+	"context"
+	goflag "flag"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/discover"
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// runDiscover runs the discover command with args:
+//
+//	discover [-interval <duration>] [-checkpoint <file>] [-prewarm] <sitemap|feed>...
+//
+// Movie pages listed by the sitemaps or RSS/Atom feeds of providers are
+// resolved to IDs by the URL patterns of providers, and printed as
+// `provider<TAB>id` lines, e.g., to build a local index. With -prewarm,
+// the movie infos are also scraped and stored, one at a time. Fetches and
+// scrapes are rate-limited by the interval, and the progress is saved to
+// the checkpoint file, so that interrupted runs are resumed.
+func runDiscover(app *engine.Engine, args []string) error {
+	fs := goflag.NewFlagSet("discover", goflag.ContinueOnError)
+	var (
+		interval   = fs.Duration("interval", time.Second, "Min interval between fetches and scrapes")
+		checkpoint = fs.String("checkpoint", "", "File of the progress to resume from")
+		prewarm    = fs.Bool("prewarm", false, "Scrape and store the movie infos discovered")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return fmt.Errorf("usage: discover [-interval <duration>] [-checkpoint <file>] [-prewarm] <sitemap|feed>...")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	crawler := &discover.Crawler{
+		Fetch: func(rawURL string) (*http.Response, error) {
+			return app.Fetch(rawURL, feedProvider(app, rawURL))
+		},
+		Interval:   *interval,
+		Checkpoint: *checkpoint,
+	}
+	var lastScrape time.Time
+	return crawler.Crawl(ctx, fs.Args(), func(entry *discover.Entry) error {
+		kind, provider, id, err := app.ResolveURL(entry.URL)
+		if err != nil || kind != model.MovieKind {
+			return nil // not a movie page.
+		}
+		fmt.Printf("%s\t%s\n", provider, id)
+		if !*prewarm {
+			return nil
+		}
+		if wait := *interval - time.Since(lastScrape); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(wait):
+			}
+		}
+		lastScrape = time.Now()
+		if _, err = app.GetMovieInfoByProviderID(provider, id, true); err != nil {
+			fmt.Fprintf(os.Stderr, "%s:%s: %v\n", provider, id, err)
+		}
+		return nil // failures are reported, not retried.
+	})
+}
+
+// feedProvider returns the movie provider of the host of the feed, so
+// that feeds are fetched like other resources of the provider, or nil.
+func feedProvider(app *engine.Engine, rawURL string) mt.Provider {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil
+	}
+	host := strings.TrimPrefix(u.Hostname(), "www.")
+	for _, provider := range app.GetMovieProviders() {
+		if strings.TrimPrefix(provider.URL().Hostname(), "www.") == host {
+			return provider
+		}
+	}
+	return nil
+}
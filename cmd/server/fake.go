@@ -0,0 +1,7 @@
+//go:build fake
+
+package main
+
+// The fake provider is registered in builds tagged fake, which serves
+// deterministic metadata for integration tests without network access.
+import _ "github.com/metatube-community/metatube-sdk-go/provider/fake"
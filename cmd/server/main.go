@@ -1,46 +1,129 @@
 package main
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
 	goflag "flag"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/peterbourgon/ff/v3"
+	"gorm.io/gorm"
 
+	"github.com/metatube-community/metatube-sdk-go/common/bandwidth"
+	"github.com/metatube-community/metatube-sdk-go/common/dlock"
+	"github.com/metatube-community/metatube-sdk-go/common/httpcache"
+	"github.com/metatube-community/metatube-sdk-go/common/random"
+	"github.com/metatube-community/metatube-sdk-go/common/socks"
+	"github.com/metatube-community/metatube-sdk-go/common/storage"
+	"github.com/metatube-community/metatube-sdk-go/common/studio"
 	"github.com/metatube-community/metatube-sdk-go/database"
 	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/export"
 	V "github.com/metatube-community/metatube-sdk-go/internal/version"
+	"github.com/metatube-community/metatube-sdk-go/media"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/notify"
+	"github.com/metatube-community/metatube-sdk-go/postprocess"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
 	"github.com/metatube-community/metatube-sdk-go/route"
 	"github.com/metatube-community/metatube-sdk-go/route/auth"
 )
 
-const defaultRequestTimeout = time.Minute
+const (
+	defaultRequestTimeout = time.Minute
+	purgeInterval         = time.Hour
+)
+
+// d2passRealm is the credential realm of D2Pass member sites.
+const d2passRealm = "d2pass"
+
+// Data directory layout.
+const (
+	dataDBName        = "metatube.db"
+	dataImageCacheDir = "cache/images"
+)
 
 var (
 	opts = new(options)
 	flag = goflag.NewFlagSet("", goflag.ExitOnError)
 )
 
+// commands are sub-commands run with args instead of server.
+var commands = map[string]func(app *engine.Engine, args []string) error{
+	migrateCommand: runMigrate,
+	"backup":       runBackup,
+	"compare":      runCompare,
+	"discover":     runDiscover,
+	"organize":     runOrganize,
+	"override":     runOverride,
+	"scrape":       runScrape,
+	"stats":        runStats,
+	"telegram-bot": runTelegramBot,
+}
+
 type options struct {
 	// main options
 	bind  string
 	port  string
 	token string
 	dsn   string
+	data  string
 
 	// engine options
 	requestTimeout time.Duration
+	subTimeout     time.Duration
+	webArchive     bool
+	parseMode      string
+	poolSize       int
+	searchDeadline time.Duration
+	devCacheDir    string
+	imagePackURL   string
+	languages      string
+	privacyMode    bool
+	d2passAccount  string
+	bwCap          int64
+	bwPeriod       time.Duration
+	bwQuotas       string
+	notifyURLs     string
+	patchesFile    string
+	profilesFile   string
+	hostsFile      string
+	soft404File    string
+	phHashes       string
+	phRetry        time.Duration
+	proxies        string
+	postProcess    string
+	studiosFile    string
+	filtersFile    string
+	contentRatings string
+	seriesInterval time.Duration
+	watchInterval  time.Duration
+	prefetchActors int
+	prefetchSeries int
+	lazyFields     string
+	priorityTuning time.Duration
+	cacheURL       string
+	sessionStore   string
+	distLocks      bool
+	secretKey      string
+	ffmpeg         string
 
 	// database options
 	dbMaxIdleConns int
 	dbMaxOpenConns int
 	dbAutoMigrate  bool
 	dbPreparedStmt bool
+	dbRetention    time.Duration
 
 	// version flag
 	versionFlag bool
@@ -55,11 +138,49 @@ func init() {
 	flag.StringVar(&opts.port, "port", "8080", "Port number of server")
 	flag.StringVar(&opts.token, "token", "", "Token to access server")
 	flag.StringVar(&opts.dsn, "dsn", "", "Database Service Name")
+	flag.StringVar(&opts.data, "data-dir", "", "Directory of embedded SQLite database and caches")
 	flag.DurationVar(&opts.requestTimeout, "request-timeout", time.Minute, "Timeout per request")
+	flag.DurationVar(&opts.subTimeout, "sub-request-timeout", 15*time.Second, "Timeout of nested sub-requests, e.g., of sample videos")
+	flag.BoolVar(&opts.webArchive, "archive-fallback", false, "Fetch removed provider pages from the Internet Archive")
+	flag.StringVar(&opts.parseMode, "parse-mode", "", "Movie info parse mode: normal, strict or lenient")
+	flag.IntVar(&opts.poolSize, "provider-pool-size", 0, "Max instances of each provider used concurrently")
+	flag.DurationVar(&opts.searchDeadline, "search-deadline", 0, "Deadline of searching from all providers")
+	flag.StringVar(&opts.imagePackURL, "actor-image-pack-url", "", "Root URL of gfriends compatible actor image pack")
+	flag.StringVar(&opts.languages, "languages", "", "Preferred languages of texts separated by comma, e.g., en,ja")
+	flag.BoolVar(&opts.privacyMode, "privacy-mode", false, "Redact titles, numbers and URLs in logs")
+	flag.StringVar(&opts.d2passAccount, "d2pass-credentials", "", "D2Pass account for member-only contents, in username:password")
+	flag.Int64Var(&opts.bwCap, "bandwidth-cap", 0, "Max MiB downloaded from all providers per bandwidth period, 0 for unlimited")
+	flag.DurationVar(&opts.bwPeriod, "bandwidth-period", 30*24*time.Hour, "Period to reset bandwidth usage, 0 to never reset")
+	flag.StringVar(&opts.bwQuotas, "bandwidth-quotas", "", "Max MiB downloaded per bandwidth period by provider, e.g., JavBus=512,FANZA=1024")
+	flag.StringVar(&opts.notifyURLs, "notify-urls", "", "Notifier URLs separated by comma, e.g., telegram://<token>@<chat-id>")
+	flag.StringVar(&opts.patchesFile, "selector-patches", "", "JSON file of selector patches of info fields by provider name")
+	flag.StringVar(&opts.hostsFile, "host-overrides", "", "JSON file of static IPs and Host headers of hostnames by provider name")
+	flag.StringVar(&opts.soft404File, "soft-not-found", "", "JSON file of page markers and placeholder image hashes of missing contents by provider name")
+	flag.StringVar(&opts.phHashes, "placeholder-hashes", "", "SHA-256 hashes of placeholder images separated by comma, e.g., of now printing covers")
+	flag.DurationVar(&opts.phRetry, "placeholder-retry", 0, "Interval to re-scrape movies with placeholder images for real ones, 0 to disable")
+	flag.StringVar(&opts.proxies, "proxies", "", "SOCKS5 proxy chains by provider name or * for the rest, e.g., JavBus=tor://127.0.0.1:9050,*=socks5://host:1080")
+	flag.StringVar(&opts.profilesFile, "browser-profiles", "", "JSON file of browser profiles, i.e., User-Agent and headers, picked by each provider session")
+	flag.StringVar(&opts.postProcess, "post-process", "", "JSON file of post-processors of infos, e.g., title replacements and genre blocklist")
+	flag.StringVar(&opts.studiosFile, "studios", "", "JSON file of studios in addition to the built-in ones, to canonicalize makers")
+	flag.StringVar(&opts.filtersFile, "filters", "", "JSON file of content filters to block, flag or allow results")
+	flag.StringVar(&opts.contentRatings, "content-ratings", "", "Content ratings of export targets, e.g., jellyfin=XXX,api=R18+")
+	flag.DurationVar(&opts.seriesInterval, "series-interval", 0, "Interval to watch tracked series for new entries, 0 to disable")
+	flag.DurationVar(&opts.watchInterval, "watchlist-interval", 0, "Interval to check watchlist for available metadata, 0 to disable")
+	flag.IntVar(&opts.prefetchActors, "prefetch-actors", 0, "Max actors of new movies scraped in background, 0 to disable")
+	flag.IntVar(&opts.prefetchSeries, "prefetch-series", 0, "Max recent series entries of new movies scraped in background, 0 to disable")
+	flag.DurationVar(&opts.priorityTuning, "priority-tuning", 0, "Sliding window of provider metrics tuning priorities, 0 to disable")
+	flag.StringVar(&opts.lazyFields, "lazy-fields", "", "Expensive fields resolved on demand separated by comma, e.g., preview_video,preview_images")
+	flag.StringVar(&opts.cacheURL, "cache-url", "", "Store URL of the image cache, e.g., redis://host:6379/0 shared by instances, or bolt:///data/cache.db")
+	flag.StringVar(&opts.sessionStore, "session-store", "", "Store URL persisting member sessions, e.g., file:///data/sessions")
+	flag.BoolVar(&opts.distLocks, "distributed-locks", false, "Lock scrapes across instances sharing the DB, by Redis of cache-url or Postgres advisory locks")
+	flag.StringVar(&opts.secretKey, "secret-key", "", "Base64 key to decrypt ${enc:...} secrets of flags, generated by the secret keygen command")
+	flag.StringVar(&opts.ffmpeg, "ffmpeg", "", "Path of ffmpeg to remux HLS trailers into MP4, disabled if empty")
+	flag.StringVar(&opts.devCacheDir, "dev-cache-dir", "", "Directory to cache provider responses for development")
 	flag.IntVar(&opts.dbMaxIdleConns, "db-max-idle-conns", 0, "Database max idle connections")
 	flag.IntVar(&opts.dbMaxOpenConns, "db-max-open-conns", 0, "Database max open connections")
 	flag.BoolVar(&opts.dbAutoMigrate, "db-auto-migrate", false, "Database auto migration")
 	flag.BoolVar(&opts.dbPreparedStmt, "db-prepared-stmt", false, "Database prepared statement")
+	flag.DurationVar(&opts.dbRetention, "db-retention", 0, "Retention of deleted metadata before purged, 0 to keep forever")
 	flag.BoolVar(&opts.versionFlag, "version", false, "Show version")
 	ff.Parse(flag, os.Args[1:], ff.WithEnvVars())
 }
@@ -75,11 +196,45 @@ func main() {
 		showVersionAndExit()
 	}
 
+	// resolve secret references of flags, e.g., ${env:NAME}.
+	if err := resolveSecrets(); err != nil {
+		log.Fatal(err)
+	}
+
+	// secret command runs before the DB is opened.
+	if flag.Arg(0) == secretCommand {
+		if err := runSecret(flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	var imageCacheDir string
+	if opts.data != "" {
+		if err := os.MkdirAll(opts.data, 0o755); err != nil {
+			log.Fatal(err)
+		}
+		// use embedded sqlite DB in data directory if DSN is not set.
+		if opts.dsn == "" {
+			opts.dsn = sqliteDSN(filepath.Join(opts.data, dataDBName))
+		}
+		imageCacheDir = filepath.Join(opts.data, dataImageCacheDir)
+	}
+
+	// restore command runs before the DB is opened.
+	if flag.Arg(0) == restoreCommand {
+		if err := runRestore(flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
 	db, err := database.Open(&database.Config{
 		DSN:                  opts.dsn,
 		PreparedStmt:         opts.dbPreparedStmt,
 		MaxIdleConns:         opts.dbMaxIdleConns,
 		MaxOpenConns:         opts.dbMaxOpenConns,
+		ParameterizedQueries: opts.privacyMode,
 		DisableAutomaticPing: true,
 	})
 	if err != nil {
@@ -96,11 +251,181 @@ func main() {
 		opts.requestTimeout = defaultRequestTimeout
 	}
 
-	app := engine.New(db, opts.requestTimeout)
-	if err = app.AutoMigrate(opts.dbAutoMigrate); err != nil {
+	parseMode, err := engine.ParseParseMode(opts.parseMode)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	bandwidthMeter, err := newBandwidthMeter(opts.bwPeriod, opts.bwCap, opts.bwQuotas)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	notifier, err := parseNotifiers(opts.notifyURLs)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	selectorPatches, err := loadSelectorPatches(opts.patchesFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	hostOverrides, err := loadHostOverrides(opts.hostsFile)
+	if err != nil {
 		log.Fatal(err)
 	}
 
+	softNotFound, err := loadSoftNotFound(opts.soft404File)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	placeholderHashes, err := parsePlaceholderHashes(opts.phHashes)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	proxies, err := parseProxies(opts.proxies)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	// profiles must be loaded before providers are created.
+	if err = loadBrowserProfiles(opts.profilesFile); err != nil {
+		log.Fatal(err)
+	}
+
+	if err = loadStudios(opts.studiosFile); err != nil {
+		log.Fatal(err)
+	}
+
+	postProcess := &postprocess.Config{}
+	if opts.postProcess != "" {
+		if postProcess, err = postprocess.Load(opts.postProcess); err != nil {
+			log.Fatal(err)
+		}
+	}
+	movieProcessors, err := postProcess.MovieProcessors()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	filters, err := loadFilters(opts.filtersFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	contentRatings, err := export.ParseRatings(opts.contentRatings)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var (
+		imageCache httpcache.Cache
+		locker     dlock.Locker
+	)
+	if opts.cacheURL != "" {
+		cache, err := storage.OpenKV(opts.cacheURL, engine.DefaultImageCacheTTL)
+		if err != nil {
+			log.Fatal(err)
+		}
+		if redis, ok := cache.(*storage.Redis); ok {
+			if err = redis.Ping(); err != nil {
+				log.Fatal(err)
+			}
+			if opts.distLocks {
+				locker = dlock.NewRedis(redis.Client(), redis.Prefix(), opts.requestTimeout*2)
+			}
+		}
+		imageCache = cache
+	}
+	var sessionStore storage.KV
+	if opts.sessionStore != "" {
+		if sessionStore, err = storage.OpenKV(opts.sessionStore, 0); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if opts.distLocks && locker == nil {
+		if locker, err = newPostgresLocker(db); err != nil {
+			log.Fatal(err)
+		}
+	}
+
+	var trailerRemuxer media.Remuxer
+	if opts.ffmpeg != "" {
+		trailerRemuxer = &media.FFmpegRemuxer{Path: opts.ffmpeg}
+	}
+
+	// member credentials are only used if explicitly set.
+	username, password, _ := strings.Cut(opts.d2passAccount, ":")
+
+	var app *engine.Engine
+	app = engine.New(db, opts.requestTimeout,
+		engine.WithParseMode(parseMode),
+		engine.WithSubRequestTimeout(opts.subTimeout),
+		engine.WithArchiveFallback(opts.webArchive),
+		engine.WithProviderPoolSize(opts.poolSize),
+		engine.WithSearchDeadline(opts.searchDeadline),
+		engine.WithActorImagePackURL(opts.imagePackURL),
+		engine.WithPreferredLanguages(parseLanguages(opts.languages)...),
+		engine.WithPrivacyMode(opts.privacyMode),
+		engine.WithCredentials(d2passRealm, username, password),
+		engine.WithImageCacheDir(imageCacheDir),
+		engine.WithImageCache(imageCache),
+		engine.WithSessionStore(sessionStore),
+		engine.WithBandwidthMeter(bandwidthMeter),
+		engine.WithNotifier(notifier),
+		engine.WithMovieChangeHandler(func(old, new *model.MovieInfo, changes []*model.FieldChange) {
+			app.NotifyMovieChanged(old, new, changes)
+		}),
+		engine.WithSelectorPatches(selectorPatches),
+		engine.WithHostOverrides(hostOverrides),
+		engine.WithSoftNotFound(softNotFound),
+		engine.WithPlaceholderImages(placeholderHashes...),
+		engine.WithProxies(proxies),
+		engine.WithPrefetch(opts.prefetchActors, opts.prefetchSeries),
+		engine.WithLazyFields(parseLazyFields(opts.lazyFields)...),
+		engine.WithPriorityTuning(opts.priorityTuning),
+		engine.WithMovieProcessors(movieProcessors...),
+		engine.WithActorProcessors(postProcess.ActorProcessors()...),
+		engine.WithFilters(filters),
+		engine.WithContentRatings(contentRatings),
+		engine.WithLocker(locker),
+		engine.WithTrailerRemuxer(trailerRemuxer),
+		engine.WithDevCacheDir(opts.devCacheDir))
+
+	// migrate command controls migrations itself.
+	if err = app.AutoMigrate(opts.dbAutoMigrate && flag.Arg(0) != migrateCommand); err != nil {
+		log.Fatal(err)
+	}
+
+	// run sub-command instead of server.
+	if command, ok := commands[flag.Arg(0)]; ok {
+		err = command(app, flag.Args()[1:])
+		app.WaitNotifications()
+		if err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	if opts.dbRetention > 0 {
+		go purgeDeleted(app, opts.dbRetention)
+	}
+
+	if opts.seriesInterval > 0 {
+		go watchSeries(app, opts.seriesInterval)
+	}
+
+	if opts.watchInterval > 0 {
+		go checkWatchlist(app, opts.watchInterval)
+	}
+
+	if opts.phRetry > 0 {
+		go refreshPlaceholders(app, opts.phRetry)
+	}
+
 	var token auth.Validator
 	if opts.token != "" {
 		token = auth.Token(opts.token)
@@ -114,3 +439,282 @@ func main() {
 		log.Fatal(err)
 	}
 }
+
+// purgeDeleted purges soft-deleted metadata periodically. Periodic tasks
+// are claimed in DB, so that they run once per interval by any instance.
+func purgeDeleted(app *engine.Engine, retention time.Duration) {
+	for ; ; time.Sleep(purgeInterval) {
+		if err := app.RunScheduledTask("purge-deleted", purgeInterval, func() error {
+			_, err := app.PurgeDeleted(retention)
+			return err
+		}); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// watchSeries refreshes tracked series periodically.
+func watchSeries(app *engine.Engine, interval time.Duration) {
+	for ; ; time.Sleep(interval) {
+		if err := app.RunScheduledTask("watch-series", interval, app.RefreshTrackedSeries); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// checkWatchlist checks watchlist entries periodically.
+func checkWatchlist(app *engine.Engine, interval time.Duration) {
+	for ; ; time.Sleep(interval) {
+		if err := app.RunScheduledTask("check-watchlist", interval, app.CheckWatchlist); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// refreshPlaceholders re-scrapes movies with placeholder images
+// periodically, which are retried once per interval.
+func refreshPlaceholders(app *engine.Engine, interval time.Duration) {
+	for ; ; time.Sleep(interval) {
+		if err := app.RunScheduledTask("refresh-placeholders", interval, func() error {
+			_, err := app.RefreshPlaceholderImages(interval)
+			return err
+		}); err != nil {
+			log.Println(err)
+		}
+	}
+}
+
+// newPostgresLocker returns the locker of Postgres advisory locks, which
+// takes connections of a separate pool, so that scrapes holding locks
+// never starve the DB pool.
+func newPostgresLocker(db *gorm.DB) (dlock.Locker, error) {
+	if db.Config.Dialector.Name() != database.Postgres {
+		return nil, fmt.Errorf("distributed locks require cache-url or Postgres DB")
+	}
+	lockDB, err := database.Open(&database.Config{
+		DSN:                  opts.dsn,
+		DisableAutomaticPing: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := lockDB.DB()
+	if err != nil {
+		return nil, err
+	}
+	return dlock.NewPostgres(sqlDB), nil
+}
+
+// sqliteDSN returns the DSN of SQLite DB file in WAL mode, which allows
+// reads concurrent with writes.
+func sqliteDSN(name string) string {
+	return "file:" + name + "?_pragma=journal_mode(WAL)&_pragma=busy_timeout(5000)"
+}
+
+// newBandwidthMeter returns the meter of limits in MiB, or nil if there
+// are no limits.
+func newBandwidthMeter(period time.Duration, cap int64, quotas string) (*bandwidth.Meter, error) {
+	if cap <= 0 && quotas == "" {
+		return nil, nil
+	}
+	m := bandwidth.New(period, cap<<20)
+	for _, quota := range strings.Split(quotas, ",") {
+		if quota = strings.TrimSpace(quota); quota == "" {
+			continue
+		}
+		name, mib, ok := strings.Cut(quota, "=")
+		n, err := strconv.ParseInt(strings.TrimSpace(mib), 10, 64)
+		if !ok || err != nil {
+			return nil, fmt.Errorf("invalid bandwidth quota: %s", quota)
+		}
+		m.SetQuota(strings.TrimSpace(name), n<<20)
+	}
+	return m, nil
+}
+
+// parseProxies parses SOCKS5 proxy chains by provider name, the chain is
+// of proxy URLs separated by `>`.
+func parseProxies(s string) (map[string]*socks.Dialer, error) {
+	proxies := make(map[string]*socks.Dialer)
+	for _, entry := range strings.Split(s, ",") {
+		if entry = strings.TrimSpace(entry); entry == "" {
+			continue
+		}
+		name, chain, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid proxy: %s", entry)
+		}
+		d, err := socks.Parse(chain)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy of %s: %w", name, err)
+		}
+		proxies[strings.TrimSpace(name)] = d
+	}
+	return proxies, nil
+}
+
+// parseNotifiers returns the notifier of the URLs, or nil if none.
+func parseNotifiers(s string) (notify.Notifier, error) {
+	var notifiers notify.Multi
+	for _, rawURL := range strings.Split(s, ",") {
+		if rawURL = strings.TrimSpace(rawURL); rawURL == "" {
+			continue
+		}
+		n, err := notify.Parse(rawURL)
+		if err != nil {
+			return nil, err
+		}
+		notifiers = append(notifiers, n)
+	}
+	if len(notifiers) == 0 {
+		return nil, nil
+	}
+	return notifiers, nil
+}
+
+// loadSelectorPatches loads selector patches by provider names from the
+// JSON file, or nil if no file.
+func loadSelectorPatches(name string) (patches map[string][]*mt.SelectorPatch, err error) {
+	if name == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &patches); err != nil {
+		return nil, fmt.Errorf("invalid selector patches: %w", err)
+	}
+	return patches, nil
+}
+
+// loadStudios loads the studio table from the JSON file if any, which takes
+// precedence over the built-in one.
+func loadStudios(name string) error {
+	if name == "" {
+		return nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return studio.LoadTable(f)
+}
+
+// loadHostOverrides loads host overrides by provider name from the JSON
+// file, or nil if no file.
+func loadHostOverrides(name string) (overrides map[string][]*mt.HostOverride, err error) {
+	if name == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("invalid host overrides: %w", err)
+	}
+	for provider, hosts := range overrides {
+		for _, host := range hosts {
+			if host == nil || host.Hostname == "" {
+				return nil, fmt.Errorf("invalid host overrides of %s: hostname is required", provider)
+			}
+			for _, ip := range host.IPs {
+				if net.ParseIP(ip) == nil {
+					return nil, fmt.Errorf("invalid host overrides of %s: invalid IP: %s", provider, ip)
+				}
+			}
+		}
+	}
+	return overrides, nil
+}
+
+// loadSoftNotFound loads soft 404 detectors by provider name from the JSON
+// file, or nil if no file.
+func loadSoftNotFound(name string) (detectors map[string]*mt.SoftNotFound, err error) {
+	if name == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &detectors); err != nil {
+		return nil, fmt.Errorf("invalid soft 404 detectors: %w", err)
+	}
+	for provider, d := range detectors {
+		if d == nil {
+			return nil, fmt.Errorf("invalid soft 404 detectors of %s", provider)
+		}
+		for _, hash := range d.ImageHashes {
+			if b, err := hex.DecodeString(hash); err != nil || len(b) != sha256.Size {
+				return nil, fmt.Errorf("invalid soft 404 detectors of %s: invalid SHA-256 hash: %s", provider, hash)
+			}
+		}
+	}
+	return detectors, nil
+}
+
+// parsePlaceholderHashes parses SHA-256 hashes in hex separated by comma.
+func parsePlaceholderHashes(s string) ([]string, error) {
+	var hashes []string
+	for _, hash := range strings.Split(s, ",") {
+		if hash = strings.TrimSpace(hash); hash == "" {
+			continue
+		}
+		if b, err := hex.DecodeString(hash); err != nil || len(b) != sha256.Size {
+			return nil, fmt.Errorf("invalid placeholder hash: %s", hash)
+		}
+		hashes = append(hashes, hash)
+	}
+	return hashes, nil
+}
+
+// loadBrowserProfiles replaces the default browser profiles with the JSON
+// file, if any.
+func loadBrowserProfiles(name string) error {
+	if name == "" {
+		return nil
+	}
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return random.LoadProfiles(f)
+}
+
+// loadFilters loads content filters from the JSON file, or nil if no file.
+func loadFilters(name string) (*engine.Filters, error) {
+	if name == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	filters := &engine.Filters{}
+	if err = json.Unmarshal(data, filters); err != nil {
+		return nil, fmt.Errorf("invalid filters: %w", err)
+	}
+	return filters, nil
+}
+
+func parseLazyFields(s string) (fields []mt.Field) {
+	for _, field := range strings.Split(s, ",") {
+		if field = strings.TrimSpace(field); field != "" {
+			fields = append(fields, mt.Field(field))
+		}
+	}
+	return
+}
+
+func parseLanguages(s string) (langs []string) {
+	for _, lang := range strings.Split(s, ",") {
+		if lang = strings.TrimSpace(lang); lang != "" {
+			langs = append(langs, lang)
+		}
+	}
+	return
+}
@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+const migrateCommand = "migrate"
+
+// runMigrate runs the migrate command with args:
+//
+//	migrate [up]      apply the auto migration and all pending migrations
+//	migrate down [n]  revert the last n (default 1) applied migrations
+//	migrate status    show the applied states of migrations
+func runMigrate(app *engine.Engine, args []string) error {
+	action := "up"
+	if len(args) > 0 {
+		action = args[0]
+	}
+	switch action {
+	case "up":
+		if err := app.AutoMigrate(true); err != nil {
+			return err
+		}
+	case "down":
+		n := 1
+		if len(args) > 1 {
+			var err error
+			if n, err = strconv.Atoi(args[1]); err != nil || n < 1 {
+				return fmt.Errorf("invalid migration steps: %s", args[1])
+			}
+		}
+		ids, err := app.Migrator().Down(n)
+		for _, id := range ids {
+			fmt.Println("reverted", id)
+		}
+		if err != nil {
+			return err
+		}
+	case "status":
+	default:
+		return fmt.Errorf("unknown migrate action: %s", action)
+	}
+	status, err := app.Migrator().Status()
+	if err != nil {
+		return err
+	}
+	for _, s := range status {
+		if s.AppliedAt != nil {
+			fmt.Printf("%s\tapplied at %s\n", s.ID, s.AppliedAt.Format(time.RFC3339))
+		} else {
+			fmt.Printf("%s\tpending\n", s.ID)
+		}
+	}
+	return nil
+}
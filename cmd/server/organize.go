@@ -0,0 +1,440 @@
+package main
+
+import (
+	"bufio"
+	goerr "errors"
+	goflag "flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/common/download"
+	"github.com/metatube-community/metatube-sdk-go/common/number"
+	"github.com/metatube-community/metatube-sdk-go/common/safepath"
+	"github.com/metatube-community/metatube-sdk-go/common/storage"
+	"github.com/metatube-community/metatube-sdk-go/common/tmpl"
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/notify"
+	"github.com/metatube-community/metatube-sdk-go/organize"
+)
+
+const (
+	defaultOrganizePath    = "{{.Number}}"
+	defaultOrganizeWorkers = 4
+)
+
+// Organize modes of videos.
+const (
+	moveMode     = "move"
+	symlinkMode  = "symlink"
+	hardlinkMode = "hardlink"
+)
+
+// errSkipped is returned if the video is skipped by the interactive pick.
+var errSkipped = goerr.New("skipped")
+
+// runOrganize runs the organize command with args:
+//
+//	organize [-interactive] [-dest <dir>] [-path <template>] [-mode <mode>] [-sidecar <format>] [-workers <n>] [-plan <file>] [-artwork-store <url>] <video>...
+//	organize -apply <plan> [-journal <file>] [-artwork-store <url>]
+//	organize -rollback <journal>
+//
+// Videos are moved into directories named by the path template under
+// dest, with posters and infos beside, written in the sidecar format,
+// i.e., json, yaml or toml. In the symlink or hardlink mode,
+// videos are linked instead, so the originals are kept untouched, e.g.,
+// for seeding. With -plan, the plan is written to the file for review
+// instead of applied. Applied operations are journaled to the journal
+// file, which can be rolled back. Videos are processed by workers in
+// stages with bounded queues, from identifying and scraping to placing,
+// downloading and writing, and providers still throttle requests by
+// their own rate limits. In the interactive mode, candidates of ambiguous
+// videos are listed to pick from one at a time, and the choices are
+// remembered the same as the scrape command. With -artwork-store, artwork
+// is put into the blob store, e.g., s3://bucket/prefix, by the paths
+// relative to dest instead, which is kept on rollback.
+func runOrganize(app *engine.Engine, args []string) error {
+	fs := goflag.NewFlagSet("organize", goflag.ContinueOnError)
+	var (
+		interactive = fs.Bool("interactive", false, "Pick from candidates if ambiguous")
+		dest        = fs.String("dest", ".", "Root directory of the library")
+		path        = fs.String("path", defaultOrganizePath, "Template of movie directories")
+		mode        = fs.String("mode", moveMode, "How videos are placed: move, symlink or hardlink")
+		sidecar     = fs.String("sidecar", string(model.SidecarJSON), "Format of infos beside videos: json, yaml or toml")
+		planFile    = fs.String("plan", "", "Write the plan to the file without applying")
+		apply       = fs.String("apply", "", "Apply the plan of the file")
+		journal     = fs.String("journal", "", "Journal file of applied operations")
+		rollback    = fs.String("rollback", "", "Roll back operations of the journal file")
+		workers     = fs.Int("workers", defaultOrganizeWorkers, "Number of videos processed concurrently")
+		artwork     = fs.String("artwork-store", "", "Blob store URL of artwork instead of the library, e.g., s3://bucket/prefix")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *rollback != "" {
+		return organize.Rollback(*rollback)
+	}
+
+	if *apply == "" && fs.NArg() == 0 {
+		return fmt.Errorf("usage: organize [-interactive] [-dest <dir>] [-path <template>] [-mode <mode>] [-sidecar <format>] [-workers <n>] [-plan <file>] [-artwork-store <url>] <video>...")
+	}
+	var (
+		format model.SidecarFormat
+		picker *organizePicker
+		err    error
+	)
+	if *apply == "" {
+		if format, err = model.ParseSidecarFormat(*sidecar); err != nil {
+			return err
+		}
+		if *interactive {
+			if picker, err = newOrganizePicker(app); err != nil {
+				return err
+			}
+		}
+	}
+	if *planFile != "" {
+		plan, err := planOrganize(app, *dest, *path, *mode, format, *workers, picker, nil, fs.Args())
+		if err != nil {
+			return err
+		}
+		return plan.Save(*planFile)
+	}
+
+	if *journal == "" {
+		*journal = filepath.Join(*dest, fmt.Sprintf(".metatube-journal-%d.jsonl", time.Now().Unix()))
+	}
+	fmt.Fprintf(os.Stderr, "journal: %s\n", *journal)
+	var blob storage.Blob
+	if *artwork != "" {
+		if blob, err = storage.OpenBlob(*artwork); err != nil {
+			return err
+		}
+	}
+	manifests := &organizeManifests{m: make(map[string]*download.Manifest)}
+
+	var plan *organize.Plan
+	if *apply != "" {
+		if plan, err = organize.LoadPlan(*apply); err != nil {
+			return err
+		}
+		err = plan.ApplyParallel(organizeDownload(app, *dest, blob, manifests, plan.CreatedAt), *journal, *workers)
+	} else {
+		// artwork is downloaded right after scraping.
+		applier, aErr := organize.NewApplier(organizeDownload(app, *dest, blob, manifests, time.Now()), *journal)
+		if aErr != nil {
+			return aErr
+		}
+		plan, err = planOrganize(app, *dest, *path, *mode, format, *workers, picker, applier, fs.Args())
+		_ = applier.Close()
+		if plan == nil {
+			return err
+		}
+	}
+	// re-key scan states of videos moved, and record artwork downloaded,
+	// even if some groups failed.
+	return goerr.Join(err, moveScanStates(app, plan), manifests.save())
+}
+
+// organizeDownload returns the download function of artwork into the blob
+// store if not nil, or into movie directories with manifests, infos of
+// the artwork are scraped at the time given.
+func organizeDownload(app *engine.Engine, dest string, blob storage.Blob, manifests *organizeManifests, scrapedAt time.Time) organize.DownloadFunc {
+	return func(provider, url, name string) error {
+		p, err := app.GetMovieProviderByName(provider)
+		if err != nil {
+			return err
+		}
+		if blob != nil {
+			rel, err := filepath.Rel(dest, safepath.ShortPath(name))
+			if err != nil {
+				return err
+			}
+			_, err = app.DownloadBlob(p, url, blob, filepath.ToSlash(rel))
+			return err
+		}
+		m, err := manifests.get(filepath.Dir(name))
+		if err != nil {
+			return err
+		}
+		_, err = app.DownloadManifest(p, url, m, filepath.Base(name), scrapedAt)
+		return err
+	}
+}
+
+// organizeManifests are the download manifests of movie directories, see
+// download.Manifest, which are loaded as artwork is downloaded into them.
+type organizeManifests struct {
+	mu sync.Mutex
+	m  map[string]*download.Manifest
+}
+
+func (ms *organizeManifests) get(dir string) (*download.Manifest, error) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	if m, ok := ms.m[dir]; ok {
+		return m, nil
+	}
+	m, err := download.LoadManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	ms.m[dir] = m
+	return m, nil
+}
+
+func (ms *organizeManifests) save() error {
+	var errs []error
+	for _, m := range ms.m {
+		errs = append(errs, m.Save())
+	}
+	return goerr.Join(errs...)
+}
+
+// moveScanStates re-keys scan states of videos moved by the plan applied,
+// so they are not scanned as new in the next runs.
+func moveScanStates(app *engine.Engine, plan *organize.Plan) error {
+	var errs []error
+	for _, op := range plan.Ops {
+		if op.Kind != organize.RenameOp {
+			continue
+		}
+		if _, err := os.Lstat(op.Src); !os.IsNotExist(err) {
+			continue // not moved.
+		}
+		if _, err := os.Lstat(op.Dst); err != nil {
+			continue
+		}
+		if err := app.MoveScanState(op.Src, op.Dst); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", op.Src, err))
+		}
+	}
+	return goerr.Join(errs...)
+}
+
+// organizeJob is a video passed through the organize pipeline.
+type organizeJob struct {
+	video string
+	state *model.ScanState
+	// changed reports whether the video is new or changed.
+	changed bool
+	info    *model.MovieInfo
+	dir     string
+	// skip reports whether the video is placed already.
+	skip bool
+	// ops are the operations planned of the video.
+	ops []*organize.Op
+}
+
+// organizePicker picks from candidates of ambiguous videos interactively,
+// one video at a time, while others are processed by workers.
+type organizePicker struct {
+	mu          sync.Mutex
+	app         *engine.Engine
+	stdin       *bufio.Reader
+	choicesFile string
+	choices     map[string]*choice
+}
+
+func newOrganizePicker(app *engine.Engine) (*organizePicker, error) {
+	var choicesFile string
+	if opts.data != "" {
+		choicesFile = filepath.Join(opts.data, dataChoicesName)
+	}
+	choices, err := loadChoices(choicesFile)
+	if err != nil {
+		return nil, err
+	}
+	return &organizePicker{
+		app:         app,
+		stdin:       bufio.NewReader(os.Stdin),
+		choicesFile: choicesFile,
+		choices:     choices,
+	}, nil
+}
+
+// pick returns the remembered choice of the keyword, or the candidate
+// picked, errSkipped is returned if skipped.
+func (p *organizePicker) pick(video, keyword string, candidates []*model.MovieSearchResult) (*choice, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	key := strings.ToUpper(number.Canonicalize("", keyword))
+	if c, ok := p.choices[key]; ok {
+		return c, nil
+	}
+	pick, err := pickCandidate(p.app, p.stdin, video, candidates)
+	if err != nil {
+		return nil, err
+	}
+	if pick == nil {
+		return nil, errSkipped
+	}
+	c := &choice{Provider: pick.Provider, ID: pick.ID}
+	p.choices[key] = c
+	return c, saveChoices(p.choicesFile, p.choices)
+}
+
+// planOrganize plans to place each video into its movie directory by the
+// mode, with the poster and the info in the sidecar format beside. Videos
+// are identified and scraped by workers concurrently. Scan states of videos
+// are kept in DB, so videos unchanged are neither matched again, nor
+// planned if placed already. Videos failed or skipped are left out of the
+// plan. Ambiguous videos are picked by the picker if not nil, or matched
+// to the best ranked candidates. If the applier is not nil, operations of
+// each video are applied by the later stages of the same pipeline as soon
+// as planned, and the errors of applying are returned with the plan.
+func planOrganize(app *engine.Engine, dest, path, mode string, format model.SidecarFormat, workers int, picker *organizePicker, applier *organize.Applier, videos []string) (*organize.Plan, error) {
+	switch mode {
+	case moveMode, symlinkMode, hardlinkMode:
+	default:
+		return nil, fmt.Errorf("invalid organize mode: %s", mode)
+	}
+	t, err := tmpl.New("path", path)
+	if err != nil {
+		return nil, err
+	}
+
+	jobs := make([]*organizeJob, len(videos))
+	for i, video := range videos {
+		jobs[i] = &organizeJob{video: video}
+	}
+	var applyErrs []error
+	stages := []*organize.Stage[*organizeJob]{{
+		Name:    "identify",
+		Workers: workers,
+		Do: func(job *organizeJob) (err error) {
+			if job.state, job.changed, err = app.ScanFile(job.video); err != nil {
+				return
+			}
+			// unchanged videos are not matched again.
+			if job.changed || !job.state.Matched() {
+				keyword := number.Trim(filepath.Base(job.video))
+				candidates, err := scrapeCandidates(app, keyword)
+				if err != nil {
+					return err
+				}
+				pick := &choice{Provider: candidates[0].Provider, ID: candidates[0].ID}
+				if picker != nil && len(candidates) > 1 {
+					if pick, err = picker.pick(job.video, keyword, candidates); err != nil {
+						return err
+					}
+				}
+				job.state.Number, job.state.Provider, job.state.ID =
+					candidates[0].Number, pick.Provider, pick.ID
+				for _, candidate := range candidates {
+					if candidate.Provider == pick.Provider && candidate.ID == pick.ID {
+						job.state.Number = candidate.Number
+					}
+				}
+			}
+			return nil
+		},
+	}, {
+		Name:    "scrape",
+		Workers: workers,
+		Do: func(job *organizeJob) (err error) {
+			if job.info, err = app.GetMovieInfoByProviderID(job.state.Provider, job.state.ID, true); err != nil {
+				return
+			}
+			if err = app.SaveScanState(job.state); err != nil {
+				return
+			}
+			rel, err := t.ExecutePath(job.info)
+			if err != nil {
+				return
+			}
+			job.dir = filepath.Join(dest, filepath.FromSlash(rel))
+			if _, err = os.Lstat(organizeTarget(job)); err == nil && !job.changed {
+				job.skip = true // placed already.
+			}
+			return nil
+		},
+	}, {
+		Name:    "plan",
+		Workers: workers,
+		Do: func(job *organizeJob) error {
+			if job.skip {
+				return nil
+			}
+			p := organize.NewPlan()
+			p.Group(job.video)
+			p.Mkdir(job.dir)
+			switch target := organizeTarget(job); mode {
+			case symlinkMode:
+				if err := p.Symlink(job.video, target); err != nil {
+					return err
+				}
+			case hardlinkMode:
+				p.Hardlink(job.video, target)
+			default:
+				p.Rename(job.video, target)
+			}
+			cover := job.info.BigCoverURL
+			if cover == "" {
+				cover = job.info.CoverURL
+			}
+			if cover != "" && !artworkUnchanged(job.dir, cover, "poster.jpg") {
+				p.Download(job.info.Provider, cover, filepath.Join(job.dir, "poster.jpg"))
+			}
+			data, err := model.MarshalSidecar(job.info, format)
+			if err != nil {
+				return err
+			}
+			p.Write(organizeBase(job)+format.Ext(), data)
+			job.ops = p.Ops
+			return nil
+		},
+	}}
+	if applier != nil {
+		stages = append(stages, organize.ApplyStages(applier, workers, func(job *organizeJob) []*organize.Op {
+			return job.ops
+		})...)
+	}
+	organize.RunPipeline(jobs, workers, func(job *organizeJob, stage string, err error) {
+		if goerr.Is(err, errSkipped) {
+			return
+		}
+		fmt.Fprintf(os.Stderr, "%s: %s: %v\n", job.video, stage, err)
+		switch stage {
+		case "identify":
+			app.Notify(&notify.Event{
+				Kind:    notify.IdentifyFailed,
+				Title:   filepath.Base(job.video),
+				Message: err.Error(),
+			})
+		case "place", "download", "write":
+			applyErrs = append(applyErrs, fmt.Errorf("%s: %w", job.video, err))
+		}
+	}, stages...)
+
+	// plan in the order of videos, including the ones failed to apply,
+	// which are partially applied and journaled.
+	plan := organize.NewPlan()
+	for _, job := range jobs {
+		plan.Ops = append(plan.Ops, job.ops...)
+	}
+	return plan, goerr.Join(applyErrs...)
+}
+
+// artworkUnchanged reports whether the artwork of name in the directory
+// has been downloaded from the url and is intact by its manifest, so that
+// it's not downloaded again.
+func artworkUnchanged(dir, url, name string) bool {
+	m, err := download.LoadManifest(dir)
+	return err == nil && m.Unchanged(url, name)
+}
+
+// organizeBase returns the path of files of the job without extension.
+func organizeBase(job *organizeJob) string {
+	return filepath.Join(job.dir, filepath.Base(job.dir))
+}
+
+// organizeTarget returns the path the video is placed at.
+func organizeTarget(job *organizeJob) string {
+	return organizeBase(job) + filepath.Ext(job.video)
+}
@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+// overrideActor is the audit actor of overrides applied by command.
+const overrideActor = "cli"
+
+// runOverride runs the override command with args:
+//
+//	override get <kind> <provider> <id>
+//	override set <kind> <provider> <id> <json>  merge fields, e.g., {"title":"..."}
+//	override clear <kind> <provider> <id> [field...]
+func runOverride(app *engine.Engine, args []string) error {
+	if len(args) < 4 {
+		return fmt.Errorf("usage: override get|set|clear <kind> <provider> <id> [json|field...]")
+	}
+	var (
+		action   = args[0]
+		kind     = args[1]
+		provider = args[2]
+		id       = args[3]
+		auditor  = app.Auditor(overrideActor)
+	)
+	switch action {
+	case "get":
+	case "set":
+		if len(args) != 5 {
+			return fmt.Errorf("override set requires fields in JSON")
+		}
+		fields := make(map[string]json.RawMessage)
+		if err := json.Unmarshal([]byte(args[4]), &fields); err != nil {
+			return err
+		}
+		if _, err := auditor.SetOverride(kind, provider, id, fields); err != nil {
+			return err
+		}
+	case "clear":
+		return auditor.ClearOverride(kind, provider, id, args[4:]...)
+	default:
+		return fmt.Errorf("unknown override action: %s", action)
+	}
+	o, err := app.GetOverride(kind, provider, id)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(o)
+}
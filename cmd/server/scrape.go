@@ -0,0 +1,291 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	goflag "flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/common/number"
+	"github.com/metatube-community/metatube-sdk-go/common/tmpl"
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// dataChoicesName is the file of remembered choices in the data directory.
+const dataChoicesName = "choices.json"
+
+// choice is a remembered pick of candidates of a number.
+type choice struct {
+	Provider string `json:"provider"`
+	ID       string `json:"id"`
+}
+
+// runScrape runs the scrape command with args:
+//
+//	scrape [-interactive] [-har <file>] [-format <template>|-path <template>] <number|filename>...
+//	scrape -from-file <file|-> [-format <template>|-path <template>] <url>
+//
+// Movie infos are printed in JSON, or rendered by the template of format,
+// or the template of path which is sanitized as a relative file path, see
+// package tmpl for the helpers of templates.
+//
+// With -har, infos are scraped bypassing the DB, and all HTTP transactions
+// of the scrapes are exported to the HAR file, even if they fail, so as to
+// share reproductions of provider issues.
+//
+// With -from-file, the page saved manually, or read from stdin if `-`, is
+// parsed offline by the provider of the URL it was saved from.
+//
+// In the interactive mode, candidates of ambiguous numbers are listed to
+// pick from, and the choices are remembered in the data directory for the
+// subsequent identical cases.
+func runScrape(app *engine.Engine, args []string) (err error) {
+	fs := goflag.NewFlagSet("scrape", goflag.ContinueOnError)
+	var (
+		interactive = fs.Bool("interactive", false, "Pick from candidates if ambiguous")
+		format      = fs.String("format", "", "Template to render infos")
+		pathFormat  = fs.String("path", "", "Template to render infos as file paths")
+		fromFile    = fs.String("from-file", "", "Parse the saved page of the URL, or stdin if -")
+		harFile     = fs.String("har", "", "Export HTTP transactions of scrapes to the HAR file")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 || (*format != "" && *pathFormat != "") || (*fromFile != "" && fs.NArg() != 1) {
+		return fmt.Errorf("usage: scrape [-interactive] [-har <file>] [-format <template>|-path <template>] <number|filename>...\n" +
+			"       scrape -from-file <file|-> [-format <template>|-path <template>] <url>")
+	}
+	printInfo, err := newScrapePrinter(*format, *pathFormat)
+	if err != nil {
+		return err
+	}
+	if *fromFile != "" {
+		info, err := scrapeFromFile(app, *fromFile, fs.Arg(0))
+		if err != nil {
+			return err
+		}
+		return printInfo(info)
+	}
+
+	var choicesFile string
+	if opts.data != "" {
+		choicesFile = filepath.Join(opts.data, dataChoicesName)
+	}
+	choices, err := loadChoices(choicesFile)
+	if err != nil {
+		return err
+	}
+
+	var har *mt.Snapshot
+	if *harFile != "" {
+		har = &mt.Snapshot{}
+		defer func() {
+			if harErr := writeHAR(*harFile, har); err == nil {
+				err = harErr
+			}
+		}()
+	}
+
+	stdin := bufio.NewReader(os.Stdin)
+	for _, arg := range fs.Args() {
+		keyword := number.Trim(filepath.Base(arg))
+		key := strings.ToUpper(number.Canonicalize("", keyword))
+
+		c, ok := choices[key]
+		if !ok {
+			candidates, err := scrapeCandidates(app, keyword)
+			if err != nil {
+				return fmt.Errorf("%s: %w", arg, err)
+			}
+			pick := candidates[0] // the best ranked by default.
+			if *interactive && len(candidates) > 1 {
+				if pick, err = pickCandidate(app, stdin, arg, candidates); err != nil {
+					return err
+				}
+				if pick == nil {
+					continue // skipped.
+				}
+				choices[key] = &choice{Provider: pick.Provider, ID: pick.ID}
+				if err = saveChoices(choicesFile, choices); err != nil {
+					return err
+				}
+			}
+			c = &choice{Provider: pick.Provider, ID: pick.ID}
+		}
+
+		var info *model.MovieInfo
+		if har != nil {
+			var snapshot *mt.Snapshot
+			info, snapshot, err = app.GetMovieInfoByProviderIDWithDebug(c.Provider, c.ID)
+			if snapshot != nil {
+				har.Responses = append(har.Responses, snapshot.Responses...)
+			}
+		} else {
+			info, err = app.GetMovieInfoByProviderID(c.Provider, c.ID, true)
+		}
+		if err != nil {
+			return fmt.Errorf("%s: %w", arg, err)
+		}
+		if err = printInfo(info); err != nil {
+			return fmt.Errorf("%s: %w", arg, err)
+		}
+	}
+	return nil
+}
+
+// newScrapePrinter returns the printer of infos by the templates, or in
+// JSON if no templates.
+func newScrapePrinter(format, pathFormat string) (func(info *model.MovieInfo) error, error) {
+	if format == "" && pathFormat == "" {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		return func(info *model.MovieInfo) error { return enc.Encode(info) }, nil
+	}
+	t, err := tmpl.New("scrape", format+pathFormat /* one of them */)
+	if err != nil {
+		return nil, err
+	}
+	return func(info *model.MovieInfo) error {
+		var s string
+		if pathFormat != "" {
+			s, err = t.ExecutePath(info)
+		} else {
+			s, err = t.Execute(info)
+		}
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Println(s)
+		return err
+	}, nil
+}
+
+// writeHAR writes the HTTP transactions of the snapshot as a HAR file.
+func writeHAR(name string, snapshot *mt.Snapshot) error {
+	data, err := snapshot.MarshalHAR()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0o644)
+}
+
+// scrapeFromFile parses the page saved in the file, or stdin if `-`, by
+// the provider of the URL.
+func scrapeFromFile(app *engine.Engine, name, rawURL string) (*model.MovieInfo, error) {
+	var (
+		data []byte
+		err  error
+	)
+	if name == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(name)
+	}
+	if err != nil {
+		return nil, err
+	}
+	provider, err := app.GetMovieProviderByURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return app.GetMovieInfoFromHTML(provider.Name(), data, rawURL)
+}
+
+// scrapeCandidates searches the keyword, results of the same number go
+// first, or all results are candidates if none of them matches.
+func scrapeCandidates(app *engine.Engine, keyword string) ([]*model.MovieSearchResult, error) {
+	results, err := app.SearchMovieAll(keyword, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, fmt.Errorf("no results of %s", keyword)
+	}
+	canonical := number.Canonicalize("", keyword)
+	var matched []*model.MovieSearchResult
+	for _, result := range results {
+		if strings.EqualFold(number.Canonicalize(result.Provider, result.Number), canonical) {
+			matched = append(matched, result)
+		}
+	}
+	if len(matched) == 0 {
+		return results, nil
+	}
+	return matched, nil
+}
+
+// pickCandidate lists candidates and reads the pick from r, nil is returned
+// if skipped.
+func pickCandidate(app *engine.Engine, r *bufio.Reader, name string, candidates []*model.MovieSearchResult) (*model.MovieSearchResult, error) {
+	fmt.Fprintf(os.Stderr, "%s has %d candidates:\n", name, len(candidates))
+	w := tabwriter.NewWriter(os.Stderr, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "#\tNUMBER\tTITLE\tDATE\tCOVER\tPROVIDER")
+	for i, candidate := range candidates {
+		cover := "-"
+		if cfg, err := app.ProbeImageByURL(app.MustGetMovieProviderByName(candidate.Provider), candidate.CoverURL); err == nil {
+			cover = fmt.Sprintf("%dx%d", cfg.Width, cfg.Height)
+		}
+		date := "-"
+		if t := time.Time(candidate.ReleaseDate); !t.IsZero() {
+			date = t.Format(time.DateOnly)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n", i+1, candidate.Number,
+			formatCompareValue(candidate.Title), date, cover, candidate.Provider)
+	}
+	if err := w.Flush(); err != nil {
+		return nil, err
+	}
+	for {
+		fmt.Fprintf(os.Stderr, "pick [1-%d], or s to skip (default 1): ", len(candidates))
+		line, err := r.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return nil, err
+		}
+		switch line = strings.TrimSpace(line); line {
+		case "":
+			return candidates[0], nil
+		case "s", "S":
+			return nil, nil
+		}
+		if i, err := strconv.Atoi(line); err == nil && i >= 1 && i <= len(candidates) {
+			return candidates[i-1], nil
+		}
+	}
+}
+
+// loadChoices loads remembered choices, choices are kept in memory only
+// if name is empty.
+func loadChoices(name string) (map[string]*choice, error) {
+	choices := make(map[string]*choice)
+	if name == "" {
+		return choices, nil
+	}
+	data, err := os.ReadFile(name)
+	if os.IsNotExist(err) {
+		return choices, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return choices, json.Unmarshal(data, &choices)
+}
+
+func saveChoices(name string, choices map[string]*choice) error {
+	if name == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(choices, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0o644)
+}
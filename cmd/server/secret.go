@@ -0,0 +1,86 @@
+package main
+
+import (
+	goflag "flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/metatube-community/metatube-sdk-go/common/secret"
+)
+
+const secretCommand = "secret"
+
+var (
+	// secrets resolves secret references of flags and command args.
+	secrets, _ = secret.NewResolver(nil)
+	// secretKey is the parsed secret key, nil if not set.
+	secretKey []byte
+)
+
+// resolveSecrets resolves secret references of all flags, e.g.,
+// `-dsn postgres://user:${env:PGPASSWORD}@host/db`. The secret key may
+// refer to the environment or a file itself, but can't be encrypted.
+func resolveSecrets() error {
+	if opts.secretKey != "" {
+		encoded, err := secrets.Resolve(opts.secretKey)
+		if err != nil {
+			return fmt.Errorf("secret-key: %w", err)
+		}
+		if secretKey, err = secret.ParseKey(encoded); err != nil {
+			return fmt.Errorf("secret-key: %w", err)
+		}
+		if secrets, err = secret.NewResolver(secretKey); err != nil {
+			return err
+		}
+	}
+	var err error
+	flag.VisitAll(func(f *goflag.Flag) {
+		if err != nil || f.Name == "secret-key" || !secret.HasRefs(f.Value.String()) {
+			return
+		}
+		var value string
+		if value, err = secrets.Resolve(f.Value.String()); err != nil {
+			err = fmt.Errorf("%s: %w", f.Name, err)
+			return
+		}
+		err = f.Value.Set(value)
+	})
+	return err
+}
+
+// runSecret runs the secret command with args, which is run before the
+// DB is opened:
+//
+//	secret keygen   print a new secret key
+//	secret encrypt  print the encrypted reference of the secret read from stdin
+func runSecret(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: secret keygen|encrypt")
+	}
+	switch args[0] {
+	case "keygen":
+		key, err := secret.GenerateKey()
+		if err != nil {
+			return err
+		}
+		fmt.Println(key)
+		return nil
+	case "encrypt":
+		if secretKey == nil {
+			return fmt.Errorf("secret-key is required to encrypt")
+		}
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return err
+		}
+		ref, err := secret.Encrypt(secretKey, strings.TrimRight(string(data), "\r\n"))
+		if err != nil {
+			return err
+		}
+		fmt.Println(ref)
+		return nil
+	}
+	return fmt.Errorf("unknown secret command: %s", args[0])
+}
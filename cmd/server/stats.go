@@ -0,0 +1,51 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+// runStats runs the stats command with args:
+//
+//	stats [limit]  print statistics of stored metadata, limit top makers and actors
+//
+// Cache and failure statistics are only collected by the server, see
+// the `/v1/stats` endpoint.
+func runStats(app *engine.Engine, args []string) error {
+	var limit int
+	if len(args) > 1 {
+		return fmt.Errorf("usage: stats [limit]")
+	} else if len(args) == 1 {
+		var err error
+		if limit, err = strconv.Atoi(args[0]); err != nil {
+			return fmt.Errorf("invalid limit: %s", args[0])
+		}
+	}
+	stats, err := app.GetStats(limit)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Movies\t%d\n", stats.Movies)
+	fmt.Fprintf(w, "Actors\t%d\n", stats.Actors)
+	fmt.Fprintf(w, "Completeness\t%.1f%%\n", stats.Completeness*100)
+	for _, section := range []struct {
+		title  string
+		counts []*engine.StatsCount
+	}{
+		{"Providers", stats.Providers},
+		{"Makers", stats.Makers},
+		{"Actors", stats.TopActors},
+		{"Years", stats.Years},
+	} {
+		fmt.Fprintf(w, "\n%s\n", section.title)
+		for _, c := range section.counts {
+			fmt.Fprintf(w, "  %s\t%d\n", c.Name, c.Count)
+		}
+	}
+	return w.Flush()
+}
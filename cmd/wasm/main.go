@@ -0,0 +1,86 @@
+//go:build js && wasm
+
+// Command wasm exposes the offline subset of the SDK, i.e., number parsing
+// and metadata export, to JavaScript as the global `metatube` object. The
+// scrapers are excluded, since browsers disallow cross-origin requests.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"syscall/js"
+
+	"github.com/metatube-community/metatube-sdk-go/common/number"
+	"github.com/metatube-community/metatube-sdk-go/common/parser"
+	"github.com/metatube-community/metatube-sdk-go/export"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func main() {
+	js.Global().Set("metatube", js.ValueOf(map[string]any{
+		"trimNumber":      stringFunc(number.Trim),
+		"isUncensored":    boolFunc(number.IsUncensored),
+		"isFC2":           boolFunc(number.IsFC2),
+		"isSpecial":       boolFunc(number.IsSpecial),
+		"parseIDToNumber": stringFunc(parser.ParseIDToNumber),
+		"canonicalize": js.FuncOf(func(_ js.Value, args []js.Value) any {
+			return number.Canonicalize(arg(args, 0), arg(args, 1))
+		}),
+		"exportDIDL": movieFunc(func(info *model.MovieInfo) (any, error) {
+			data, err := export.MarshalDIDL(info)
+			return string(data), err
+		}),
+		"exportJellyfin": movieFunc(func(info *model.MovieInfo) (any, error) {
+			return marshal(export.Jellyfin(info))
+		}),
+		"exportCast": movieFunc(func(info *model.MovieInfo) (any, error) {
+			return marshal(export.Cast(info))
+		}),
+	}))
+	select {} // keep the exported functions alive.
+}
+
+func arg(args []js.Value, i int) string {
+	if i >= len(args) || args[i].Type() != js.TypeString {
+		return ""
+	}
+	return args[i].String()
+}
+
+func stringFunc(fn func(string) string) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) any {
+		return fn(arg(args, 0))
+	})
+}
+
+func boolFunc(fn func(string) bool) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) any {
+		return fn(arg(args, 0))
+	})
+}
+
+// movieFunc decodes the movie info from the JSON argument, errors are
+// returned as JavaScript Error objects, since panics in callbacks would
+// terminate the Go program.
+func movieFunc(fn func(*model.MovieInfo) (any, error)) js.Func {
+	return js.FuncOf(func(_ js.Value, args []js.Value) any {
+		info := &model.MovieInfo{}
+		if err := json.Unmarshal([]byte(arg(args, 0)), info); err != nil {
+			return jsError(fmt.Errorf("invalid movie info: %w", err))
+		}
+		v, err := fn(info)
+		if err != nil {
+			return jsError(err)
+		}
+		return v
+	})
+}
+
+func jsError(err error) js.Value {
+	return js.Global().Get("Error").New(err.Error())
+}
+
+func marshal(v any) (any, error) {
+	data, err := json.Marshal(v)
+	return string(data), err
+}
@@ -0,0 +1,145 @@
+package bandwidth
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/errors"
+)
+
+var ErrQuotaExceeded = errors.New(http.StatusTooManyRequests, "bandwidth quota exceeded")
+
+// Usage is the bytes downloaded in the current period.
+type Usage struct {
+	Total int64            `json:"total"`
+	ByKey map[string]int64 `json:"by_key"`
+	// Since is the start of the current period.
+	Since time.Time `json:"since"`
+}
+
+// Meter accounts bytes downloaded by key, e.g., provider name, and caps
+// downloads by a global cap and quotas of keys. The usage is reset every
+// period, or never if period <= 0. Limits <= 0 are unlimited.
+type Meter struct {
+	mu     sync.Mutex
+	period time.Duration
+	cap    int64
+	quotas map[string]int64
+	usage  Usage
+}
+
+func New(period time.Duration, cap int64) *Meter {
+	return &Meter{
+		period: period,
+		cap:    cap,
+		quotas: make(map[string]int64),
+		usage: Usage{
+			ByKey: make(map[string]int64),
+			Since: time.Now(),
+		},
+	}
+}
+
+// SetQuota sets the quota of the key in bytes per period, keys of quotas
+// are case-insensitive.
+func (m *Meter) SetQuota(key string, quota int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.quotas[strings.ToLower(key)] = quota
+}
+
+// Allow returns ErrQuotaExceeded if the global cap or the quota of the
+// key is used up. Downloads in flight are never cut, so the usage might
+// exceed limits by the size of the last downloads.
+func (m *Meter) Allow(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rotate()
+	if m.cap > 0 && m.usage.Total >= m.cap {
+		return ErrQuotaExceeded
+	}
+	if quota := m.quotas[strings.ToLower(key)]; quota > 0 && m.usage.ByKey[key] >= quota {
+		return ErrQuotaExceeded
+	}
+	return nil
+}
+
+// Add accounts n bytes downloaded by the key.
+func (m *Meter) Add(key string, n int64) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rotate()
+	m.usage.Total += n
+	m.usage.ByKey[key] += n
+}
+
+// Usage returns a copy of the usage of the current period.
+func (m *Meter) Usage() Usage {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.rotate()
+	usage := m.usage
+	usage.ByKey = make(map[string]int64, len(m.usage.ByKey))
+	for key, n := range m.usage.ByKey {
+		usage.ByKey[key] = n
+	}
+	return usage
+}
+
+// rotate resets the usage if the period is over, m.mu must be held.
+func (m *Meter) rotate() {
+	if m.period <= 0 || time.Since(m.usage.Since) < m.period {
+		return
+	}
+	m.usage = Usage{
+		ByKey: make(map[string]int64),
+		Since: time.Now(),
+	}
+}
+
+// Body wraps the response body to account bytes read by the key.
+func (m *Meter) Body(key string, body io.ReadCloser) io.ReadCloser {
+	return &meteredBody{ReadCloser: body, meter: m, key: key}
+}
+
+// Wrapper returns the transport wrapper accounting responses by the key.
+func (m *Meter) Wrapper(key string) func(http.RoundTripper) http.RoundTripper {
+	return func(base http.RoundTripper) http.RoundTripper {
+		return &transport{base: base, meter: m, key: key}
+	}
+}
+
+type transport struct {
+	base  http.RoundTripper
+	meter *Meter
+	key   string
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if err := t.meter.Allow(t.key); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = t.meter.Body(t.key, resp.Body)
+	return resp, nil
+}
+
+type meteredBody struct {
+	io.ReadCloser
+	meter *Meter
+	key   string
+}
+
+func (b *meteredBody) Read(p []byte) (n int, err error) {
+	n, err = b.ReadCloser.Read(p)
+	if n > 0 {
+		b.meter.Add(b.key, int64(n))
+	}
+	return
+}
@@ -0,0 +1,54 @@
+package bandwidth
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMeter(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = io.WriteString(w, strings.Repeat("x", 100))
+	}))
+	defer srv.Close()
+
+	m := New(0, 250)
+	m.SetQuota("A", 150)
+	get := func(key string) error {
+		c := &http.Client{Transport: m.Wrapper(key)(http.DefaultTransport)}
+		resp, err := c.Get(srv.URL)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, err = io.ReadAll(resp.Body)
+		return err
+	}
+
+	assert.NoError(t, get("a"))
+	assert.NoError(t, get("a"))
+	// quota of a exceeded.
+	assert.ErrorIs(t, get("a"), ErrQuotaExceeded)
+	assert.NoError(t, get("b"))
+	// global cap exceeded.
+	assert.ErrorIs(t, get("b"), ErrQuotaExceeded)
+
+	usage := m.Usage()
+	assert.Equal(t, int64(300), usage.Total)
+	assert.Equal(t, int64(200), usage.ByKey["a"])
+	assert.Equal(t, int64(100), usage.ByKey["b"])
+}
+
+func TestMeter_Period(t *testing.T) {
+	m := New(time.Millisecond, 10)
+	m.Add("a", 10)
+	assert.ErrorIs(t, m.Allow("a"), ErrQuotaExceeded)
+	time.Sleep(2 * time.Millisecond)
+	assert.NoError(t, m.Allow("a"))
+	assert.Zero(t, m.Usage().Total)
+}
@@ -0,0 +1,88 @@
+// Package canonurl canonicalizes page URLs of providers, so that variants of
+// the same page, e.g., with tracking params or of other hosts, are stored and
+// cached as one.
+package canonurl
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// trackingParams are query params of tracking and affiliate links, which
+// never change contents of pages.
+var trackingParams = map[string]struct{}{
+	"fbclid":       {},
+	"gclid":        {},
+	"yclid":        {},
+	"msclkid":      {},
+	"mc_cid":       {},
+	"mc_eid":       {},
+	"af_id":        {},
+	"aff_id":       {},
+	"affi_id":      {},
+	"affiliate_id": {},
+	"dmmref":       {},
+	"i3_ref":       {},
+	"i3_ord":       {},
+}
+
+// IsTrackingParam reports whether the query param is of tracking or
+// affiliate links, e.g., `utm_source` and `af_id`.
+func IsTrackingParam(name string) bool {
+	name = strings.ToLower(name)
+	if strings.HasPrefix(name, "utm_") {
+		return true
+	}
+	_, ok := trackingParams[name]
+	return ok
+}
+
+// Canonicalize returns the canonical form of the URL, which is of https,
+// without tracking params, fragments or default ports, and with remaining
+// params sorted. The host is replaced by the first of hosts which it equals
+// regardless of cases and the `www.` prefix. URLs not of http(s) are kept
+// as is.
+func Canonicalize(rawURL string, hosts ...string) string {
+	u, err := url.Parse(strings.TrimSpace(rawURL))
+	if err != nil || u.Host == "" {
+		return rawURL
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		u.Scheme = "https"
+	default:
+		return rawURL
+	}
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host = net.JoinHostPort(host, port)
+	}
+	for _, h := range hosts {
+		if h != "" && strings.EqualFold(bareHost(h), bareHost(host)) {
+			host = strings.ToLower(h)
+			break
+		}
+	}
+	u.Host = host
+	u.User = nil
+	u.Fragment, u.RawFragment = "", ""
+	if u.RawQuery != "" {
+		query := u.Query()
+		for name := range query {
+			if IsTrackingParam(name) {
+				query.Del(name)
+			}
+		}
+		u.RawQuery = query.Encode() // sorted by names.
+	}
+	u.ForceQuery = false
+	if u.Path == "" {
+		u.Path = "/"
+	}
+	return u.String()
+}
+
+func bareHost(host string) string {
+	return strings.TrimPrefix(strings.ToLower(host), "www.")
+}
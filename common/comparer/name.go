@@ -0,0 +1,155 @@
+package comparer
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/width"
+)
+
+// CompareName returns the similarity between two person names, which is
+// aware of character widths, kana scripts, romaji spellings and the order
+// of given and family names.
+func CompareName(a, b string) (similarity float64) {
+	for _, x := range nameVariants(a) {
+		for _, y := range nameVariants(b) {
+			similarity = max(similarity,
+				Compare(x, y), Compare(Romanize(x), Romanize(y)))
+		}
+	}
+	return
+}
+
+// SameName reports whether the two person names are the same spelling-wise,
+// i.e., equal regardless of character widths, kana scripts, romaji spellings
+// and name orders. Unlike CompareName, similar names are never the same.
+func SameName(a, b string) bool {
+	for _, x := range nameVariants(a) {
+		for _, y := range nameVariants(b) {
+			if x != "" && (x == y || Romanize(x) == Romanize(y)) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// nameVariants returns the normalized name, and the one of reversed name
+// order if the name is split by spaces, e.g., `Yua Mikami`.
+func nameVariants(s string) []string {
+	s = width.Fold.String(s)
+	variants := []string{NormalizeName(s)}
+	if fields := strings.Fields(s); len(fields) > 1 {
+		for i, j := 0, len(fields)-1; i < j; i, j = i+1, j-1 {
+			fields[i], fields[j] = fields[j], fields[i]
+		}
+		variants = append(variants, NormalizeName(strings.Join(fields, "")))
+	}
+	return variants
+}
+
+// NormalizeName folds widths and cases of the name, converts katakana to
+// hiragana, and removes spaces and separators.
+func NormalizeName(s string) string {
+	var sb strings.Builder
+	for _, r := range width.Fold.String(s) {
+		switch {
+		case unicode.IsSpace(r) || unicode.IsPunct(r) || r == '・' || r == '･':
+			continue
+		case r >= 'ァ' && r <= 'ヶ':
+			r -= 'ァ' - 'ぁ' // katakana to hiragana.
+		}
+		sb.WriteRune(unicode.ToLower(r))
+	}
+	return sb.String()
+}
+
+// Romanize converts kana of the normalized name to Hepburn romaji, long
+// vowels are shortened so that `Yuu`, `Yū` and `Yu` are the same. Other
+// characters, e.g., kanji, are kept as they are.
+func Romanize(s string) string {
+	var (
+		out    []byte
+		sokuon bool
+	)
+	for _, r := range NormalizeName(s) {
+		switch {
+		case r == 'っ':
+			sokuon = true
+			continue
+		case r == 'ー':
+			continue // long vowel.
+		case r == 'ゃ' || r == 'ゅ' || r == 'ょ':
+			if n := len(out); n >= 2 && out[n-1] == 'i' {
+				out = out[:n-1]
+				vowel := kanaRomaji[r]
+				if n := len(out); n > 0 && (out[n-1] == 'h' || out[n-1] == 'j') {
+					vowel = vowel[1:] // e.g., sha, cha, ja.
+				}
+				out = append(out, vowel...)
+				continue
+			}
+		case r == 'ぁ' || r == 'ぃ' || r == 'ぅ' || r == 'ぇ' || r == 'ぉ':
+			if n := len(out); n >= 2 && isVowel(out[n-1]) {
+				out = append(out[:n-1], kanaRomaji[r]...)
+				continue
+			}
+		}
+		rom, ok := kanaRomaji[r]
+		if !ok {
+			rom = string(macronVowel(r))
+		}
+		if sokuon && rom != "" && !isVowel(rom[0]) {
+			if strings.HasPrefix(rom, "ch") {
+				out = append(out, 't')
+			} else {
+				out = append(out, rom[0])
+			}
+		}
+		sokuon = false
+		out = append(out, rom...)
+	}
+	return longVowelReplacer.Replace(string(out))
+}
+
+var longVowelReplacer = strings.NewReplacer("ou", "o", "oo", "o", "uu", "u")
+
+func isVowel(c byte) bool {
+	return strings.IndexByte("aiueo", c) >= 0
+}
+
+func macronVowel(r rune) rune {
+	switch r {
+	case 'ā', 'â':
+		return 'a'
+	case 'ī', 'î':
+		return 'i'
+	case 'ū', 'û':
+		return 'u'
+	case 'ē', 'ê':
+		return 'e'
+	case 'ō', 'ô':
+		return 'o'
+	}
+	return r
+}
+
+var kanaRomaji = map[rune]string{
+	'あ': "a", 'い': "i", 'う': "u", 'え': "e", 'お': "o",
+	'か': "ka", 'き': "ki", 'く': "ku", 'け': "ke", 'こ': "ko",
+	'さ': "sa", 'し': "shi", 'す': "su", 'せ': "se", 'そ': "so",
+	'た': "ta", 'ち': "chi", 'つ': "tsu", 'て': "te", 'と': "to",
+	'な': "na", 'に': "ni", 'ぬ': "nu", 'ね': "ne", 'の': "no",
+	'は': "ha", 'ひ': "hi", 'ふ': "fu", 'へ': "he", 'ほ': "ho",
+	'ま': "ma", 'み': "mi", 'む': "mu", 'め': "me", 'も': "mo",
+	'や': "ya", 'ゆ': "yu", 'よ': "yo",
+	'ら': "ra", 'り': "ri", 'る': "ru", 'れ': "re", 'ろ': "ro",
+	'わ': "wa", 'ゐ': "i", 'ゑ': "e", 'を': "o", 'ん': "n",
+	'が': "ga", 'ぎ': "gi", 'ぐ': "gu", 'げ': "ge", 'ご': "go",
+	'ざ': "za", 'じ': "ji", 'ず': "zu", 'ぜ': "ze", 'ぞ': "zo",
+	'だ': "da", 'ぢ': "ji", 'づ': "zu", 'で': "de", 'ど': "do",
+	'ば': "ba", 'び': "bi", 'ぶ': "bu", 'べ': "be", 'ぼ': "bo",
+	'ぱ': "pa", 'ぴ': "pi", 'ぷ': "pu", 'ぺ': "pe", 'ぽ': "po",
+	'ぁ': "a", 'ぃ': "i", 'ぅ': "u", 'ぇ': "e", 'ぉ': "o",
+	'ゃ': "ya", 'ゅ': "yu", 'ょ': "yo", 'ゎ': "wa", 'ゔ': "vu",
+}
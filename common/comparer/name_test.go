@@ -0,0 +1,50 @@
+package comparer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRomanize(t *testing.T) {
+	for _, unit := range []struct {
+		s, want string
+	}{
+		{"みかみ ゆあ", "mikamiyua"},
+		{"ミカミ　ユア", "mikamiyua"},
+		{"しゅうと", "shuto"},
+		{"きょうこ", "kyoko"},
+		{"ちゃん", "chan"},
+		{"はっとり", "hattori"},
+		{"まっちゃ", "matcha"},
+		{"Yūki", "yuki"},
+		{"三上悠亜", "三上悠亜"},
+	} {
+		assert.Equal(t, unit.want, Romanize(unit.s), unit.s)
+	}
+}
+
+func TestCompareName(t *testing.T) {
+	for _, unit := range []struct {
+		a, b string
+	}{
+		{"三上悠亜", "三上悠亜"},
+		{"つぼみ", "ツボミ"},
+		{"Yua Mikami", "みかみゆあ"},
+		{"Mikami Yua", "Yua Mikami"},
+		{"ＹＵＡ　ＭＩＫＡＭＩ", "yua mikami"},
+		{"Yuu Shinoda", "しのだゆう"},
+	} {
+		assert.Equal(t, 1.0, CompareName(unit.a, unit.b), "%s vs %s", unit.a, unit.b)
+	}
+	assert.Less(t, CompareName("三上悠亜", "松下紗栄子"), 0.5)
+}
+
+func TestSameName(t *testing.T) {
+	assert.True(t, SameName("Yua Mikami", "みかみゆあ"))
+	assert.True(t, SameName("Mikami Yua", "ミカミ ユア"))
+	assert.True(t, SameName("Yūki", "ゆうき"))
+	assert.False(t, SameName("Yua Mikami", "Yui Mikami"))
+	assert.False(t, SameName("三上悠亜", "Yua Mikami"))
+	assert.False(t, SameName("", ""))
+}
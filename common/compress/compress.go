@@ -0,0 +1,260 @@
+// Package compress decodes and encodes HTTP bodies of the gzip, deflate,
+// br and zstd content encodings, decoded sizes are limited to guard
+// against decompression bombs.
+package compress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+	"github.com/klauspost/compress/zstd"
+)
+
+// DefaultMaxSize is the default max size of decoded bodies.
+const DefaultMaxSize = 64 << 20
+
+// AcceptEncoding is the `Accept-Encoding` of all supported encodings.
+const AcceptEncoding = "gzip, deflate, br, zstd"
+
+const (
+	Gzip    = "gzip"
+	Deflate = "deflate"
+	Brotli  = "br"
+	Zstd    = "zstd"
+)
+
+var (
+	ErrTooLarge    = errors.New("decoded body too large")
+	ErrUnsupported = errors.New("unsupported content encoding")
+)
+
+// preferred are the encodings of responses, in the order of preference.
+var preferred = []string{Zstd, Brotli, Gzip}
+
+// parseEncodings returns the encodings in the order applied, identity
+// is omitted.
+func parseEncodings(s string) (encodings []string, err error) {
+	for _, e := range strings.Split(s, ",") {
+		switch e = strings.ToLower(strings.TrimSpace(e)); e {
+		case "", "identity":
+		case Gzip, "x-gzip":
+			encodings = append(encodings, Gzip)
+		case Deflate, Brotli, Zstd:
+			encodings = append(encodings, e)
+		default:
+			return nil, fmt.Errorf("%w: %s", ErrUnsupported, e)
+		}
+	}
+	return
+}
+
+// NewReader returns the reader of the body decoded by the content
+// encodings, which fails with ErrTooLarge if the decoded body exceeds
+// maxSize. Decoders are created on the first read, so empty bodies of
+// encoded responses are valid. Closing it closes the body.
+func NewReader(body io.ReadCloser, encoding string, maxSize int64) (io.ReadCloser, error) {
+	encodings, err := parseEncodings(encoding)
+	if err != nil {
+		return nil, err
+	}
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	return &reader{body: body, encodings: encodings, remaining: maxSize + 1}, nil
+}
+
+type reader struct {
+	body      io.ReadCloser
+	encodings []string
+	decoder   io.Reader
+	closers   []func()
+	remaining int64
+	err       error
+}
+
+func (r *reader) init() error {
+	var src io.Reader = r.body
+	// decode in the reverse order of applied.
+	for i := len(r.encodings) - 1; i >= 0; i-- {
+		switch r.encodings[i] {
+		case Gzip:
+			zr, err := gzip.NewReader(src)
+			if err != nil {
+				return err // io.EOF if empty.
+			}
+			r.closers = append(r.closers, func() { _ = zr.Close() })
+			src = zr
+		case Deflate:
+			fr := flate.NewReader(src)
+			r.closers = append(r.closers, func() { _ = fr.Close() })
+			src = fr
+		case Brotli:
+			src = brotli.NewReader(src)
+		case Zstd:
+			zr, err := zstd.NewReader(src, zstd.WithDecoderConcurrency(1))
+			if err != nil {
+				return err
+			}
+			r.closers = append(r.closers, zr.Close)
+			src = zr
+		}
+	}
+	r.decoder = src
+	return nil
+}
+
+func (r *reader) Read(p []byte) (n int, err error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	if r.decoder == nil {
+		if r.err = r.init(); r.err != nil {
+			return 0, r.err
+		}
+	}
+	if int64(len(p)) > r.remaining {
+		p = p[:r.remaining]
+	}
+	n, err = r.decoder.Read(p)
+	if r.remaining -= int64(n); r.remaining <= 0 {
+		// one more byte than the max size has been read.
+		r.err = ErrTooLarge
+		return n - 1, r.err
+	}
+	return n, err
+}
+
+func (r *reader) Close() error {
+	for _, c := range r.closers {
+		c()
+	}
+	return r.body.Close()
+}
+
+// Transport is an http.RoundTripper which negotiates all supported
+// encodings and decodes responses, unless the request sets its own
+// `Accept-Encoding`. Responses of unsupported encodings are returned
+// as is.
+type Transport struct {
+	// Base is the underlying transport, http.DefaultTransport if nil.
+	Base http.RoundTripper
+	// MaxSize is the max size of decoded bodies, DefaultMaxSize if zero.
+	MaxSize int64
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	maxSize := t.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if req.Header.Get("Accept-Encoding") != "" {
+		return base.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Accept-Encoding", AcceptEncoding)
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	encoding := resp.Header.Get("Content-Encoding")
+	if encoding == "" || resp.Uncompressed || req.Method == http.MethodHead {
+		return resp, nil
+	}
+	// reject before reading anything if declared insane.
+	if resp.ContentLength > maxSize {
+		_ = resp.Body.Close()
+		return nil, fmt.Errorf("%w: content length %d", ErrTooLarge, resp.ContentLength)
+	}
+	body, err := NewReader(resp.Body, encoding, maxSize)
+	if err != nil {
+		return resp, nil // unsupported, as is.
+	}
+	resp.Body = body
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+	return resp, nil
+}
+
+// Negotiate returns the preferred encoding accepted by the
+// `Accept-Encoding`, or empty if none is accepted.
+func Negotiate(acceptEncoding string) string {
+	var (
+		best  string
+		bestQ float64
+	)
+	weights := make(map[string]float64)
+	for _, part := range strings.Split(acceptEncoding, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		q := 1.0
+		if k, v, ok := strings.Cut(strings.TrimSpace(params), "="); ok && strings.TrimSpace(k) == "q" {
+			if f, err := strconv.ParseFloat(strings.TrimSpace(v), 64); err == nil {
+				q = f
+			}
+		}
+		weights[strings.ToLower(strings.TrimSpace(name))] = q
+	}
+	for _, e := range preferred {
+		q, ok := weights[e]
+		if !ok {
+			if q, ok = weights["*"]; !ok {
+				continue
+			}
+		}
+		if q > bestQ {
+			best, bestQ = e, q
+		}
+	}
+	return best
+}
+
+// NewWriter returns the writer encoding into w, which must be closed to
+// flush the encoded data.
+func NewWriter(w io.Writer, encoding string) (io.WriteCloser, error) {
+	switch encoding {
+	case Gzip:
+		return gzip.NewWriterLevel(w, gzip.DefaultCompression)
+	case Brotli:
+		return brotli.NewWriterLevel(w, brotli.DefaultCompression), nil
+	case Zstd:
+		return zstd.NewWriter(w,
+			zstd.WithEncoderLevel(zstd.SpeedDefault),
+			zstd.WithEncoderConcurrency(1),
+			zstd.WithLowerEncoderMem(true))
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnsupported, encoding)
+}
+
+// Compressible reports whether bodies of the content type are worth
+// compressing, media types are mostly compressed already.
+func Compressible(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	switch {
+	case strings.HasPrefix(mediaType, "text/"),
+		strings.HasSuffix(mediaType, "+json"),
+		strings.HasSuffix(mediaType, "+xml"):
+		return true
+	}
+	switch mediaType {
+	case "application/json", "application/xml",
+		"application/javascript", "application/x-ndjson":
+		return true
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+package compress
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func encode(t *testing.T, encoding string, data []byte) []byte {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, encoding)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	_, _ = w.Write(data)
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+func TestTransport(t *testing.T) {
+	const body = "<html>metatube</html>"
+	for _, encoding := range []string{Gzip, Brotli, Zstd} {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, AcceptEncoding, r.Header.Get("Accept-Encoding"))
+			w.Header().Set("Content-Encoding", encoding)
+			_, _ = w.Write(encode(t, encoding, []byte(body)))
+		}))
+		c := &http.Client{Transport: &Transport{}}
+		resp, err := c.Get(srv.URL)
+		if assert.NoError(t, err, encoding) {
+			data, err := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			assert.NoError(t, err)
+			assert.Equal(t, body, string(data), encoding)
+			assert.Empty(t, resp.Header.Get("Content-Encoding"))
+		}
+		srv.Close()
+	}
+}
+
+func TestTransportLimits(t *testing.T) {
+	bomb := encode(t, Zstd, make([]byte, 1<<20))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", Zstd)
+		if r.URL.Path == "/length" {
+			w.Header().Set("Content-Length", "4096")
+			_, _ = w.Write(make([]byte, 4096))
+			return
+		}
+		_, _ = w.Write(bomb)
+	}))
+	defer srv.Close()
+
+	c := &http.Client{Transport: &Transport{MaxSize: 1024}}
+	resp, err := c.Get(srv.URL)
+	if assert.NoError(t, err) {
+		data, err := io.ReadAll(resp.Body)
+		_ = resp.Body.Close()
+		assert.ErrorIs(t, err, ErrTooLarge)
+		assert.Len(t, data, 1024)
+	}
+	_, err = c.Get(srv.URL + "/length")
+	assert.ErrorIs(t, err, ErrTooLarge)
+}
+
+func TestNewReader(t *testing.T) {
+	// encodings are decoded in the reverse order of applied.
+	data := encode(t, Brotli, encode(t, Gzip, []byte("data")))
+	r, err := NewReader(io.NopCloser(bytes.NewReader(data)), "gzip, br", 0)
+	if assert.NoError(t, err) {
+		b, err := io.ReadAll(r)
+		assert.NoError(t, err)
+		assert.Equal(t, "data", string(b))
+	}
+	// empty bodies are valid.
+	r, _ = NewReader(io.NopCloser(strings.NewReader("")), Gzip, 0)
+	b, err := io.ReadAll(r)
+	assert.NoError(t, err)
+	assert.Empty(t, b)
+
+	_, err = NewReader(io.NopCloser(strings.NewReader("")), "compress", 0)
+	assert.ErrorIs(t, err, ErrUnsupported)
+}
+
+func TestNegotiate(t *testing.T) {
+	for _, unit := range []struct {
+		accept, want string
+	}{
+		{"", ""},
+		{"identity", ""},
+		{"gzip, deflate", Gzip},
+		{"gzip, deflate, br, zstd", Zstd},
+		{"gzip;q=1.0, br;q=0.5", Gzip},
+		{"br, zstd;q=0", Brotli},
+		{"*", Zstd},
+	} {
+		assert.Equal(t, unit.want, Negotiate(unit.accept), unit.accept)
+	}
+}
+
+func TestCompressible(t *testing.T) {
+	assert.True(t, Compressible("application/json; charset=utf-8"))
+	assert.True(t, Compressible("text/xml"))
+	assert.True(t, Compressible("application/graphql-response+json"))
+	assert.False(t, Compressible("image/jpeg"))
+	assert.False(t, Compressible(""))
+}
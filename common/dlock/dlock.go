@@ -0,0 +1,177 @@
+// Package dlock provides locks of keys, which are shared by the server
+// instances of a deployment if backed by Redis or Postgres, e.g., so that
+// the same movie is never scraped by multiple instances concurrently.
+package dlock
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"hash/fnv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// Locker locks keys.
+type Locker interface {
+	// Lock waits for the lock of the key until the context is done, and
+	// returns the function to unlock it.
+	Lock(ctx context.Context, key string) (unlock func(), err error)
+}
+
+// Local is a Locker of a single process.
+type Local struct {
+	mu    sync.Mutex
+	locks map[string]chan struct{}
+}
+
+// NewLocal returns a new *Local.
+func NewLocal() *Local {
+	return &Local{locks: make(map[string]chan struct{})}
+}
+
+func (l *Local) Lock(ctx context.Context, key string) (func(), error) {
+	for {
+		l.mu.Lock()
+		held, ok := l.locks[key]
+		if !ok {
+			ch := make(chan struct{})
+			l.locks[key] = ch
+			l.mu.Unlock()
+			return func() {
+				l.mu.Lock()
+				delete(l.locks, key)
+				l.mu.Unlock()
+				close(ch)
+			}, nil
+		}
+		l.mu.Unlock()
+		select {
+		case <-held:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+const redisPollInterval = 100 * time.Millisecond
+
+// releaseScript deletes the lock only if it's still held by the token.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+// renewScript extends the lease only if the lock is still held by the token.
+var renewScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0`)
+
+// Redis is a Locker on Redis or Valkey servers. Locks expire after the
+// lease, so that locks of crashed instances are never held forever, while
+// the lease is renewed periodically as long as the lock is held.
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+	lease  time.Duration
+}
+
+// NewRedis returns a *Redis of the client, keys are prefixed with the
+// prefix.
+func NewRedis(client redis.UniversalClient, prefix string, lease time.Duration) *Redis {
+	return &Redis{client: client, prefix: prefix, lease: lease}
+}
+
+func (r *Redis) Lock(ctx context.Context, key string) (func(), error) {
+	key = r.prefix + "lock:" + key
+	token := randomToken()
+	for {
+		ok, err := r.client.SetNX(ctx, key, token, r.lease).Result()
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			stop := make(chan struct{})
+			go r.renew(key, token, stop)
+			var once sync.Once
+			return func() {
+				once.Do(func() {
+					close(stop)
+					releaseScript.Run(context.Background(), r.client, []string{key}, token)
+				})
+			}, nil
+		}
+		select {
+		case <-time.After(redisPollInterval):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// renew extends the lease of the lock every third of the lease until
+// stopped, or the lock is lost, e.g., expired while Redis is unreachable.
+func (r *Redis) renew(key, token string, stop <-chan struct{}) {
+	ticker := time.NewTicker(r.lease / 3)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			ctx, cancel := context.WithTimeout(context.Background(), r.lease/3)
+			n, err := renewScript.Run(ctx, r.client, []string{key}, token, r.lease.Milliseconds()).Int()
+			cancel()
+			if err == nil && n == 0 {
+				return // lost.
+			}
+		case <-stop:
+			return
+		}
+	}
+}
+
+// Postgres is a Locker of Postgres session advisory locks, which are
+// released by the server if the connection is lost. Each lock held takes
+// a connection of the pool.
+type Postgres struct {
+	db *sql.DB
+}
+
+// NewPostgres returns a *Postgres of the DB.
+func NewPostgres(db *sql.DB) *Postgres {
+	return &Postgres{db: db}
+}
+
+func (p *Postgres) Lock(ctx context.Context, key string) (func(), error) {
+	conn, err := p.db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+	id := advisoryKey(key)
+	if _, err = conn.ExecContext(ctx, "SELECT pg_advisory_lock($1)", id); err != nil {
+		_ = conn.Close()
+		return nil, err
+	}
+	return func() {
+		_, _ = conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", id)
+		_ = conn.Close()
+	}, nil
+}
+
+// advisoryKey hashes the key into the bigint key of advisory locks.
+func advisoryKey(key string) int64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	return int64(h.Sum64())
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
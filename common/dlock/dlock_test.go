@@ -0,0 +1,98 @@
+package dlock
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func testLocker(t *testing.T, l Locker) {
+	var (
+		wg      sync.WaitGroup
+		running atomic.Int32
+		maxRun  atomic.Int32
+	)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			unlock, err := l.Lock(context.Background(), "movie:ABC-123")
+			if !assert.NoError(t, err) {
+				return
+			}
+			defer unlock()
+			n := running.Add(1)
+			if n > maxRun.Load() {
+				maxRun.Store(n)
+			}
+			time.Sleep(20 * time.Millisecond)
+			running.Add(-1)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, int32(1), maxRun.Load())
+
+	// other keys are independent.
+	unlock, err := l.Lock(context.Background(), "a")
+	if assert.NoError(t, err) {
+		defer unlock()
+		unlockB, err := l.Lock(context.Background(), "b")
+		if assert.NoError(t, err) {
+			unlockB()
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		_, err = l.Lock(ctx, "a")
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	}
+}
+
+func TestLocal(t *testing.T) {
+	testLocker(t, NewLocal())
+}
+
+func TestRedis(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer client.Close()
+	testLocker(t, NewRedis(client, "test:", time.Minute))
+
+	// expired locks of crashed instances are taken over.
+	l := NewRedis(client, "test:", time.Second)
+	_, err := l.Lock(context.Background(), "crashed")
+	assert.NoError(t, err)
+	s.FastForward(2 * time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	unlock, err := l.Lock(ctx, "crashed")
+	if assert.NoError(t, err) {
+		unlock()
+		assert.False(t, s.Exists("test:lock:crashed"))
+	}
+}
+
+func TestRedis_Renew(t *testing.T) {
+	s := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: s.Addr()})
+	defer client.Close()
+
+	l := NewRedis(client, "test:", 300*time.Millisecond)
+	unlock, err := l.Lock(context.Background(), "held")
+	if !assert.NoError(t, err) {
+		return
+	}
+	s.FastForward(200 * time.Millisecond)
+	// the lease is renewed while the lock is held.
+	assert.Eventually(t, func() bool {
+		return s.TTL("test:lock:held") > 200*time.Millisecond
+	}, time.Second, 10*time.Millisecond)
+	unlock()
+	unlock() // no-op.
+	assert.False(t, s.Exists("test:lock:held"))
+}
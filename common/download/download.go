@@ -0,0 +1,242 @@
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/storage"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+)
+
+// Suffixes of the partial file and its resume state.
+const (
+	partSuffix  = ".part"
+	stateSuffix = ".part.json"
+)
+
+var ErrChecksumMismatch = errors.New(http.StatusUnprocessableEntity, "checksum mismatch")
+
+// GetFunc makes a GET request with fetch options, e.g., (*fetch.Fetcher).Get.
+// Implementations ignoring options still work, but never resume.
+type GetFunc func(url string, opts ...fetch.Option) (*http.Response, error)
+
+// Result is the file downloaded.
+type Result struct {
+	URL    string `json:"url"`
+	Path   string `json:"path"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+	// Resumed reports whether a partial download was continued.
+	Resumed bool `json:"resumed"`
+	// Skipped reports whether the file was complete already.
+	Skipped bool `json:"skipped"`
+}
+
+// state is the validator of the partial file, which makes sure the rest
+// is requested from the same version of the remote file.
+type state struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+}
+
+func (s *state) validator() string {
+	if s.ETag != "" && !strings.HasPrefix(s.ETag, "W/") /* strong only */ {
+		return s.ETag
+	}
+	return s.LastModified
+}
+
+// Downloader downloads files resumably, interrupted downloads are kept as
+// partial files, and continued by range requests next time.
+type Downloader struct {
+	get GetFunc
+}
+
+func New(get GetFunc) *Downloader {
+	return &Downloader{get: get}
+}
+
+// Download downloads the url to the file name. If sum, the hex SHA-256 of
+// the file, is given, an existing file of the same sum is not downloaded
+// again, and the completed file is verified against it.
+func (d *Downloader) Download(url, name, sum string) (*Result, error) {
+	if sum != "" {
+		if size, actual, err := hashFile(name); err == nil && strings.EqualFold(actual, sum) {
+			return &Result{URL: url, Path: name, Size: size, SHA256: actual, Skipped: true}, nil
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return nil, err
+	}
+
+	var (
+		part    = name + partSuffix
+		offset  int64
+		opts    []fetch.Option
+		resumed bool
+	)
+	if s, err := readState(name + stateSuffix); err == nil && s.URL == url && s.validator() != "" {
+		if fi, err := os.Stat(part); err == nil && fi.Size() > 0 {
+			offset = fi.Size()
+			opts = append(opts,
+				fetch.WithHeader("Range", fmt.Sprintf("bytes=%d-", offset)),
+				fetch.WithHeader("If-Range", s.validator()))
+		}
+	}
+	resp, err := d.get(url, append(opts, fetch.WithRaiseForStatus(false))...)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var (
+		body io.Reader = resp.Body
+		flag           = os.O_CREATE | os.O_WRONLY
+	)
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		if offset == 0 || !strings.HasPrefix(resp.Header.Get("Content-Range"), fmt.Sprintf("bytes %d-", offset)) {
+			return nil, fmt.Errorf("unexpected content range: %s", resp.Header.Get("Content-Range"))
+		}
+		flag |= os.O_APPEND
+		resumed = true
+	case http.StatusOK:
+		// range unsupported or remote file changed, restart.
+		flag |= os.O_TRUNC
+		if err = writeState(name+stateSuffix, &state{
+			URL:          url,
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+		}); err != nil {
+			return nil, err
+		}
+	case http.StatusRequestedRangeNotSatisfiable:
+		if offset == 0 {
+			return nil, errors.FromCode(resp.StatusCode)
+		}
+		// the partial file of the same validator is complete if it's of
+		// the remote size, e.g., interrupted right before renamed.
+		if remoteSize(resp.Header.Get("Content-Range")) != offset {
+			// longer than the remote file, start over.
+			_ = resp.Body.Close()
+			_ = os.Remove(part)
+			_ = os.Remove(name + stateSuffix)
+			return d.Download(url, name, sum)
+		}
+		flag |= os.O_APPEND
+		body, resumed = http.NoBody, true
+	default:
+		return nil, errors.FromCode(resp.StatusCode)
+	}
+
+	f, err := os.OpenFile(part, flag, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	_, err = io.Copy(f, body)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err // keep the partial file to resume.
+	}
+
+	size, actual, err := hashFile(part)
+	if err != nil {
+		return nil, err
+	}
+	if sum != "" && !strings.EqualFold(actual, sum) {
+		// the partial file is corrupted, start over next time.
+		_ = os.Remove(part)
+		_ = os.Remove(name + stateSuffix)
+		return nil, ErrChecksumMismatch
+	}
+	if err = os.Rename(part, name); err != nil {
+		return nil, err
+	}
+	_ = os.Remove(name + stateSuffix)
+	return &Result{URL: url, Path: name, Size: size, SHA256: actual, Resumed: resumed}, nil
+}
+
+// Put downloads the url into the blob store by name, which is streamed
+// without resuming, since partial objects are not kept by blob stores.
+func (d *Downloader) Put(b storage.Blob, url, name string) (*Result, error) {
+	resp, err := d.get(url, fetch.WithRaiseForStatus(false))
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, errors.FromCode(resp.StatusCode)
+	}
+	var (
+		h = sha256.New()
+		n = &countWriter{}
+	)
+	if err = b.Put(name, io.TeeReader(resp.Body, io.MultiWriter(h, n))); err != nil {
+		return nil, err
+	}
+	return &Result{URL: url, Path: name, Size: n.n, SHA256: hex.EncodeToString(h.Sum(nil))}, nil
+}
+
+type countWriter struct{ n int64 }
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
+
+// remoteSize returns the complete length of the unsatisfied content range,
+// e.g., `bytes */1024`, or -1 if unknown.
+func remoteSize(contentRange string) int64 {
+	_, size, ok := strings.Cut(contentRange, "/")
+	if !ok {
+		return -1
+	}
+	n, err := strconv.ParseInt(strings.TrimSpace(size), 10, 64)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+func hashFile(name string) (int64, string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return 0, "", err
+	}
+	defer f.Close()
+	h := sha256.New()
+	n, err := io.Copy(h, f)
+	if err != nil {
+		return 0, "", err
+	}
+	return n, hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func readState(name string) (*state, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	s := &state{}
+	return s, json.Unmarshal(data, s)
+}
+
+func writeState(name string, s *state) error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0o644)
+}
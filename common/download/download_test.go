@@ -0,0 +1,128 @@
+package download
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/storage"
+)
+
+func TestDownloader_Download(t *testing.T) {
+	content := strings.Repeat("metatube", 1024)
+	sum := sha256.Sum256([]byte(content))
+	hexSum := hex.EncodeToString(sum[:])
+
+	var ranges []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "trailer.mp4", time.Time{}, strings.NewReader(content))
+	}))
+	defer srv.Close()
+
+	d := New(fetch.Default(nil).Get)
+	name := filepath.Join(t.TempDir(), "trailer.mp4")
+
+	// simulate an interrupted download.
+	assert.NoError(t, writeState(name+stateSuffix, &state{URL: srv.URL, ETag: `"v1"`}))
+	assert.NoError(t, os.WriteFile(name+partSuffix, []byte(content[:1000]), 0o644))
+
+	result, err := d.Download(srv.URL, name, hexSum)
+	if assert.NoError(t, err) {
+		assert.True(t, result.Resumed)
+		assert.Equal(t, int64(len(content)), result.Size)
+		assert.Equal(t, hexSum, result.SHA256)
+		assert.Equal(t, []string{"bytes=1000-"}, ranges)
+		data, _ := os.ReadFile(name)
+		assert.Equal(t, content, string(data))
+	}
+	assert.NoFileExists(t, name+partSuffix)
+	assert.NoFileExists(t, name+stateSuffix)
+
+	// complete files are skipped.
+	result, err = d.Download(srv.URL, name, hexSum)
+	if assert.NoError(t, err) {
+		assert.True(t, result.Skipped)
+		assert.Len(t, ranges, 1)
+	}
+
+	// stale partial files are restarted.
+	assert.NoError(t, writeState(name+stateSuffix, &state{URL: srv.URL, ETag: `"v0"`}))
+	assert.NoError(t, os.WriteFile(name+partSuffix, []byte("stale"), 0o644))
+	result, err = d.Download(srv.URL, name, "")
+	if assert.NoError(t, err) {
+		assert.False(t, result.Resumed)
+		assert.Equal(t, hexSum, result.SHA256)
+	}
+
+	// complete partial files are renamed.
+	ranges = nil
+	assert.NoError(t, os.Remove(name))
+	assert.NoError(t, writeState(name+stateSuffix, &state{URL: srv.URL, ETag: `"v1"`}))
+	assert.NoError(t, os.WriteFile(name+partSuffix, []byte(content), 0o644))
+	result, err = d.Download(srv.URL, name, hexSum)
+	if assert.NoError(t, err) {
+		assert.True(t, result.Resumed)
+		assert.Equal(t, hexSum, result.SHA256)
+		assert.Equal(t, []string{fmt.Sprintf("bytes=%d-", len(content))}, ranges)
+		data, _ := os.ReadFile(name)
+		assert.Equal(t, content, string(data))
+	}
+	assert.NoFileExists(t, name+partSuffix)
+	assert.NoFileExists(t, name+stateSuffix)
+
+	// partial files longer than the remote one are restarted.
+	ranges = nil
+	assert.NoError(t, writeState(name+stateSuffix, &state{URL: srv.URL, ETag: `"v1"`}))
+	assert.NoError(t, os.WriteFile(name+partSuffix, []byte(content+"extra"), 0o644))
+	result, err = d.Download(srv.URL, name, "")
+	if assert.NoError(t, err) {
+		assert.False(t, result.Resumed)
+		assert.Equal(t, hexSum, result.SHA256)
+		assert.Equal(t, []string{fmt.Sprintf("bytes=%d-", len(content)+5), ""}, ranges)
+	}
+	assert.NoFileExists(t, name+partSuffix)
+
+	// checksum mismatch.
+	_, err = d.Download(srv.URL, filepath.Join(t.TempDir(), "bad.mp4"), strings.Repeat("0", 64))
+	assert.ErrorIs(t, err, ErrChecksumMismatch)
+}
+
+func TestDownloader_Put(t *testing.T) {
+	content := strings.Repeat("metatube", 1024)
+	sum := sha256.Sum256([]byte(content))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/poster.jpg" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(content))
+	}))
+	defer srv.Close()
+
+	d := New(fetch.Default(nil).Get)
+	b := storage.NewMemory(0)
+	result, err := d.Put(b, srv.URL+"/poster.jpg", "ABC-123/poster.jpg")
+	if assert.NoError(t, err) {
+		assert.Equal(t, int64(len(content)), result.Size)
+		assert.Equal(t, hex.EncodeToString(sum[:]), result.SHA256)
+		data, _ := b.Get("ABC-123/poster.jpg")
+		assert.Equal(t, content, string(data))
+	}
+
+	_, err = d.Put(b, srv.URL+"/missing.jpg", "missing.jpg")
+	assert.Error(t, err)
+	_, err = b.Stat("missing.jpg")
+	assert.ErrorIs(t, err, storage.ErrNotFound)
+}
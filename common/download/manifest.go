@@ -0,0 +1,140 @@
+package download
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ManifestName is the file name of the manifest in the artwork directory.
+const ManifestName = ".metatube-manifest.json"
+
+// ManifestEntry is the record of a downloaded file.
+type ManifestEntry struct {
+	URL       string    `json:"url"`
+	Size      int64     `json:"size"`
+	SHA256    string    `json:"sha256"`
+	ScrapedAt time.Time `json:"scraped_at"`
+}
+
+// Manifest records files downloaded into a directory by names relative
+// to it, so that the files can be verified, and downloads of unchanged
+// files are skipped on later runs.
+type Manifest struct {
+	mu      sync.Mutex
+	dir     string
+	Entries map[string]*ManifestEntry `json:"entries"`
+}
+
+// LoadManifest loads the manifest of the directory, an empty one is
+// returned if it doesn't exist yet.
+func LoadManifest(dir string) (*Manifest, error) {
+	m := &Manifest{dir: dir, Entries: make(map[string]*ManifestEntry)}
+	data, err := os.ReadFile(filepath.Join(dir, ManifestName))
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, m); err != nil {
+		return nil, err
+	}
+	if m.Entries == nil {
+		m.Entries = make(map[string]*ManifestEntry)
+	}
+	return m, nil
+}
+
+// Save writes the manifest into the directory atomically.
+func (m *Manifest) Save() error {
+	m.mu.Lock()
+	data, err := json.MarshalIndent(m, "", "  ")
+	m.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(m.dir, 0o755); err != nil {
+		return err
+	}
+	tmp := filepath.Join(m.dir, ManifestName+".tmp")
+	if err = os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, filepath.Join(m.dir, ManifestName))
+}
+
+// Download downloads the url to the file of name relative to the manifest
+// directory and records it. The download is skipped if the file has been
+// downloaded from the same url and is intact.
+func (m *Manifest) Download(d *Downloader, url, name string, scrapedAt time.Time) (*Result, error) {
+	path := filepath.Join(m.dir, name)
+	if m.Unchanged(url, name) {
+		entry := m.entry(name)
+		return &Result{URL: url, Path: path, Size: entry.Size, SHA256: entry.SHA256, Skipped: true}, nil
+	}
+	result, err := d.Download(url, path, "")
+	if err != nil {
+		return nil, err
+	}
+	m.mu.Lock()
+	m.Entries[filepath.ToSlash(name)] = &ManifestEntry{
+		URL:       url,
+		Size:      result.Size,
+		SHA256:    result.SHA256,
+		ScrapedAt: scrapedAt,
+	}
+	m.mu.Unlock()
+	return result, nil
+}
+
+// Unchanged reports whether the file of name has been downloaded from the
+// same url and is intact, i.e., the download can be skipped.
+func (m *Manifest) Unchanged(url, name string) bool {
+	entry := m.entry(name)
+	return entry != nil && entry.URL == url && m.verify(name, entry) == nil
+}
+
+// Verify checks all recorded files, and returns names of the ones missing
+// or changed since downloaded.
+func (m *Manifest) Verify() (corrupted []string) {
+	m.mu.Lock()
+	names := make([]string, 0, len(m.Entries))
+	for name := range m.Entries {
+		names = append(names, name)
+	}
+	m.mu.Unlock()
+	sort.Strings(names)
+	for _, name := range names {
+		if m.verify(name, m.entry(name)) != nil {
+			corrupted = append(corrupted, name)
+		}
+	}
+	return
+}
+
+func (m *Manifest) entry(name string) *ManifestEntry {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.Entries[filepath.ToSlash(name)]
+}
+
+func (m *Manifest) verify(name string, entry *ManifestEntry) error {
+	path := filepath.Join(m.dir, name)
+	// compare size first, which is much cheaper than hashing.
+	if fi, err := os.Stat(path); err != nil {
+		return err
+	} else if fi.Size() != entry.Size {
+		return ErrChecksumMismatch
+	}
+	if _, sum, err := hashFile(path); err != nil {
+		return err
+	} else if !strings.EqualFold(sum, entry.SHA256) {
+		return ErrChecksumMismatch
+	}
+	return nil
+}
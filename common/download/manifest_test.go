@@ -0,0 +1,68 @@
+package download
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+)
+
+func TestManifest(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		_, _ = w.Write([]byte("poster of " + r.URL.Path))
+	}))
+	defer srv.Close()
+
+	var (
+		dir       = t.TempDir()
+		d         = New(fetch.Default(nil).Get)
+		scrapedAt = time.Date(2024, 10, 1, 0, 0, 0, 0, time.UTC)
+	)
+	m, err := LoadManifest(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	_, err = m.Download(d, srv.URL+"/a.jpg", "poster.jpg", scrapedAt)
+	assert.NoError(t, err)
+	_, err = m.Download(d, srv.URL+"/b.jpg", "extrafanart/fanart1.jpg", scrapedAt)
+	assert.NoError(t, err)
+	assert.NoError(t, m.Save())
+	assert.Equal(t, 2, requests)
+
+	// reload and re-run, unchanged files are skipped.
+	m, err = LoadManifest(dir)
+	if !assert.NoError(t, err) {
+		return
+	}
+	entry := m.Entries["extrafanart/fanart1.jpg"]
+	if assert.NotNil(t, entry) {
+		assert.Equal(t, srv.URL+"/b.jpg", entry.URL)
+		assert.True(t, scrapedAt.Equal(entry.ScrapedAt))
+	}
+	assert.True(t, m.Unchanged(srv.URL+"/a.jpg", "poster.jpg"))
+	assert.False(t, m.Unchanged(srv.URL+"/c.jpg", "poster.jpg"), "url changed")
+	result, err := m.Download(d, srv.URL+"/a.jpg", "poster.jpg", scrapedAt)
+	if assert.NoError(t, err) {
+		assert.True(t, result.Skipped)
+	}
+	assert.Equal(t, 2, requests)
+	assert.Empty(t, m.Verify())
+
+	// changed files are reported and downloaded again.
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "poster.jpg"), []byte("edited"), 0o644))
+	assert.Equal(t, []string{"poster.jpg"}, m.Verify())
+	result, err = m.Download(d, srv.URL+"/a.jpg", "poster.jpg", scrapedAt)
+	if assert.NoError(t, err) {
+		assert.False(t, result.Skipped)
+	}
+	assert.Equal(t, 3, requests)
+	assert.Empty(t, m.Verify())
+}
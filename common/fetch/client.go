@@ -0,0 +1,120 @@
+package fetch
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/metatube-community/metatube-sdk-go/errors"
+)
+
+var (
+	_ Client            = (*http.Client)(nil)
+	_ Client            = (*Fetcher)(nil)
+	_ Client            = (ClientFunc)(nil)
+	_ Client            = (*FixtureClient)(nil)
+	_ http.RoundTripper = (*Transport)(nil)
+)
+
+// Client sends raw HTTP requests for providers. Backends other than
+// net/http, e.g., headless browsers and recorded fixtures, implement it
+// to be swapped in without touching the parsing code of providers.
+type Client interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// ClientFunc is a Client of the function.
+type ClientFunc func(req *http.Request) (*http.Response, error)
+
+func (fn ClientFunc) Do(req *http.Request) (*http.Response, error) { return fn(req) }
+
+// Transport adapts the Client to http.RoundTripper, so that the Client
+// backs HTTP clients and colly collectors.
+type Transport struct {
+	Client Client
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.Client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Request == nil {
+		resp.Request = req
+	}
+	return resp, nil
+}
+
+// Fixture is a recorded response of the request of the method and URL.
+type Fixture struct {
+	Method     string
+	URL        string
+	StatusCode int
+	Header     http.Header
+	Body       []byte
+}
+
+// FixtureClient is a Client replaying recorded fixtures, e.g., for tests
+// of providers without network access.
+type FixtureClient struct {
+	mu       sync.RWMutex
+	fixtures map[string]*Fixture
+}
+
+// NewFixtureClient returns a *FixtureClient of the fixtures.
+func NewFixtureClient(fixtures ...*Fixture) *FixtureClient {
+	c := &FixtureClient{fixtures: make(map[string]*Fixture, len(fixtures))}
+	for _, f := range fixtures {
+		c.Add(f)
+	}
+	return c
+}
+
+func fixtureKey(method, url string) string {
+	if method == "" {
+		method = http.MethodGet
+	}
+	return strings.ToUpper(method) + " " + url
+}
+
+// Add adds the fixture, which replaces the one of the same request.
+func (c *FixtureClient) Add(f *Fixture) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.fixtures[fixtureKey(f.Method, f.URL)] = f
+}
+
+// Do returns the response of the fixture, or an error if not recorded.
+func (c *FixtureClient) Do(req *http.Request) (*http.Response, error) {
+	c.mu.RLock()
+	f, ok := c.fixtures[fixtureKey(req.Method, req.URL.String())]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, errors.New(http.StatusNotFound, "fixture not found: "+req.Method+" "+req.URL.String())
+	}
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	code := f.StatusCode
+	if code == 0 {
+		code = http.StatusOK
+	}
+	header := f.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	return &http.Response{
+		Status:        strconv.Itoa(code) + " " + http.StatusText(code),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(f.Body)),
+		ContentLength: int64(len(f.Body)),
+		Request:       req,
+	}, nil
+}
@@ -0,0 +1,50 @@
+package fetch
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFixtureClient(t *testing.T) {
+	c := NewFixtureClient(
+		&Fixture{URL: "https://example.com/", Body: []byte("home")},
+		&Fixture{Method: http.MethodPost, URL: "https://example.com/api", StatusCode: http.StatusCreated},
+	)
+
+	req, _ := http.NewRequest(http.MethodGet, "https://example.com/", nil)
+	resp, err := c.Do(req)
+	require.NoError(t, err)
+	body, _ := io.ReadAll(resp.Body)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "home", string(body))
+
+	req, _ = http.NewRequest(http.MethodPost, "https://example.com/api", nil)
+	resp, err = c.Do(req)
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusCreated, resp.StatusCode)
+
+	req, _ = http.NewRequest(http.MethodGet, "https://example.com/api", nil)
+	_, err = c.Do(req)
+	assert.Error(t, err)
+}
+
+func TestFetcher_SetClient(t *testing.T) {
+	var got *http.Request
+	f := Default(&Config{UserAgent: "test-agent", Referer: "https://example.com/"})
+	fixtures := NewFixtureClient(&Fixture{URL: "https://example.com/missing", StatusCode: http.StatusNotFound})
+	f.SetClient(ClientFunc(func(req *http.Request) (*http.Response, error) {
+		got = req
+		return fixtures.Do(req)
+	}))
+
+	_, err := f.Get("https://example.com/missing")
+	assert.Error(t, err, "status is raised")
+	if assert.NotNil(t, got) {
+		assert.Equal(t, "test-agent", got.Header.Get("User-Agent"))
+		assert.Equal(t, "https://example.com/", got.Header.Get("Referer"))
+	}
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/hashicorp/go-cleanhttp"
 	"github.com/hashicorp/go-retryablehttp"
 
+	"github.com/metatube-community/metatube-sdk-go/common/compress"
 	"github.com/metatube-community/metatube-sdk-go/common/random"
 	"github.com/metatube-community/metatube-sdk-go/errors"
 )
@@ -22,10 +23,14 @@ type Config struct {
 	// Set Referer Header.
 	Referer string
 
+	// Set extra Headers.
+	Headers map[string]string
+
 	// Enable cookies.
 	EnableCookies bool
 
-	// Use random User-Agent.
+	// Use random User-Agent and client hints of a browser profile,
+	// which are kept for the lifetime of the Fetcher.
 	RandomUserAgent bool
 
 	// Return error when status is not OK.
@@ -39,14 +44,23 @@ type Config struct {
 }
 
 type Fetcher struct {
-	client *http.Client
+	client Client
 	config *Config
 }
 
 func New(c *http.Client, cfg *Config) *Fetcher {
 	if cfg.RandomUserAgent {
-		// assign a random user-agent.
-		cfg.UserAgent = random.UserAgent()
+		// assign a random browser profile.
+		profile := random.BrowserProfile()
+		cfg.UserAgent = profile.UserAgent
+		headers := make(map[string]string, len(profile.ClientHints)+len(cfg.Headers))
+		for key, value := range profile.ClientHints {
+			headers[key] = value
+		}
+		for key, value := range cfg.Headers {
+			headers[key] = value
+		}
+		cfg.Headers = headers
 	}
 	if cfg.EnableCookies {
 		jar, _ := cookiejar.New(nil)
@@ -82,9 +96,19 @@ func Default(cfg *Config) *Fetcher {
 	if cfg.Transport != nil {
 		c.HTTPClient.Transport = cfg.Transport
 	}
+	// decode all encodings with size limited.
+	c.HTTPClient.Transport = &compress.Transport{Base: c.HTTPClient.Transport}
 	return New(c.StandardClient(), cfg)
 }
 
+// SetClient replaces the client sending requests, e.g., by a recorded
+// FixtureClient. Options of the Fetcher still apply to requests. It must
+// be called before the Fetcher is used.
+func (f *Fetcher) SetClient(c Client) { f.client = c }
+
+// Do sends the request by the client as is, options are not applied.
+func (f *Fetcher) Do(req *http.Request) (*http.Response, error) { return f.client.Do(req) }
+
 func (f *Fetcher) Fetch(url string) (resp *http.Response, err error) {
 	return f.Get(url)
 }
@@ -114,6 +138,9 @@ func (f *Fetcher) Request(method, url string, body io.Reader, opts ...Option) (r
 	if c.Referer != "" {
 		options = append(options, WithReferer(c.Referer))
 	}
+	if len(c.Headers) > 0 {
+		options = append(options, WithHeaders(c.Headers))
+	}
 	// apply options.
 	for _, option := range append(options, opts...) {
 		option.apply(c)
@@ -0,0 +1,50 @@
+package httpcache
+
+import (
+	"fmt"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// Cache is a key-value store of cached responses, e.g., source images.
+type Cache interface {
+	// Get returns the data of the key if present and not expired.
+	Get(key string) ([]byte, bool)
+	// Set stores the data of the key.
+	Set(key string, data []byte) error
+}
+
+// Loader is a Cache that loads missing entries by itself, e.g., with
+// stampede protection across instances.
+type Loader interface {
+	Cache
+	Load(key string, load func() ([]byte, error)) ([]byte, error)
+}
+
+var _ Cache = (*Store)(nil)
+
+var loadGroup singleflight.Group
+
+// Load returns the cached data of the key, or the data loaded which is
+// then cached. Concurrent loads of the same key share a single call, and
+// data is not cached if load fails.
+func Load(c Cache, key string, load func() ([]byte, error)) ([]byte, error) {
+	if l, ok := c.(Loader); ok {
+		return l.Load(key, load)
+	}
+	if data, ok := c.Get(key); ok {
+		return data, nil
+	}
+	v, err, _ := loadGroup.Do(fmt.Sprintf("%p:%s", c, key), func() (any, error) {
+		data, err := load()
+		if err != nil {
+			return nil, err
+		}
+		_ = c.Set(key, data) // ignore error
+		return data, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
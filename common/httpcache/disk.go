@@ -0,0 +1,86 @@
+package httpcache
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httputil"
+	"os"
+	"path/filepath"
+
+	"github.com/metatube-community/metatube-sdk-go/common/storage"
+)
+
+var _ http.RoundTripper = (*DiskTransport)(nil)
+
+// DiskTransport is a development purpose HTTP transport that writes every
+// successfully fetched GET response to a content-addressed directory, and
+// serves the same requests from it on subsequent runs without hitting
+// the live sites. It never expires cached responses, remove the files or
+// the whole directory to refresh them.
+type DiskTransport struct {
+	// Dir is the root directory of the cache.
+	Dir string
+	// Transport is the underlying transport, http.DefaultTransport is used if nil.
+	Transport http.RoundTripper
+}
+
+// NewDiskTransport returns a *DiskTransport wrapping the transport.
+func NewDiskTransport(dir string, transport http.RoundTripper) *DiskTransport {
+	return &DiskTransport{Dir: dir, Transport: transport}
+}
+
+// Wrapper returns a transport wrapper of the cache directory.
+func Wrapper(dir string) func(http.RoundTripper) http.RoundTripper {
+	return func(transport http.RoundTripper) http.RoundTripper {
+		return NewDiskTransport(dir, transport)
+	}
+}
+
+// Key returns the cache key of the request.
+func Key(req *http.Request) string {
+	sum := sha256.Sum256([]byte(req.Method + " " + req.URL.String()))
+	return hex.EncodeToString(sum[:])
+}
+
+// Path returns the path of the cached file of the request.
+func (t *DiskTransport) Path(req *http.Request) string {
+	key := Key(req)
+	return filepath.Join(t.Dir, key[:2], key)
+}
+
+func (t *DiskTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet {
+		return t.transport().RoundTrip(req)
+	}
+	name := t.Path(req)
+	if data, err := os.ReadFile(name); err == nil {
+		if resp, err := http.ReadResponse(bufio.NewReader(bytes.NewReader(data)), req); err == nil {
+			return resp, nil
+		}
+		// ignore broken cache.
+	}
+	resp, err := t.transport().RoundTrip(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		return resp, err
+	}
+	data, err := httputil.DumpResponse(resp, true)
+	if err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	if err = storage.WriteFile(name, data); err != nil {
+		resp.Body.Close()
+		return nil, err
+	}
+	return resp, nil
+}
+
+func (t *DiskTransport) transport() http.RoundTripper {
+	if t.Transport != nil {
+		return t.Transport
+	}
+	return http.DefaultTransport
+}
@@ -0,0 +1,48 @@
+package httpcache
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiskTransport(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		if r.URL.Path == "/404" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, "page %s", r.URL.Path)
+	}))
+	defer srv.Close()
+
+	c := &http.Client{Transport: NewDiskTransport(t.TempDir(), nil)}
+	get := func(path string) (int, string) {
+		resp, err := c.Get(srv.URL + path)
+		if !assert.NoError(t, err) {
+			return 0, ""
+		}
+		defer resp.Body.Close()
+		data, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(data)
+	}
+
+	for i := 0; i < 3; i++ {
+		code, body := get("/a")
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, "page /a", body)
+	}
+	assert.Equal(t, 1, hits)
+
+	for i := 0; i < 2; i++ {
+		code, _ := get("/404")
+		assert.Equal(t, http.StatusNotFound, code)
+	}
+	assert.Equal(t, 3, hits) // errors are not cached.
+}
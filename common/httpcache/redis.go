@@ -0,0 +1,17 @@
+package httpcache
+
+import (
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/common/storage"
+)
+
+var _ Loader = (*Redis)(nil)
+
+// Redis is a Cache on Redis or Valkey servers, see storage.Redis.
+type Redis = storage.Redis
+
+// NewRedis returns a *Redis of the server URL, see storage.NewRedis.
+func NewRedis(rawURL string, ttl time.Duration) (*Redis, error) {
+	return storage.NewRedis(rawURL, ttl)
+}
@@ -0,0 +1,15 @@
+package httpcache
+
+import (
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/common/storage"
+)
+
+// Store is a content-addressed Cache on disk, see storage.FileKV.
+type Store = storage.FileKV
+
+// NewStore returns a *Store of the directory.
+func NewStore(dir string, ttl time.Duration) *Store {
+	return storage.NewFileKV(dir, ttl)
+}
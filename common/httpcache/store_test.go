@@ -0,0 +1,29 @@
+package httpcache
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStore(t *testing.T) {
+	s := NewStore(t.TempDir(), time.Hour)
+
+	_, ok := s.Get("a")
+	assert.False(t, ok)
+
+	if assert.NoError(t, s.Set("a", []byte("data"))) {
+		data, ok := s.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, "data", string(data))
+	}
+
+	// expire the entry.
+	past := time.Now().Add(-2 * time.Hour)
+	if assert.NoError(t, os.Chtimes(s.Path("a"), past, past)) {
+		_, ok = s.Get("a")
+		assert.False(t, ok)
+	}
+}
@@ -0,0 +1,50 @@
+package number
+
+import (
+	"testing"
+
+	"github.com/metatube-community/metatube-sdk-go/internal/race"
+)
+
+var benchNumbers = []string{
+	"[98t.tv]vema-181-4k-C.mp4",
+	"ABP-030-C-c_c-C-Cd1-cd4.mp4",
+	"FC2-PPV-1234567",
+	"h_346rebd655tk2",
+	"HEYZO-1234",
+}
+
+func BenchmarkTrim(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Trim(benchNumbers[i%len(benchNumbers)])
+	}
+}
+
+func BenchmarkCanonicalize(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = Canonicalize("", benchNumbers[i%len(benchNumbers)])
+	}
+}
+
+// Allocation budgets per call, raise them only with a reason, Trim and
+// Canonicalize are called for every file of batch scrapes.
+const (
+	trimAllocBudget         = 24 // ~16 by regexp replacements.
+	canonicalizeAllocBudget = 8  // ~5 by key building and lookups.
+)
+
+func TestAllocBudgets(t *testing.T) {
+	if race.Enabled {
+		t.Skip("allocations are inflated by the race detector")
+	}
+	for _, s := range benchNumbers {
+		if n := testing.AllocsPerRun(100, func() { _ = Trim(s) }); n > trimAllocBudget {
+			t.Errorf("Trim(%q) allocs %.0f > budget %d", s, n, trimAllocBudget)
+		}
+		if n := testing.AllocsPerRun(100, func() { _ = Canonicalize("", s) }); n > canonicalizeAllocBudget {
+			t.Errorf("Canonicalize(%q) allocs %.0f > budget %d", s, n, canonicalizeAllocBudget)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package number
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// wildcardProvider matches rules of any provider.
+const wildcardProvider = "*"
+
+//go:embed canonical.json
+var defaultCanonicalTable []byte
+
+// CanonicalTable is the serialized form of canonical number mappings.
+type CanonicalTable struct {
+	// Numbers are exact ID to number mappings.
+	Numbers []CanonicalNumber `json:"numbers"`
+	// Rules are regular expression based mappings, applied in order.
+	Rules []CanonicalRule `json:"rules"`
+}
+
+// CanonicalNumber maps a provider-internal ID to its canonical number.
+type CanonicalNumber struct {
+	Provider string `json:"provider"`
+	ID       string `json:"id"`
+	Number   string `json:"number"`
+}
+
+// CanonicalRule maps provider-internal IDs matched by Pattern to canonical
+// numbers expanded from Replace, e.g. `${1}-${2}`.
+type CanonicalRule struct {
+	Provider string `json:"provider"`
+	Pattern  string `json:"pattern"`
+	Replace  string `json:"replace"`
+}
+
+type canonicalRule struct {
+	provider string
+	re       *regexp.Regexp
+	replace  string
+}
+
+type canonicalizer struct {
+	mu sync.RWMutex
+	// provider:id -> number
+	numbers map[string]string
+	rules   []*canonicalRule
+}
+
+var defaultCanonicalizer = newCanonicalizer()
+
+func init() {
+	if err := defaultCanonicalizer.load(defaultCanonicalTable, false); err != nil {
+		panic(err)
+	}
+}
+
+func newCanonicalizer() *canonicalizer {
+	return &canonicalizer{numbers: make(map[string]string)}
+}
+
+func canonicalKey(provider, id string) string {
+	return strings.ToUpper(provider) + ":" + strings.ToUpper(id)
+}
+
+func (c *canonicalizer) load(data []byte, override bool) error {
+	table := &CanonicalTable{}
+	if err := json.Unmarshal(data, table); err != nil {
+		return err
+	}
+	rules := make([]*canonicalRule, 0, len(table.Rules))
+	for _, rule := range table.Rules {
+		re, err := regexp.Compile(rule.Pattern)
+		if err != nil {
+			return fmt.Errorf("invalid canonical rule %q: %w", rule.Pattern, err)
+		}
+		rules = append(rules, &canonicalRule{
+			provider: strings.ToUpper(rule.Provider),
+			re:       re,
+			replace:  rule.Replace,
+		})
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, n := range table.Numbers {
+		c.numbers[canonicalKey(n.Provider, n.ID)] = n.Number
+	}
+	if override /* user rules take precedence */ {
+		c.rules = append(rules, c.rules...)
+	} else {
+		c.rules = append(c.rules, rules...)
+	}
+	return nil
+}
+
+func (c *canonicalizer) canonicalize(provider, id string) string {
+	id = strings.TrimSpace(id)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if n, ok := c.numbers[canonicalKey(provider, id)]; ok {
+		return n
+	}
+	if n, ok := c.numbers[canonicalKey(wildcardProvider, id)]; ok {
+		return n
+	}
+	provider = strings.ToUpper(provider)
+	for _, rule := range c.rules {
+		if rule.provider != provider && rule.provider != wildcardProvider {
+			continue
+		}
+		if rule.re.MatchString(id) {
+			return strings.ToUpper(rule.re.ReplaceAllString(id, rule.replace))
+		}
+	}
+	return strings.ToUpper(id)
+}
+
+// Canonicalize translates the provider-internal ID to the canonical
+// number, e.g. FANZA's `h_068mxgs1234` to `MXGS-1234`. Exact mappings
+// are looked up first, then the rules of the provider and the wildcard
+// rules, IDs without any matches are returned in upper case.
+func Canonicalize(provider, id string) string {
+	return defaultCanonicalizer.canonicalize(provider, id)
+}
+
+// RegisterCanonicalNumber registers an exact canonical number mapping,
+// use `*` as provider to match any providers.
+func RegisterCanonicalNumber(provider, id, number string) {
+	defaultCanonicalizer.mu.Lock()
+	defaultCanonicalizer.numbers[canonicalKey(provider, id)] = number
+	defaultCanonicalizer.mu.Unlock()
+}
+
+// RegisterCanonicalRule registers a regular expression based canonical
+// number mapping, which takes precedence over the existing rules.
+func RegisterCanonicalRule(provider, pattern, replace string) error {
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		return err
+	}
+	defaultCanonicalizer.mu.Lock()
+	defaultCanonicalizer.rules = append([]*canonicalRule{{
+		provider: strings.ToUpper(provider),
+		re:       re,
+		replace:  replace,
+	}}, defaultCanonicalizer.rules...)
+	defaultCanonicalizer.mu.Unlock()
+	return nil
+}
+
+// LoadCanonicalTable loads user overrides in the same JSON format as
+// the embedded default table, and they take precedence over defaults.
+func LoadCanonicalTable(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return defaultCanonicalizer.load(data, true)
+}
@@ -0,0 +1,42 @@
+package number
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalize(t *testing.T) {
+	for _, unit := range []struct {
+		provider string
+		id       string
+		want     string
+	}{
+		{"FANZA", "h_068mxgs01234", "MXGS-1234"},
+		{"fanza", "118abp00077", "ABP-077"},
+		{"FANZA", "ssis00123", "SSIS-123"},
+		{"FANZA", "ssis00123tk", "SSIS-123TK"},
+		{"MGS", "300MIUM-123", "300MIUM-123"},
+		{"JavBus", "ABP-030", "ABP-030"},
+		{"JavBus", "abp030", "ABP-030"},
+		{"FC2", "FC2PPV-123456", "FC2-123456"},
+		{"HEYZO", "HEYZO-1234", "HEYZO-1234"},
+		{"1Pondo", "010121_001", "010121_001"},
+	} {
+		assert.Equal(t, unit.want, Canonicalize(unit.provider, unit.id), unit)
+	}
+}
+
+func TestLoadCanonicalTable(t *testing.T) {
+	c := newCanonicalizer()
+	assert.NoError(t, c.load(defaultCanonicalTable, false))
+	assert.NoError(t, c.load([]byte(`{
+		"numbers": [{"provider": "FANZA", "id": "1sdde00001", "number": "SDDE-001-X"}],
+		"rules": [{"provider": "FANZA", "pattern": "(?i)^1(sdde)0*(\\d{3,})$", "replace": "${1}-${2}-Y"}]
+	}`), true))
+	assert.Equal(t, "SDDE-001-X", c.canonicalize("FANZA", "1sdde00001"))
+	assert.Equal(t, "SDDE-002-Y", c.canonicalize("FANZA", "1sdde00002"))
+	assert.Equal(t, "SSIS-123", c.canonicalize("FANZA", "ssis00123"))
+	assert.Error(t, LoadCanonicalTable(strings.NewReader(`{"rules": [{"pattern": "("}]}`)))
+}
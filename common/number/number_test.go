@@ -2,6 +2,7 @@ package number
 
 import (
 	"testing"
+	"unicode/utf8"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -294,3 +295,21 @@ func TestRequireFaceDetection(t *testing.T) {
 		assert.Equal(t, unit.want, RequireFaceDetection(unit.orig), unit.orig)
 	}
 }
+
+func FuzzTrim(f *testing.F) {
+	for _, s := range []string{"", "ABP-030-C.mp4", "FC2PPV_1234567", "carib-010120-001", "www.example.com@SSIS-001.1080p.mkv", "ch"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		n := Trim(s)
+		if len(n) > len(s) {
+			t.Errorf("Trim(%q) = %q longer than input", s, n)
+		}
+		if utf8.ValidString(s) && !utf8.ValidString(n) {
+			t.Errorf("Trim(%q) = %q is not valid UTF-8", s, n)
+		}
+		_ = IsUncensored(n)
+		_ = IsFC2(n)
+		_ = IsSpecial(n)
+	})
+}
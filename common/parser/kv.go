@@ -0,0 +1,115 @@
+package parser
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/antchfx/htmlquery"
+	"golang.org/x/net/html"
+)
+
+// KV is the label-value pairs extracted from tables by labels, values are
+// kept as nodes, so that texts, links and lists are all parsed from them.
+type KV map[string]*html.Node
+
+// Node returns the value node of the key, nil if missing.
+func (kv KV) Node(key string) *html.Node { return kv[key] }
+
+// Text returns the trimmed text of the value of the key.
+func (kv KV) Text(key string) string {
+	n, ok := kv[key]
+	if !ok {
+		return ""
+	}
+	return strings.TrimSpace(htmlquery.InnerText(n))
+}
+
+// Texts returns all non-empty texts of the value of the key.
+func (kv KV) Texts(key string) []string {
+	texts := make([]string, 0)
+	if n, ok := kv[key]; ok {
+		ParseTexts(n, &texts)
+	}
+	return texts
+}
+
+// ChildText returns the trimmed text of the first child of the value
+// matched by the XPath.
+func (kv KV) ChildText(key, xpath string) string {
+	n, ok := kv[key]
+	if !ok {
+		return ""
+	}
+	if child := htmlquery.FindOne(n, xpath); child != nil {
+		return strings.TrimSpace(htmlquery.InnerText(child))
+	}
+	return ""
+}
+
+// ChildTexts returns the trimmed texts of all children of the value
+// matched by the XPath.
+func (kv KV) ChildTexts(key, xpath string) []string {
+	texts := make([]string, 0)
+	if n, ok := kv[key]; ok {
+		for _, child := range htmlquery.Find(n, xpath) {
+			texts = append(texts, strings.TrimSpace(htmlquery.InnerText(child)))
+		}
+	}
+	return texts
+}
+
+// ExtractKV extracts label-value pairs under the node, e.g., of table rows
+// (`.//tr`, `./td[1]`, `./td[2]`) or definition lists (`.//dl`, `./dt`,
+// `./dd`). Labels and values of each row are paired by their positions.
+// Labels are trimmed of spaces and colons, and then mapped by the aliases
+// if any, so that labels varied by pages share the same keys. The first
+// value of duplicated keys is kept.
+func ExtractKV(n *html.Node, rowXPath, keyXPath, valXPath string, aliases map[string]string) KV {
+	kv := make(KV)
+	if n == nil {
+		return kv
+	}
+	for _, row := range htmlquery.Find(n, rowXPath) {
+		keys := htmlquery.Find(row, keyXPath)
+		values := htmlquery.Find(row, valXPath)
+		for i := 0; i < len(keys) && i < len(values); i++ {
+			key := normalizeKey(htmlquery.InnerText(keys[i]), aliases)
+			if _, ok := kv[key]; key == "" || ok {
+				continue
+			}
+			kv[key] = values[i]
+		}
+	}
+	return kv
+}
+
+// SplitKV splits `label: value` lines by the separator into label-value
+// pairs, labels are normalized as ExtractKV does, and lines without the
+// separator or with empty values are skipped.
+func SplitKV(lines []string, sep string, aliases map[string]string) map[string]string {
+	kv := make(map[string]string)
+	for _, line := range lines {
+		key, value, ok := strings.Cut(line, sep)
+		if !ok {
+			continue
+		}
+		key, value = normalizeKey(key, aliases), strings.TrimSpace(value)
+		if _, ok := kv[key]; key == "" || value == "" || ok {
+			continue
+		}
+		kv[key] = value
+	}
+	return kv
+}
+
+// normalizeKey trims spaces and colons of the label, and maps it by the
+// aliases.
+func normalizeKey(s string, aliases map[string]string) string {
+	s = strings.TrimFunc(s, func(r rune) bool {
+		return r == ':' || r == '：' || unicode.IsSpace(r)
+	})
+	if alias, ok := aliases[s]; ok {
+		return alias
+	}
+	return s
+}
@@ -0,0 +1,51 @@
+package parser
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/net/html"
+)
+
+func TestExtractKV(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`<html><body>
+<table><tbody>
+	<tr><td>配信開始日：</td><td> 2024-01-02 </td></tr>
+	<tr><td>出演</td><td><a><span>A</span></a><a><span>B</span></a></td></tr>
+	<tr><td>発売日</td><td>2025-01-01</td></tr>
+	<tr><td>Label</td></tr>
+</tbody></table>
+<dl><dt>監督</dt><dd>Director</dd><dt>ジャンル</dt><dd><a>X</a> <a>Y</a></dd></dl>
+</body></html>`))
+	require.NoError(t, err)
+
+	aliases := map[string]string{"配信開始日": "発売日"}
+	kv := ExtractKV(doc, `//table/tbody/tr`, `./td[1]`, `./td[2]`, aliases)
+	assert.Len(t, kv, 2)
+	assert.Equal(t, "2024-01-02", kv.Text("発売日"), "first value of aliased keys is kept")
+	assert.Equal(t, []string{"A", "B"}, kv.ChildTexts("出演", `./a/span`))
+	assert.Equal(t, "A", kv.ChildText("出演", `.//span`))
+	assert.Equal(t, "", kv.Text("Label"), "labels without values are skipped")
+	assert.Equal(t, []string{}, kv.Texts("Label"))
+
+	kv = ExtractKV(doc, `//dl`, `./dt`, `./dd`, nil)
+	assert.Equal(t, "Director", kv.Text("監督"))
+	assert.Equal(t, []string{"X", "Y"}, kv.Texts("ジャンル"))
+
+	assert.Empty(t, ExtractKV(nil, `//tr`, `./td[1]`, `./td[2]`, nil))
+}
+
+func TestSplitKV(t *testing.T) {
+	kv := SplitKV([]string{
+		" 出生: 1990-01-01 ",
+		"三围:",
+		"no separator",
+		"出生: 2000-01-01",
+	}, ":", nil)
+	assert.Equal(t, map[string]string{"出生": "1990-01-01"}, kv)
+
+	kv = SplitKV([]string{"身高： 160cm"}, "：", map[string]string{"身高": "height"})
+	assert.Equal(t, map[string]string{"height": "160cm"}, kv)
+}
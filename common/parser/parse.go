@@ -11,6 +11,8 @@ import (
 	"github.com/araddon/dateparse"
 	"golang.org/x/net/html"
 	dt "gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
 )
 
 // ParseInt parses string to int regardless.
@@ -31,12 +33,17 @@ func ParseTime(s string) time.Time {
 			strings.TrimSpace(ss[3]))
 	}
 	t, _ := dateparse.ParseAny(s)
+	if t.Year() < 1 || t.Year() > 9999 {
+		// out of range of most databases, e.g., `0000-00-00`.
+		return time.Time{}
+	}
 	return t
 }
 
-// ParseDate parses a string with valid date format into Date.
+// ParseDate parses a string with valid date format into Date, which is
+// the calendar date as written, normalized by model.DateOf.
 func ParseDate(s string) dt.Date {
-	return dt.Date(ParseTime(s))
+	return model.DateOf(ParseTime(s))
 }
 
 // ParseDuration parses a string with valid duration format into time.Duration.
@@ -63,3 +63,43 @@ func TestParseIDToNumber(t *testing.T) {
 		assert.Equal(t, unit.want, ParseIDToNumber(unit.id))
 	}
 }
+
+func FuzzParseDate(f *testing.F) {
+	for _, s := range []string{"2021-01-02", "2021年1月2日", "01/02/2021", "Jan 2, 2021", "", "2021", "0000-00-00", "0000-0000"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		if d := time.Time(ParseDate(s)); !d.IsZero() && (d.Year() < 1 || d.Year() > 9999) {
+			t.Errorf("ParseDate(%q) = %v out of range", s, d)
+		}
+	})
+}
+
+func FuzzParseRuntime(f *testing.F) {
+	for _, s := range []string{"120分", "01:02:03", "PT1H2M3S", "apx.1min", "", "99999999999h"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		if n := ParseRuntime(s); n < 0 {
+			t.Errorf("ParseRuntime(%q) = %d < 0", s, n)
+		}
+	})
+}
+
+func TestParseDate_TimeZone(t *testing.T) {
+	local := time.Local
+	defer func() { time.Local = local }()
+	// dates are kept as written regardless of the local time zone.
+	time.Local = time.FixedZone("JST", 9*60*60)
+
+	want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	for _, s := range []string{
+		"2024-01-02",
+		"2024年1月2日",
+		"2024-01-02T00:00:00+09:00",
+		"2024-01-02T23:00:00-08:00",
+	} {
+		assert.Equal(t, want, time.Time(ParseDate(s)), s)
+	}
+	assert.True(t, time.Time(ParseDate("")).IsZero())
+}
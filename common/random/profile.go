@@ -0,0 +1,90 @@
+package random
+
+import (
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"sync"
+)
+
+//go:embed profiles.json
+var defaultProfiles []byte
+
+// Profile is a consistent set of User-Agent and headers of a browser,
+// it should be picked once per session rather than per request, since
+// real browsers don't change their fingerprints between requests. The
+// `Accept-Language` and `Accept-Encoding` are negotiated by clients, so
+// they are not part of profiles.
+type Profile struct {
+	Name      string `json:"name"`
+	UserAgent string `json:"user_agent"`
+	// ClientHints are `Sec-CH-UA` headers, sent by Chromium-based
+	// browsers with all requests.
+	ClientHints map[string]string `json:"client_hints,omitempty"`
+	// Headers are sent with page navigations, e.g., `Accept`.
+	Headers map[string]string `json:"headers,omitempty"`
+}
+
+// NavigationHeaders returns headers of page navigations, including the
+// client hints, but not the User-Agent.
+func (p *Profile) NavigationHeaders() map[string]string {
+	headers := make(map[string]string, len(p.ClientHints)+len(p.Headers))
+	for key, value := range p.ClientHints {
+		headers[key] = value
+	}
+	for key, value := range p.Headers {
+		headers[key] = value
+	}
+	return headers
+}
+
+var (
+	profilesMu sync.RWMutex
+	profiles   []*Profile
+)
+
+func init() {
+	if err := loadProfiles(defaultProfiles); err != nil {
+		panic(err)
+	}
+}
+
+func loadProfiles(data []byte) error {
+	var ps []*Profile
+	if err := json.Unmarshal(data, &ps); err != nil {
+		return fmt.Errorf("invalid browser profiles: %w", err)
+	}
+	if len(ps) == 0 {
+		return errors.New("no browser profiles")
+	}
+	for i, p := range ps {
+		if p == nil || p.UserAgent == "" {
+			return fmt.Errorf("browser profile #%d: user_agent is required", i)
+		}
+	}
+	profilesMu.Lock()
+	profiles = ps
+	profilesMu.Unlock()
+	return nil
+}
+
+// LoadProfiles replaces browser profiles with the JSON array read from
+// r, in the same format as the embedded defaults. Sessions started
+// before keep their profiles.
+func LoadProfiles(r io.Reader) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return loadProfiles(data)
+}
+
+// BrowserProfile returns a random browser profile for a new session.
+func BrowserProfile() *Profile {
+	profilesMu.RLock()
+	defer profilesMu.RUnlock()
+	return profiles[rand.Intn(len(profiles))]
+}
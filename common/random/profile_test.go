@@ -0,0 +1,45 @@
+package random
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDefaultProfiles(t *testing.T) {
+	chromeRe := regexp.MustCompile(`Chrome/(\d+)\.`)
+	for _, p := range profiles {
+		// client hints must be consistent with the User-Agent.
+		if ss := chromeRe.FindStringSubmatch(p.UserAgent); len(ss) > 1 {
+			assert.Contains(t, p.ClientHints["Sec-CH-UA"], `v="`+ss[1]+`"`, p.Name)
+			platform := p.ClientHints["Sec-CH-UA-Platform"]
+			switch {
+			case strings.Contains(p.UserAgent, "Windows"):
+				assert.Equal(t, `"Windows"`, platform, p.Name)
+			case strings.Contains(p.UserAgent, "Macintosh"):
+				assert.Equal(t, `"macOS"`, platform, p.Name)
+			}
+		} else {
+			assert.Empty(t, p.ClientHints, p.Name)
+		}
+		assert.NotContains(t, p.NavigationHeaders(), "Accept-Language", p.Name)
+	}
+}
+
+func TestLoadProfiles(t *testing.T) {
+	defer func() { _ = loadProfiles(defaultProfiles) }()
+
+	assert.Error(t, LoadProfiles(strings.NewReader(`[]`)))
+	assert.Error(t, LoadProfiles(strings.NewReader(`[{"name":"empty"}]`)))
+	assert.Error(t, LoadProfiles(strings.NewReader(`{`)))
+
+	err := LoadProfiles(strings.NewReader(`[{"name":"custom","user_agent":"Custom/1.0","headers":{"Accept":"*/*"}}]`))
+	if assert.NoError(t, err) {
+		p := BrowserProfile()
+		assert.Equal(t, "Custom/1.0", p.UserAgent)
+		assert.Equal(t, "Custom/1.0", UserAgent())
+		assert.Equal(t, map[string]string{"Accept": "*/*"}, p.NavigationHeaders())
+	}
+}
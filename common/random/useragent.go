@@ -3,66 +3,23 @@ package random
 import (
 	"fmt"
 	"math/rand"
-	"strings"
 )
 
-var uaGens = []func() string{
-	genFirefoxUA,
-	genChromeUA,
-	genEdgeUA,
-}
-
 var uaGensMobile = []func() string{
 	genMobileUcwebUA,
 	genMobileNexus10UA,
 }
 
+// UserAgent returns the User-Agent of a random browser profile, use
+// BrowserProfile instead to send the headers consistent with it.
 func UserAgent() string {
-	return uaGens[rand.Intn(len(uaGens))]()
+	return BrowserProfile().UserAgent
 }
 
 func MobileUserAgent() string {
 	return uaGensMobile[rand.Intn(len(uaGensMobile))]()
 }
 
-var ffVersions = []float32{
-	// 2020
-	72.0,
-	73.0,
-	74.0,
-	75.0,
-	76.0,
-	77.0,
-	78.0,
-	79.0,
-	80.0,
-	81.0,
-	82.0,
-	83.0,
-	84.0,
-
-	// 2021
-	85.0,
-	86.0,
-	87.0,
-	88.0,
-	89.0,
-	90.0,
-	91.0,
-	92.0,
-	93.0,
-	94.0,
-	95.0,
-
-	// 2022
-	96.0,
-	97.0,
-	98.0,
-	99.0,
-	100.0,
-	101.0,
-}
-
 var chromeVersions = []string{
 	// 2020
 	"79.0.3945.117",
@@ -132,14 +89,6 @@ var chromeVersions = []string{
 	"102.0.5005.63",
 }
 
-var edgeVersions = []string{
-	"79.0.3945.74,79.0.309.43",
-	"80.0.3987.87,80.0.361.48",
-	"84.0.4147.105,84.0.522.50",
-	"89.0.4389.128,89.0.774.77",
-	"90.0.4430.72,90.0.818.39",
-}
-
 var ucwebVersions = []string{
 	"10.9.8.1006",
 	"11.0.0.1016",
@@ -222,96 +171,6 @@ var nexus10Safari = []string{
 	"600.1.4",
 }
 
-var osStrings = []string{
-	// MacOS - High Sierra
-	"Macintosh; Intel Mac OS X 10_13",
-	"Macintosh; Intel Mac OS X 10_13_1",
-	"Macintosh; Intel Mac OS X 10_13_2",
-	"Macintosh; Intel Mac OS X 10_13_3",
-	"Macintosh; Intel Mac OS X 10_13_4",
-	"Macintosh; Intel Mac OS X 10_13_5",
-	"Macintosh; Intel Mac OS X 10_13_6",
-
-	// MacOS - Mojave
-	"Macintosh; Intel Mac OS X 10_14",
-	"Macintosh; Intel Mac OS X 10_14_1",
-	"Macintosh; Intel Mac OS X 10_14_2",
-	"Macintosh; Intel Mac OS X 10_14_3",
-	"Macintosh; Intel Mac OS X 10_14_4",
-	"Macintosh; Intel Mac OS X 10_14_5",
-	"Macintosh; Intel Mac OS X 10_14_6",
-
-	// MacOS - Catalina
-	"Macintosh; Intel Mac OS X 10_15",
-	"Macintosh; Intel Mac OS X 10_15_1",
-	"Macintosh; Intel Mac OS X 10_15_2",
-	"Macintosh; Intel Mac OS X 10_15_3",
-	"Macintosh; Intel Mac OS X 10_15_4",
-	"Macintosh; Intel Mac OS X 10_15_5",
-	"Macintosh; Intel Mac OS X 10_15_6",
-	"Macintosh; Intel Mac OS X 10_15_7",
-
-	// MacOS - Big Sur
-	"Macintosh; Intel Mac OS X 11_0",
-	"Macintosh; Intel Mac OS X 11_0_1",
-	"Macintosh; Intel Mac OS X 11_1",
-	"Macintosh; Intel Mac OS X 11_2",
-	"Macintosh; Intel Mac OS X 11_2_1",
-	"Macintosh; Intel Mac OS X 11_2_2",
-	"Macintosh; Intel Mac OS X 11_2_3",
-	"Macintosh; Intel Mac OS X 11_3",
-	"Macintosh; Intel Mac OS X 11_3_1",
-	"Macintosh; Intel Mac OS X 11_4",
-	"Macintosh; Intel Mac OS X 11_5",
-	"Macintosh; Intel Mac OS X 11_5_1",
-	"Macintosh; Intel Mac OS X 11_5_2",
-	"Macintosh; Intel Mac OS X 11_6",
-	"Macintosh; Intel Mac OS X 11_6_1",
-	"Macintosh; Intel Mac OS X 11_6_2",
-	"Macintosh; Intel Mac OS X 11_6_3",
-	"Macintosh; Intel Mac OS X 11_6_4",
-	"Macintosh; Intel Mac OS X 11_6_5",
-
-	// MacOS - Monterey
-	"Macintosh; Intel Mac OS X 12_0",
-	"Macintosh; Intel Mac OS X 12_0_1",
-	"Macintosh; Intel Mac OS X 12_1",
-	"Macintosh; Intel Mac OS X 12_2",
-	"Macintosh; Intel Mac OS X 12_2_1",
-	"Macintosh; Intel Mac OS X 12_3",
-	"Macintosh; Intel Mac OS X 12_3_1",
-	"Macintosh; Intel Mac OS X 12_4",
-
-	// Windows
-	"Windows NT 10.0; Win64; x64",
-	"Windows NT 5.1",
-	"Windows NT 6.1; WOW64",
-	"Windows NT 6.1; Win64; x64",
-
-	// Linux
-	"X11; Linux x86_64",
-}
-
-func genFirefoxUA() string {
-	version := ffVersions[rand.Intn(len(ffVersions))]
-	os := osStrings[rand.Intn(len(osStrings))]
-	return fmt.Sprintf("Mozilla/5.0 (%s; rv:%.1f) Gecko/20100101 Firefox/%.1f", os, version, version)
-}
-
-func genChromeUA() string {
-	version := chromeVersions[rand.Intn(len(chromeVersions))]
-	os := osStrings[rand.Intn(len(osStrings))]
-	return fmt.Sprintf("Mozilla/5.0 (%s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%s Safari/537.36", os, version)
-}
-
-func genEdgeUA() string {
-	version := edgeVersions[rand.Intn(len(edgeVersions))]
-	chromeVersion := strings.Split(version, ",")[0]
-	edgeVersion := strings.Split(version, ",")[1]
-	os := osStrings[rand.Intn(len(osStrings))]
-	return fmt.Sprintf("Mozilla/5.0 (%s) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/%s Safari/537.36 Edg/%s", os, chromeVersion, edgeVersion)
-}
-
 func genMobileUcwebUA() string {
 	device := ucwebDevices[rand.Intn(len(ucwebDevices))]
 	version := ucwebVersions[rand.Intn(len(ucwebVersions))]
@@ -0,0 +1,119 @@
+// Package redact anonymizes sensitive texts, e.g., titles, numbers and
+// URLs, in logs and metrics labels.
+package redact
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// urlRegexp matches URLs in messages, e.g., of request errors.
+var urlRegexp = regexp.MustCompile(`https?://[^\s"'<>]+`)
+
+// Redactor replaces texts with their keyed hashes, so that the same text
+// is always redacted to the same label within the same key, which still
+// makes logs correlatable. A nil *Redactor redacts nothing.
+type Redactor struct {
+	key []byte
+}
+
+// New returns a *Redactor with the key, a random key is used if empty.
+func New(key []byte) *Redactor {
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		_, _ = rand.Read(key)
+	}
+	return &Redactor{key: key}
+}
+
+// Enabled reports whether the redactor is enabled.
+func (r *Redactor) Enabled() bool { return r != nil }
+
+// String redacts s as a short hash label.
+func (r *Redactor) String(s string) string {
+	if r == nil || s == "" {
+		return s
+	}
+	mac := hmac.New(sha256.New, r.key)
+	mac.Write([]byte(s))
+	return "~" + hex.EncodeToString(mac.Sum(nil))[:12]
+}
+
+// URL redacts the path segments and query values of the URL, the scheme,
+// host and query keys are kept.
+func (r *Redactor) URL(rawURL string) string {
+	if r == nil {
+		return rawURL
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return r.String(rawURL)
+	}
+	segments := strings.Split(u.Path, "/")
+	for i, segment := range segments {
+		segments[i] = r.String(segment)
+	}
+	u.Path, u.RawPath = strings.Join(segments, "/"), ""
+	u.RawQuery = r.Query(u.RawQuery)
+	u.Fragment, u.RawFragment = "", ""
+	return u.String()
+}
+
+// Query redacts values of the raw query.
+func (r *Redactor) Query(rawQuery string) string {
+	if r == nil || rawQuery == "" {
+		return rawQuery
+	}
+	q, err := url.ParseQuery(rawQuery)
+	if err != nil {
+		return r.String(rawQuery)
+	}
+	for k, vs := range q {
+		for i, v := range vs {
+			vs[i] = r.String(v)
+		}
+		q[k] = vs
+	}
+	return q.Encode()
+}
+
+// Error redacts the URLs and the texts, e.g., IDs or names the error is
+// about, in the message of the error. The redacted error doesn't wrap the
+// original, which has the texts.
+func (r *Redactor) Error(err error, texts ...string) error {
+	if r == nil || err == nil {
+		return err
+	}
+	msg := urlRegexp.ReplaceAllStringFunc(err.Error(), r.URL)
+	for _, text := range texts {
+		if text != "" {
+			msg = strings.ReplaceAll(msg, text, r.String(text))
+		}
+	}
+	return errors.New(msg)
+}
+
+// Error returns the error with the secrets, e.g., tokens in request URLs,
+// replaced in its message, or the error itself if none is found. The
+// redacted error doesn't wrap the original, which has the secrets.
+func Error(err error, secrets ...string) error {
+	if err == nil {
+		return nil
+	}
+	msg := err.Error()
+	for _, secret := range secrets {
+		if secret != "" {
+			msg = strings.ReplaceAll(msg, secret, "REDACTED")
+		}
+	}
+	if msg == err.Error() {
+		return err
+	}
+	return errors.New(msg)
+}
@@ -0,0 +1,53 @@
+package redact
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactor_String(t *testing.T) {
+	r := New([]byte("key"))
+	assert.Equal(t, r.String("ABC-123"), r.String("ABC-123"))
+	assert.NotEqual(t, r.String("ABC-123"), r.String("ABC-124"))
+	assert.NotEqual(t, r.String("ABC-123"), New([]byte("other")).String("ABC-123"))
+	assert.True(t, strings.HasPrefix(r.String("ABC-123"), "~"))
+	assert.Equal(t, "", r.String(""))
+
+	var disabled *Redactor
+	assert.False(t, disabled.Enabled())
+	assert.Equal(t, "ABC-123", disabled.String("ABC-123"))
+	assert.Equal(t, "https://example.com/a?b=c", disabled.URL("https://example.com/a?b=c"))
+}
+
+func TestRedactor_URL(t *testing.T) {
+	r := New(nil)
+	s := r.URL("https://example.com/movies/ABC-123?q=title&lazy=1")
+	assert.True(t, strings.HasPrefix(s, "https://example.com/~"))
+	assert.NotContains(t, s, "ABC-123")
+	assert.NotContains(t, s, "title")
+	assert.Contains(t, s, "lazy=~")
+}
+
+func TestRedactor_Error(t *testing.T) {
+	r := New(nil)
+	err := r.Error(errors.New(`Get "https://example.com/movies/ABC-123": EOF, series Title`), "Title")
+	assert.NotContains(t, err.Error(), "ABC-123")
+	assert.NotContains(t, err.Error(), "Title")
+	assert.Contains(t, err.Error(), `Get "https://example.com/~`)
+	assert.Contains(t, err.Error(), "series "+r.String("Title"))
+
+	var disabled *Redactor
+	err = errors.New("https://example.com/ABC-123")
+	assert.Equal(t, err, disabled.Error(err, "ABC-123"))
+	assert.NoError(t, r.Error(nil))
+}
+
+func TestError(t *testing.T) {
+	err := errors.New(`Post "https://api.telegram.org/bot123:secret/sendMessage": EOF`)
+	assert.Equal(t, `Post "https://api.telegram.org/botREDACTED/sendMessage": EOF`, Error(err, "123:secret").Error())
+	assert.Equal(t, err, Error(err, "other", ""))
+	assert.NoError(t, Error(nil, "123:secret"))
+}
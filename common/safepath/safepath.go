@@ -0,0 +1,128 @@
+// Package safepath makes texts, e.g., titles, safe to be used in file
+// paths, with the strictest rules of common file systems, which are the
+// Windows ones, so that libraries can be shared across platforms.
+package safepath
+
+import (
+	"path/filepath"
+	"runtime"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/width"
+)
+
+const (
+	// MaxSegmentLength is the max bytes of a path segment, which most file
+	// systems limit to 255, with some room left for suffixes of temporary
+	// and backup files.
+	MaxSegmentLength = 200
+	// maxPathLength is the MAX_PATH of Windows, beyond which the long path
+	// prefix is required.
+	maxPathLength = 260
+)
+
+// replacement replaces invalid characters.
+const replacement = '_'
+
+// reservedNames are device names of Windows, which are reserved with any
+// extensions, e.g., `CON.txt`.
+var reservedNames = map[string]struct{}{
+	"CON": {}, "PRN": {}, "AUX": {}, "NUL": {},
+	"COM1": {}, "COM2": {}, "COM3": {}, "COM4": {}, "COM5": {},
+	"COM6": {}, "COM7": {}, "COM8": {}, "COM9": {},
+	"LPT1": {}, "LPT2": {}, "LPT3": {}, "LPT4": {}, "LPT5": {},
+	"LPT6": {}, "LPT7": {}, "LPT8": {}, "LPT9": {},
+}
+
+// Segment makes s a safe path segment, i.e., a file or directory name.
+// Full-width alphanumerics and symbols are normalized to half-width ones,
+// invalid characters are replaced, reserved names are suffixed, trailing
+// dots and spaces are trimmed, and it's truncated to MaxSegmentLength.
+func Segment(s string) string {
+	return segment(s, "")
+}
+
+// File makes name a safe file name like Segment, but the extension is
+// kept when truncated.
+func File(name string) string {
+	ext := filepath.Ext(name)
+	if len(ext) > 16 || strings.ContainsAny(ext, " ") {
+		ext = "" // not a real extension.
+	}
+	return segment(strings.TrimSuffix(name, ext), Segment(ext))
+}
+
+func segment(s, ext string) string {
+	s = strings.Map(func(r rune) rune {
+		switch {
+		case unicode.IsSpace(r):
+			return ' '
+		case r < 0x20 || r == 0x7f:
+			return -1
+		case strings.ContainsRune(`/\:*?"<>|`, r):
+			return replacement
+		}
+		return r
+	}, width.Fold.String(s))
+	s = strings.Join(strings.Fields(s), " ")
+	s = truncate(strings.TrimLeft(s, " "), MaxSegmentLength-len(ext))
+	s = strings.TrimRight(s, ". ")
+	if s == "" {
+		return s
+	}
+	if base, _, _ := strings.Cut(s, "."); isReserved(base) {
+		s = base + string(replacement) + s[len(base):]
+	}
+	return s + ext
+}
+
+func isReserved(name string) bool {
+	_, ok := reservedNames[strings.ToUpper(strings.TrimSpace(name))]
+	return ok
+}
+
+// truncate truncates s to n bytes at most, on a rune boundary.
+func truncate(s string, n int) string {
+	if n < 0 {
+		n = 0
+	}
+	if len(s) <= n {
+		return s
+	}
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
+
+// LongPath returns the path with the long path prefix on Windows if it
+// exceeds MAX_PATH, so that it's accessible without the registry opt-in.
+// The path is returned as is on other platforms.
+func LongPath(path string) string {
+	return longPath(path, runtime.GOOS)
+}
+
+func longPath(path, goos string) string {
+	if goos != "windows" || len(path) < maxPathLength || strings.HasPrefix(path, `\\?\`) {
+		return path
+	}
+	path = strings.ReplaceAll(path, "/", `\`)
+	switch {
+	case strings.HasPrefix(path, `\\`): // UNC path, e.g., \\server\share.
+		return `\\?\UNC\` + path[2:]
+	case len(path) >= 3 && path[1] == ':' && path[2] == '\\': // absolute path.
+		return `\\?\` + path
+	}
+	return path // relative paths can't be prefixed.
+}
+
+// ShortPath returns the path without the long path prefix of LongPath,
+// e.g., to be compared with other paths.
+func ShortPath(path string) string {
+	if rest, ok := strings.CutPrefix(path, `\\?\UNC\`); ok {
+		return `\\` + rest
+	}
+	return strings.TrimPrefix(path, `\\?\`)
+}
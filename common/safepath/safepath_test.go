@@ -0,0 +1,51 @@
+package safepath
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSegment(t *testing.T) {
+	for _, unit := range []struct {
+		s, want string
+	}{
+		{"ABP-030 Title", "ABP-030 Title"},
+		{`a:b/c\d*e?f"g<h>i|j`, "a_b_c_d_e_f_g_h_i_j"},
+		{"ＡＢＰ－０３０　タイトル：前編", "ABP-030 タイトル_前編"},
+		{"ｱｲｳ", "アイウ"},
+		{"title. . ", "title"},
+		{"CON", "CON_"},
+		{"com1.txt", "com1_.txt"},
+		{"CONSOLE", "CONSOLE"},
+		{"a\tb\x00c", "a bc"},
+		{"...", ""},
+	} {
+		assert.Equal(t, unit.want, Segment(unit.s), unit.s)
+	}
+	long := Segment(strings.Repeat("三", 100))
+	assert.LessOrEqual(t, len(long), MaxSegmentLength)
+	assert.Equal(t, strings.Repeat("三", MaxSegmentLength/3), long)
+}
+
+func TestFile(t *testing.T) {
+	assert.Equal(t, "a_b.mp4", File("a:b.mp4"))
+	assert.Equal(t, "nul_.jpg", File("nul.jpg"))
+	name := File(strings.Repeat("a", 300) + ".mp4")
+	assert.Len(t, name, MaxSegmentLength)
+	assert.True(t, strings.HasSuffix(name, ".mp4"))
+}
+
+func TestLongPath(t *testing.T) {
+	long := `C:\Library\` + strings.Repeat("a", 300) + `\movie.mp4`
+	assert.Equal(t, `\\?\`+long, longPath(long, "windows"))
+	assert.Equal(t, long, longPath(long, "linux"))
+	assert.Equal(t, `C:\short.mp4`, longPath(`C:\short.mp4`, "windows"))
+	unc := `\\nas\share\` + strings.Repeat("b", 300)
+	assert.Equal(t, `\\?\UNC\nas\share\`+strings.Repeat("b", 300), longPath(unc, "windows"))
+
+	assert.Equal(t, long, ShortPath(longPath(long, "windows")))
+	assert.Equal(t, unc, ShortPath(longPath(unc, "windows")))
+	assert.Equal(t, "/library/movie.mp4", ShortPath("/library/movie.mp4"))
+}
@@ -0,0 +1,158 @@
+// Package secret resolves secret references in config values, so that
+// configs are safe to commit without secret material. References may be
+// embedded anywhere in a value, e.g., in the password of a DSN:
+//
+//	${env:NAME}    value of the environment variable
+//	${file:/path}  content of the file, e.g., Docker or Kubernetes secrets
+//	${enc:BASE64}  value encrypted at rest by the secret key
+package secret
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// KeySize is the size of secret keys, which are AES-256 keys.
+const KeySize = 32
+
+var (
+	ErrNoKey       = errors.New("secret key is required to decrypt")
+	ErrInvalidKey  = errors.New("invalid secret key")
+	ErrEnvNotFound = errors.New("environment variable not found")
+)
+
+var refPattern = regexp.MustCompile(`\$\{(env|file|enc):([^}]*)}`)
+
+// Resolver resolves secret references with the secret key, if any.
+type Resolver struct {
+	aead cipher.AEAD
+}
+
+// NewResolver returns a *Resolver of the key, a nil key resolves all
+// references except encrypted ones.
+func NewResolver(key []byte) (*Resolver, error) {
+	r := &Resolver{}
+	if key == nil {
+		return r, nil
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	r.aead = aead
+	return r, nil
+}
+
+// Resolve replaces all references in the value with their secrets.
+func (r *Resolver) Resolve(value string) (string, error) {
+	var err error
+	resolved := refPattern.ReplaceAllStringFunc(value, func(ref string) string {
+		if err != nil {
+			return ""
+		}
+		var (
+			m      = refPattern.FindStringSubmatch(ref)
+			secret string
+		)
+		secret, err = r.resolve(m[1], m[2])
+		return secret
+	})
+	if err != nil {
+		return "", err
+	}
+	return resolved, nil
+}
+
+func (r *Resolver) resolve(kind, arg string) (string, error) {
+	switch kind {
+	case "env":
+		if v, ok := os.LookupEnv(arg); ok {
+			return v, nil
+		}
+		return "", fmt.Errorf("%w: %s", ErrEnvNotFound, arg)
+	case "file":
+		data, err := os.ReadFile(arg)
+		if err != nil {
+			return "", err
+		}
+		// files usually end with a newline.
+		return strings.TrimRight(string(data), "\r\n"), nil
+	default: // enc
+		if r.aead == nil {
+			return "", ErrNoKey
+		}
+		return r.decrypt(arg)
+	}
+}
+
+func (r *Resolver) decrypt(s string) (string, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return "", fmt.Errorf("invalid encrypted secret: %w", err)
+	}
+	n := r.aead.NonceSize()
+	if len(data) < n {
+		return "", errors.New("invalid encrypted secret: too short")
+	}
+	plaintext, err := r.aead.Open(nil, data[:n], data[n:], nil)
+	if err != nil {
+		return "", errors.New("failed to decrypt secret, wrong key?")
+	}
+	return string(plaintext), nil
+}
+
+// HasRefs reports whether the value contains any references.
+func HasRefs(value string) bool {
+	return refPattern.MatchString(value)
+}
+
+// Encrypt returns the encrypted reference of the secret, which is
+// resolved by the Resolver of the same key.
+func Encrypt(key []byte, secret string) (string, error) {
+	aead, err := newAEAD(key)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", err
+	}
+	data := aead.Seal(nonce, nonce, []byte(secret), nil)
+	return "${enc:" + base64.RawURLEncoding.EncodeToString(data) + "}", nil
+}
+
+// GenerateKey returns a new random key, encoded as ParseKey accepts.
+func GenerateKey() (string, error) {
+	key := make([]byte, KeySize)
+	if _, err := rand.Read(key); err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+// ParseKey parses the base64 encoded key.
+func ParseKey(s string) ([]byte, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(s))
+	if err != nil || len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, ErrInvalidKey
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
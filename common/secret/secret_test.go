@@ -0,0 +1,68 @@
+package secret
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResolve(t *testing.T) {
+	t.Setenv("METATUBE_TEST_PASSWORD", "p@ss")
+	name := filepath.Join(t.TempDir(), "token")
+	_ = os.WriteFile(name, []byte("s3cret\n"), 0o600)
+
+	r, _ := NewResolver(nil)
+	for _, unit := range []struct {
+		value, want string
+	}{
+		{"literal", "literal"},
+		{"file::memory:?cache=shared", "file::memory:?cache=shared"},
+		{"${env:METATUBE_TEST_PASSWORD}", "p@ss"},
+		{"postgres://user:${env:METATUBE_TEST_PASSWORD}@host/db", "postgres://user:p@ss@host/db"},
+		{"${file:" + name + "}", "s3cret"},
+	} {
+		got, err := r.Resolve(unit.value)
+		assert.NoError(t, err, unit.value)
+		assert.Equal(t, unit.want, got)
+	}
+
+	_, err := r.Resolve("${env:METATUBE_TEST_UNSET}")
+	assert.ErrorIs(t, err, ErrEnvNotFound)
+	_, err = r.Resolve("${file:" + name + ".missing}")
+	assert.Error(t, err)
+	_, err = r.Resolve("${enc:AAAA}")
+	assert.ErrorIs(t, err, ErrNoKey)
+}
+
+func TestEncrypt(t *testing.T) {
+	encoded, err := GenerateKey()
+	if !assert.NoError(t, err) {
+		return
+	}
+	key, err := ParseKey(encoded)
+	if !assert.NoError(t, err) {
+		return
+	}
+	ref, err := Encrypt(key, "user:password")
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.True(t, HasRefs(ref))
+
+	r, _ := NewResolver(key)
+	got, err := r.Resolve(ref)
+	assert.NoError(t, err)
+	assert.Equal(t, "user:password", got)
+
+	// other keys can't decrypt.
+	other, _ := GenerateKey()
+	otherKey, _ := ParseKey(other)
+	r, _ = NewResolver(otherKey)
+	_, err = r.Resolve(ref)
+	assert.Error(t, err)
+
+	_, err = ParseKey("short")
+	assert.ErrorIs(t, err, ErrInvalidKey)
+}
@@ -0,0 +1,84 @@
+//go:build !js && !wasip1
+
+package storage
+
+import (
+	"bytes"
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var _ KV = (*Bolt)(nil)
+
+// boltBucket is the bucket of entries.
+var boltBucket = []byte("metatube")
+
+// Bolt is a KV in a single bbolt file, which persists across restarts
+// without external services, but is locked by one process at a time.
+type Bolt struct {
+	db *bolt.DB
+	// TTL is the lifetime of entries, zero means never expire.
+	TTL time.Duration
+}
+
+// OpenBolt opens or creates the bbolt file of the path.
+func OpenBolt(path string, ttl time.Duration) (*Bolt, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+	if err = db.Update(func(tx *bolt.Tx) error {
+		_, err := tx.CreateBucketIfNotExists(boltBucket)
+		return err
+	}); err != nil {
+		db.Close()
+		return nil, err
+	}
+	return &Bolt{db: db, TTL: ttl}, nil
+}
+
+// Get returns the value of the key if present and not expired, values
+// are prefixed by the time set.
+func (b *Bolt) Get(key string) (value []byte, ok bool) {
+	_ = b.db.View(func(tx *bolt.Tx) error {
+		v := tx.Bucket(boltBucket).Get([]byte(key))
+		if len(v) < 8 {
+			return nil
+		}
+		setAt := time.Unix(0, int64(binary.BigEndian.Uint64(v[:8])))
+		if b.TTL > 0 && time.Since(setAt) > b.TTL {
+			return nil
+		}
+		// values are only valid in transactions.
+		value, ok = bytes.Clone(v[8:]), true
+		return nil
+	})
+	return
+}
+
+func (b *Bolt) Set(key string, value []byte) error {
+	v := make([]byte, 8+len(value))
+	binary.BigEndian.PutUint64(v[:8], uint64(time.Now().UnixNano()))
+	copy(v[8:], value)
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Put([]byte(key), v)
+	})
+}
+
+func (b *Bolt) Delete(key string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(boltBucket).Delete([]byte(key))
+	})
+}
+
+// Close closes the file.
+func (b *Bolt) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,27 @@
+//go:build js || wasip1
+
+package storage
+
+import (
+	"errors"
+	"time"
+)
+
+var _ KV = (*Bolt)(nil)
+
+// ErrBoltUnsupported is returned by OpenBolt on WASM, which has no file
+// locks and memory maps required by bbolt.
+var ErrBoltUnsupported = errors.New("bolt is not supported on wasm")
+
+// Bolt is unavailable on WASM, see OpenBolt.
+type Bolt struct{}
+
+func OpenBolt(string, time.Duration) (*Bolt, error) { return nil, ErrBoltUnsupported }
+
+func (*Bolt) Get(string) ([]byte, bool) { return nil, false }
+
+func (*Bolt) Set(string, []byte) error { return ErrBoltUnsupported }
+
+func (*Bolt) Delete(string) error { return ErrBoltUnsupported }
+
+func (*Bolt) Close() error { return nil }
@@ -0,0 +1,157 @@
+package storage
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	_ KV   = (*FileKV)(nil)
+	_ Blob = (*FileBlob)(nil)
+)
+
+// FileKV is a content-addressed key-value store on disk, entries older
+// than the TTL are treated as missing and overwritten by the next Set.
+type FileKV struct {
+	// Dir is the root directory of the store.
+	Dir string
+	// TTL is the lifetime of entries, zero means never expire.
+	TTL time.Duration
+}
+
+// NewFileKV returns a *FileKV of the directory.
+func NewFileKV(dir string, ttl time.Duration) *FileKV {
+	return &FileKV{Dir: dir, TTL: ttl}
+}
+
+// Path returns the path of the stored file of the key.
+func (s *FileKV) Path(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	name := hex.EncodeToString(sum[:])
+	return filepath.Join(s.Dir, name[:2], name)
+}
+
+// Get returns the data of the key if present and not expired.
+func (s *FileKV) Get(key string) ([]byte, bool) {
+	name := s.Path(key)
+	if s.TTL > 0 {
+		fi, err := os.Stat(name)
+		if err != nil || time.Since(fi.ModTime()) > s.TTL {
+			return nil, false
+		}
+	}
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Set writes the data of the key atomically.
+func (s *FileKV) Set(key string, data []byte) error {
+	return WriteFile(s.Path(key), data)
+}
+
+func (s *FileKV) Delete(key string) error {
+	return removeFile(s.Path(key))
+}
+
+// FileBlob is a Blob of files by names under the directory.
+type FileBlob struct {
+	// Dir is the root directory of the store.
+	Dir string
+}
+
+// NewFileBlob returns a *FileBlob of the directory.
+func NewFileBlob(dir string) *FileBlob {
+	return &FileBlob{Dir: dir}
+}
+
+// Path returns the path of the file of the name.
+func (b *FileBlob) Path(name string) (string, error) {
+	name, err := cleanName(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(b.Dir, filepath.FromSlash(name)), nil
+}
+
+func (b *FileBlob) Open(name string) (io.ReadCloser, error) {
+	path, err := b.Path(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+func (b *FileBlob) Put(name string, r io.Reader) error {
+	path, err := b.Path(name)
+	if err != nil {
+		return err
+	}
+	return writeFile(path, r)
+}
+
+func (b *FileBlob) Stat(name string) (*BlobInfo, error) {
+	path, err := b.Path(name)
+	if err != nil {
+		return nil, err
+	}
+	fi, err := os.Stat(path)
+	if os.IsNotExist(err) {
+		return nil, ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &BlobInfo{Name: name, Size: fi.Size(), ModTime: fi.ModTime()}, nil
+}
+
+func (b *FileBlob) Delete(name string) error {
+	path, err := b.Path(name)
+	if err != nil {
+		return err
+	}
+	return removeFile(path)
+}
+
+// WriteFile writes data to a temp file first, and then renames it, so
+// that concurrent readers never see partially written files.
+func WriteFile(name string, data []byte) error {
+	return writeFile(name, bytes.NewReader(data))
+}
+
+func writeFile(name string, r io.Reader) error {
+	if err := os.MkdirAll(filepath.Dir(name), 0o755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(name), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	if _, err = io.Copy(f, r); err != nil {
+		f.Close()
+		return err
+	}
+	if err = f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), name)
+}
+
+func removeFile(name string) error {
+	if err := os.Remove(name); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
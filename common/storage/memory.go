@@ -0,0 +1,94 @@
+package storage
+
+import (
+	"bytes"
+	"io"
+	"sync"
+	"time"
+)
+
+var (
+	_ KV   = (*Memory)(nil)
+	_ Blob = (*Memory)(nil)
+)
+
+type memoryEntry struct {
+	value   []byte
+	modTime time.Time
+}
+
+// Memory is a KV and Blob in memory, which is lost on restarts. It's for
+// tests and single instances without persistence.
+type Memory struct {
+	// TTL is the lifetime of entries, zero means never expire.
+	TTL time.Duration
+
+	mu      sync.RWMutex
+	entries map[string]*memoryEntry
+}
+
+// NewMemory returns an empty *Memory.
+func NewMemory(ttl time.Duration) *Memory {
+	return &Memory{TTL: ttl, entries: make(map[string]*memoryEntry)}
+}
+
+func (m *Memory) entry(key string) (*memoryEntry, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	e, ok := m.entries[key]
+	if !ok || (m.TTL > 0 && time.Since(e.modTime) > m.TTL) {
+		return nil, false
+	}
+	return e, true
+}
+
+func (m *Memory) Get(key string) ([]byte, bool) {
+	e, ok := m.entry(key)
+	if !ok {
+		return nil, false
+	}
+	return bytes.Clone(e.value), true
+}
+
+func (m *Memory) Set(key string, value []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.entries[key] = &memoryEntry{value: bytes.Clone(value), modTime: time.Now()}
+	return nil
+}
+
+func (m *Memory) Delete(key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.entries, key)
+	return nil
+}
+
+func (m *Memory) Open(name string) (io.ReadCloser, error) {
+	e, ok := m.entry(name)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	// values are never modified in place, so no need to copy.
+	return io.NopCloser(bytes.NewReader(e.value)), nil
+}
+
+func (m *Memory) Put(name string, r io.Reader) error {
+	name, err := cleanName(name)
+	if err != nil {
+		return err
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	return m.Set(name, data)
+}
+
+func (m *Memory) Stat(name string) (*BlobInfo, error) {
+	e, ok := m.entry(name)
+	if !ok {
+		return nil, ErrNotFound
+	}
+	return &BlobInfo{Name: name, Size: int64(len(e.value)), ModTime: e.modTime}, nil
+}
@@ -0,0 +1,207 @@
+package storage
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// defaultRedisLockTTL bounds the time other instances wait for a load.
+	defaultRedisLockTTL   = 30 * time.Second
+	redisLockPollInterval = 100 * time.Millisecond
+	redisDefaultPort      = "6379"
+)
+
+// releaseScript deletes the lock only if it's still held by the token.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0`)
+
+var _ KV = (*Redis)(nil)
+
+// Redis is a KV on Redis or Valkey servers, which is shared by server
+// instances behind a load balancer. Loads of missing entries are locked
+// across instances, so that a response is fetched once at a time, and
+// the others wait for it to be cached.
+type Redis struct {
+	client redis.UniversalClient
+	prefix string
+	// TTL is the lifetime of entries, zero means never expire.
+	TTL time.Duration
+	// LockTTL is the max time of a load holding the lock.
+	LockTTL time.Duration
+	group   singleflight.Group
+}
+
+// NewRedis returns a *Redis of the server URL:
+//
+//	redis://[[user]:password@]host[:port][,host[:port]...][/db][?prefix=...&cluster=true]
+//
+// Use rediss for TLS. Multiple hosts or the cluster option connect to a
+// cluster, and the prefix namespaces keys, `metatube:` by default.
+func NewRedis(rawURL string, ttl time.Duration) (*Redis, error) {
+	// url.Parse doesn't accept multiple hosts, so they are split first.
+	scheme, rest, ok := strings.Cut(rawURL, "://")
+	if !ok {
+		return nil, fmt.Errorf("invalid redis URL: %s", rawURL)
+	}
+	authority, path := rest, ""
+	if i := strings.IndexAny(rest, "/?"); i >= 0 {
+		authority, path = rest[:i], rest[i:]
+	}
+	userinfo, hosts := "", authority
+	if i := strings.LastIndex(authority, "@"); i >= 0 {
+		userinfo, hosts = authority[:i+1], authority[i+1:]
+	}
+	var addrs []string
+	for _, host := range strings.Split(hosts, ",") {
+		if _, _, err := net.SplitHostPort(host); err != nil {
+			host = net.JoinHostPort(host, redisDefaultPort)
+		}
+		addrs = append(addrs, host)
+	}
+	// parse the other options by the first host.
+	u, err := url.Parse(scheme + "://" + userinfo + addrs[0] + path)
+	if err != nil {
+		return nil, err
+	}
+	query := u.Query()
+	prefix := "metatube:"
+	if query.Has("prefix") {
+		prefix = query.Get("prefix")
+	}
+	cluster := query.Get("cluster") == "true"
+	query.Del("prefix")
+	query.Del("cluster")
+	u.RawQuery = query.Encode()
+	opts, err := redis.ParseURL(u.String())
+	if err != nil {
+		return nil, fmt.Errorf("invalid redis URL: %w", err)
+	}
+	uopts := &redis.UniversalOptions{
+		Addrs:     addrs,
+		DB:        opts.DB,
+		Username:  opts.Username,
+		Password:  opts.Password,
+		TLSConfig: opts.TLSConfig,
+	}
+	var client redis.UniversalClient
+	if cluster {
+		client = redis.NewClusterClient(uopts.Cluster())
+	} else {
+		client = redis.NewUniversalClient(uopts)
+	}
+	return &Redis{client: client, prefix: prefix, TTL: ttl, LockTTL: defaultRedisLockTTL}, nil
+}
+
+// Key returns the Redis key of the cache key.
+func (r *Redis) Key(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return r.prefix + hex.EncodeToString(sum[:])
+}
+
+// Get returns the data of the key if present, errors are misses.
+func (r *Redis) Get(key string) ([]byte, bool) {
+	data, err := r.client.Get(context.Background(), r.Key(key)).Bytes()
+	if err != nil {
+		return nil, false
+	}
+	return data, true
+}
+
+// Set stores the data of the key with the TTL.
+func (r *Redis) Set(key string, data []byte) error {
+	return r.client.Set(context.Background(), r.Key(key), data, r.TTL).Err()
+}
+
+// Delete removes the key.
+func (r *Redis) Delete(key string) error {
+	return r.client.Del(context.Background(), r.Key(key)).Err()
+}
+
+// Load returns the cached data of the key, or loads it under the lock of
+// the key. Instances failed to get the lock wait for the data cached by
+// the holder, and load by themselves if the holder fails or times out.
+func (r *Redis) Load(key string, load func() ([]byte, error)) ([]byte, error) {
+	if data, ok := r.Get(key); ok {
+		return data, nil
+	}
+	v, err, _ := r.group.Do(key, func() (any, error) {
+		return r.load(key, load)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]byte), nil
+}
+
+func (r *Redis) load(key string, load func() ([]byte, error)) ([]byte, error) {
+	var (
+		ctx   = context.Background()
+		lock  = r.Key(key) + ":lock"
+		token = randomToken()
+	)
+	acquired, err := r.client.SetNX(ctx, lock, token, r.LockTTL).Result()
+	if err == nil && !acquired {
+		for deadline := time.Now().Add(r.LockTTL); time.Now().Before(deadline); {
+			time.Sleep(redisLockPollInterval)
+			if data, ok := r.Get(key); ok {
+				return data, nil
+			}
+			if n, err := r.client.Exists(ctx, lock).Result(); err != nil || n == 0 {
+				break // released without data, or server down.
+			}
+		}
+	}
+	if acquired {
+		defer releaseScript.Run(ctx, r.client, []string{lock}, token)
+	}
+	data, err := load()
+	if err != nil {
+		return nil, err
+	}
+	_ = r.Set(key, data) // ignore error
+	return data, nil
+}
+
+// Client returns the client of the servers.
+func (r *Redis) Client() redis.UniversalClient {
+	return r.client
+}
+
+// Prefix returns the prefix of keys.
+func (r *Redis) Prefix() string {
+	return r.prefix
+}
+
+// Ping checks the connection to the servers.
+func (r *Redis) Ping() error {
+	if err := r.client.Ping(context.Background()).Err(); err != nil {
+		return errors.Join(errors.New("redis unavailable"), err)
+	}
+	return nil
+}
+
+// Close closes the connections.
+func (r *Redis) Close() error {
+	return r.client.Close()
+}
+
+func randomToken() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -0,0 +1,279 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/errors"
+)
+
+var _ Blob = (*S3)(nil)
+
+const (
+	s3DefaultRegion  = "us-east-1"
+	s3UnsignedSHA256 = "UNSIGNED-PAYLOAD"
+	s3TimeFormat     = "20060102T150405Z"
+)
+
+// S3 is a Blob on S3 compatible services, e.g., AWS S3, MinIO and R2,
+// requests are signed by AWS Signature Version 4.
+type S3 struct {
+	// Client sends requests, http.DefaultClient is used if nil.
+	Client *http.Client
+
+	endpoint *url.URL
+	// pathStyle puts the bucket in paths instead of hosts, which is
+	// required by most self-hosted services.
+	pathStyle bool
+	bucket    string
+	prefix    string
+	region    string
+
+	accessKey    string
+	secretKey    string
+	sessionToken string
+}
+
+// NewS3 returns a *S3 of the URL:
+//
+//	s3://[access_key:secret_key@]bucket[/prefix][?region=...&endpoint=...]
+//
+// Keys fall back to the AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY and
+// AWS_SESSION_TOKEN environment variables. Custom endpoints, e.g.,
+// `http://minio:9000`, are addressed in the path style.
+func NewS3(rawURL string) (*S3, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Host == "" {
+		return nil, fmt.Errorf("bucket is required: %s", rawURL)
+	}
+	query := u.Query()
+	s := &S3{
+		bucket:       u.Host,
+		prefix:       strings.Trim(u.Path, "/"),
+		region:       query.Get("region"),
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+	}
+	if s.region == "" {
+		s.region = s3DefaultRegion
+	}
+	if u.User != nil {
+		s.accessKey = u.User.Username()
+		s.secretKey, _ = u.User.Password()
+		s.sessionToken = ""
+	}
+	if endpoint := query.Get("endpoint"); endpoint != "" {
+		if s.endpoint, err = url.Parse(endpoint); err != nil || s.endpoint.Host == "" {
+			return nil, fmt.Errorf("invalid s3 endpoint: %s", endpoint)
+		}
+		s.pathStyle = true
+	} else {
+		s.endpoint = &url.URL{Scheme: "https", Host: s.bucket + ".s3." + s.region + ".amazonaws.com"}
+	}
+	return s, nil
+}
+
+// objectURL returns the URL of the object of the name.
+func (s *S3) objectURL(name string) (*url.URL, error) {
+	name, err := cleanName(name)
+	if err != nil {
+		return nil, err
+	}
+	key := name
+	if s.prefix != "" {
+		key = s.prefix + "/" + name
+	}
+	u := *s.endpoint
+	u.Path = "/" + key
+	if s.pathStyle {
+		u.Path = strings.TrimSuffix(s.endpoint.Path, "/") + "/" + s.bucket + "/" + key
+	}
+	// escaped as signed.
+	u.RawPath = s3EscapePath(u.Path)
+	return &u, nil
+}
+
+func (s *S3) do(method, name string, body io.Reader, size int64) (*http.Response, error) {
+	u, err := s.objectURL(name)
+	if err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequest(method, u.String(), body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.ContentLength = size
+	}
+	s.sign(req, time.Now().UTC())
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return client.Do(req)
+}
+
+// sign signs the request with the unsigned payload, which is accepted
+// by S3 compatible services, so that bodies are not read twice.
+func (s *S3) sign(req *http.Request, now time.Time) {
+	amzDate := now.Format(s3TimeFormat)
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", s3UnsignedSHA256)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+	if s.accessKey == "" {
+		return // anonymous.
+	}
+
+	signed := []string{"host", "x-amz-content-sha256", "x-amz-date"}
+	if s.sessionToken != "" {
+		signed = append(signed, "x-amz-security-token")
+	}
+	var headers strings.Builder
+	for _, name := range signed {
+		value := req.Header.Get(name)
+		if name == "host" {
+			value = req.URL.Host
+		}
+		headers.WriteString(name + ":" + strings.TrimSpace(value) + "\n")
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		s3EscapePath(req.URL.Path),
+		req.URL.Query().Encode(),
+		headers.String(),
+		strings.Join(signed, ";"),
+		s3UnsignedSHA256,
+	}, "\n")
+
+	date := amzDate[:8]
+	scope := date + "/" + s.region + "/s3/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex(canonicalRequest)
+	key := hmacSHA256([]byte("AWS4"+s.secretKey), date)
+	for _, v := range []string{s.region, "s3", "aws4_request"} {
+		key = hmacSHA256(key, v)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, scope, strings.Join(signed, ";"),
+		hex.EncodeToString(hmacSHA256(key, stringToSign))))
+}
+
+func (s *S3) Open(name string) (io.ReadCloser, error) {
+	resp, err := s.do(http.MethodGet, name, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err = s3Error(resp); err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Put uploads the object, r is spooled to a temp file first, since the
+// size must be known before uploading.
+func (s *S3) Put(name string, r io.Reader) error {
+	f, err := os.CreateTemp("", "metatube-s3-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+	size, err := io.Copy(f, r)
+	if err != nil {
+		return err
+	}
+	if _, err = f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	resp, err := s.do(http.MethodPut, name, f, size)
+	if err != nil {
+		return err
+	}
+	return s3Error(resp)
+}
+
+func (s *S3) Stat(name string) (*BlobInfo, error) {
+	resp, err := s.do(http.MethodHead, name, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	if err = s3Error(resp); err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	info := &BlobInfo{Name: name}
+	info.Size, _ = strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	info.ModTime, _ = http.ParseTime(resp.Header.Get("Last-Modified"))
+	return info, nil
+}
+
+func (s *S3) Delete(name string) error {
+	resp, err := s.do(http.MethodDelete, name, nil, 0)
+	if err != nil {
+		return err
+	}
+	if err = s3Error(resp); err != nil && err != ErrNotFound {
+		return err
+	}
+	return nil
+}
+
+// s3Error returns the error of the response, the body is closed if any.
+func s3Error(resp *http.Response) error {
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		resp.Body.Close()
+		return ErrNotFound
+	case resp.StatusCode >= http.StatusBadRequest:
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1<<10))
+		return errors.New(resp.StatusCode, fmt.Sprintf("s3: %s: %s",
+			resp.Status, strings.TrimSpace(string(body))))
+	}
+	if resp.Request.Method != http.MethodGet {
+		resp.Body.Close()
+	}
+	return nil
+}
+
+// s3EscapePath escapes the path by the rules of SigV4, in which all
+// characters but the unreserved ones and slashes are escaped.
+func s3EscapePath(path string) string {
+	var b strings.Builder
+	for i := 0; i < len(path); i++ {
+		c := path[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' ||
+			c == '-' || c == '_' || c == '.' || c == '~' || c == '/' {
+			b.WriteByte(c)
+			continue
+		}
+		fmt.Fprintf(&b, "%%%02X", c)
+	}
+	return b.String()
+}
+
+func sha256Hex(s string) string {
+	sum := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
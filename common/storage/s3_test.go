@@ -0,0 +1,62 @@
+package storage
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// s3Server is a minimal S3 of objects by paths, requests are required to
+// be signed by the access key.
+func s3Server(t *testing.T) *httptest.Server {
+	var (
+		mu      sync.Mutex
+		objects = make(map[string][]byte)
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		data, ok := objects[r.URL.Path]
+		switch r.Method {
+		case http.MethodPut:
+			objects[r.URL.Path], _ = io.ReadAll(r.Body)
+		case http.MethodDelete:
+			delete(objects, r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		default:
+			if !ok {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.Header().Set("Last-Modified", time.Now().UTC().Format(http.TimeFormat))
+			http.ServeContent(w, r, "", time.Time{}, strings.NewReader(string(data)))
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestS3(t *testing.T) {
+	srv := s3Server(t)
+	s, err := NewS3("s3://AKID:secret@bucket/prefix?endpoint=" + srv.URL)
+	if !assert.NoError(t, err) {
+		return
+	}
+	testBlob(t, s)
+
+	u, _ := s.objectURL("a b/c.jpg")
+	assert.Equal(t, "/bucket/prefix/a%20b/c.jpg", u.EscapedPath())
+
+	s.accessKey = "wrong"
+	assert.Error(t, s.Put("a.jpg", strings.NewReader("image")))
+}
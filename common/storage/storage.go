@@ -0,0 +1,122 @@
+// Package storage defines the persistence consumed by caches, sessions and
+// artwork downloads, so that integrators can plug in their own stores.
+package storage
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/errors"
+)
+
+var ErrNotFound = errors.New(http.StatusNotFound, "object not found")
+
+// KV is a key-value store of small entries, e.g., cached responses and
+// login sessions. Entries may expire by the TTL of the store.
+type KV interface {
+	// Get returns the value of the key if present and not expired, errors
+	// are misses.
+	Get(key string) ([]byte, bool)
+	// Set stores the value of the key.
+	Set(key string, value []byte) error
+	// Delete removes the key, it's not an error if the key is missing.
+	Delete(key string) error
+}
+
+// BlobInfo is the metadata of a blob.
+type BlobInfo struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Blob is a store of large objects by slash-separated names, e.g.,
+// artwork downloaded, which are streamed rather than held in memory.
+type Blob interface {
+	// Open opens the object of the name, ErrNotFound if missing.
+	Open(name string) (io.ReadCloser, error)
+	// Put writes the object of the name from r, the object is replaced
+	// only if r is read completely.
+	Put(name string, r io.Reader) error
+	// Stat returns the metadata of the object, ErrNotFound if missing.
+	Stat(name string) (*BlobInfo, error)
+	// Delete removes the object, it's not an error if it's missing.
+	Delete(name string) error
+}
+
+// OpenKV opens the key-value store of the URL, entries expire after the
+// TTL if positive:
+//
+//	memory://
+//	file:///path/to/dir
+//	bolt:///path/to/file.db
+//	redis://host:6379/0, see NewRedis
+func OpenKV(rawURL string, ttl time.Duration) (KV, error) {
+	scheme, path, err := splitURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch scheme {
+	case "memory":
+		return NewMemory(ttl), nil
+	case "file":
+		return NewFileKV(path, ttl), nil
+	case "bolt":
+		return OpenBolt(path, ttl)
+	case "redis", "rediss":
+		return NewRedis(rawURL, ttl)
+	}
+	return nil, fmt.Errorf("unsupported key-value store: %s", rawURL)
+}
+
+// OpenBlob opens the blob store of the URL:
+//
+//	memory://
+//	file:///path/to/dir
+//	s3://bucket/prefix?region=...&endpoint=..., see NewS3
+func OpenBlob(rawURL string) (Blob, error) {
+	scheme, path, err := splitURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	switch scheme {
+	case "memory":
+		return NewMemory(0), nil
+	case "file":
+		return NewFileBlob(path), nil
+	case "s3":
+		return NewS3(rawURL)
+	}
+	return nil, fmt.Errorf("unsupported blob store: %s", rawURL)
+}
+
+// splitURL returns the scheme, and the path of file URLs.
+func splitURL(rawURL string) (scheme, path string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", err
+	}
+	scheme = strings.ToLower(u.Scheme)
+	if scheme == "file" || scheme == "bolt" {
+		// relative paths are kept, e.g., `file://data/cache`.
+		if path = u.Host + u.Path; path == "" {
+			return "", "", fmt.Errorf("path is required: %s", rawURL)
+		}
+	}
+	return scheme, path, nil
+}
+
+// cleanName validates the blob name, names escaping the root are invalid.
+func cleanName(name string) (string, error) {
+	name = strings.TrimPrefix(name, "/")
+	for _, elem := range strings.Split(name, "/") {
+		if elem == "" || elem == "." || elem == ".." {
+			return "", fmt.Errorf("invalid blob name: %q", name)
+		}
+	}
+	return name, nil
+}
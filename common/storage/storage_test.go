@@ -0,0 +1,134 @@
+package storage
+
+import (
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func testKV(t *testing.T, kv KV) {
+	_, ok := kv.Get("a")
+	assert.False(t, ok)
+
+	if assert.NoError(t, kv.Set("a", []byte("data"))) {
+		data, ok := kv.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, "data", string(data))
+	}
+	assert.NoError(t, kv.Set("a", []byte("new")))
+	data, _ := kv.Get("a")
+	assert.Equal(t, "new", string(data))
+
+	assert.NoError(t, kv.Delete("a"))
+	_, ok = kv.Get("a")
+	assert.False(t, ok)
+	assert.NoError(t, kv.Delete("missing"))
+}
+
+func testBlob(t *testing.T, b Blob) {
+	_, err := b.Open("a/b.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+	_, err = b.Stat("a/b.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	if assert.NoError(t, b.Put("a/b.jpg", strings.NewReader("image"))) {
+		r, err := b.Open("a/b.jpg")
+		if assert.NoError(t, err) {
+			data, _ := io.ReadAll(r)
+			r.Close()
+			assert.Equal(t, "image", string(data))
+		}
+		info, err := b.Stat("a/b.jpg")
+		if assert.NoError(t, err) {
+			assert.Equal(t, int64(5), info.Size)
+		}
+	}
+	for _, name := range []string{"", "../a", "a//b", "a/./b"} {
+		assert.Error(t, b.Put(name, strings.NewReader("image")), name)
+	}
+
+	assert.NoError(t, b.Delete("a/b.jpg"))
+	_, err = b.Open("a/b.jpg")
+	assert.ErrorIs(t, err, ErrNotFound)
+	assert.NoError(t, b.Delete("a/b.jpg"))
+}
+
+func TestMemory(t *testing.T) {
+	testKV(t, NewMemory(0))
+	testBlob(t, NewMemory(0))
+
+	m := NewMemory(time.Millisecond)
+	_ = m.Set("a", []byte("data"))
+	time.Sleep(2 * time.Millisecond)
+	_, ok := m.Get("a")
+	assert.False(t, ok)
+}
+
+func TestFile(t *testing.T) {
+	testKV(t, NewFileKV(t.TempDir(), time.Hour))
+	testBlob(t, NewFileBlob(t.TempDir()))
+}
+
+func TestBolt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kv.db")
+	b, err := OpenBolt(path, 0)
+	if !assert.NoError(t, err) {
+		return
+	}
+	testKV(t, b)
+
+	// persisted across reopens.
+	_ = b.Set("a", []byte("data"))
+	b.Close()
+	if b, err = OpenBolt(path, time.Hour); assert.NoError(t, err) {
+		defer b.Close()
+		data, ok := b.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, "data", string(data))
+	}
+}
+
+func TestRedis(t *testing.T) {
+	s := miniredis.RunT(t)
+	r, err := NewRedis("redis://"+s.Addr(), 0)
+	if assert.NoError(t, err) {
+		defer r.Close()
+		testKV(t, r)
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	for rawURL, want := range map[string]any{
+		"memory://":                          &Memory{},
+		"file://" + dir:                      &FileKV{},
+		"bolt://" + filepath.Join(dir, "db"): &Bolt{},
+	} {
+		kv, err := OpenKV(rawURL, 0)
+		if assert.NoError(t, err, rawURL) {
+			assert.IsType(t, want, kv, rawURL)
+		}
+		if b, ok := kv.(*Bolt); ok {
+			b.Close()
+		}
+	}
+	for rawURL, want := range map[string]any{
+		"memory://":       &Memory{},
+		"file://" + dir:   &FileBlob{},
+		"s3://bucket/dir": &S3{},
+	} {
+		b, err := OpenBlob(rawURL)
+		if assert.NoError(t, err, rawURL) {
+			assert.IsType(t, want, b, rawURL)
+		}
+	}
+	for _, rawURL := range []string{"", "file://", "ftp://host"} {
+		_, err := OpenKV(rawURL, 0)
+		assert.Error(t, err, rawURL)
+	}
+}
@@ -0,0 +1,161 @@
+// Package studio canonicalizes maker, label and studio strings of providers
+// to studio entities, e.g., `エスワン ナンバーワンスタイル` of FANZA and
+// `S1 NO.1 STYLE` of others are both S1.
+package studio
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/metatube-community/metatube-sdk-go/common/comparer"
+)
+
+// DefaultLanguage is the language of names of studios by default.
+const DefaultLanguage = "ja"
+
+//go:embed studios.json
+var defaultTable []byte
+
+// Table is the serialized form of studio registries.
+type Table struct {
+	Studios []*Studio `json:"studios"`
+}
+
+// Studio is a canonical studio entity.
+type Studio struct {
+	// ID is the unique key of the studio, e.g., `s1`.
+	ID string `json:"id"`
+	// Names are the localized names by lower-case language tags, e.g.,
+	// `ja` and `en`.
+	Names map[string]string `json:"names"`
+	// Aliases are other spellings of the studio by providers.
+	Aliases []string `json:"aliases,omitempty"`
+}
+
+// Name returns the name in the first of the languages localized, matched by
+// base tags, e.g., `en-US` matches `en`, or the one of DefaultLanguage.
+func (s *Studio) Name(langs ...string) string {
+	for _, lang := range langs {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if name, ok := s.Names[lang]; ok {
+			return name
+		}
+		base, _, _ := strings.Cut(lang, "-")
+		if name, ok := s.Names[base]; ok {
+			return name
+		}
+	}
+	if name, ok := s.Names[DefaultLanguage]; ok {
+		return name
+	}
+	return s.ID
+}
+
+// Registry looks up studios by their names and aliases, which are matched
+// regardless of widths, cases, kana scripts, spaces and punctuations.
+type Registry struct {
+	mu    sync.RWMutex
+	index map[string]*Studio
+}
+
+// NewRegistry returns an empty *Registry.
+func NewRegistry() *Registry {
+	return &Registry{index: make(map[string]*Studio)}
+}
+
+func studioKey(name string) string { return comparer.NormalizeName(name) }
+
+// Register registers the studios, which replace those of the same names.
+func (r *Registry) Register(studios ...*Studio) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, s := range studios {
+		for _, name := range s.Names {
+			if key := studioKey(name); key != "" {
+				r.index[key] = s
+			}
+		}
+		for _, alias := range s.Aliases {
+			if key := studioKey(alias); key != "" {
+				r.index[key] = s
+			}
+		}
+	}
+}
+
+// Load registers studios of the table in JSON.
+func (r *Registry) Load(reader io.Reader) error {
+	table := &Table{}
+	if err := json.NewDecoder(reader).Decode(table); err != nil {
+		return fmt.Errorf("invalid studio table: %w", err)
+	}
+	for _, s := range table.Studios {
+		if s.ID == "" {
+			return fmt.Errorf("invalid studio table: studio without id")
+		}
+	}
+	r.Register(table.Studios...)
+	return nil
+}
+
+// Lookup returns the studio of the name.
+func (r *Registry) Lookup(name string) (*Studio, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	s, ok := r.index[studioKey(name)]
+	return s, ok
+}
+
+// Canonicalize returns the name of the studio of the name in the first of
+// the languages, or the name as is if unknown.
+func (r *Registry) Canonicalize(name string, langs ...string) string {
+	if s, ok := r.Lookup(name); ok {
+		return s.Name(langs...)
+	}
+	return name
+}
+
+// Same reports whether the two names are of the same studio, unknown names
+// are the same if they're equal case-insensitively.
+func (r *Registry) Same(a, b string) bool {
+	if strings.EqualFold(a, b) {
+		return true
+	}
+	x, ok := r.Lookup(a)
+	if !ok {
+		return false
+	}
+	y, ok := r.Lookup(b)
+	return ok && x.ID == y.ID
+}
+
+// Default is the registry of the embedded studio table.
+var Default = NewRegistry()
+
+func init() {
+	if err := Default.Load(bytes.NewReader(defaultTable)); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns the studio of the name from the default registry.
+func Lookup(name string) (*Studio, bool) { return Default.Lookup(name) }
+
+// Canonicalize canonicalizes the name by the default registry.
+func Canonicalize(name string, langs ...string) string { return Default.Canonicalize(name, langs...) }
+
+// Same reports whether the names are of the same studio by the default
+// registry.
+func Same(a, b string) bool { return Default.Same(a, b) }
+
+// Register registers the studios into the default registry.
+func Register(studios ...*Studio) { Default.Register(studios...) }
+
+// LoadTable loads user studios in the same JSON format as the embedded
+// default table, and they take precedence over defaults.
+func LoadTable(r io.Reader) error { return Default.Load(r) }
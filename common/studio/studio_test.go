@@ -0,0 +1,43 @@
+package studio
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalize(t *testing.T) {
+	for _, unit := range []struct {
+		name, lang, want string
+	}{
+		{"エスワン ナンバーワンスタイル", "en", "S1 NO.1 STYLE"},
+		{"S1 NO.1 STYLE", "ja", "エスワン ナンバーワンスタイル"},
+		{"ｓ１ ｎｏ．１ ｓｔｙｌｅ", "en-US", "S1 NO.1 STYLE"},
+		{"S1", "", "エスワン ナンバーワンスタイル"},
+		{"MOODYZ", "ja", "ムーディーズ"},
+		{"Unknown Studio", "en", "Unknown Studio"},
+	} {
+		assert.Equal(t, unit.want, Canonicalize(unit.name, unit.lang), unit.name)
+	}
+}
+
+func TestSame(t *testing.T) {
+	assert.True(t, Same("エスワン ナンバーワンスタイル", "S1 NO.1 STYLE"))
+	assert.True(t, Same("Unknown", "unknown"))
+	assert.False(t, Same("S1 NO.1 STYLE", "MOODYZ"))
+	assert.False(t, Same("S1 NO.1 STYLE", "Unknown"))
+}
+
+func TestRegistry_Load(t *testing.T) {
+	r := NewRegistry()
+	assert.NoError(t, r.Load(strings.NewReader(`{"studios": [
+		{"id": "example", "names": {"ja": "エグザンプル", "en": "Example"}, "aliases": ["EXP"]}
+	]}`)))
+	s, ok := r.Lookup("exp")
+	if assert.True(t, ok) {
+		assert.Equal(t, "example", s.ID)
+		assert.Equal(t, "Example", s.Name("fr", "en"))
+	}
+	assert.Error(t, r.Load(strings.NewReader(`{"studios": [{"names": {"en": "X"}}]}`)))
+}
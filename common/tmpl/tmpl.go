@@ -0,0 +1,161 @@
+package tmpl
+
+import (
+	"fmt"
+	"path"
+	"reflect"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/common/parser"
+	"github.com/metatube-community/metatube-sdk-go/common/safepath"
+)
+
+// Funcs are helper functions of templates.
+var Funcs = template.FuncMap{
+	"zeroPad":      ZeroPad,
+	"sanitizePath": SanitizePath,
+	"firstActor":   FirstActor,
+	"yearOf":       YearOf,
+}
+
+// Template is a Go text template of movie or actor infos with helpers,
+// e.g., `{{yearOf .ReleaseDate}}/{{.Number}} {{.Title}}`.
+type Template struct {
+	t *template.Template
+	// segments are templates of path segments split by literal slashes.
+	segments []*template.Template
+}
+
+// New parses the template text.
+func New(name, text string) (*Template, error) {
+	t, err := parse(name, text)
+	if err != nil {
+		return nil, err
+	}
+	tmpl := &Template{t: t}
+	for i, segment := range splitPath(text) {
+		st, err := parse(fmt.Sprintf("%s[%d]", name, i), segment)
+		if err != nil {
+			return nil, err
+		}
+		tmpl.segments = append(tmpl.segments, st)
+	}
+	return tmpl, nil
+}
+
+func parse(name, text string) (*template.Template, error) {
+	return template.New(name).Funcs(Funcs).Option("missingkey=error").Parse(text)
+}
+
+// Execute renders the template with data.
+func (t *Template) Execute(data any) (string, error) {
+	return execute(t.t, data)
+}
+
+func execute(t *template.Template, data any) (string, error) {
+	sb := &strings.Builder{}
+	if err := t.Execute(sb, data); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// ExecutePath renders the template as a slash separated relative path.
+// Only literal slashes of the template separate directories, and each
+// segment is sanitized, so values like titles can't break the layout.
+// Actions must not span segments in path templates.
+func (t *Template) ExecutePath(data any) (string, error) {
+	var segments []string
+	for _, st := range t.segments {
+		s, err := execute(st, data)
+		if err != nil {
+			return "", err
+		}
+		if s = SanitizePath(s); s != "" {
+			segments = append(segments, s)
+		}
+	}
+	if len(segments) == 0 {
+		return "", fmt.Errorf("empty path rendered by template %s", t.t.Name())
+	}
+	return path.Join(segments...), nil
+}
+
+// splitPath splits the template text by slashes outside of actions.
+func splitPath(text string) (segments []string) {
+	var (
+		start int
+		depth int
+	)
+	for i := 0; i < len(text); i++ {
+		switch {
+		case strings.HasPrefix(text[i:], "{{"):
+			depth++
+			i++
+		case strings.HasPrefix(text[i:], "}}") && depth > 0:
+			depth--
+			i++
+		case text[i] == '/' && depth == 0:
+			segments = append(segments, text[start:i])
+			start = i + 1
+		}
+	}
+	return append(segments, text[start:])
+}
+
+// ZeroPad pads the number with leading zeros to the width.
+func ZeroPad(v any, width int) string {
+	s := strings.TrimSpace(fmt.Sprint(v))
+	if _, err := strconv.Atoi(s); err != nil || len(s) >= width {
+		return s
+	}
+	return strings.Repeat("0", width-len(s)) + s
+}
+
+// SanitizePath makes s a safe path segment on common file systems, see
+// safepath.Segment.
+func SanitizePath(s string) string {
+	return safepath.Segment(s)
+}
+
+// FirstActor returns the first actor of the actors, or empty if none.
+func FirstActor(actors any) string {
+	switch v := actors.(type) {
+	case pq.StringArray:
+		return FirstActor([]string(v))
+	case []string:
+		if len(v) > 0 {
+			return v[0]
+		}
+	case string:
+		return v
+	}
+	return ""
+}
+
+// YearOf returns the year of the date, or 0 if unknown.
+func YearOf(date any) int {
+	var t time.Time
+	switch v := date.(type) {
+	case datatypes.Date:
+		t = time.Time(v)
+	case time.Time:
+		t = v
+	case string:
+		t = time.Time(parser.ParseDate(v))
+	default:
+		if rv := reflect.ValueOf(date); rv.Kind() == reflect.Ptr && !rv.IsNil() {
+			return YearOf(rv.Elem().Interface())
+		}
+	}
+	if t.IsZero() {
+		return 0
+	}
+	return t.Year()
+}
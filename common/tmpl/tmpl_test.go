@@ -0,0 +1,44 @@
+package tmpl
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func TestTemplate_ExecutePath(t *testing.T) {
+	info := &model.MovieInfo{
+		Number:      "ABP-030",
+		Title:       `Title: "A/B" test.`,
+		Actors:      []string{"三上悠亜", "河北彩花"},
+		ReleaseDate: datatypes.Date(time.Date(2013, 6, 1, 0, 0, 0, 0, time.UTC)),
+	}
+	for _, unit := range []struct {
+		text, want string
+	}{
+		{`{{firstActor .Actors}}/{{yearOf .ReleaseDate}}/{{.Number}} {{.Title}}`, `三上悠亜/2013/ABP-030 Title_ _A_B_ test`},
+		{`{{.Number}}-{{zeroPad 7 3}}`, `ABP-030-007`},
+		{`../{{.Number}}`, `ABP-030`},
+	} {
+		tmpl, err := New("test", unit.text)
+		if assert.NoError(t, err) {
+			s, err := tmpl.ExecutePath(info)
+			assert.NoError(t, err)
+			assert.Equal(t, unit.want, s)
+		}
+	}
+}
+
+func TestHelpers(t *testing.T) {
+	assert.Equal(t, "007", ZeroPad(7, 3))
+	assert.Equal(t, "1234", ZeroPad("1234", 3))
+	assert.Equal(t, "abc", ZeroPad("abc", 5))
+	assert.Equal(t, "", FirstActor([]string{}))
+	assert.Equal(t, 2020, YearOf("2020-02-01"))
+	assert.Equal(t, 0, YearOf(datatypes.Date{}))
+	assert.Equal(t, "a_b", SanitizePath("a:b"))
+}
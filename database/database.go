@@ -33,6 +33,9 @@ type Config struct {
 
 	// Max DB idle connections.
 	MaxIdleConns int
+
+	// Log SQL without parameter values.
+	ParameterizedQueries bool
 }
 
 func Open(cfg *Config) (db *gorm.DB, err error) {
@@ -66,6 +69,7 @@ func Open(cfg *Config) (db *gorm.DB, err error) {
 			LogLevel:                  logger.Info,
 			IgnoreRecordNotFoundError: false,
 			Colorful:                  false,
+			ParameterizedQueries:      cfg.ParameterizedQueries,
 		}),
 		NowFunc: func() time.Time {
 			return time.Now().UTC()
@@ -0,0 +1,140 @@
+// Package migrate applies versioned schema migrations on top of the GORM
+// auto migration, which only creates tables and adds missing columns, so
+// that destructive or data changes can be applied and reverted in order.
+package migrate
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TableName is the name of the table recording applied migrations.
+const TableName = "schema_migrations"
+
+// Migration is a reversible schema change.
+type Migration struct {
+	// ID is the unique version of the migration, migrations are applied
+	// in the lexical order of IDs, e.g., `20240101000000_add_column`.
+	ID string
+	// Up applies the change.
+	Up func(tx *gorm.DB) error
+	// Down reverts the change.
+	Down func(tx *gorm.DB) error
+}
+
+// Status is the applied state of a migration.
+type Status struct {
+	ID        string     `json:"id"`
+	AppliedAt *time.Time `json:"applied_at"`
+}
+
+type record struct {
+	ID        string `gorm:"primaryKey"`
+	AppliedAt time.Time
+}
+
+func (*record) TableName() string {
+	return TableName
+}
+
+// Migrator applies migrations to the DB.
+type Migrator struct {
+	db         *gorm.DB
+	migrations []*Migration
+}
+
+// New returns a *Migrator of the migrations.
+func New(db *gorm.DB, migrations ...*Migration) *Migrator {
+	migrations = append([]*Migration(nil), migrations...)
+	sort.SliceStable(migrations, func(i, j int) bool {
+		return migrations[i].ID < migrations[j].ID
+	})
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Up applies all pending migrations in order, each in a transaction, and
+// returns IDs of the applied ones.
+func (m *Migrator) Up() (ids []string, err error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.ID]; ok {
+			continue
+		}
+		if err = m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Up(tx); err != nil {
+				return err
+			}
+			return tx.Create(&record{ID: migration.ID, AppliedAt: time.Now().UTC()}).Error
+		}); err != nil {
+			return ids, fmt.Errorf("migrate up %s: %w", migration.ID, err)
+		}
+		ids = append(ids, migration.ID)
+	}
+	return
+}
+
+// Down reverts the last n applied migrations in reverse order, and
+// returns IDs of the reverted ones.
+func (m *Migrator) Down(n int) (ids []string, err error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	for i := len(m.migrations) - 1; i >= 0 && len(ids) < n; i-- {
+		migration := m.migrations[i]
+		if _, ok := applied[migration.ID]; !ok {
+			continue
+		}
+		if migration.Down == nil {
+			return ids, fmt.Errorf("migrate down %s: irreversible", migration.ID)
+		}
+		if err = m.db.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Down(tx); err != nil {
+				return err
+			}
+			return tx.Delete(&record{ID: migration.ID}).Error
+		}); err != nil {
+			return ids, fmt.Errorf("migrate down %s: %w", migration.ID, err)
+		}
+		ids = append(ids, migration.ID)
+	}
+	return
+}
+
+// Status returns the applied states of all migrations in order.
+func (m *Migrator) Status() ([]*Status, error) {
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	status := make([]*Status, 0, len(m.migrations))
+	for _, migration := range m.migrations {
+		s := &Status{ID: migration.ID}
+		if t, ok := applied[migration.ID]; ok {
+			s.AppliedAt = &t
+		}
+		status = append(status, s)
+	}
+	return status, nil
+}
+
+func (m *Migrator) applied() (map[string]time.Time, error) {
+	if err := m.db.AutoMigrate(&record{}); err != nil {
+		return nil, err
+	}
+	var records []*record
+	if err := m.db.Find(&records).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[string]time.Time, len(records))
+	for _, r := range records {
+		applied[r.ID] = r.AppliedAt
+	}
+	return applied, nil
+}
@@ -0,0 +1,78 @@
+package migrate
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/gorm"
+
+	"github.com/metatube-community/metatube-sdk-go/database"
+)
+
+type item struct {
+	ID   string `gorm:"primaryKey"`
+	Name string
+}
+
+func TestMigrator(t *testing.T) {
+	db, err := database.Open(&database.Config{
+		DSN:                  "file::memory:",
+		MaxOpenConns:         1,
+		DisableAutomaticPing: true,
+	})
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	migrations := []*Migration{
+		{
+			ID:   "0002_add_name",
+			Up:   func(tx *gorm.DB) error { return tx.Migrator().AddColumn(&item{}, "Name") },
+			Down: func(tx *gorm.DB) error { return tx.Migrator().DropColumn(&item{}, "Name") },
+		},
+		{
+			ID: "0001_create_items",
+			Up: func(tx *gorm.DB) error {
+				return tx.Exec("CREATE TABLE items (id TEXT PRIMARY KEY)").Error
+			},
+			Down: func(tx *gorm.DB) error { return tx.Migrator().DropTable(&item{}) },
+		},
+	}
+	m := New(db, migrations...)
+
+	ids, err := m.Up()
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"0001_create_items", "0002_add_name"}, ids)
+		assert.True(t, db.Migrator().HasColumn(&item{}, "Name"))
+	}
+
+	// already applied.
+	ids, err = m.Up()
+	assert.NoError(t, err)
+	assert.Empty(t, ids)
+
+	ids, err = m.Down(1)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"0002_add_name"}, ids)
+		assert.False(t, db.Migrator().HasColumn(&item{}, "Name"))
+		assert.True(t, db.Migrator().HasTable(&item{}))
+	}
+
+	status, err := m.Status()
+	if assert.NoError(t, err) && assert.Len(t, status, 2) {
+		assert.NotNil(t, status[0].AppliedAt)
+		assert.Nil(t, status[1].AppliedAt)
+	}
+
+	// failed migration is rolled back and not recorded.
+	m = New(db, append(migrations, &Migration{
+		ID: "0003_broken",
+		Up: func(tx *gorm.DB) error { return errors.New("broken") },
+	})...)
+	ids, err = m.Up()
+	assert.Error(t, err)
+	assert.Equal(t, []string{"0002_add_name"}, ids)
+	status, _ = m.Status()
+	assert.Nil(t, status[2].AppliedAt)
+}
@@ -0,0 +1,197 @@
+package discover
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+)
+
+// DefaultMaxDepth is the max depth of nested sitemap indexes followed.
+const DefaultMaxDepth = 3
+
+// checkpointInterval is the number of entries handled between saves of
+// the checkpoint.
+const checkpointInterval = 100
+
+// Crawler crawls feeds and their nested sitemaps for entries. Fetches of
+// feeds are rate-limited by the interval, and the progress is persisted
+// to the checkpoint file if set, so that interrupted crawls are resumed
+// rather than restarted. Remove the checkpoint file to crawl afresh.
+type Crawler struct {
+	// Fetch fetches the feed of the URL.
+	Fetch func(url string) (*http.Response, error)
+	// Interval is the min interval between fetches.
+	Interval time.Duration
+	// Checkpoint is the file of the progress, not persisted if empty.
+	Checkpoint string
+	// MaxDepth limits nested sitemap indexes, DefaultMaxDepth if zero.
+	MaxDepth int
+
+	state     *checkpoint
+	lastFetch time.Time
+	unsaved   int
+}
+
+// checkpoint is the progress of crawls, which is bounded by the number of
+// feeds rather than entries. Feeds are done when all their entries and
+// nested sitemaps are handled, and feeds in progress are resumed from
+// the offsets of their entries, since feeds list entries in stable order.
+type checkpoint struct {
+	Feeds   map[string]time.Time `json:"feeds"`
+	Offsets map[string]int       `json:"offsets,omitempty"`
+}
+
+// Crawl crawls the feeds in order, and calls fn with each entry not
+// handled yet, entries listed by several feeds are handled for each of
+// them. The crawl stops on the first error of fn, fetches or
+// the context, with the progress saved.
+func (c *Crawler) Crawl(ctx context.Context, urls []string, fn func(*Entry) error) (err error) {
+	if err = c.load(); err != nil {
+		return err
+	}
+	defer func() {
+		if saveErr := c.save(); err == nil {
+			err = saveErr
+		}
+	}()
+	maxDepth := c.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = DefaultMaxDepth
+	}
+	for _, u := range urls {
+		if err = c.crawl(ctx, u, maxDepth, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (c *Crawler) crawl(ctx context.Context, url string, depth int, fn func(*Entry) error) error {
+	if _, ok := c.state.Feeds[url]; ok {
+		return nil // done.
+	}
+	if err := c.wait(ctx); err != nil {
+		return err
+	}
+	// entries are handled as they're scanned, so that memory is bounded
+	// regardless of the size of the feed, and only nested sitemaps are
+	// queued until the feed is scanned.
+	var (
+		i        int
+		sitemaps []string
+		fnErr    error
+	)
+	err := c.scan(url, func(entry *Entry) error {
+		defer func() { i++ }()
+		if i < c.state.Offsets[url] {
+			return nil // handled before interrupted.
+		}
+		if fnErr = ctx.Err(); fnErr != nil {
+			return fnErr
+		}
+		if fnErr = fn(entry); fnErr != nil {
+			return fnErr
+		}
+		c.state.Offsets[url] = i + 1
+		if c.unsaved++; c.unsaved >= checkpointInterval {
+			if fnErr = c.save(); fnErr != nil {
+				return fnErr
+			}
+		}
+		return nil
+	}, func(sitemap string) error {
+		if depth <= 1 {
+			fnErr = fmt.Errorf("sitemaps of %s nested too deep", url)
+			return fnErr
+		}
+		sitemaps = append(sitemaps, sitemap)
+		return nil
+	})
+	if fnErr != nil {
+		return fnErr
+	} else if err != nil {
+		return fmt.Errorf("fetch %s: %w", url, err)
+	}
+	for _, sitemap := range sitemaps {
+		if err = c.crawl(ctx, sitemap, depth-1, fn); err != nil {
+			return err
+		}
+	}
+	c.state.Feeds[url] = time.Now().UTC()
+	delete(c.state.Offsets, url)
+	return c.save()
+}
+
+// scan fetches the feed of the URL, and streams it to the callbacks.
+func (c *Crawler) scan(url string, onEntry func(*Entry) error, onSitemap func(string) error) error {
+	resp, err := c.Fetch(url)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("status %s", resp.Status)
+	}
+	return ScanFeed(resp.Body, onEntry, onSitemap)
+}
+
+// wait waits for the interval since the last fetch.
+func (c *Crawler) wait(ctx context.Context) error {
+	if wait := c.Interval - time.Since(c.lastFetch); wait > 0 {
+		timer := time.NewTimer(wait)
+		defer timer.Stop()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-timer.C:
+		}
+	}
+	c.lastFetch = time.Now()
+	return nil
+}
+
+func (c *Crawler) load() error {
+	c.state = &checkpoint{
+		Feeds:   make(map[string]time.Time),
+		Offsets: make(map[string]int),
+	}
+	if c.Checkpoint == "" {
+		return nil
+	}
+	data, err := os.ReadFile(c.Checkpoint)
+	if os.IsNotExist(err) {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if err = json.Unmarshal(data, c.state); err != nil {
+		return fmt.Errorf("invalid checkpoint: %w", err)
+	}
+	if c.state.Feeds == nil {
+		c.state.Feeds = make(map[string]time.Time)
+	}
+	if c.state.Offsets == nil {
+		c.state.Offsets = make(map[string]int)
+	}
+	return nil
+}
+
+func (c *Crawler) save() error {
+	c.unsaved = 0
+	if c.Checkpoint == "" || c.state == nil {
+		return nil
+	}
+	data, err := json.Marshal(c.state)
+	if err != nil {
+		return err
+	}
+	// written atomically, so that checkpoints are never corrupted.
+	tmp := c.Checkpoint + ".tmp"
+	if err = os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.Checkpoint)
+}
@@ -0,0 +1,142 @@
+package discover
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"testing/iotest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCrawler(t *testing.T) {
+	var fetches atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fetches.Add(1)
+		switch r.URL.Path {
+		case "/index.xml":
+			fmt.Fprintf(w, `<sitemapindex><sitemap><loc>http://%s/a.xml</loc></sitemap><sitemap><loc>http://%[1]s/b.xml</loc></sitemap></sitemapindex>`, r.Host)
+		case "/a.xml":
+			fmt.Fprint(w, `<urlset><url><loc>https://example.com/1</loc></url><url><loc>https://example.com/2</loc></url></urlset>`)
+		case "/b.xml":
+			fmt.Fprint(w, `<urlset><url><loc>https://example.com/3</loc></url><url><loc>https://example.com/1</loc></url></urlset>`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+	newCrawler := func() *Crawler {
+		return &Crawler{
+			Fetch:      http.Get,
+			Interval:   10 * time.Millisecond,
+			Checkpoint: checkpoint,
+		}
+	}
+
+	// interrupted by the entry 3.
+	errStop := errors.New("stop")
+	var urls []string
+	err := newCrawler().Crawl(context.Background(), []string{srv.URL + "/index.xml"}, func(e *Entry) error {
+		if e.URL == "https://example.com/3" {
+			return errStop
+		}
+		urls = append(urls, e.URL)
+		return nil
+	})
+	assert.ErrorIs(t, err, errStop)
+	assert.Equal(t, []string{"https://example.com/1", "https://example.com/2"}, urls)
+
+	// resumed from the entry 3, the sitemap a is not fetched again, and
+	// entries are handled for each sitemap listing them.
+	urls = nil
+	fetches.Store(0)
+	start := time.Now()
+	err = newCrawler().Crawl(context.Background(), []string{srv.URL + "/index.xml"}, func(e *Entry) error {
+		urls = append(urls, e.URL)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/3", "https://example.com/1"}, urls)
+	assert.Equal(t, int32(2), fetches.Load())
+	assert.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond, "rate-limited")
+
+	// progress is kept by feeds instead of entries.
+	data, err := os.ReadFile(checkpoint)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "example.com")
+
+	// done.
+	err = newCrawler().Crawl(context.Background(), []string{srv.URL + "/index.xml"}, func(e *Entry) error {
+		t.Errorf("unexpected entry: %s", e.URL)
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// not found.
+	err = (&Crawler{Fetch: http.Get}).Crawl(context.Background(), []string{srv.URL + "/missing.xml"}, func(*Entry) error { return nil })
+	assert.Error(t, err)
+}
+
+func TestCrawler_Offset(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, "https://example.com/1\nhttps://example.com/2\nhttps://example.com/1\nhttps://example.com/3\n")
+	}))
+	defer srv.Close()
+
+	checkpoint := filepath.Join(t.TempDir(), "checkpoint.json")
+	crawl := func(stop string) (urls []string, err error) {
+		err = (&Crawler{Fetch: http.Get, Checkpoint: checkpoint}).Crawl(context.Background(),
+			[]string{srv.URL + "/sitemap.txt"}, func(e *Entry) error {
+				if e.URL == stop {
+					return errors.New("stop")
+				}
+				urls = append(urls, e.URL)
+				return nil
+			})
+		return
+	}
+
+	// interrupted by the entry 3, after the duplicate of entry 1.
+	urls, err := crawl("https://example.com/3")
+	assert.Error(t, err)
+	assert.Equal(t, []string{"https://example.com/1", "https://example.com/2", "https://example.com/1"}, urls)
+
+	// resumed from the offset of the entry 3.
+	urls, err = crawl("")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com/3"}, urls)
+}
+
+func TestCrawler_Stream(t *testing.T) {
+	errBroken := errors.New("broken")
+	c := &Crawler{Fetch: func(string) (*http.Response, error) {
+		return &http.Response{
+			Status:     "200 OK",
+			StatusCode: http.StatusOK,
+			Body: io.NopCloser(io.MultiReader(
+				strings.NewReader("https://example.com/1\n"),
+				iotest.ErrReader(errBroken))),
+		}, nil
+	}}
+
+	// entries are handled as they're scanned, before the feed is read.
+	var urls []string
+	err := c.Crawl(context.Background(), []string{"https://example.com/sitemap.txt"}, func(e *Entry) error {
+		urls = append(urls, e.URL)
+		return nil
+	})
+	assert.ErrorIs(t, err, errBroken)
+	assert.Equal(t, []string{"https://example.com/1"}, urls)
+}
@@ -0,0 +1,232 @@
+// Package discover enumerates movie pages of providers by their sitemaps
+// and RSS/Atom feeds, e.g., to pre-warm the cache or build a local index.
+package discover
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Entry is a page listed by feeds.
+type Entry struct {
+	URL string `json:"url"`
+	// Modified is the last modified time if listed.
+	Modified time.Time `json:"modified,omitempty"`
+}
+
+// Feed is a parsed sitemap, sitemap index, RSS or Atom feed.
+type Feed struct {
+	Entries []*Entry
+	// Sitemaps are nested sitemaps of sitemap indexes.
+	Sitemaps []string
+}
+
+type sitemapURL struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod"`
+}
+
+type rssItem struct {
+	Link    string `xml:"link"`
+	PubDate string `xml:"pubDate"`
+}
+
+type atomEntry struct {
+	Links []struct {
+		Href string `xml:"href,attr"`
+		Rel  string `xml:"rel,attr"`
+	} `xml:"link"`
+	Updated string `xml:"updated"`
+}
+
+// ParseFeed parses the feed, which is gzipped or not. Sitemaps of plain
+// texts, i.e., one URL per line, are also supported.
+func ParseFeed(r io.Reader) (*Feed, error) {
+	feed := &Feed{}
+	err := ScanFeed(r, func(entry *Entry) error {
+		feed.Entries = append(feed.Entries, entry)
+		return nil
+	}, func(sitemap string) error {
+		feed.Sitemaps = append(feed.Sitemaps, sitemap)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return feed, nil
+}
+
+// ScanFeed parses the feed like ParseFeed, but streams entries and nested
+// sitemaps to the callbacks as they're decoded rather than collecting them,
+// so that memory is bounded by the largest item instead of the whole feed,
+// e.g., of sitemaps listing millions of pages. Scans stop on the first
+// error of callbacks.
+func ScanFeed(r io.Reader, onEntry func(*Entry) error, onSitemap func(string) error) error {
+	br := bufio.NewReader(r)
+	if magic, _ := br.Peek(2); bytes.Equal(magic, []byte{0x1f, 0x8b}) {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return err
+		}
+		defer gr.Close()
+		br = bufio.NewReader(gr)
+	}
+	first, err := firstByte(br)
+	if err != nil {
+		return err
+	}
+	if first != '<' {
+		return scanText(br, onEntry)
+	}
+	return scanXML(br, onEntry, onSitemap)
+}
+
+// firstByte peeks the first non-space byte, leading spaces are discarded.
+func firstByte(br *bufio.Reader) (byte, error) {
+	for {
+		b, err := br.Peek(1)
+		if err != nil {
+			return 0, err
+		}
+		if !unicode.IsSpace(rune(b[0])) {
+			return b[0], nil
+		}
+		_, _ = br.ReadByte()
+	}
+}
+
+func scanText(br *bufio.Reader, onEntry func(*Entry) error) error {
+	for {
+		line, err := br.ReadString('\n')
+		if line = strings.TrimSpace(line); strings.HasPrefix(line, "http") {
+			if cbErr := emit(onEntry, line, time.Time{}); cbErr != nil {
+				return cbErr
+			}
+		}
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return err
+		}
+	}
+}
+
+// scanXML walks tokens of the feed, and only decodes the items, i.e.,
+// `url` and `sitemap` of sitemaps, `item` of RSS and `entry` of Atom, as
+// elements of their own. Formats are distinguished by the name of the
+// root element.
+func scanXML(r io.Reader, onEntry func(*Entry) error, onSitemap func(string) error) error {
+	d := xml.NewDecoder(r)
+	var root string
+	for depth := 0; ; {
+		token, err := d.Token()
+		if err == io.EOF {
+			if root == "" {
+				return io.ErrUnexpectedEOF
+			}
+			return nil
+		} else if err != nil {
+			return err
+		}
+		switch t := token.(type) {
+		case xml.EndElement:
+			if depth--; depth == 0 {
+				return nil // end of root.
+			}
+		case xml.StartElement:
+			if depth == 0 {
+				root = t.Name.Local
+				switch root {
+				case "urlset", "sitemapindex", "rss", "feed":
+				default:
+					return fmt.Errorf("unsupported feed: %s", root)
+				}
+				depth++
+				continue
+			}
+			if err = scanItem(d, &t, root, depth, onEntry, onSitemap); err == errNotItem {
+				depth++
+			} else if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+var errNotItem = errors.New("not an item")
+
+// scanItem decodes the element if it's an item of the root, or returns
+// errNotItem, so that the walk goes into the element.
+func scanItem(d *xml.Decoder, start *xml.StartElement, root string, depth int,
+	onEntry func(*Entry) error, onSitemap func(string) error,
+) error {
+	switch name := start.Name.Local; {
+	case root == "urlset" && name == "url" && depth == 1:
+		var u sitemapURL
+		if err := d.DecodeElement(&u, start); err != nil {
+			return err
+		}
+		return emit(onEntry, u.Loc, parseTime(u.LastMod))
+	case root == "sitemapindex" && name == "sitemap" && depth == 1:
+		var s sitemapURL
+		if err := d.DecodeElement(&s, start); err != nil {
+			return err
+		}
+		if loc := strings.TrimSpace(s.Loc); loc != "" {
+			return onSitemap(loc)
+		}
+		return nil
+	case root == "rss" && name == "item" && depth == 2:
+		var item rssItem
+		if err := d.DecodeElement(&item, start); err != nil {
+			return err
+		}
+		return emit(onEntry, item.Link, parseTime(item.PubDate))
+	case root == "feed" && name == "entry" && depth == 1:
+		var entry atomEntry
+		if err := d.DecodeElement(&entry, start); err != nil {
+			return err
+		}
+		for _, link := range entry.Links {
+			if link.Rel == "" || link.Rel == "alternate" {
+				return emit(onEntry, link.Href, parseTime(entry.Updated))
+			}
+		}
+		return nil
+	case root == "rss" && name == "channel" && depth == 1:
+		return errNotItem
+	}
+	return d.Skip() // neither items nor their parents.
+}
+
+func emit(onEntry func(*Entry) error, url string, modified time.Time) error {
+	if url = strings.TrimSpace(url); url != "" {
+		return onEntry(&Entry{URL: url, Modified: modified})
+	}
+	return nil
+}
+
+// parseTime parses times of W3C datetime, RFC 1123 and RFC 3339, zero if
+// invalid.
+func parseTime(s string) time.Time {
+	s = strings.TrimSpace(s)
+	for _, layout := range []string{
+		time.RFC3339,
+		time.DateOnly,
+		time.RFC1123Z,
+		time.RFC1123,
+	} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
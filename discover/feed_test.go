@@ -0,0 +1,131 @@
+package discover
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFeed(t *testing.T) {
+	for _, unit := range []struct {
+		name     string
+		data     string
+		entries  []string
+		sitemaps []string
+		modified time.Time
+	}{
+		{
+			name: "sitemap",
+			data: `<?xml version="1.0" encoding="UTF-8"?>
+<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <url><loc>https://example.com/movie/1</loc><lastmod>2024-03-05</lastmod></url>
+  <url><loc> https://example.com/movie/2 </loc></url>
+</urlset>`,
+			entries:  []string{"https://example.com/movie/1", "https://example.com/movie/2"},
+			modified: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "sitemap index",
+			data: `<sitemapindex xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">
+  <sitemap><loc>https://example.com/sitemap-1.xml</loc></sitemap>
+</sitemapindex>`,
+			sitemaps: []string{"https://example.com/sitemap-1.xml"},
+		},
+		{
+			name: "rss",
+			data: `<rss version="2.0"><channel><title>New</title>
+  <item><link>https://example.com/movie/3</link><pubDate>Tue, 05 Mar 2024 00:00:00 +0000</pubDate></item>
+</channel></rss>`,
+			entries:  []string{"https://example.com/movie/3"},
+			modified: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "atom",
+			data: `<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry><link rel="self" href="https://example.com/self"/><link href="https://example.com/movie/4"/><updated>2024-03-05T00:00:00Z</updated></entry>
+</feed>`,
+			entries:  []string{"https://example.com/movie/4"},
+			modified: time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "text",
+			data:    "https://example.com/movie/5\n\nhttps://example.com/movie/6\n",
+			entries: []string{"https://example.com/movie/5", "https://example.com/movie/6"},
+		},
+	} {
+		t.Run(unit.name, func(t *testing.T) {
+			feed, err := ParseFeed(strings.NewReader(unit.data))
+			require.NoError(t, err)
+			var entries []string
+			for _, entry := range feed.Entries {
+				entries = append(entries, entry.URL)
+			}
+			assert.Equal(t, unit.entries, entries)
+			assert.Equal(t, unit.sitemaps, feed.Sitemaps)
+			if len(feed.Entries) > 0 {
+				assert.True(t, unit.modified.Equal(feed.Entries[0].Modified))
+			}
+		})
+	}
+
+	_, err := ParseFeed(strings.NewReader(`<html></html>`))
+	assert.Error(t, err)
+}
+
+func TestParseFeed_Gzip(t *testing.T) {
+	buf := &bytes.Buffer{}
+	w := gzip.NewWriter(buf)
+	_, _ = w.Write([]byte(`<urlset><url><loc>https://example.com/movie/1</loc></url></urlset>`))
+	require.NoError(t, w.Close())
+
+	feed, err := ParseFeed(buf)
+	require.NoError(t, err)
+	if assert.Len(t, feed.Entries, 1) {
+		assert.Equal(t, "https://example.com/movie/1", feed.Entries[0].URL)
+	}
+}
+
+func TestScanFeed(t *testing.T) {
+	pr, pw := io.Pipe()
+	go func() {
+		_, _ = io.WriteString(pw, `<urlset xmlns="http://www.sitemaps.org/schemas/sitemap/0.9">`)
+		for i := 0; i < 10000; i++ {
+			_, _ = fmt.Fprintf(pw, `<url><loc>https://example.com/movie/%d</loc></url>`, i)
+		}
+		_, _ = io.WriteString(pw, `</urlset>`)
+		_ = pw.Close()
+	}()
+	var n int
+	err := ScanFeed(pr, func(entry *Entry) error {
+		n++
+		return nil
+	}, func(string) error { return nil })
+	require.NoError(t, err)
+	assert.Equal(t, 10000, n)
+
+	// stopped by callbacks.
+	stop := errors.New("stop")
+	n = 0
+	err = ScanFeed(strings.NewReader(`<rss><channel>
+  <item><link>https://example.com/movie/1</link></item>
+  <item><link>https://example.com/movie/2</link></item>
+</channel></rss>`), func(*Entry) error {
+		n++
+		return stop
+	}, nil)
+	assert.ErrorIs(t, err, stop)
+	assert.Equal(t, 1, n)
+
+	// truncated feeds.
+	err = ScanFeed(strings.NewReader(`<urlset><url><loc>https://example.com/movie/1</loc>`),
+		func(*Entry) error { return nil }, nil)
+	assert.Error(t, err)
+}
@@ -1,9 +1,12 @@
 package engine
 
 import (
+	"context"
+	goerr "errors"
 	"fmt"
 	"sort"
 	"sync"
+	"time"
 
 	"gorm.io/gorm/clause"
 
@@ -32,12 +35,12 @@ func (e *Engine) searchActorFromDB(keyword string, provider mt.Provider) (result
 	return
 }
 
-func (e *Engine) searchActor(keyword string, provider mt.Provider, fallback bool) ([]*model.ActorSearchResult, error) {
+func (e *Engine) searchActor(ctx context.Context, keyword string, provider mt.Provider, fallback bool) ([]*model.ActorSearchResult, error) {
 	innerSearch := func(keyword string) (results []*model.ActorSearchResult, err error) {
 		if provider.Name() == gfriends.Name {
 			return provider.(mt.ActorSearcher).SearchActor(keyword)
 		}
-		if searcher, ok := provider.(mt.ActorSearcher); ok {
+		if _, ok := provider.(mt.ActorSearcher); ok {
 			defer func() {
 				if err != nil || len(results) == 0 {
 					return // ignore error or empty.
@@ -45,7 +48,7 @@ func (e *Engine) searchActor(keyword string, provider mt.Provider, fallback bool
 				const minSimilarity = 0.3
 				ps := new(priority.Slice[float64, *model.ActorSearchResult])
 				for _, result := range results {
-					if similarity := comparer.Compare(result.Name, keyword); similarity >= minSimilarity {
+					if similarity := comparer.CompareName(result.Name, keyword); similarity >= minSimilarity {
 						ps.Append(similarity, result)
 					}
 				}
@@ -68,7 +71,12 @@ func (e *Engine) searchActor(keyword string, provider mt.Provider, fallback bool
 					}
 				}()
 			}
-			return searcher.SearchActor(keyword)
+			instance, release, err := e.acquireActorProviderContext(ctx, provider.(mt.ActorProvider))
+			if err != nil {
+				return nil, mt.ErrProviderTimeout
+			}
+			defer release()
+			return instance.(mt.ActorSearcher).SearchActor(keyword)
 		}
 		// All providers should implement ActorSearcher interface.
 		return nil, mt.ErrInfoNotFound
@@ -106,7 +114,14 @@ func (e *Engine) SearchActor(keyword, name string, fallback bool) ([]*model.Acto
 	if err != nil {
 		return nil, err
 	}
-	return e.searchActor(keyword, provider, fallback)
+	results, err := e.searchActor(context.Background(), keyword, provider, fallback)
+	if err != nil {
+		return nil, err
+	}
+	if results = e.filterActorResults(results); len(results) == 0 {
+		return nil, mt.ErrInfoNotFound
+	}
+	return results, nil
 }
 
 func (e *Engine) SearchActorAll(keyword string, fallback bool) (results []*model.ActorSearchResult, err error) {
@@ -114,11 +129,20 @@ func (e *Engine) SearchActorAll(keyword string, fallback bool) (results []*model
 		mu sync.Mutex
 		wg sync.WaitGroup
 	)
+	maxPriority := e.maxActorProviderPriority()
 	for _, provider := range e.actorProviders {
 		wg.Add(1)
 		go func(provider mt.ActorProvider) {
 			defer wg.Done()
-			if innerResults, innerErr := e.searchActor(keyword, provider, fallback); innerErr == nil {
+			startTime := time.Now()
+			e.emitProvider(model.ActorKind, keyword, provider.Name(), time.Time{}, 0, nil)
+			timeout := providerTimeout(e.searchDeadline, e.actorProviderPriority(provider), maxPriority)
+			innerResults, innerErr := withTimeout(timeout, func(ctx context.Context) ([]*model.ActorSearchResult, error) {
+				return e.searchActor(ctx, keyword, provider, fallback)
+			})
+			e.tuner.record(model.ActorKind, provider.Name(), innerErr, time.Since(startTime), -1)
+			e.emitProvider(model.ActorKind, keyword, provider.Name(), startTime, len(innerResults), innerErr)
+			if innerErr == nil {
 				for _, result := range innerResults {
 					if result.Valid() /* validation check */ {
 						mu.Lock()
@@ -131,9 +155,10 @@ func (e *Engine) SearchActorAll(keyword string, fallback bool) (results []*model
 	}
 	wg.Wait()
 
+	results = e.filterActorResults(results)
 	sort.SliceStable(results, func(i, j int) bool {
-		return e.MustGetActorProviderByName(results[i].Provider).Priority() >
-			e.MustGetActorProviderByName(results[j].Provider).Priority()
+		return e.actorProviderPriority(e.MustGetActorProviderByName(results[i].Provider)) >
+			e.actorProviderPriority(e.MustGetActorProviderByName(results[j].Provider))
 	})
 	return
 }
@@ -157,10 +182,41 @@ func (e *Engine) getActorInfoWithCallback(provider mt.ActorProvider, id string,
 	if provider.Name() == gfriends.Name {
 		return provider.GetActorInfoByID(id)
 	}
+	defer func() {
+		// manual override injection.
+		if err == nil && info != nil {
+			e.applyOverride(model.ActorKind, info.Provider, info.ID, info)
+		}
+	}()
+	defer func() {
+		// post-processing of returned info.
+		if err == nil && info != nil {
+			if info.Source == nil { // stored before attributed.
+				info.Attribute(DisplayName(provider), info.UpdatedAt)
+			}
+			// stored before canonicalized.
+			info.Homepage = canonicalHomepage(provider, info.Homepage)
+			for _, process := range e.actorProcessors {
+				process(info)
+			}
+		}
+	}()
+	defer func() {
+		// content filtering of returned info.
+		if err == nil && info != nil {
+			err = e.filterActorInfo(info)
+		}
+	}()
 	defer func() {
 		// actor image injection.
 		if err == nil && info != nil {
 			if gInfo, gErr := e.MustGetActorProviderByName(gfriends.Name).GetActorInfoByID(info.Name); gErr == nil && len(gInfo.Images) > 0 {
+				if info.ImageSources == nil {
+					info.ImageSources = make(map[string]string)
+				}
+				for _, image := range gInfo.Images {
+					info.ImageSources[image] = gInfo.Provider
+				}
 				info.Images = append(gInfo.Images, info.Images...)
 			}
 		}
@@ -168,19 +224,37 @@ func (e *Engine) getActorInfoWithCallback(provider mt.ActorProvider, id string,
 	// Query DB first (by id).
 	if lazy {
 		if info, err = e.getActorInfoFromDB(provider, id); err == nil && info.Valid() {
+			e.recordCacheHit()
 			return
 		}
 	}
 	// Delayed info auto-save.
 	defer func() {
-		if err == nil && info.Valid() {
+		if err == nil && info.Valid() && !e.isBlocked(info.Provider) &&
+			e.filterActorInfo(info) == nil /* not filtered out */ {
 			// Make sure we save the original info here.
 			e.db.Clauses(clause.OnConflict{
 				UpdateAll: true,
 			}).Create(info) // ignore error
 		}
 	}()
-	return callback()
+	startTime := time.Now()
+	e.emitProvider(model.ActorKind, id, provider.Name(), time.Time{}, 0, nil)
+	info, err = callback()
+	if err != nil && goerr.Is(err, mt.ErrInfoNotFound) {
+		err = mt.ErrInfoNotFound // e.g., wrapped by transports of soft 404 pages.
+	} else if err == nil && info != nil && e.isMissingImage(provider, firstImage(info.Images)) {
+		info, err = nil, mt.ErrInfoNotFound
+	}
+	if err == nil && info != nil {
+		info.Homepage = canonicalHomepage(provider, info.Homepage)
+		info.Attribute(DisplayName(provider), startTime)
+		e.emitFields(model.ActorKind, id, provider.Name(), info)
+	}
+	e.emitProvider(model.ActorKind, id, provider.Name(), startTime, 1, err)
+	e.recordFetch(lazy, provider.Name(), err)
+	e.tuner.record(model.ActorKind, provider.Name(), err, time.Since(startTime), -1)
+	return
 }
 
 func (e *Engine) getActorInfoByProviderID(provider mt.ActorProvider, id string, lazy bool) (*model.ActorInfo, error) {
@@ -188,7 +262,13 @@ func (e *Engine) getActorInfoByProviderID(provider mt.ActorProvider, id string,
 		return nil, mt.ErrInvalidID
 	}
 	return e.getActorInfoWithCallback(provider, id, lazy, func() (*model.ActorInfo, error) {
-		return provider.GetActorInfoByID(id)
+		instance, release := e.acquireActorProvider(provider)
+		defer release()
+		info, err := instance.GetActorInfoByID(id)
+		if err == nil && info != nil {
+			flagArchived(instance, info.Homepage, info)
+		}
+		return info, err
 	})
 }
 
@@ -209,7 +289,13 @@ func (e *Engine) getActorInfoByProviderURL(provider mt.ActorProvider, rawURL str
 		return nil, mt.ErrInvalidURL
 	}
 	return e.getActorInfoWithCallback(provider, id, lazy, func() (*model.ActorInfo, error) {
-		return provider.GetActorInfoByURL(rawURL)
+		instance, release := e.acquireActorProvider(provider)
+		defer release()
+		info, err := instance.GetActorInfoByURL(rawURL)
+		if err == nil && info != nil {
+			flagArchived(instance, info.Homepage, info)
+		}
+		return info, err
 	})
 }
 
@@ -220,3 +306,27 @@ func (e *Engine) GetActorInfoByURL(rawURL string, lazy bool) (*model.ActorInfo,
 	}
 	return e.getActorInfoByProviderURL(provider, rawURL, lazy)
 }
+
+// GetLocalizedActorInfoByProviderID gets actor's info with the name and aliases
+// localized in lang. Localized infos are neither read from nor saved to DB, as
+// they would be mixed up with the default ones. Providers that don't support
+// localization fall back to the default info.
+func (e *Engine) GetLocalizedActorInfoByProviderID(name, id, lang string, lazy bool) (*model.ActorInfo, error) {
+	provider, err := e.GetActorProviderByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := provider.(mt.ActorLocalizer); !ok || lang == "" {
+		return e.getActorInfoByProviderID(provider, id, lazy)
+	}
+	if id = provider.NormalizeActorID(id); id == "" {
+		return nil, mt.ErrInvalidID
+	}
+	instance, release := e.acquireActorProvider(provider)
+	defer release()
+	info, err := instance.(mt.ActorLocalizer).GetLocalizedActorInfoByID(id, lang)
+	if err == nil && (info == nil || !info.Valid()) {
+		err = mt.ErrIncompleteMetadata
+	}
+	return info, err
+}
@@ -0,0 +1,59 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+	"github.com/metatube-community/metatube-sdk-go/provider/gfriends"
+)
+
+// localizedFake is a fake actor provider of names suffixed by languages.
+type localizedFake struct{ *fake.Fake }
+
+func (f localizedFake) GetLocalizedActorInfoByID(id, lang string) (*model.ActorInfo, error) {
+	info, err := f.GetActorInfoByID(id)
+	if err == nil {
+		info.OriginalName, info.Name = info.Name, info.Name+" ("+lang+")"
+	}
+	return info, err
+}
+
+func TestEngine_GetLocalizedActorInfoByProviderID(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.actorProviders = map[string]mt.ActorProvider{
+		"GFRIENDS": &galleryFake{Fake: fake.New(), name: gfriends.Name},
+		"FAKE":     localizedFake{fake.New()},
+		"PLAIN":    &benchProvider{Fake: fake.New(), name: "Plain"},
+	}
+
+	info, err := e.GetLocalizedActorInfoByProviderID(fake.Name, "1", "en", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Fake Actor 1 (en)", info.Name)
+	assert.Equal(t, "Fake Actor 1", info.OriginalName)
+
+	// localized infos are not saved, nor read from DB.
+	info, err = e.GetActorInfoByProviderID(fake.Name, "1", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Fake Actor 1", info.Name)
+	info, err = e.GetLocalizedActorInfoByProviderID(fake.Name, "1", "ja", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Fake Actor 1 (ja)", info.Name)
+
+	// fall back to the default info.
+	info, err = e.GetLocalizedActorInfoByProviderID(fake.Name, "1", "", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Fake Actor 1", info.Name)
+	info, err = e.GetLocalizedActorInfoByProviderID("Plain", "2", "en", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Fake Actor 2", info.Name)
+
+	_, err = e.GetLocalizedActorInfoByProviderID(fake.Name, "", "en", true)
+	assert.Equal(t, mt.ErrInvalidID, err)
+	_, err = e.GetLocalizedActorInfoByProviderID(fake.Name, "0", "en", true)
+	assert.Equal(t, mt.ErrInfoNotFound, err)
+}
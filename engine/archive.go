@@ -0,0 +1,17 @@
+package engine
+
+import (
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// flagArchived flags the info as archived if its homepage has been fetched
+// from the Internet Archive by the provider instance.
+func flagArchived(instance any, homepage string, info interface{ SetArchiveURL(string) }) {
+	a, ok := instance.(mt.ArchiveFallback)
+	if !ok {
+		return
+	}
+	if snapshot, ok := a.ArchivedURL(homepage); ok {
+		info.SetArchiveURL(snapshot)
+	}
+}
@@ -0,0 +1,279 @@
+package engine
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+const defaultAuditLogLimit = 100
+
+// AuditQuery filters audit logs, zero values match all.
+type AuditQuery struct {
+	Action   string    `form:"action"`
+	Actor    string    `form:"actor"`
+	Kind     string    `form:"kind"`
+	Provider string    `form:"provider"`
+	ID       string    `form:"id"`
+	Since    time.Time `form:"since" time_format:"2006-01-02T15:04:05Z07:00"`
+	Limit    int       `form:"limit"`
+}
+
+// Auditor performs changes of stored metadata on behalf of the actor,
+// and records them with diffs in the audit logs. Changes made by the
+// engine itself are recorded as of model.SystemAuditActor.
+type Auditor struct {
+	e     *Engine
+	actor string
+}
+
+// Auditor returns an *Auditor of the actor, e.g., the token label.
+func (e *Engine) Auditor(actor string) *Auditor {
+	return &Auditor{e: e, actor: actor}
+}
+
+// systemAuditor returns the *Auditor of changes made by the engine itself.
+func (e *Engine) systemAuditor() *Auditor {
+	return e.Auditor(model.SystemAuditActor)
+}
+
+func (a *Auditor) GetMovieInfoByProviderID(name, id string, lazy bool) (*model.MovieInfo, error) {
+	provider, err := a.e.GetMovieProviderByName(name)
+	if err != nil {
+		return nil, err
+	}
+	old, _ := a.e.getMovieInfoFromDB(provider, provider.NormalizeMovieID(id))
+	info, err := a.e.GetMovieInfoByProviderID(name, id, lazy)
+	if err != nil {
+		return nil, err
+	}
+	if cur, err := a.e.getMovieInfoFromDB(provider, info.ID); err == nil {
+		if !old.Valid() {
+			old = nil
+		}
+		a.recordScrape(model.MovieKind, info.Provider, info.ID, old != nil, lazy,
+			auditChanges(model.DiffMovieInfo(old, cur)))
+	}
+	return info, nil
+}
+
+func (a *Auditor) GetActorInfoByProviderID(name, id string, lazy bool) (*model.ActorInfo, error) {
+	provider, err := a.e.GetActorProviderByName(name)
+	if err != nil {
+		return nil, err
+	}
+	old, _ := a.e.getActorInfoFromDB(provider, provider.NormalizeActorID(id))
+	info, err := a.e.GetActorInfoByProviderID(name, id, lazy)
+	if err != nil {
+		return nil, err
+	}
+	if cur, err := a.e.getActorInfoFromDB(provider, info.ID); err == nil {
+		var prev any
+		if old.Valid() {
+			prev = old
+		}
+		a.recordScrape(model.ActorKind, info.Provider, info.ID, prev != nil, lazy, diff(prev, cur))
+	}
+	return info, nil
+}
+
+// GetLocalizedMovieInfoByProviderID records localized infos scraped as
+// localized, or audits as GetMovieInfoByProviderID if not localized.
+func (a *Auditor) GetLocalizedMovieInfoByProviderID(name, id, lang string, lazy bool) (*model.MovieInfo, error) {
+	provider, err := a.e.GetMovieProviderByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := provider.(mt.MovieLocalizer); !ok || lang == "" {
+		return a.GetMovieInfoByProviderID(name, id, lazy)
+	}
+	info, err := a.e.GetLocalizedMovieInfoByProviderID(name, id, lang, lazy)
+	if err != nil {
+		return nil, err
+	}
+	stored, _ := a.e.getMovieInfoFromDB(provider, info.ID)
+	if !stored.Valid() {
+		stored = nil
+	}
+	_ = a.record(model.LocalizeAuditAction, model.MovieKind, info.Provider, info.ID,
+		auditChanges(model.DiffMovieInfo(stored, info)))
+	return info, nil
+}
+
+// GetLocalizedActorInfoByProviderID records localized infos scraped as
+// localized, or audits as GetActorInfoByProviderID if not localized.
+func (a *Auditor) GetLocalizedActorInfoByProviderID(name, id, lang string, lazy bool) (*model.ActorInfo, error) {
+	provider, err := a.e.GetActorProviderByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := provider.(mt.ActorLocalizer); !ok || lang == "" {
+		return a.GetActorInfoByProviderID(name, id, lazy)
+	}
+	info, err := a.e.GetLocalizedActorInfoByProviderID(name, id, lang, lazy)
+	if err != nil {
+		return nil, err
+	}
+	var prev any
+	if stored, _ := a.e.getActorInfoFromDB(provider, info.ID); stored.Valid() {
+		prev = stored
+	}
+	_ = a.record(model.LocalizeAuditAction, model.ActorKind, info.Provider, info.ID, diff(prev, info))
+	return info, nil
+}
+
+func (a *Auditor) GetActorInfoByName(name string, lazy bool) (*model.ActorInfo, error) {
+	best, err := a.e.bestActorMatch(name)
+	if err != nil {
+		return nil, err
+	}
+	return a.GetActorInfoByProviderID(best.Provider, best.ID, lazy)
+}
+
+func (a *Auditor) DeleteMovieInfo(name, id string) error {
+	provider, err := a.e.GetMovieProviderByName(name)
+	if err != nil {
+		return err
+	}
+	if err = a.e.DeleteMovieInfo(name, id); err != nil {
+		return err
+	}
+	return a.record(model.DeleteAuditAction, model.MovieKind, provider.Name(), id, nil)
+}
+
+func (a *Auditor) DeleteActorInfo(name, id string) error {
+	provider, err := a.e.GetActorProviderByName(name)
+	if err != nil {
+		return err
+	}
+	if err = a.e.DeleteActorInfo(name, id); err != nil {
+		return err
+	}
+	return a.record(model.DeleteAuditAction, model.ActorKind, provider.Name(), id, nil)
+}
+
+func (a *Auditor) AddBlocklistEntry(entry *model.BlocklistEntry) error {
+	if err := a.e.AddBlocklistEntry(entry); err != nil {
+		return err
+	}
+	return a.record(model.BlockAuditAction, model.BlocklistKind, entry.Provider, entry.Number, nil)
+}
+
+func (a *Auditor) RemoveBlocklistEntry(provider, number string) error {
+	if err := a.e.RemoveBlocklistEntry(provider, number); err != nil {
+		return err
+	}
+	return a.record(model.UnblockAuditAction, model.BlocklistKind, provider, number, nil)
+}
+
+func (a *Auditor) SetOverride(kind, name, id string, fields map[string]json.RawMessage) (*model.Override, error) {
+	old, _ := a.e.GetOverride(kind, name, id)
+	o, err := a.e.SetOverride(kind, name, id, fields)
+	if err != nil {
+		return nil, err
+	}
+	return o, a.record(model.EditAuditAction, kind, o.Provider, o.ID, diff(overrideFields(old), overrideFields(o)))
+}
+
+func (a *Auditor) ClearOverride(kind, name, id string, fields ...string) error {
+	old, err := a.e.GetOverride(kind, name, id)
+	if err != nil {
+		return err
+	}
+	if err = a.e.ClearOverride(kind, name, id, fields...); err != nil {
+		return err
+	}
+	cur, _ := a.e.GetOverride(kind, name, id)
+	return a.record(model.ClearAuditAction, kind, old.Provider, old.ID, diff(overrideFields(old), overrideFields(cur)))
+}
+
+// recordScrape records the scrape if the info is newly stored, or the
+// refresh if it's refetched explicitly.
+func (a *Auditor) recordScrape(kind, provider, id string, existed, lazy bool, changes map[string]*model.AuditChange) {
+	switch {
+	case !existed:
+		_ = a.record(model.ScrapeAuditAction, kind, provider, id, changes)
+	case !lazy:
+		_ = a.record(model.RefreshAuditAction, kind, provider, id, changes)
+	}
+}
+
+func (a *Auditor) record(action, kind, provider, id string, changes map[string]*model.AuditChange) error {
+	return a.e.db.Create(&model.AuditLog{
+		Action:   action,
+		Actor:    a.actor,
+		Kind:     kind,
+		Provider: provider,
+		ID:       id,
+		Diff:     changes,
+	}).Error
+}
+
+// GetAuditLogs returns audit logs matched in reverse chronological order.
+func (e *Engine) GetAuditLogs(query *AuditQuery) (logs []*model.AuditLog, err error) {
+	tx := e.db.Where(&model.AuditLog{
+		Action:   query.Action,
+		Actor:    query.Actor,
+		Kind:     query.Kind,
+		Provider: query.Provider,
+		ID:       query.ID,
+	})
+	if !query.Since.IsZero() {
+		tx = tx.Where("created_at >= ?", query.Since.UTC())
+	}
+	limit := query.Limit
+	if limit <= 0 {
+		limit = defaultAuditLogLimit
+	}
+	err = tx.Order("seq DESC").Limit(limit).Find(&logs).Error
+	return
+}
+
+// diff returns changed fields of JSON representations, a nil old value
+// means all fields of the new are added.
+func diff(old, cur any) map[string]*model.AuditChange {
+	oldFields, curFields := jsonFields(old), jsonFields(cur)
+	changes := make(map[string]*model.AuditChange)
+	for k, v := range curFields {
+		if o, ok := oldFields[k]; !ok || !reflect.DeepEqual(o, v) {
+			changes[k] = &model.AuditChange{Old: o, New: v}
+		}
+	}
+	for k, o := range oldFields {
+		if _, ok := curFields[k]; !ok {
+			changes[k] = &model.AuditChange{Old: o}
+		}
+	}
+	return changes
+}
+
+// auditChanges converts field changes to audit changes.
+func auditChanges(changes []*model.FieldChange) map[string]*model.AuditChange {
+	m := make(map[string]*model.AuditChange, len(changes))
+	for _, c := range changes {
+		m[c.Field] = &model.AuditChange{Old: c.Old, New: c.New}
+	}
+	return m
+}
+
+func overrideFields(o *model.Override) any {
+	if o == nil {
+		return nil
+	}
+	return &o.Fields
+}
+
+func jsonFields(v any) (fields map[string]any) {
+	if v == nil || reflect.ValueOf(v).IsNil() {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil
+	}
+	_ = json.Unmarshal(data, &fields)
+	return
+}
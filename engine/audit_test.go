@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+	"github.com/metatube-community/metatube-sdk-go/provider/gfriends"
+)
+
+// genresFake is a fake provider of which extra genres are added to infos,
+// so that infos are changed upstream between scrapes.
+type genresFake struct {
+	*fake.Fake
+	genres []string
+}
+
+func (f *genresFake) GetMovieInfoByID(id string) (*model.MovieInfo, error) {
+	info, err := f.Fake.GetMovieInfoByID(id)
+	if err == nil {
+		info.Genres = append(info.Genres, f.genres...)
+	}
+	return info, err
+}
+
+func systemAuditLogs(t *testing.T, e *Engine) (actions []string) {
+	logs, err := e.GetAuditLogs(&AuditQuery{Actor: model.SystemAuditActor})
+	require.NoError(t, err)
+	for i := len(logs) - 1; i >= 0; i-- {
+		assert.Equal(t, fake.Name, logs[i].Provider)
+		assert.Equal(t, "FAKE-001", logs[i].ID)
+		actions = append(actions, logs[i].Action)
+	}
+	return
+}
+
+func TestEngine_SystemAudit(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	f := &genresFake{Fake: fake.New()}
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": f}
+
+	// watchlist checks scrape and refresh infos.
+	require.NoError(t, e.AddWatchlistEntry(&model.WatchlistEntry{Kind: model.MovieKind, Name: "FAKE-001"}))
+	assert.Equal(t, []string{model.ScrapeAuditAction}, systemAuditLogs(t, e))
+	require.NoError(t, e.CheckWatchlist())
+	assert.Equal(t, []string{model.ScrapeAuditAction, model.RefreshAuditAction}, systemAuditLogs(t, e))
+
+	// overrides are merged with upstream changes.
+	user := e.Auditor("user")
+	_, err := user.SetOverride(model.MovieKind, "fake", "FAKE-001", map[string]json.RawMessage{
+		"genres": json.RawMessage(`["Fake","Mine"]`),
+	})
+	require.NoError(t, err)
+	f.genres = []string{"Upstream"}
+	_, err = user.GetMovieInfoByProviderID("fake", "FAKE-001", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{model.ScrapeAuditAction, model.RefreshAuditAction, model.MergeAuditAction}, systemAuditLogs(t, e))
+
+	logs, err := e.GetAuditLogs(&AuditQuery{Action: model.MergeAuditAction})
+	require.NoError(t, err)
+	if assert.Len(t, logs, 1) && assert.Contains(t, logs[0].Diff, "genres") {
+		assert.Contains(t, logs[0].Diff["genres"].New, "Upstream")
+		assert.Contains(t, logs[0].Diff["genres"].New, "Mine")
+	}
+	// the user is the actor of changes requested.
+	logs, err = e.GetAuditLogs(&AuditQuery{Actor: "user"})
+	require.NoError(t, err)
+	if assert.Len(t, logs, 2) {
+		assert.Equal(t, model.RefreshAuditAction, logs[0].Action)
+		assert.Equal(t, model.EditAuditAction, logs[1].Action)
+	}
+}
+
+func TestAuditor_Localized(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": localizedMovieFake{fake.New()}}
+	e.actorProviders = map[string]mt.ActorProvider{
+		"GFRIENDS": &galleryFake{Fake: fake.New(), name: gfriends.Name},
+		"FAKE":     localizedFake{fake.New()},
+	}
+	user := e.Auditor("user")
+
+	_, err := user.GetLocalizedMovieInfoByProviderID("fake", "FAKE-001", "", true)
+	require.NoError(t, err)
+	_, err = user.GetLocalizedMovieInfoByProviderID("fake", "FAKE-001", "en", true)
+	require.NoError(t, err)
+	_, err = user.GetLocalizedActorInfoByProviderID("fake", "1", "en", true)
+	require.NoError(t, err)
+
+	logs, err := e.GetAuditLogs(&AuditQuery{Actor: "user"})
+	require.NoError(t, err)
+	if assert.Len(t, logs, 3) {
+		assert.Equal(t, model.LocalizeAuditAction, logs[0].Action)
+		assert.Equal(t, model.ActorKind, logs[0].Kind)
+		assert.Equal(t, "Fake Actor 1 (en)", logs[0].Diff["name"].New)
+		// changes from the stored info.
+		assert.Equal(t, model.LocalizeAuditAction, logs[1].Action)
+		assert.Equal(t, model.MovieKind, logs[1].Kind)
+		assert.Contains(t, logs[1].Diff, "title")
+		assert.NotContains(t, logs[1].Diff, "number")
+		assert.Equal(t, model.ScrapeAuditAction, logs[2].Action)
+	}
+}
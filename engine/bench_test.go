@@ -0,0 +1,83 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+
+	"github.com/metatube-community/metatube-sdk-go/internal/race"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+// benchProvider is a fake provider renamed, so that the engine fans out
+// to many providers without network access.
+type benchProvider struct {
+	*fake.Fake
+	name string
+}
+
+func (p *benchProvider) Name() string { return p.name }
+
+func (p *benchProvider) SearchMovie(keyword string) ([]*model.MovieSearchResult, error) {
+	results, err := p.Fake.SearchMovie(keyword)
+	for _, result := range results {
+		result.Provider = p.name
+	}
+	return results, err
+}
+
+// newBenchEngine returns an engine of n fake providers only.
+func newBenchEngine(tb testing.TB, n int) *Engine {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	if err != nil {
+		tb.Fatal(err)
+	}
+	e := New(db, time.Minute)
+	if err = e.AutoMigrate(true); err != nil {
+		tb.Fatal(err)
+	}
+	e.logger = zap.NewNop().Sugar()
+	e.movieProviders = make(map[string]mt.MovieProvider)
+	for i := 0; i < n; i++ {
+		name := fmt.Sprintf("Bench%d", i)
+		e.movieProviders[strings.ToUpper(name)] = &benchProvider{Fake: fake.New(), name: name}
+	}
+	return e
+}
+
+func BenchmarkSearchMovieAll(b *testing.B) {
+	e := newBenchEngine(b, 8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := e.SearchMovieAll(fmt.Sprintf("FAKE-%03d", i%999+1), false); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Allocation budget of searching all providers, raise it only with a
+// reason: ~35 by merging and sorting, plus ~60 by each provider searched.
+func searchAllocBudget(providers int) float64 { return float64(64 + 80*providers) }
+
+func TestAllocBudgets(t *testing.T) {
+	if race.Enabled {
+		t.Skip("allocations are inflated by the race detector")
+	}
+	for _, n := range []int{1, 8} {
+		e := newBenchEngine(t, n)
+		if allocs := testing.AllocsPerRun(20, func() {
+			_, _ = e.SearchMovieAll("FAKE-001", false)
+		}); allocs > searchAllocBudget(n) {
+			t.Errorf("SearchMovieAll of %d providers allocs %.0f > budget %.0f", n, allocs, searchAllocBudget(n))
+		}
+	}
+}
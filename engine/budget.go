@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"context"
+	"time"
+
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// Min share of the deadline given to each provider, the rest of the
+// deadline is given in proportion to the priority of the provider.
+const minProviderTimeoutShare = 0.5
+
+// providerTimeout returns the sub-timeout of a provider derived from its
+// priority, so that providers of higher priority are given more time, but
+// none of them exceeds the total deadline.
+func providerTimeout(deadline time.Duration, priority, maxPriority int) time.Duration {
+	share := minProviderTimeoutShare
+	if priority > 0 && maxPriority > 0 {
+		share += (1 - minProviderTimeoutShare) * min(float64(priority)/float64(maxPriority), 1)
+	}
+	return time.Duration(float64(deadline) * share)
+}
+
+// withTimeout runs fn with a context canceled when timeout exceeds, and
+// stops waiting for fn then. fn should pass the context to the provider,
+// e.g., by acquireMovieProviderContext, so that its requests are canceled
+// too, otherwise fn keeps running, and holds its provider instance, until
+// the requests finish. The timeout is disabled if timeout <= 0.
+func withTimeout[T any](timeout time.Duration, fn func(ctx context.Context) (T, error)) (T, error) {
+	if timeout <= 0 {
+		return fn(context.Background())
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	type result struct {
+		v   T
+		err error
+	}
+	// buffered, so the goroutine won't leak after timeout.
+	ch := make(chan result, 1)
+	go func() {
+		v, err := fn(ctx)
+		ch <- result{v, err}
+	}()
+	select {
+	case r := <-ch:
+		return r.v, r.err
+	case <-ctx.Done():
+		var zero T
+		return zero, mt.ErrProviderTimeout
+	}
+}
+
+func (e *Engine) maxMovieProviderPriority() (p int) {
+	for _, provider := range e.movieProviders {
+		p = max(p, e.movieProviderPriority(provider))
+	}
+	return
+}
+
+func (e *Engine) maxActorProviderPriority() (p int) {
+	for _, provider := range e.actorProviders {
+		p = max(p, e.actorProviderPriority(provider))
+	}
+	return
+}
@@ -0,0 +1,132 @@
+package engine
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+func TestProviderTimeout(t *testing.T) {
+	for _, unit := range []struct {
+		priority, maxPriority int
+		want                  time.Duration
+	}{
+		{0, 0, 5 * time.Second},
+		{0, 10, 5 * time.Second},
+		{5, 10, 7500 * time.Millisecond},
+		{10, 10, 10 * time.Second},
+		{20, 10, 10 * time.Second}, // never exceeds the deadline.
+		{-1, 10, 5 * time.Second},
+	} {
+		assert.Equal(t, unit.want, providerTimeout(10*time.Second, unit.priority, unit.maxPriority),
+			"priority %d of %d", unit.priority, unit.maxPriority)
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	var canceled atomic.Bool
+	slow := func(ctx context.Context) (int, error) {
+		select {
+		case <-time.After(100 * time.Millisecond):
+			return 1, nil
+		case <-ctx.Done():
+			canceled.Store(true)
+			return 0, ctx.Err()
+		}
+	}
+
+	v, err := withTimeout(time.Second, slow)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+
+	v, err = withTimeout(10*time.Millisecond, slow)
+	assert.Equal(t, mt.ErrProviderTimeout, err)
+	assert.Zero(t, v)
+	// fn is canceled instead of left running.
+	assert.Eventually(t, canceled.Load, time.Second, time.Millisecond)
+
+	// disabled.
+	v, err = withTimeout(0, slow)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, v)
+}
+
+// slowProvider is a ranked fake provider searching slowly.
+type slowProvider struct {
+	*rankedProvider
+	delay time.Duration
+}
+
+func (p *slowProvider) SearchMovie(keyword string) ([]*model.MovieSearchResult, error) {
+	time.Sleep(p.delay)
+	return p.rankedProvider.SearchMovie(keyword)
+}
+
+func TestEngine_SearchDeadline(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.searchDeadline = 200 * time.Millisecond
+	e.movieProviders = map[string]mt.MovieProvider{
+		"FAST": &rankedProvider{&benchProvider{Fake: fake.New(), name: "Fast"}, 10},
+		// given 55% of the deadline by its priority.
+		"SLOW": &slowProvider{&rankedProvider{&benchProvider{Fake: fake.New(), name: "Slow"}, 1}, time.Second},
+	}
+
+	start := time.Now()
+	responses := make(map[string]*MovieSearchResponse)
+	for resp := range e.searchMovieSeq(context.Background(), "FAKE-001") {
+		responses[resp.Provider.Name()] = resp
+	}
+	assert.Less(t, time.Since(start), e.searchDeadline)
+
+	require.Contains(t, responses, "Fast")
+	assert.NoError(t, responses["Fast"].Error)
+	assert.NotEmpty(t, responses["Fast"].Results)
+	require.Contains(t, responses, "Slow")
+	assert.Equal(t, mt.ErrProviderTimeout, responses["Slow"].Error)
+}
+
+// contextProvider searches until its context is done, if set.
+type contextProvider struct {
+	*benchProvider
+	ctx context.Context
+}
+
+func (p *contextProvider) SetContext(ctx context.Context) { p.ctx = ctx }
+
+func (p *contextProvider) SearchMovie(keyword string) ([]*model.MovieSearchResult, error) {
+	if p.ctx == nil {
+		return p.benchProvider.SearchMovie(keyword)
+	}
+	<-p.ctx.Done()
+	return nil, p.ctx.Err()
+}
+
+func TestEngine_SearchDeadlineReleasesPooled(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.poolSize, e.searchDeadline = 2, 50*time.Millisecond
+	e.movieProviders = map[string]mt.MovieProvider{
+		"CONTEXT": &contextProvider{benchProvider: &benchProvider{Fake: fake.New(), name: "Context"}},
+	}
+	pooled := &contextProvider{benchProvider: &benchProvider{Fake: fake.New(), name: "Context"}}
+	pool := newProviderPool(1, func() (mt.MovieProvider, error) { return pooled, nil })
+	e.pools.movie["CONTEXT"] = pool
+
+	for resp := range e.searchMovieSeq(context.Background(), "FAKE-001") {
+		assert.Equal(t, mt.ErrProviderTimeout, resp.Error)
+	}
+	// the pooled instance is canceled and released soon after.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	instance, err := pool.GetContext(ctx)
+	require.NoError(t, err)
+	assert.Same(t, pooled, instance)
+	assert.Nil(t, pooled.ctx)
+}
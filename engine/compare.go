@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"net/http"
+	"reflect"
+	"strings"
+	"sync"
+
+	"github.com/metatube-community/metatube-sdk-go/common/number"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+var ErrNoComparableInfos = errors.New(http.StatusNotFound, "no comparable infos")
+
+// MovieComparison compares infos of the same movie from providers.
+type MovieComparison struct {
+	Number string `json:"number"`
+	// Providers in the order of search ranking.
+	Providers []string           `json:"providers"`
+	Fields    []*FieldComparison `json:"fields"`
+}
+
+// FieldComparison is the values of a field by provider names.
+type FieldComparison struct {
+	Field  string         `json:"field"`
+	Values map[string]any `json:"values"`
+	// Agreed reports whether all providers have the same value.
+	Agreed bool `json:"agreed"`
+}
+
+// CompareMovieInfos fetches the movie of the number from all capable
+// providers, and compares their infos field by field.
+func (e *Engine) CompareMovieInfos(keyword string) (*MovieComparison, error) {
+	results, err := e.SearchMovieAll(keyword, false)
+	if err != nil {
+		return nil, err
+	}
+
+	// pick the best matched result of each provider.
+	var (
+		canonical = number.Canonicalize("", number.Trim(keyword))
+		picked    []*model.MovieSearchResult
+		seen      = make(map[string]struct{})
+	)
+	for _, result := range results {
+		if _, ok := seen[result.Provider]; ok ||
+			!strings.EqualFold(number.Canonicalize(result.Provider, result.Number), canonical) {
+			continue
+		}
+		seen[result.Provider] = struct{}{}
+		picked = append(picked, result)
+	}
+
+	infos := make([]*model.MovieInfo, len(picked))
+	var wg sync.WaitGroup
+	for i, result := range picked {
+		wg.Add(1)
+		go func(i int, result *model.MovieSearchResult) {
+			defer wg.Done()
+			infos[i], _ = e.GetMovieInfoByProviderID(result.Provider, result.ID, true)
+		}(i, result)
+	}
+	wg.Wait()
+
+	c := &MovieComparison{Number: canonical}
+	fetched := infos[:0]
+	for _, info := range infos {
+		if info != nil /* skip failed */ {
+			c.Providers = append(c.Providers, info.Provider)
+			fetched = append(fetched, info)
+		}
+	}
+	if len(fetched) == 0 {
+		return nil, ErrNoComparableInfos
+	}
+	c.Fields = compareFields(fetched)
+	return c, nil
+}
+
+// compareFields compares infos by JSON fields in the struct order.
+func compareFields(infos []*model.MovieInfo) (fields []*FieldComparison) {
+	// fields differ from the first info.
+	differed := make(map[string]struct{})
+	for _, info := range infos[1:] {
+		for _, change := range model.DiffMovieInfo(infos[0], info) {
+			differed[change.Field] = struct{}{}
+		}
+	}
+	t := reflect.TypeOf(model.MovieInfo{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous || !f.IsExported() || name == "-" ||
+			name == "id" || name == "provider" ||
+			f.Tag.Get("gorm") == "-" /* not stored */ {
+			continue
+		}
+		fc := &FieldComparison{Field: name, Values: make(map[string]any, len(infos))}
+		for _, info := range infos {
+			fc.Values[info.Provider] = reflect.ValueOf(info).Elem().Field(i).Interface()
+		}
+		_, ok := differed[name]
+		fc.Agreed = !ok
+		fields = append(fields, fc)
+	}
+	return
+}
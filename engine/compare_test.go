@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+// compareProvider is a ranked provider of which infos are retitled, or
+// fail to be fetched.
+type compareProvider struct {
+	*rankedProvider
+	title string
+	fail  bool
+}
+
+func (p *compareProvider) GetMovieInfoByID(id string) (*model.MovieInfo, error) {
+	if p.fail {
+		return nil, mt.ErrInfoNotFound
+	}
+	info, err := p.Fake.GetMovieInfoByID(id)
+	if err != nil {
+		return nil, err
+	}
+	info.Provider = p.name
+	if p.title != "" {
+		info.Title = p.title
+	}
+	return info, nil
+}
+
+func newCompareProvider(name string, priority int, title string, fail bool) *compareProvider {
+	return &compareProvider{
+		rankedProvider: &rankedProvider{&benchProvider{Fake: fake.New(), name: name}, priority},
+		title:          title,
+		fail:           fail,
+	}
+}
+
+func TestEngine_CompareMovieInfos(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{
+		"A": newCompareProvider("A", 30, "", false),
+		"B": newCompareProvider("B", 20, "", false),
+		"C": newCompareProvider("C", 10, "Other Title", false),
+		"D": newCompareProvider("D", 40, "", true),
+	}
+
+	c, err := e.CompareMovieInfos("fake-001")
+	require.NoError(t, err)
+	assert.Equal(t, "FAKE-001", c.Number)
+	// in the search ranking, failed ones are skipped.
+	assert.Equal(t, []string{"A", "B", "C"}, c.Providers)
+
+	fields := make(map[string]*FieldComparison)
+	for _, f := range c.Fields {
+		fields[f.Field] = f
+	}
+	assert.NotContains(t, fields, "id")
+	assert.NotContains(t, fields, "provider")
+	if assert.Contains(t, fields, "title") {
+		assert.False(t, fields["title"].Agreed)
+		assert.Equal(t, map[string]any{
+			"A": "Fake Movie 001",
+			"B": "Fake Movie 001",
+			"C": "Other Title",
+		}, fields["title"].Values)
+	}
+	if assert.Contains(t, fields, "runtime") {
+		assert.True(t, fields["runtime"].Agreed)
+		assert.Equal(t, map[string]any{"A": 61, "B": 61, "C": 61}, fields["runtime"].Values)
+	}
+	// fields are in the struct order.
+	assert.Equal(t, "number", c.Fields[0].Field)
+
+	e.movieProviders = map[string]mt.MovieProvider{"D": newCompareProvider("D", 40, "", true)}
+	_, err = e.CompareMovieInfos("FAKE-001")
+	assert.Equal(t, ErrNoComparableInfos, err)
+}
@@ -0,0 +1,129 @@
+package engine
+
+import (
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/metatube-community/metatube-sdk-go/common/comparer"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// ReconcileActors dedupes the names of the same performers spelled in
+// different ways, e.g., by different providers, the first spelling is kept.
+// Names are the same if they're equal regardless of widths, kana scripts,
+// romaji and name orders, or they're the names and aliases of the same
+// actor info stored, e.g., `三上悠亜` and `Yua Mikami`, but never the ones
+// of the related performers listed by the info, see actorCrosswalk.
+func (e *Engine) ReconcileActors(actors []string) []string {
+	crosswalk := e.actorCrosswalk(actors)
+	same := func(a, b string) bool {
+		for _, group := range crosswalk {
+			if (containsName(group.names, a) && containsName(group.related, b)) ||
+				(containsName(group.names, b) && containsName(group.related, a)) {
+				return false
+			}
+		}
+		if comparer.SameName(a, b) {
+			return true
+		}
+		for _, group := range crosswalk {
+			if containsName(group.names, a) && containsName(group.names, b) {
+				return true
+			}
+		}
+		return false
+	}
+	reconciled := make([]string, 0, len(actors))
+	for _, actor := range actors {
+		if actor == "" || slices.ContainsFunc(reconciled, func(kept string) bool {
+			return kept == actor || same(kept, actor)
+		}) {
+			continue
+		}
+		reconciled = append(reconciled, actor)
+	}
+	return reconciled
+}
+
+// crosswalkGroup is the names of the same performer, and the names of the
+// related but different performers.
+type crosswalkGroup struct {
+	names   []string
+	related []string
+}
+
+// actorCrosswalk returns the groups of stored actor infos of the names,
+// with their names, original names and aliases. Infos of the same social
+// links are of the same performer, and solo movies of the filmography
+// stored crediting the performer by other names add the names as well.
+func (e *Engine) actorCrosswalk(names []string) (crosswalk []*crosswalkGroup) {
+	if len(names) < 2 {
+		return // nothing to reconcile.
+	}
+	var infos []*model.ActorInfo
+	if err := e.db.Where("name IN ? OR original_name IN ?", names, names).Find(&infos).Error; err != nil {
+		return // ignore DB query error.
+	}
+	var (
+		byLink   = make(map[string]*crosswalkGroup)
+		byNumber = make(map[string][]*crosswalkGroup)
+	)
+	for _, info := range infos {
+		group := &crosswalkGroup{names: append(nonEmpty(info.Name, info.OriginalName), info.Aliases...)}
+		for _, link := range info.SocialLinks {
+			link = strings.TrimSuffix(strings.ToLower(link), "/")
+			if joined, ok := byLink[link]; ok && joined != group {
+				joined.names = append(joined.names, group.names...)
+				joined.related = append(joined.related, group.related...)
+				group = joined
+			}
+			byLink[link] = group
+		}
+		for _, r := range info.Related {
+			group.related = append(group.related, r.Name)
+		}
+		for _, entry := range info.Filmography {
+			if entry.Number != "" {
+				byNumber[strings.ToUpper(entry.Number)] = append(byNumber[strings.ToUpper(entry.Number)], group)
+			}
+		}
+		if !slices.Contains(crosswalk, group) {
+			crosswalk = append(crosswalk, group)
+		}
+	}
+	if len(byNumber) == 0 {
+		return
+	}
+	var movies []*model.MovieInfo
+	if err := e.db.Select("number", "actors").
+		Where("UPPER(number) IN ?", slices.Collect(maps.Keys(byNumber))).
+		Find(&movies).Error; err != nil {
+		return // ignore DB query error.
+	}
+	for _, movie := range movies {
+		if len(movie.Actors) != 1 || !slices.Contains(names, movie.Actors[0]) {
+			continue // only solo movies credit the performer.
+		}
+		for _, group := range byNumber[strings.ToUpper(movie.Number)] {
+			if !slices.Contains(group.names, movie.Actors[0]) {
+				group.names = append(group.names, movie.Actors[0])
+			}
+		}
+	}
+	return
+}
+
+func containsName(names []string, name string) bool {
+	return slices.ContainsFunc(names, func(s string) bool {
+		return s == name || comparer.SameName(s, name)
+	})
+}
+
+// reconcileMovieActors reconciles the actors of the info, see
+// ReconcileActors.
+func (e *Engine) reconcileMovieActors(info *model.MovieInfo) {
+	if len(info.Actors) > 1 {
+		info.Actors = e.ReconcileActors(info.Actors)
+	}
+}
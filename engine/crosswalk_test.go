@@ -0,0 +1,108 @@
+package engine
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+	"github.com/metatube-community/metatube-sdk-go/provider/gfriends"
+	"github.com/metatube-community/metatube-sdk-go/provider/xslist"
+)
+
+func TestEngine_ReconcileActors(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	require.NoError(t, e.db.Create(&model.ActorInfo{
+		ID:       "1",
+		Name:     "三上悠亜",
+		Provider: "TEST",
+		Homepage: "https://example.com/actors/1",
+		Aliases:  []string{"Yua Mikami", "鬼頭桃菜"},
+		Images:   []string{},
+	}).Error)
+
+	assert.Equal(t, []string{"三上悠亜", "つぼみ", "松下紗栄子"}, e.ReconcileActors([]string{
+		"三上悠亜", "つぼみ", "Mikami Yua", "ツボミ", "みかみ ゆあ", "松下紗栄子", "鬼頭桃菜", "",
+	}))
+	// different performers of similar names are kept.
+	assert.Equal(t, []string{"Yua Mikami", "Yui Mikami"}, e.ReconcileActors([]string{"Yua Mikami", "Yui Mikami"}))
+}
+
+func TestEngine_ReconcileActorsCrosswalk(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	for _, info := range []*model.ActorInfo{{
+		ID:          "107",
+		Name:        "三上悠亜",
+		Provider:    "XSLIST",
+		Homepage:    "https://xslist.org/zh/model/107.html",
+		Filmography: []*model.FilmographyEntry{{Number: "SSIS-001"}, {Number: "SSIS-002"}},
+		SocialLinks: map[string]string{"twitter": "https://twitter.com/yua_mikami"},
+	}, {
+		ID:          "1",
+		Name:        "鬼頭桃菜",
+		Provider:    "TEST",
+		Homepage:    "https://example.com/actors/1",
+		SocialLinks: map[string]string{"twitter": "https://twitter.com/Yua_Mikami/"},
+	}, {
+		ID:       "2",
+		Name:     "Yu Kawakami",
+		Provider: "TEST",
+		Homepage: "https://example.com/actors/2",
+		Related:  []*model.ActorRef{{ID: "3", Name: "Kawakami Yu"}},
+	}} {
+		info.Images = []string{}
+		require.NoError(t, e.db.Create(info).Error)
+	}
+	for _, movie := range []*model.MovieInfo{
+		{ID: "ssis001", Number: "SSIS-001", Actors: []string{"Y. Mikami"}},
+		{ID: "ssis002", Number: "SSIS-002", Actors: []string{"三上悠亜", "Other Actor"}},
+	} {
+		movie.Provider, movie.Homepage = "TEST", "https://example.com/movies/"+movie.ID
+		require.NoError(t, e.db.Create(movie).Error)
+	}
+
+	// joined by the social link.
+	assert.Equal(t, []string{"三上悠亜"}, e.ReconcileActors([]string{"三上悠亜", "鬼頭桃菜"}))
+	// credited by the solo movie of the filmography.
+	assert.Equal(t, []string{"三上悠亜"}, e.ReconcileActors([]string{"三上悠亜", "Y. Mikami"}))
+	assert.Equal(t, []string{"三上悠亜", "Other Actor"}, e.ReconcileActors([]string{"三上悠亜", "Other Actor"}))
+	// related performers are never the same.
+	assert.Equal(t, []string{"Yu Kawakami", "Kawakami Yu"}, e.ReconcileActors([]string{"Yu Kawakami", "Kawakami Yu"}))
+}
+
+// TestEngine_ReconcileActorsOriginalNames reconciles the Chinese and
+// Japanese spellings by the original name of the actor info scraped and
+// stored by the engine, with xslist pages replayed by fixtures.
+func TestEngine_ReconcileActorsOriginalNames(t *testing.T) {
+	page := func(lang, name string) *fetch.Fixture {
+		return &fetch.Fixture{
+			URL:    "https://xslist.org/" + lang + "/model/107.html",
+			Header: http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+			Body: []byte(`<html><body><div id="sss1"><header><h1><span>` + name +
+				`</span></h1></header></div><div id="gallery"><a href="https://xslist.org/1.jpg" data-width="100" data-height="100"></a></div></body></html>`),
+		}
+	}
+	provider := xslist.New()
+	provider.SetFetchClient(fetch.NewFixtureClient(page("zh", "三上悠亚"), page("ja", "三上悠亜")))
+
+	e := newBenchEngine(t, 0)
+	e.actorProviders = map[string]mt.ActorProvider{
+		"GFRIENDS": &galleryFake{Fake: fake.New(), name: gfriends.Name},
+		"XSLIST":   provider,
+	}
+	assert.Equal(t, []string{"三上悠亜", "三上悠亚"}, e.ReconcileActors([]string{"三上悠亜", "三上悠亚"}))
+
+	_, err := e.GetActorInfoByProviderID(xslist.Name, "107", false)
+	require.NoError(t, err)
+	stored, err := e.getActorInfoFromDB(provider, "107")
+	require.NoError(t, err)
+	assert.Equal(t, "三上悠亚", stored.Name)
+	assert.Equal(t, "三上悠亜", stored.OriginalName)
+
+	assert.Equal(t, []string{"三上悠亜"}, e.ReconcileActors([]string{"三上悠亜", "三上悠亚"}))
+}
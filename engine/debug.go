@@ -0,0 +1,50 @@
+package engine
+
+import (
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+func (e *Engine) getMovieInfoWithDebug(name string, callback func(mt.MovieProvider) (*model.MovieInfo, error)) (info *model.MovieInfo, snapshot *mt.Snapshot, err error) {
+	provider, err := e.newMovieProvider(name)
+	if err != nil {
+		return
+	}
+	snapshotter, ok := provider.(mt.Snapshotter)
+	if !ok {
+		return nil, nil, mt.ErrSnapshotNotSupported
+	}
+	snapshotter.EnableSnapshot()
+	defer func() {
+		// snapshot is returned regardless of errors.
+		snapshot = snapshotter.Snapshot()
+	}()
+	if info, err = callback(provider); err == nil && (info == nil || !info.Valid()) {
+		err = mt.ErrIncompleteMetadata
+	}
+	return
+}
+
+// GetMovieInfoByProviderIDWithDebug gets movie info directly from provider, along
+// with the raw responses and matched selectors of the scrape for diagnosis. The
+// DB is neither queried nor updated.
+func (e *Engine) GetMovieInfoByProviderIDWithDebug(name, id string) (*model.MovieInfo, *mt.Snapshot, error) {
+	return e.getMovieInfoWithDebug(name, func(provider mt.MovieProvider) (*model.MovieInfo, error) {
+		if id = provider.NormalizeMovieID(id); id == "" {
+			return nil, mt.ErrInvalidID
+		}
+		return provider.GetMovieInfoByID(id)
+	})
+}
+
+// GetMovieInfoByURLWithDebug is like GetMovieInfoByProviderIDWithDebug, but gets
+// movie info by the given URL.
+func (e *Engine) GetMovieInfoByURLWithDebug(rawURL string) (*model.MovieInfo, *mt.Snapshot, error) {
+	provider, err := e.GetMovieProviderByURL(rawURL)
+	if err != nil {
+		return nil, nil, err
+	}
+	return e.getMovieInfoWithDebug(provider.Name(), func(provider mt.MovieProvider) (*model.MovieInfo, error) {
+		return provider.GetMovieInfoByURL(rawURL)
+	})
+}
@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"image"
+	"sync"
+
+	"github.com/metatube-community/metatube-sdk-go/common/number"
+	"github.com/metatube-community/metatube-sdk-go/imageutil"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// Max perception hash distance of two images to be treated as the same.
+const maxCoverHashDistance = 8
+
+// MovieDuplicates is a group of movie infos that refer to the same release.
+type MovieDuplicates struct {
+	// Canonical is the preferred info of the release.
+	Canonical *model.MovieInfo `json:"canonical"`
+	// References are the other infos of the same release.
+	References []*model.MovieSearchResult `json:"references"`
+}
+
+// DedupeMovieInfos recognizes the same release published under different
+// providers/IDs, and collapses them into groups with cross-references.
+// Infos are grouped by their canonical numbers, and optionally by the
+// perception hash of their covers if compareCover is enabled.
+func (e *Engine) DedupeMovieInfos(infos []*model.MovieInfo, compareCover bool) []*MovieDuplicates {
+	infos = validMovieInfos(infos)
+
+	// union-find parents.
+	parents := make([]int, len(infos))
+	for i := range parents {
+		parents[i] = i
+	}
+	find := func(i int) int {
+		for parents[i] != i {
+			parents[i] = parents[parents[i]]
+			i = parents[i]
+		}
+		return i
+	}
+	union := func(i, j int) {
+		if pi, pj := find(i), find(j); pi != pj {
+			parents[pj] = pi
+		}
+	}
+
+	// group by canonical numbers.
+	numbers := make(map[string]int)
+	for i, info := range infos {
+		key := number.Canonicalize(info.Provider, number.Trim(info.Number))
+		if j, ok := numbers[key]; ok {
+			union(j, i)
+			continue
+		}
+		numbers[key] = i
+	}
+
+	// group by cover similarities.
+	if compareCover {
+		covers := e.fetchMovieCovers(infos)
+		for i := range infos {
+			for j := i + 1; j < len(infos); j++ {
+				if covers[i] == nil || covers[j] == nil || find(i) == find(j) {
+					continue
+				}
+				if imageutil.PerceptionHashDistance(covers[i], covers[j]) <= maxCoverHashDistance {
+					union(i, j)
+				}
+			}
+		}
+	}
+
+	var (
+		roots  []int
+		groups = make(map[int][]*model.MovieInfo)
+	)
+	for i, info := range infos {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], info)
+	}
+
+	results := make([]*MovieDuplicates, 0, len(roots))
+	for _, root := range roots {
+		group := groups[root]
+		canonical := 0
+		for i, info := range group {
+			if e.moviePriority(info.Provider) > e.moviePriority(group[canonical].Provider) {
+				canonical = i
+			}
+		}
+		dup := &MovieDuplicates{
+			Canonical:  group[canonical],
+			References: make([]*model.MovieSearchResult, 0, len(group)-1),
+		}
+		for i, info := range group {
+			if i != canonical {
+				dup.References = append(dup.References, info.ToSearchResult())
+			}
+		}
+		results = append(results, dup)
+	}
+	return results
+}
+
+func (e *Engine) fetchMovieCovers(infos []*model.MovieInfo) []image.Image {
+	var (
+		wg     sync.WaitGroup
+		covers = make([]image.Image, len(infos))
+	)
+	for i, info := range infos {
+		provider, err := e.GetMovieProviderByName(info.Provider)
+		if err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			covers[i], _ = e.getImageByURL(provider, url) // ignore error
+		}(i, info.CoverURL)
+	}
+	wg.Wait()
+	return covers
+}
+
+func (e *Engine) moviePriority(name string) int {
+	if provider, err := e.GetMovieProviderByName(name); err == nil {
+		return e.movieProviderPriority(provider)
+	}
+	return 0
+}
+
+func validMovieInfos(infos []*model.MovieInfo) []*model.MovieInfo {
+	results := make([]*model.MovieInfo, 0, len(infos))
+	for _, info := range infos {
+		if info != nil && info.Valid() {
+			results = append(results, info)
+		}
+	}
+	return results
+}
@@ -0,0 +1,119 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+// rankedProvider is a renamed fake provider of the priority.
+type rankedProvider struct {
+	*benchProvider
+	priority int
+}
+
+func (p *rankedProvider) Priority() int { return p.priority }
+
+func TestEngine_DedupeMovieInfos(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{
+		"LOW":  &rankedProvider{&benchProvider{Fake: fake.New(), name: "Low"}, 1},
+		"HIGH": &rankedProvider{&benchProvider{Fake: fake.New(), name: "High"}, 10},
+	}
+	newInfo := func(provider, id, number string) *model.MovieInfo {
+		info, err := fake.New().GetMovieInfoByID(id)
+		require.NoError(t, err)
+		info.Provider, info.Number = provider, number
+		return info
+	}
+	sharedCover := newInfo("Low", "FAKE-001", "FAKE-001").CoverURL
+
+	for _, unit := range []struct {
+		name         string
+		infos        []*model.MovieInfo
+		compareCover bool
+		// want are IDs of canonical infos followed by references.
+		want [][]string
+	}{
+		{
+			name: "distinct",
+			infos: []*model.MovieInfo{
+				newInfo("Low", "FAKE-001", "FAKE-001"),
+				newInfo("Low", "FAKE-002", "FAKE-002"),
+			},
+			want: [][]string{{"Low:FAKE-001"}, {"Low:FAKE-002"}},
+		},
+		{
+			name: "same number, higher priority is canonical",
+			infos: []*model.MovieInfo{
+				newInfo("Low", "FAKE-001", "FAKE-001"),
+				newInfo("High", "FAKE-010", "FAKE-001"),
+				newInfo("Low", "FAKE-002", "FAKE-002"),
+			},
+			want: [][]string{{"High:FAKE-010", "Low:FAKE-001"}, {"Low:FAKE-002"}},
+		},
+		{
+			name: "number variants",
+			infos: []*model.MovieInfo{
+				newInfo("Low", "FAKE-001", "FAKE-001"),
+				newInfo("High", "FAKE-010", "FAKE-001.mp4"),
+			},
+			want: [][]string{{"High:FAKE-010", "Low:FAKE-001"}},
+		},
+		{
+			name: "invalid infos are dropped",
+			infos: []*model.MovieInfo{
+				nil,
+				{ID: "FAKE-003", Provider: "Low"},
+				newInfo("Low", "FAKE-001", "FAKE-001"),
+			},
+			want: [][]string{{"Low:FAKE-001"}},
+		},
+		{
+			name: "same cover",
+			infos: []*model.MovieInfo{
+				newInfo("Low", "FAKE-001", "FAKE-001"),
+				func() *model.MovieInfo {
+					info := newInfo("High", "FAKE-002", "FAKE-002")
+					info.CoverURL = sharedCover
+					return info
+				}(),
+				func() *model.MovieInfo {
+					// covers of unknown providers are not compared.
+					info := newInfo("Unknown", "FAKE-003", "FAKE-003")
+					info.CoverURL = sharedCover
+					return info
+				}(),
+			},
+			compareCover: true,
+			want:         [][]string{{"High:FAKE-002", "Low:FAKE-001"}, {"Unknown:FAKE-003"}},
+		},
+		{
+			name: "same cover, disabled",
+			infos: []*model.MovieInfo{
+				newInfo("Low", "FAKE-001", "FAKE-001"),
+				func() *model.MovieInfo {
+					info := newInfo("High", "FAKE-002", "FAKE-002")
+					info.CoverURL = sharedCover
+					return info
+				}(),
+			},
+			want: [][]string{{"Low:FAKE-001"}, {"High:FAKE-002"}},
+		},
+	} {
+		var got [][]string
+		for _, dup := range e.DedupeMovieInfos(unit.infos, unit.compareCover) {
+			ids := []string{dup.Canonical.Provider + ":" + dup.Canonical.ID}
+			for _, ref := range dup.References {
+				ids = append(ids, ref.Provider+":"+ref.ID)
+			}
+			got = append(got, ids)
+		}
+		assert.Equal(t, unit.want, got, unit.name)
+	}
+}
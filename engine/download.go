@@ -0,0 +1,46 @@
+package engine
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/common/download"
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/storage"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// Download downloads the image or trailer of the provider to the file name
+// resumably, see (*download.Downloader).Download for sum.
+func (e *Engine) Download(provider mt.Provider, url, name, sum string) (*download.Result, error) {
+	return download.New(e.downloadGetFunc(provider)).Download(url, name, sum)
+}
+
+// DownloadManifest downloads the image or trailer of the provider to the
+// file name relative to the manifest directory, which is skipped if it's
+// unchanged since recorded, see (*download.Manifest).Download.
+func (e *Engine) DownloadManifest(provider mt.Provider, url string, m *download.Manifest, name string, scrapedAt time.Time) (*download.Result, error) {
+	return m.Download(download.New(e.downloadGetFunc(provider)), url, name, scrapedAt)
+}
+
+// DownloadBlob downloads the image or trailer of the provider into the
+// blob store by name, e.g., artwork kept in object storage.
+func (e *Engine) DownloadBlob(provider mt.Provider, url string, b storage.Blob, name string) (*download.Result, error) {
+	return download.New(e.downloadGetFunc(provider)).Put(b, url, name)
+}
+
+func (e *Engine) downloadGetFunc(provider mt.Provider) download.GetFunc {
+	if _, ok := provider.(mt.Fetcher); ok {
+		// custom fetchers don't take range options, so never resume.
+		return func(url string, _ ...fetch.Option) (*http.Response, error) {
+			return e.Fetch(url, provider)
+		}
+	}
+	return func(url string, opts ...fetch.Option) (*http.Response, error) {
+		return e.meterFetch(provider, func() (*http.Response, error) {
+			return e.providerFetcher(provider).Get(url, append([]fetch.Option{
+				fetch.WithReferer(provider.URL().String()),
+			}, opts...)...)
+		})
+	}
+}
@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// editionPatterns match editions in titles and genres of provider pages.
+var editionPatterns = []struct {
+	edition string
+	re      *regexp.Regexp
+}{
+	{model.FourKEdition, regexp.MustCompile(`(?i)\b4k\b|\b2160p\b|ultra\s?hd`)},
+	{model.VREdition, regexp.MustCompile(`(?i)\bvr\b`)},
+	{model.RemasteredEdition, regexp.MustCompile(`(?i)\bremaster(ed)?\b|リマスター`)},
+	{model.DirectorsCutEdition, regexp.MustCompile(`(?i)\bdirector'?s\s?cut\b|ディレクターズ\s?[・･]?\s?カット`)},
+	{model.BehindTheScenesEdition, regexp.MustCompile(`(?i)\bbehind\s?the\s?scenes\b|\bmaking\s?of\b|メイキング`)},
+}
+
+// editionSuffixRe matches edition suffixes of numbers, e.g., `ABC-123-4K`.
+var editionSuffixRe = regexp.MustCompile(`(?i)[-_](4k|vr|remaster(?:ed)?|dc|making)$`)
+
+var editionSuffixes = map[string]string{
+	"4k":         model.FourKEdition,
+	"vr":         model.VREdition,
+	"remaster":   model.RemasteredEdition,
+	"remastered": model.RemasteredEdition,
+	"dc":         model.DirectorsCutEdition,
+	"making":     model.BehindTheScenesEdition,
+}
+
+// detectEditions sets editions of the info by its title and genres, and
+// by edition suffixes of the number, which is kept as the provider gives,
+// and the base number without suffixes is set for editions to share. VR
+// movies without VR metadata given by providers are parsed from the texts,
+// or of the default format.
+func detectEditions(info *model.MovieInfo) {
+	base := info.Number
+	for ss := editionSuffixRe.FindStringSubmatch(base); ss != nil; ss = editionSuffixRe.FindStringSubmatch(base) {
+		info.SetEdition(editionSuffixes[strings.ToLower(ss[1])])
+		base = base[:len(base)-len(ss[0])]
+	}
+	if info.BaseNumber = ""; base != info.Number {
+		info.BaseNumber = base
+	}
+	texts := append([]string{info.Title}, info.Genres...)
+	for _, p := range editionPatterns {
+		for _, text := range texts {
+			if p.re.MatchString(text) {
+				info.SetEdition(p.edition)
+				break
+			}
+		}
+	}
+	if info.VR != nil {
+		info.SetEdition(model.VREdition)
+	} else if info.HasEdition(model.VREdition) {
+		if info.VR = model.ParseVRInfo(texts...); info.VR == nil {
+			info.VR = model.DefaultVRInfo()
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func TestDetectEditions(t *testing.T) {
+	for _, unit := range []struct {
+		number, title string
+		genres        []string
+		wantBase      string
+		want          []string
+	}{
+		{"ABC-123", "Some Title", []string{"Drama"}, "", nil},
+		{"ABC-123-4K", "Some Title", nil, "ABC-123", []string{model.FourKEdition}},
+		{"ABC-123_VR-4K", "Some Title", nil, "ABC-123", []string{model.FourKEdition, model.VREdition}},
+		{"ABC-123", "【VR】長尺", []string{"ハイクオリティVR"}, "", []string{model.VREdition}},
+		{"ABC-123", "4Kリマスター版", nil, "", []string{model.FourKEdition, model.RemasteredEdition}},
+		{"ABC-123", "Director's Cut", nil, "", []string{model.DirectorsCutEdition}},
+		{"ABC-123", "撮影メイキング", nil, "", []string{model.BehindTheScenesEdition}},
+		{"ABC-123", "Kavrin 24kg", nil, "", nil},
+	} {
+		info := &model.MovieInfo{Number: unit.number, Title: unit.title, Genres: unit.genres}
+		detectEditions(info)
+		assert.Equal(t, unit.number, info.Number, "kept as given")
+		assert.Equal(t, unit.wantBase, info.BaseNumber, unit.number)
+		assert.Equal(t, pq.StringArray(unit.want), info.Editions, unit.title)
+
+		// idempotent.
+		detectEditions(info)
+		assert.Equal(t, unit.number, info.Number)
+		assert.Equal(t, unit.wantBase, info.BaseNumber, unit.number)
+		assert.Equal(t, pq.StringArray(unit.want), info.Editions, unit.title)
+	}
+}
+
+func TestDetectEditions_VR(t *testing.T) {
+	info := &model.MovieInfo{Number: "ABC-123", Title: "【VR】長尺", Genres: []string{"ハイクオリティVR"}}
+	detectEditions(info)
+	assert.Equal(t, model.DefaultVRInfo(), info.VR)
+
+	info = &model.MovieInfo{Number: "ABC-123-VR", Title: "Fisheye 3D"}
+	detectEditions(info)
+	assert.Equal(t, &model.VRInfo{Projection: model.FisheyeProjection, StereoMode: model.SideBySideStereo, FOV: 190}, info.VR)
+
+	// VR metadata given by providers are kept.
+	vr := &model.VRInfo{Projection: model.EquirectangularProjection, StereoMode: model.TopBottomStereo, FOV: 360}
+	info = &model.MovieInfo{Number: "ABC-123", Title: "Some Title", VR: vr}
+	detectEditions(info)
+	assert.Equal(t, vr, info.VR)
+	assert.True(t, info.HasEdition(model.VREdition))
+}
@@ -2,41 +2,145 @@ package engine
 
 import (
 	"net/http"
-	"net/url"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/hashicorp/go-cleanhttp"
 	"go.uber.org/zap"
+	"golang.org/x/net/proxy"
 	"gorm.io/gorm"
 
+	"github.com/metatube-community/metatube-sdk-go/common/bandwidth"
+	"github.com/metatube-community/metatube-sdk-go/common/dlock"
 	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/httpcache"
+	"github.com/metatube-community/metatube-sdk-go/common/redact"
+	"github.com/metatube-community/metatube-sdk-go/common/socks"
+	"github.com/metatube-community/metatube-sdk-go/common/storage"
 	"github.com/metatube-community/metatube-sdk-go/database"
+	"github.com/metatube-community/metatube-sdk-go/export"
+	"github.com/metatube-community/metatube-sdk-go/media"
 	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/notify"
 	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/gfriends"
 )
 
 type Engine struct {
 	db      *gorm.DB
+	timeout time.Duration
 	fetcher *fetch.Fetcher
 	// Engine Logger
 	logger *zap.SugaredLogger
 	// Name:Provider Map
 	actorProviders map[string]mt.ActorProvider
 	movieProviders map[string]mt.MovieProvider
-	// Host:Providers Map
-	actorHostProviders map[string][]mt.ActorProvider
-	movieHostProviders map[string][]mt.MovieProvider
+	// URL:Provider Router
+	router *mt.Router
+	// Movie Info Parse Mode
+	parseMode ParseMode
+	// Provider Instance Pools
+	poolSize int
+	pools    providerPools
+	// Aggregated Search Deadline
+	searchDeadline time.Duration
+	// Nested Sub-Request Timeout, disabled if zero
+	subTimeout time.Duration
+	// Internet Archive Fallback of Removed Pages
+	archiveFallback bool
+	// Actor Image Pack URL
+	actorImagePackURL string
+	// Preferred Languages
+	languages []string
+	// Member Credentials by Realm
+	credentials map[string]credentials
+	// Info Post-Processors
+	movieProcessors []func(*model.MovieInfo)
+	actorProcessors []func(*model.ActorInfo)
+	// Content Ratings by Target
+	contentRatings export.Ratings
+	// Content Filters, nil if disabled
+	filters *Filters
+	// Selector Patches by Provider Name
+	selectorPatches map[string][]*mt.SelectorPatch
+	// Host Overrides by Provider Name
+	hostOverrides map[string][]*mt.HostOverride
+	// Soft 404 Detectors by Provider Name
+	softNotFound map[string]*mt.SoftNotFound
+	// Placeholder Image Hashes, e.g., of "now printing" covers
+	placeholderHashes []string
+	// Proxy Dialers by Provider Name, isolated by provider
+	proxyMu sync.Mutex
+	proxies map[string]*socks.Dialer
+	// Resource Fetchers by Provider Name, see providerFetcher
+	fetcherMu sync.Mutex
+	fetchers  map[string]*fetch.Fetcher
+	// Provider Transport Wrappers
+	transportWrappers []func(http.RoundTripper) http.RoundTripper
+	// Provider HTTP Backends by Provider Name, nil for the default
+	fetchClient func(provider string) fetch.Client
+	// Source Image Cache
+	imageCache httpcache.Cache
+	// HLS Trailer Remuxer, nil if disabled
+	trailerRemuxer media.Remuxer
+	// Member Session Store, nil if not persisted
+	sessionStore storage.KV
+	// Stored Movie Change Handler
+	movieChangeHandler func(old, new *model.MovieInfo, changes []*model.FieldChange)
+	// Log Redactor, nil if privacy mode disabled
+	redactor *redact.Redactor
+	// Bandwidth Meter, nil if accounting disabled
+	bandwidth *bandwidth.Meter
+	// Event Notifier, nil if disabled
+	notifier notify.Notifier
+	notifyWG sync.WaitGroup
+	// Scrape Progress Events of Subscribers
+	events EventBus
+	// Expensive Fields Resolved on Demand
+	lazyFields []mt.Field
+	// Provider Priority Tuner, nil if disabled
+	tuner *priorityTuner
+	// Related Entity Prefetcher, nil if disabled
+	prefetcher *prefetcher
+	// Locker of Scrapes, shared by instances if distributed
+	locker dlock.Locker
+	// Provider Throttle Statistics
+	throttleMu    sync.Mutex
+	throttleStats map[string]*ThrottleStats
+	outageAt      map[string]time.Time
+	// Info Cache and Provider Failure Statistics
+	statsMu      sync.Mutex
+	cacheStats   CacheStats
+	failureStats map[string]*FailureStats
 }
 
-func New(db *gorm.DB, timeout time.Duration) *Engine {
+func New(db *gorm.DB, timeout time.Duration, opts ...Option) *Engine {
 	engine := &Engine{
 		db:      db,
+		timeout: timeout,
 		fetcher: fetch.Default(&fetch.Config{Timeout: timeout}),
+		pools: providerPools{
+			actor: make(map[string]*providerPool[mt.ActorProvider]),
+			movie: make(map[string]*providerPool[mt.MovieProvider]),
+		},
+		throttleStats: make(map[string]*ThrottleStats),
+		outageAt:      make(map[string]time.Time),
+		failureStats:  make(map[string]*FailureStats),
+		router:        mt.NewRouter(),
+		locker:        dlock.NewLocal(),
+	}
+	for _, opt := range opts {
+		// Apply options.
+		opt(engine)
 	}
 	logger, _ := zap.NewProduction()
 	engine.logger = logger.Sugar()
 	engine.initActorProviders(timeout)
 	engine.initMovieProviders(timeout)
+	if engine.prefetcher != nil {
+		go engine.prefetcher.run()
+	}
 	return engine
 }
 
@@ -54,18 +158,14 @@ func Default() *Engine {
 func (e *Engine) initActorProviders(timeout time.Duration) {
 	{ // init
 		e.actorProviders = make(map[string]mt.ActorProvider)
-		e.actorHostProviders = make(map[string][]mt.ActorProvider)
 	}
 	mt.RangeActorFactory(func(name string, factory mt.ActorFactory) {
 		provider := factory()
-		if s, ok := provider.(mt.RequestTimeoutSetter); ok {
-			s.SetRequestTimeout(timeout)
-		}
+		e.setupProvider(provider, timeout)
 		// Add actor provider by name.
 		e.actorProviders[strings.ToUpper(name)] = provider
-		// Add actor provider by host.
-		host := provider.URL().Hostname()
-		e.actorHostProviders[host] = append(e.actorHostProviders[host], provider)
+		// Add actor provider by URL patterns.
+		e.router.AddActorProvider(provider)
 	})
 }
 
@@ -73,21 +173,145 @@ func (e *Engine) initActorProviders(timeout time.Duration) {
 func (e *Engine) initMovieProviders(timeout time.Duration) {
 	{ // init
 		e.movieProviders = make(map[string]mt.MovieProvider)
-		e.movieHostProviders = make(map[string][]mt.MovieProvider)
 	}
 	mt.RangeMovieFactory(func(name string, factory mt.MovieFactory) {
 		provider := factory()
-		if s, ok := provider.(mt.RequestTimeoutSetter); ok {
-			s.SetRequestTimeout(timeout)
-		}
+		e.setupProvider(provider, timeout)
 		// Add movie provider by name.
 		e.movieProviders[strings.ToUpper(name)] = provider
-		// Add movie provider by host.
-		host := provider.URL().Hostname()
-		e.movieHostProviders[host] = append(e.movieHostProviders[host], provider)
+		// Add movie provider by URL patterns.
+		e.router.AddMovieProvider(provider)
 	})
 }
 
+// setupProvider applies engine-wide settings to the provider.
+func (e *Engine) setupProvider(provider mt.Provider, timeout time.Duration) {
+	if s, ok := provider.(mt.RequestTimeoutSetter); ok {
+		s.SetRequestTimeout(timeout)
+	}
+	if s, ok := provider.(mt.SubRequestTimeoutSetter); ok && e.subTimeout > 0 {
+		s.SetSubRequestTimeout(e.subTimeout)
+	}
+	if a, ok := provider.(mt.ArchiveFallback); ok && e.archiveFallback {
+		a.SetArchiveFallback(true)
+	}
+	if gf, ok := provider.(*gfriends.GFriends); ok && e.actorImagePackURL != "" {
+		gf.SetImagePackURL(e.actorImagePackURL)
+	}
+	if c, ok := provider.(mt.CredentialSetter); ok {
+		if cred, ok := e.credentials[c.CredentialRealm()]; ok {
+			c.SetCredentials(cred.username, cred.password)
+		}
+	}
+	if s, ok := provider.(mt.SessionStorer); ok && e.sessionStore != nil {
+		s.SetSessionStore(e.sessionStore)
+	}
+	if p, ok := provider.(mt.SelectorPatcher); ok {
+		if patches, ok := e.selectorPatches[strings.ToUpper(provider.Name())]; ok {
+			p.SetSelectorPatches(patches...)
+		}
+	}
+	if s, ok := provider.(mt.SoftNotFoundSetter); ok {
+		if d, ok := e.softNotFound[strings.ToUpper(provider.Name())]; ok {
+			s.SetSoftNotFound(d)
+		}
+	}
+	if h, ok := provider.(mt.HostOverrider); ok {
+		if overrides, ok := e.hostOverrides[strings.ToUpper(provider.Name())]; ok {
+			h.SetHostOverrides(overrides...)
+		}
+	}
+	if d, ok := provider.(mt.DialerSetter); ok {
+		if dialer := e.proxyDialer(provider.Name()); dialer != nil {
+			d.SetDialer(dialer)
+		}
+	}
+	if m, ok := provider.(mt.MovieEnricher); ok && len(e.lazyFields) > 0 {
+		m.SetLazyFields(e.lazyFields...)
+	}
+	if n, ok := provider.(mt.ThrottleNotifier); ok {
+		name := provider.Name()
+		n.SetThrottleHandler(func(wait time.Duration) { e.recordThrottle(name, wait) })
+	}
+	if f, ok := provider.(mt.FetchClientSetter); ok && e.fetchClient != nil {
+		if client := e.fetchClient(provider.Name()); client != nil {
+			f.SetFetchClient(client)
+		}
+	}
+	if w, ok := provider.(mt.TransportWrapper); ok {
+		for _, wrapper := range e.transportWrappers {
+			w.WrapTransport(wrapper)
+		}
+		if e.bandwidth != nil {
+			w.WrapTransport(e.bandwidth.Wrapper(provider.Name()))
+		}
+	}
+}
+
+// proxyDialer returns the proxy dialer of the provider, which is shared
+// by its instances, or nil if not proxied.
+func (e *Engine) proxyDialer(name string) *socks.Dialer {
+	e.proxyMu.Lock()
+	defer e.proxyMu.Unlock()
+	name = strings.ToUpper(name)
+	if d, ok := e.proxies[name]; ok {
+		return d
+	}
+	if d, ok := e.proxies[wildcardProvider]; ok {
+		// isolate providers proxied by the wildcard from each other.
+		d = d.Isolate(name)
+		e.proxies[name] = d
+		return d
+	}
+	return nil
+}
+
+// newMovieProvider allocates a new movie provider instance by name, which
+// is not shared with other callers, so it's safe to change its states.
+func (e *Engine) newMovieProvider(name string) (provider mt.MovieProvider, err error) {
+	mt.RangeMovieFactory(func(n string, factory mt.MovieFactory) {
+		if provider == nil && strings.EqualFold(n, name) {
+			provider = factory()
+		}
+	})
+	if provider == nil {
+		return nil, mt.ErrProviderNotFound
+	}
+	e.setupProvider(provider, e.timeout)
+	return
+}
+
+// newActorProvider allocates a new actor provider instance by name, which
+// is not shared with other callers, so it's safe to change its states.
+func (e *Engine) newActorProvider(name string) (provider mt.ActorProvider, err error) {
+	mt.RangeActorFactory(func(n string, factory mt.ActorFactory) {
+		if provider == nil && strings.EqualFold(n, name) {
+			provider = factory()
+		}
+	})
+	if provider == nil {
+		return nil, mt.ErrProviderNotFound
+	}
+	e.setupProvider(provider, e.timeout)
+	return
+}
+
+// Redactor returns the redactor of logs, which is nil if the privacy
+// mode is disabled.
+func (e *Engine) Redactor() *redact.Redactor { return e.redactor }
+
+// ContentRatings returns the content ratings emitted to targets.
+func (e *Engine) ContentRatings() export.Ratings { return e.contentRatings }
+
+// DisplayName returns the display name of the provider, which is credited
+// as the source of infos, or the name if not provided.
+func DisplayName(provider mt.Provider) string {
+	if p, ok := provider.(mt.DisplayNamer); ok {
+		return p.DisplayName()
+	}
+	return provider.Name()
+}
+
 func (e *Engine) IsActorProvider(name string) (ok bool) {
 	_, ok = e.actorProviders[strings.ToUpper(name)]
 	return
@@ -98,16 +322,11 @@ func (e *Engine) GetActorProviders() map[string]mt.ActorProvider {
 }
 
 func (e *Engine) GetActorProviderByURL(rawURL string) (mt.ActorProvider, error) {
-	u, err := url.Parse(rawURL)
+	provider, _, err := e.router.RouteActorURL(rawURL)
 	if err != nil {
 		return nil, err
 	}
-	for _, p := range e.actorHostProviders[u.Hostname()] {
-		if strings.HasPrefix(u.Path, p.URL().Path) {
-			return p, nil
-		}
-	}
-	return nil, mt.ErrProviderNotFound
+	return provider, nil
 }
 
 func (e *Engine) GetActorProviderByName(name string) (mt.ActorProvider, error) {
@@ -136,16 +355,11 @@ func (e *Engine) GetMovieProviders() map[string]mt.MovieProvider {
 }
 
 func (e *Engine) GetMovieProviderByURL(rawURL string) (mt.MovieProvider, error) {
-	u, err := url.Parse(rawURL)
+	provider, _, err := e.router.RouteMovieURL(rawURL)
 	if err != nil {
 		return nil, err
 	}
-	for _, p := range e.movieHostProviders[u.Hostname()] {
-		if strings.HasPrefix(u.Path, p.URL().Path) {
-			return p, nil
-		}
-	}
-	return nil, mt.ErrProviderNotFound
+	return provider, nil
 }
 
 func (e *Engine) GetMovieProviderByName(name string) (mt.MovieProvider, error) {
@@ -175,20 +389,81 @@ func (e *Engine) AutoMigrate(v bool) error {
 		locale = 'und-u-ks-level2',
 		deterministic = FALSE)`)
 	}
-	return e.db.AutoMigrate(
+	if err := e.db.AutoMigrate(
 		&model.MovieInfo{},
 		&model.ActorInfo{},
 		&model.MovieReviewInfo{},
-	)
+	); err != nil {
+		return err
+	}
+	// Apply versioned migrations.
+	_, err := e.Migrator().Up()
+	return err
 }
 
 // Fetch fetches content from url. If provider is nil, the
-// default fetcher will be used.
+// default fetcher will be used, and the content is not metered.
 func (e *Engine) Fetch(url string, provider mt.Provider) (*http.Response, error) {
 	// Provider which implements Fetcher interface should be
 	// used to fetch all its corresponding resources.
 	if fetcher, ok := provider.(mt.Fetcher); ok {
-		return fetcher.Fetch(url)
+		return e.meterFetch(provider, func() (*http.Response, error) { return fetcher.Fetch(url) })
+	}
+	return e.meterFetch(provider, func() (*http.Response, error) { return e.providerFetcher(provider).Fetch(url) })
+}
+
+// providerFetcher returns the fetcher of resources of the provider, which
+// is dialed by the proxy and pinned by the host overrides of the provider
+// like its pages, or the default fetcher if neither is configured.
+func (e *Engine) providerFetcher(provider mt.Provider) *fetch.Fetcher {
+	if provider == nil {
+		return e.fetcher
+	}
+	name := strings.ToUpper(provider.Name())
+	e.fetcherMu.Lock()
+	defer e.fetcherMu.Unlock()
+	if f, ok := e.fetchers[name]; ok {
+		return f
+	}
+	f := e.fetcher
+	var dialer proxy.ContextDialer
+	if d := e.proxyDialer(name); d != nil {
+		dialer = d
+	}
+	if overrides := e.hostOverrides[name]; dialer != nil || len(overrides) > 0 {
+		transport, _ := mt.DialTransport(cleanhttp.DefaultPooledTransport(), dialer, overrides...)
+		f = fetch.Default(&fetch.Config{Timeout: e.timeout, Transport: transport})
+	}
+	if e.fetchers == nil {
+		e.fetchers = make(map[string]*fetch.Fetcher)
+	}
+	e.fetchers[name] = f
+	return f
+}
+
+// meterFetch accounts the response body of do to the provider if the
+// bandwidth accounting is enabled.
+func (e *Engine) meterFetch(provider mt.Provider, do func() (*http.Response, error)) (*http.Response, error) {
+	if e.bandwidth == nil || provider == nil {
+		return do()
+	}
+	if err := e.bandwidth.Allow(provider.Name()); err != nil {
+		return nil, err
+	}
+	resp, err := do()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = e.bandwidth.Body(provider.Name(), resp.Body)
+	return resp, nil
+}
+
+// BandwidthUsage returns bytes downloaded by provider name in the current
+// period, or nil if the bandwidth accounting is disabled.
+func (e *Engine) BandwidthUsage() *bandwidth.Usage {
+	if e.bandwidth == nil {
+		return nil
 	}
-	return e.fetcher.Fetch(url)
+	usage := e.bandwidth.Usage()
+	return &usage
 }
@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/common/bandwidth"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+func TestEngine_FetchWithoutProvider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	meter := bandwidth.New(time.Hour, 0)
+	e := New(nil, time.Second, WithBandwidthMeter(meter))
+
+	// the default fetcher is used, and the content is not metered.
+	resp, err := e.Fetch(srv.URL, nil)
+	require.NoError(t, err)
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Equal(t, "ok", string(data))
+	assert.Zero(t, meter.Usage().Total)
+}
+
+// pageProvider is a provider without custom fetchers.
+type pageProvider struct{ mt.MovieProvider }
+
+func TestEngine_FetchWithHostOverrides(t *testing.T) {
+	var host string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	u, err := url.Parse(srv.URL)
+	require.NoError(t, err)
+	e := New(nil, time.Second, WithHostOverrides(map[string][]*mt.HostOverride{
+		"fake": {{Hostname: "images.fake.invalid", IPs: []string{u.Hostname()}, Host: "cdn.fake.invalid"}},
+	}))
+
+	// resources are fetched the same way as pages of the provider.
+	p := &pageProvider{fake.New()}
+	resp, err := e.Fetch("http://images.fake.invalid:"+u.Port()+"/cover.jpg", p)
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, "cdn.fake.invalid", host)
+	assert.Same(t, e.providerFetcher(p), e.providerFetcher(p))
+
+	// other providers are not overridden.
+	assert.Same(t, e.fetcher, e.providerFetcher(&pageProvider{&benchProvider{Fake: fake.New(), name: "Other"}}))
+}
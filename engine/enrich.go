@@ -0,0 +1,68 @@
+package engine
+
+import (
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// enrichedColumns are the DB columns of the fields enriched.
+var enrichedColumns = map[mt.Field][]string{
+	mt.PreviewVideoField:  {"preview_video_url", "preview_video_hls_url"},
+	mt.PreviewImagesField: {"preview_images"},
+}
+
+// EnrichMovieInfo resolves the lazy fields of the movie info, all fields
+// if none given, and stores them. The movie info is scraped first if not
+// stored yet, and returned as is if the provider doesn't support it.
+func (e *Engine) EnrichMovieInfo(name, id string, fields ...mt.Field) (*model.MovieInfo, error) {
+	provider, err := e.GetMovieProviderByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if id = provider.NormalizeMovieID(id); id == "" {
+		return nil, mt.ErrInvalidID
+	}
+	if _, ok := provider.(mt.MovieEnricher); !ok {
+		return e.getMovieInfoByProviderID(provider, id, true)
+	}
+	if _, err = e.getMovieInfoByProviderID(provider, id, true); err != nil {
+		return nil, err
+	}
+	// enrich the stored info, rather than the returned one, which is
+	// post-processed.
+	info, err := e.getMovieInfoFromDB(provider, id)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		fields = []mt.Field{mt.PreviewVideoField, mt.PreviewImagesField}
+	}
+	var columns []string
+	for _, field := range fields {
+		columns = append(columns, enrichedColumns[field]...)
+	}
+	if len(columns) == 0 {
+		return nil, mt.ErrInvalidField
+	}
+	startTime := time.Now()
+	e.emitProvider(model.MovieKind, id, provider.Name(), time.Time{}, 0, nil)
+	instance, release := e.acquireMovieProvider(provider)
+	err = instance.(mt.MovieEnricher).EnrichMovieInfo(info, fields...)
+	release()
+	if err == nil {
+		e.emitFields(model.MovieKind, id, provider.Name(), info, columns...)
+	}
+	e.emitProvider(model.MovieKind, id, provider.Name(), startTime, 1, err)
+	if err != nil {
+		return nil, err
+	}
+	if err = e.db.Model(&model.MovieInfo{}).
+		Where("provider = ? AND id = ?", info.Provider, info.ID).
+		Select(columns).
+		Updates(info).Error; err != nil {
+		return nil, err
+	}
+	return e.getMovieInfoByProviderID(provider, id, true)
+}
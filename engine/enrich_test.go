@@ -0,0 +1,54 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+	"github.com/metatube-community/metatube-sdk-go/provider/gfriends"
+)
+
+// enricherProvider resolves preview images on demand only.
+type enricherProvider struct {
+	*fake.Fake
+	fields []mt.Field
+}
+
+func (p *enricherProvider) SetLazyFields(fields ...mt.Field) { p.fields = fields }
+
+func (p *enricherProvider) EnrichMovieInfo(info *model.MovieInfo, fields ...mt.Field) error {
+	info.Title = "Changed"         // not stored.
+	info.PreviewVideoURL = "video" // not requested.
+	info.PreviewImages = []string{"image-1", "image-2"}
+	return nil
+}
+
+func TestEngine_EnrichMovieInfo(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.lazyFields = []mt.Field{mt.PreviewImagesField}
+	p := &enricherProvider{Fake: fake.New()}
+	e.setupProvider(p, e.timeout)
+	assert.Equal(t, e.lazyFields, p.fields)
+
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": p}
+	e.actorProviders = map[string]mt.ActorProvider{strings.ToUpper(gfriends.Name): fake.New()}
+
+	_, err := e.EnrichMovieInfo(fake.Name, "FAKE-001", "unknown")
+	assert.ErrorIs(t, err, mt.ErrInvalidField)
+
+	info, err := e.EnrichMovieInfo(fake.Name, "FAKE-001", mt.PreviewImagesField)
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{"image-1", "image-2"}, []string(info.PreviewImages))
+		assert.NotEqual(t, "Changed", info.Title)
+		assert.Empty(t, info.PreviewVideoURL)
+	}
+
+	stored, err := e.getMovieInfoFromDB(p, "FAKE-001")
+	if assert.NoError(t, err) {
+		assert.Len(t, stored.PreviewImages, 2)
+	}
+}
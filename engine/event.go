@@ -0,0 +1,146 @@
+package engine
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// EventKind is the kind of scrape events.
+type EventKind string
+
+// Kinds of scrape events.
+const (
+	// ProviderStarted is emitted when a provider starts to search or
+	// scrape.
+	ProviderStarted EventKind = "provider_started"
+	// ProviderFinished is emitted when a provider succeeds, with the
+	// number of results, i.e., one of infos.
+	ProviderFinished EventKind = "provider_finished"
+	// ProviderFailed is emitted when a provider fails, with the error.
+	ProviderFailed EventKind = "provider_failed"
+	// FieldResolved is emitted for each non-empty field of infos scraped
+	// or enriched, before the provider finishes.
+	FieldResolved EventKind = "field_resolved"
+)
+
+// Event is the progress of searches and scrapes, e.g., of aggregated
+// searches of all providers.
+type Event struct {
+	Kind EventKind
+	// Type is the kind of records, i.e., model.MovieKind or ActorKind.
+	Type string
+	// Key is the keyword of searches, or the ID of infos.
+	Key      string
+	Provider string
+	// Field is the JSON name of the field resolved.
+	Field string
+	// Results is the number of results of finished providers.
+	Results int
+	Error   error
+	Elapsed time.Duration
+	Time    time.Time
+}
+
+// EventBus delivers events to in-process subscribers, e.g., GUI wrappers
+// showing real-time progress. Events are dropped for subscribers which
+// are not keeping up, so that scrapes are never blocked.
+type EventBus struct {
+	mu   sync.RWMutex
+	subs map[chan *Event]struct{}
+}
+
+// Subscribe subscribes to events with the buffer size, the channel is
+// closed by cancel.
+func (b *EventBus) Subscribe(buffer int) (events <-chan *Event, cancel func()) {
+	ch := make(chan *Event, buffer)
+	b.mu.Lock()
+	if b.subs == nil {
+		b.subs = make(map[chan *Event]struct{})
+	}
+	b.subs[ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			delete(b.subs, ch)
+			b.mu.Unlock()
+			close(ch)
+		})
+	}
+}
+
+// Active reports whether there are any subscribers, so that events
+// expensive to build are skipped if not.
+func (b *EventBus) Active() bool {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return len(b.subs) > 0
+}
+
+// Publish delivers the event to subscribers without blocking.
+func (b *EventBus) Publish(event *Event) {
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for ch := range b.subs {
+		select {
+		case ch <- event:
+		default: // dropped.
+		}
+	}
+}
+
+// Events returns the event bus of the engine.
+func (e *Engine) Events() *EventBus { return &e.events }
+
+// emitProvider emits ProviderStarted if started is zero, otherwise the
+// result of the provider by the error.
+func (e *Engine) emitProvider(typ, key, provider string, started time.Time, results int, err error) {
+	if !e.events.Active() {
+		return
+	}
+	event := &Event{Type: typ, Key: key, Provider: provider}
+	switch {
+	case started.IsZero():
+		event.Kind = ProviderStarted
+	case err != nil:
+		event.Kind, event.Error, event.Elapsed = ProviderFailed, err, time.Since(started)
+	default:
+		event.Kind, event.Results, event.Elapsed = ProviderFinished, results, time.Since(started)
+	}
+	e.events.Publish(event)
+}
+
+// emitFields emits FieldResolved for each non-empty field of the info,
+// or the fields by JSON names if any.
+func (e *Engine) emitFields(typ, key, provider string, info any, fields ...string) {
+	if !e.events.Active() {
+		return
+	}
+	v := reflect.ValueOf(info).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous || !f.IsExported() || name == "-" || name == "" ||
+			v.Field(i).IsZero() || (len(fields) > 0 && !slices.Contains(fields, name)) {
+			continue
+		}
+		if v.Field(i).Kind() == reflect.Slice && v.Field(i).Len() == 0 {
+			continue
+		}
+		e.events.Publish(&Event{
+			Kind:     FieldResolved,
+			Type:     typ,
+			Key:      key,
+			Provider: provider,
+			Field:    name,
+		})
+	}
+}
@@ -0,0 +1,89 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+// drainEvents returns the events delivered so far.
+func drainEvents(events <-chan *Event) (drained []*Event) {
+	for {
+		select {
+		case event := <-events:
+			drained = append(drained, event)
+		default:
+			return
+		}
+	}
+}
+
+func TestEngine_Events(t *testing.T) {
+	e := newBenchEngine(t, 3)
+	events, cancel := e.Events().Subscribe(100)
+	defer cancel()
+
+	_, err := e.SearchMovieAll("FAKE-001", false)
+	require.NoError(t, err)
+	kinds := make(map[string][]EventKind)
+	for _, event := range drainEvents(events) {
+		assert.Equal(t, model.MovieKind, event.Type)
+		assert.Equal(t, "FAKE-001", event.Key)
+		kinds[event.Provider] = append(kinds[event.Provider], event.Kind)
+	}
+	assert.Len(t, kinds, 3)
+	for _, k := range kinds {
+		assert.Equal(t, []EventKind{ProviderStarted, ProviderFinished}, k)
+	}
+
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": fake.New()}
+	_, err = e.GetMovieInfoByProviderID(fake.Name, "FAKE-001", false)
+	require.NoError(t, err)
+	drained := drainEvents(events)
+	require.Greater(t, len(drained), 2)
+	assert.Equal(t, ProviderStarted, drained[0].Kind)
+	assert.Equal(t, ProviderFinished, drained[len(drained)-1].Kind)
+	var fields []string
+	for _, event := range drained[1 : len(drained)-1] {
+		assert.Equal(t, FieldResolved, event.Kind)
+		fields = append(fields, event.Field)
+	}
+	assert.Contains(t, fields, "title")
+	assert.NotContains(t, fields, "extra")
+
+	_, err = e.GetMovieInfoByProviderID(fake.Name, "unknown", false)
+	require.Error(t, err)
+	drained = drainEvents(events)
+	if assert.Len(t, drained, 2) {
+		assert.Equal(t, ProviderFailed, drained[1].Kind)
+		assert.Error(t, drained[1].Error)
+	}
+}
+
+func TestEventBus(t *testing.T) {
+	bus := &EventBus{}
+	assert.False(t, bus.Active())
+	slow, cancelSlow := bus.Subscribe(1)
+	fast, cancelFast := bus.Subscribe(10)
+	assert.True(t, bus.Active())
+
+	for i := 0; i < 3; i++ {
+		bus.Publish(&Event{Kind: ProviderStarted})
+	}
+	assert.Len(t, drainEvents(slow), 1, "events are dropped if not keeping up")
+	assert.Len(t, drainEvents(fast), 3)
+
+	cancelSlow()
+	cancelSlow() // idempotent.
+	_, ok := <-slow
+	assert.False(t, ok)
+	bus.Publish(&Event{Kind: ProviderStarted})
+	assert.Len(t, drainEvents(fast), 1)
+	cancelFast()
+	assert.False(t, bus.Active())
+}
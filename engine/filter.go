@@ -0,0 +1,186 @@
+package engine
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/metatube-community/metatube-sdk-go/common/studio"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+var ErrFilteredOut = errors.New(http.StatusForbidden, "filtered out")
+
+// Filters are the content filters of results and infos configured by the
+// operator, matched items are dropped by Block or flagged by Flag, and if
+// Allow is set, items not allowed are dropped.
+type Filters struct {
+	Block []*FilterRule `json:"block,omitempty"`
+	Flag  []*FilterRule `json:"flag,omitempty"`
+	Allow *FilterRule   `json:"allow,omitempty"`
+}
+
+// FilterRule matches items by any of its conditions, all of which are
+// case-insensitive. As an allowlist, all non-empty conditions must be
+// met instead, except for the ones the items have no data of, e.g.,
+// makers of search results.
+type FilterRule struct {
+	// Name is the flag of the matched items.
+	Name      string   `json:"name,omitempty"`
+	Providers []string `json:"providers,omitempty"`
+	// Makers are matched with makers and labels, other spellings of the
+	// same studios are matched as well, see package studio.
+	Makers []string `json:"makers,omitempty"`
+	// Keywords are matched as substrings of titles and genres.
+	Keywords []string `json:"keywords,omitempty"`
+	// Actors are matched with actor names, and aliases of actor infos.
+	Actors []string `json:"actors,omitempty"`
+}
+
+// filterItem is the common view of results and infos to be filtered.
+type filterItem struct {
+	provider string
+	makers   []string
+	texts    []string // titles and genres.
+	actors   []string
+}
+
+// conditions returns the match results of the non-empty conditions, and
+// whether the item has data to be matched for each of them.
+func (r *FilterRule) conditions(item *filterItem) (matched, known []bool) {
+	add := func(m, k bool) {
+		matched, known = append(matched, m), append(known, k)
+	}
+	if len(r.Providers) > 0 {
+		add(containsFold(r.Providers, item.provider), true)
+	}
+	if len(r.Makers) > 0 {
+		add(slices.ContainsFunc(item.makers, func(s string) bool {
+			return slices.ContainsFunc(r.Makers, func(maker string) bool { return studio.Same(maker, s) })
+		}), len(item.makers) > 0)
+	}
+	if len(r.Keywords) > 0 {
+		add(slices.ContainsFunc(item.texts, func(s string) bool {
+			return slices.ContainsFunc(r.Keywords, func(keyword string) bool {
+				return keyword != "" && strings.Contains(strings.ToLower(s), strings.ToLower(keyword))
+			})
+		}), len(item.texts) > 0)
+	}
+	if len(r.Actors) > 0 {
+		add(slices.ContainsFunc(item.actors, func(s string) bool {
+			return containsFold(r.Actors, s)
+		}), len(item.actors) > 0)
+	}
+	return
+}
+
+func (r *FilterRule) match(item *filterItem) bool {
+	matched, _ := r.conditions(item)
+	return slices.Contains(matched, true)
+}
+
+func (r *FilterRule) allow(item *filterItem) bool {
+	matched, known := r.conditions(item)
+	for i := range matched {
+		if known[i] && !matched[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// apply reports whether the item is dropped, or returns the flags of it.
+func (f *Filters) apply(item *filterItem) (flags []string, dropped bool) {
+	if f == nil {
+		return nil, false
+	}
+	if f.Allow != nil && !f.Allow.allow(item) {
+		return nil, true
+	}
+	for _, rule := range f.Block {
+		if rule.match(item) {
+			return nil, true
+		}
+	}
+	for _, rule := range f.Flag {
+		if rule.match(item) && !slices.Contains(flags, rule.Name) {
+			flags = append(flags, rule.Name)
+		}
+	}
+	return flags, false
+}
+
+// filterMovieInfo flags the info, or returns ErrFilteredOut if dropped.
+func (e *Engine) filterMovieInfo(info *model.MovieInfo) error {
+	flags, dropped := e.filters.apply(&filterItem{
+		provider: info.Provider,
+		makers:   nonEmpty(info.Maker, info.Label),
+		texts:    append(nonEmpty(info.Title), info.Genres...),
+		actors:   info.Actors,
+	})
+	if dropped {
+		return ErrFilteredOut
+	}
+	info.Flags = flags
+	return nil
+}
+
+// filterMovieResults flags the results, and drops the filtered ones.
+func (e *Engine) filterMovieResults(results []*model.MovieSearchResult) []*model.MovieSearchResult {
+	if e.filters == nil {
+		return results
+	}
+	return slices.DeleteFunc(results, func(result *model.MovieSearchResult) bool {
+		flags, dropped := e.filters.apply(&filterItem{
+			provider: result.Provider,
+			texts:    nonEmpty(result.Title),
+			actors:   result.Actors,
+		})
+		result.Flags = flags
+		return dropped
+	})
+}
+
+// filterActorInfo flags the info, or returns ErrFilteredOut if dropped.
+func (e *Engine) filterActorInfo(info *model.ActorInfo) error {
+	flags, dropped := e.filters.apply(&filterItem{
+		provider: info.Provider,
+		actors:   append(nonEmpty(info.Name), info.Aliases...),
+	})
+	if dropped {
+		return ErrFilteredOut
+	}
+	info.Flags = flags
+	return nil
+}
+
+// filterActorResults flags the results, and drops the filtered ones.
+func (e *Engine) filterActorResults(results []*model.ActorSearchResult) []*model.ActorSearchResult {
+	if e.filters == nil {
+		return results
+	}
+	return slices.DeleteFunc(results, func(result *model.ActorSearchResult) bool {
+		flags, dropped := e.filters.apply(&filterItem{
+			provider: result.Provider,
+			actors:   append(nonEmpty(result.Name), result.Aliases...),
+		})
+		result.Flags = flags
+		return dropped
+	})
+}
+
+func containsFold(list []string, s string) bool {
+	return slices.ContainsFunc(list, func(v string) bool {
+		return strings.EqualFold(v, s)
+	})
+}
+
+func nonEmpty(s ...string) (values []string) {
+	for _, v := range s {
+		if v != "" {
+			values = append(values, v)
+		}
+	}
+	return
+}
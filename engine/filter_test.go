@@ -0,0 +1,95 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func TestFilterRule_Makers(t *testing.T) {
+	rule := &FilterRule{Makers: []string{"S1 NO.1 STYLE", "Indie"}}
+	assert.True(t, rule.match(&filterItem{makers: []string{"エスワン ナンバーワンスタイル"}}))
+	assert.True(t, rule.match(&filterItem{makers: []string{"indie"}}))
+	assert.False(t, rule.match(&filterItem{makers: []string{"MOODYZ"}}))
+	assert.True(t, rule.allow(&filterItem{}), "unknown makers are allowed")
+}
+
+func TestFilters_Apply(t *testing.T) {
+	filters := &Filters{
+		Block: []*FilterRule{{Providers: []string{"blocked"}}},
+		Flag: []*FilterRule{
+			{Name: "vr", Keywords: []string{"VR"}},
+			{Name: "vr", Actors: []string{"Actor A"}},
+			{Name: "indie", Makers: []string{"Indie"}},
+		},
+	}
+	for _, unit := range []struct {
+		item    *filterItem
+		flags   []string
+		dropped bool
+	}{
+		{&filterItem{provider: "BLOCKED", texts: []string{"VR"}}, nil, true},
+		{&filterItem{provider: "ok"}, nil, false},
+		{&filterItem{provider: "ok", texts: []string{"Some vr Title"}, actors: []string{"actor a"}}, []string{"vr"}, false},
+		{&filterItem{provider: "ok", texts: []string{"VR"}, makers: []string{"INDIE"}}, []string{"vr", "indie"}, false},
+	} {
+		flags, dropped := filters.apply(unit.item)
+		assert.Equal(t, unit.flags, flags)
+		assert.Equal(t, unit.dropped, dropped)
+	}
+
+	var none *Filters
+	flags, dropped := none.apply(&filterItem{provider: "blocked"})
+	assert.Nil(t, flags)
+	assert.False(t, dropped)
+}
+
+func TestFilters_Allow(t *testing.T) {
+	filters := &Filters{Allow: &FilterRule{Providers: []string{"ok"}, Makers: []string{"Indie"}}}
+	for _, unit := range []struct {
+		item    *filterItem
+		dropped bool
+	}{
+		{&filterItem{provider: "ok", makers: []string{"indie"}}, false},
+		{&filterItem{provider: "ok"}, false}, // makers unknown, e.g., results.
+		{&filterItem{provider: "ok", makers: []string{"MOODYZ"}}, true},
+		{&filterItem{provider: "other", makers: []string{"Indie"}}, true},
+	} {
+		_, dropped := filters.apply(unit.item)
+		assert.Equal(t, unit.dropped, dropped, unit.item)
+	}
+}
+
+func TestEngine_FilterResults(t *testing.T) {
+	e := &Engine{filters: &Filters{
+		Block: []*FilterRule{{Actors: []string{"Blocked Actor"}}},
+		Flag:  []*FilterRule{{Name: "flagged", Keywords: []string{"keyword"}}},
+	}}
+
+	movies := e.filterMovieResults([]*model.MovieSearchResult{
+		{ID: "1", Title: "Title", Actors: []string{"blocked actor"}},
+		{ID: "2", Title: "Title with Keyword"},
+		{ID: "3", Title: "Title"},
+	})
+	if assert.Len(t, movies, 2) {
+		assert.Equal(t, []string{"flagged"}, movies[0].Flags)
+		assert.Empty(t, movies[1].Flags)
+	}
+
+	actors := e.filterActorResults([]*model.ActorSearchResult{
+		{ID: "1", Name: "Actor", Aliases: []string{"Blocked Actor"}},
+		{ID: "2", Name: "Actor"},
+	})
+	if assert.Len(t, actors, 1) {
+		assert.Equal(t, "2", actors[0].ID)
+	}
+
+	info := &model.MovieInfo{Title: "Keyword", Actors: []string{"Actor"}}
+	assert.NoError(t, e.filterMovieInfo(info))
+	assert.Equal(t, []string{"flagged"}, info.Flags)
+	info.Actors = append(info.Actors, "Blocked Actor")
+	assert.Equal(t, ErrFilteredOut, e.filterMovieInfo(info))
+	assert.Equal(t, ErrFilteredOut, e.filterActorInfo(&model.ActorInfo{Name: "Blocked Actor"}))
+}
@@ -0,0 +1,218 @@
+package engine
+
+import (
+	"fmt"
+	"math/bits"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// Fingerprint matching parameters.
+const (
+	maxVideoHashDistance   = 8
+	maxDurationDeviation   = 60 // in seconds
+	defaultFingerprintHits = 20
+)
+
+var ErrNoFingerprints = errors.New(http.StatusBadRequest, "no fingerprints")
+
+// Fingerprints are the fingerprints of a movie file.
+type Fingerprints struct {
+	// Duration of the file in seconds.
+	Duration int `json:"duration"`
+	// OSHash is the OpenSubtitles hash of the file.
+	OSHash string `json:"oshash"`
+	// PHash is the perceptual hash of the video.
+	PHash string `json:"phash"`
+}
+
+func (fp *Fingerprints) records(provider, id string) (records []*model.MovieFingerprint) {
+	for algorithm, hash := range map[string]string{
+		model.OSHashAlgorithm: fp.OSHash,
+		model.PHashAlgorithm:  fp.PHash,
+	} {
+		if hash = strings.ToLower(strings.TrimSpace(hash)); hash != "" {
+			record := &model.MovieFingerprint{
+				Algorithm: algorithm,
+				Hash:      hash,
+				Provider:  provider,
+				ID:        id,
+				Duration:  fp.Duration,
+			}
+			if v, err := parseVideoHash(hash); err == nil && algorithm == model.PHashAlgorithm {
+				record.HashBuckets = model.NewHashBuckets(v)
+			}
+			records = append(records, record)
+		}
+	}
+	return
+}
+
+// FingerprintMatch is a movie matched by fingerprints.
+type FingerprintMatch struct {
+	*model.MovieSearchResult
+	// Confidence of the match in range [0, 1].
+	Confidence float64 `json:"confidence"`
+	// MatchedBy lists what the movie is matched by.
+	MatchedBy []string `json:"matched_by"`
+}
+
+// SubmitMovieFingerprints associates the fingerprints of a file with the
+// movie, so that later files with the same fingerprints can be matched.
+func (e *Engine) SubmitMovieFingerprints(name, id string, fp *Fingerprints) error {
+	info, err := e.GetMovieInfoByProviderID(name, id, true)
+	if err != nil {
+		return err
+	}
+	records := fp.records(info.Provider, info.ID)
+	if len(records) == 0 {
+		return ErrNoFingerprints
+	}
+	return e.db.Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(records).Error
+}
+
+// MatchMovieFingerprints matches fingerprints of a file against known
+// movies. Hashes are matched against submitted fingerprints, and the
+// duration is matched against runtimes of movies in DB, which is a weak
+// hint and only raises confidence of hash matches or fills the results.
+func (e *Engine) MatchMovieFingerprints(fp *Fingerprints) ([]*FingerprintMatch, error) {
+	matches := make(map[string]*FingerprintMatch)
+	add := func(provider, id, by string, confidence float64) {
+		key := provider + ":" + id
+		m, ok := matches[key]
+		if !ok {
+			m = &FingerprintMatch{
+				MovieSearchResult: &model.MovieSearchResult{ID: id, Provider: provider},
+			}
+			matches[key] = m
+		}
+		m.Confidence = max(m.Confidence, confidence)
+		m.MatchedBy = append(m.MatchedBy, by)
+	}
+
+	if hash := strings.ToLower(strings.TrimSpace(fp.OSHash)); hash != "" {
+		var records []*model.MovieFingerprint
+		if err := e.db.
+			Where("algorithm = ? AND hash = ?", model.OSHashAlgorithm, hash).
+			Find(&records).Error; err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			add(record.Provider, record.ID, "oshash", 1.0)
+		}
+	}
+
+	if hash, err := parseVideoHash(fp.PHash); err == nil {
+		var records []*model.MovieFingerprint
+		if err = e.db.
+			Where("algorithm = ?", model.PHashAlgorithm).
+			Where(nearHashBuckets(e.db, hash, maxVideoHashDistance)).
+			Order("provider, id").
+			Find(&records).Error; err != nil {
+			return nil, err
+		}
+		for _, record := range records {
+			other, err := parseVideoHash(record.Hash)
+			if err != nil {
+				continue
+			}
+			if distance := bits.OnesCount64(hash ^ other); distance <= maxVideoHashDistance {
+				add(record.Provider, record.ID, "phash", 0.9-float64(distance)*0.05)
+			}
+		}
+	}
+
+	if fp.Duration > 0 {
+		// raise confidence of hash matches with close durations.
+		for _, m := range matches {
+			var record model.MovieFingerprint
+			if e.db.Where("provider = ? AND id = ?", m.Provider, m.ID).
+				First(&record).Error == nil && record.Duration > 0 &&
+				abs(record.Duration-fp.Duration) <= maxDurationDeviation {
+				m.Confidence = min(m.Confidence+0.1, 1.0)
+				m.MatchedBy = append(m.MatchedBy, "duration")
+			}
+		}
+		// fill results with movies of the closest runtimes.
+		if len(matches) == 0 {
+			var infos []*model.MovieInfo
+			if err := e.db.
+				Where("runtime BETWEEN ? AND ?",
+					(fp.Duration-maxDurationDeviation)/60, (fp.Duration+maxDurationDeviation)/60).
+				Order(clause.Expr{SQL: "ABS(runtime - ?), provider, id", Vars: []any{fp.Duration / 60}}).
+				Limit(defaultFingerprintHits).
+				Find(&infos).Error; err != nil {
+				return nil, err
+			}
+			for _, info := range infos {
+				add(info.Provider, info.ID, "runtime", 0.1)
+			}
+		}
+	}
+
+	results := make([]*FingerprintMatch, 0, len(matches))
+	for _, m := range matches {
+		if provider, err := e.GetMovieProviderByName(m.Provider); err == nil {
+			if info, err := e.getMovieInfoFromDB(provider, m.ID); err == nil {
+				m.MovieSearchResult = info.ToSearchResult()
+			}
+		}
+		results = append(results, m)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Confidence != results[j].Confidence {
+			return results[i].Confidence > results[j].Confidence
+		}
+		return results[i].Provider+results[i].ID < results[j].Provider+results[j].ID
+	})
+	if len(results) > defaultFingerprintHits {
+		results = results[:defaultFingerprintHits]
+	}
+	return results, nil
+}
+
+// nearHashBuckets returns the condition of perceptual hashes within the
+// distance of hash by their buckets, which are candidates to be checked
+// by exact distances. Of hashes within the distance, at least one bucket
+// differs from the one of hash in at most distance/4 bits, so the nearby
+// values of each bucket are looked up by its index.
+func nearHashBuckets(db *gorm.DB, hash uint64, distance int) *gorm.DB {
+	buckets := model.NewHashBuckets(hash).Values()
+	cond := db.Session(&gorm.Session{NewDB: true}).
+		Where("bucket0 IN ?", nearBuckets(buckets[0], distance/4))
+	for i := 1; i < len(buckets); i++ {
+		cond = cond.Or(fmt.Sprintf("bucket%d IN ?", i), nearBuckets(buckets[i], distance/4))
+	}
+	return cond
+}
+
+// nearBuckets returns the 16-bit values within the distance of bucket.
+func nearBuckets(bucket, distance int) (values []int) {
+	for mask := 0; mask <= 0xffff; mask++ {
+		if bits.OnesCount16(uint16(mask)) <= distance {
+			values = append(values, bucket^mask)
+		}
+	}
+	return
+}
+
+func parseVideoHash(s string) (uint64, error) {
+	return strconv.ParseUint(strings.TrimPrefix(strings.TrimSpace(s), "0x"), 16, 64)
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+	return x
+}
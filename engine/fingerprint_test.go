@@ -0,0 +1,121 @@
+package engine
+
+import (
+	"math/bits"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+func TestEngine_MatchMovieFingerprints(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": fake.New()}
+
+	require.NoError(t, e.SubmitMovieFingerprints(fake.Name, "FAKE-001", &Fingerprints{
+		Duration: 61 * 60,
+		OSHash:   " ABCDEF0123456789 ",
+		PHash:    "0xff00ff00ff00ff00",
+	}))
+	require.NoError(t, e.SubmitMovieFingerprints(fake.Name, "FAKE-002", &Fingerprints{
+		Duration: 10 * 60,
+		PHash:    "ff00ff00ff00ff0f",
+	}))
+	assert.ErrorIs(t, e.SubmitMovieFingerprints(fake.Name, "FAKE-003", &Fingerprints{Duration: 60}), ErrNoFingerprints)
+
+	type match struct {
+		ID         string
+		Confidence float64
+		MatchedBy  []string
+	}
+	for _, unit := range []struct {
+		name string
+		fp   *Fingerprints
+		want []match
+	}{
+		{"oshash", &Fingerprints{OSHash: "abcdef0123456789"}, []match{
+			{"FAKE-001", 1.0, []string{"oshash"}},
+		}},
+		{"oshash with duration", &Fingerprints{OSHash: "ABCDEF0123456789", Duration: 60 * 60}, []match{
+			{"FAKE-001", 1.0, []string{"oshash", "duration"}},
+		}},
+		{"phash", &Fingerprints{PHash: "0xff00ff00ff00ff01"}, []match{
+			{"FAKE-001", 0.85, []string{"phash"}},
+			{"FAKE-002", 0.75, []string{"phash"}},
+		}},
+		{"phash with duration", &Fingerprints{PHash: "0xff00ff00ff00ff0e", Duration: 10 * 60}, []match{
+			{"FAKE-002", 0.95, []string{"phash", "duration"}},
+			{"FAKE-001", 0.75, []string{"phash"}},
+		}},
+		{"all", &Fingerprints{OSHash: "abcdef0123456789", PHash: "ff00ff00ff00ff00"}, []match{
+			{"FAKE-001", 1.0, []string{"oshash", "phash"}},
+			{"FAKE-002", 0.7, []string{"phash"}},
+		}},
+		{"far phash", &Fingerprints{PHash: "00ff00ff00ff00ff"}, nil},
+		{"invalid phash", &Fingerprints{PHash: "not a hash"}, nil},
+		{"runtime", &Fingerprints{Duration: 61 * 60}, []match{
+			{"FAKE-001", 0.1, []string{"runtime"}},
+			{"FAKE-002", 0.1, []string{"runtime"}},
+		}},
+		{"none", &Fingerprints{}, nil},
+	} {
+		t.Run(unit.name, func(t *testing.T) {
+			results, err := e.MatchMovieFingerprints(unit.fp)
+			require.NoError(t, err)
+			var got []match
+			for _, result := range results {
+				assert.Equal(t, fake.Name, result.Provider)
+				assert.NotEmpty(t, result.Title, "search result should be filled from DB")
+				got = append(got, match{result.ID, result.Confidence, result.MatchedBy})
+			}
+			if assert.Len(t, got, len(unit.want)) {
+				for i := range got {
+					assert.Equal(t, unit.want[i].ID, got[i].ID)
+					assert.InDelta(t, unit.want[i].Confidence, got[i].Confidence, 1e-9)
+					assert.Equal(t, unit.want[i].MatchedBy, got[i].MatchedBy)
+				}
+			}
+		})
+	}
+}
+
+func TestEngine_MigrateHashBuckets(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": fake.New()}
+	ids, err := e.Migrator().Down(2)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{
+		"20241011000000_add_movie_fingerprint_buckets",
+		"20241012000000_add_movie_image_hash_buckets",
+	}, ids)
+
+	// hashes saved before buckets.
+	var hash uint64 = 0xff00ff00ff00ff00
+	require.NoError(t, e.db.Exec("INSERT INTO movie_fingerprints (algorithm, hash, provider, id, duration) VALUES (?, ?, ?, ?, ?)",
+		model.PHashAlgorithm, "ff00ff00ff00ff00", fake.Name, "FAKE-001", 0).Error)
+	require.NoError(t, e.db.Exec("INSERT INTO movie_image_hashes (provider, id, url, hash) VALUES (?, ?, ?, ?)",
+		fake.Name, "FAKE-001", "https://fake.metatube.invalid/cover.jpg", int64(hash)).Error)
+	_, err = e.Migrator().Up()
+	require.NoError(t, err)
+
+	results, err := e.MatchMovieFingerprints(&Fingerprints{PHash: "ff00ff00ff00ff01"})
+	require.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "FAKE-001", results[0].ID)
+	}
+	var stored model.MovieImageHash
+	require.NoError(t, e.db.First(&stored).Error)
+	assert.Equal(t, model.NewHashBuckets(hash), stored.HashBuckets)
+}
+
+func TestNearBuckets(t *testing.T) {
+	assert.Equal(t, []int{0xff}, nearBuckets(0xff, 0))
+	assert.Len(t, nearBuckets(0xff, 2), 1+16+120)
+	for _, v := range nearBuckets(0xff, 3) {
+		assert.LessOrEqual(t, bits.OnesCount16(uint16(v^0xff)), 3)
+	}
+}
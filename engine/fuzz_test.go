@@ -0,0 +1,57 @@
+package engine
+
+import (
+	"testing"
+	"unicode/utf8"
+
+	"github.com/metatube-community/metatube-sdk-go/common/parser"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+var fuzzSeeds = []string{"", "ABP-030", "abp030", "FC2-PPV-1234567", "010120-001", "n1234", "1000giri-123456", " \t", "中文", "%zz"}
+
+func FuzzNormalizeMovieID(f *testing.F) {
+	var providers []mt.MovieProvider
+	mt.RangeMovieFactory(func(_ string, factory mt.MovieFactory) {
+		providers = append(providers, factory())
+	})
+	for _, s := range fuzzSeeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, provider := range providers {
+			id := provider.NormalizeMovieID(s)
+			if utf8.ValidString(s) && !utf8.ValidString(id) {
+				t.Errorf("%s: NormalizeMovieID(%q) = %q is not valid UTF-8", provider.Name(), s, id)
+			}
+			if again := provider.NormalizeMovieID(id); id != "" && again != id {
+				t.Errorf("%s: NormalizeMovieID(%q) = %q is not stable: %q", provider.Name(), s, id, again)
+			}
+			if searcher, ok := provider.(mt.MovieSearcher); ok {
+				_ = searcher.NormalizeMovieKeyword(s)
+			}
+		}
+		_ = parser.ParseIDToNumber(s)
+	})
+}
+
+func FuzzNormalizeActorID(f *testing.F) {
+	var providers []mt.ActorProvider
+	mt.RangeActorFactory(func(_ string, factory mt.ActorFactory) {
+		providers = append(providers, factory())
+	})
+	for _, s := range fuzzSeeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		for _, provider := range providers {
+			id := provider.NormalizeActorID(s)
+			if utf8.ValidString(s) && !utf8.ValidString(id) {
+				t.Errorf("%s: NormalizeActorID(%q) = %q is not valid UTF-8", provider.Name(), s, id)
+			}
+			if again := provider.NormalizeActorID(id); id != "" && again != id {
+				t.Errorf("%s: NormalizeActorID(%q) = %q is not stable: %q", provider.Name(), s, id, again)
+			}
+		}
+	})
+}
@@ -0,0 +1,17 @@
+package engine
+
+import (
+	"github.com/metatube-community/metatube-sdk-go/common/canonurl"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// canonicalHomepage canonicalizes the homepage of the info, e.g., of
+// tracking params or the `www.` host variant of the base URL, so that
+// infos are stored and cached by the same URL.
+func canonicalHomepage(provider mt.Provider, homepage string) string {
+	var host string
+	if u := provider.URL(); u != nil {
+		host = u.Host
+	}
+	return canonurl.Canonicalize(homepage, host)
+}
@@ -1,10 +1,20 @@
 package engine
 
 import (
+	"bytes"
+	"fmt"
 	"image"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
 
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/httpcache"
 	"github.com/metatube-community/metatube-sdk-go/common/number"
 	R "github.com/metatube-community/metatube-sdk-go/constant"
+	"github.com/metatube-community/metatube-sdk-go/errors"
 	"github.com/metatube-community/metatube-sdk-go/imageutil"
 	"github.com/metatube-community/metatube-sdk-go/imageutil/pigo"
 	"github.com/metatube-community/metatube-sdk-go/model"
@@ -19,6 +29,12 @@ const (
 	defaultMovieBackdropImagePosition = 0.0
 )
 
+// DefaultImageCacheTTL is the lifetime of cached source images.
+const DefaultImageCacheTTL = 7 * 24 * time.Hour
+
+// maxActorImageWorkers limits concurrent image downloads of GetActorImages.
+const maxActorImageWorkers = 8
+
 func (e *Engine) GetActorPrimaryImage(name, id string) (image.Image, error) {
 	info, err := e.GetActorInfoByProviderID(name, id, true)
 	if err != nil {
@@ -30,6 +46,128 @@ func (e *Engine) GetActorPrimaryImage(name, id string) (image.Image, error) {
 	return e.GetImageByURL(e.MustGetActorProviderByName(name), info.Images[0], R.PrimaryImageRatio, defaultActorPrimaryImagePosition, false)
 }
 
+// ActorImage is an actor image with its source and resolution.
+type ActorImage struct {
+	URL      string `json:"url"`
+	Provider string `json:"provider"`
+	Width    int    `json:"width"`
+	Height   int    `json:"height"`
+}
+
+// GetActorImages merges actor images of the given name from all actor
+// providers, visually duplicated images are collapsed into the one with
+// the highest resolution, and the results are ranked by resolution.
+func (e *Engine) GetActorImages(name string) ([]*ActorImage, error) {
+	results, err := e.SearchActorAll(name, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) == 0 {
+		return nil, mt.ErrInfoNotFound
+	}
+
+	type imageJob struct {
+		provider mt.ActorProvider
+		source   string
+		url      string
+	}
+	var (
+		jobs []imageJob
+		seen = make(map[string]struct{})
+	)
+	for _, result := range results {
+		provider, err := e.GetActorProviderByName(result.Provider)
+		if err != nil {
+			e.logger.Warnf("Skip actor images from %s: %v", result.Provider, err)
+			continue
+		}
+		var (
+			urls    = []string(result.Images)
+			sources map[string]string
+		)
+		if info, err := e.GetActorInfoByProviderID(result.Provider, result.ID, true); err == nil {
+			urls, sources = append(urls, info.Images...), info.ImageSources
+		}
+		for _, url := range urls {
+			if _, ok := seen[url]; ok || url == "" {
+				continue
+			}
+			seen[url] = struct{}{}
+			source := provider.Name()
+			if s, ok := sources[url]; ok {
+				source = s
+			}
+			jobs = append(jobs, imageJob{provider, source, url})
+		}
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		images []*ActorImage
+		decode []image.Image
+		queue  = make(chan imageJob)
+	)
+	for i := 0; i < min(maxActorImageWorkers, len(jobs)); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for job := range queue {
+				img, err := e.getImageByURL(job.provider, job.url)
+				if err != nil {
+					continue // ignore error
+				}
+				bounds := img.Bounds()
+				mu.Lock()
+				images = append(images, &ActorImage{
+					URL:      job.url,
+					Provider: job.source,
+					Width:    bounds.Dx(),
+					Height:   bounds.Dy(),
+				})
+				decode = append(decode, img)
+				mu.Unlock()
+			}
+		}()
+	}
+	for _, job := range jobs {
+		queue <- job
+	}
+	close(queue)
+	wg.Wait()
+
+	// sort by resolution first, so the first one of duplicates is kept.
+	indexes := make([]int, len(images))
+	for i := range indexes {
+		indexes[i] = i
+	}
+	sort.SliceStable(indexes, func(i, j int) bool {
+		a, b := images[indexes[i]], images[indexes[j]]
+		return a.Width*a.Height > b.Width*b.Height
+	})
+	var (
+		kept   []int
+		ranked = make([]*ActorImage, 0, len(images))
+	)
+	for _, i := range indexes {
+		duplicated := false
+		for _, j := range kept {
+			if imageutil.PerceptionHashDistance(decode[i], decode[j]) <= maxCoverHashDistance {
+				duplicated = true
+				break
+			}
+		}
+		if !duplicated {
+			kept = append(kept, i)
+			ranked = append(ranked, images[i])
+		}
+	}
+	if len(ranked) == 0 {
+		return nil, mt.ErrImageNotFound
+	}
+	return ranked, nil
+}
+
 func (e *Engine) GetMoviePrimaryImage(name, id string, ratio, pos float64) (image.Image, error) {
 	url, info, err := e.getPreferredMovieImageURLAndInfo(name, id, true)
 	if err != nil {
@@ -46,6 +184,16 @@ func (e *Engine) GetMoviePrimaryImage(name, id string, ratio, pos float64) (imag
 	return e.GetImageByURL(e.MustGetMovieProviderByName(name), url, ratio, pos, auto)
 }
 
+// GetMovieSafePrimaryImage gets movie primary image cropped to the region
+// of the face and upper body only, which excludes most explicit content.
+func (e *Engine) GetMovieSafePrimaryImage(name, id string, ratio float64) (image.Image, error) {
+	url, _, err := e.getPreferredMovieImageURLAndInfo(name, id, true)
+	if err != nil {
+		return nil, err
+	}
+	return e.GetSafeImageByURL(e.MustGetMovieProviderByName(name), url, ratio)
+}
+
 func (e *Engine) GetMovieThumbImage(name, id string) (image.Image, error) {
 	url, _, err := e.getPreferredMovieImageURLAndInfo(name, id, false)
 	if err != nil {
@@ -62,6 +210,30 @@ func (e *Engine) GetMovieBackdropImage(name, id string) (image.Image, error) {
 	return e.GetImageByURL(e.MustGetMovieProviderByName(name), url, R.BackdropImageRatio, defaultMovieBackdropImagePosition, false)
 }
 
+// GetMovieScreenshotImage gets the screenshot of the index, i.e., the
+// 1-based position in the gallery, of the movie uncropped.
+func (e *Engine) GetMovieScreenshotImage(name, id string, index int) (image.Image, error) {
+	info, err := e.GetMovieInfoByProviderID(name, id, true)
+	if err != nil {
+		return nil, err
+	}
+	artwork := info.Artwork(model.ScreenshotArtwork, index)
+	if artwork == nil {
+		return nil, mt.ErrImageNotFound
+	}
+	return e.GetImageByURL(e.MustGetMovieProviderByName(name), artwork.URL, R.BackdropImageRatio, defaultMovieBackdropImagePosition, false)
+}
+
+// GetMovieArtworks returns the labeled images of the movie, see
+// model.MovieInfo.Artworks.
+func (e *Engine) GetMovieArtworks(name, id string) ([]*model.Artwork, error) {
+	info, err := e.GetMovieInfoByProviderID(name, id, true)
+	if err != nil {
+		return nil, err
+	}
+	return info.Artworks(), nil
+}
+
 func (e *Engine) GetImageByURL(provider mt.Provider, url string, ratio, pos float64, auto bool) (img image.Image, err error) {
 	if img, err = e.getImageByURL(provider, url); err != nil {
 		return
@@ -72,14 +244,72 @@ func (e *Engine) GetImageByURL(provider mt.Provider, url string, ratio, pos floa
 	return imageutil.CropImagePosition(img, ratio, pos), nil
 }
 
+// GetSafeImageByURL gets image by url and crops it in the safe mode.
+func (e *Engine) GetSafeImageByURL(provider mt.Provider, url string, ratio float64) (image.Image, error) {
+	img, err := e.getImageByURL(provider, url)
+	if err != nil {
+		return nil, err
+	}
+	if ratio < 0 /* default primary ratio */ {
+		ratio = R.PrimaryImageRatio
+	}
+	return pigo.SafeCrop(img, ratio), nil
+}
+
 func (e *Engine) getImageByURL(provider mt.Provider, url string) (img image.Image, err error) {
+	if e.imageCache == nil {
+		resp, err := e.Fetch(url, provider)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		img, _, err = image.Decode(resp.Body)
+		return img, err
+	}
+	data, err := e.getImageData(provider, url)
+	if err != nil {
+		return nil, err
+	}
+	img, _, err = image.Decode(bytes.NewReader(data))
+	return
+}
+
+// getImageData returns the encoded image of the URL, which is cached if
+// the image cache is enabled.
+func (e *Engine) getImageData(provider mt.Provider, url string) ([]byte, error) {
+	if e.imageCache == nil {
+		return e.fetchImageData(provider, url)
+	}
+	return httpcache.Load(e.imageCache, url, func() ([]byte, error) {
+		return e.fetchImageData(provider, url)
+	})
+}
+
+// refreshImageData fetches the image of the URL bypassing the image cache,
+// e.g., of images replaced at the same URL, and replaces the cached one.
+func (e *Engine) refreshImageData(provider mt.Provider, url string) ([]byte, error) {
+	data, err := e.fetchImageData(provider, url)
+	if err == nil && e.imageCache != nil {
+		_ = e.imageCache.Set(url, data) // ignore error
+	}
+	return data, err
+}
+
+func (e *Engine) fetchImageData(provider mt.Provider, url string) ([]byte, error) {
 	resp, err := e.Fetch(url, provider)
 	if err != nil {
-		return
+		return nil, err
 	}
 	defer resp.Body.Close()
-	img, _, err = image.Decode(resp.Body)
-	return
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	// cache decodable images only.
+	if _, _, err = image.DecodeConfig(bytes.NewReader(data)); err != nil {
+		return nil, err
+	}
+	return data, nil
 }
 
 func (e *Engine) getPreferredMovieImageURLAndInfo(name, id string, thumb bool) (url string, info *model.MovieInfo, err error) {
@@ -87,11 +317,123 @@ func (e *Engine) getPreferredMovieImageURLAndInfo(name, id string, thumb bool) (
 	if err != nil {
 		return
 	}
-	url = info.CoverURL
-	if thumb && info.BigThumbURL != "" /* big thumb > cover */ {
-		url = info.BigThumbURL
-	} else if !thumb && info.BigCoverURL != "" /* big cover > cover */ {
-		url = info.BigCoverURL
+	// big thumb > cover for posters, and big cover > cover for others.
+	typ := model.BackdropArtwork
+	if thumb {
+		typ = model.PosterArtwork
+	}
+	artwork := info.Artwork(typ, 0)
+	if artwork == nil {
+		err = mt.ErrImageNotFound // placeholders, e.g., of "now printing".
+		return
+	}
+	url = artwork.URL
+	if cover := info.CoverURL; !thumb && cover != url && !info.Source.Placeholder("cover_url") {
+		// big covers are not always larger, e.g., resized thumbnails of
+		// some providers, so the larger one of covers is preferred.
+		url = e.largestImageURL(e.MustGetMovieProviderByName(name), url, cover)
 	}
 	return
 }
+
+// largestImageURL returns the URL of the largest image by probing, or the
+// first one if none can be probed.
+func (e *Engine) largestImageURL(provider mt.Provider, urls ...string) string {
+	if ranked := e.RankImagesByResolution(provider, urls); len(ranked) > 0 {
+		return ranked[0].URL
+	}
+	return urls[0]
+}
+
+// ImageConfig is the dimensions and format of an image.
+type ImageConfig struct {
+	URL    string `json:"url"`
+	Width  int    `json:"width"`
+	Height int    `json:"height"`
+	Format string `json:"format"`
+}
+
+// ProbeImageByURL gets the dimensions and format of the image by reading
+// only its header, the Referer is set to the provider site which images
+// are usually protected by.
+func (e *Engine) ProbeImageByURL(provider mt.Provider, url string) (*ImageConfig, error) {
+	var r io.Reader
+	if data, ok := e.imageCacheGet(url); ok {
+		r = bytes.NewReader(data)
+	} else {
+		resp, err := e.fetchImageHeader(provider, url)
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		r = resp.Body
+	}
+	cfg, format, err := imageutil.Probe(r)
+	if err != nil {
+		return nil, err
+	}
+	return &ImageConfig{
+		URL:    url,
+		Width:  cfg.Width,
+		Height: cfg.Height,
+		Format: format,
+	}, nil
+}
+
+// RankImagesByResolution probes images concurrently and ranks them by
+// resolution, images failed to probe are dropped.
+func (e *Engine) RankImagesByResolution(provider mt.Provider, urls []string) []*ImageConfig {
+	var (
+		wg      sync.WaitGroup
+		configs = make([]*ImageConfig, len(urls))
+	)
+	for i, url := range urls {
+		wg.Add(1)
+		go func(i int, url string) {
+			defer wg.Done()
+			configs[i], _ = e.ProbeImageByURL(provider, url)
+		}(i, url)
+	}
+	wg.Wait()
+
+	ranked := configs[:0]
+	for _, cfg := range configs {
+		if cfg != nil {
+			ranked = append(ranked, cfg)
+		}
+	}
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return ranked[i].Width*ranked[i].Height > ranked[j].Width*ranked[j].Height
+	})
+	return ranked
+}
+
+func (e *Engine) imageCacheGet(url string) ([]byte, bool) {
+	if e.imageCache == nil {
+		return nil, false
+	}
+	return e.imageCache.Get(url)
+}
+
+// fetchImageHeader requests the leading bytes of the image by range, the
+// whole image might still be responded if range is not supported, so the
+// body should be read limitedly.
+func (e *Engine) fetchImageHeader(provider mt.Provider, url string) (*http.Response, error) {
+	if _, ok := provider.(mt.Fetcher); ok {
+		return e.Fetch(url, provider)
+	}
+	resp, err := e.meterFetch(provider, func() (*http.Response, error) {
+		return e.providerFetcher(provider).Get(url,
+			fetch.WithReferer(provider.URL().String()),
+			fetch.WithHeader("Range", fmt.Sprintf("bytes=0-%d", imageutil.ProbeSize-1)),
+			fetch.WithRaiseForStatus(false))
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		defer resp.Body.Close()
+		return nil, errors.FromCode(resp.StatusCode)
+	}
+	return resp, nil
+}
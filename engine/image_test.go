@@ -0,0 +1,202 @@
+package engine
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+	"github.com/metatube-community/metatube-sdk-go/provider/gfriends"
+)
+
+// galleryFake is a renamed fake actor provider of the images, which are
+// of a circle or a bar by their paths, e.g., `/circle.jpg`, and
+// sized by the `w` query, so that images of the same path are visually
+// the same.
+type galleryFake struct {
+	*fake.Fake
+	name    string
+	images  []string
+	sources map[string]string
+}
+
+func (f *galleryFake) Name() string { return f.name }
+
+func (f *galleryFake) info() *model.ActorInfo {
+	return &model.ActorInfo{
+		ID:           "1",
+		Name:         "Fake Actor",
+		Provider:     f.name,
+		Homepage:     "https://fake.metatube.invalid/actors/1",
+		Images:       f.images,
+		ImageSources: f.sources,
+	}
+}
+
+func (f *galleryFake) GetActorInfoByID(string) (*model.ActorInfo, error) { return f.info(), nil }
+
+func (f *galleryFake) SearchActor(string) ([]*model.ActorSearchResult, error) {
+	result := f.info().ToSearchResult()
+	result.Images = nil // merged from infos.
+	return []*model.ActorSearchResult{result}, nil
+}
+
+func (f *galleryFake) Fetch(rawURL string) (*http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || path.Ext(u.Path) != ".jpg" {
+		return nil, mt.ErrImageNotFound
+	}
+	width, _ := strconv.Atoi(u.Query().Get("w"))
+	height := width * 4 / 3
+	img := image.NewGray(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			white := x > width*2/3
+			if path.Base(u.Path) == "circle.jpg" {
+				dx, dy := x-width/3, y-height/3
+				white = dx*dx+dy*dy < width*width/16
+			}
+			if white {
+				img.SetGray(x, y, color.Gray{Y: 0xff})
+			}
+		}
+	}
+	buf := &bytes.Buffer{}
+	if err = jpeg.Encode(buf, img, nil); err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"image/jpeg"}},
+		Body:       io.NopCloser(buf),
+	}, nil
+}
+
+func TestEngine_GetActorImages(t *testing.T) {
+	const base = "https://fake.metatube.invalid/images/"
+	e := newBenchEngine(t, 0)
+	// images of infos are always injected from Gfriends.
+	noImages := &galleryFake{Fake: fake.New(), name: gfriends.Name}
+	e.actorProviders = map[string]mt.ActorProvider{
+		"GFRIENDS": noImages,
+		"A": &galleryFake{Fake: fake.New(), name: "A", images: []string{
+			base + "circle.jpg?w=200",
+			base + "bar.jpg?w=300",
+			base + "missing.png",
+		}},
+		"B": &galleryFake{Fake: fake.New(), name: "B", images: []string{
+			base + "circle.jpg?w=400",
+			base + "bar.jpg?w=300", // the same URL.
+		}, sources: map[string]string{base + "circle.jpg?w=400": "Gallery"}},
+	}
+
+	images, err := e.GetActorImages("Fake Actor")
+	require.NoError(t, err)
+	if assert.Len(t, images, 2) {
+		// duplicates are collapsed into the largest, ranked by resolution.
+		assert.Equal(t, &ActorImage{URL: base + "circle.jpg?w=400", Provider: "Gallery", Width: 400, Height: 533}, images[0])
+		assert.Equal(t, base+"bar.jpg?w=300", images[1].URL)
+		assert.Equal(t, 300, images[1].Width)
+	}
+
+	e.actorProviders = map[string]mt.ActorProvider{
+		"GFRIENDS": noImages,
+		"C":        &galleryFake{Fake: fake.New(), name: "C", images: []string{base + "missing.png"}},
+	}
+	_, err = e.GetActorImages("Fake Actor")
+	assert.Equal(t, mt.ErrImageNotFound, err)
+}
+
+// concurrentGallery is a gallery fake recording peak concurrent fetches.
+type concurrentGallery struct {
+	*galleryFake
+	running, peak atomic.Int32
+}
+
+func (f *concurrentGallery) Fetch(rawURL string) (*http.Response, error) {
+	n := f.running.Add(1)
+	defer f.running.Add(-1)
+	for p := f.peak.Load(); n > p && !f.peak.CompareAndSwap(p, n); p = f.peak.Load() {
+	}
+	time.Sleep(5 * time.Millisecond)
+	return f.galleryFake.Fetch(rawURL)
+}
+
+func TestEngine_GetActorImagesBounded(t *testing.T) {
+	var urls []string
+	for i := 0; i < 4*maxActorImageWorkers; i++ {
+		urls = append(urls, fmt.Sprintf("https://fake.metatube.invalid/images/bar.jpg?w=%d", 100+i))
+	}
+	gallery := &concurrentGallery{galleryFake: &galleryFake{Fake: fake.New(), name: "A", images: urls}}
+	e := newBenchEngine(t, 0)
+	e.actorProviders = map[string]mt.ActorProvider{
+		"GFRIENDS": &galleryFake{Fake: fake.New(), name: gfriends.Name},
+		"A":        gallery,
+	}
+
+	images, err := e.GetActorImages("Fake Actor")
+	require.NoError(t, err)
+	assert.NotEmpty(t, images)
+	assert.LessOrEqual(t, gallery.peak.Load(), int32(maxActorImageWorkers))
+}
+
+// bigCoverFake is a gallery fake of movies of the cover and the big cover.
+type bigCoverFake struct {
+	*galleryFake
+	cover, bigCover string
+}
+
+func (f *bigCoverFake) GetMovieInfoByID(id string) (*model.MovieInfo, error) {
+	info, err := f.Fake.GetMovieInfoByID(id)
+	if err != nil {
+		return nil, err
+	}
+	info.Provider = f.name
+	info.CoverURL, info.ThumbURL, info.BigCoverURL = f.cover, f.cover, f.bigCover
+	return info, nil
+}
+
+func TestEngine_PreferredBackdropImage(t *testing.T) {
+	const base = "https://fake.metatube.invalid/images/"
+	for _, unit := range []struct {
+		name, cover, bigCover string
+		want                  string
+	}{
+		{"larger big cover", base + "bar.jpg?w=200", base + "bar.jpg?w=400", base + "bar.jpg?w=400"},
+		{"smaller big cover", base + "bar.jpg?w=400", base + "bar.jpg?w=200", base + "bar.jpg?w=400"},
+		{"same size", base + "bar.jpg?w=300", base + "circle.jpg?w=300", base + "circle.jpg?w=300"},
+		{"broken big cover", base + "bar.jpg?w=200", base + "missing.png", base + "bar.jpg?w=200"},
+		{"broken covers", base + "missing.png", base + "missing.jpeg", base + "missing.jpeg"},
+	} {
+		t.Run(unit.name, func(t *testing.T) {
+			e := newBenchEngine(t, 0)
+			e.movieProviders = map[string]mt.MovieProvider{"GALLERY": &bigCoverFake{
+				galleryFake: &galleryFake{Fake: fake.New(), name: "Gallery"},
+				cover:       unit.cover,
+				bigCover:    unit.bigCover,
+			}}
+			url, _, err := e.getPreferredMovieImageURLAndInfo("Gallery", "FAKE-001", false)
+			require.NoError(t, err)
+			assert.Equal(t, unit.want, url)
+			// posters are cropped from covers without big thumbs.
+			url, _, err = e.getPreferredMovieImageURLAndInfo("Gallery", "FAKE-001", true)
+			require.NoError(t, err)
+			assert.Equal(t, unit.cover, url)
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package engine
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/metatube-community/metatube-sdk-go/common/comparer"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+const (
+	// minActorMatchScore is the confidence threshold to auto-pick.
+	minActorMatchScore = 0.85
+	// maxActorMatchCandidates limits candidates of ambiguity details.
+	maxActorMatchCandidates = 10
+)
+
+var ErrAmbiguousActorName = errors.New(http.StatusConflict, "ambiguous actor name")
+
+// ActorMatch is an actor search result scored by name similarity.
+type ActorMatch struct {
+	*model.ActorSearchResult
+	Score float64 `json:"score"`
+}
+
+// AmbiguousActorError reports the candidates when no search result is
+// confident enough to be picked, it unwraps to ErrAmbiguousActorName.
+type AmbiguousActorError struct {
+	Name       string        `json:"name"`
+	Candidates []*ActorMatch `json:"candidates"`
+}
+
+func (e *AmbiguousActorError) Error() string {
+	return fmt.Sprintf("ambiguous actor name: %s (%d candidates)", e.Name, len(e.Candidates))
+}
+
+func (e *AmbiguousActorError) Unwrap() error {
+	return ErrAmbiguousActorName
+}
+
+// MatchActor searches the name from all providers, and scores results by
+// the similarity of names and aliases, best matches go first.
+func (e *Engine) MatchActor(name string) ([]*ActorMatch, error) {
+	results, err := e.SearchActorAll(name, true)
+	if err != nil {
+		return nil, err
+	}
+	matches := make([]*ActorMatch, 0, len(results))
+	for _, result := range results {
+		score := comparer.CompareName(name, result.Name)
+		for _, alias := range result.Aliases {
+			score = max(score, comparer.CompareName(name, alias))
+		}
+		matches = append(matches, &ActorMatch{ActorSearchResult: result, Score: score})
+	}
+	// stable, so the provider priority order is kept for equal scores.
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	return matches, nil
+}
+
+// GetActorInfoByName gets the info of the best matched actor of the name,
+// an *AmbiguousActorError is returned if no match is above the confidence
+// threshold, or different actors of a provider are matched equally.
+func (e *Engine) GetActorInfoByName(name string, lazy bool) (*model.ActorInfo, error) {
+	best, err := e.bestActorMatch(name)
+	if err != nil {
+		return nil, err
+	}
+	return e.GetActorInfoByProviderID(best.Provider, best.ID, lazy)
+}
+
+// bestActorMatch returns the best match of the name, see GetActorInfoByName.
+func (e *Engine) bestActorMatch(name string) (*ActorMatch, error) {
+	matches, err := e.MatchActor(name)
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, mt.ErrInfoNotFound
+	}
+	best := matches[0]
+	ambiguous := best.Score < minActorMatchScore
+	for _, m := range matches[1:] {
+		if m.Score < best.Score {
+			break
+		}
+		if m.Provider == best.Provider && m.ID != best.ID {
+			ambiguous = true // namesakes.
+		}
+	}
+	if ambiguous {
+		return nil, &AmbiguousActorError{
+			Name:       name,
+			Candidates: matches[:min(len(matches), maxActorMatchCandidates)],
+		}
+	}
+	return best, nil
+}
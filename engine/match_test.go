@@ -0,0 +1,149 @@
+package engine
+
+import (
+	goerr "errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+	"github.com/metatube-community/metatube-sdk-go/provider/gfriends"
+)
+
+// actorsFake is a renamed fake actor provider of the actors, of which all
+// are returned by searching any keyword.
+type actorsFake struct {
+	*fake.Fake
+	name     string
+	priority int
+	actors   []*model.ActorInfo
+}
+
+func newActorsFake(name string, priority int, names ...string) *actorsFake {
+	f := &actorsFake{Fake: fake.New(), name: name, priority: priority}
+	for i, n := range names {
+		// aliases are split by slashes, e.g., `name/alias`.
+		parts := strings.Split(n, "/")
+		f.actors = append(f.actors, &model.ActorInfo{
+			ID:       fmt.Sprint(i + 1),
+			Name:     parts[0],
+			Aliases:  parts[1:],
+			Provider: name,
+			Homepage: fmt.Sprintf("https://fake.metatube.invalid/%s/actors/%d", name, i+1),
+		})
+	}
+	return f
+}
+
+func (f *actorsFake) Name() string { return f.name }
+
+func (f *actorsFake) Priority() int { return f.priority }
+
+func (f *actorsFake) GetActorInfoByID(id string) (*model.ActorInfo, error) {
+	for _, actor := range f.actors {
+		if actor.ID == id {
+			info := *actor
+			return &info, nil
+		}
+	}
+	return nil, mt.ErrInfoNotFound
+}
+
+func (f *actorsFake) SearchActor(string) (results []*model.ActorSearchResult, err error) {
+	for _, actor := range f.actors {
+		results = append(results, actor.ToSearchResult())
+	}
+	if len(results) == 0 {
+		return nil, mt.ErrInfoNotFound
+	}
+	return
+}
+
+func TestEngine_GetActorInfoByName(t *testing.T) {
+	for _, unit := range []struct {
+		name      string
+		providers []*actorsFake
+		// want is provider:id of the info, or the number of candidates
+		// if ambiguous.
+		want       string
+		candidates int
+		err        error
+	}{
+		{"exact", []*actorsFake{
+			newActorsFake("A", 10, "Other Actor", "Yua Mikami"),
+		}, "A:2", 0, nil},
+		{"alias", []*actorsFake{
+			newActorsFake("A", 10, "Mikami/Yua Mikami"),
+		}, "A:1", 0, nil},
+		{"name order", []*actorsFake{
+			newActorsFake("A", 10, "Mikami Yua"),
+		}, "A:1", 0, nil},
+		{"same actor of providers", []*actorsFake{
+			newActorsFake("A", 10, "Yua Mikami"),
+			newActorsFake("B", 20, "Yua Mikami"),
+		}, "B:1", 0, nil},
+		{"namesakes", []*actorsFake{
+			newActorsFake("A", 10, "Yua Mikami", "Yua Mikami"),
+		}, "", 2, ErrAmbiguousActorName},
+		{"namesakes of lower scores", []*actorsFake{
+			newActorsFake("A", 10, "Yua Mikami"),
+			newActorsFake("B", 20, "Yua Mikam", "Yua Mikam"),
+		}, "A:1", 0, nil},
+		{"not confident", []*actorsFake{
+			newActorsFake("A", 10, "Mikami"),
+		}, "", 1, ErrAmbiguousActorName},
+		{"capped candidates", []*actorsFake{
+			newActorsFake("A", 10, strings.Split(strings.Repeat("Yua Mikami,", maxActorMatchCandidates+2), ",")[:maxActorMatchCandidates+2]...),
+		}, "", maxActorMatchCandidates, ErrAmbiguousActorName},
+		{"not found", []*actorsFake{
+			newActorsFake("A", 10),
+		}, "", 0, mt.ErrInfoNotFound},
+	} {
+		t.Run(unit.name, func(t *testing.T) {
+			e := newBenchEngine(t, 0)
+			// images of infos are always injected from Gfriends.
+			e.actorProviders = map[string]mt.ActorProvider{"GFRIENDS": newActorsFake(gfriends.Name, 0)}
+			for _, provider := range unit.providers {
+				e.actorProviders[strings.ToUpper(provider.name)] = provider
+			}
+
+			info, err := e.GetActorInfoByName("Yua Mikami", true)
+			if unit.err != nil {
+				require.ErrorIs(t, err, unit.err)
+				var ambiguous *AmbiguousActorError
+				if assert.Equal(t, unit.candidates > 0, goerr.As(err, &ambiguous)) && ambiguous != nil {
+					assert.Equal(t, "Yua Mikami", ambiguous.Name)
+					assert.Len(t, ambiguous.Candidates, unit.candidates)
+				}
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, unit.want, info.Provider+":"+info.ID)
+		})
+	}
+}
+
+func TestEngine_MatchActor(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.actorProviders = map[string]mt.ActorProvider{
+		"A": newActorsFake("A", 10, "Mikami", "Completely Different", "Yua Mikam", "Yua/Yua Mikami"),
+	}
+	matches, err := e.MatchActor("Yua Mikami")
+	require.NoError(t, err)
+	var names []string
+	for i, m := range matches {
+		names = append(names, m.Name)
+		if i > 0 {
+			assert.GreaterOrEqual(t, matches[i-1].Score, m.Score)
+		}
+	}
+	// names too different are never searched.
+	assert.Equal(t, []string{"Yua", "Yua Mikam", "Mikami"}, names)
+	assert.Equal(t, 1.0, matches[0].Score)
+	assert.Less(t, matches[2].Score, minActorMatchScore)
+}
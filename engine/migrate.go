@@ -0,0 +1,185 @@
+package engine
+
+import (
+	"net/http"
+
+	"gorm.io/gorm"
+
+	"github.com/metatube-community/metatube-sdk-go/database"
+	"github.com/metatube-community/metatube-sdk-go/database/migrate"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+var ErrSnapshotUnsupported = errors.New(http.StatusNotImplemented, "DB snapshot is only supported by SQLite")
+
+// migrations are versioned schema changes applied after the baseline
+// auto migration, new changes must be appended with greater IDs.
+var migrations = []*migrate.Migration{
+	{
+		ID: "20241001000000_create_movie_fingerprints",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AutoMigrate(&model.MovieFingerprint{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&model.MovieFingerprint{})
+		},
+	},
+	{
+		ID: "20241002000000_create_movie_image_hashes",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AutoMigrate(&model.MovieImageHash{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&model.MovieImageHash{})
+		},
+	},
+	{
+		ID: "20241004000000_create_blocklist",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AutoMigrate(&model.BlocklistEntry{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&model.BlocklistEntry{})
+		},
+	},
+	{
+		ID: "20241005000000_create_audit_logs",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AutoMigrate(&model.AuditLog{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&model.AuditLog{})
+		},
+	},
+	{
+		ID: "20241006000000_create_metadata_overrides",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AutoMigrate(&model.Override{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&model.Override{})
+		},
+	},
+	{
+		ID: "20241007000000_create_scan_states",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AutoMigrate(&model.ScanState{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&model.ScanState{})
+		},
+	},
+	{
+		ID: "20241008000000_create_series",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AutoMigrate(&model.Series{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&model.Series{})
+		},
+	},
+	{
+		ID: "20241009000000_create_watchlist",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AutoMigrate(&model.WatchlistEntry{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&model.WatchlistEntry{})
+		},
+	},
+	{
+		ID: "20241010000000_create_scheduled_tasks",
+		Up: func(tx *gorm.DB) error {
+			return tx.Migrator().AutoMigrate(&model.ScheduledTask{})
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(&model.ScheduledTask{})
+		},
+	},
+	{
+		ID: "20241011000000_add_movie_fingerprint_buckets",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AutoMigrate(&model.MovieFingerprint{}); err != nil {
+				return err
+			}
+			var records []*model.MovieFingerprint
+			return tx.Where("algorithm = ?", model.PHashAlgorithm).
+				FindInBatches(&records, 1000, func(tx *gorm.DB, _ int) error {
+					for _, record := range records {
+						if hash, err := parseVideoHash(record.Hash); err == nil {
+							if err = updateHashBuckets(tx, record, hash); err != nil {
+								return err
+							}
+						}
+					}
+					return nil
+				}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropHashBuckets(tx, &model.MovieFingerprint{})
+		},
+	},
+	{
+		ID: "20241012000000_add_movie_image_hash_buckets",
+		Up: func(tx *gorm.DB) error {
+			if err := tx.Migrator().AutoMigrate(&model.MovieImageHash{}); err != nil {
+				return err
+			}
+			var hashes []*model.MovieImageHash
+			return tx.FindInBatches(&hashes, 1000, func(tx *gorm.DB, _ int) error {
+				for _, h := range hashes {
+					if err := updateHashBuckets(tx, h, uint64(h.Hash)); err != nil {
+						return err
+					}
+				}
+				return nil
+			}).Error
+		},
+		Down: func(tx *gorm.DB) error {
+			return dropHashBuckets(tx, &model.MovieImageHash{})
+		},
+	},
+}
+
+// updateHashBuckets fills the hash buckets of the record of hash, which
+// are added to existing rows.
+func updateHashBuckets(tx *gorm.DB, record any, hash uint64) error {
+	b := model.NewHashBuckets(hash)
+	return tx.Model(record).UpdateColumns(map[string]any{
+		"bucket0": b.Bucket0,
+		"bucket1": b.Bucket1,
+		"bucket2": b.Bucket2,
+		"bucket3": b.Bucket3,
+	}).Error
+}
+
+// dropHashBuckets drops the hash buckets and their indexes of the model.
+func dropHashBuckets(tx *gorm.DB, value any) error {
+	fields := []string{"Bucket0", "Bucket1", "Bucket2", "Bucket3"}
+	for _, field := range fields {
+		if err := tx.Migrator().DropIndex(value, field); err != nil {
+			return err
+		}
+	}
+	for _, field := range fields {
+		if err := tx.Migrator().DropColumn(value, field); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Migrator returns the versioned schema migrator of the engine DB.
+func (e *Engine) Migrator() *migrate.Migrator {
+	return migrate.New(e.db, migrations...)
+}
+
+// SnapshotDB writes a consistent copy of the embedded SQLite DB to the
+// file, which must not exist, e.g., for backups of a running server.
+func (e *Engine) SnapshotDB(name string) error {
+	if e.db.Config.Dialector.Name() != database.Sqlite {
+		return ErrSnapshotUnsupported
+	}
+	return e.db.Exec("VACUUM INTO ?", name).Error
+}
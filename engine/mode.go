@@ -0,0 +1,67 @@
+package engine
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// ParseMode controls how strict the movie info validation is.
+type ParseMode uint8
+
+const (
+	// NormalParseMode requires ID, number, title and cover.
+	NormalParseMode ParseMode = iota
+	// StrictParseMode fails if any critical fields, including title,
+	// cover and release date, are missing. It suits media managers
+	// which auto-import metadata.
+	StrictParseMode
+	// LenientParseMode returns whatever parsed as long as the info is
+	// identifiable. It suits interactive users.
+	LenientParseMode
+)
+
+func (m ParseMode) String() string {
+	switch m {
+	case NormalParseMode:
+		return "normal"
+	case StrictParseMode:
+		return "strict"
+	case LenientParseMode:
+		return "lenient"
+	default:
+		return fmt.Sprintf("ParseMode(%d)", m)
+	}
+}
+
+// ParseParseMode parses the parse mode from string, empty string
+// stands for the normal mode.
+func ParseParseMode(s string) (ParseMode, error) {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "", "normal":
+		return NormalParseMode, nil
+	case "strict":
+		return StrictParseMode, nil
+	case "lenient":
+		return LenientParseMode, nil
+	default:
+		return NormalParseMode, fmt.Errorf("invalid parse mode: %s", s)
+	}
+}
+
+// validMovieInfo checks movie info according to the parse mode.
+func (e *Engine) validMovieInfo(info *model.MovieInfo) bool {
+	if info == nil {
+		return false
+	}
+	switch e.parseMode {
+	case StrictParseMode:
+		return info.Valid() && !time.Time(info.ReleaseDate).IsZero()
+	case LenientParseMode:
+		return info.ID != "" && info.Provider != "" && info.Homepage != ""
+	default:
+		return info.Valid()
+	}
+}
@@ -0,0 +1,71 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func TestParseParseMode(t *testing.T) {
+	for _, unit := range []struct {
+		s    string
+		want ParseMode
+		err  bool
+	}{
+		{"", NormalParseMode, false},
+		{"normal", NormalParseMode, false},
+		{" Strict ", StrictParseMode, false},
+		{"LENIENT", LenientParseMode, false},
+		{"loose", NormalParseMode, true},
+	} {
+		mode, err := ParseParseMode(unit.s)
+		assert.Equal(t, unit.want, mode, unit.s)
+		assert.Equal(t, unit.err, err != nil, unit.s)
+		if err == nil {
+			again, _ := ParseParseMode(mode.String())
+			assert.Equal(t, mode, again, "round trip")
+		}
+	}
+	assert.Equal(t, "ParseMode(9)", ParseMode(9).String())
+}
+
+func TestEngine_ValidMovieInfo(t *testing.T) {
+	full := &model.MovieInfo{
+		ID:          "ABC-123",
+		Number:      "ABC-123",
+		Title:       "Title",
+		Provider:    "Example",
+		Homepage:    "https://example.com/ABC-123",
+		CoverURL:    "https://example.com/ABC-123.jpg",
+		ThumbURL:    "https://example.com/ABC-123-thumb.jpg",
+		ReleaseDate: datatypes.Date(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)),
+	}
+	undated := *full
+	undated.ReleaseDate = datatypes.Date{}
+	untitled := *full
+	untitled.Title = ""
+	unidentified := *full
+	unidentified.Homepage = ""
+
+	for _, unit := range []struct {
+		name string
+		info *model.MovieInfo
+		// want of normal, strict and lenient modes.
+		want [3]bool
+	}{
+		{"nil", nil, [3]bool{false, false, false}},
+		{"full", full, [3]bool{true, true, true}},
+		{"undated", &undated, [3]bool{true, false, true}},
+		{"untitled", &untitled, [3]bool{false, false, true}},
+		{"unidentified", &unidentified, [3]bool{false, false, false}},
+	} {
+		for i, mode := range []ParseMode{NormalParseMode, StrictParseMode, LenientParseMode} {
+			e := &Engine{parseMode: mode}
+			assert.Equal(t, unit.want[i], e.validMovieInfo(unit.info), "%s in %s mode", unit.name, mode)
+		}
+	}
+}
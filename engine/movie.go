@@ -1,7 +1,10 @@
 package engine
 
 import (
+	"context"
+	goerr "errors"
 	"fmt"
+	"iter"
 	"strings"
 	"sync"
 	"time"
@@ -12,6 +15,7 @@ import (
 	"github.com/metatube-community/metatube-sdk-go/common/number"
 	"github.com/metatube-community/metatube-sdk-go/common/priority"
 	"github.com/metatube-community/metatube-sdk-go/engine/internal/utils"
+	"github.com/metatube-community/metatube-sdk-go/export"
 	"github.com/metatube-community/metatube-sdk-go/model"
 	mt "github.com/metatube-community/metatube-sdk-go/provider"
 )
@@ -43,7 +47,13 @@ func (e *Engine) searchMovieFromDB(keyword string, provider mt.MovieProvider, al
 	return
 }
 
-func (e *Engine) searchMovie(keyword string, provider mt.MovieProvider, fallback bool) (results []*model.MovieSearchResult, err error) {
+func (e *Engine) searchMovie(ctx context.Context, keyword string, provider mt.MovieProvider, fallback bool) (results []*model.MovieSearchResult, err error) {
+	defer func() {
+		// content filtering of results.
+		if err == nil {
+			results = e.filterMovieResults(results)
+		}
+	}()
 	// Regular keyword searching.
 	if searcher, ok := provider.(mt.MovieSearcher); ok {
 		if keyword = searcher.NormalizeMovieKeyword(keyword); keyword == "" {
@@ -64,7 +74,12 @@ func (e *Engine) searchMovie(keyword string, provider mt.MovieProvider, fallback
 				}
 			}()
 		}
-		return searcher.SearchMovie(keyword)
+		instance, release, err := e.acquireMovieProviderContext(ctx, provider)
+		if err != nil {
+			return nil, mt.ErrProviderTimeout
+		}
+		defer release()
+		return instance.(mt.MovieSearcher).SearchMovie(keyword)
 	}
 	// Fallback to movie info querying.
 	info, err := e.getMovieInfoByProviderID(provider, keyword, true)
@@ -82,51 +97,109 @@ func (e *Engine) SearchMovie(keyword, name string, fallback bool) ([]*model.Movi
 	if err != nil {
 		return nil, err
 	}
-	return e.searchMovie(keyword, provider, fallback)
+	return e.searchMovie(context.Background(), keyword, provider, fallback)
 }
 
-func (e *Engine) searchMovieAll(keyword string) (results []*model.MovieSearchResult, err error) {
-	type response struct {
-		Results   []*model.MovieSearchResult
-		Error     error
-		Provider  mt.MovieProvider
-		StartTime time.Time
-		EndTime   time.Time
-	}
-	respCh := make(chan response)
+// MovieSearchResponse is the search response of a single movie provider.
+type MovieSearchResponse struct {
+	Results  []*model.MovieSearchResult
+	Error    error
+	Provider mt.MovieProvider
+	Elapsed  time.Duration
+}
+
+func (e *Engine) searchMovieSeq(ctx context.Context, keyword string) iter.Seq[*MovieSearchResponse] {
+	return func(yield func(*MovieSearchResponse) bool) {
+		if e.searchDeadline > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, e.searchDeadline)
+			defer cancel()
+		}
 
-	var wg sync.WaitGroup
-	for _, provider := range e.movieProviders {
-		wg.Add(1)
-		// Goroutine started time.
-		startTime := time.Now()
-		// Async searching.
-		go func(provider mt.MovieProvider) {
-			defer wg.Done()
-			innerResults, innerErr := e.searchMovie(keyword, provider, false)
-			respCh <- response{
-				Results:   innerResults,
-				Error:     innerErr,
-				Provider:  provider,
-				StartTime: startTime,
-				EndTime:   time.Now(),
+		// buffered, so that pending searching tasks won't be
+		// blocked after the consumer stops.
+		respCh := make(chan *MovieSearchResponse, len(e.movieProviders))
+
+		maxPriority := e.maxMovieProviderPriority()
+
+		var wg sync.WaitGroup
+		for _, provider := range e.movieProviders {
+			wg.Add(1)
+			// Goroutine started time.
+			startTime := time.Now()
+			// Async searching.
+			go func(provider mt.MovieProvider) {
+				defer wg.Done()
+				e.emitProvider(model.MovieKind, keyword, provider.Name(), time.Time{}, 0, nil)
+				timeout := providerTimeout(e.searchDeadline, e.movieProviderPriority(provider), maxPriority)
+				innerResults, innerErr := withTimeout(timeout, func(ctx context.Context) ([]*model.MovieSearchResult, error) {
+					return e.searchMovie(ctx, keyword, provider, false)
+				})
+				e.tuner.record(model.MovieKind, provider.Name(), innerErr, time.Since(startTime), -1)
+				e.emitProvider(model.MovieKind, keyword, provider.Name(), startTime, len(innerResults), innerErr)
+				respCh <- &MovieSearchResponse{
+					Results:  innerResults,
+					Error:    innerErr,
+					Provider: provider,
+					Elapsed:  time.Since(startTime),
+				}
+			}(provider)
+		}
+		go func() {
+			wg.Wait()
+			// notify when all searching tasks done.
+			close(respCh)
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case resp, ok := <-respCh:
+				if !ok || !yield(resp) {
+					return
+				}
 			}
-		}(provider)
+		}
 	}
-	go func() {
-		wg.Wait()
-		// notify when all searching tasks done.
-		close(respCh)
-	}()
+}
+
+// SearchMovieSeq searches the keyword from all providers and yields
+// the response of each provider as soon as it finishes, so callers
+// can render results progressively. The iteration stops early when
+// ctx is done.
+func (e *Engine) SearchMovieSeq(ctx context.Context, keyword string) (iter.Seq[*MovieSearchResponse], error) {
+	if keyword = number.Trim(keyword); keyword == "" {
+		return nil, mt.ErrInvalidKeyword
+	}
+	return func(yield func(*MovieSearchResponse) bool) {
+		for resp := range e.searchMovieSeq(ctx, keyword) {
+			if resp.Error == nil {
+				// filter out invalid results.
+				results := make([]*model.MovieSearchResult, 0, len(resp.Results))
+				for _, result := range resp.Results {
+					if result.Valid() {
+						results = append(results, result)
+					}
+				}
+				resp.Results = results
+			}
+			if !yield(resp) {
+				return
+			}
+		}
+	}, nil
+}
 
+func (e *Engine) searchMovieAll(keyword string) (results []*model.MovieSearchResult, err error) {
 	ds := &strings.Builder{}
 
-	// response channel.
-	for resp := range respCh {
+	// response iteration.
+	for resp := range e.searchMovieSeq(context.Background(), keyword) {
 		ds.WriteString(fmt.Sprintf(" %s(%s): %v",
 			resp.Provider.Name(),
-			resp.EndTime.Sub(resp.StartTime),
-			resp.Error))
+			resp.Elapsed,
+			e.redactor.Error(resp.Error, keyword)))
 
 		if resp.Error != nil {
 			continue
@@ -134,7 +207,7 @@ func (e *Engine) searchMovieAll(keyword string) (results []*model.MovieSearchRes
 		results = append(results, resp.Results...)
 	}
 
-	e.logger.Infof("Search keyword %s:%s", keyword, ds.String())
+	e.logger.Infof("Search keyword %s:%s", e.redactor.String(keyword), ds.String())
 	return
 }
 
@@ -148,7 +221,8 @@ func (e *Engine) SearchMovieAll(keyword string, fallback bool) (results []*model
 		if err != nil {
 			return
 		}
-		if len(results) == 0 {
+		// content filtering, including results from DB.
+		if results = e.filterMovieResults(results); len(results) == 0 {
 			err = mt.ErrInfoNotFound
 			return
 		}
@@ -166,7 +240,10 @@ func (e *Engine) SearchMovieAll(keyword string, fallback bool) (results []*model
 				e.logger.Warnf("ignore provider %s as not found", result.Provider)
 				continue
 			}
-			ps.Append(comparer.Compare(keyword, result.Number)*float64(e.MustGetMovieProviderByName(result.Provider).Priority()), result)
+			similarity := comparer.Compare(
+				number.Canonicalize("", keyword),
+				number.Canonicalize(result.Provider, result.Number))
+			ps.Append(similarity*float64(e.movieProviderPriority(e.MustGetMovieProviderByName(result.Provider))), result)
 		}
 		// sort according to priority.
 		results = ps.Stable().Underlying()
@@ -198,36 +275,157 @@ func (e *Engine) getMovieInfoFromDB(provider mt.MovieProvider, id string) (*mode
 	return info, err
 }
 
-func (e *Engine) getMovieInfoWithCallback(provider mt.MovieProvider, id string, lazy bool, callback func() (*model.MovieInfo, error)) (info *model.MovieInfo, err error) {
+// getMovieInfoWithCallback gets the info by the callback. If offline, the info
+// is parsed from a page saved manually, so it never replaces the stored one,
+// nor is it counted in the fetch statistics.
+func (e *Engine) getMovieInfoWithCallback(provider mt.MovieProvider, id string, lazy, offline bool, callback func() (*model.MovieInfo, error)) (info *model.MovieInfo, err error) {
 	defer func() {
-		// metadata validation check.
-		if err == nil && (info == nil || !info.Valid()) {
-			err = mt.ErrIncompleteMetadata
-		}
+		err = e.finalizeMovieInfo(provider, info, err)
 	}()
 	// Query DB first (by id).
 	if lazy {
-		if info, err = e.getMovieInfoFromDB(provider, id); err == nil && info.Valid() {
+		if info, err = e.getMovieInfoFromDB(provider, id); err == nil && e.validMovieInfo(info) {
+			e.recordCacheHit()
 			return // ignore DB query error.
 		}
 	}
+	// scrapes of the same movie are serialized, and the later ones are
+	// served from DB if lazy. It's scraped anyway if the lock is not
+	// acquired in time, while failures of the locker are returned.
+	ctx, cancel := context.WithTimeout(context.Background(), e.timeout)
+	defer cancel()
+	key := "movie:" + provider.Name() + ":" + strings.ToUpper(id)
+	if unlock, lockErr := e.locker.Lock(ctx, key); lockErr == nil {
+		defer unlock() // after auto-save.
+		if lazy {
+			if info, err = e.getMovieInfoFromDB(provider, id); err == nil && e.validMovieInfo(info) {
+				e.recordCacheHit()
+				return
+			}
+		}
+	} else if goerr.Is(lockErr, context.DeadlineExceeded) {
+		e.logger.Warnf("Scrape %s without lock: %v", e.redactor.String(key), lockErr)
+	} else {
+		e.logger.Errorf("Lock %s: %v", e.redactor.String(key), e.redactor.Error(lockErr, key))
+		return nil, lockErr
+	}
 	// delayed info auto-save.
 	defer func() {
-		if err == nil && info.Valid() {
-			e.db.Clauses(clause.OnConflict{
-				UpdateAll: true,
-			}).Create(info) // ignore error
+		if err == nil && info.Valid() && !e.isBlocked(info.Provider, info.Number, info.ID) &&
+			e.filterMovieInfo(info) == nil /* not filtered out */ {
+			if offline {
+				if _, dbErr := e.getMovieInfoFromDB(provider, info.ID); dbErr == nil {
+					return // keep the stored one.
+				}
+			}
+			e.saveMovieInfo(info) // ignore error
 		}
 	}()
-	return callback()
+	startTime := time.Now()
+	if !offline {
+		e.emitProvider(model.MovieKind, id, provider.Name(), time.Time{}, 0, nil)
+	}
+	info, err = callback()
+	if err != nil && goerr.Is(err, mt.ErrInfoNotFound) {
+		err = mt.ErrInfoNotFound // e.g., wrapped by transports of soft 404 pages.
+	} else if err == nil && info != nil && e.isMissingImage(provider, info.CoverURL) {
+		info, err = nil, mt.ErrInfoNotFound
+	}
+	if err == nil && info != nil {
+		info.Homepage = canonicalHomepage(provider, info.Homepage)
+		e.flagPlaceholders(provider, info)
+		detectEditions(info)
+		info.Attribute(DisplayName(provider), startTime)
+		if !offline {
+			e.emitFields(model.MovieKind, id, provider.Name(), info)
+		}
+	}
+	if offline {
+		return
+	}
+	e.emitProvider(model.MovieKind, id, provider.Name(), startTime, 1, err)
+	e.recordFetch(lazy, provider.Name(), err)
+	completeness := -1.0
+	if err == nil && info != nil {
+		completeness = movieCompleteness(info)
+	}
+	e.tuner.record(model.MovieKind, provider.Name(), err, time.Since(startTime), completeness)
+	return
+}
+
+// finalizeMovieInfo prepares the returned info in order: the manual override
+// is applied first, so that the fields it sets are canonicalized, processed
+// and filtered as the scraped ones, then the metadata is validated.
+func (e *Engine) finalizeMovieInfo(provider mt.MovieProvider, info *model.MovieInfo, err error) error {
+	if err != nil {
+		return err
+	}
+	if info != nil {
+		e.applyOverride(model.MovieKind, info.Provider, info.ID, info)
+		info.ContentRating = e.contentRatings.Get(export.RatingTargetAPI)
+		if info.Source == nil { // stored before attributed.
+			info.Attribute(DisplayName(provider), info.UpdatedAt)
+		}
+		// stored before canonicalized.
+		info.Homepage = canonicalHomepage(provider, info.Homepage)
+		detectEditions(info) // stored before detected.
+		for _, process := range e.movieProcessors {
+			process(info)
+		}
+		if err = e.filterMovieInfo(info); err != nil {
+			return err
+		}
+	}
+	if !e.validMovieInfo(info) {
+		return mt.ErrIncompleteMetadata
+	}
+	return nil
+}
+
+// saveMovieInfo stores the info if it's new or changed, and notifies the
+// change handler of the changes to the stored one, or the notifier of the
+// new movie scraped.
+func (e *Engine) saveMovieInfo(info *model.MovieInfo) error {
+	var (
+		changes []*model.FieldChange
+		old     = &model.MovieInfo{}
+		existed = e.db.
+			Where("provider = ? AND id = ?", info.Provider, info.ID).
+			First(old).Error == nil
+	)
+	if existed {
+		if changes = model.DiffMovieInfo(old, info); len(changes) == 0 {
+			// unchanged, but retrieved again.
+			return e.db.Model(info).Select("Source").UpdateColumns(info).Error
+		}
+		_ = e.mergeMovieOverride(old, info) // ignore error
+	}
+	if err := e.db.Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(info).Error; err != nil {
+		return err
+	}
+	if !existed {
+		e.notifyMovieScraped(info)
+		e.prefetchRelated(info)
+	} else if e.movieChangeHandler != nil {
+		e.movieChangeHandler(old, info, changes)
+	}
+	return nil
 }
 
 func (e *Engine) getMovieInfoByProviderID(provider mt.MovieProvider, id string, lazy bool) (*model.MovieInfo, error) {
 	if id = provider.NormalizeMovieID(id); id == "" {
 		return nil, mt.ErrInvalidID
 	}
-	return e.getMovieInfoWithCallback(provider, id, lazy, func() (*model.MovieInfo, error) {
-		return provider.GetMovieInfoByID(id)
+	return e.getMovieInfoWithCallback(provider, id, lazy, false, func() (*model.MovieInfo, error) {
+		instance, release := e.acquireMovieProvider(provider)
+		defer release()
+		info, err := instance.GetMovieInfoByID(id)
+		if err == nil && info != nil {
+			flagArchived(instance, info.Homepage, info)
+		}
+		return info, err
 	})
 }
 
@@ -239,6 +437,30 @@ func (e *Engine) GetMovieInfoByProviderID(name, id string, lazy bool) (*model.Mo
 	return e.getMovieInfoByProviderID(provider, id, lazy)
 }
 
+// GetLocalizedMovieInfoByProviderID gets movie's info with the texts
+// localized in lang. Localized infos are neither read from nor saved to
+// DB, as they would be mixed up with the default ones. Providers that
+// don't support localization fall back to the default info.
+func (e *Engine) GetLocalizedMovieInfoByProviderID(name, id, lang string, lazy bool) (*model.MovieInfo, error) {
+	provider, err := e.GetMovieProviderByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := provider.(mt.MovieLocalizer); !ok || lang == "" {
+		return e.getMovieInfoByProviderID(provider, id, lazy)
+	}
+	if id = provider.NormalizeMovieID(id); id == "" {
+		return nil, mt.ErrInvalidID
+	}
+	instance, release := e.acquireMovieProvider(provider)
+	defer release()
+	info, err := instance.(mt.MovieLocalizer).GetLocalizedMovieInfoByID(id, lang)
+	if err == nil && (info == nil || !info.Valid()) {
+		err = mt.ErrIncompleteMetadata
+	}
+	return info, err
+}
+
 func (e *Engine) getMovieInfoByProviderURL(provider mt.MovieProvider, rawURL string, lazy bool) (*model.MovieInfo, error) {
 	id, err := provider.ParseMovieIDFromURL(rawURL)
 	switch {
@@ -247,8 +469,14 @@ func (e *Engine) getMovieInfoByProviderURL(provider mt.MovieProvider, rawURL str
 	case id == "":
 		return nil, mt.ErrInvalidURL
 	}
-	return e.getMovieInfoWithCallback(provider, id, lazy, func() (*model.MovieInfo, error) {
-		return provider.GetMovieInfoByURL(rawURL)
+	return e.getMovieInfoWithCallback(provider, id, lazy, false, func() (*model.MovieInfo, error) {
+		instance, release := e.acquireMovieProvider(provider)
+		defer release()
+		info, err := instance.GetMovieInfoByURL(rawURL)
+		if err == nil && info != nil {
+			flagArchived(instance, info.Homepage, info)
+		}
+		return info, err
 	})
 }
 
@@ -0,0 +1,336 @@
+package engine
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/metatube-community/metatube-sdk-go/common/httpcache"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+func TestEngine_MovieSource(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	p := fake.New()
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": p}
+
+	start := time.Now().UTC()
+	info, err := e.GetMovieInfoByProviderID(fake.Name, "FAKE-001", true)
+	require.NoError(t, err)
+	if assert.NotNil(t, info.Source) {
+		assert.Equal(t, fake.Name, info.Source.Provider)
+		assert.Equal(t, info.Homepage, info.Source.URL)
+		assert.False(t, info.Source.RetrievedAt.Before(start.Truncate(time.Second)))
+	}
+
+	// stored, and re-attributed by scraping again, though unchanged.
+	stored, err := e.getMovieInfoFromDB(p, "FAKE-001")
+	require.NoError(t, err)
+	assert.Equal(t, info.Source.RetrievedAt.Unix(), stored.Source.RetrievedAt.Unix())
+	stored.Source.RetrievedAt = start.Add(-time.Hour)
+	require.NoError(t, e.db.Model(stored).Select("Source").UpdateColumns(stored).Error)
+	_, err = e.GetMovieInfoByProviderID(fake.Name, "FAKE-001", false)
+	require.NoError(t, err)
+	stored, err = e.getMovieInfoFromDB(p, "FAKE-001")
+	require.NoError(t, err)
+	assert.False(t, stored.Source.RetrievedAt.Before(start.Truncate(time.Second)))
+
+	// legacy rows are attributed by the update time.
+	require.NoError(t, e.db.Model(&model.MovieInfo{}).
+		Where("provider = ? AND id = ?", stored.Provider, stored.ID).
+		UpdateColumn("source", nil).Error)
+	info, err = e.GetMovieInfoByProviderID(fake.Name, "FAKE-001", true)
+	require.NoError(t, err)
+	if assert.NotNil(t, info.Source) {
+		assert.Equal(t, stored.UpdatedAt.Unix(), info.Source.RetrievedAt.Unix())
+	}
+}
+
+// archivedFake serves all pages as archived.
+type archivedFake struct{ *fake.Fake }
+
+func (archivedFake) SetArchiveFallback(bool) {}
+
+func (archivedFake) ArchivedURL(rawURL string) (string, bool) {
+	return "https://web.archive.org/web/20200101000000id_/" + rawURL, true
+}
+
+func TestEngine_MovieArchived(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	p := archivedFake{fake.New()}
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": p}
+
+	info, err := e.GetMovieInfoByProviderID(fake.Name, "FAKE-001", true)
+	require.NoError(t, err)
+	assert.True(t, info.Source.Archived())
+	assert.Equal(t, "https://web.archive.org/web/20200101000000id_/"+info.Homepage, info.Source.ArchiveURL)
+
+	// flagged in DB as well.
+	stored, err := e.getMovieInfoFromDB(p, "FAKE-001")
+	require.NoError(t, err)
+	assert.True(t, stored.Source.Archived())
+}
+
+// trackedFake serves movie pages of affiliate links.
+type trackedFake struct{ *fake.Fake }
+
+func (f trackedFake) GetMovieInfoByID(id string) (*model.MovieInfo, error) {
+	info, err := f.Fake.GetMovieInfoByID(id)
+	if err == nil {
+		info.Homepage = "http://FAKE.metatube.invalid/movies/" + info.ID + "?utm_source=feed&af_id=123#top"
+	}
+	return info, err
+}
+
+func TestEngine_MovieHomepageCanonicalized(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	p := trackedFake{fake.New()}
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": p}
+
+	info, err := e.GetMovieInfoByProviderID(fake.Name, "FAKE-001", false)
+	require.NoError(t, err)
+	assert.Equal(t, "https://fake.metatube.invalid/movies/FAKE-001", info.Homepage)
+	assert.Equal(t, info.Homepage, info.Source.URL)
+
+	stored, err := e.getMovieInfoFromDB(p, "FAKE-001")
+	require.NoError(t, err)
+	assert.Equal(t, info.Homepage, stored.Homepage)
+}
+
+// softNotFoundFake fails movie pages with wrapped not found errors.
+type softNotFoundFake struct{ *fake.Fake }
+
+func (softNotFoundFake) GetMovieInfoByID(string) (*model.MovieInfo, error) {
+	return nil, fmt.Errorf("visit: %w", mt.ErrInfoNotFound)
+}
+
+func TestEngine_MovieSoftNotFound(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	p := fake.New()
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": p}
+
+	info, err := e.GetMovieInfoByProviderID(fake.Name, "FAKE-001", false)
+	require.NoError(t, err)
+	data, err := e.getImageData(p, info.CoverURL)
+	require.NoError(t, err)
+	sum := sha256.Sum256(data)
+
+	// the cover is then a known placeholder.
+	WithSoftNotFound(map[string]*mt.SoftNotFound{
+		"fake": {ImageHashes: []string{hex.EncodeToString(sum[:])}},
+	})(e)
+	_, err = e.GetMovieInfoByProviderID(fake.Name, "FAKE-001", false)
+	assert.Equal(t, mt.ErrInfoNotFound, err)
+
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": softNotFoundFake{p}}
+	_, err = e.GetMovieInfoByProviderID(fake.Name, "FAKE-002", false)
+	assert.Equal(t, mt.ErrInfoNotFound, err)
+	_, err = e.getMovieInfoFromDB(p, "FAKE-002")
+	assert.Error(t, err, "never stored")
+}
+
+// nowPrintingFake serves "now printing" covers until printed.
+type nowPrintingFake struct {
+	*fake.Fake
+	printed *bool
+}
+
+func (f nowPrintingFake) GetMovieInfoByID(id string) (*model.MovieInfo, error) {
+	info, err := f.Fake.GetMovieInfoByID(id)
+	if err == nil && !*f.printed {
+		info.CoverURL = "https://fake.metatube.invalid/images/now_printing/now_printing.jpg"
+		info.ThumbURL, info.BigCoverURL, info.BigThumbURL = info.CoverURL, "", ""
+	}
+	return info, err
+}
+
+func TestEngine_MoviePlaceholderImages(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	printed := false
+	p := nowPrintingFake{Fake: fake.New(), printed: &printed}
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": p}
+
+	info, err := e.GetMovieInfoByProviderID(fake.Name, "FAKE-001", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cover_url", "thumb_url"}, info.Source.Placeholders)
+	_, _, err = e.getPreferredMovieImageURLAndInfo(fake.Name, "FAKE-001", true)
+	assert.Equal(t, mt.ErrImageNotFound, err)
+	completeness := movieCompleteness(info)
+
+	// retried once retrieved before the age.
+	n, err := e.RefreshPlaceholderImages(time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	printed = true
+	n, err = e.RefreshPlaceholderImages(-time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	info, err = e.GetMovieInfoByProviderID(fake.Name, "FAKE-001", true)
+	require.NoError(t, err)
+	assert.Empty(t, info.Source.Placeholders)
+	assert.Greater(t, movieCompleteness(info), completeness)
+	url, _, err := e.getPreferredMovieImageURLAndInfo(fake.Name, "FAKE-001", true)
+	require.NoError(t, err)
+	assert.NotContains(t, url, "now_printing")
+}
+
+func TestEngine_MovieArtworks(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": fake.New()}
+
+	artworks, err := e.GetMovieArtworks(fake.Name, "FAKE-001")
+	require.NoError(t, err)
+	require.Len(t, artworks, 5)
+	assert.Equal(t, model.ScreenshotArtwork, artworks[4].Type)
+	assert.Equal(t, "Screenshot 2", artworks[4].Label())
+	assert.Equal(t, "Fake Scene 2", artworks[4].Caption)
+
+	img, err := e.GetMovieScreenshotImage(fake.Name, "FAKE-001", 2)
+	require.NoError(t, err)
+	assert.NotNil(t, img)
+	_, err = e.GetMovieScreenshotImage(fake.Name, "FAKE-001", 3)
+	assert.Equal(t, mt.ErrImageNotFound, err)
+}
+
+// reprintedFake serves covers of "now printing" until printed, and replaces
+// them at the same URLs.
+type reprintedFake struct {
+	*fake.Fake
+	printed *bool
+	fetches *int
+}
+
+func (f reprintedFake) GetMovieInfoByID(id string) (*model.MovieInfo, error) {
+	info, err := f.Fake.GetMovieInfoByID(id)
+	if err == nil {
+		info.ThumbURL, info.BigCoverURL, info.BigThumbURL = info.CoverURL, "", ""
+	}
+	return info, err
+}
+
+func (f reprintedFake) Fetch(rawURL string) (*http.Response, error) {
+	*f.fetches++
+	if !*f.printed {
+		rawURL = strings.Replace(rawURL, "cover", "now", 1)
+	}
+	return f.Fake.Fetch(rawURL)
+}
+
+func TestEngine_MoviePlaceholderImageCache(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.imageCache = httpcache.NewStore(t.TempDir(), DefaultImageCacheTTL)
+	var (
+		printed bool
+		fetches int
+	)
+	p := reprintedFake{Fake: fake.New(), printed: &printed, fetches: &fetches}
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": p}
+
+	info, err := p.GetMovieInfoByID("FAKE-001")
+	require.NoError(t, err)
+	data, err := e.getImageData(p, info.CoverURL) // cached.
+	require.NoError(t, err)
+	sum := sha256.Sum256(data)
+	e.placeholderHashes = []string{hex.EncodeToString(sum[:])}
+
+	info, err = e.GetMovieInfoByProviderID(fake.Name, "FAKE-001", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"cover_url", "thumb_url"}, info.Source.Placeholders)
+
+	// replaced at the same URL, which is re-checked bypassing the cache.
+	printed = true
+	n, err := e.RefreshPlaceholderImages(-time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, 1, n)
+
+	// images unchanged are not hashed again.
+	fetches = 0
+	info, err = e.GetMovieInfoByProviderID(fake.Name, "FAKE-001", false)
+	require.NoError(t, err)
+	assert.Empty(t, info.Source.Placeholders)
+	assert.Zero(t, fetches)
+}
+
+// localizedMovieFake is a fake movie provider of titles suffixed by
+// languages, which fails to localize in unsupported languages.
+type localizedMovieFake struct{ *fake.Fake }
+
+func (f localizedMovieFake) GetLocalizedMovieInfoByID(id, lang string) (*model.MovieInfo, error) {
+	if lang != "en" {
+		return nil, mt.ErrInvalidLanguage
+	}
+	info, err := f.GetMovieInfoByID(id)
+	if err == nil {
+		info.Title += " (" + lang + ")"
+	}
+	return info, err
+}
+
+func TestEngine_GetLocalizedMovieInfoByProviderID(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{
+		"FAKE":  localizedMovieFake{fake.New()},
+		"PLAIN": &benchProvider{Fake: fake.New(), name: "Plain"},
+	}
+
+	info, err := e.GetLocalizedMovieInfoByProviderID(fake.Name, "FAKE-001", "en", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Fake Movie 001 (en)", info.Title)
+
+	// localized infos are not saved, and errors are returned as is.
+	info, err = e.GetMovieInfoByProviderID(fake.Name, "FAKE-001", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Fake Movie 001", info.Title)
+	_, err = e.GetLocalizedMovieInfoByProviderID(fake.Name, "FAKE-001", "fr", true)
+	assert.Equal(t, mt.ErrInvalidLanguage, err)
+
+	// fall back to the default info.
+	info, err = e.GetLocalizedMovieInfoByProviderID(fake.Name, "FAKE-001", "", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Fake Movie 001", info.Title)
+	info, err = e.GetLocalizedMovieInfoByProviderID("Plain", "FAKE-002", "en", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Fake Movie 002", info.Title)
+}
+
+// lockerFunc is a dlock.Locker of the function.
+type lockerFunc func(ctx context.Context, key string) (func(), error)
+
+func (fn lockerFunc) Lock(ctx context.Context, key string) (func(), error) { return fn(ctx, key) }
+
+func TestEngine_MovieLockErrors(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": fake.New()}
+	core, logs := observer.New(zap.WarnLevel)
+	e.logger = zap.New(core).Sugar()
+
+	// failures of the locker are returned.
+	lockErr := errors.New("redis: connection refused")
+	e.locker = lockerFunc(func(context.Context, string) (func(), error) { return nil, lockErr })
+	_, err := e.GetMovieInfoByProviderID("fake", "FAKE-001", true)
+	assert.ErrorIs(t, err, lockErr)
+	assert.Equal(t, 1, logs.FilterMessageSnippet("connection refused").Len())
+
+	// scraped anyway if the lock is not acquired in time.
+	e.locker = lockerFunc(func(context.Context, string) (func(), error) {
+		return nil, context.DeadlineExceeded
+	})
+	info, err := e.GetMovieInfoByProviderID("fake", "FAKE-001", true)
+	require.NoError(t, err)
+	assert.Equal(t, "FAKE-001", info.ID)
+	assert.Equal(t, 1, logs.FilterMessageSnippet("without lock").Len())
+}
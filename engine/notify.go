@@ -0,0 +1,56 @@
+package engine
+
+import (
+	"strings"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/notify"
+)
+
+// Notify sends the event by the notifier asynchronously if enabled, so
+// that slow services never block scrapes, errors are logged.
+func (e *Engine) Notify(event *notify.Event) {
+	if e.notifier == nil {
+		return
+	}
+	e.notifyWG.Add(1)
+	go func() {
+		defer e.notifyWG.Done()
+		if err := e.notifier.Notify(event); err != nil {
+			e.logger.Warnw("notify failed", "kind", event.Kind,
+				"error", e.redactor.Error(err, event.Title, event.Message))
+		}
+	}()
+}
+
+// WaitNotifications waits for notifications in flight to be sent, which
+// should be called before short-lived programs exit.
+func (e *Engine) WaitNotifications() {
+	e.notifyWG.Wait()
+}
+
+func (e *Engine) notifyMovieScraped(info *model.MovieInfo) {
+	e.Notify(&notify.Event{
+		Kind:     notify.ScrapeCompleted,
+		Title:    info.Number,
+		Message:  info.Title,
+		URL:      info.Homepage,
+		ImageURL: movieImageURL(info, "cover_url", info.CoverURL),
+	})
+}
+
+// NotifyMovieChanged notifies the changed fields of the stored movie, it's
+// meant to be the handler of WithMovieChangeHandler.
+func (e *Engine) NotifyMovieChanged(_, info *model.MovieInfo, changes []*model.FieldChange) {
+	fields := make([]string, 0, len(changes))
+	for _, c := range changes {
+		fields = append(fields, c.Field)
+	}
+	e.Notify(&notify.Event{
+		Kind:     notify.MetadataChanged,
+		Title:    info.Number,
+		Message:  info.Title + "\nChanges: " + strings.Join(fields, ", "),
+		URL:      info.Homepage,
+		ImageURL: movieImageURL(info, "cover_url", info.CoverURL),
+	})
+}
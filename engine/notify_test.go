@@ -0,0 +1,107 @@
+package engine
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/notify"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+type recordNotifier struct {
+	mu     sync.Mutex
+	events []*notify.Event
+}
+
+func (n *recordNotifier) Notify(e *notify.Event) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.events = append(n.events, e)
+	return nil
+}
+
+func TestEngine_NotifyMovieChanged(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	f := &genresFake{Fake: fake.New()}
+	n := &recordNotifier{}
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": f}
+	e.notifier = n
+	e.movieChangeHandler = e.NotifyMovieChanged
+
+	_, err := e.GetMovieInfoByProviderID("fake", "FAKE-001", false)
+	require.NoError(t, err)
+	// unchanged.
+	_, err = e.GetMovieInfoByProviderID("fake", "FAKE-001", false)
+	require.NoError(t, err)
+	f.genres = []string{"Upstream"}
+	_, err = e.GetMovieInfoByProviderID("fake", "FAKE-001", false)
+	require.NoError(t, err)
+	e.WaitNotifications()
+
+	// events are sent asynchronously.
+	events := make(map[notify.Kind]*notify.Event)
+	for _, event := range n.events {
+		events[event.Kind] = event
+	}
+	if assert.Len(t, n.events, 2) && assert.Contains(t, events, notify.ScrapeCompleted) {
+		assert.Equal(t, &notify.Event{
+			Kind:     notify.MetadataChanged,
+			Title:    "FAKE-001",
+			Message:  "Fake Movie 001\nChanges: genres",
+			URL:      "https://fake.metatube.invalid/movies/FAKE-001",
+			ImageURL: events[notify.ScrapeCompleted].ImageURL,
+		}, events[notify.MetadataChanged])
+	}
+}
+
+// failingNotifier fails with the URL of the event, like HTTP clients do.
+type failingNotifier struct{}
+
+func (failingNotifier) Notify(e *notify.Event) error {
+	return fmt.Errorf("Post %q: EOF", e.URL+"?title="+url.QueryEscape(e.Message))
+}
+
+// failingSearchFake fails to search with the keyword in the URL.
+type failingSearchFake struct{ *fake.Fake }
+
+func (f *failingSearchFake) SearchMovie(keyword string) ([]*model.MovieSearchResult, error) {
+	return nil, fmt.Errorf("Get %q: EOF, keyword %s", "https://fake.metatube.invalid/search?q="+url.QueryEscape(keyword), keyword)
+}
+
+func TestEngine_RedactedLogs(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	WithPrivacyMode(true)(e)
+	core, logs := observer.New(zap.InfoLevel)
+	e.logger = zap.New(core).Sugar()
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": &failingSearchFake{fake.New()}}
+	e.notifier = failingNotifier{}
+
+	info, err := e.GetMovieInfoByProviderID("fake", "FAKE-001", false)
+	require.NoError(t, err)
+	e.notifyMovieScraped(info)
+	e.WaitNotifications()
+	_, err = e.RefreshSeries(&model.Series{Provider: "Fake", Name: info.Series})
+	require.NoError(t, err)
+	require.NoError(t, e.AddWatchlistEntry(&model.WatchlistEntry{Kind: model.MovieKind, Name: "FAKE-999999"}))
+
+	require.NotZero(t, logs.Len())
+	for _, entry := range logs.All() {
+		data, err := json.Marshal(entry.ContextMap())
+		require.NoError(t, err)
+		for _, s := range []string{info.ID, info.Title, info.Series, "Fake Series", "FAKE-999999", "fake.metatube.invalid/movies"} {
+			assert.NotContains(t, entry.Message+string(data), s)
+		}
+	}
+	assert.NotZero(t, logs.FilterMessage("notify failed").Len())
+	assert.NotZero(t, logs.FilterMessage("series search failed").Len())
+}
@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/metatube-community/metatube-sdk-go/common/number"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// minNumberCompleteness is the completeness of infos accepted without
+// falling back to the next provider.
+const minNumberCompleteness = 0.6
+
+// MovieNumberMatch is the movie info of a number and the provider chosen.
+type MovieNumberMatch struct {
+	Provider string           `json:"provider"`
+	ID       string           `json:"id"`
+	Info     *model.MovieInfo `json:"info"`
+	// Tried is the providers fallen back from, in order.
+	Tried []string `json:"tried,omitempty"`
+}
+
+// GetMovieInfoByNumber searches the number from all providers, and gets
+// the info from the providers of exactly matched results in the order of
+// priority. It falls back to the next provider if the info is failed or
+// incomplete, and the most complete info is chosen if none is complete.
+func (e *Engine) GetMovieInfoByNumber(ctx context.Context, num string) (*MovieNumberMatch, error) {
+	if num = number.Trim(num); num == "" {
+		return nil, mt.ErrInvalidKeyword
+	}
+	candidates, err := e.movieNumberCandidates(ctx, num)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		best         *MovieNumberMatch
+		completeness float64
+		tried        []string
+	)
+	for _, result := range candidates {
+		if err = ctx.Err(); err != nil {
+			break
+		}
+		info, innerErr := e.GetMovieInfoByProviderID(result.Provider, result.ID, true)
+		if innerErr != nil {
+			err = innerErr
+			tried = append(tried, result.Provider)
+			continue
+		}
+		if c := movieCompleteness(info); best == nil || c > completeness {
+			best, completeness = &MovieNumberMatch{
+				Provider: info.Provider,
+				ID:       info.ID,
+				Info:     info,
+				Tried:    tried,
+			}, c
+		}
+		if completeness >= minNumberCompleteness {
+			break
+		}
+		tried = append(tried, result.Provider)
+	}
+	if best != nil {
+		return best, nil
+	}
+	if err == nil {
+		err = mt.ErrInfoNotFound
+	}
+	return nil, err
+}
+
+// movieNumberCandidates returns the best result of each provider whose
+// number matches exactly, in the order of priority.
+func (e *Engine) movieNumberCandidates(ctx context.Context, num string) ([]*model.MovieSearchResult, error) {
+	seq, err := e.SearchMovieSeq(ctx, num)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		canonical  = number.Canonicalize("", num)
+		candidates []*model.MovieSearchResult
+		seen       = make(map[string]struct{})
+	)
+	for resp := range seq {
+		for _, result := range resp.Results {
+			if _, ok := seen[result.Provider]; ok ||
+				!strings.EqualFold(number.Canonicalize(result.Provider, result.Number), canonical) {
+				continue
+			}
+			seen[result.Provider] = struct{}{}
+			candidates = append(candidates, result)
+		}
+	}
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+	priorities := make(map[string]int, len(candidates))
+	for _, result := range candidates {
+		if provider, err := e.GetMovieProviderByName(result.Provider); err == nil {
+			priorities[result.Provider] = e.movieProviderPriority(provider)
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if pi, pj := priorities[candidates[i].Provider], priorities[candidates[j].Provider]; pi != pj {
+			return pi > pj
+		}
+		return candidates[i].Provider < candidates[j].Provider
+	})
+	return candidates, nil
+}
@@ -0,0 +1,84 @@
+package engine
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+// numberProvider is a bench provider of the priority, whose infos are
+// failed by err, or stripped to be incomplete.
+type numberProvider struct {
+	*benchProvider
+	priority   int
+	err        error
+	incomplete bool
+}
+
+func (p *numberProvider) Priority() int { return p.priority }
+
+func (p *numberProvider) GetMovieInfoByID(id string) (*model.MovieInfo, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	info, err := p.benchProvider.GetMovieInfoByID(id)
+	if err == nil {
+		info.Provider = p.name
+		if p.incomplete {
+			info.Summary, info.Genres, info.Actors, info.PreviewImages = "", nil, nil, nil
+			info.Director, info.Maker, info.Runtime = "", "", 0
+		}
+	}
+	return info, err
+}
+
+func TestEngine_GetMovieInfoByNumber(t *testing.T) {
+	newProvider := func(name string, priority int) *numberProvider {
+		return &numberProvider{benchProvider: &benchProvider{Fake: fake.New(), name: name}, priority: priority}
+	}
+	var (
+		failed     = newProvider("Failed", 300)
+		incomplete = newProvider("Incomplete", 200)
+		complete   = newProvider("Complete", 100)
+	)
+	failed.err = mt.ErrIncompleteMetadata
+	incomplete.incomplete = true
+
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{
+		"FAILED":     failed,
+		"INCOMPLETE": incomplete,
+		"COMPLETE":   complete,
+	}
+
+	_, err := e.GetMovieInfoByNumber(context.Background(), " ")
+	assert.ErrorIs(t, err, mt.ErrInvalidKeyword)
+
+	match, err := e.GetMovieInfoByNumber(context.Background(), "fake-001")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Complete", match.Provider)
+		assert.Equal(t, "FAKE-001", match.Info.Number)
+		assert.Equal(t, []string{"Failed", "Incomplete"}, match.Tried)
+	}
+
+	// the most complete one is chosen if none is complete.
+	delete(e.movieProviders, "COMPLETE")
+	match, err = e.GetMovieInfoByNumber(context.Background(), "FAKE-001")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Incomplete", match.Provider)
+	}
+
+	delete(e.movieProviders, "INCOMPLETE")
+	_, err = e.GetMovieInfoByNumber(context.Background(), "FAKE-001")
+	assert.ErrorIs(t, err, mt.ErrIncompleteMetadata)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = e.GetMovieInfoByNumber(ctx, "FAKE-001")
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,44 @@
+package engine
+
+import (
+	"net/http"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// GetMovieInfoFromHTML parses the movie page saved manually, e.g., behind
+// logins the SDK can't do, by the selectors of the provider, as if it was
+// fetched from sourceURL. Nothing else is fetched, so fields of other pages
+// are missing. The info is stored only if the movie is not stored yet, and
+// it's not counted in the fetch statistics.
+func (e *Engine) GetMovieInfoFromHTML(name string, html []byte, sourceURL string) (*model.MovieInfo, error) {
+	provider, err := e.GetMovieProviderByName(name)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := provider.(mt.FetchClientSetter); !ok {
+		return nil, mt.ErrOfflineNotSupported
+	}
+	id, err := provider.ParseMovieIDFromURL(sourceURL)
+	switch {
+	case err != nil:
+		return nil, err
+	case id == "":
+		return nil, mt.ErrInvalidURL
+	}
+	return e.getMovieInfoWithCallback(provider, id, false, true, func() (*model.MovieInfo, error) {
+		// a new instance, as its backend is replaced by the page.
+		instance, err := e.newMovieProvider(provider.Name())
+		if err != nil {
+			return nil, err
+		}
+		instance.(mt.FetchClientSetter).SetFetchClient(fetch.NewFixtureClient(&fetch.Fixture{
+			URL:    sourceURL,
+			Header: http.Header{"Content-Type": {"text/html"}},
+			Body:   html,
+		}))
+		return instance.GetMovieInfoByURL(sourceURL)
+	})
+}
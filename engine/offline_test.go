@@ -0,0 +1,72 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+	"github.com/metatube-community/metatube-sdk-go/provider/fc2"
+)
+
+const fc2ArticleHTML = `<html><body>
+<div class="items_article_headerInfo">
+  <h3>Saved Article</h3>
+  <ul><li>by <a href="/users/1/">Seller</a></li></ul>
+  <div class="items_article_Releasedate"><p>Release date : 2021/06/05</p></div>
+</div>
+<div class="items_article_MainitemThumb"><span><img src="https://storage.example.com/thumb.jpg"></span></div>
+<section class="items_article_SampleImages"><ul>
+  <li><a href="https://storage.example.com/sample1.jpg"><img src="https://storage.example.com/sample1_s.jpg" alt=" Opening "></a></li>
+  <li><a href="https://storage.example.com/sample2.jpg"><img src="https://storage.example.com/sample2_s.jpg"></a></li>
+</ul></section>
+</body></html>`
+
+func TestEngine_GetMovieInfoFromHTML(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	p := fc2.New()
+	e.movieProviders = map[string]mt.MovieProvider{"FC2": p}
+
+	const sourceURL = "https://adult.contents.fc2.com/article/1234567/"
+	info, err := e.GetMovieInfoFromHTML(fc2.Name, []byte(fc2ArticleHTML), sourceURL)
+	require.NoError(t, err)
+	assert.Equal(t, "1234567", info.ID)
+	assert.Equal(t, "Saved Article", info.Title)
+	assert.Equal(t, "Seller", info.Maker)
+	assert.Equal(t, "https://storage.example.com/thumb.jpg", info.CoverURL)
+	if artwork := info.Artwork(model.ScreenshotArtwork, 1); assert.NotNil(t, artwork) {
+		assert.Equal(t, "https://storage.example.com/sample1.jpg", artwork.URL)
+		assert.Equal(t, "Opening", artwork.Caption)
+	}
+	if artwork := info.Artwork(model.ScreenshotArtwork, 2); assert.NotNil(t, artwork) {
+		assert.Empty(t, artwork.Caption)
+	}
+	if assert.NotNil(t, info.Source) {
+		assert.Equal(t, sourceURL, info.Source.URL)
+	}
+
+	// stored as scraped ones are, if not stored yet.
+	stored, err := e.getMovieInfoFromDB(p, "1234567")
+	require.NoError(t, err)
+	assert.Equal(t, "Saved Article", stored.Title)
+
+	// but never replaces the stored one.
+	require.NoError(t, e.db.Model(stored).Update("title", "Scraped Article").Error)
+	_, err = e.GetMovieInfoFromHTML(fc2.Name, []byte(fc2ArticleHTML), sourceURL)
+	require.NoError(t, err)
+	stored, err = e.getMovieInfoFromDB(p, "1234567")
+	require.NoError(t, err)
+	assert.Equal(t, "Scraped Article", stored.Title)
+
+	// nor is it counted in the fetch statistics.
+	_, err = e.GetMovieInfoFromHTML(fc2.Name, []byte("<html></html>"), sourceURL)
+	assert.Error(t, err)
+	assert.Empty(t, e.failureStats)
+
+	e.movieProviders["FAKE"] = fake.New()
+	_, err = e.GetMovieInfoFromHTML(fake.Name, nil, "https://fake.example.com/movies/FAKE-001")
+	assert.ErrorIs(t, err, mt.ErrOfflineNotSupported)
+}
@@ -0,0 +1,306 @@
+package engine
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/common/bandwidth"
+	"github.com/metatube-community/metatube-sdk-go/common/dlock"
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/httpcache"
+	"github.com/metatube-community/metatube-sdk-go/common/redact"
+	"github.com/metatube-community/metatube-sdk-go/common/socks"
+	"github.com/metatube-community/metatube-sdk-go/common/storage"
+	"github.com/metatube-community/metatube-sdk-go/export"
+	"github.com/metatube-community/metatube-sdk-go/media"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/notify"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+type Option func(*Engine)
+
+// WithParseMode sets the parse mode of movie infos.
+func WithParseMode(mode ParseMode) Option {
+	return func(e *Engine) { e.parseMode = mode }
+}
+
+// WithSearchDeadline sets the total deadline of searching from all providers,
+// each provider is given a sub-timeout derived from its priority, so that the
+// aggregated search always returns in time even if some providers hang.
+// The deadline is disabled if d <= 0.
+func WithSearchDeadline(d time.Duration) Option {
+	return func(e *Engine) { e.searchDeadline = d }
+}
+
+// WithSubRequestTimeout sets the timeout of nested sub-requests of scrapes,
+// e.g., of HLS playlists, so that a slow CDN can't consume the whole
+// request timeout. It's disabled if d <= 0.
+func WithSubRequestTimeout(d time.Duration) Option {
+	return func(e *Engine) { e.subTimeout = d }
+}
+
+// WithArchiveFallback enables fetching removed provider pages, e.g., of
+// dead studios or delisted articles, from the Internet Archive. Infos of
+// archived pages are flagged by their sources, see model.Source.
+func WithArchiveFallback(v bool) Option {
+	return func(e *Engine) { e.archiveFallback = v }
+}
+
+// WithProviderPoolSize sets the max number of instances of each provider
+// used concurrently. Providers are safe for concurrent use, but separate
+// instances don't share HTTP clients, which suits heavy parallel use.
+// Pooling is disabled if size <= 1.
+func WithProviderPoolSize(size int) Option {
+	return func(e *Engine) { e.poolSize = size }
+}
+
+// WithActorImagePackURL sets the root URL of the community actor avatar
+// pack, which must share the same layout with the gfriends repository.
+// The gfriends repository is used by default.
+func WithActorImagePackURL(rawURL string) Option {
+	return func(e *Engine) { e.actorImagePackURL = rawURL }
+}
+
+// WithPreferredLanguages sets the preferred languages of texts, e.g., of
+// canonical studio names. Infos are localized by the language of each
+// call instead, see GetLocalizedMovieInfoByProviderID.
+func WithPreferredLanguages(langs ...string) Option {
+	return func(e *Engine) { e.languages = langs }
+}
+
+type credentials struct {
+	username string
+	password string
+}
+
+// WithCredentials sets the account of the realm, e.g., `d2pass`, which
+// enables member-only contents of providers of the realm.
+func WithCredentials(realm, username, password string) Option {
+	return func(e *Engine) {
+		if realm == "" || username == "" {
+			return
+		}
+		if e.credentials == nil {
+			e.credentials = make(map[string]credentials)
+		}
+		e.credentials[realm] = credentials{username: username, password: password}
+	}
+}
+
+// WithPrivacyMode redacts titles, numbers, keywords and URLs in logs as
+// keyed hashes, which is required by operators serving others.
+func WithPrivacyMode(v bool) Option {
+	return func(e *Engine) {
+		if v {
+			e.redactor = redact.New(nil)
+		}
+	}
+}
+
+// WithImageCacheDir caches source images fetched from providers in dir
+// for a week, so that repeated image requests are served from disk.
+func WithImageCacheDir(dir string) Option {
+	return func(e *Engine) {
+		if dir == "" {
+			return
+		}
+		e.imageCache = httpcache.NewStore(dir, DefaultImageCacheTTL)
+	}
+}
+
+// WithImageCache caches source images in the cache, e.g., a Redis shared
+// by server instances or any storage.KV, which overrides WithImageCacheDir.
+func WithImageCache(cache httpcache.Cache) Option {
+	return func(e *Engine) {
+		if cache == nil {
+			return
+		}
+		e.imageCache = cache
+	}
+}
+
+// WithSessionStore persists member sessions of providers in the store, so
+// that providers don't log in again after restarts.
+func WithSessionStore(store storage.KV) Option {
+	return func(e *Engine) { e.sessionStore = store }
+}
+
+// WithMovieChangeHandler sets the handler called when a stored movie
+// info is refreshed with changes, e.g., to notify subscribers.
+func WithMovieChangeHandler(handler func(old, new *model.MovieInfo, changes []*model.FieldChange)) Option {
+	return func(e *Engine) { e.movieChangeHandler = handler }
+}
+
+// WithDevCacheDir caches all successful GET responses of providers in dir,
+// and replays them on later requests, which lets parsers be iterated on
+// offline without hammering the live sites. It is for development only.
+func WithDevCacheDir(dir string) Option {
+	return func(e *Engine) {
+		if dir == "" {
+			return
+		}
+		e.transportWrappers = append(e.transportWrappers, httpcache.Wrapper(dir))
+	}
+}
+
+// WithTransportWrapper wraps the HTTP transports of all providers.
+func WithTransportWrapper(wrapper func(http.RoundTripper) http.RoundTripper) Option {
+	return func(e *Engine) { e.transportWrappers = append(e.transportWrappers, wrapper) }
+}
+
+// WithFetchClient replaces the HTTP backends of providers by the clients
+// returned by the function with the provider name, e.g., headless browsers
+// or recorded fixtures. Providers with nil clients are kept as is.
+func WithFetchClient(fn func(provider string) fetch.Client) Option {
+	return func(e *Engine) { e.fetchClient = fn }
+}
+
+// WithBandwidthMeter accounts bytes downloaded from each provider by its
+// name, and stops requests once the cap or quota of the meter is used up.
+func WithBandwidthMeter(m *bandwidth.Meter) Option {
+	return func(e *Engine) { e.bandwidth = m }
+}
+
+// WithNotifier announces events, e.g., new movies scraped and provider
+// outages, by the notifier.
+func WithNotifier(n notify.Notifier) Option {
+	return func(e *Engine) { e.notifier = n }
+}
+
+// WithSelectorPatches overrides or supplements selectors of info fields by
+// provider names, so that site layout changes can be hotfixed before the
+// providers are updated.
+func WithSelectorPatches(patches map[string][]*mt.SelectorPatch) Option {
+	return func(e *Engine) {
+		e.selectorPatches = make(map[string][]*mt.SelectorPatch, len(patches))
+		for name, p := range patches {
+			e.selectorPatches[strings.ToUpper(name)] = p
+		}
+	}
+}
+
+// WithHostOverrides pins hostnames requested by providers to static IPs,
+// and overrides their `Host` headers, by provider names.
+func WithHostOverrides(overrides map[string][]*mt.HostOverride) Option {
+	return func(e *Engine) {
+		e.hostOverrides = make(map[string][]*mt.HostOverride, len(overrides))
+		for name, o := range overrides {
+			e.hostOverrides[strings.ToUpper(name)] = o
+		}
+	}
+}
+
+// WithSoftNotFound detects pages of missing contents served with 200 by
+// provider names, e.g., "content not found" templates or placeholder
+// covers, which are reported as not found instead of stored as infos.
+func WithSoftNotFound(detectors map[string]*mt.SoftNotFound) Option {
+	return func(e *Engine) {
+		e.softNotFound = make(map[string]*mt.SoftNotFound, len(detectors))
+		for name, d := range detectors {
+			e.softNotFound[strings.ToUpper(name)] = d
+		}
+	}
+}
+
+// WithPlaceholderImages detects placeholder images, e.g., "now printing"
+// covers of movies not yet released, by SHA-256 hashes in hex in addition
+// to the built-in file names, which are excluded from artwork and the
+// completeness, see RefreshPlaceholderImages.
+func WithPlaceholderImages(hashes ...string) Option {
+	return func(e *Engine) { e.placeholderHashes = hashes }
+}
+
+// WithPrefetch scrapes up to the number of actors and recent series
+// entries of new movies scraped in background, so that they are served
+// from the DB later. Entries scraped this way don't prefetch further.
+func WithPrefetch(actors, series int) Option {
+	return func(e *Engine) {
+		if actors <= 0 && series <= 0 {
+			return
+		}
+		e.prefetcher = newPrefetcher(max(actors, 0), max(series, 0))
+	}
+}
+
+// WithLazyFields skips the expensive fields by getting movie infos from
+// providers supporting it, which are resolved by EnrichMovieInfo on
+// demand instead.
+func WithLazyFields(fields ...mt.Field) Option {
+	return func(e *Engine) { e.lazyFields = fields }
+}
+
+// WithPriorityTuning tunes the effective priorities of providers by the
+// success rate, latency and completeness measured over the sliding window,
+// which only lower the static priorities of providers performing poorly.
+// It's disabled if window is not positive.
+func WithPriorityTuning(window time.Duration) Option {
+	return func(e *Engine) {
+		if window <= 0 {
+			return
+		}
+		e.tuner = newPriorityTuner(window)
+	}
+}
+
+// wildcardProvider matches providers not configured by names.
+const wildcardProvider = "*"
+
+// WithProxies routes providers through the SOCKS5 proxy chains by
+// provider names, and `*` for the rest of providers. Each provider is
+// isolated by its name, e.g., on different Tor circuits.
+func WithProxies(proxies map[string]*socks.Dialer) Option {
+	return func(e *Engine) {
+		e.proxies = make(map[string]*socks.Dialer, len(proxies))
+		for name, d := range proxies {
+			if name == wildcardProvider {
+				e.proxies[name] = d
+				continue
+			}
+			e.proxies[strings.ToUpper(name)] = d.Isolate(strings.ToUpper(name))
+		}
+	}
+}
+
+// WithMovieProcessors appends processors of movie infos returned, e.g.,
+// title cleanup. They run before manual overrides, and the infos stored
+// are kept unprocessed.
+func WithMovieProcessors(processors ...func(*model.MovieInfo)) Option {
+	return func(e *Engine) { e.movieProcessors = append(e.movieProcessors, processors...) }
+}
+
+// WithActorProcessors appends processors of actor infos returned, see
+// WithMovieProcessors.
+func WithActorProcessors(processors ...func(*model.ActorInfo)) Option {
+	return func(e *Engine) { e.actorProcessors = append(e.actorProcessors, processors...) }
+}
+
+// WithContentRatings sets the content ratings emitted to targets, which
+// fall back to export.DefaultRatings.
+func WithContentRatings(ratings export.Ratings) Option {
+	return func(e *Engine) { e.contentRatings = ratings }
+}
+
+// WithLocker sets the locker of scrapes, which should be shared by the
+// instances of a deployment, so that the same movie is never scraped
+// concurrently. Scrapes are only locked in process by default.
+func WithLocker(locker dlock.Locker) Option {
+	return func(e *Engine) {
+		if locker == nil {
+			return
+		}
+		e.locker = locker
+	}
+}
+
+// WithTrailerRemuxer remuxes HLS trailers into MP4 videos by the remuxer,
+// e.g., *media.FFmpegRemuxer, HLS trailers can't be written otherwise.
+func WithTrailerRemuxer(remuxer media.Remuxer) Option {
+	return func(e *Engine) { e.trailerRemuxer = remuxer }
+}
+
+// WithFilters drops or flags results and infos by the content filters.
+func WithFilters(filters *Filters) Option {
+	return func(e *Engine) { e.filters = filters }
+}
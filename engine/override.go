@@ -0,0 +1,198 @@
+package engine
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/metatube-community/metatube-sdk-go/common/studio"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+var (
+	ErrInvalidOverride  = errors.New(http.StatusBadRequest, "invalid override")
+	ErrOverrideNotFound = errors.New(http.StatusNotFound, "override not found")
+)
+
+// GetOverride returns the override of the info.
+func (e *Engine) GetOverride(kind, name, id string) (*model.Override, error) {
+	provider, err := e.overrideProvider(kind, name)
+	if err != nil {
+		return nil, err
+	}
+	o := &model.Override{}
+	if err = e.db.
+		Where("kind = ? AND provider = ?", kind, provider).
+		Where("id = ? COLLATE NOCASE", id).
+		First(o).Error; err != nil {
+		return nil, ErrOverrideNotFound
+	}
+	return o, nil
+}
+
+// SetOverride merges fields into the override of the info, the fields
+// are JSON names and values of the info, primary keys are not allowed.
+func (e *Engine) SetOverride(kind, name, id string, fields map[string]json.RawMessage) (*model.Override, error) {
+	provider, err := e.overrideProvider(kind, name)
+	if err != nil {
+		return nil, err
+	}
+	o, err := e.GetOverride(kind, provider, id)
+	if err != nil {
+		o = &model.Override{Kind: kind, Provider: provider, ID: id}
+	}
+	if o.Fields == nil {
+		o.Fields = make(map[string]json.RawMessage)
+	}
+	for k, v := range fields {
+		o.Fields[k] = v
+	}
+	if err = validateOverride(o); err != nil {
+		return nil, err
+	}
+	return o, e.db.Save(o).Error
+}
+
+// ClearOverride removes fields from the override of the info, or the
+// whole override if no fields given.
+func (e *Engine) ClearOverride(kind, name, id string, fields ...string) error {
+	o, err := e.GetOverride(kind, name, id)
+	if err != nil {
+		return err
+	}
+	for _, k := range fields {
+		delete(o.Fields, k)
+	}
+	if len(fields) == 0 || len(o.Fields) == 0 {
+		return e.db.Delete(o).Error
+	}
+	return e.db.Save(o).Error
+}
+
+// applyOverride applies the override if any to the info read.
+func (e *Engine) applyOverride(kind, provider, id string, info any) {
+	if o, err := e.GetOverride(kind, provider, id); err == nil {
+		_ = o.Apply(info) // validated on set.
+		if m, ok := info.(*model.MovieInfo); ok && m.Source != nil && len(m.Source.Placeholders) > 0 {
+			// images set manually are never placeholders.
+			source := *m.Source
+			source.Placeholders = slices.DeleteFunc(slices.Clone(source.Placeholders), func(field string) bool {
+				_, ok := o.Fields[field]
+				return ok
+			})
+			m.Source = &source
+		}
+	}
+}
+
+// mergeMovieOverride rebases the override of the stored movie onto the
+// new provider data by three-way merge, so that manual edits are kept
+// while upstream changes of edited arrays are picked up.
+func (e *Engine) mergeMovieOverride(base, theirs *model.MovieInfo) error {
+	o, err := e.GetOverride(model.MovieKind, base.Provider, base.ID)
+	if err != nil {
+		return nil // no override.
+	}
+	ours := &model.MovieInfo{}
+	if err = deepCopyJSON(base, ours); err != nil {
+		return err
+	}
+	if err = o.Apply(ours); err != nil {
+		return err
+	}
+	// studios respelled by the provider are not upstream changes.
+	respelled := *theirs
+	if studio.Same(base.Maker, theirs.Maker) {
+		respelled.Maker = base.Maker
+	}
+	if studio.Same(base.Label, theirs.Label) {
+		respelled.Label = base.Label
+	}
+	// actors unioned are reconciled, so that the same performers spelled
+	// differently by the user and the provider are listed once.
+	info := model.MergeMovieInfo(base, ours, &respelled)
+	e.reconcileMovieActors(info)
+	merged := make(map[string]json.RawMessage)
+	if err = deepCopyJSON(info, &merged); err != nil {
+		return err
+	}
+	before := jsonFields(overrideFields(o))
+	for k := range o.Fields {
+		o.Fields[k] = merged[k]
+	}
+	if err = e.db.Save(o).Error; err != nil {
+		return err
+	}
+	if changes := diff(before, overrideFields(o)); len(changes) > 0 {
+		return e.systemAuditor().record(model.MergeAuditAction, model.MovieKind, o.Provider, o.ID, changes)
+	}
+	return nil
+}
+
+func deepCopyJSON(src, dst any) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, dst)
+}
+
+func (e *Engine) overrideProvider(kind, name string) (string, error) {
+	var (
+		provider mt.Provider
+		err      error
+	)
+	switch kind {
+	case model.MovieKind:
+		provider, err = e.GetMovieProviderByName(name)
+	case model.ActorKind:
+		provider, err = e.GetActorProviderByName(name)
+	default:
+		return "", ErrInvalidOverride
+	}
+	if err != nil {
+		return "", err
+	}
+	return provider.Name(), nil
+}
+
+func validateOverride(o *model.Override) error {
+	var info any
+	switch o.Kind {
+	case model.MovieKind:
+		info = &model.MovieInfo{}
+	case model.ActorKind:
+		info = &model.ActorInfo{}
+	}
+	known := jsonNames(reflect.TypeOf(info).Elem())
+	for k := range o.Fields {
+		if _, ok := known[k]; !ok || k == "id" || k == "provider" {
+			return ErrInvalidOverride
+		}
+	}
+	if !o.Valid() || o.Apply(info) != nil {
+		return ErrInvalidOverride
+	}
+	return nil
+}
+
+// jsonNames returns JSON names of exported fields of the struct type.
+func jsonNames(t reflect.Type) map[string]struct{} {
+	names := make(map[string]struct{})
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		names[name] = struct{}{}
+	}
+	return names
+}
@@ -0,0 +1,186 @@
+package engine
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+	"github.com/metatube-community/metatube-sdk-go/provider/gfriends"
+)
+
+// placeholderFake is a fake provider of which covers are placeholders.
+type placeholderFake struct {
+	*fake.Fake
+}
+
+func (f *placeholderFake) GetMovieInfoByID(id string) (*model.MovieInfo, error) {
+	info, err := f.Fake.GetMovieInfoByID(id)
+	if err == nil {
+		info.CoverURL = "https://fake.metatube.invalid/images/now_printing.jpg"
+	}
+	return info, err
+}
+
+func TestEngine_SetOverride(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": fake.New()}
+
+	for _, unit := range []struct {
+		kind, name string
+		fields     string
+		err        error
+	}{
+		{"unknown", "fake", `{"title":"Title"}`, ErrInvalidOverride},
+		{model.MovieKind, "unknown", `{"title":"Title"}`, mt.ErrProviderNotFound},
+		{model.MovieKind, "fake", `{}`, ErrInvalidOverride},
+		{model.MovieKind, "fake", `{"id":"FAKE-002"}`, ErrInvalidOverride},
+		{model.MovieKind, "fake", `{"provider":"Other"}`, ErrInvalidOverride},
+		{model.MovieKind, "fake", `{"unknown":"value"}`, ErrInvalidOverride},
+		{model.MovieKind, "fake", `{"runtime":"long"}`, ErrInvalidOverride},
+	} {
+		var fields map[string]json.RawMessage
+		require.NoError(t, json.Unmarshal([]byte(unit.fields), &fields))
+		_, err := e.SetOverride(unit.kind, unit.name, "FAKE-001", fields)
+		assert.Equal(t, unit.err, err, unit.fields)
+	}
+	_, err := e.GetOverride(model.MovieKind, "fake", "FAKE-001")
+	assert.Equal(t, ErrOverrideNotFound, err)
+
+	o, err := e.SetOverride(model.MovieKind, "fake", "FAKE-001", map[string]json.RawMessage{
+		"title": json.RawMessage(`"Manual Title"`),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, fake.Name, o.Provider) // canonical name.
+	_, err = e.SetOverride(model.MovieKind, "FAKE", "FAKE-001", map[string]json.RawMessage{
+		"runtime": json.RawMessage(`120`),
+	})
+	require.NoError(t, err)
+
+	// fields are merged.
+	o, err = e.GetOverride(model.MovieKind, "fake", "fake-001")
+	require.NoError(t, err)
+	assert.Len(t, o.Fields, 2)
+
+	// overrides are applied on every read, but never stored into infos.
+	for _, lazy := range []bool{false, true} {
+		info, err := e.GetMovieInfoByProviderID("fake", "FAKE-001", lazy)
+		require.NoError(t, err)
+		assert.Equal(t, "Manual Title", info.Title)
+		assert.Equal(t, 120, info.Runtime)
+	}
+	var stored model.MovieInfo
+	require.NoError(t, e.db.Where("id = ?", "FAKE-001").First(&stored).Error)
+	assert.Equal(t, "Fake Movie 001", stored.Title)
+
+	require.NoError(t, e.ClearOverride(model.MovieKind, "fake", "FAKE-001", "title"))
+	info, err := e.GetMovieInfoByProviderID("fake", "FAKE-001", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Fake Movie 001", info.Title)
+	assert.Equal(t, 120, info.Runtime)
+
+	require.NoError(t, e.ClearOverride(model.MovieKind, "fake", "FAKE-001"))
+	_, err = e.GetOverride(model.MovieKind, "fake", "FAKE-001")
+	assert.Equal(t, ErrOverrideNotFound, err)
+	assert.Equal(t, ErrOverrideNotFound, e.ClearOverride(model.MovieKind, "fake", "FAKE-001"))
+	info, err = e.GetMovieInfoByProviderID("fake", "FAKE-001", true)
+	require.NoError(t, err)
+	assert.Equal(t, 61, info.Runtime)
+}
+
+func TestEngine_ActorOverride(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.actorProviders = map[string]mt.ActorProvider{
+		"GFRIENDS": &galleryFake{Fake: fake.New(), name: gfriends.Name},
+		"FAKE":     fake.New(),
+	}
+
+	_, err := e.SetOverride(model.ActorKind, "fake", "1", map[string]json.RawMessage{
+		"name":    json.RawMessage(`"Manual Name"`),
+		"aliases": json.RawMessage(`["Alias"]`),
+	})
+	require.NoError(t, err)
+	info, err := e.GetActorInfoByProviderID("fake", "1", true)
+	require.NoError(t, err)
+	assert.Equal(t, "Manual Name", info.Name)
+	assert.Equal(t, []string{"Alias"}, []string(info.Aliases))
+}
+
+func TestEngine_OverrideFilteredMaker(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": fake.New()}
+	e.filters = &Filters{Block: []*FilterRule{{Makers: []string{"Fake Studio"}}}}
+
+	_, err := e.GetMovieInfoByProviderID("fake", "FAKE-001", true)
+	require.Equal(t, ErrFilteredOut, err)
+
+	// the override is applied before filtering.
+	_, err = e.SetOverride(model.MovieKind, "fake", "FAKE-001", map[string]json.RawMessage{
+		"maker": json.RawMessage(`""`),
+	})
+	require.NoError(t, err)
+	info, err := e.GetMovieInfoByProviderID("fake", "FAKE-001", true)
+	require.NoError(t, err)
+	assert.Empty(t, info.Maker)
+}
+
+func TestEngine_OverridePlaceholders(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": &placeholderFake{Fake: fake.New()}}
+
+	info, err := e.GetMovieInfoByProviderID("fake", "FAKE-001", true)
+	require.NoError(t, err)
+	require.NotNil(t, info.Source)
+	assert.Contains(t, info.Source.Placeholders, "cover_url")
+
+	// images set manually are never placeholders.
+	_, err = e.SetOverride(model.MovieKind, "fake", "FAKE-001", map[string]json.RawMessage{
+		"cover_url": json.RawMessage(`"https://example.com/cover.jpg"`),
+	})
+	require.NoError(t, err)
+	info, err = e.GetMovieInfoByProviderID("fake", "FAKE-001", true)
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/cover.jpg", info.CoverURL)
+	assert.NotContains(t, info.Source.Placeholders, "cover_url")
+}
+
+func TestEngine_MergeMovieOverride(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	f := &genresFake{Fake: fake.New()}
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": f}
+
+	base, err := e.GetMovieInfoByProviderID("fake", "FAKE-001", true)
+	require.NoError(t, err)
+	require.Len(t, base.Genres, 2)
+
+	// the second genre is removed and one is added manually.
+	_, err = e.SetOverride(model.MovieKind, "fake", "FAKE-001", map[string]json.RawMessage{
+		"title":  json.RawMessage(`"Manual Title"`),
+		"genres": json.RawMessage(`["Fake","Mine"]`),
+	})
+	require.NoError(t, err)
+
+	// unchanged upstream.
+	info, err := e.GetMovieInfoByProviderID("fake", "FAKE-001", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Fake", "Mine"}, []string(info.Genres))
+
+	// the genre added upstream is picked up, and manual edits are kept.
+	f.genres = []string{"Upstream"}
+	info, err = e.GetMovieInfoByProviderID("fake", "FAKE-001", false)
+	require.NoError(t, err)
+	assert.Equal(t, "Manual Title", info.Title)
+	assert.ElementsMatch(t, []string{"Fake", "Mine", "Upstream"}, []string(info.Genres))
+
+	o, err := e.GetOverride(model.MovieKind, "fake", "FAKE-001")
+	require.NoError(t, err)
+	assert.JSONEq(t, `"Manual Title"`, string(o.Fields["title"]))
+	var genres []string
+	require.NoError(t, json.Unmarshal(o.Fields["genres"], &genres))
+	assert.ElementsMatch(t, []string{"Fake", "Mine", "Upstream"}, genres)
+	assert.Len(t, o.Fields, 2) // fields not edited are not locked.
+}
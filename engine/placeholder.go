@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// placeholderMarkers are file names of placeholder images in URL paths,
+// e.g., `now_printing.jpg` of FANZA.
+var placeholderMarkers = []string{"now_printing", "nowprinting", "noimage", "no_image"}
+
+// isPlaceholderURL reports whether the image URL is of a placeholder by
+// its path.
+func isPlaceholderURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	p := strings.ToLower(u.Path)
+	for _, marker := range placeholderMarkers {
+		if strings.Contains(p, marker) {
+			return true
+		}
+	}
+	return false
+}
+
+// flagPlaceholders flags the image fields of placeholder images of the
+// info, by paths of their URLs, or by hashes of the images if configured.
+// Images are only hashed if their URLs are changed from the stored info, or
+// were placeholders, which are then fetched bypassing the image cache, as
+// placeholders are usually replaced at the same URLs.
+func (e *Engine) flagPlaceholders(provider mt.MovieProvider, info *model.MovieInfo) {
+	var stored map[string]string // field -> URL of non-placeholder images.
+	if len(e.placeholderHashes) > 0 {
+		if old, err := e.getMovieInfoFromDB(provider, info.ID); err == nil {
+			stored = make(map[string]string)
+			for _, image := range movieImages(old) {
+				if !old.Source.Placeholder(image.field) {
+					stored[image.field] = image.url
+				}
+			}
+		}
+	}
+	checked := make(map[string]bool)
+	for _, image := range movieImages(info) {
+		if image.url == "" {
+			continue
+		}
+		placeholder, ok := checked[image.url]
+		if !ok {
+			placeholder = isPlaceholderURL(image.url) ||
+				(stored[image.field] != image.url && // changed or placeholder.
+					e.matchImageHash(provider, image.url, e.placeholderHashes, true))
+			checked[image.url] = placeholder
+		}
+		if placeholder {
+			info.SetPlaceholder(image.field)
+		}
+	}
+}
+
+// movieImages returns the image fields of the info.
+func movieImages(info *model.MovieInfo) []struct{ field, url string } {
+	return []struct{ field, url string }{
+		{"cover_url", info.CoverURL},
+		{"big_cover_url", info.BigCoverURL},
+		{"thumb_url", info.ThumbURL},
+		{"big_thumb_url", info.BigThumbURL},
+	}
+}
+
+// movieImageURL returns the URL of the image field of the info, or empty
+// if it's a placeholder.
+func movieImageURL(info *model.MovieInfo, field, url string) string {
+	if info.Source.Placeholder(field) {
+		return ""
+	}
+	return url
+}
+
+// RefreshPlaceholderImages re-scrapes stored movies with placeholder
+// images retrieved before the age, e.g., "now printing" covers of movies
+// not yet released, so that real images are picked up once available.
+// Failed scrapes are retried next time. It returns the number of movies
+// of which placeholders are gone.
+func (e *Engine) RefreshPlaceholderImages(age time.Duration) (n int, err error) {
+	var infos []*model.MovieInfo
+	if err = e.db.
+		Where("source LIKE ?", `%"placeholders"%`).
+		Find(&infos).Error; err != nil {
+		return 0, err
+	}
+	before := time.Now().Add(-age)
+	for _, info := range infos {
+		if len(info.Source.Placeholders) == 0 || info.Source.RetrievedAt.After(before) {
+			continue
+		}
+		fresh, err := e.systemAuditor().GetMovieInfoByProviderID(info.Provider, info.ID, false)
+		if err != nil {
+			e.logger.Warnw("placeholder refresh failed", "provider", info.Provider,
+				"id", e.redactor.String(info.ID), "error", e.redactor.Error(err, info.ID))
+			continue
+		}
+		if fresh.Source == nil || len(fresh.Source.Placeholders) == 0 {
+			n++
+		}
+	}
+	return n, nil
+}
@@ -0,0 +1,158 @@
+package engine
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// providerPool is a bounded pool of provider instances of the same kind.
+// Instances are allocated lazily, and callers are blocked when all of
+// them are in use.
+type providerPool[T mt.Provider] struct {
+	mu        sync.Mutex
+	size      int
+	allocated int
+	factory   func() (T, error)
+	instances chan T
+}
+
+func newProviderPool[T mt.Provider](size int, factory func() (T, error)) *providerPool[T] {
+	return &providerPool[T]{
+		size:      size,
+		factory:   factory,
+		instances: make(chan T, size),
+	}
+}
+
+// Get takes an idle instance from the pool or allocates a new one.
+func (p *providerPool[T]) Get() (T, error) {
+	return p.GetContext(context.Background())
+}
+
+// GetContext is like Get, but stops waiting for an instance to be
+// returned when ctx is done.
+func (p *providerPool[T]) GetContext(ctx context.Context) (T, error) {
+	select {
+	case v := <-p.instances:
+		return v, nil
+	default:
+	}
+	p.mu.Lock()
+	if p.allocated < p.size {
+		p.allocated++
+		p.mu.Unlock()
+		v, err := p.factory()
+		if err != nil {
+			p.mu.Lock()
+			p.allocated--
+			p.mu.Unlock()
+		}
+		return v, err
+	}
+	p.mu.Unlock()
+	// wait for an instance to be returned.
+	select {
+	case v := <-p.instances:
+		return v, nil
+	case <-ctx.Done():
+		var zero T
+		return zero, ctx.Err()
+	}
+}
+
+// Put returns the instance to the pool.
+func (p *providerPool[T]) Put(v T) {
+	p.instances <- v
+}
+
+type providerPools struct {
+	mu    sync.Mutex
+	actor map[string]*providerPool[mt.ActorProvider]
+	movie map[string]*providerPool[mt.MovieProvider]
+}
+
+// acquireMovieProvider returns a pooled instance of the given provider
+// and a function to release it. The shared instance is returned as is
+// if pooling is disabled.
+func (e *Engine) acquireMovieProvider(provider mt.MovieProvider) (mt.MovieProvider, func()) {
+	instance, release, _ := e.acquireMovieProviderContext(context.Background(), provider)
+	return instance, release
+}
+
+// acquireMovieProviderContext is like acquireMovieProvider, but requests
+// of the pooled instance are canceled when ctx is done, so that it's
+// released soon after its caller stops waiting, e.g., by withTimeout. The
+// error of ctx is returned if it's done before an instance is idle. The
+// shared instance can't be canceled as others are using it.
+func (e *Engine) acquireMovieProviderContext(ctx context.Context, provider mt.MovieProvider) (mt.MovieProvider, func(), error) {
+	if e.poolSize <= 1 {
+		return provider, func() {}, nil
+	}
+	name := strings.ToUpper(provider.Name())
+	e.pools.mu.Lock()
+	pool, ok := e.pools.movie[name]
+	if !ok {
+		pool = newProviderPool(e.poolSize, func() (mt.MovieProvider, error) {
+			return e.newMovieProvider(name)
+		})
+		e.pools.movie[name] = pool
+	}
+	e.pools.mu.Unlock()
+	instance, err := pool.GetContext(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, err
+		}
+		return provider, func() {}, nil // fallback to the shared one.
+	}
+	return instance, releaseContext(ctx, instance, func() { pool.Put(instance) }), nil
+}
+
+// acquireActorProvider is like acquireMovieProvider, but for actor providers.
+func (e *Engine) acquireActorProvider(provider mt.ActorProvider) (mt.ActorProvider, func()) {
+	instance, release, _ := e.acquireActorProviderContext(context.Background(), provider)
+	return instance, release
+}
+
+// acquireActorProviderContext is like acquireMovieProviderContext, but for
+// actor providers.
+func (e *Engine) acquireActorProviderContext(ctx context.Context, provider mt.ActorProvider) (mt.ActorProvider, func(), error) {
+	if e.poolSize <= 1 {
+		return provider, func() {}, nil
+	}
+	name := strings.ToUpper(provider.Name())
+	e.pools.mu.Lock()
+	pool, ok := e.pools.actor[name]
+	if !ok {
+		pool = newProviderPool(e.poolSize, func() (mt.ActorProvider, error) {
+			return e.newActorProvider(name)
+		})
+		e.pools.actor[name] = pool
+	}
+	e.pools.mu.Unlock()
+	instance, err := pool.GetContext(ctx)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, nil, err
+		}
+		return provider, func() {}, nil
+	}
+	return instance, releaseContext(ctx, instance, func() { pool.Put(instance) }), nil
+}
+
+// releaseContext sets ctx to the pooled instance if it supports, and
+// returns the release func unsetting it before put.
+func releaseContext(ctx context.Context, instance mt.Provider, put func()) func() {
+	setter, ok := instance.(mt.ContextSetter)
+	if !ok || ctx.Done() == nil /* never canceled */ {
+		return put
+	}
+	setter.SetContext(ctx)
+	return func() {
+		setter.SetContext(nil)
+		put()
+	}
+}
@@ -0,0 +1,141 @@
+package engine
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// prefetchQueueSize is the max number of pending prefetches, the later
+// ones are dropped rather than delaying scrapes.
+const prefetchQueueSize = 64
+
+type prefetchJob struct {
+	key string
+	run func()
+}
+
+// prefetcher scrapes related entities of new movies in background, one
+// at a time, so that later navigations are served from the DB.
+type prefetcher struct {
+	// max actors and series entries prefetched per movie.
+	actors int
+	series int
+
+	jobs chan prefetchJob
+	mu   sync.Mutex
+	// keys of jobs queued or running.
+	pending map[string]struct{}
+}
+
+func newPrefetcher(actors, series int) *prefetcher {
+	return &prefetcher{
+		actors:  actors,
+		series:  series,
+		jobs:    make(chan prefetchJob, prefetchQueueSize),
+		pending: make(map[string]struct{}),
+	}
+}
+
+func (p *prefetcher) run() {
+	for job := range p.jobs {
+		job.run()
+		p.mu.Lock()
+		delete(p.pending, job.key)
+		p.mu.Unlock()
+	}
+}
+
+// enqueue queues the job unless it's pending or the queue is full.
+func (p *prefetcher) enqueue(key string, run func()) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, ok := p.pending[key]; ok {
+		return
+	}
+	select {
+	case p.jobs <- prefetchJob{key: key, run: run}:
+		p.pending[key] = struct{}{}
+	default: // dropped.
+	}
+}
+
+func (p *prefetcher) isPending(key string) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, ok := p.pending[key]
+	return ok
+}
+
+func prefetchMovieKey(provider, id string) string {
+	return "movie:" + strings.ToUpper(provider) + ":" + strings.ToUpper(id)
+}
+
+// prefetchRelated queues prefetches of the actors and recent series
+// entries of the new movie scraped. Movies scraped by prefetches don't
+// prefetch further, so the depth is bounded to one.
+func (e *Engine) prefetchRelated(info *model.MovieInfo) {
+	p := e.prefetcher
+	if p == nil || p.isPending(prefetchMovieKey(info.Provider, info.ID)) {
+		return
+	}
+	for _, name := range info.Actors[:min(len(info.Actors), p.actors)] {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		p.enqueue("actor:"+strings.ToUpper(name), func() { e.prefetchActor(name) })
+	}
+	if series := strings.TrimSpace(info.Series); series != "" && p.series > 0 {
+		provider, id := info.Provider, info.ID
+		p.enqueue("series:"+strings.ToUpper(provider)+":"+strings.ToUpper(series),
+			func() { e.prefetchSeries(provider, series, id) })
+	}
+}
+
+// prefetchActor scrapes the info of the actor result best matching the
+// name, errors are ignored.
+func (e *Engine) prefetchActor(name string) {
+	results, err := e.SearchActorAll(name, false)
+	if err != nil {
+		return
+	}
+	for _, result := range results {
+		if !strings.EqualFold(result.Name, name) {
+			continue
+		}
+		if provider, err := e.GetActorProviderByName(result.Provider); err == nil {
+			_, _ = e.getActorInfoByProviderID(provider, result.ID, true)
+			return
+		}
+	}
+}
+
+// prefetchSeries queues scrapes of the most recent entries found by the
+// series name from the provider, except the movie itself.
+func (e *Engine) prefetchSeries(name, series, id string) {
+	provider, err := e.GetMovieProviderByName(name)
+	if err != nil {
+		return
+	}
+	results, err := e.searchMovie(context.Background(), series, provider, false)
+	if err != nil {
+		return
+	}
+	results = slices.DeleteFunc(results, func(result *model.MovieSearchResult) bool {
+		return strings.EqualFold(result.ID, id)
+	})
+	slices.SortStableFunc(results, func(a, b *model.MovieSearchResult) int {
+		return time.Time(b.ReleaseDate).Compare(time.Time(a.ReleaseDate))
+	})
+	for _, result := range results[:min(len(results), e.prefetcher.series)] {
+		id := result.ID
+		e.prefetcher.enqueue(prefetchMovieKey(provider.Name(), id), func() {
+			_, _ = e.getMovieInfoByProviderID(provider, id, true)
+		})
+	}
+}
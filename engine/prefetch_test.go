@@ -0,0 +1,65 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+	"github.com/metatube-community/metatube-sdk-go/provider/gfriends"
+)
+
+func TestPrefetch(t *testing.T) {
+	entry := func(id string, year int, actors ...string) *model.MovieInfo {
+		return &model.MovieInfo{
+			ID:          id,
+			Number:      id,
+			Title:       "Fake Series 1 " + id,
+			Series:      "Fake Series 1",
+			Actors:      actors,
+			ReleaseDate: datatypes.Date(time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)),
+		}
+	}
+	fake.SetSeed(&fake.Seed{Movies: []*model.MovieInfo{
+		entry("SERIES-1", 2020),
+		entry("SERIES-2", 2021, "Fake Actor 40"),
+		entry("SERIES-3", 2022, "Fake Actor 41"),
+	}})
+	defer fake.SetSeed(&fake.Seed{})
+
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": fake.New()}
+	e.actorProviders = map[string]mt.ActorProvider{
+		"FAKE": fake.New(),
+		// images of actors are injected from gfriends.
+		strings.ToUpper(gfriends.Name): fake.New(),
+	}
+	e.prefetcher = newPrefetcher(1, 2)
+	go e.prefetcher.run()
+
+	// FAKE-010 belongs to `Fake Series 1`.
+	info, err := e.GetMovieInfoByProviderID(fake.Name, "FAKE-010", true)
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Eventually(t, func() bool {
+		e.prefetcher.mu.Lock()
+		defer e.prefetcher.mu.Unlock()
+		return len(e.prefetcher.pending) == 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	var ids []string
+	e.db.Model(&model.MovieInfo{}).Order("id").Pluck("id", &ids)
+	// the most recent entries only.
+	assert.Equal(t, []string{"FAKE-010", "SERIES-2", "SERIES-3"}, ids)
+
+	var names []string
+	e.db.Model(&model.ActorInfo{}).Pluck("name", &names)
+	// the first actor only, and entries prefetched don't prefetch further.
+	assert.Equal(t, []string{info.Actors[0]}, names)
+}
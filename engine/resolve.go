@@ -0,0 +1,52 @@
+package engine
+
+import (
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// Resolution is the info of a page URL resolved by provider.
+type Resolution struct {
+	Kind     string `json:"kind"`
+	Provider string `json:"provider"`
+	ID       string `json:"id"`
+	Info     any    `json:"info"`
+}
+
+// ResolveURL resolves the owning provider and ID of an arbitrary page URL
+// by the URL patterns of providers, movies take precedence over actors.
+func (e *Engine) ResolveURL(rawURL string) (kind, provider, id string, err error) {
+	if p, id, err := e.router.RouteMovieURL(rawURL); err == nil {
+		return model.MovieKind, p.Name(), id, nil
+	}
+	p, id, err := e.router.RouteActorURL(rawURL)
+	if err != nil {
+		return "", "", "", err
+	}
+	return model.ActorKind, p.Name(), id, nil
+}
+
+// Resolve resolves the page URL, and gets the info of it, which is then
+// stored like any other info, see ResolveURL.
+func (a *Auditor) Resolve(rawURL string, lazy bool) (*Resolution, error) {
+	kind, provider, id, err := a.e.ResolveURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	r := &Resolution{Kind: kind, Provider: provider}
+	switch kind {
+	case model.MovieKind:
+		var info *model.MovieInfo
+		if info, err = a.GetMovieInfoByProviderID(provider, id, lazy); err == nil {
+			r.ID, r.Info = info.ID, info
+		}
+	case model.ActorKind:
+		var info *model.ActorInfo
+		if info, err = a.GetActorInfoByProviderID(provider, id, lazy); err == nil {
+			r.ID, r.Info = info.ID, info
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return r, nil
+}
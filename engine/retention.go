@@ -0,0 +1,144 @@
+package engine
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+var ErrInvalidBlocklistEntry = errors.New(http.StatusBadRequest, "invalid blocklist entry")
+
+// DeleteMovieInfo soft-deletes the stored movie info, which is purged
+// after the retention period, or restored if scraped again before that.
+func (e *Engine) DeleteMovieInfo(name, id string) error {
+	provider, err := e.GetMovieProviderByName(name)
+	if err != nil {
+		return err
+	}
+	return e.db.
+		Where("provider = ?", provider.Name()).
+		Where("id = ? COLLATE NOCASE", id).
+		Delete(&model.MovieInfo{}).Error
+}
+
+// DeleteActorInfo soft-deletes the stored actor info, see DeleteMovieInfo.
+func (e *Engine) DeleteActorInfo(name, id string) error {
+	provider, err := e.GetActorProviderByName(name)
+	if err != nil {
+		return err
+	}
+	return e.db.
+		Where("provider = ?", provider.Name()).
+		Where("id = ? COLLATE NOCASE", id).
+		Delete(&model.ActorInfo{}).Error
+}
+
+// PurgeDeleted permanently deletes infos soft-deleted longer than the
+// retention, and returns the number of rows purged.
+func (e *Engine) PurgeDeleted(retention time.Duration) (n int64, err error) {
+	deadline := time.Now().UTC().Add(-retention)
+	for _, v := range []any{&model.MovieInfo{}, &model.ActorInfo{}} {
+		tx := e.db.Unscoped().Where("deleted_at < ?", deadline).Delete(v)
+		if tx.Error != nil {
+			return n, tx.Error
+		}
+		n += tx.RowsAffected
+	}
+	return
+}
+
+// GetBlocklist returns all blocklist entries.
+func (e *Engine) GetBlocklist() (entries []*model.BlocklistEntry, err error) {
+	err = e.db.Order("provider, number").Find(&entries).Error
+	return
+}
+
+// AddBlocklistEntry adds the entry to the blocklist, and permanently
+// deletes stored infos matched.
+func (e *Engine) AddBlocklistEntry(entry *model.BlocklistEntry) error {
+	if !entry.Valid() {
+		return ErrInvalidBlocklistEntry
+	}
+	if entry.Provider != "" {
+		name, err := e.canonicalProviderName(entry.Provider)
+		if err != nil {
+			return err
+		}
+		entry.Provider = name
+	}
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(entry).Error; err != nil {
+			return err
+		}
+		if err := blocklistScope(tx.Unscoped(), entry.Provider, entry.Number).
+			Delete(&model.MovieInfo{}).Error; err != nil {
+			return err
+		}
+		if entry.Number == "" /* actors are only blocked by provider */ {
+			return tx.Unscoped().
+				Where("provider = ?", entry.Provider).
+				Delete(&model.ActorInfo{}).Error
+		}
+		return nil
+	})
+}
+
+// RemoveBlocklistEntry removes the entry from the blocklist.
+func (e *Engine) RemoveBlocklistEntry(provider, number string) error {
+	if provider != "" {
+		if name, err := e.canonicalProviderName(provider); err == nil {
+			provider = name
+		}
+	}
+	return e.db.
+		Where("provider = ? AND number = ?", provider, number).
+		Delete(&model.BlocklistEntry{}).Error
+}
+
+// isBlocked reports whether infos of the provider and the movie number
+// or id must not be stored, pass empty number for actors.
+func (e *Engine) isBlocked(provider string, numbers ...string) bool {
+	var entries []*model.BlocklistEntry
+	if err := e.db.
+		Where("provider IN ?", []string{provider, ""}).
+		Find(&entries).Error; err != nil {
+		return false // ignore DB query error.
+	}
+	for _, entry := range entries {
+		if entry.Number == "" {
+			return true
+		}
+		for _, number := range numbers {
+			if strings.EqualFold(entry.Number, number) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (e *Engine) canonicalProviderName(name string) (string, error) {
+	if provider, err := e.GetMovieProviderByName(name); err == nil {
+		return provider.Name(), nil
+	}
+	if provider, err := e.GetActorProviderByName(name); err == nil {
+		return provider.Name(), nil
+	}
+	return "", mt.ErrProviderNotFound
+}
+
+func blocklistScope(tx *gorm.DB, provider, number string) *gorm.DB {
+	if provider != "" {
+		tx = tx.Where("provider = ?", provider)
+	}
+	if number != "" {
+		tx = tx.Where("number = ? COLLATE NOCASE OR id = ? COLLATE NOCASE", number, number)
+	}
+	return tx
+}
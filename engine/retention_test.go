@@ -0,0 +1,135 @@
+package engine
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+func newRetentionEngine(t *testing.T) *Engine {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": fake.New()}
+	e.actorProviders = map[string]mt.ActorProvider{"FAKE": fake.New()}
+	return e
+}
+
+func countRows(t *testing.T, e *Engine, v any, unscoped bool) (n int64) {
+	tx := e.db.Model(v)
+	if unscoped {
+		tx = tx.Unscoped()
+	}
+	require.NoError(t, tx.Count(&n).Error)
+	return
+}
+
+func TestEngine_DeleteMovieInfo(t *testing.T) {
+	e := newRetentionEngine(t)
+
+	_, err := e.GetMovieInfoByProviderID("fake", "FAKE-001", true)
+	require.NoError(t, err)
+	require.EqualValues(t, 1, countRows(t, e, &model.MovieInfo{}, false))
+
+	assert.Equal(t, mt.ErrProviderNotFound, e.DeleteMovieInfo("unknown", "FAKE-001"))
+	require.NoError(t, e.DeleteMovieInfo("fake", "fake-001"))
+	assert.EqualValues(t, 0, countRows(t, e, &model.MovieInfo{}, false))
+	assert.EqualValues(t, 1, countRows(t, e, &model.MovieInfo{}, true))
+
+	// restored by scraping again.
+	info, err := e.GetMovieInfoByProviderID("fake", "FAKE-001", true)
+	require.NoError(t, err)
+	assert.Equal(t, "FAKE-001", info.ID)
+	assert.EqualValues(t, 1, countRows(t, e, &model.MovieInfo{}, false))
+	assert.EqualValues(t, 1, countRows(t, e, &model.MovieInfo{}, true))
+}
+
+func TestEngine_PurgeDeleted(t *testing.T) {
+	e := newRetentionEngine(t)
+
+	for _, id := range []string{"FAKE-001", "FAKE-002"} {
+		_, err := e.GetMovieInfoByProviderID("fake", id, true)
+		require.NoError(t, err)
+	}
+	require.NoError(t, e.db.Create(&model.ActorInfo{ID: "1", Name: "Fake Actor 1", Provider: fake.Name, Homepage: "https://fake.metatube.invalid/actors/1"}).Error)
+
+	require.NoError(t, e.DeleteMovieInfo("fake", "FAKE-001"))
+	require.NoError(t, e.DeleteActorInfo("fake", "1"))
+
+	// within the retention.
+	n, err := e.PurgeDeleted(time.Hour)
+	require.NoError(t, err)
+	assert.Zero(t, n)
+	assert.EqualValues(t, 2, countRows(t, e, &model.MovieInfo{}, true))
+
+	// out of the retention.
+	n, err = e.PurgeDeleted(-time.Minute)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, n)
+	assert.EqualValues(t, 1, countRows(t, e, &model.MovieInfo{}, true))
+	assert.EqualValues(t, 0, countRows(t, e, &model.ActorInfo{}, true))
+
+	var info model.MovieInfo
+	require.NoError(t, e.db.First(&info).Error)
+	assert.Equal(t, "FAKE-002", info.ID)
+}
+
+func TestEngine_Blocklist(t *testing.T) {
+	e := newRetentionEngine(t)
+
+	for _, id := range []string{"FAKE-001", "FAKE-002", "FAKE-003"} {
+		_, err := e.GetMovieInfoByProviderID("fake", id, true)
+		require.NoError(t, err)
+	}
+	require.NoError(t, e.db.Create(&model.ActorInfo{ID: "1", Name: "Fake Actor 1", Provider: fake.Name, Homepage: "https://fake.metatube.invalid/actors/1"}).Error)
+
+	assert.Equal(t, ErrInvalidBlocklistEntry, e.AddBlocklistEntry(&model.BlocklistEntry{}))
+	assert.Equal(t, mt.ErrProviderNotFound, e.AddBlocklistEntry(&model.BlocklistEntry{Provider: "unknown", Number: "FAKE-001"}))
+
+	// blocked movies of the provider are deleted permanently.
+	require.NoError(t, e.AddBlocklistEntry(&model.BlocklistEntry{Provider: "fake", Number: "fake-001", Reason: "test"}))
+	assert.EqualValues(t, 2, countRows(t, e, &model.MovieInfo{}, true))
+	assert.EqualValues(t, 1, countRows(t, e, &model.ActorInfo{}, true))
+	// blocked numbers of all providers.
+	require.NoError(t, e.AddBlocklistEntry(&model.BlocklistEntry{Number: "FAKE-002"}))
+	assert.EqualValues(t, 1, countRows(t, e, &model.MovieInfo{}, true))
+
+	entries, err := e.GetBlocklist()
+	require.NoError(t, err)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, "", entries[0].Provider)
+		assert.Equal(t, "FAKE-002", entries[0].Number)
+		assert.Equal(t, fake.Name, entries[1].Provider) // canonical name.
+		assert.Equal(t, "fake-001", entries[1].Number)
+	}
+
+	// blocked movies are still scraped, but never stored.
+	for _, id := range []string{"FAKE-001", "FAKE-002"} {
+		info, err := e.GetMovieInfoByProviderID("fake", id, true)
+		require.NoError(t, err)
+		assert.Equal(t, id, info.ID)
+	}
+	assert.EqualValues(t, 1, countRows(t, e, &model.MovieInfo{}, true))
+	assert.True(t, e.isBlocked(fake.Name, "FAKE-001"))
+	assert.True(t, e.isBlocked("Other", "FAKE-002"))
+	assert.False(t, e.isBlocked("Other", "FAKE-001"))
+	assert.False(t, e.isBlocked(fake.Name))
+
+	// blocked providers include actors.
+	require.NoError(t, e.AddBlocklistEntry(&model.BlocklistEntry{Provider: "FAKE"}))
+	assert.EqualValues(t, 0, countRows(t, e, &model.MovieInfo{}, true))
+	assert.EqualValues(t, 0, countRows(t, e, &model.ActorInfo{}, true))
+	assert.True(t, e.isBlocked(fake.Name))
+
+	// unblocked.
+	require.NoError(t, e.RemoveBlocklistEntry("FAKE", ""))
+	require.NoError(t, e.RemoveBlocklistEntry("fake", "fake-001"))
+	_, err = e.GetMovieInfoByProviderID("fake", "FAKE-001", true)
+	require.NoError(t, err)
+	assert.EqualValues(t, 1, countRows(t, e, &model.MovieInfo{}, false))
+	assert.False(t, e.isBlocked(fake.Name))
+}
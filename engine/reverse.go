@@ -0,0 +1,133 @@
+package engine
+
+import (
+	"image"
+	"math/bits"
+	"sort"
+	"sync"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/metatube-community/metatube-sdk-go/imageutil"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// Max perception hash distance of reverse image search candidates.
+const maxReverseSearchDistance = 12
+
+// ImageSearchMatch is a movie candidate of reverse image search.
+type ImageSearchMatch struct {
+	*model.MovieSearchResult
+	// URL of the most similar image of the movie.
+	ImageURL string `json:"image_url"`
+	// Distance of perception hashes, the smaller the more similar.
+	Distance int `json:"distance"`
+	// Similarity in range [0, 1].
+	Similarity float64 `json:"similarity"`
+}
+
+// IndexMovieImages computes perception hashes of the cover and preview
+// images of the movie, and saves them to DB for reverse image search.
+func (e *Engine) IndexMovieImages(name, id string) (int, error) {
+	info, err := e.GetMovieInfoByProviderID(name, id, true)
+	if err != nil {
+		return 0, err
+	}
+	provider := e.MustGetMovieProviderByName(info.Provider)
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		hashes []*model.MovieImageHash
+	)
+	for _, url := range append([]string{info.CoverURL}, info.PreviewImages...) {
+		if url == "" {
+			continue
+		}
+		wg.Add(1)
+		go func(url string) {
+			defer wg.Done()
+			img, err := e.getImageByURL(provider, url)
+			if err != nil {
+				return // ignore error
+			}
+			hash := imageutil.PerceptionHash(img)
+			mu.Lock()
+			defer mu.Unlock()
+			hashes = append(hashes, &model.MovieImageHash{
+				Provider:    info.Provider,
+				ID:          info.ID,
+				URL:         url,
+				Hash:        int64(hash),
+				HashBuckets: model.NewHashBuckets(hash),
+			})
+		}(url)
+	}
+	wg.Wait()
+
+	if len(hashes) == 0 {
+		return 0, mt.ErrImageNotFound
+	}
+	return len(hashes), e.db.Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(hashes).Error
+}
+
+// ReverseSearchMovieImage searches movies of which indexed images look
+// similar to the given image, e.g., a frame screenshot of a movie file.
+// Candidates are ranked by similarity, and at most limit are returned.
+func (e *Engine) ReverseSearchMovieImage(img image.Image, limit int) ([]*ImageSearchMatch, error) {
+	if limit <= 0 {
+		limit = defaultFingerprintHits
+	}
+	hash := imageutil.PerceptionHash(img)
+
+	var hashes []*model.MovieImageHash
+	if err := e.db.
+		Where(nearHashBuckets(e.db, hash, maxReverseSearchDistance)).
+		Order("provider, id, url").
+		Find(&hashes).Error; err != nil {
+		return nil, err
+	}
+
+	matches := make(map[string]*ImageSearchMatch)
+	for _, h := range hashes {
+		distance := bits.OnesCount64(hash ^ uint64(h.Hash))
+		if distance > maxReverseSearchDistance {
+			continue
+		}
+		key := h.Provider + ":" + h.ID
+		if m, ok := matches[key]; ok && m.Distance <= distance {
+			continue
+		}
+		matches[key] = &ImageSearchMatch{
+			MovieSearchResult: &model.MovieSearchResult{ID: h.ID, Provider: h.Provider},
+			ImageURL:          h.URL,
+			Distance:          distance,
+			Similarity:        1 - float64(distance)/64,
+		}
+	}
+
+	results := make([]*ImageSearchMatch, 0, len(matches))
+	for _, m := range matches {
+		results = append(results, m)
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].Distance != results[j].Distance {
+			return results[i].Distance < results[j].Distance
+		}
+		return results[i].Provider+results[i].ID < results[j].Provider+results[j].ID
+	})
+	if len(results) > limit {
+		results = results[:limit]
+	}
+	for _, m := range results {
+		if provider, err := e.GetMovieProviderByName(m.Provider); err == nil {
+			if info, err := e.getMovieInfoFromDB(provider, m.ID); err == nil {
+				m.MovieSearchResult = info.ToSearchResult()
+			}
+		}
+	}
+	return results, nil
+}
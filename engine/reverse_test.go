@@ -0,0 +1,92 @@
+package engine
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+// coverFake is a gallery fake of movies, of which the cover is the image.
+type coverFake struct {
+	*galleryFake
+	cover string
+}
+
+func (f *coverFake) GetMovieInfoByID(id string) (*model.MovieInfo, error) {
+	info, err := f.Fake.GetMovieInfoByID(id)
+	if err != nil {
+		return nil, err
+	}
+	info.Provider = f.name
+	info.CoverURL = f.cover
+	info.ThumbURL = ""
+	info.PreviewImages = []string{f.cover + "&preview=1", "https://fake.metatube.invalid/missing.png"}
+	return info, nil
+}
+
+func TestEngine_ReverseSearchMovieImage(t *testing.T) {
+	const base = "https://fake.metatube.invalid/images/"
+	e := newBenchEngine(t, 0)
+	circles := &coverFake{galleryFake: &galleryFake{Fake: fake.New(), name: "Circles"}, cover: base + "circle.jpg?w=200"}
+	bars := &coverFake{galleryFake: &galleryFake{Fake: fake.New(), name: "Bars"}, cover: base + "bar.jpg?w=200"}
+	e.movieProviders = map[string]mt.MovieProvider{"CIRCLES": circles, "BARS": bars}
+
+	for _, unit := range []struct {
+		provider, id string
+		n            int
+	}{
+		{"Circles", "FAKE-001", 2},
+		{"Circles", "FAKE-002", 2},
+		{"Bars", "FAKE-003", 2},
+	} {
+		n, err := e.IndexMovieImages(unit.provider, unit.id)
+		require.NoError(t, err)
+		assert.Equal(t, unit.n, n)
+	}
+	// re-indexing updates existing hashes.
+	_, err := e.IndexMovieImages("Circles", "FAKE-001")
+	require.NoError(t, err)
+	var count int64
+	require.NoError(t, e.db.Model(&model.MovieImageHash{}).Count(&count).Error)
+	assert.EqualValues(t, 6, count)
+
+	frame := func(url string) image.Image {
+		img, err := e.getImageByURL(circles, url)
+		require.NoError(t, err)
+		return img
+	}
+
+	// a larger frame of the circle matches both circle movies.
+	results, err := e.ReverseSearchMovieImage(frame(base+"circle.jpg?w=400"), 0)
+	require.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		for i, id := range []string{"FAKE-001", "FAKE-002"} {
+			assert.Equal(t, "Circles", results[i].Provider)
+			assert.Equal(t, id, results[i].ID)
+			assert.Equal(t, "Fake Movie 00"+id[len(id)-1:], results[i].Title)
+			assert.LessOrEqual(t, results[i].Distance, maxReverseSearchDistance)
+			assert.Equal(t, 1-float64(results[i].Distance)/64, results[i].Similarity)
+		}
+	}
+
+	results, err = e.ReverseSearchMovieImage(frame(base+"circle.jpg?w=400"), 1)
+	require.NoError(t, err)
+	assert.Len(t, results, 1)
+
+	results, err = e.ReverseSearchMovieImage(frame(base+"bar.jpg?w=200"), 0)
+	require.NoError(t, err)
+	if assert.Len(t, results, 1) {
+		assert.Equal(t, "Bars", results[0].Provider)
+		assert.Equal(t, "FAKE-003", results[0].ID)
+		assert.Zero(t, results[0].Distance)
+	}
+
+	_, err = e.IndexMovieImages("Circles", "FAKE-0000")
+	assert.Error(t, err)
+}
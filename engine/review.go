@@ -61,13 +61,14 @@ func (e *Engine) getMovieReviewsByProviderID(provider mt.MovieProvider, id strin
 		return nil, mt.ErrInvalidID
 	}
 
-	reviewer, ok := provider.(mt.MovieReviewer)
-	if !ok {
+	if _, ok := provider.(mt.MovieReviewer); !ok {
 		return nil, fmt.Errorf("reviews not supported by %s", provider.Name())
 	}
 
 	return e.getMovieReviewsWithCallback(provider, id, lazy, func() ([]*model.MovieReviewDetail, error) {
-		return reviewer.GetMovieReviewsByID(id)
+		instance, release := e.acquireMovieProvider(provider)
+		defer release()
+		return instance.(mt.MovieReviewer).GetMovieReviewsByID(id)
 	})
 }
 
@@ -88,13 +89,14 @@ func (e *Engine) getMovieReviewsByProviderURL(provider mt.MovieProvider, rawURL
 		return nil, mt.ErrInvalidURL
 	}
 
-	reviewer, ok := provider.(mt.MovieReviewer)
-	if !ok {
+	if _, ok := provider.(mt.MovieReviewer); !ok {
 		return nil, fmt.Errorf("reviews not supported by %s", provider.Name())
 	}
 
 	return e.getMovieReviewsWithCallback(provider, id, lazy, func() ([]*model.MovieReviewDetail, error) {
-		return reviewer.GetMovieReviewsByURL(rawURL)
+		instance, release := e.acquireMovieProvider(provider)
+		defer release()
+		return instance.(mt.MovieReviewer).GetMovieReviewsByURL(rawURL)
 	})
 }
 
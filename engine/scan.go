@@ -0,0 +1,77 @@
+package engine
+
+import (
+	goerr "errors"
+	"os"
+	"path/filepath"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/metatube-community/metatube-sdk-go/media"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// ScanFile returns the scan state of the library file, and whether it's
+// new or changed since last scanned. Files of the same size and mtime are
+// unchanged, otherwise they are compared by OSHash, so touched files keep
+// their matches. The state is not saved until SaveScanState.
+func (e *Engine) ScanFile(name string) (state *model.ScanState, changed bool, err error) {
+	if name, err = filepath.Abs(name); err != nil {
+		return
+	}
+	fi, err := os.Stat(name)
+	if err != nil {
+		return
+	}
+	state = &model.ScanState{}
+	if e.db.Where("path = ?", name).First(state).Error == nil &&
+		state.Size == fi.Size() && state.ModTime.Equal(fi.ModTime()) {
+		return state, false, nil
+	}
+	hash, _ := media.OSHash(name) // ignore error, small files have no hash.
+	if state.Path != "" && hash != "" && hash == state.OSHash && state.Size == fi.Size() {
+		state.ModTime = fi.ModTime()
+		return state, false, nil
+	}
+	return &model.ScanState{
+		Path:    name,
+		Size:    fi.Size(),
+		ModTime: fi.ModTime(),
+		OSHash:  hash,
+	}, true, nil
+}
+
+// SaveScanState saves the scan state, e.g., with the movie matched.
+func (e *Engine) SaveScanState(state *model.ScanState) error {
+	return e.db.Clauses(clause.OnConflict{
+		UpdateAll: true,
+	}).Create(state).Error
+}
+
+// MoveScanState re-keys the scan state of the file moved from src to dst,
+// so that the moved file keeps its match and is not scanned as new.
+func (e *Engine) MoveScanState(src, dst string) (err error) {
+	if src, err = filepath.Abs(src); err != nil {
+		return
+	}
+	if dst, err = filepath.Abs(dst); err != nil {
+		return
+	}
+	return e.db.Transaction(func(tx *gorm.DB) error {
+		state := &model.ScanState{}
+		if err := tx.Where("path = ?", src).First(state).Error; err != nil {
+			if goerr.Is(err, gorm.ErrRecordNotFound) {
+				return nil // never scanned.
+			}
+			return err
+		}
+		if err := tx.Delete(state).Error; err != nil {
+			return err
+		}
+		state.Path = dst
+		return tx.Clauses(clause.OnConflict{
+			UpdateAll: true,
+		}).Create(state).Error
+	})
+}
@@ -0,0 +1,42 @@
+package engine
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEngine_MoveScanState(t *testing.T) {
+	e := newBenchEngine(t, 0)
+
+	var (
+		root = t.TempDir()
+		src  = filepath.Join(root, "abp-030.mp4")
+		dst  = filepath.Join(root, "ABP-030", "ABP-030.mp4")
+	)
+	require.NoError(t, os.WriteFile(src, []byte("video"), 0o644))
+
+	state, changed, err := e.ScanFile(src)
+	require.NoError(t, err)
+	assert.True(t, changed)
+	state.Number, state.Provider, state.ID = "ABP-030", "Fake", "ABP-030"
+	require.NoError(t, e.SaveScanState(state))
+
+	require.NoError(t, os.MkdirAll(filepath.Dir(dst), 0o755))
+	require.NoError(t, os.Rename(src, dst))
+	require.NoError(t, e.MoveScanState(src, dst))
+
+	// moved files keep their matches.
+	state, changed, err = e.ScanFile(dst)
+	require.NoError(t, err)
+	assert.False(t, changed)
+	assert.True(t, state.Matched())
+	assert.Equal(t, "ABP-030", state.ID)
+
+	// states of files never scanned are not made up.
+	assert.NoError(t, e.MoveScanState(filepath.Join(root, "unknown.mp4"), src))
+	assert.EqualValues(t, 1, countRows(t, e, state, false))
+}
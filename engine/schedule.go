@@ -0,0 +1,35 @@
+package engine
+
+import (
+	"time"
+
+	"gorm.io/gorm/clause"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// ClaimScheduledTask claims the run of the periodic task, and reports
+// false if any instance sharing the DB has run it within the interval.
+// A tenth of the interval is tolerated for clock skews of instances.
+func (e *Engine) ClaimScheduledTask(name string, interval time.Duration) (bool, error) {
+	if err := e.db.Clauses(clause.OnConflict{
+		DoNothing: true,
+	}).Create(&model.ScheduledTask{Name: name}).Error; err != nil {
+		return false, err
+	}
+	now := time.Now().UTC()
+	tx := e.db.Model(&model.ScheduledTask{}).
+		Where("name = ? AND run_at <= ?", name, now.Add(-interval+interval/10)).
+		Update("run_at", now)
+	return tx.RowsAffected == 1, tx.Error
+}
+
+// RunScheduledTask runs the periodic task if claimed, see
+// ClaimScheduledTask.
+func (e *Engine) RunScheduledTask(name string, interval time.Duration, task func() error) error {
+	claimed, err := e.ClaimScheduledTask(name, interval)
+	if err != nil || !claimed {
+		return err
+	}
+	return task()
+}
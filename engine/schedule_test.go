@@ -0,0 +1,69 @@
+package engine
+
+import (
+	goerr "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func TestEngine_ClaimScheduledTask(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	// another instance sharing the DB.
+	other := New(e.db, time.Minute)
+
+	setRunAt := func(runAt time.Time) {
+		require.NoError(t, e.db.Model(&model.ScheduledTask{}).
+			Where("name = ?", "task").
+			Update("run_at", runAt.UTC()).Error)
+	}
+
+	for _, unit := range []struct {
+		name    string
+		before  func()
+		claimed bool
+	}{
+		{"first run", nil, true},
+		{"run already", nil, false},
+		{"run by others", func() { e = other }, false},
+		{"interval passed", func() { setRunAt(time.Now().Add(-time.Hour - time.Second)) }, true},
+		{"clock skew tolerated", func() { setRunAt(time.Now().Add(-time.Hour + 5*time.Minute)) }, true},
+		{"beyond clock skew", func() { setRunAt(time.Now().Add(-time.Hour + 7*time.Minute)) }, false},
+	} {
+		t.Run(unit.name, func(t *testing.T) {
+			if unit.before != nil {
+				unit.before()
+			}
+			claimed, err := e.ClaimScheduledTask("task", time.Hour)
+			require.NoError(t, err)
+			assert.Equal(t, unit.claimed, claimed)
+		})
+	}
+
+	// tasks are claimed independently.
+	claimed, err := e.ClaimScheduledTask("another", time.Hour)
+	require.NoError(t, err)
+	assert.True(t, claimed)
+}
+
+func TestEngine_RunScheduledTask(t *testing.T) {
+	e := newBenchEngine(t, 0)
+
+	var runs int
+	task := func() error {
+		runs++
+		return nil
+	}
+	require.NoError(t, e.RunScheduledTask("task", time.Hour, task))
+	require.NoError(t, e.RunScheduledTask("task", time.Hour, task))
+	assert.Equal(t, 1, runs)
+
+	// errors of tasks are returned, and the runs are still claimed.
+	errTask := goerr.New("task failed")
+	assert.Equal(t, errTask, e.RunScheduledTask("failing", time.Hour, func() error { return errTask }))
+	assert.NoError(t, e.RunScheduledTask("failing", time.Hour, func() error { return errTask }))
+}
@@ -0,0 +1,169 @@
+package engine
+
+import (
+	"context"
+	goerr "errors"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/notify"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+var (
+	ErrInvalidSeries  = errors.New(http.StatusBadRequest, "invalid series")
+	ErrSeriesNotFound = errors.New(http.StatusNotFound, "series not found")
+)
+
+// GetTrackedSeries returns all tracked series.
+func (e *Engine) GetTrackedSeries() (series []*model.Series, err error) {
+	err = e.db.Order("provider, name").Find(&series).Error
+	return
+}
+
+// GetSeries returns the tracked series.
+func (e *Engine) GetSeries(name, series string) (*model.Series, error) {
+	provider, err := e.GetMovieProviderByName(name)
+	if err != nil {
+		return nil, err
+	}
+	s := &model.Series{}
+	if err = e.db.
+		Where("provider = ?", provider.Name()).
+		Where("name = ? COLLATE NOCASE", strings.TrimSpace(series)).
+		First(s).Error; err != nil {
+		return nil, ErrSeriesNotFound
+	}
+	return s, nil
+}
+
+// TrackSeries starts tracking the series of the movie provider, and
+// collects its known entries at once.
+func (e *Engine) TrackSeries(series *model.Series) error {
+	if series.Name = strings.TrimSpace(series.Name); !series.Valid() {
+		return ErrInvalidSeries
+	}
+	provider, err := e.GetMovieProviderByName(series.Provider)
+	if err != nil {
+		return err
+	}
+	series.Provider = provider.Name()
+	if s, err := e.GetSeries(series.Provider, series.Name); err == nil {
+		*series = *s // already tracked.
+		return nil
+	}
+	_, err = e.RefreshSeries(series)
+	return err
+}
+
+// UntrackSeries stops tracking the series.
+func (e *Engine) UntrackSeries(name, series string) error {
+	s, err := e.GetSeries(name, series)
+	if err != nil {
+		return err
+	}
+	return e.db.Delete(s).Error
+}
+
+// RefreshSeries finds new entries of the series and saves it, returns the
+// numbers added. Entries are collected from the stored infos, and from
+// the provider search results by the series name, which are only kept if
+// their infos belong to the series. Providers that don't support keyword
+// searching are only watched through the stored infos.
+func (e *Engine) RefreshSeries(series *model.Series) (added []string, err error) {
+	provider, err := e.GetMovieProviderByName(series.Provider)
+	if err != nil {
+		return nil, err
+	}
+	add := func(number string) {
+		if number != "" && !containsFold(series.Entries, number) {
+			series.Entries = append(series.Entries, number)
+			added = append(added, number)
+		}
+	}
+	var numbers []string
+	if err = e.db.Model(&model.MovieInfo{}).
+		Where("provider = ?", provider.Name()).
+		Where("series = ? COLLATE NOCASE", series.Name).
+		Pluck("number", &numbers).Error; err != nil {
+		return nil, err
+	}
+	for _, number := range numbers {
+		add(number)
+	}
+	// listings are paginated by providers themselves, e.g., by
+	// scraper.Crawl of their searches, engines only see the results.
+	if results, err := e.searchMovie(context.Background(), series.Name, provider, false); err == nil {
+		for _, result := range results {
+			if containsFold(series.Entries, result.Number) {
+				continue
+			}
+			info, err := e.systemAuditor().GetMovieInfoByProviderID(provider.Name(), result.ID, true)
+			if err == nil && strings.EqualFold(strings.TrimSpace(info.Series), series.Name) {
+				add(info.Number)
+			}
+		}
+	} else if !goerr.Is(err, mt.ErrInvalidKeyword) {
+		e.logger.Warnw("series search failed", "provider", provider.Name(),
+			"series", e.redactor.String(series.Name), "error", e.redactor.Error(err, series.Name))
+	}
+	slices.Sort(series.Entries)
+	series.CheckedAt = time.Now().UTC()
+	return added, e.db.Save(series).Error
+}
+
+// RefreshTrackedSeries refreshes all tracked series, and sends events of
+// the new entries found.
+func (e *Engine) RefreshTrackedSeries() error {
+	series, err := e.GetTrackedSeries()
+	if err != nil {
+		return err
+	}
+	for _, s := range series {
+		added, err := e.RefreshSeries(s)
+		if err != nil {
+			e.logger.Warnw("series refresh failed", "provider", s.Provider,
+				"series", e.redactor.String(s.Name), "error", e.redactor.Error(err, s.Name))
+			continue
+		}
+		if len(added) > 0 {
+			e.Notify(&notify.Event{
+				Kind:    notify.SeriesUpdated,
+				Title:   s.Name,
+				Message: strings.Join(added, ", "),
+			})
+		}
+	}
+	return nil
+}
+
+// GetSeriesStatus returns the owned and missing entries of the series,
+// entries are owned if any library files are matched with their numbers.
+func (e *Engine) GetSeriesStatus(name, series string) (*model.SeriesStatus, error) {
+	s, err := e.GetSeries(name, series)
+	if err != nil {
+		return nil, err
+	}
+	var owned []string
+	if len(s.Entries) > 0 {
+		if err = e.db.Model(&model.ScanState{}).
+			Where("provider <> '' AND id <> ''").
+			Distinct().
+			Pluck("number", &owned).Error; err != nil {
+			return nil, err
+		}
+	}
+	status := &model.SeriesStatus{Series: s, Owned: []string{}, Missing: []string{}}
+	for _, number := range s.Entries {
+		if containsFold(owned, number) {
+			status.Owned = append(status.Owned, number)
+		} else {
+			status.Missing = append(status.Missing, number)
+		}
+	}
+	return status, nil
+}
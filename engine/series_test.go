@@ -0,0 +1,114 @@
+package engine
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/notify"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+// seriesFake searches movies by series names, results are of the series
+// or not.
+type seriesFake struct {
+	*fake.Fake
+	results map[string][]string
+}
+
+func (f *seriesFake) SearchMovie(keyword string) (results []*model.MovieSearchResult, err error) {
+	for _, id := range f.results[strings.ToUpper(keyword)] {
+		info, err := f.GetMovieInfoByID(id)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, info.ToSearchResult())
+	}
+	if len(results) == 0 {
+		return nil, mt.ErrInfoNotFound
+	}
+	return
+}
+
+func TestEngine_TrackSeries(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	n := &recordNotifier{}
+	f := &seriesFake{Fake: fake.New(), results: map[string][]string{
+		// FAKE-002 is of Fake Series 3.
+		"FAKE SERIES 2": {"FAKE-001", "FAKE-002", "FAKE-011"},
+	}}
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": f}
+	e.notifier = n
+
+	assert.Equal(t, ErrInvalidSeries, e.TrackSeries(&model.Series{Provider: "fake", Name: " "}))
+	assert.Equal(t, mt.ErrProviderNotFound, e.TrackSeries(&model.Series{Provider: "unknown", Name: "Fake Series 2"}))
+
+	series := &model.Series{Provider: "fake", Name: " Fake Series 2 "}
+	require.NoError(t, e.TrackSeries(series))
+	assert.Equal(t, "Fake", series.Provider)
+	assert.Equal(t, "Fake Series 2", series.Name)
+	assert.Equal(t, []string{"FAKE-001", "FAKE-011"}, []string(series.Entries))
+	assert.False(t, series.CheckedAt.IsZero())
+
+	// tracked already, names are case-insensitive.
+	again := &model.Series{Provider: "fake", Name: "fake series 2"}
+	require.NoError(t, e.TrackSeries(again))
+	assert.Equal(t, series.Entries, again.Entries)
+	all, err := e.GetTrackedSeries()
+	require.NoError(t, err)
+	assert.Len(t, all, 1)
+
+	// entries are collected from stored infos too.
+	_, err = e.GetMovieInfoByProviderID("fake", "FAKE-021", true)
+	require.NoError(t, err)
+	require.NoError(t, e.RefreshTrackedSeries())
+	e.WaitNotifications()
+	series, err = e.GetSeries("fake", "Fake Series 2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"FAKE-001", "FAKE-011", "FAKE-021"}, []string(series.Entries))
+
+	var updates []*notify.Event
+	for _, event := range n.events {
+		if event.Kind == notify.SeriesUpdated {
+			updates = append(updates, event)
+		}
+	}
+	if assert.Len(t, updates, 1) {
+		assert.Equal(t, "Fake Series 2", updates[0].Title)
+		assert.Equal(t, "FAKE-021", updates[0].Message)
+	}
+
+	require.NoError(t, e.UntrackSeries("fake", "FAKE SERIES 2"))
+	_, err = e.GetSeries("fake", "Fake Series 2")
+	assert.Equal(t, ErrSeriesNotFound, err)
+	assert.Equal(t, ErrSeriesNotFound, e.UntrackSeries("fake", "Fake Series 2"))
+}
+
+func TestEngine_GetSeriesStatus(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": &seriesFake{Fake: fake.New()}}
+
+	for _, id := range []string{"FAKE-001", "FAKE-011"} {
+		_, err := e.GetMovieInfoByProviderID("fake", id, true)
+		require.NoError(t, err)
+	}
+	require.NoError(t, e.TrackSeries(&model.Series{Provider: "fake", Name: "Fake Series 2"}))
+
+	require.NoError(t, e.SaveScanState(&model.ScanState{
+		Path: "/library/FAKE-011.mp4", Number: "FAKE-011", Provider: "Fake", ID: "FAKE-011",
+	}))
+	// files not matched own nothing.
+	require.NoError(t, e.SaveScanState(&model.ScanState{Path: "/library/FAKE-001.mp4", Number: "FAKE-001"}))
+
+	status, err := e.GetSeriesStatus("fake", "Fake Series 2")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"FAKE-011"}, status.Owned)
+	assert.Equal(t, []string{"FAKE-001"}, status.Missing)
+
+	_, err = e.GetSeriesStatus("fake", "Fake Series 3")
+	assert.Equal(t, ErrSeriesNotFound, err)
+}
@@ -0,0 +1,51 @@
+package engine
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// isMissingImage reports whether the image of the URL is a placeholder of
+// missing contents of the provider, see mt.SoftNotFound.
+func (e *Engine) isMissingImage(provider mt.Provider, url string) bool {
+	d, ok := e.softNotFound[strings.ToUpper(provider.Name())]
+	return ok && d != nil && e.matchImageHash(provider, url, d.ImageHashes, false)
+}
+
+// matchImageHash reports whether the SHA-256 hash of the image of the URL
+// is any of the hashes in hex. Images are only fetched if there are hashes,
+// and images which fail to be fetched match none. If fresh, the image is
+// fetched again rather than read from the image cache.
+func (e *Engine) matchImageHash(provider mt.Provider, url string, hashes []string, fresh bool) bool {
+	if len(hashes) == 0 || url == "" {
+		return false
+	}
+	getImageData := e.getImageData
+	if fresh {
+		getImageData = e.refreshImageData
+	}
+	data, err := getImageData(provider, url)
+	if err != nil {
+		return false
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+	for _, h := range hashes {
+		if strings.EqualFold(strings.TrimSpace(h), hash) {
+			return true
+		}
+	}
+	return false
+}
+
+// firstImage returns the first of the images, i.e., the primary one of
+// actors, or empty if none.
+func firstImage(images []string) string {
+	if len(images) == 0 {
+		return ""
+	}
+	return images[0]
+}
@@ -0,0 +1,318 @@
+package engine
+
+import (
+	goerr "errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/common/studio"
+	"github.com/metatube-community/metatube-sdk-go/database"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+const defaultStatsLimit = 20
+
+// Stats is the statistics of stored metadata, and of info queries since
+// the engine started.
+type Stats struct {
+	Movies int64 `json:"movies"`
+	Actors int64 `json:"actors"`
+	// Providers, Makers, TopActors and Years are movie counts, the
+	// makers and actors are limited to the top ones. Makers of the same
+	// studios are counted together by the names in preferred languages.
+	Providers []*StatsCount `json:"providers"`
+	Makers    []*StatsCount `json:"makers"`
+	TopActors []*StatsCount `json:"top_actors"`
+	Years     []*StatsCount `json:"years"`
+	// Completeness is the average ratio of filled fields of movies.
+	Completeness float64         `json:"completeness"`
+	Cache        CacheStats      `json:"cache"`
+	Failures     []*FailureStats `json:"failures"`
+}
+
+// StatsCount is the movie count of a name, e.g., a maker or a year.
+type StatsCount struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+// CacheStats is the statistics of lazy info queries, which are hits if
+// served from the DB.
+type CacheStats struct {
+	Hits     int64   `json:"hits"`
+	Misses   int64   `json:"misses"`
+	HitRatio float64 `json:"hit_ratio"`
+}
+
+// FailureStats is the statistics of failed info fetches of a provider,
+// infos not found are not failures.
+type FailureStats struct {
+	Provider  string    `json:"provider"`
+	Count     int64     `json:"count"`
+	LastError string    `json:"last_error"`
+	LastTime  time.Time `json:"last_time"`
+}
+
+func (e *Engine) recordCacheHit() {
+	e.statsMu.Lock()
+	e.cacheStats.Hits++
+	e.statsMu.Unlock()
+}
+
+// recordFetch records the info fetched from the provider.
+func (e *Engine) recordFetch(lazy bool, name string, err error) {
+	e.statsMu.Lock()
+	defer e.statsMu.Unlock()
+	if lazy {
+		e.cacheStats.Misses++
+	}
+	if err == nil || goerr.Is(err, mt.ErrInfoNotFound) {
+		return
+	}
+	stats, ok := e.failureStats[name]
+	if !ok {
+		stats = &FailureStats{Provider: name}
+		e.failureStats[name] = stats
+	}
+	stats.Count++
+	stats.LastError = err.Error()
+	stats.LastTime = time.Now()
+}
+
+// GetStats returns the statistics, makers and actors are limited to the
+// top ones, or a default number if limit is not positive.
+func (e *Engine) GetStats(limit int) (*Stats, error) {
+	if limit <= 0 {
+		limit = defaultStatsLimit
+	}
+	stats := &Stats{}
+	if err := e.db.Model(&model.ActorInfo{}).Count(&stats.Actors).Error; err != nil {
+		return nil, err
+	}
+	if err := e.db.Model(&model.MovieInfo{}).Count(&stats.Movies).Error; err != nil {
+		return nil, err
+	}
+	providers, err := e.countMoviesBy("provider", nil)
+	if err != nil {
+		return nil, err
+	}
+	// makers of the same studio are grouped in SQL by their raw names.
+	makers, err := e.countMoviesBy("maker", func(maker string) string {
+		if maker == "" {
+			return ""
+		}
+		return studio.Canonicalize(maker, e.languages...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	years, err := e.countMovieYears()
+	if err != nil {
+		return nil, err
+	}
+	actors, err := e.countMovieActors(limit)
+	if err != nil {
+		return nil, err
+	}
+	if stats.Movies > 0 {
+		if stats.Completeness, err = e.averageMovieCompleteness(stats.Movies); err != nil {
+			return nil, err
+		}
+	}
+	stats.Providers = sortStatsCounts(providers, 0)
+	stats.Makers = sortStatsCounts(makers, limit)
+	stats.TopActors = sortStatsCounts(actors, limit)
+	stats.Years = sortStatsCounts(years, 0)
+	sort.SliceStable(stats.Years, func(i, j int) bool {
+		return stats.Years[i].Name > stats.Years[j].Name
+	})
+
+	e.statsMu.Lock()
+	stats.Cache = e.cacheStats
+	for _, failure := range e.failureStats {
+		f := *failure
+		stats.Failures = append(stats.Failures, &f)
+	}
+	e.statsMu.Unlock()
+	if total := stats.Cache.Hits + stats.Cache.Misses; total > 0 {
+		stats.Cache.HitRatio = float64(stats.Cache.Hits) / float64(total)
+	}
+	sort.Slice(stats.Failures, func(i, j int) bool {
+		if stats.Failures[i].Count != stats.Failures[j].Count {
+			return stats.Failures[i].Count > stats.Failures[j].Count
+		}
+		return stats.Failures[i].Provider < stats.Failures[j].Provider
+	})
+	return stats, nil
+}
+
+// sortStatsCounts returns the counts in descending order, limited to n if
+// n is positive.
+func sortStatsCounts(counts map[string]int64, n int) []*StatsCount {
+	results := make([]*StatsCount, 0, len(counts))
+	for name, count := range counts {
+		results = append(results, &StatsCount{Name: name, Count: count})
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Count != results[j].Count {
+			return results[i].Count > results[j].Count
+		}
+		return results[i].Name < results[j].Name
+	})
+	if n > 0 && len(results) > n {
+		results = results[:n]
+	}
+	return results
+}
+
+// countMoviesBy returns movie counts grouped by the column, names are
+// mapped by fn if not nil, and counts of the empty names are dropped.
+func (e *Engine) countMoviesBy(column string, fn func(string) string) (map[string]int64, error) {
+	var rows []*StatsCount
+	if err := e.db.Model(&model.MovieInfo{}).
+		Select(column + " AS name, COUNT(*) AS count").
+		Group(column).
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int64, len(rows))
+	for _, row := range rows {
+		if fn != nil {
+			row.Name = fn(row.Name)
+		}
+		if row.Name != "" {
+			counts[row.Name] += row.Count
+		}
+	}
+	return counts, nil
+}
+
+// countMovieYears returns movie counts by release years, which are
+// grouped by dates in SQL, since years are extracted differently by
+// dialects.
+func (e *Engine) countMovieYears() (map[string]int64, error) {
+	var rows []struct {
+		ReleaseDate datatypes.Date
+		Count       int64
+	}
+	if err := e.db.Model(&model.MovieInfo{}).
+		Select("release_date, COUNT(*) AS count").
+		Group("release_date").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	years := make(map[string]int64)
+	for _, row := range rows {
+		if date := time.Time(row.ReleaseDate); !date.IsZero() {
+			years[strconv.Itoa(date.Year())] += row.Count
+		}
+	}
+	return years, nil
+}
+
+// countMovieActors returns movie counts of the top actors. Arrays are
+// only unnested by Postgres, otherwise they are grouped by the whole
+// lists in SQL, and counted by actors afterward.
+func (e *Engine) countMovieActors(limit int) (map[string]int64, error) {
+	actors := make(map[string]int64)
+	if e.db.Config.Dialector.Name() == database.Postgres {
+		var rows []*StatsCount
+		if err := e.db.Model(&model.MovieInfo{}).
+			Select("unnest(actors) AS name, COUNT(*) AS count").
+			Group("name").
+			Order("count DESC, name").
+			Limit(limit).
+			Scan(&rows).Error; err != nil {
+			return nil, err
+		}
+		for _, row := range rows {
+			actors[row.Name] = row.Count
+		}
+		return actors, nil
+	}
+	var rows []struct {
+		Actors pq.StringArray `gorm:"type:text[]"`
+		Count  int64
+	}
+	if err := e.db.Model(&model.MovieInfo{}).
+		Select("actors, COUNT(*) AS count").
+		Group("actors").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		for _, actor := range row.Actors {
+			actors[actor] += row.Count
+		}
+	}
+	return actors, nil
+}
+
+// averageMovieCompleteness returns the average ratio of the filled fields
+// of the movies like movieCompleteness, which are counted in SQL field by
+// field.
+func (e *Engine) averageMovieCompleteness(movies int64) (float64, error) {
+	placeholder := func(field string) string {
+		// placeholders are the last of sources in JSON.
+		return `COALESCE(source, '') NOT LIKE '%"placeholders":[%"` + field + `"%'`
+	}
+	filled := []string{
+		"COALESCE(title, '') <> ''",
+		"COALESCE(summary, '') <> ''",
+		"COALESCE(director, '') <> ''",
+		"actors IS NOT NULL AND actors <> '{}'",
+		"COALESCE(thumb_url, '') <> '' AND " + placeholder("thumb_url"),
+		"COALESCE(cover_url, '') <> '' AND " + placeholder("cover_url"),
+		"preview_images IS NOT NULL AND preview_images <> '{}'",
+		"COALESCE(maker, '') <> ''",
+		"genres IS NOT NULL AND genres <> '{}'",
+		"runtime > 0",
+		"release_date > ?",
+	}
+	var total int64
+	for _, cond := range filled {
+		var (
+			n    int64
+			args []any
+		)
+		if strings.HasSuffix(cond, "?") {
+			args = append(args, datatypes.Date(time.Time{}))
+		}
+		if err := e.db.Model(&model.MovieInfo{}).Where(cond, args...).Count(&n).Error; err != nil {
+			return 0, err
+		}
+		total += n
+	}
+	return float64(total) / float64(movies*int64(len(filled))), nil
+}
+
+// movieCompleteness returns the ratio of the filled fields of the info,
+// which are the ones media servers usually display.
+func movieCompleteness(info *model.MovieInfo) float64 {
+	filled := []bool{
+		info.Title != "",
+		info.Summary != "",
+		info.Director != "",
+		len(info.Actors) > 0,
+		movieImageURL(info, "thumb_url", info.ThumbURL) != "",
+		movieImageURL(info, "cover_url", info.CoverURL) != "",
+		len(info.PreviewImages) > 0,
+		info.Maker != "",
+		len(info.Genres) > 0,
+		info.Runtime > 0,
+		!time.Time(info.ReleaseDate).IsZero(),
+	}
+	var n int
+	for _, ok := range filled {
+		if ok {
+			n++
+		}
+	}
+	return float64(n) / float64(len(filled))
+}
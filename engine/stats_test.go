@@ -0,0 +1,66 @@
+package engine
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+func TestEngine_GetStats(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": fake.New()}
+
+	stats, err := e.GetStats(0)
+	require.NoError(t, err)
+	assert.Zero(t, stats.Movies)
+	assert.Empty(t, stats.Providers)
+	assert.Zero(t, stats.Completeness)
+
+	for i := 1; i <= 12; i++ {
+		_, err = e.GetMovieInfoByProviderID("fake", fmt.Sprintf("FAKE-%03d", i), true)
+		require.NoError(t, err)
+	}
+	// sparse infos, with placeholders and without dates or arrays.
+	require.NoError(t, e.db.Create(&model.MovieInfo{
+		ID: "SPARSE-001", Number: "SPARSE-001", Provider: "Sparse", Title: "Sparse",
+		CoverURL: "https://sparse.invalid/now-printing.jpg",
+		Source:   &model.Source{Provider: "Sparse", Placeholders: []string{"cover_url"}},
+		Actors:   []string{}, Genres: nil,
+	}).Error)
+
+	// statistics of SQL agree with the ones counted by rows.
+	var (
+		infos        []*model.MovieInfo
+		actors       = make(map[string]int64)
+		years        = make(map[string]int64)
+		completeness float64
+	)
+	require.NoError(t, e.db.Find(&infos).Error)
+	for _, info := range infos {
+		for _, actor := range info.Actors {
+			actors[actor]++
+		}
+		if date := time.Time(info.ReleaseDate); !date.IsZero() {
+			years[strconv.Itoa(date.Year())]++
+		}
+		completeness += movieCompleteness(info)
+	}
+
+	stats, err = e.GetStats(3)
+	require.NoError(t, err)
+	assert.EqualValues(t, 13, stats.Movies)
+	assert.Equal(t, []*StatsCount{{Name: "Fake", Count: 12}, {Name: "Sparse", Count: 1}}, stats.Providers)
+	assert.Equal(t, []*StatsCount{{Name: "Fake Studio", Count: 12}}, stats.Makers)
+	assert.Equal(t, sortStatsCounts(actors, 3), stats.TopActors)
+	assert.Equal(t, sortStatsCounts(years, 0), stats.Years)
+	assert.InDelta(t, completeness/float64(len(infos)), stats.Completeness, 1e-9)
+	assert.Less(t, stats.Completeness, 1.0)
+}
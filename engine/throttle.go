@@ -0,0 +1,64 @@
+package engine
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/notify"
+)
+
+// outageNotifyCooldown is the min interval of outage notifications of a
+// provider, so that a provider down won't flood the channel.
+const outageNotifyCooldown = 30 * time.Minute
+
+// ThrottleStats is the statistics of server throttling of a provider.
+type ThrottleStats struct {
+	// Count is the number of times the provider was throttled.
+	Count int64 `json:"count"`
+	// TotalWait is the total time paused by throttling.
+	TotalWait time.Duration `json:"total_wait"`
+	// LastWait is the time paused by the last throttling.
+	LastWait time.Duration `json:"last_wait"`
+	// LastTime is the time of the last throttling.
+	LastTime time.Time `json:"last_time"`
+}
+
+func (e *Engine) recordThrottle(name string, wait time.Duration) {
+	e.throttleMu.Lock()
+	stats, ok := e.throttleStats[name]
+	if !ok {
+		stats = &ThrottleStats{}
+		e.throttleStats[name] = stats
+	}
+	stats.Count++
+	stats.TotalWait += wait
+	stats.LastWait = wait
+	stats.LastTime = time.Now()
+	// outages are announced once per cooldown.
+	announce := time.Since(e.outageAt[name]) > outageNotifyCooldown
+	if announce {
+		e.outageAt[name] = stats.LastTime
+	}
+	e.throttleMu.Unlock()
+
+	e.logger.Warnw("provider throttled", "provider", name, "wait", wait)
+	if announce {
+		e.Notify(&notify.Event{
+			Kind:    notify.ProviderOutage,
+			Title:   name + " throttled",
+			Message: fmt.Sprintf("Requests to %s are paused for %s.", name, wait),
+		})
+	}
+}
+
+// GetThrottleStats returns throttle statistics of providers by name,
+// providers never throttled are not included.
+func (e *Engine) GetThrottleStats() map[string]ThrottleStats {
+	e.throttleMu.Lock()
+	defer e.throttleMu.Unlock()
+	results := make(map[string]ThrottleStats, len(e.throttleStats))
+	for name, stats := range e.throttleStats {
+		results[name] = *stats
+	}
+	return results
+}
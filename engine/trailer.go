@@ -0,0 +1,33 @@
+package engine
+
+import (
+	"context"
+	"io"
+
+	"github.com/metatube-community/metatube-sdk-go/media"
+)
+
+// GetMovieTrailer resolves the playable trailer of the movie, which is
+// probed through the proxy of the provider, see media.Resolver.
+func (e *Engine) GetMovieTrailer(name, id string) (*media.Trailer, error) {
+	provider, err := e.GetMovieProviderByName(name)
+	if err != nil {
+		return nil, err
+	}
+	info, err := e.getMovieInfoByProviderID(provider, id, true)
+	if err != nil {
+		return nil, err
+	}
+	return media.NewResolver(e.providerFetcher(provider), e.trailerRemuxer).Resolve(info)
+}
+
+// WriteMovieTrailer writes the trailer of the movie to w as an MP4 video,
+// HLS trailers are only written if remuxing is enabled, see
+// WithTrailerRemuxer.
+func (e *Engine) WriteMovieTrailer(ctx context.Context, name string, trailer *media.Trailer, w io.Writer) error {
+	provider, err := e.GetMovieProviderByName(name)
+	if err != nil {
+		return err
+	}
+	return media.NewResolver(e.providerFetcher(provider), e.trailerRemuxer).WriteMP4(ctx, trailer, w)
+}
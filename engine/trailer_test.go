@@ -0,0 +1,60 @@
+package engine
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/media"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+// trailerProvider is a provider with the preview video at url.
+type trailerProvider struct {
+	mt.MovieProvider
+	url string
+}
+
+func (p *trailerProvider) GetMovieInfoByID(id string) (*model.MovieInfo, error) {
+	info, err := p.MovieProvider.GetMovieInfoByID(id)
+	if err != nil {
+		return nil, err
+	}
+	info.PreviewVideoURL = p.url
+	return info, nil
+}
+
+func TestEngine_MovieTrailer(t *testing.T) {
+	video := []byte("\x00\x00\x00\x18ftypmp42")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "video/mp4")
+		_, _ = w.Write(video)
+	}))
+	defer srv.Close()
+
+	e := newBenchEngine(t, 0)
+	e.movieProviders = map[string]mt.MovieProvider{
+		"FAKE": &trailerProvider{MovieProvider: fake.New(), url: srv.URL + "/trailer.mp4"},
+	}
+
+	trailer, err := e.GetMovieTrailer("Fake", "FAKE-001")
+	require.NoError(t, err)
+	assert.Equal(t, media.MP4Trailer, trailer.Kind)
+	assert.Equal(t, srv.URL+"/trailer.mp4", trailer.URL)
+
+	var buf bytes.Buffer
+	require.NoError(t, e.WriteMovieTrailer(context.Background(), "Fake", trailer, &buf))
+	assert.Equal(t, video, buf.Bytes())
+
+	// movies without preview videos have no trailers.
+	e.movieProviders["FAKE"] = &trailerProvider{MovieProvider: fake.New()}
+	_, err = e.GetMovieTrailer("Fake", "FAKE-002")
+	assert.ErrorIs(t, err, media.ErrTrailerNotFound)
+}
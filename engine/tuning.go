@@ -0,0 +1,207 @@
+package engine
+
+import (
+	goerr "errors"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+const (
+	// minTuningSamples is the min number of samples in the window before
+	// the priority of a provider is tuned, so a few errors won't matter.
+	minTuningSamples = 10
+	// maxTuningSamples bounds the samples kept per provider.
+	maxTuningSamples = 1024
+	// tuningLatencyTarget is the average latency not penalized.
+	tuningLatencyTarget = 3 * time.Second
+	// minTuningFactor is the min ratio of the effective priority to the
+	// static one, so that a provider is never tuned out entirely.
+	minTuningFactor = 0.25
+)
+
+// EffectivePriority is the priority of a provider tuned by the metrics
+// measured over the sliding window, the static priority is the baseline
+// which is only lowered.
+type EffectivePriority struct {
+	Kind      string `json:"kind"`
+	Provider  string `json:"provider"`
+	Static    int    `json:"static"`
+	Effective int    `json:"effective"`
+	// Samples is the number of searches and fetches in the window, the
+	// priority is not tuned if less than minTuningSamples.
+	Samples     int           `json:"samples"`
+	SuccessRate float64       `json:"success_rate"`
+	AvgLatency  time.Duration `json:"avg_latency"`
+	// Completeness is the average ratio of filled fields of movie infos
+	// fetched, and 1 for actors.
+	Completeness float64 `json:"completeness"`
+}
+
+type tuningSample struct {
+	at      time.Time
+	ok      bool
+	latency time.Duration
+	// completeness of the info fetched, or negative if not applicable.
+	completeness float64
+}
+
+// priorityTuner keeps samples of providers over the sliding window.
+type priorityTuner struct {
+	window time.Duration
+
+	mu sync.Mutex
+	// samples by kind and provider name, in time order.
+	samples map[[2]string][]tuningSample
+}
+
+func newPriorityTuner(window time.Duration) *priorityTuner {
+	return &priorityTuner{
+		window:  window,
+		samples: make(map[[2]string][]tuningSample),
+	}
+}
+
+// prune drops the samples out of the window, the caller holds the lock.
+func (t *priorityTuner) prune(key [2]string, now time.Time) []tuningSample {
+	samples := t.samples[key]
+	i := sort.Search(len(samples), func(i int) bool {
+		return now.Sub(samples[i].at) < t.window
+	})
+	samples = samples[max(i, len(samples)-maxTuningSamples):]
+	t.samples[key] = samples
+	return samples
+}
+
+func (t *priorityTuner) record(kind, name string, err error, latency time.Duration, completeness float64) {
+	if t == nil {
+		return
+	}
+	key := [2]string{kind, name}
+	now := time.Now()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.samples[key] = append(t.prune(key, now), tuningSample{
+		at:           now,
+		ok:           !isProviderFailure(err),
+		latency:      latency,
+		completeness: completeness,
+	})
+}
+
+// priority returns the effective priority of the provider, which is the
+// static one if tuning is disabled or samples are insufficient.
+func (t *priorityTuner) priority(kind, name string, static int) int {
+	if t == nil {
+		return static
+	}
+	return t.stats(kind, name, static).Effective
+}
+
+func (t *priorityTuner) stats(kind, name string, static int) *EffectivePriority {
+	key := [2]string{kind, name}
+	t.mu.Lock()
+	samples := t.prune(key, time.Now())
+	p := &EffectivePriority{
+		Kind:         kind,
+		Provider:     name,
+		Static:       static,
+		Effective:    static,
+		Samples:      len(samples),
+		Completeness: 1,
+	}
+	var (
+		ok, filled int
+		latency    time.Duration
+		sum        float64
+	)
+	for _, s := range samples {
+		if s.ok {
+			ok++
+		}
+		latency += s.latency
+		if s.completeness >= 0 {
+			filled++
+			sum += s.completeness
+		}
+	}
+	t.mu.Unlock()
+
+	if p.Samples == 0 {
+		return p
+	}
+	p.SuccessRate = float64(ok) / float64(p.Samples)
+	p.AvgLatency = latency / time.Duration(p.Samples)
+	if filled > 0 {
+		p.Completeness = sum / float64(filled)
+	}
+	if p.Samples >= minTuningSamples {
+		p.Effective = int(math.Round(float64(static) * tuningFactor(p)))
+	}
+	return p
+}
+
+// tuningFactor scores the provider by its success rate, completeness and
+// latency, and maps the score to [minTuningFactor, 1].
+func tuningFactor(p *EffectivePriority) float64 {
+	score := p.SuccessRate * (0.5 + 0.5*p.Completeness)
+	if p.AvgLatency > tuningLatencyTarget {
+		score *= float64(tuningLatencyTarget) / float64(p.AvgLatency)
+	}
+	return minTuningFactor + (1-minTuningFactor)*score
+}
+
+// isProviderFailure reports whether the error is caused by the provider,
+// infos not found and invalid inputs are not.
+func isProviderFailure(err error) bool {
+	return err != nil &&
+		!goerr.Is(err, mt.ErrInfoNotFound) &&
+		!goerr.Is(err, mt.ErrInvalidID) &&
+		!goerr.Is(err, mt.ErrInvalidKeyword)
+}
+
+func (e *Engine) movieProviderPriority(provider mt.MovieProvider) int {
+	return e.tuner.priority(model.MovieKind, provider.Name(), provider.Priority())
+}
+
+func (e *Engine) actorProviderPriority(provider mt.ActorProvider) int {
+	return e.tuner.priority(model.ActorKind, provider.Name(), provider.Priority())
+}
+
+// GetEffectivePriorities returns the effective priorities of providers,
+// which are the static ones if tuning is disabled.
+func (e *Engine) GetEffectivePriorities() []*EffectivePriority {
+	results := make([]*EffectivePriority, 0, len(e.movieProviders)+len(e.actorProviders))
+	add := func(kind string, provider mt.Provider) {
+		if e.tuner == nil {
+			results = append(results, &EffectivePriority{
+				Kind:      kind,
+				Provider:  provider.Name(),
+				Static:    provider.Priority(),
+				Effective: provider.Priority(),
+			})
+			return
+		}
+		results = append(results, e.tuner.stats(kind, provider.Name(), provider.Priority()))
+	}
+	for _, provider := range e.movieProviders {
+		add(model.MovieKind, provider)
+	}
+	for _, provider := range e.actorProviders {
+		add(model.ActorKind, provider)
+	}
+	sort.Slice(results, func(i, j int) bool {
+		if results[i].Kind != results[j].Kind {
+			return results[i].Kind > results[j].Kind // movies first.
+		}
+		if results[i].Effective != results[j].Effective {
+			return results[i].Effective > results[j].Effective
+		}
+		return results[i].Provider < results[j].Provider
+	})
+	return results
+}
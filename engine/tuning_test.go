@@ -0,0 +1,62 @@
+package engine
+
+import (
+	goerr "errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+func TestPriorityTuner(t *testing.T) {
+	var disabled *priorityTuner
+	disabled.record(model.MovieKind, "A", nil, time.Second, 1)
+	assert.Equal(t, 1000, disabled.priority(model.MovieKind, "A", 1000))
+
+	tuner := newPriorityTuner(time.Hour)
+	for i := 0; i < minTuningSamples-1; i++ {
+		tuner.record(model.MovieKind, "A", goerr.New("timeout"), time.Second, -1)
+	}
+	// insufficient samples.
+	assert.Equal(t, 1000, tuner.priority(model.MovieKind, "A", 1000))
+
+	tuner.record(model.MovieKind, "A", goerr.New("timeout"), time.Second, -1)
+	assert.Equal(t, int(1000*minTuningFactor), tuner.priority(model.MovieKind, "A", 1000))
+
+	// not found is not a failure of the provider.
+	for i := 0; i < minTuningSamples; i++ {
+		tuner.record(model.MovieKind, "B", mt.ErrInfoNotFound, time.Second, 1)
+		tuner.record(model.MovieKind, "C", nil, 2*tuningLatencyTarget, 1)
+	}
+	assert.Equal(t, 1000, tuner.priority(model.MovieKind, "B", 1000))
+	// slow providers are lowered, and kinds are separated.
+	assert.Less(t, tuner.priority(model.MovieKind, "C", 1000), 1000)
+	assert.Equal(t, 1000, tuner.priority(model.ActorKind, "C", 1000))
+
+	// samples out of the window are dropped.
+	tuner.window = time.Nanosecond
+	time.Sleep(time.Millisecond)
+	stats := tuner.stats(model.MovieKind, "A", 1000)
+	assert.Zero(t, stats.Samples)
+	assert.Equal(t, 1000, stats.Effective)
+}
+
+func TestEngine_GetEffectivePriorities(t *testing.T) {
+	e := newBenchEngine(t, 2)
+	e.actorProviders = map[string]mt.ActorProvider{"FAKE": fake.New()}
+	e.tuner = newPriorityTuner(time.Hour)
+	for i := 0; i < minTuningSamples; i++ {
+		e.tuner.record(model.MovieKind, "Bench1", goerr.New("timeout"), time.Second, -1)
+	}
+	results := e.GetEffectivePriorities()
+	if assert.Len(t, results, 3) {
+		assert.Equal(t, "Bench0", results[0].Provider)
+		assert.Equal(t, "Bench1", results[1].Provider)
+		assert.Less(t, results[1].Effective, results[1].Static)
+		assert.Equal(t, model.ActorKind, results[2].Kind)
+	}
+}
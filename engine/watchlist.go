@@ -0,0 +1,176 @@
+package engine
+
+import (
+	goerr "errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/common/number"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/notify"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+var (
+	ErrInvalidWatchlistEntry  = errors.New(http.StatusBadRequest, "invalid watchlist entry")
+	ErrWatchlistEntryNotFound = errors.New(http.StatusNotFound, "watchlist entry not found")
+)
+
+// GetWatchlist returns all watchlist entries.
+func (e *Engine) GetWatchlist() (entries []*model.WatchlistEntry, err error) {
+	err = e.db.Order("kind, name").Find(&entries).Error
+	return
+}
+
+// GetWatchlistEntry returns the watchlist entry of the kind and name.
+func (e *Engine) GetWatchlistEntry(kind, name string) (*model.WatchlistEntry, error) {
+	entry := &model.WatchlistEntry{}
+	if err := e.db.
+		Where("kind = ?", kind).
+		Where("name = ? COLLATE NOCASE", strings.TrimSpace(name)).
+		First(entry).Error; err != nil {
+		return nil, ErrWatchlistEntryNotFound
+	}
+	return entry, nil
+}
+
+// AddWatchlistEntry adds the entry to the watchlist, and checks its
+// states at once without notifications, failed checks are retried later.
+func (e *Engine) AddWatchlistEntry(entry *model.WatchlistEntry) error {
+	entry.Name = strings.TrimSpace(entry.Name)
+	if entry.Kind == model.MovieKind {
+		entry.Name = number.Trim(entry.Name)
+	}
+	if !entry.Valid() {
+		return ErrInvalidWatchlistEntry
+	}
+	if w, err := e.GetWatchlistEntry(entry.Kind, entry.Name); err == nil {
+		*entry = *w // already watched.
+		return nil
+	}
+	// only states are checked, user inputs are not trusted.
+	*entry = model.WatchlistEntry{Kind: entry.Kind, Name: entry.Name}
+	if _, err := e.checkWatchlistEntry(entry); err != nil {
+		e.logger.Warnw("watchlist check failed", "kind", entry.Kind,
+			"name", e.redactor.String(entry.Name), "error", e.redactor.Error(err, entry.Name))
+	}
+	return e.db.Save(entry).Error
+}
+
+// RemoveWatchlistEntry removes the entry from the watchlist.
+func (e *Engine) RemoveWatchlistEntry(kind, name string) error {
+	entry, err := e.GetWatchlistEntry(kind, name)
+	if err != nil {
+		return err
+	}
+	return e.db.Delete(entry).Error
+}
+
+// CheckWatchlist checks all watchlist entries, and sends events of the
+// changed ones, e.g., movies that become available or released.
+func (e *Engine) CheckWatchlist() error {
+	entries, err := e.GetWatchlist()
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		event, err := e.checkWatchlistEntry(entry)
+		if err != nil {
+			e.logger.Warnw("watchlist check failed", "kind", entry.Kind,
+				"name", e.redactor.String(entry.Name), "error", e.redactor.Error(err, entry.Name))
+			continue
+		}
+		if err = e.db.Save(entry).Error; err != nil {
+			return err
+		}
+		if event != nil {
+			e.Notify(event)
+		}
+	}
+	return nil
+}
+
+// checkWatchlistEntry updates states of the entry, and returns the event
+// of changes, or nil if unchanged. Infos not found yet are not errors,
+// and states are never reset, so that flaky providers don't cause
+// repeated events.
+func (e *Engine) checkWatchlistEntry(entry *model.WatchlistEntry) (*notify.Event, error) {
+	var (
+		changes []string
+		event   = &notify.Event{Kind: notify.WatchlistUpdated, Title: entry.Name}
+	)
+	update := func(provider, id string, released, preview bool) {
+		if !entry.Available() {
+			changes = append(changes, "available")
+		}
+		if released && !entry.Released {
+			changes = append(changes, "released")
+		}
+		if preview && !entry.HasPreview {
+			changes = append(changes, "preview")
+		}
+		entry.Provider, entry.ID = provider, id
+		entry.Released = entry.Released || released
+		entry.HasPreview = entry.HasPreview || preview
+	}
+	var err error
+	switch entry.Kind {
+	case model.MovieKind:
+		var info *model.MovieInfo
+		if info, err = e.getWatchedMovieInfo(entry); err == nil {
+			update(info.Provider, info.ID,
+				!time.Time(info.ReleaseDate).IsZero() && !time.Time(info.ReleaseDate).After(time.Now()),
+				info.PreviewVideoURL != "" || info.PreviewVideoHLSURL != "" || len(info.PreviewImages) > 0)
+			event.Message, event.URL, event.ImageURL = info.Title, info.Homepage, movieImageURL(info, "cover_url", info.CoverURL)
+		}
+	case model.ActorKind:
+		var info *model.ActorInfo
+		if info, err = e.systemAuditor().GetActorInfoByName(entry.Name, false); err == nil {
+			update(info.Provider, info.ID, false, len(info.Images) > 0)
+			event.URL = info.Homepage
+			if len(info.Images) > 0 {
+				event.ImageURL = info.Images[0]
+			}
+		}
+	default:
+		return nil, ErrInvalidWatchlistEntry
+	}
+	if err != nil && !goerr.Is(err, mt.ErrInfoNotFound) && !goerr.Is(err, ErrAmbiguousActorName) {
+		return nil, err
+	}
+	entry.CheckedAt = time.Now().UTC()
+	if len(changes) == 0 {
+		return nil, nil
+	}
+	if event.Message != "" {
+		event.Message += "\n"
+	}
+	event.Message += "Changes: " + strings.Join(changes, ", ")
+	return event, nil
+}
+
+// getWatchedMovieInfo gets the movie info of the entry, from the provider
+// found before, or the best search result of the same number. Infos are
+// always re-scraped, since un-released movies are usually updated later.
+func (e *Engine) getWatchedMovieInfo(entry *model.WatchlistEntry) (*model.MovieInfo, error) {
+	if entry.Available() {
+		if info, err := e.systemAuditor().GetMovieInfoByProviderID(entry.Provider, entry.ID, false); err == nil {
+			return info, nil
+		}
+	}
+	results, err := e.SearchMovieAll(entry.Name, false)
+	if err != nil {
+		return nil, err
+	}
+	// only results of the same number are trusted, the entry is left
+	// pending until the movie shows up.
+	canonical := number.Canonicalize("", entry.Name)
+	for _, result := range results {
+		if strings.EqualFold(number.Canonicalize(result.Provider, result.Number), canonical) {
+			return e.systemAuditor().GetMovieInfoByProviderID(result.Provider, result.ID, false)
+		}
+	}
+	return nil, mt.ErrInfoNotFound
+}
@@ -0,0 +1,36 @@
+package engine
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+func TestEngine_WatchMovie(t *testing.T) {
+	e := newBenchEngine(t, 0)
+	f := &seriesFake{Fake: fake.New(), results: map[string][]string{
+		// search results of other numbers only.
+		"FAKE-002": {"FAKE-001"},
+	}}
+	e.movieProviders = map[string]mt.MovieProvider{"FAKE": f}
+
+	entry := &model.WatchlistEntry{Kind: model.MovieKind, Name: "fake-002.mp4"}
+	require.NoError(t, e.AddWatchlistEntry(entry))
+	assert.Equal(t, "fake-002", entry.Name)
+	assert.False(t, entry.Available(), "results of other numbers are not trusted")
+	assert.False(t, entry.CheckedAt.IsZero())
+
+	f.results["FAKE-002"] = []string{"FAKE-001", "FAKE-002"}
+	require.NoError(t, e.CheckWatchlist())
+	entry, err := e.GetWatchlistEntry(model.MovieKind, "FAKE-002")
+	require.NoError(t, err)
+	assert.Equal(t, "Fake", entry.Provider)
+	assert.Equal(t, "FAKE-002", entry.ID)
+	assert.True(t, entry.Released)
+	assert.True(t, entry.HasPreview)
+}
@@ -0,0 +1,50 @@
+package export
+
+import (
+	"maps"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// Chromecast metadata type of movies.
+const castMovieMetadataType = 1
+
+// CastMetadata is the Google Cast MovieMediaMetadata.
+type CastMetadata struct {
+	MetadataType int         `json:"metadataType"`
+	Title        string      `json:"title"`
+	Subtitle     string      `json:"subtitle,omitempty"`
+	Studio       string      `json:"studio,omitempty"`
+	ReleaseDate  string      `json:"releaseDate,omitempty"`
+	Images       []CastImage `json:"images"`
+	// CustomData holds extra fields and the source of the info for
+	// receivers, see model.Source.
+	CustomData model.Extra `json:"customData,omitempty"`
+}
+
+type CastImage struct {
+	URL string `json:"url"`
+}
+
+// Cast converts the movie info into the Google Cast movie metadata.
+func Cast(info *model.MovieInfo) *CastMetadata {
+	metadata := &CastMetadata{
+		MetadataType: castMovieMetadataType,
+		Title:        displayTitle(info),
+		Subtitle:     info.Series,
+		Studio:       info.Maker,
+		ReleaseDate:  formatDate(info.ReleaseDate, time.DateOnly),
+		Images:       []CastImage{},
+		CustomData:   maps.Clone(info.Extra),
+	}
+	if info.Source != nil {
+		metadata.CustomData.Set("source", info.Source)
+	}
+	for _, url := range []string{preferredCover(info), info.ThumbURL} {
+		if url != "" {
+			metadata.Images = append(metadata.Images, CastImage{URL: url})
+		}
+	}
+	return metadata
+}
@@ -0,0 +1,109 @@
+package export
+
+import (
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// DeoVR screen types, see https://deovr.com/app/doc.
+const (
+	deoVRFlat    = "flat"
+	deoVRDome    = "dome"
+	deoVRSphere  = "sphere"
+	deoVRFisheye = "fisheye"
+	deoVRMKX200  = "mkx200"
+)
+
+// DeoVRVideo is the video JSON of DeoVR, which is also consumed by
+// HereSphere.
+type DeoVRVideo struct {
+	Title        string          `json:"title"`
+	Description  string          `json:"description,omitempty"`
+	Date         int64           `json:"date,omitempty"`
+	VideoLength  int             `json:"videoLength,omitempty"`
+	Is3D         bool            `json:"is3d"`
+	ScreenType   string          `json:"screenType"`
+	StereoMode   string          `json:"stereoMode"`
+	ThumbnailURL string          `json:"thumbnailUrl,omitempty"`
+	VideoPreview string          `json:"videoPreview,omitempty"`
+	Encodings    []DeoVREncoding `json:"encodings"`
+	Actors       []DeoVRName     `json:"actors"`
+	Categories   []DeoVRCategory `json:"categories"`
+	Paysite      *DeoVRName      `json:"paysite,omitempty"`
+	// Extra holds extra fields of the info, which are ignored by players.
+	Extra model.Extra `json:"extra,omitempty"`
+	// Source credits the provider of the info, see model.Source.
+	Source *model.Source `json:"source,omitempty"`
+}
+
+// DeoVREncoding is an encoding of videos, which is left to media servers
+// of the video files.
+type DeoVREncoding struct {
+	Name         string             `json:"name"`
+	VideoSources []DeoVRVideoSource `json:"videoSources"`
+}
+
+type DeoVRVideoSource struct {
+	Resolution int    `json:"resolution,omitempty"`
+	URL        string `json:"url"`
+}
+
+type DeoVRName struct {
+	Name string `json:"name"`
+}
+
+type DeoVRCategory struct {
+	Tag DeoVRName `json:"tag"`
+}
+
+// DeoVR converts the movie info into the DeoVR video JSON. Movies without
+// VR metadata are exported as flat ones.
+func DeoVR(info *model.MovieInfo) *DeoVRVideo {
+	video := &DeoVRVideo{
+		Title:        displayTitle(info),
+		Description:  info.Summary,
+		VideoLength:  info.Runtime * 60,
+		ScreenType:   deoVRFlat,
+		StereoMode:   "off",
+		ThumbnailURL: preferredCover(info),
+		VideoPreview: info.PreviewVideoURL,
+		Encodings:    []DeoVREncoding{},
+		Actors:       []DeoVRName{},
+		Categories:   []DeoVRCategory{},
+		Extra:        info.Extra,
+		Source:       info.Source,
+	}
+	if date := time.Time(model.NormalizeDate(info.ReleaseDate)); !date.IsZero() {
+		video.Date = date.Unix()
+	}
+	if vr := info.VR; vr != nil {
+		video.ScreenType = deoVRScreenType(vr)
+		switch vr.StereoMode {
+		case model.SideBySideStereo, model.TopBottomStereo:
+			video.Is3D, video.StereoMode = true, vr.StereoMode
+		}
+	}
+	for _, actor := range info.Actors {
+		video.Actors = append(video.Actors, DeoVRName{Name: actor})
+	}
+	for _, genre := range info.Genres {
+		video.Categories = append(video.Categories, DeoVRCategory{Tag: DeoVRName{Name: genre}})
+	}
+	if info.Maker != "" {
+		video.Paysite = &DeoVRName{Name: info.Maker}
+	}
+	return video
+}
+
+func deoVRScreenType(vr *model.VRInfo) string {
+	switch {
+	case vr.Projection == model.FisheyeProjection && vr.FOV >= 200:
+		return deoVRMKX200
+	case vr.Projection == model.FisheyeProjection:
+		return deoVRFisheye
+	case vr.FOV >= 360:
+		return deoVRSphere
+	}
+	return deoVRDome
+}
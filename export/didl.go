@@ -0,0 +1,119 @@
+package export
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// DIDLLite is the DIDL-Lite document used by DLNA/UPnP media servers.
+type DIDLLite struct {
+	XMLName xml.Name   `xml:"DIDL-Lite"`
+	XMLNS   string     `xml:"xmlns,attr"`
+	DC      string     `xml:"xmlns:dc,attr"`
+	UPnP    string     `xml:"xmlns:upnp,attr"`
+	Items   []DIDLItem `xml:"item"`
+}
+
+type DIDLItem struct {
+	ID              string     `xml:"id,attr"`
+	ParentID        string     `xml:"parentID,attr"`
+	Restricted      string     `xml:"restricted,attr"`
+	Title           string     `xml:"dc:title"`
+	Source          string     `xml:"dc:source,omitempty"`
+	Date            string     `xml:"dc:date,omitempty"`
+	Description     string     `xml:"dc:description,omitempty"`
+	Publisher       string     `xml:"dc:publisher,omitempty"`
+	Class           string     `xml:"upnp:class"`
+	LongDescription string     `xml:"upnp:longDescription,omitempty"`
+	Actors          []string   `xml:"upnp:actor"`
+	Directors       []string   `xml:"upnp:director"`
+	Genres          []string   `xml:"upnp:genre"`
+	Rating          string     `xml:"upnp:rating,omitempty"`
+	AlbumArtURI     string     `xml:"upnp:albumArtURI,omitempty"`
+	Resources       []DIDLRes  `xml:"res"`
+	Descs           []DIDLDesc `xml:"desc"`
+}
+
+type DIDLRes struct {
+	ProtocolInfo string `xml:"protocolInfo,attr"`
+	URL          string `xml:",chardata"`
+}
+
+// DIDLDesc is the vendor-specific metadata of items, extra fields of infos
+// are kept in JSON by names.
+type DIDLDesc struct {
+	ID        string `xml:"id,attr"`
+	NameSpace string `xml:"nameSpace,attr"`
+	Value     string `xml:",chardata"`
+}
+
+// Name spaces of descs, of extra fields by names and of the source in
+// JSON, see model.Source.
+const (
+	DIDLExtraNameSpace  = "urn:metatube:extra"
+	DIDLSourceNameSpace = "urn:metatube:source"
+)
+
+// DIDL converts the movie info into a DIDL-Lite movie item, the trailer is
+// attached as a resource if present.
+func DIDL(info *model.MovieInfo, opts ...Option) *DIDLLite {
+	o := newOptions(opts)
+	item := DIDLItem{
+		ID:              info.Provider + ":" + info.ID,
+		ParentID:        "-1",
+		Restricted:      "1",
+		Title:           displayTitle(info),
+		Date:            formatDate(info.ReleaseDate, time.DateOnly),
+		Description:     info.Summary,
+		Publisher:       info.Maker,
+		Class:           "object.item.videoItem.movie",
+		LongDescription: info.Summary,
+		Actors:          info.Actors,
+		Genres:          info.Genres,
+		Rating:          o.ratings.Get(RatingTargetDIDL),
+		AlbumArtURI:     preferredCover(info),
+	}
+	if info.Director != "" {
+		item.Directors = []string{info.Director}
+	}
+	if info.PreviewVideoURL != "" {
+		item.Resources = append(item.Resources, DIDLRes{
+			ProtocolInfo: "http-get:*:video/mp4:*",
+			URL:          info.PreviewVideoURL,
+		})
+	}
+	if info.Source != nil {
+		item.Source = info.Source.URL
+		data, _ := json.Marshal(info.Source)
+		item.Descs = append(item.Descs, DIDLDesc{
+			ID:        "source",
+			NameSpace: DIDLSourceNameSpace,
+			Value:     string(data),
+		})
+	}
+	for _, key := range info.Extra.Keys() {
+		item.Descs = append(item.Descs, DIDLDesc{
+			ID:        key,
+			NameSpace: DIDLExtraNameSpace,
+			Value:     string(info.Extra[key]),
+		})
+	}
+	return &DIDLLite{
+		XMLNS: "urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/",
+		DC:    "http://purl.org/dc/elements/1.1/",
+		UPnP:  "urn:schemas-upnp-org:metadata-1-0/upnp/",
+		Items: []DIDLItem{item},
+	}
+}
+
+// MarshalDIDL returns the DIDL-Lite XML document of the movie info.
+func MarshalDIDL(info *model.MovieInfo, opts ...Option) ([]byte, error) {
+	data, err := xml.MarshalIndent(DIDL(info, opts...), "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), data...), nil
+}
@@ -0,0 +1,42 @@
+// Package export converts movie infos into metadata payloads of media
+// servers and players, e.g., DLNA/DIDL-Lite, Jellyfin, Google Cast and
+// DeoVR.
+package export
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func displayTitle(info *model.MovieInfo) string {
+	if info.Number == "" {
+		return info.Title
+	}
+	return info.Number + " " + info.Title
+}
+
+func preferredCover(info *model.MovieInfo) string {
+	if artwork := info.Artwork(model.BackdropArtwork, 0); artwork != nil {
+		return artwork.URL
+	}
+	return ""
+}
+
+// formatDate formats the calendar date of the date, which is normalized
+// first, so that layouts with time zones, e.g., RFC 3339, are in UTC.
+func formatDate(date datatypes.Date, layout string) string {
+	if t := time.Time(model.NormalizeDate(date)); !t.IsZero() {
+		return t.Format(layout)
+	}
+	return ""
+}
+
+func nonNil(ss []string) []string {
+	if ss == nil {
+		return []string{}
+	}
+	return ss
+}
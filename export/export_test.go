@@ -0,0 +1,162 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+var testMovieInfo = &model.MovieInfo{
+	ID:              "abc00123",
+	Number:          "ABC-123",
+	Title:           "Title & More",
+	Summary:         "Summary",
+	Provider:        "FANZA",
+	Homepage:        "https://example.com/abc00123",
+	Director:        "Director",
+	Actors:          []string{"Actor A", "Actor B"},
+	CoverURL:        "https://example.com/cover.jpg",
+	PreviewVideoURL: "https://example.com/trailer.mp4",
+	Maker:           "Maker",
+	Genres:          []string{"Drama"},
+	Score:           4.5,
+	Runtime:         120,
+	ReleaseDate:     datatypes.Date(time.Date(2022, 3, 4, 0, 0, 0, 0, time.UTC)),
+}
+
+func TestMarshalDIDL(t *testing.T) {
+	data, err := MarshalDIDL(testMovieInfo)
+	if assert.NoError(t, err) {
+		s := string(data)
+		for _, want := range []string{
+			`<DIDL-Lite xmlns="urn:schemas-upnp-org:metadata-1-0/DIDL-Lite/"`,
+			`<item id="FANZA:abc00123" parentID="-1" restricted="1">`,
+			`<dc:title>ABC-123 Title &amp; More</dc:title>`,
+			`<dc:date>2022-03-04</dc:date>`,
+			`<upnp:class>object.item.videoItem.movie</upnp:class>`,
+			`<upnp:actor>Actor B</upnp:actor>`,
+			`<upnp:rating>XXX</upnp:rating>`,
+			`<res protocolInfo="http-get:*:video/mp4:*">https://example.com/trailer.mp4</res>`,
+		} {
+			assert.True(t, strings.Contains(s, want), want)
+		}
+	}
+}
+
+func TestJellyfin(t *testing.T) {
+	item := Jellyfin(testMovieInfo)
+	assert.Equal(t, "ABC-123 Title & More", item.Name)
+	assert.Equal(t, 2022, item.ProductionYear)
+	assert.Equal(t, 9.0, item.CommunityRating)
+	assert.Equal(t, int64(120*60*10_000_000), item.RunTimeTicks)
+	assert.Equal(t, []JellyfinPerson{
+		{Name: "Actor A", Type: "Actor"},
+		{Name: "Actor B", Type: "Actor"},
+		{Name: "Director", Type: "Director"},
+	}, item.People)
+	assert.Equal(t, "XXX", item.OfficialRating)
+	_, err := json.Marshal(item)
+	assert.NoError(t, err)
+
+	item = Jellyfin(testMovieInfo, WithRatings(Ratings{RatingTargetJellyfin: "NC-17"}))
+	assert.Equal(t, "NC-17", item.OfficialRating)
+
+	// dates of local time zones are exported as is, rather than shifted.
+	info := *testMovieInfo
+	info.ReleaseDate = datatypes.Date(time.Date(2022, 3, 4, 0, 0, 0, 0, time.FixedZone("JST", 9*60*60)))
+	assert.Equal(t, "2022-03-04T00:00:00Z", Jellyfin(&info).PremiereDate)
+
+	// editions are tagged by labels.
+	info.Editions = []string{model.FourKEdition, model.DirectorsCutEdition}
+	assert.Subset(t, Jellyfin(&info).Tags, []string{"4K", "Director's Cut"})
+}
+
+func TestDeoVR(t *testing.T) {
+	video := DeoVR(testMovieInfo)
+	assert.Equal(t, "ABC-123 Title & More", video.Title)
+	assert.Equal(t, 120*60, video.VideoLength)
+	assert.Equal(t, "flat", video.ScreenType)
+	assert.Equal(t, "off", video.StereoMode)
+	assert.False(t, video.Is3D)
+
+	info := *testMovieInfo
+	info.VR = model.DefaultVRInfo()
+	video = DeoVR(&info)
+	assert.Equal(t, "dome", video.ScreenType)
+	assert.Equal(t, "sbs", video.StereoMode)
+	assert.True(t, video.Is3D)
+
+	info.VR = &model.VRInfo{Projection: model.FisheyeProjection, StereoMode: model.TopBottomStereo, FOV: 200}
+	video = DeoVR(&info)
+	assert.Equal(t, "mkx200", video.ScreenType)
+	assert.Equal(t, "tb", video.StereoMode)
+	_, err := json.Marshal(video)
+	assert.NoError(t, err)
+}
+
+func TestParseRatings(t *testing.T) {
+	ratings, err := ParseRatings("jellyfin=R18, api= ,")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "R18", ratings.Get(RatingTargetJellyfin))
+		assert.Equal(t, "", ratings.Get(RatingTargetAPI))
+		assert.Equal(t, "XXX", ratings.Get(RatingTargetDIDL))
+	}
+	for _, s := range []string{"nfo=XXX", "XXX"} {
+		_, err = ParseRatings(s)
+		assert.Error(t, err, s)
+	}
+	assert.Equal(t, "R18+", Ratings(nil).Get(RatingTargetAPI))
+}
+
+func TestCast(t *testing.T) {
+	metadata := Cast(testMovieInfo)
+	assert.Equal(t, 1, metadata.MetadataType)
+	assert.Equal(t, "2022-03-04", metadata.ReleaseDate)
+	assert.Equal(t, []CastImage{{URL: testMovieInfo.CoverURL}}, metadata.Images)
+}
+
+func TestExtra(t *testing.T) {
+	info := *testMovieInfo
+	info.Extra = nil
+	assert.NoError(t, info.Extra.Set("price", 1980))
+
+	data, err := MarshalDIDL(&info)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(data), `<desc id="price" nameSpace="urn:metatube:extra">1980</desc>`)
+	}
+	data, err = json.Marshal(Jellyfin(&info))
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(data), `"Extra":{"price":1980}`)
+	}
+	data, err = json.Marshal(Cast(&info))
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(data), `"customData":{"price":1980}`)
+	}
+
+	// omitted if none.
+	data, _ = json.Marshal(Jellyfin(testMovieInfo))
+	assert.NotContains(t, string(data), `"Extra"`)
+}
+
+func TestSource(t *testing.T) {
+	info := *testMovieInfo
+	info.Attribute("Example", time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC))
+
+	data, err := MarshalDIDL(&info)
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(data), `<dc:source>https://example.com/abc00123</dc:source>`)
+		assert.Contains(t, string(data), `<desc id="source" nameSpace="urn:metatube:source">`)
+	}
+	assert.Equal(t, info.Source, Jellyfin(&info).Source)
+	data, err = json.Marshal(Cast(&info))
+	if assert.NoError(t, err) {
+		assert.Contains(t, string(data), `"customData":{"source":{"provider":"Example"`)
+	}
+	assert.Nil(t, Cast(testMovieInfo).CustomData)
+}
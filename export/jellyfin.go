@@ -0,0 +1,150 @@
+package export
+
+import (
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// JellyfinItem is a subset of the Jellyfin BaseItemDto of movies.
+type JellyfinItem struct {
+	Name            string            `json:"Name"`
+	OriginalTitle   string            `json:"OriginalTitle,omitempty"`
+	Overview        string            `json:"Overview,omitempty"`
+	Type            string            `json:"Type"`
+	PremiereDate    string            `json:"PremiereDate,omitempty"`
+	ProductionYear  int               `json:"ProductionYear,omitempty"`
+	CommunityRating float64           `json:"CommunityRating,omitempty"`
+	OfficialRating  string            `json:"OfficialRating,omitempty"`
+	RunTimeTicks    int64             `json:"RunTimeTicks,omitempty"`
+	Genres          []string          `json:"Genres"`
+	Tags            []string          `json:"Tags"`
+	Studios         []JellyfinNameID  `json:"Studios"`
+	People          []JellyfinPerson  `json:"People"`
+	ProviderIds     map[string]string `json:"ProviderIds"`
+	RemoteTrailers  []JellyfinURL     `json:"RemoteTrailers"`
+	ExternalUrls    []JellyfinURL     `json:"ExternalUrls"`
+	// Extra holds extra fields of the info, which are ignored by Jellyfin
+	// but kept for plugins.
+	Extra model.Extra `json:"Extra,omitempty"`
+	// Source credits the provider of the info, see model.Source.
+	Source *model.Source `json:"Source,omitempty"`
+}
+
+type JellyfinNameID struct {
+	Name string `json:"Name"`
+}
+
+type JellyfinPerson struct {
+	Name string `json:"Name"`
+	Type string `json:"Type"`
+}
+
+type JellyfinURL struct {
+	Name string `json:"Name,omitempty"`
+	URL  string `json:"Url"`
+}
+
+// Jellyfin converts the movie info into a Jellyfin compatible item, the
+// provider ID is kept in ProviderIds in the form of `provider:id`.
+func Jellyfin(info *model.MovieInfo, opts ...Option) *JellyfinItem {
+	o := newOptions(opts)
+	item := &JellyfinItem{
+		Name:            displayTitle(info),
+		OriginalTitle:   info.Title,
+		Overview:        info.Summary,
+		Type:            "Movie",
+		PremiereDate:    formatDate(info.ReleaseDate, time.RFC3339),
+		CommunityRating: info.Score * 2, // 5-point to 10-point.
+		OfficialRating:  o.ratings.Get(RatingTargetJellyfin),
+		RunTimeTicks:    int64(time.Duration(info.Runtime) * time.Minute / 100),
+		Genres:          nonNil(info.Genres),
+		Tags:            []string{},
+		Studios:         []JellyfinNameID{},
+		People:          []JellyfinPerson{},
+		ProviderIds:     map[string]string{JellyfinProviderName: info.Provider + ":" + info.ID},
+		RemoteTrailers:  []JellyfinURL{},
+		ExternalUrls:    []JellyfinURL{{Name: info.Provider, URL: info.Homepage}},
+		Extra:           info.Extra,
+		Source:          info.Source,
+	}
+	if date := time.Time(info.ReleaseDate); !date.IsZero() {
+		item.ProductionYear = date.Year()
+	}
+	for _, studio := range []string{info.Maker, info.Label} {
+		if studio != "" {
+			item.Studios = append(item.Studios, JellyfinNameID{Name: studio})
+		}
+	}
+	if info.Series != "" {
+		item.Tags = append(item.Tags, info.Series)
+	}
+	for _, edition := range info.Editions {
+		item.Tags = append(item.Tags, model.EditionLabel(edition))
+	}
+	for _, actor := range info.Actors {
+		item.People = append(item.People, JellyfinPerson{Name: actor, Type: "Actor"})
+	}
+	if info.Director != "" {
+		item.People = append(item.People, JellyfinPerson{Name: info.Director, Type: "Director"})
+	}
+	if info.PreviewVideoURL != "" {
+		item.RemoteTrailers = append(item.RemoteTrailers, JellyfinURL{URL: info.PreviewVideoURL})
+	}
+	return item
+}
+
+// JellyfinRemoteSearchResult is the RemoteSearchResult of Jellyfin
+// remote metadata providers.
+type JellyfinRemoteSearchResult struct {
+	Name               string            `json:"Name"`
+	ProviderIds        map[string]string `json:"ProviderIds"`
+	ProductionYear     int               `json:"ProductionYear,omitempty"`
+	PremiereDate       string            `json:"PremiereDate,omitempty"`
+	ImageURL           string            `json:"ImageUrl,omitempty"`
+	SearchProviderName string            `json:"SearchProviderName"`
+	Overview           string            `json:"Overview,omitempty"`
+}
+
+// JellyfinRemoteImageInfo is the RemoteImageInfo of Jellyfin remote
+// image providers.
+type JellyfinRemoteImageInfo struct {
+	ProviderName string `json:"ProviderName"`
+	URL          string `json:"Url"`
+	Type         string `json:"Type"`
+	Width        int    `json:"Width,omitempty"`
+	Height       int    `json:"Height,omitempty"`
+}
+
+// JellyfinProviderName is the provider name reported to Jellyfin.
+const JellyfinProviderName = "MetaTube"
+
+// JellyfinMovieSearchResult converts the movie search result into a
+// Jellyfin remote search result.
+func JellyfinMovieSearchResult(result *model.MovieSearchResult) *JellyfinRemoteSearchResult {
+	r := &JellyfinRemoteSearchResult{
+		Name:               result.Number + " " + result.Title,
+		ProviderIds:        map[string]string{JellyfinProviderName: result.Provider + ":" + result.ID},
+		PremiereDate:       formatDate(result.ReleaseDate, time.RFC3339),
+		ImageURL:           result.CoverURL,
+		SearchProviderName: JellyfinProviderName,
+	}
+	if date := time.Time(result.ReleaseDate); !date.IsZero() {
+		r.ProductionYear = date.Year()
+	}
+	return r
+}
+
+// JellyfinActorSearchResult converts the actor search result into a
+// Jellyfin remote search result.
+func JellyfinActorSearchResult(result *model.ActorSearchResult) *JellyfinRemoteSearchResult {
+	r := &JellyfinRemoteSearchResult{
+		Name:               result.Name,
+		ProviderIds:        map[string]string{JellyfinProviderName: result.Provider + ":" + result.ID},
+		SearchProviderName: JellyfinProviderName,
+	}
+	if len(result.Images) > 0 {
+		r.ImageURL = result.Images[0]
+	}
+	return r
+}
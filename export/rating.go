@@ -0,0 +1,73 @@
+package export
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Content rating targets.
+const (
+	RatingTargetAPI      = "api"
+	RatingTargetJellyfin = "jellyfin"
+	RatingTargetDIDL     = "didl"
+)
+
+// Ratings maps targets to the content ratings emitted to them, which are
+// the same for all movies, since media servers handle these fields in
+// their own schemes, e.g., `XXX` for Jellyfin parental control.
+type Ratings map[string]string
+
+// DefaultRatings are the ratings of targets not configured.
+var DefaultRatings = Ratings{
+	RatingTargetAPI:      "R18+",
+	RatingTargetJellyfin: "XXX",
+	RatingTargetDIDL:     "XXX",
+}
+
+// Get returns the rating of the target, a target configured with empty
+// rating emits no rating field.
+func (r Ratings) Get(target string) string {
+	if rating, ok := r[target]; ok {
+		return rating
+	}
+	return DefaultRatings[target]
+}
+
+// ParseRatings parses ratings of `target=rating` pairs separated by comma,
+// e.g., `jellyfin=XXX,api=R18+`.
+func ParseRatings(s string) (Ratings, error) {
+	ratings := make(Ratings)
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		target, rating, ok := strings.Cut(pair, "=")
+		target = strings.ToLower(strings.TrimSpace(target))
+		if _, known := DefaultRatings[target]; !ok || !known {
+			return nil, fmt.Errorf("invalid content rating: %s", pair)
+		}
+		ratings[target] = strings.TrimSpace(rating)
+	}
+	return ratings, nil
+}
+
+// Option configures the conversion of exports.
+type Option func(*options)
+
+type options struct {
+	ratings Ratings
+}
+
+func newOptions(opts []Option) *options {
+	o := &options{}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o
+}
+
+// WithRatings sets the content ratings of targets, DefaultRatings are
+// used if not set.
+func WithRatings(ratings Ratings) Option {
+	return func(o *options) { o.ratings = ratings }
+}
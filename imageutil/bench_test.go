@@ -0,0 +1,100 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"testing"
+
+	"github.com/metatube-community/metatube-sdk-go/internal/race"
+)
+
+// benchCover returns a JPEG of the size of typical covers.
+func benchCover(tb testing.TB) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 800, 538))
+	for y := 0; y < 538; y++ {
+		for x := 0; x < 800; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: uint8(x + y), A: 0xff})
+		}
+	}
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, img, nil); err != nil {
+		tb.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// pipeline is what primary images go through: decode, crop, resize and
+// encode.
+func pipeline(data []byte) error {
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	img = Resize(CropImagePosition(img, 0.7, 1), 0, 400)
+	return Encode(io.Discard, img, JPEG, DefaultQuality)
+}
+
+func BenchmarkPipeline(b *testing.B) {
+	data := benchCover(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := pipeline(data); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPerceptionHash(b *testing.B) {
+	img, _, _ := image.Decode(bytes.NewReader(benchCover(b)))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_ = PerceptionHash(img)
+	}
+}
+
+func BenchmarkProbe(b *testing.B) {
+	data := benchCover(b)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, _, _ = Probe(bytes.NewReader(data))
+	}
+}
+
+// Allocation budgets per image, raise them only with a reason. Counts
+// are small as buffers are allocated whole, e.g., ~6MB per pipeline run
+// of a cover, so a regression in count usually means per-pixel allocs.
+const (
+	pipelineAllocBudget = 32 // ~24 by decoding, resizing and encoding.
+	hashAllocBudget     = 32 // ~22 by grayscale and DCT buffers.
+	probeAllocBudget    = 8  // ~5 by header decoding.
+)
+
+func TestAllocBudgets(t *testing.T) {
+	if race.Enabled {
+		t.Skip("allocations are inflated by the race detector")
+	}
+	data := benchCover(t)
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, unit := range []struct {
+		name   string
+		budget int
+		run    func()
+	}{
+		{"pipeline", pipelineAllocBudget, func() { _ = pipeline(data) }},
+		{"PerceptionHash", hashAllocBudget, func() { _ = PerceptionHash(img) }},
+		{"Probe", probeAllocBudget, func() { _, _, _ = Probe(bytes.NewReader(data)) }},
+	} {
+		if n := testing.AllocsPerRun(5, unit.run); n > float64(unit.budget) {
+			t.Errorf("%s allocs %.0f > budget %d", unit.name, n, unit.budget)
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package imageutil
+
+import (
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"strings"
+
+	"github.com/HugoSmits86/nativewebp"
+	"github.com/gen2brain/avif"
+)
+
+// Format is the encoding format of images.
+type Format string
+
+const (
+	JPEG Format = "jpeg"
+	PNG  Format = "png"
+	WebP Format = "webp"
+	AVIF Format = "avif"
+)
+
+// DefaultQuality is the default encoding quality of lossy formats.
+const DefaultQuality = 90
+
+// ParseFormat parses format from string, an empty string is parsed as JPEG.
+func ParseFormat(s string) (Format, error) {
+	switch f := Format(strings.ToLower(s)); f {
+	case "", "jpg":
+		return JPEG, nil
+	case JPEG, PNG, WebP, AVIF:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid image format: %s", s)
+	}
+}
+
+// MIMEType returns the MIME type of the format.
+func (f Format) MIMEType() string { return "image/" + string(f) }
+
+// Encode writes the image to w in the given format. The quality in range
+// [1, 100] applies to lossy formats only, WebP is always encoded losslessly.
+func Encode(w io.Writer, img image.Image, format Format, quality int) error {
+	if quality <= 0 || quality > 100 {
+		quality = DefaultQuality
+	}
+	switch format {
+	case JPEG, "":
+		return jpeg.Encode(w, img, &jpeg.Options{Quality: quality})
+	case PNG:
+		return png.Encode(w, img)
+	case WebP:
+		return nativewebp.Encode(w, img, nil)
+	case AVIF:
+		return avif.Encode(w, img, avif.Options{
+			Quality:           quality,
+			QualityAlpha:      quality,
+			Speed:             avif.DefaultSpeed,
+			ChromaSubsampling: image.YCbCrSubsampleRatio420,
+		})
+	default:
+		return fmt.Errorf("unsupported image format: %s", format)
+	}
+}
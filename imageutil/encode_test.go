@@ -0,0 +1,78 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFormat(t *testing.T) {
+	for _, unit := range []struct {
+		s    string
+		want Format
+		err  bool
+	}{
+		{"", JPEG, false},
+		{"jpg", JPEG, false},
+		{"JPEG", JPEG, false},
+		{"png", PNG, false},
+		{"WebP", WebP, false},
+		{"avif", AVIF, false},
+		{"gif", "", true},
+	} {
+		format, err := ParseFormat(unit.s)
+		assert.Equal(t, unit.want, format, unit.s)
+		assert.Equal(t, unit.err, err != nil, unit.s)
+	}
+	assert.Equal(t, "image/webp", WebP.MIMEType())
+}
+
+func TestEncode(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 64, 48))
+	for y := 0; y < 48; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 4), G: uint8(y * 5), B: 0x80, A: 0xff})
+		}
+	}
+	encode := func(format Format, quality int) []byte {
+		buf := &bytes.Buffer{}
+		require.NoError(t, Encode(buf, img, format, quality), format)
+		return buf.Bytes()
+	}
+
+	for _, unit := range []struct {
+		format Format
+		// magic returns whether the data is of the format.
+		magic func(data []byte) bool
+	}{
+		{JPEG, func(data []byte) bool { return bytes.HasPrefix(data, []byte{0xff, 0xd8, 0xff}) }},
+		{"", func(data []byte) bool { return bytes.HasPrefix(data, []byte{0xff, 0xd8, 0xff}) }},
+		{PNG, func(data []byte) bool { return bytes.HasPrefix(data, []byte("\x89PNG\r\n\x1a\n")) }},
+		{WebP, func(data []byte) bool {
+			return len(data) > 12 && string(data[:4]) == "RIFF" && string(data[8:12]) == "WEBP"
+		}},
+		{AVIF, func(data []byte) bool { return len(data) > 12 && string(data[4:12]) == "ftypavif" }},
+	} {
+		data := encode(unit.format, DefaultQuality)
+		assert.True(t, unit.magic(data), "format %q", unit.format)
+	}
+
+	// decoded as is.
+	for _, format := range []Format{JPEG, PNG} {
+		decoded, name, err := image.Decode(bytes.NewReader(encode(format, DefaultQuality)))
+		require.NoError(t, err)
+		assert.Equal(t, string(format), name)
+		assert.Equal(t, img.Bounds(), decoded.Bounds())
+	}
+
+	// qualities out of range are the default.
+	assert.Equal(t, encode(JPEG, DefaultQuality), encode(JPEG, 0))
+	assert.Equal(t, encode(JPEG, DefaultQuality), encode(JPEG, 101))
+	assert.Less(t, len(encode(JPEG, 10)), len(encode(JPEG, 100)))
+
+	assert.Error(t, Encode(&bytes.Buffer{}, img, "gif", DefaultQuality))
+}
@@ -33,6 +33,15 @@ func PerceptionHashDistance(imgA, imgB image.Image) (distance int) {
 	return
 }
 
+// PerceptionHash returns the 64-bit perception hash of the image.
+func PerceptionHash(img image.Image) uint64 {
+	hash, _ := goimagehash.PerceptionHash(img)
+	if hash == nil {
+		return 0
+	}
+	return hash.GetHash()
+}
+
 func Similar(imgA, imgB image.Image) bool {
 	switch {
 	case AverageHashDistance(imgA, imgB) < thAverageHash:
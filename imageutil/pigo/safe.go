@@ -0,0 +1,63 @@
+package pigo
+
+import (
+	"image"
+	"sort"
+
+	"github.com/metatube-community/metatube-sdk-go/imageutil"
+)
+
+const (
+	// head room above the face center, in face sizes.
+	safeCropHeadRoom = 1.0
+	// body room below the face center, in face sizes, which keeps
+	// the shoulders and upper chest but nothing below.
+	safeCropBodyRoom = 2.5
+	// portion of the image height kept if no face is detected.
+	safeCropFallback = 0.4
+)
+
+// SafeCrop crops the image to the given aspect ratio, keeping only the
+// region of the most prominent face and the upper body around it, so that
+// explicit content in the lower part of covers is excluded. The top portion
+// of the image is kept if no face is detected.
+func SafeCrop(img image.Image, ratio float64) image.Image {
+	return imageutil.CropImage(img, SafeCropRect(img, ratio).Add(img.Bounds().Min))
+}
+
+// SafeCropRect returns the safe crop region of the image, relative to
+// the top-left corner of the image bounds.
+func SafeCropRect(img image.Image, ratio float64) image.Rectangle {
+	var (
+		width  = img.Bounds().Dx()
+		height = img.Bounds().Dy()
+	)
+	dets := DetectFaces(img)
+	if len(dets) == 0 {
+		return fitRect(width, height, width/2, 0, int(float64(height)*safeCropFallback), ratio)
+	}
+	sort.SliceStable(dets, func(i, j int) bool {
+		return float32(dets[i].Scale)*dets[i].Q > float32(dets[j].Scale)*dets[j].Q
+	})
+	var (
+		face   = float64(dets[0].Scale)
+		top    = max(dets[0].Row-int(face*safeCropHeadRoom), 0)
+		bottom = min(dets[0].Row+int(face*safeCropBodyRoom), height)
+	)
+	return fitRect(width, height, dets[0].Col, top, bottom-top, ratio)
+}
+
+// fitRect fits a rectangle of the ratio into the image, starting from top
+// and horizontally centered at cx, with the height no more than h.
+func fitRect(width, height, cx, top, h int, ratio float64) image.Rectangle {
+	if ratio <= 0 {
+		return image.Rect(0, top, width, top+h)
+	}
+	w := int(float64(h) * ratio)
+	if w > width {
+		w, h = width, int(float64(width)/ratio)
+	}
+	h = min(h, height-top)
+	x := max(min(cx-w/2, width-w), 0)
+	return image.Rect(x, top, x+w, top+h)
+}
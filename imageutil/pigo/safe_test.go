@@ -0,0 +1,52 @@
+package pigo
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFitRect(t *testing.T) {
+	for _, unit := range []struct {
+		name                      string
+		width, height, cx, top, h int
+		ratio                     float64
+		want                      image.Rectangle
+	}{
+		{"centered", 800, 600, 400, 100, 200, 1, image.Rect(300, 100, 500, 300)},
+		{"no ratio", 800, 600, 400, 100, 200, 0, image.Rect(0, 100, 800, 300)},
+		{"left edge", 800, 600, 10, 0, 200, 1, image.Rect(0, 0, 200, 200)},
+		{"right edge", 800, 600, 790, 0, 200, 1, image.Rect(600, 0, 800, 200)},
+		{"wider than image", 400, 600, 200, 0, 300, 2, image.Rect(0, 0, 400, 200)},
+		{"below bottom", 800, 600, 400, 500, 200, 1, image.Rect(300, 500, 500, 600)},
+		{"empty", 0, 0, 0, 0, 0, 1, image.Rect(0, 0, 0, 0)},
+	} {
+		assert.Equal(t, unit.want, fitRect(unit.width, unit.height, unit.cx, unit.top, unit.h, unit.ratio), unit.name)
+	}
+}
+
+func TestSafeCropRect(t *testing.T) {
+	// the top portion is kept without faces.
+	for _, unit := range []struct {
+		width, height int
+		ratio         float64
+		want          image.Rectangle
+	}{
+		{800, 538, 0.7, image.Rect(325, 0, 475, 215)},
+		{800, 538, 0, image.Rect(0, 0, 800, 215)},
+		{100, 1000, 1, image.Rect(0, 0, 100, 100)},
+		{1, 1, 1, image.Rect(0, 0, 0, 0)},
+	} {
+		img := image.NewGray(image.Rect(0, 0, unit.width, unit.height))
+		assert.Equal(t, unit.want, SafeCropRect(img, unit.ratio), "%dx%d", unit.width, unit.height)
+	}
+}
+
+func TestSafeCrop(t *testing.T) {
+	// regions are relative to the image bounds.
+	img := image.NewGray(image.Rect(100, 100, 900, 638))
+	cropped := SafeCrop(img, 0.7)
+	assert.Equal(t, 150, cropped.Bounds().Dx())
+	assert.Equal(t, 215, cropped.Bounds().Dy())
+}
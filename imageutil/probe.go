@@ -0,0 +1,16 @@
+package imageutil
+
+import (
+	"image"
+	"io"
+)
+
+// ProbeSize is the max number of header bytes read to probe an image,
+// which covers the EXIF segments of most JPEG images.
+const ProbeSize = 64 << 10
+
+// Probe decodes the dimensions and format name of the image from at most
+// ProbeSize bytes of r, without decoding the entire image.
+func Probe(r io.Reader) (image.Config, string, error) {
+	return image.DecodeConfig(io.LimitReader(r, ProbeSize))
+}
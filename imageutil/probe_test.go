@@ -0,0 +1,37 @@
+package imageutil
+
+import (
+	"bytes"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProbe(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 800, 538))
+	for _, unit := range []struct {
+		format string
+		encode func(w io.Writer, m image.Image) error
+	}{
+		{"png", png.Encode},
+		{"jpeg", func(w io.Writer, m image.Image) error { return jpeg.Encode(w, m, nil) }},
+	} {
+		buf := &bytes.Buffer{}
+		if !assert.NoError(t, unit.encode(buf, img)) {
+			continue
+		}
+		// trailing data beyond the probe size must not be required.
+		buf.Write(make([]byte, 2*ProbeSize))
+		cfg, format, err := Probe(buf)
+		if assert.NoError(t, err) {
+			assert.Equal(t, unit.format, format)
+			assert.Equal(t, 800, cfg.Width)
+			assert.Equal(t, 538, cfg.Height)
+		}
+		assert.GreaterOrEqual(t, buf.Len(), ProbeSize)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build race
+
+// Package race reports whether the race detector is enabled, e.g., to
+// skip allocation budgets which the detector inflates.
+package race
+
+const Enabled = true
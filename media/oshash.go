@@ -0,0 +1,41 @@
+package media
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+)
+
+// oshashChunkSize is the size of the head and tail chunks hashed.
+const oshashChunkSize = 64 << 10
+
+// OSHash returns the OpenSubtitles hash of the file, which is the sum of
+// the size and 64-bit words of the head and tail 64KB chunks, so that it's
+// cheap to compute even for huge videos.
+func OSHash(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	fi, err := f.Stat()
+	if err != nil {
+		return "", err
+	}
+	size := fi.Size()
+	if size < oshashChunkSize {
+		return "", fmt.Errorf("file too small to hash: %d bytes", size)
+	}
+	hash := uint64(size)
+	buf := make([]byte, oshashChunkSize)
+	for _, offset := range []int64{0, size - oshashChunkSize} {
+		if _, err = f.ReadAt(buf, offset); err != nil && err != io.EOF {
+			return "", err
+		}
+		for i := 0; i < len(buf); i += 8 {
+			hash += binary.LittleEndian.Uint64(buf[i:])
+		}
+	}
+	return fmt.Sprintf("%016x", hash), nil
+}
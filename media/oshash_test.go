@@ -0,0 +1,29 @@
+package media
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOSHash(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "video.mp4")
+	// zero bytes sum to the size only.
+	assert.NoError(t, os.WriteFile(name, make([]byte, 3*oshashChunkSize), 0o644))
+	hash, err := OSHash(name)
+	assert.NoError(t, err)
+	assert.Equal(t, "0000000000030000", hash)
+
+	data := make([]byte, 2*oshashChunkSize)
+	data[0], data[len(data)-8] = 1, 2
+	assert.NoError(t, os.WriteFile(name, data, 0o644))
+	hash, err = OSHash(name)
+	assert.NoError(t, err)
+	assert.Equal(t, "0000000000020003", hash)
+
+	assert.NoError(t, os.WriteFile(name, []byte("tiny"), 0o644))
+	_, err = OSHash(name)
+	assert.Error(t, err)
+}
@@ -0,0 +1,39 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strings"
+)
+
+var _ Remuxer = (*FFmpegRemuxer)(nil)
+
+// FFmpegRemuxer remuxes HLS streams with the ffmpeg executable, streams
+// are copied without transcoding, so it's fast and lossless.
+type FFmpegRemuxer struct {
+	// Path of the ffmpeg executable, `ffmpeg` in PATH is used if empty.
+	Path string
+}
+
+func (r *FFmpegRemuxer) Remux(ctx context.Context, hlsURL string, w io.Writer) error {
+	path := r.Path
+	if path == "" {
+		path = "ffmpeg"
+	}
+	stderr := &bytes.Buffer{}
+	cmd := exec.CommandContext(ctx, path,
+		"-hide_banner", "-loglevel", "error",
+		"-i", hlsURL,
+		"-c", "copy", "-bsf:a", "aac_adtstoasc",
+		// fragmented MP4 can be written to non-seekable outputs.
+		"-movflags", "frag_keyframe+empty_moov",
+		"-f", "mp4", "pipe:1")
+	cmd.Stdout, cmd.Stderr = w, stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package media
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+var ErrTrailerNotFound = errors.New(http.StatusNotFound, "trailer not found")
+
+// TrailerKind is the kind of trailer video.
+type TrailerKind uint8
+
+const (
+	MP4Trailer TrailerKind = iota
+	HLSTrailer
+)
+
+func (k TrailerKind) String() string {
+	switch k {
+	case MP4Trailer:
+		return "mp4"
+	case HLSTrailer:
+		return "hls"
+	default:
+		return "unknown"
+	}
+}
+
+// Trailer is a playable trailer video of a movie.
+type Trailer struct {
+	URL  string      `json:"url"`
+	Kind TrailerKind `json:"kind"`
+}
+
+// Remuxer remuxes HLS streams into MP4 videos.
+type Remuxer interface {
+	// Remux writes the MP4 video remuxed from the HLS stream to w.
+	Remux(ctx context.Context, hlsURL string, w io.Writer) error
+}
+
+// Resolver resolves playable trailers of movies.
+type Resolver struct {
+	fetcher *fetch.Fetcher
+	remuxer Remuxer
+}
+
+// NewResolver returns a *Resolver with the remuxer, remuxing is
+// disabled if the remuxer is nil.
+func NewResolver(fetcher *fetch.Fetcher, remuxer Remuxer) *Resolver {
+	if fetcher == nil {
+		fetcher = fetch.DefaultFetcher
+	}
+	return &Resolver{fetcher: fetcher, remuxer: remuxer}
+}
+
+var defaultResolver = NewResolver(nil, nil)
+
+// ResolveTrailer resolves the trailer of info with the default resolver.
+func ResolveTrailer(info *model.MovieInfo) (*Trailer, error) {
+	return defaultResolver.Resolve(info)
+}
+
+// Resolve picks the playable trailer of info, MP4 is preferred over HLS
+// when both exist since MP4 is playable everywhere.
+func (r *Resolver) Resolve(info *model.MovieInfo) (*Trailer, error) {
+	if info == nil {
+		return nil, ErrTrailerNotFound
+	}
+	if info.PreviewVideoURL != "" && r.playableMP4(info.PreviewVideoURL) {
+		return &Trailer{URL: info.PreviewVideoURL, Kind: MP4Trailer}, nil
+	}
+	if info.PreviewVideoHLSURL != "" && r.playableHLS(info.PreviewVideoHLSURL) {
+		return &Trailer{URL: info.PreviewVideoHLSURL, Kind: HLSTrailer}, nil
+	}
+	return nil, ErrTrailerNotFound
+}
+
+// WriteMP4 writes the trailer to w as an MP4 video, HLS trailers are
+// remuxed by the remuxer of the resolver.
+func (r *Resolver) WriteMP4(ctx context.Context, trailer *Trailer, w io.Writer) error {
+	switch trailer.Kind {
+	case MP4Trailer:
+		resp, err := r.fetcher.Get(trailer.URL, fetch.WithRequest(func(req *http.Request) {
+			*req = *req.WithContext(ctx)
+		}))
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
+		_, err = io.Copy(w, resp.Body)
+		return err
+	case HLSTrailer:
+		if r.remuxer == nil {
+			return errors.New(http.StatusNotImplemented, "hls remuxing not enabled")
+		}
+		return r.remuxer.Remux(ctx, trailer.URL, w)
+	default:
+		return ErrTrailerNotFound
+	}
+}
+
+// playableMP4 checks the video by requesting its first byte only.
+func (r *Resolver) playableMP4(url string) bool {
+	resp, err := r.fetcher.Get(url,
+		fetch.WithRaiseForStatus(false),
+		fetch.WithHeader("Range", "bytes=0-0"))
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		return false
+	}
+	ct := resp.Header.Get("Content-Type")
+	return ct == "" || strings.HasPrefix(ct, "video/") ||
+		strings.HasPrefix(ct, "application/octet-stream")
+}
+
+// playableHLS checks the header line of the playlist.
+func (r *Resolver) playableHLS(url string) bool {
+	resp, err := r.fetcher.Fetch(url)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+	line, _ := bufio.NewReader(resp.Body).ReadString('\n')
+	return strings.HasPrefix(strings.TrimSpace(line), "#EXTM3U")
+}
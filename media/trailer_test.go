@@ -0,0 +1,54 @@
+package media
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func TestResolver_Resolve(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/video.mp4":
+			w.Header().Set("Content-Type", "video/mp4")
+			w.WriteHeader(http.StatusPartialContent)
+			w.Write([]byte{0})
+		case "/playlist.m3u8":
+			w.Write([]byte("#EXTM3U\n#EXT-X-VERSION:3\n"))
+		case "/broken.m3u8":
+			w.Write([]byte("<html></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	r := NewResolver(nil, nil)
+	for _, unit := range []struct {
+		mp4, hls string
+		want     *Trailer
+	}{
+		{"/video.mp4", "/playlist.m3u8", &Trailer{URL: srv.URL + "/video.mp4", Kind: MP4Trailer}},
+		{"/missing.mp4", "/playlist.m3u8", &Trailer{URL: srv.URL + "/playlist.m3u8", Kind: HLSTrailer}},
+		{"", "/playlist.m3u8", &Trailer{URL: srv.URL + "/playlist.m3u8", Kind: HLSTrailer}},
+		{"/missing.mp4", "/broken.m3u8", nil},
+	} {
+		info := &model.MovieInfo{}
+		if unit.mp4 != "" {
+			info.PreviewVideoURL = srv.URL + unit.mp4
+		}
+		info.PreviewVideoHLSURL = srv.URL + unit.hls
+		trailer, err := r.Resolve(info)
+		if unit.want == nil {
+			assert.Equal(t, ErrTrailerNotFound, err)
+			continue
+		}
+		if assert.NoError(t, err) {
+			assert.Equal(t, unit.want, trailer)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"github.com/lib/pq"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 const ActorMetadataTableName = "actor_metadata"
@@ -15,6 +16,7 @@ type ActorSearchResult struct {
 	Homepage string         `json:"homepage"`
 	Aliases  pq.StringArray `json:"aliases,omitempty"`
 	Images   pq.StringArray `json:"images"`
+	Flags    []string       `json:"flags,omitempty"`
 }
 
 func (a *ActorSearchResult) Valid() bool {
@@ -22,9 +24,23 @@ func (a *ActorSearchResult) Valid() bool {
 		a.Provider != "" && a.Homepage != ""
 }
 
+// FilmographyEntry is a movie of an actor's filmography.
+type FilmographyEntry struct {
+	Number      string         `json:"number"`
+	Title       string         `json:"title"`
+	ReleaseDate datatypes.Date `json:"release_date"`
+}
+
+// ActorRef refers to an actor of the same provider.
+type ActorRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 type ActorInfo struct {
 	ID           string         `json:"id" gorm:"primaryKey"`
 	Name         string         `json:"name"`
+	OriginalName string         `json:"original_name,omitempty"`
 	Provider     string         `json:"provider" gorm:"primaryKey"`
 	Homepage     string         `json:"homepage"`
 	Summary      string         `json:"summary"`
@@ -37,9 +53,24 @@ type ActorInfo struct {
 	Height       int            `json:"height"`
 	Aliases      pq.StringArray `json:"aliases" gorm:"type:text[]"`
 	Images       pq.StringArray `json:"images" gorm:"type:text[]"`
-	Birthday     datatypes.Date `json:"birthday"`
-	DebutDate    datatypes.Date `json:"debut_date"`
-	TimeTracker  `json:"-"`
+	// ImageSources tags images merged from other sources, e.g., community
+	// avatar packs, by image URL. It's filled at query time, not stored.
+	ImageSources map[string]string `json:"image_sources,omitempty" gorm:"-"`
+	Birthday     datatypes.Date    `json:"birthday"`
+	DebutDate    datatypes.Date    `json:"debut_date"`
+	// Filmography, SocialLinks and Related are parsed by providers which
+	// list them, e.g., XsList, and reconcile actor names by the crosswalk.
+	Filmography []*FilmographyEntry `json:"filmography,omitempty" gorm:"type:text;serializer:json"`
+	SocialLinks map[string]string   `json:"social_links,omitempty" gorm:"type:text;serializer:json"` // by network
+	Related     []*ActorRef         `json:"related,omitempty" gorm:"type:text;serializer:json"`
+	// Source credits the provider of the info, see Source.
+	Source *Source `json:"source,omitempty" gorm:"type:text;serializer:json"`
+	// Flags are names of content filters matched, filled at query time.
+	Flags []string `json:"flags,omitempty" gorm:"-"`
+	// Extra holds JSON fields unknown to this version, see Extra.
+	Extra       Extra `json:"-" gorm:"type:text;serializer:json"`
+	TimeTracker `json:"-"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 func (*ActorInfo) TableName() string {
@@ -58,5 +89,6 @@ func (a *ActorInfo) ToSearchResult() *ActorSearchResult {
 		Homepage: a.Homepage,
 		Aliases:  a.Aliases,
 		Images:   a.Images,
+		Flags:    a.Flags,
 	}
 }
@@ -0,0 +1,107 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+)
+
+// Artwork types of movies.
+const (
+	// PosterArtwork is the portrait image, cropped from the cover if no
+	// big thumb.
+	PosterArtwork = "poster"
+	// BackdropArtwork is the full cover.
+	BackdropArtwork = "backdrop"
+	// LandscapeArtwork is the wide image, cropped from the cover.
+	LandscapeArtwork = "landscape"
+	// ScreenshotArtwork is a preview image of the gallery.
+	ScreenshotArtwork = "screenshot"
+)
+
+// Artwork is a labeled image of movies, so that consumers, e.g., exporters
+// and the image proxy, pick images by their roles rather than by fields.
+type Artwork struct {
+	// Type is the artwork type, e.g., `poster`.
+	Type string `json:"type"`
+	// Index is the 1-based position of screenshots in the gallery, zero
+	// for other types.
+	Index int `json:"index,omitempty"`
+	// URL is the source image.
+	URL string `json:"url"`
+	// Caption is the caption of the image given by the provider, if any.
+	Caption string `json:"caption,omitempty"`
+}
+
+// Label returns the display label of the artwork, e.g., `Screenshot 3`.
+func (a *Artwork) Label() string {
+	if a.Type == "" {
+		return ""
+	}
+	label := strings.ToUpper(a.Type[:1]) + a.Type[1:]
+	if a.Index > 0 {
+		label += " " + strconv.Itoa(a.Index)
+	}
+	return label
+}
+
+// SetImageCaption sets the caption of the image by its URL, which is called
+// by providers of captioned galleries.
+func (m *MovieInfo) SetImageCaption(url, caption string) {
+	if caption = strings.TrimSpace(caption); url == "" || caption == "" {
+		return
+	}
+	if m.ImageCaptions == nil {
+		m.ImageCaptions = make(map[string]string)
+	}
+	m.ImageCaptions[url] = caption
+}
+
+// Artworks returns the labeled images of the info, i.e., the poster, the
+// backdrop and the landscape image, followed by screenshots in the order
+// of the gallery. Placeholder images are excluded, see Source.
+func (m *MovieInfo) Artworks() []*Artwork {
+	image := func(field, url string) string {
+		if m.Source.Placeholder(field) {
+			return ""
+		}
+		return url
+	}
+	cover := image("cover_url", m.CoverURL)
+	poster, backdrop := cover, cover
+	if bigThumb := image("big_thumb_url", m.BigThumbURL); bigThumb != "" {
+		poster = bigThumb
+	}
+	if bigCover := image("big_cover_url", m.BigCoverURL); bigCover != "" {
+		backdrop = bigCover
+	}
+	artworks := make([]*Artwork, 0, 3+len(m.PreviewImages))
+	for _, a := range []struct{ typ, url string }{
+		{PosterArtwork, poster},
+		{BackdropArtwork, backdrop},
+		{LandscapeArtwork, backdrop},
+	} {
+		if a.url != "" {
+			artworks = append(artworks, &Artwork{Type: a.typ, URL: a.url, Caption: m.ImageCaptions[a.url]})
+		}
+	}
+	for i, url := range m.PreviewImages {
+		artworks = append(artworks, &Artwork{
+			Type:    ScreenshotArtwork,
+			Index:   i + 1,
+			URL:     url,
+			Caption: m.ImageCaptions[url],
+		})
+	}
+	return artworks
+}
+
+// Artwork returns the artwork of the type, and of the index if screenshots,
+// or nil if none.
+func (m *MovieInfo) Artwork(typ string, index int) *Artwork {
+	for _, a := range m.Artworks() {
+		if a.Type == typ && (typ != ScreenshotArtwork || a.Index == index) {
+			return a
+		}
+	}
+	return nil
+}
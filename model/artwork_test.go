@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArtworks(t *testing.T) {
+	info := &MovieInfo{
+		CoverURL:      "https://example.com/cover.jpg",
+		BigCoverURL:   "https://example.com/big_cover.jpg",
+		ThumbURL:      "https://example.com/thumb.jpg",
+		PreviewImages: []string{"https://example.com/1.jpg", "https://example.com/2.jpg"},
+	}
+	info.SetImageCaption("https://example.com/2.jpg", " Scene 2 ")
+	info.SetImageCaption("https://example.com/3.jpg", "")
+
+	assert.Equal(t, map[string]string{"https://example.com/2.jpg": "Scene 2"}, info.ImageCaptions)
+
+	artworks := info.Artworks()
+	if assert.Len(t, artworks, 5) {
+		assert.Equal(t, &Artwork{Type: PosterArtwork, URL: "https://example.com/cover.jpg"}, artworks[0])
+		assert.Equal(t, &Artwork{Type: BackdropArtwork, URL: "https://example.com/big_cover.jpg"}, artworks[1])
+		assert.Equal(t, &Artwork{Type: LandscapeArtwork, URL: "https://example.com/big_cover.jpg"}, artworks[2])
+		assert.Equal(t, &Artwork{Type: ScreenshotArtwork, Index: 1, URL: "https://example.com/1.jpg"}, artworks[3])
+		assert.Equal(t, &Artwork{Type: ScreenshotArtwork, Index: 2, URL: "https://example.com/2.jpg", Caption: "Scene 2"}, artworks[4])
+	}
+	assert.Equal(t, "Poster", artworks[0].Label())
+	assert.Equal(t, "Screenshot 2", artworks[4].Label())
+	assert.Empty(t, (&Artwork{}).Label())
+
+	assert.Equal(t, "https://example.com/2.jpg", info.Artwork(ScreenshotArtwork, 2).URL)
+	assert.Nil(t, info.Artwork(ScreenshotArtwork, 3))
+
+	// placeholders are excluded.
+	info.SetPlaceholder("big_cover_url")
+	assert.Equal(t, "https://example.com/cover.jpg", info.Artwork(BackdropArtwork, 0).URL)
+	info.SetPlaceholder("cover_url")
+	assert.Nil(t, info.Artwork(PosterArtwork, 0))
+	assert.Nil(t, info.Artwork(BackdropArtwork, 0))
+	assert.Len(t, info.Artworks(), 2)
+}
@@ -0,0 +1,48 @@
+package model
+
+import (
+	"time"
+)
+
+const AuditLogsTableName = "audit_logs"
+
+// Audit actions of metadata.
+const (
+	ScrapeAuditAction  = "scrape"
+	RefreshAuditAction = "refresh"
+	DeleteAuditAction  = "delete"
+	BlockAuditAction   = "block"
+	UnblockAuditAction = "unblock"
+	EditAuditAction    = "edit"
+	ClearAuditAction   = "clear"
+	MergeAuditAction   = "merge"
+	// LocalizeAuditAction is of localized infos scraped, which are never
+	// stored, with the changes from the stored infos.
+	LocalizeAuditAction = "localize"
+)
+
+// SystemAuditActor is the actor of changes made by the engine itself,
+// e.g., scheduled refreshes and override merges.
+const SystemAuditActor = "system"
+
+// AuditChange is the old and new values of a changed field.
+type AuditChange struct {
+	Old any `json:"old"`
+	New any `json:"new"`
+}
+
+// AuditLog records a change of stored metadata.
+type AuditLog struct {
+	Seq       uint                    `json:"seq" gorm:"primaryKey;autoIncrement"`
+	Action    string                  `json:"action" gorm:"index"`
+	Actor     string                  `json:"actor" gorm:"index"`
+	Kind      string                  `json:"kind"`
+	Provider  string                  `json:"provider" gorm:"index:idx_audit_target"`
+	ID        string                  `json:"id" gorm:"index:idx_audit_target"`
+	Diff      map[string]*AuditChange `json:"diff,omitempty" gorm:"type:text;serializer:json"`
+	CreatedAt time.Time               `json:"created_at" gorm:"index"`
+}
+
+func (*AuditLog) TableName() string {
+	return AuditLogsTableName
+}
@@ -0,0 +1,21 @@
+package model
+
+const BlocklistTableName = "blocklist"
+
+// BlocklistEntry forbids metadata matched from being stored, an empty
+// provider matches all providers and an empty number matches all movies
+// and actors of the provider.
+type BlocklistEntry struct {
+	Provider    string `json:"provider" gorm:"primaryKey"`
+	Number      string `json:"number" gorm:"primaryKey"`
+	Reason      string `json:"reason"`
+	TimeTracker `json:"-"`
+}
+
+func (*BlocklistEntry) TableName() string {
+	return BlocklistTableName
+}
+
+func (b *BlocklistEntry) Valid() bool {
+	return b.Provider != "" || b.Number != ""
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// Dates, e.g., release dates and birthdays, are calendar dates without
+// time zones, which are normalized to midnight in UTC. Otherwise, dates
+// parsed in local time zones are shifted by a day when converted across
+// time zones, e.g., `2024-01-02T00:00:00+09:00` is `2024-01-01` in UTC.
+
+// DateOf returns the calendar date of the time in its own location, at
+// midnight in UTC. Zero times are kept as is.
+func DateOf(t time.Time) datatypes.Date {
+	if t.IsZero() {
+		return datatypes.Date{}
+	}
+	y, m, d := t.Date()
+	return datatypes.Date(time.Date(y, m, d, 0, 0, 0, 0, time.UTC))
+}
+
+// NormalizeDate normalizes the date to midnight in UTC, see DateOf.
+func NormalizeDate(date datatypes.Date) datatypes.Date {
+	return DateOf(time.Time(date))
+}
+
+func normalizeDates(dates ...*datatypes.Date) {
+	for _, date := range dates {
+		*date = NormalizeDate(*date)
+	}
+}
+
+// NormalizeDates normalizes dates of the movie info, which is called
+// before saved and after loaded, since rows may have been stored with
+// local time zones.
+func (m *MovieInfo) NormalizeDates() {
+	normalizeDates(&m.ReleaseDate)
+}
+
+func (m *MovieInfo) BeforeSave(*gorm.DB) error {
+	m.NormalizeDates()
+	return nil
+}
+
+func (m *MovieInfo) AfterFind(*gorm.DB) error {
+	m.NormalizeDates()
+	return nil
+}
+
+// NormalizeDates normalizes dates of the reviews, see MovieInfo.
+func (m *MovieReviewInfo) NormalizeDates() {
+	for _, review := range m.Reviews.Data() {
+		if review != nil {
+			normalizeDates(&review.Date)
+		}
+	}
+}
+
+func (m *MovieReviewInfo) BeforeSave(*gorm.DB) error {
+	m.NormalizeDates()
+	return nil
+}
+
+func (m *MovieReviewInfo) AfterFind(*gorm.DB) error {
+	m.NormalizeDates()
+	return nil
+}
+
+// NormalizeDates normalizes dates of the actor info and filmography,
+// see MovieInfo.
+func (a *ActorInfo) NormalizeDates() {
+	normalizeDates(&a.Birthday, &a.DebutDate)
+	for _, entry := range a.Filmography {
+		if entry != nil {
+			normalizeDates(&entry.ReleaseDate)
+		}
+	}
+}
+
+func (a *ActorInfo) BeforeSave(*gorm.DB) error {
+	a.NormalizeDates()
+	return nil
+}
+
+func (a *ActorInfo) AfterFind(*gorm.DB) error {
+	a.NormalizeDates()
+	return nil
+}
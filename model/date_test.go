@@ -0,0 +1,57 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestDateOf(t *testing.T) {
+	jst := time.FixedZone("JST", 9*60*60)
+	pst := time.FixedZone("PST", -8*60*60)
+	want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+	for _, unit := range []time.Time{
+		time.Date(2024, 1, 2, 0, 0, 0, 0, jst),
+		time.Date(2024, 1, 2, 23, 59, 59, 0, pst),
+		time.Date(2024, 1, 2, 12, 0, 0, 0, time.UTC),
+	} {
+		assert.Equal(t, want, time.Time(DateOf(unit)), unit.String())
+	}
+	assert.True(t, time.Time(DateOf(time.Time{})).IsZero())
+}
+
+func TestNormalizeDates(t *testing.T) {
+	db, err := gorm.Open(sqlite.Open(":memory:"), &gorm.Config{Logger: logger.Discard})
+	require.NoError(t, err)
+	require.NoError(t, db.AutoMigrate(&MovieInfo{}, &ActorInfo{}))
+
+	jst := time.FixedZone("JST", 9*60*60)
+	date := datatypes.Date(time.Date(2024, 1, 2, 0, 0, 0, 0, jst))
+	want := time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC)
+
+	movie := &MovieInfo{ID: "1", Provider: "TEST", ReleaseDate: date}
+	require.NoError(t, db.Create(movie).Error)
+	assert.Equal(t, want, time.Time(movie.ReleaseDate), "normalized before saved")
+
+	// rows stored before are normalized after loaded.
+	require.NoError(t, db.Model(movie).UpdateColumn("release_date", date).Error)
+	loaded := &MovieInfo{}
+	require.NoError(t, db.First(loaded).Error)
+	assert.Equal(t, want, time.Time(loaded.ReleaseDate))
+
+	actor := &ActorInfo{
+		ID: "1", Provider: "TEST", Birthday: date,
+		Filmography: []*FilmographyEntry{{Number: "ABC-123", ReleaseDate: date}},
+	}
+	require.NoError(t, db.Create(actor).Error)
+	loadedActor := &ActorInfo{}
+	require.NoError(t, db.First(loadedActor).Error)
+	assert.Equal(t, want, time.Time(loadedActor.Birthday))
+	assert.Equal(t, want, time.Time(loadedActor.Filmography[0].ReleaseDate))
+}
@@ -0,0 +1,91 @@
+package model
+
+import (
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+)
+
+// FieldChange is a change of an info field by its JSON name, and for
+// array fields, the elements added and removed are also listed.
+type FieldChange struct {
+	Field   string   `json:"field"`
+	Old     any      `json:"old"`
+	New     any      `json:"new"`
+	Added   []string `json:"added,omitempty"`
+	Removed []string `json:"removed,omitempty"`
+}
+
+// DiffMovieInfo returns changes of metadata fields from old to new in
+// the field order, identity and bookkeeping fields are not compared.
+func DiffMovieInfo(old, new *MovieInfo) []*FieldChange {
+	if old == nil {
+		old = &MovieInfo{}
+	}
+	if new == nil {
+		new = &MovieInfo{}
+	}
+	return diffFields(reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem())
+}
+
+func diffFields(ov, nv reflect.Value) (changes []*FieldChange) {
+	t := ov.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if f.Anonymous || !f.IsExported() || name == "-" ||
+			name == "id" || name == "provider" || name == "source" ||
+			f.Tag.Get("gorm") == "-" /* not stored */ {
+			continue
+		}
+		o, n := ov.Field(i).Interface(), nv.Field(i).Interface()
+		if equalField(o, n) {
+			continue
+		}
+		change := &FieldChange{Field: name, Old: o, New: n}
+		if oa, ok := o.(pq.StringArray); ok {
+			change.Added, change.Removed = diffStrings(oa, n.(pq.StringArray))
+		}
+		changes = append(changes, change)
+	}
+	return
+}
+
+func equalField(o, n any) bool {
+	switch o := o.(type) {
+	case pq.StringArray:
+		return slices.Equal(o, n.(pq.StringArray))
+	case datatypes.Date:
+		// dates are compared by days, regardless of time zones.
+		return time.Time(o).Format(time.DateOnly) == time.Time(n.(datatypes.Date)).Format(time.DateOnly)
+	}
+	if ov, nv := reflect.ValueOf(o), reflect.ValueOf(n); ov.Kind() == reflect.Map &&
+		ov.Len() == 0 && nv.Len() == 0 {
+		return true // nil and empty maps are the same.
+	}
+	return reflect.DeepEqual(o, n)
+}
+
+func diffStrings(old, new []string) (added, removed []string) {
+	oldSet := make(map[string]struct{}, len(old))
+	for _, s := range old {
+		oldSet[s] = struct{}{}
+	}
+	newSet := make(map[string]struct{}, len(new))
+	for _, s := range new {
+		newSet[s] = struct{}{}
+		if _, ok := oldSet[s]; !ok {
+			added = append(added, s)
+		}
+	}
+	for _, s := range old {
+		if _, ok := newSet[s]; !ok {
+			removed = append(removed, s)
+		}
+	}
+	return
+}
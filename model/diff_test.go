@@ -0,0 +1,41 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"gorm.io/datatypes"
+)
+
+func TestDiffMovieInfo(t *testing.T) {
+	date := datatypes.Date(time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC))
+	old := &MovieInfo{
+		ID:          "abc-1",
+		Provider:    "A",
+		Title:       "title",
+		Actors:      []string{"a", "b"},
+		ReleaseDate: date,
+	}
+	new := &MovieInfo{
+		ID:            "abc-1",
+		Provider:      "B",
+		Title:         "new title",
+		Actors:        []string{"b", "c"},
+		PreviewImages: []string{},
+		ReleaseDate:   datatypes.Date(time.Date(2024, 1, 2, 9, 0, 0, 0, time.FixedZone("JST", 9*3600))),
+	}
+
+	changes := DiffMovieInfo(old, new)
+	if assert.Len(t, changes, 2) {
+		assert.Equal(t, "title", changes[0].Field)
+		assert.Equal(t, "title", changes[0].Old)
+		assert.Equal(t, "new title", changes[0].New)
+		assert.Equal(t, "actors", changes[1].Field)
+		assert.Equal(t, []string{"c"}, changes[1].Added)
+		assert.Equal(t, []string{"a"}, changes[1].Removed)
+	}
+
+	assert.Empty(t, DiffMovieInfo(old, old))
+	assert.Len(t, DiffMovieInfo(nil, &MovieInfo{Title: "title"}), 1)
+}
@@ -0,0 +1,42 @@
+package model
+
+import "slices"
+
+// Editions of movies, so that media managers group editions of the same
+// number under one title.
+const (
+	FourKEdition           = "4k"
+	VREdition              = "vr"
+	RemasteredEdition      = "remastered"
+	DirectorsCutEdition    = "directors_cut"
+	BehindTheScenesEdition = "behind_the_scenes"
+)
+
+var editionLabels = map[string]string{
+	FourKEdition:           "4K",
+	VREdition:              "VR",
+	RemasteredEdition:      "Remastered",
+	DirectorsCutEdition:    "Director's Cut",
+	BehindTheScenesEdition: "Behind the Scenes",
+}
+
+// EditionLabel returns the display label of the edition, e.g., `4K`, or
+// the edition itself if unknown.
+func EditionLabel(edition string) string {
+	if label, ok := editionLabels[edition]; ok {
+		return label
+	}
+	return edition
+}
+
+// SetEdition adds the edition to the info if not yet.
+func (m *MovieInfo) SetEdition(edition string) {
+	if edition != "" && !m.HasEdition(edition) {
+		m.Editions = append(m.Editions, edition)
+	}
+}
+
+// HasEdition reports whether the info is of the edition.
+func (m *MovieInfo) HasEdition(edition string) bool {
+	return slices.Contains(m.Editions, edition)
+}
@@ -0,0 +1,208 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Extra holds JSON fields unknown to this version of the model, e.g.,
+// written by newer servers. They're kept by decoding, written back by
+// encoding and stored in DB, so that round trips through older versions
+// lose nothing.
+//
+// Providers surface site-specific data, e.g., campaign prices, as extra
+// fields too, by the typed accessors below, without struct changes.
+type Extra map[string]json.RawMessage
+
+// Set sets the field to the JSON encoding of the value, the map is made
+// if nil.
+func (e *Extra) Set(key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if *e == nil {
+		*e = make(Extra)
+	}
+	(*e)[key] = data
+	return nil
+}
+
+// Get decodes the field into v, and reports whether it's present and
+// decoded.
+func (e Extra) Get(key string, v any) bool {
+	data, ok := e[key]
+	return ok && json.Unmarshal(data, v) == nil
+}
+
+// String returns the field of the string, empty if missing or mistyped.
+func (e Extra) String(key string) (s string) {
+	e.Get(key, &s)
+	return
+}
+
+// Int returns the field of the integer, zero if missing or mistyped.
+func (e Extra) Int(key string) (i int64) {
+	e.Get(key, &i)
+	return
+}
+
+// Float returns the field of the number, zero if missing or mistyped.
+func (e Extra) Float(key string) (f float64) {
+	e.Get(key, &f)
+	return
+}
+
+// Bool returns the field of the boolean, false if missing or mistyped.
+func (e Extra) Bool(key string) (b bool) {
+	e.Get(key, &b)
+	return
+}
+
+// Keys returns the names of fields in order.
+func (e Extra) Keys() []string {
+	keys := make([]string, 0, len(e))
+	for key := range e {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+var (
+	movieInfoFields = sync.OnceValue(func() map[string]struct{} { return jsonFieldSet(reflect.TypeFor[MovieInfo]()) })
+	actorInfoFields = sync.OnceValue(func() map[string]struct{} { return jsonFieldSet(reflect.TypeFor[ActorInfo]()) })
+)
+
+func (m *MovieInfo) UnmarshalJSON(data []byte) (err error) {
+	type plain MovieInfo
+	if err = json.Unmarshal(data, (*plain)(m)); err != nil {
+		return
+	}
+	m.Extra, err = decodeExtra(data, movieInfoFields())
+	return
+}
+
+func (m MovieInfo) MarshalJSON() ([]byte, error) {
+	type plain MovieInfo
+	return encodeExtra(plain(m), m.Extra, movieInfoFields())
+}
+
+func (a *ActorInfo) UnmarshalJSON(data []byte) (err error) {
+	type plain ActorInfo
+	if err = json.Unmarshal(data, (*plain)(a)); err != nil {
+		return
+	}
+	a.Extra, err = decodeExtra(data, actorInfoFields())
+	return
+}
+
+func (a ActorInfo) MarshalJSON() ([]byte, error) {
+	type plain ActorInfo
+	return encodeExtra(plain(a), a.Extra, actorInfoFields())
+}
+
+// decodeExtra returns the fields of the JSON object which are not known,
+// nil if none. Known names are matched case-insensitively, as they're
+// decoded by encoding/json.
+func decodeExtra(data []byte, known map[string]struct{}) (Extra, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+	var extra Extra
+	for name, value := range fields {
+		if _, ok := known[strings.ToLower(name)]; ok {
+			continue
+		}
+		if extra == nil {
+			extra = make(Extra)
+		}
+		extra[name] = value
+	}
+	return extra, nil
+}
+
+// encodeExtra encodes v, and then appends the extra fields in order of
+// names, fields known are never overwritten by extra ones.
+func encodeExtra(v any, extra Extra, known map[string]struct{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil || len(extra) == 0 {
+		return data, err
+	}
+	names := make([]string, 0, len(extra))
+	for name := range extra {
+		if _, ok := known[strings.ToLower(name)]; !ok {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+
+	buf := bytes.NewBuffer(data[:len(data)-1]) // trim `}`.
+	for i, name := range names {
+		if i > 0 || len(data) > 2 /* not `{}` */ {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(name)
+		buf.Write(key)
+		buf.WriteByte(':')
+		if err = json.Compact(buf, extra[name]); err != nil {
+			return nil, err
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// jsonFieldSet returns the lower-case JSON names of fields of the struct,
+// including those of embedded structs.
+func jsonFieldSet(t reflect.Type) map[string]struct{} {
+	set := make(map[string]struct{})
+	for _, f := range jsonFields(t) {
+		set[strings.ToLower(f.name)] = struct{}{}
+	}
+	return set
+}
+
+type jsonField struct {
+	name      string
+	omitEmpty bool
+	field     reflect.StructField
+}
+
+// jsonFields returns the fields of the struct encoded by encoding/json,
+// in the order of declarations. Indexes of fields of embedded structs are
+// relative to the struct.
+func jsonFields(t reflect.Type) (fields []jsonField) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct {
+			for _, sub := range jsonFields(f.Type) {
+				sub.field.Index = append([]int{i}, sub.field.Index...)
+				fields = append(fields, sub)
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fields = append(fields, jsonField{
+			name:      name,
+			omitEmpty: strings.Contains(opts, "omitempty"),
+			field:     f,
+		})
+	}
+	return
+}
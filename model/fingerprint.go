@@ -0,0 +1,54 @@
+package model
+
+const MovieFingerprintsTableName = "movie_fingerprints"
+
+// Fingerprint algorithms of movie files.
+const (
+	OSHashAlgorithm = "OSHASH"
+	PHashAlgorithm  = "PHASH"
+	MD5Algorithm    = "MD5"
+)
+
+// HashBuckets are the 16-bit bands of a 64-bit perceptual hash, which are
+// indexed to look up similar hashes without scanning all of them.
+type HashBuckets struct {
+	Bucket0 int `json:"-" gorm:"index"`
+	Bucket1 int `json:"-" gorm:"index"`
+	Bucket2 int `json:"-" gorm:"index"`
+	Bucket3 int `json:"-" gorm:"index"`
+}
+
+// NewHashBuckets splits the hash into buckets from the high bits.
+func NewHashBuckets(hash uint64) HashBuckets {
+	return HashBuckets{
+		Bucket0: int(hash >> 48 & 0xffff),
+		Bucket1: int(hash >> 32 & 0xffff),
+		Bucket2: int(hash >> 16 & 0xffff),
+		Bucket3: int(hash & 0xffff),
+	}
+}
+
+// Values returns the buckets in order.
+func (b HashBuckets) Values() [4]int {
+	return [4]int{b.Bucket0, b.Bucket1, b.Bucket2, b.Bucket3}
+}
+
+// MovieFingerprint associates a file fingerprint with a movie.
+type MovieFingerprint struct {
+	Algorithm string `json:"algorithm" gorm:"primaryKey"`
+	Hash      string `json:"hash" gorm:"primaryKey"`
+	Provider  string `json:"provider" gorm:"primaryKey"`
+	ID        string `json:"id" gorm:"primaryKey"`
+	Duration  int    `json:"duration"` // in seconds
+	// HashBuckets of PHASH fingerprints, zeros of others.
+	HashBuckets `gorm:"embedded"`
+	TimeTracker `json:"-"`
+}
+
+func (*MovieFingerprint) TableName() string {
+	return MovieFingerprintsTableName
+}
+
+func (m *MovieFingerprint) Valid() bool {
+	return m.Algorithm != "" && m.Hash != "" && m.Provider != "" && m.ID != ""
+}
@@ -0,0 +1,18 @@
+package model
+
+const MovieImageHashesTableName = "movie_image_hashes"
+
+// MovieImageHash is the perception hash of a movie image, which is
+// used as the index of reverse image search.
+type MovieImageHash struct {
+	Provider    string `json:"provider" gorm:"primaryKey"`
+	ID          string `json:"id" gorm:"primaryKey"`
+	URL         string `json:"url" gorm:"primaryKey"`
+	Hash        int64  `json:"hash" gorm:"index"` // bits of uint64 hash
+	HashBuckets `gorm:"embedded"`
+	TimeTracker `json:"-"`
+}
+
+func (*MovieImageHash) TableName() string {
+	return MovieImageHashesTableName
+}
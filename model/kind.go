@@ -0,0 +1,8 @@
+package model
+
+// Kinds of stored records.
+const (
+	MovieKind     = "movie"
+	ActorKind     = "actor"
+	BlocklistKind = "blocklist"
+)
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"reflect"
+
+	"github.com/lib/pq"
+)
+
+// MergeMovieInfo merges the provider data changes from base to theirs
+// into ours, which is base with manual edits, and returns the merged.
+// Edited fields are kept unless they're arrays, for which elements added
+// and removed by the provider are also applied, e.g., new preview images.
+func MergeMovieInfo(base, ours, theirs *MovieInfo) *MovieInfo {
+	merged := *ours
+	bv := reflect.ValueOf(base).Elem()
+	ov := reflect.ValueOf(ours).Elem()
+	tv := reflect.ValueOf(theirs).Elem()
+	mv := reflect.ValueOf(&merged).Elem()
+	for i := 0; i < mv.NumField(); i++ {
+		if f := mv.Type().Field(i); f.Anonymous || !f.IsExported() {
+			continue
+		}
+		b, o, t := bv.Field(i).Interface(), ov.Field(i).Interface(), tv.Field(i).Interface()
+		switch {
+		case equalField(b, t): // unchanged by provider.
+		case equalField(b, o): // unchanged by user.
+			mv.Field(i).Set(tv.Field(i))
+		default: // changed by both.
+			if ba, ok := b.(pq.StringArray); ok {
+				mv.Field(i).Set(reflect.ValueOf(merge3Strings(ba, o.(pq.StringArray), t.(pq.StringArray))))
+			}
+		}
+	}
+	return &merged
+}
+
+// merge3Strings applies elements added and removed from base to theirs
+// onto ours, in the order of ours followed by additions.
+func merge3Strings(base, ours, theirs pq.StringArray) pq.StringArray {
+	added, removed := diffStrings(base, theirs)
+	drop := make(map[string]struct{}, len(removed))
+	for _, s := range removed {
+		drop[s] = struct{}{}
+	}
+	merged := make(pq.StringArray, 0, len(ours)+len(added))
+	seen := make(map[string]struct{}, len(ours)+len(added))
+	for _, s := range append(append([]string{}, ours...), added...) {
+		if _, ok := drop[s]; ok {
+			continue
+		}
+		if _, ok := seen[s]; ok {
+			continue
+		}
+		seen[s] = struct{}{}
+		merged = append(merged, s)
+	}
+	return merged
+}
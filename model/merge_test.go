@@ -0,0 +1,35 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeMovieInfo(t *testing.T) {
+	base := &MovieInfo{
+		Title:         "title",
+		Summary:       "summary",
+		Actors:        pq.StringArray{"a", "wrong"},
+		PreviewImages: pq.StringArray{"1", "2"},
+	}
+	ours := &MovieInfo{
+		Title:         "fixed title",
+		Summary:       "summary",
+		Actors:        pq.StringArray{"a"},
+		PreviewImages: pq.StringArray{"1", "2"},
+	}
+	theirs := &MovieInfo{
+		Title:         "upstream title",
+		Summary:       "new summary",
+		Actors:        pq.StringArray{"a", "wrong", "b"},
+		PreviewImages: pq.StringArray{"1", "2", "3"},
+	}
+
+	merged := MergeMovieInfo(base, ours, theirs)
+	assert.Equal(t, "fixed title", merged.Title)
+	assert.Equal(t, "new summary", merged.Summary)
+	assert.Equal(t, pq.StringArray{"a", "b"}, merged.Actors)
+	assert.Equal(t, pq.StringArray{"1", "2", "3"}, merged.PreviewImages)
+}
@@ -3,6 +3,7 @@ package model
 import (
 	"github.com/lib/pq"
 	"gorm.io/datatypes"
+	"gorm.io/gorm"
 )
 
 const (
@@ -22,6 +23,7 @@ type MovieSearchResult struct {
 	Score       float64        `json:"score"`
 	Actors      pq.StringArray `json:"actors,omitempty"`
 	ReleaseDate datatypes.Date `json:"release_date"`
+	Flags       []string       `json:"flags,omitempty"`
 }
 
 func (m *MovieSearchResult) Valid() bool {
@@ -91,7 +93,33 @@ type MovieInfo struct {
 	Runtime     int            `json:"runtime"`
 	ReleaseDate datatypes.Date `json:"release_date"`
 
+	// Editions are variants of the movie, e.g., `4k` and `vr`, which are
+	// detected from provider pages and number suffixes, see Edition.
+	Editions pq.StringArray `json:"editions,omitempty" gorm:"type:text[]"`
+	// BaseNumber is the number without edition suffixes, e.g., `ABC-123`
+	// of `ABC-123-4K`, which editions share. It's empty if no suffixes.
+	BaseNumber string `json:"base_number,omitempty"`
+	// VR is the VR-specific metadata of VR movies, see VRInfo.
+	VR *VRInfo `json:"vr,omitempty" gorm:"type:text;serializer:json"`
+
+	// FieldLanguages records languages of text fields by JSON names,
+	// which is only set by multilingual providers.
+	FieldLanguages map[string]string `json:"field_languages,omitempty" gorm:"type:text;serializer:json"`
+	// ImageCaptions are captions of images by URLs, which are only set by
+	// providers of captioned galleries.
+	ImageCaptions map[string]string `json:"image_captions,omitempty" gorm:"type:text;serializer:json"`
+	// Source credits the provider of the info, see Source.
+	Source *Source `json:"source,omitempty" gorm:"type:text;serializer:json"`
+	// Flags are names of content filters matched, which are filled at
+	// query time, not stored.
+	Flags []string `json:"flags,omitempty" gorm:"-"`
+	// ContentRating is the configured rating of API payloads, not stored.
+	ContentRating string `json:"content_rating,omitempty" gorm:"-"`
+	// Extra holds JSON fields unknown to this version, see Extra.
+	Extra Extra `json:"-" gorm:"type:text;serializer:json"`
+
 	TimeTracker `json:"-"`
+	DeletedAt   gorm.DeletedAt `json:"-" gorm:"index"`
 }
 
 func (*MovieInfo) TableName() string {
@@ -115,5 +143,6 @@ func (m *MovieInfo) ToSearchResult() *MovieSearchResult {
 		Score:       m.Score,
 		Actors:      m.Actors,
 		ReleaseDate: m.ReleaseDate,
+		Flags:       m.Flags,
 	}
 }
@@ -0,0 +1,50 @@
+package model
+
+import (
+	"encoding/json"
+)
+
+const OverridesTableName = "metadata_overrides"
+
+// Override holds manual edits of a stored info by JSON field names, which
+// are locked and applied on top of provider data every time it's read,
+// so that corrections persist across automatic refreshes. Edited arrays
+// of movies are rebased on refreshes, see MergeMovieInfo.
+type Override struct {
+	Kind        string                     `json:"kind" gorm:"primaryKey"`
+	Provider    string                     `json:"provider" gorm:"primaryKey"`
+	ID          string                     `json:"id" gorm:"primaryKey"`
+	Fields      map[string]json.RawMessage `json:"fields" gorm:"type:text;serializer:json"`
+	TimeTracker `json:"-"`
+}
+
+func (*Override) TableName() string {
+	return OverridesTableName
+}
+
+func (o *Override) Valid() bool {
+	return o.Kind != "" && o.Provider != "" && o.ID != "" && len(o.Fields) > 0
+}
+
+// Apply overlays the override fields onto the info in place, v must be a
+// pointer to the info struct.
+func (o *Override) Apply(v any) error {
+	if o == nil || len(o.Fields) == 0 {
+		return nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err = json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	for k, f := range o.Fields {
+		fields[k] = f
+	}
+	if data, err = json.Marshal(fields); err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
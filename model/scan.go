@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+)
+
+const ScanStatesTableName = "scan_states"
+
+// ScanState is the state of a library file when last scanned, so that
+// later scans only process new or changed files.
+type ScanState struct {
+	Path    string    `json:"path" gorm:"primaryKey"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+	OSHash  string    `json:"oshash"`
+	// Number, Provider and ID are of the movie matched.
+	Number      string `json:"number"`
+	Provider    string `json:"provider"`
+	ID          string `json:"id"`
+	TimeTracker `json:"-"`
+}
+
+func (*ScanState) TableName() string {
+	return ScanStatesTableName
+}
+
+// Matched reports whether the file has been matched with a movie.
+func (s *ScanState) Matched() bool {
+	return s.Provider != "" && s.ID != ""
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+)
+
+const ScheduledTasksTableName = "scheduled_tasks"
+
+// ScheduledTask is the last run of a periodic task, which is claimed by
+// only one of the server instances sharing the DB.
+type ScheduledTask struct {
+	Name        string    `json:"name" gorm:"primaryKey"`
+	RunAt       time.Time `json:"run_at"`
+	TimeTracker `json:"-"`
+}
+
+func (*ScheduledTask) TableName() string {
+	return ScheduledTasksTableName
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+)
+
+// SchemaVersion is the version of the JSON encoding of models, which is
+// bumped on breaking changes only, e.g., fields renamed or retyped. New
+// fields are compatible, since unknown fields are preserved, see Extra.
+const SchemaVersion = 1
+
+// schemaTypes are the models published in the JSON schema.
+var schemaTypes = []reflect.Type{
+	reflect.TypeFor[MovieInfo](),
+	reflect.TypeFor[MovieSearchResult](),
+	reflect.TypeFor[MovieReviewDetail](),
+	reflect.TypeFor[ActorInfo](),
+	reflect.TypeFor[ActorSearchResult](),
+}
+
+// JSONSchema returns the JSON Schema (draft 2020-12) of the models, which
+// are defined by type names in `$defs`. Objects allow additional
+// properties, so that consumers are compatible with newer versions.
+func JSONSchema() ([]byte, error) {
+	defs := make(map[string]any)
+	for _, t := range schemaTypes {
+		schemaOf(t, defs)
+	}
+	return json.MarshalIndent(map[string]any{
+		"$schema":          "https://json-schema.org/draft/2020-12/schema",
+		"title":            "MetaTube models",
+		"x-schema-version": SchemaVersion,
+		"$defs":            defs,
+	}, "", "  ")
+}
+
+var (
+	timeType        = reflect.TypeFor[time.Time]()
+	dateType        = reflect.TypeFor[datatypes.Date]()
+	rawType         = reflect.TypeFor[json.RawMessage]()
+	stringArrayType = reflect.TypeFor[pq.StringArray]()
+)
+
+// schemaOf returns the schema of the type, named structs are defined in
+// defs and referred to.
+func schemaOf(t reflect.Type, defs map[string]any) map[string]any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	switch t {
+	case timeType:
+		return map[string]any{"type": "string", "format": "date-time"}
+	case dateType:
+		return map[string]any{
+			"type":        "string",
+			"format":      "date-time",
+			"description": "calendar date at midnight in UTC",
+		}
+	case rawType:
+		return map[string]any{}
+	case stringArrayType:
+		return map[string]any{"type": "array", "items": map[string]any{"type": "string"}}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return map[string]any{"type": "string"}
+	case reflect.Bool:
+		return map[string]any{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]any{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]any{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return map[string]any{"type": "array", "items": schemaOf(t.Elem(), defs)}
+	case reflect.Map:
+		return map[string]any{"type": "object", "additionalProperties": schemaOf(t.Elem(), defs)}
+	case reflect.Struct:
+		ref := map[string]any{"$ref": "#/$defs/" + t.Name()}
+		if _, ok := defs[t.Name()]; ok {
+			return ref
+		}
+		defs[t.Name()] = nil // placeholder of recursive types.
+		properties := make(map[string]any)
+		required := make([]string, 0)
+		for _, f := range jsonFields(t) {
+			properties[f.name] = schemaOf(f.field.Type, defs)
+			if !f.omitEmpty {
+				required = append(required, f.name)
+			}
+		}
+		defs[t.Name()] = map[string]any{
+			"type":       "object",
+			"properties": properties,
+			"required":   required,
+		}
+		return ref
+	}
+	return map[string]any{}
+}
@@ -0,0 +1,103 @@
+package model
+
+import (
+	"encoding/json"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var updateSchema = flag.Bool("update", false, "update schema.json")
+
+// TestJSONSchema keeps the published schema.json up to date, run with
+// `-update` after models are changed.
+func TestJSONSchema(t *testing.T) {
+	data, err := JSONSchema()
+	require.NoError(t, err)
+	data = append(data, '\n')
+	if *updateSchema {
+		require.NoError(t, os.WriteFile("schema.json", data, 0o644))
+	}
+	published, err := os.ReadFile("schema.json")
+	require.NoError(t, err)
+	assert.Equal(t, string(published), string(data), "schema.json is outdated, run `make schema`")
+
+	var schema struct {
+		Defs map[string]struct {
+			Properties map[string]any `json:"properties"`
+			Required   []string       `json:"required"`
+		} `json:"$defs"`
+	}
+	require.NoError(t, json.Unmarshal(data, &schema))
+	movie := schema.Defs["MovieInfo"]
+	assert.Contains(t, movie.Properties, "release_date")
+	assert.Contains(t, movie.Required, "title")
+	assert.NotContains(t, movie.Required, "flags")
+	assert.NotContains(t, movie.Properties, "Extra")
+	assert.Contains(t, schema.Defs, "FilmographyEntry")
+}
+
+func TestExtra(t *testing.T) {
+	data := []byte(`{"id":"1","title":"Title","Provider":"TEST","new_field":{"a":[1, 2]},"another":"x"}`)
+
+	info := &MovieInfo{}
+	require.NoError(t, json.Unmarshal(data, info))
+	assert.Equal(t, "Title", info.Title)
+	assert.Equal(t, "TEST", info.Provider, "known fields are matched case-insensitively")
+	assert.Equal(t, Extra{
+		"new_field": json.RawMessage(`{"a":[1, 2]}`),
+		"another":   json.RawMessage(`"x"`),
+	}, info.Extra)
+
+	out, err := json.Marshal(info)
+	require.NoError(t, err)
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(out, &fields))
+	assert.JSONEq(t, `{"a":[1,2]}`, string(fields["new_field"]))
+	assert.JSONEq(t, `"x"`, string(fields["another"]))
+	assert.JSONEq(t, `"Title"`, string(fields["title"]))
+
+	// extra fields never overwrite known ones.
+	info.Extra["title"] = json.RawMessage(`"Other"`)
+	out, err = json.Marshal(info)
+	require.NoError(t, err)
+	decoded := &MovieInfo{}
+	require.NoError(t, json.Unmarshal(out, decoded))
+	assert.Equal(t, "Title", decoded.Title)
+
+	// no extra fields.
+	actor := &ActorInfo{}
+	require.NoError(t, json.Unmarshal([]byte(`{"id":"1","name":"Name"}`), actor))
+	assert.Nil(t, actor.Extra)
+	out, err = json.Marshal(actor)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"name":"Name"`)
+}
+
+func TestExtra_Accessors(t *testing.T) {
+	info := &MovieInfo{}
+	require.NoError(t, info.Extra.Set("price", 1980))
+	require.NoError(t, info.Extra.Set("rate", 4.5))
+	require.NoError(t, info.Extra.Set("currency", "JPY"))
+	require.NoError(t, info.Extra.Set("sale", true))
+
+	assert.Equal(t, int64(1980), info.Extra.Int("price"))
+	assert.Equal(t, 4.5, info.Extra.Float("rate"))
+	assert.Equal(t, "JPY", info.Extra.String("currency"))
+	assert.True(t, info.Extra.Bool("sale"))
+	assert.Equal(t, []string{"currency", "price", "rate", "sale"}, info.Extra.Keys())
+
+	// missing or mistyped.
+	assert.Equal(t, "", info.Extra.String("price"))
+	assert.Equal(t, int64(0), info.Extra.Int("missing"))
+	var v []int
+	assert.False(t, info.Extra.Get("currency", &v))
+
+	out, err := json.Marshal(info)
+	require.NoError(t, err)
+	assert.Contains(t, string(out), `"currency":"JPY"`)
+	assert.Empty(t, Extra(nil).Keys())
+}
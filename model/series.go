@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/lib/pq"
+)
+
+const SeriesTableName = "series"
+
+// Series is a tracked movie series of a provider, whose known entries are
+// watched for new releases.
+type Series struct {
+	Name     string `json:"name" gorm:"primaryKey"`
+	Provider string `json:"provider" gorm:"primaryKey"`
+	// Entries are numbers of the known movies in the series.
+	Entries     pq.StringArray `json:"entries" gorm:"type:text[]"`
+	CheckedAt   time.Time      `json:"checked_at"`
+	TimeTracker `json:"-"`
+}
+
+func (*Series) TableName() string {
+	return SeriesTableName
+}
+
+func (s *Series) Valid() bool {
+	return s.Name != "" && s.Provider != ""
+}
+
+// SeriesStatus is the library status of a series, the entries owned are
+// the ones matched with scanned files.
+type SeriesStatus struct {
+	*Series
+	Owned   []string `json:"owned"`
+	Missing []string `json:"missing"`
+}
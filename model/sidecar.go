@@ -0,0 +1,306 @@
+package model
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+	"gorm.io/datatypes"
+)
+
+// SidecarFormat is the format of metadata files kept beside videos, which
+// are edited by hand, e.g., YAML and TOML.
+type SidecarFormat string
+
+const (
+	SidecarJSON SidecarFormat = "json"
+	SidecarYAML SidecarFormat = "yaml"
+	SidecarTOML SidecarFormat = "toml"
+)
+
+// ParseSidecarFormat parses the format by its name or file extension.
+func ParseSidecarFormat(s string) (SidecarFormat, error) {
+	switch strings.ToLower(strings.TrimPrefix(s, ".")) {
+	case "json":
+		return SidecarJSON, nil
+	case "yaml", "yml":
+		return SidecarYAML, nil
+	case "toml":
+		return SidecarTOML, nil
+	}
+	return "", fmt.Errorf("unsupported sidecar format: %s", s)
+}
+
+// Ext returns the file extension of the format.
+func (f SidecarFormat) Ext() string { return "." + string(f) }
+
+// MarshalSidecar encodes the info, e.g., *MovieInfo or *ActorInfo, in the
+// format. Fields are named as JSON ones and kept in the declaration order,
+// followed by extra fields. Dates are written as `YYYY-MM-DD`, and zero
+// dates are omitted.
+func MarshalSidecar(v any, format SidecarFormat) ([]byte, error) {
+	switch format {
+	case SidecarJSON:
+		return json.MarshalIndent(v, "", "  ")
+	case SidecarYAML:
+		buf := &bytes.Buffer{}
+		enc := yaml.NewEncoder(buf)
+		enc.SetIndent(2)
+		if err := enc.Encode(yamlNode(sidecarValue(reflect.ValueOf(v)))); err != nil {
+			return nil, err
+		}
+		if err := enc.Close(); err != nil {
+			return nil, err
+		}
+		return buf.Bytes(), nil
+	case SidecarTOML:
+		return toml.Marshal(tomlValue(sidecarValue(reflect.ValueOf(v))))
+	}
+	return nil, fmt.Errorf("unsupported sidecar format: %s", format)
+}
+
+// UnmarshalSidecar decodes the info in the format into v, which is a
+// pointer to the info, e.g., *MovieInfo or *ActorInfo. Fields unknown are
+// kept as extra fields.
+func UnmarshalSidecar(data []byte, v any, format SidecarFormat) error {
+	var tree any
+	switch format {
+	case SidecarJSON:
+		return json.Unmarshal(data, v)
+	case SidecarYAML:
+		if err := yaml.Unmarshal(data, &tree); err != nil {
+			return err
+		}
+	case SidecarTOML:
+		var m map[string]any
+		if err := toml.Unmarshal(data, &m); err != nil {
+			return err
+		}
+		tree = m
+	default:
+		return fmt.Errorf("unsupported sidecar format: %s", format)
+	}
+	// decoded by JSON, so that extra fields are kept.
+	data, err := json.Marshal(fromSidecar(reflect.TypeOf(v), tree))
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, v)
+}
+
+type (
+	sidecarObject []sidecarField
+	sidecarField  struct {
+		key   string
+		value any
+	}
+	// sidecarDate is a date formatted in time.DateOnly.
+	sidecarDate string
+)
+
+var extraType = reflect.TypeFor[Extra]()
+
+// sidecarValue converts the value to an ordered tree, nil if omitted.
+func sidecarValue(v reflect.Value) any {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil
+		}
+		v = v.Elem()
+	}
+	switch v.Type() {
+	case dateType:
+		date := time.Time(NormalizeDate(v.Interface().(datatypes.Date)))
+		if date.IsZero() {
+			return nil
+		}
+		return sidecarDate(date.Format(time.DateOnly))
+	case timeType:
+		return v.Interface()
+	case rawType:
+		var value any
+		_ = json.Unmarshal(v.Bytes(), &value)
+		return value
+	}
+	switch v.Kind() {
+	case reflect.Struct:
+		obj := make(sidecarObject, 0, v.NumField())
+		for _, f := range jsonFields(v.Type()) {
+			fv := v.FieldByIndex(f.field.Index)
+			if f.omitEmpty && isEmptyValue(fv) {
+				continue
+			}
+			if value := sidecarValue(fv); value != nil {
+				obj = append(obj, sidecarField{f.name, value})
+			}
+		}
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).Type == extraType {
+				obj = append(obj, sidecarValue(v.Field(i)).(sidecarObject)...)
+			}
+		}
+		return obj
+	case reflect.Map:
+		keys := make([]string, 0, v.Len())
+		for _, key := range v.MapKeys() {
+			keys = append(keys, fmt.Sprint(key.Interface()))
+		}
+		slices.Sort(keys)
+		obj := make(sidecarObject, 0, len(keys))
+		for _, key := range keys {
+			value := sidecarValue(v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key())))
+			if value != nil {
+				obj = append(obj, sidecarField{key, value})
+			}
+		}
+		return obj
+	case reflect.Slice, reflect.Array:
+		items := make([]any, 0, v.Len())
+		for i := 0; i < v.Len(); i++ {
+			if item := sidecarValue(v.Index(i)); item != nil {
+				items = append(items, item)
+			}
+		}
+		return items
+	}
+	return v.Interface()
+}
+
+// isEmptyValue reports whether the value is omitted by `omitempty`.
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map, reflect.String, reflect.Array:
+		return v.Len() == 0
+	case reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	}
+	return v.IsZero()
+}
+
+func yamlNode(v any) *yaml.Node {
+	switch v := v.(type) {
+	case sidecarObject:
+		n := &yaml.Node{Kind: yaml.MappingNode}
+		for _, f := range v {
+			n.Content = append(n.Content, yamlNode(f.key), yamlNode(f.value))
+		}
+		return n
+	case []any:
+		n := &yaml.Node{Kind: yaml.SequenceNode}
+		if len(v) == 0 {
+			n.Style = yaml.FlowStyle // `[]`
+		}
+		for _, item := range v {
+			n.Content = append(n.Content, yamlNode(item))
+		}
+		return n
+	case sidecarDate:
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!timestamp", Value: string(v)}
+	}
+	n := &yaml.Node{}
+	if err := n.Encode(v); err != nil {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!null", Value: "null"}
+	}
+	return n
+}
+
+// tomlValue converts the tree to values encoded by go-toml in order, i.e.,
+// objects are converted to structs of fields tagged by keys.
+func tomlValue(v any) any {
+	switch v := v.(type) {
+	case sidecarObject:
+		fields := make([]reflect.StructField, len(v))
+		for i, f := range v {
+			fields[i] = reflect.StructField{
+				Name: "F" + strconv.Itoa(i),
+				Type: reflect.TypeFor[any](),
+				Tag:  reflect.StructTag("toml:" + strconv.Quote(f.key)),
+			}
+		}
+		s := reflect.New(reflect.StructOf(fields)).Elem()
+		for i, f := range v {
+			s.Field(i).Set(reflect.ValueOf(tomlValue(f.value)))
+		}
+		return s.Interface()
+	case []any:
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = tomlValue(item)
+		}
+		return items
+	case sidecarDate:
+		date, _ := time.Parse(time.DateOnly, string(v))
+		return toml.LocalDate{Year: date.Year(), Month: int(date.Month()), Day: date.Day()}
+	}
+	return v
+}
+
+// fromSidecar converts the decoded tree to the one decoded by JSON into
+// the type, i.e., dates and times are converted to RFC 3339 times.
+func fromSidecar(t reflect.Type, v any) any {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == timeType {
+		if v, ok := v.(time.Time); ok {
+			return v.Format(time.RFC3339Nano)
+		}
+		return v
+	}
+	if t == dateType {
+		switch v := v.(type) {
+		case time.Time:
+			return time.Time(DateOf(v)).Format(time.RFC3339)
+		case toml.LocalDate:
+			return time.Time(DateOf(v.AsTime(time.UTC))).Format(time.RFC3339)
+		case toml.LocalDateTime:
+			return time.Time(DateOf(v.AsTime(time.UTC))).Format(time.RFC3339)
+		case string:
+			if date, err := time.Parse(time.DateOnly, v); err == nil {
+				return date.Format(time.RFC3339)
+			}
+		}
+		return v
+	}
+	switch v := v.(type) {
+	case map[string]any:
+		if t.Kind() != reflect.Struct && t.Kind() != reflect.Map {
+			return v
+		}
+		types := make(map[string]reflect.Type)
+		if t.Kind() == reflect.Struct {
+			for _, f := range jsonFields(t) {
+				types[f.name] = f.field.Type
+			}
+		}
+		m := make(map[string]any, len(v))
+		for key, value := range v {
+			ft, ok := types[key]
+			if t.Kind() == reflect.Map {
+				ft, ok = t.Elem(), true
+			}
+			if !ok {
+				ft = reflect.TypeFor[any]() // extra fields as is.
+			}
+			m[key] = fromSidecar(ft, value)
+		}
+		return m
+	case []any:
+		if t.Kind() != reflect.Slice && t.Kind() != reflect.Array {
+			return v
+		}
+		items := make([]any, len(v))
+		for i, item := range v {
+			items[i] = fromSidecar(t.Elem(), item)
+		}
+		return items
+	}
+	return v
+}
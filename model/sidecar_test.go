@@ -0,0 +1,106 @@
+package model
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/datatypes"
+)
+
+func testSidecarMovie() *MovieInfo {
+	return &MovieInfo{
+		ID:          "1",
+		Number:      "ABC-123",
+		Title:       "Title",
+		Provider:    "TEST",
+		Actors:      []string{"A", "B"},
+		ReleaseDate: datatypes.Date(time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC)),
+		Source: &Source{
+			Provider:    "Test",
+			RetrievedAt: time.Date(2024, 3, 6, 12, 30, 0, 0, time.UTC),
+		},
+		Extra: Extra{"new_field": json.RawMessage(`{"a":[1,2]}`)},
+	}
+}
+
+func TestMarshalSidecar(t *testing.T) {
+	for _, format := range []SidecarFormat{SidecarJSON, SidecarYAML, SidecarTOML} {
+		t.Run(string(format), func(t *testing.T) {
+			info := testSidecarMovie()
+			data, err := MarshalSidecar(info, format)
+			require.NoError(t, err)
+
+			// stable output.
+			again, err := MarshalSidecar(info, format)
+			require.NoError(t, err)
+			assert.Equal(t, string(data), string(again))
+
+			decoded := &MovieInfo{}
+			require.NoError(t, UnmarshalSidecar(data, decoded, format))
+			assert.Equal(t, info.Number, decoded.Number)
+			assert.Equal(t, info.Title, decoded.Title)
+			assert.Equal(t, info.Actors, decoded.Actors)
+			assert.Equal(t, time.Time(info.ReleaseDate), time.Time(decoded.ReleaseDate).UTC())
+			if assert.NotNil(t, decoded.Source) {
+				assert.True(t, info.Source.RetrievedAt.Equal(decoded.Source.RetrievedAt), "times are kept")
+			}
+			if assert.Contains(t, decoded.Extra, "new_field") {
+				assert.JSONEq(t, `{"a":[1,2]}`, string(decoded.Extra["new_field"]))
+			}
+		})
+	}
+}
+
+func TestMarshalSidecar_Format(t *testing.T) {
+	info := testSidecarMovie()
+
+	data, err := MarshalSidecar(info, SidecarYAML)
+	require.NoError(t, err)
+	text := string(data)
+	assert.Contains(t, text, "release_date: 2024-03-05\n")
+	assert.Contains(t, text, "actors:\n  - A\n  - B\n")
+	assert.Contains(t, text, "genres: []\n")
+	assert.NotContains(t, text, "birthday")
+	assert.Less(t, strings.Index(text, "id:"), strings.Index(text, "number:"))
+	assert.Less(t, strings.Index(text, "number:"), strings.Index(text, "title:"))
+	assert.Less(t, strings.Index(text, "release_date:"), strings.Index(text, "new_field:"))
+
+	data, err = MarshalSidecar(info, SidecarTOML)
+	require.NoError(t, err)
+	text = string(data)
+	assert.Contains(t, text, "release_date = 2024-03-05\n")
+	assert.Less(t, strings.Index(text, "id ="), strings.Index(text, "number ="))
+	assert.Less(t, strings.Index(text, "number ="), strings.Index(text, "title ="))
+
+	// zero dates are omitted, and decoded as zero.
+	actor := &ActorInfo{ID: "1", Name: "Name", Provider: "TEST"}
+	data, err = MarshalSidecar(actor, SidecarTOML)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "birthday")
+	decoded := &ActorInfo{}
+	require.NoError(t, UnmarshalSidecar(data, decoded, SidecarTOML))
+	assert.Equal(t, "Name", decoded.Name)
+	assert.True(t, time.Time(decoded.Birthday).IsZero())
+}
+
+func TestParseSidecarFormat(t *testing.T) {
+	for _, unit := range []struct {
+		s      string
+		format SidecarFormat
+	}{
+		{"json", SidecarJSON},
+		{".yml", SidecarYAML},
+		{"YAML", SidecarYAML},
+		{"toml", SidecarTOML},
+	} {
+		format, err := ParseSidecarFormat(unit.s)
+		require.NoError(t, err)
+		assert.Equal(t, unit.format, format)
+	}
+	_, err := ParseSidecarFormat("nfo")
+	assert.Error(t, err)
+}
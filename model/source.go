@@ -0,0 +1,124 @@
+package model
+
+import (
+	"fmt"
+	"slices"
+	"time"
+)
+
+// Source credits where the info is scraped from, so that UIs are able to
+// display "data from X, retrieved on Y" per record.
+type Source struct {
+	// Provider is the display name of the provider.
+	Provider string `json:"provider"`
+	// URL is the page the info is scraped from, i.e., the homepage.
+	URL string `json:"url"`
+	// RetrievedAt is the time the info is scraped, in UTC.
+	RetrievedAt time.Time `json:"retrieved_at"`
+	// Fields records source URLs of fields by JSON names, which are
+	// scraped from other pages than URL, e.g., of sample videos.
+	Fields map[string]string `json:"fields,omitempty"`
+	// ArchiveURL is the snapshot of the Internet Archive which the info is
+	// scraped from, as the page has been removed, empty if live.
+	ArchiveURL string `json:"archive_url,omitempty"`
+	// Placeholders are JSON names of image fields of placeholder images,
+	// e.g., `cover_url` of "now printing" covers of unreleased movies.
+	Placeholders []string `json:"placeholders,omitempty"`
+}
+
+// FieldURL returns the source URL of the field by its JSON name.
+func (s *Source) FieldURL(field string) string {
+	if s == nil {
+		return ""
+	}
+	if u, ok := s.Fields[field]; ok {
+		return u
+	}
+	return s.URL
+}
+
+// Archived reports whether the info is scraped from an archived snapshot.
+func (s *Source) Archived() bool { return s != nil && s.ArchiveURL != "" }
+
+// Placeholder reports whether the image field by its JSON name is of a
+// placeholder image.
+func (s *Source) Placeholder(field string) bool {
+	return s != nil && slices.Contains(s.Placeholders, field)
+}
+
+// String returns the credit line of the source.
+func (s *Source) String() string {
+	if s == nil {
+		return ""
+	}
+	provider := s.Provider
+	if s.Archived() {
+		provider += " (archived)"
+	}
+	if s.RetrievedAt.IsZero() {
+		return fmt.Sprintf("Data from %s", provider)
+	}
+	return fmt.Sprintf("Data from %s, retrieved on %s", provider, s.RetrievedAt.Format(time.DateOnly))
+}
+
+// setFieldSource records the source URL of the field of the source, which
+// is made if nil.
+func setFieldSource(s **Source, field, url string) {
+	if *s == nil {
+		*s = &Source{}
+	}
+	if (*s).Fields == nil {
+		(*s).Fields = make(map[string]string)
+	}
+	(*s).Fields[field] = url
+}
+
+// SetFieldSource records the source URL of the field by its JSON name,
+// which is called by providers scraping the field from other pages.
+func (m *MovieInfo) SetFieldSource(field, url string) { setFieldSource(&m.Source, field, url) }
+
+// SetFieldSource records the source URL of the field, see MovieInfo.
+func (a *ActorInfo) SetFieldSource(field, url string) { setFieldSource(&a.Source, field, url) }
+
+// SetArchiveURL flags the info as scraped from the archived snapshot.
+func (m *MovieInfo) SetArchiveURL(url string) { setArchiveURL(&m.Source, url) }
+
+// SetArchiveURL flags the info as archived, see MovieInfo.
+func (a *ActorInfo) SetArchiveURL(url string) { setArchiveURL(&a.Source, url) }
+
+// SetPlaceholder flags the image field by its JSON name as a placeholder.
+func (m *MovieInfo) SetPlaceholder(field string) {
+	if m.Source == nil {
+		m.Source = &Source{}
+	}
+	if !m.Source.Placeholder(field) {
+		m.Source.Placeholders = append(m.Source.Placeholders, field)
+	}
+}
+
+func setArchiveURL(s **Source, url string) {
+	if *s == nil {
+		*s = &Source{}
+	}
+	(*s).ArchiveURL = url
+}
+
+// Attribute credits the info to the provider by its display name at the
+// time, field sources recorded by the provider are kept.
+func (m *MovieInfo) Attribute(provider string, at time.Time) {
+	attribute(&m.Source, provider, m.Homepage, at)
+}
+
+// Attribute credits the info to the provider, see MovieInfo.
+func (a *ActorInfo) Attribute(provider string, at time.Time) {
+	attribute(&a.Source, provider, a.Homepage, at)
+}
+
+func attribute(s **Source, provider, url string, at time.Time) {
+	if *s == nil {
+		*s = &Source{}
+	}
+	(*s).Provider = provider
+	(*s).URL = url
+	(*s).RetrievedAt = at.UTC()
+}
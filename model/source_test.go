@@ -0,0 +1,42 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSource(t *testing.T) {
+	info := &MovieInfo{Homepage: "https://example.com/1"}
+	info.SetFieldSource("preview_video_url", "https://example.com/1/sample")
+	info.Attribute("Example", time.Date(2024, 3, 5, 9, 0, 0, 0, time.FixedZone("JST", 9*60*60)))
+
+	assert.Equal(t, "Example", info.Source.Provider)
+	assert.Equal(t, time.Date(2024, 3, 5, 0, 0, 0, 0, time.UTC), info.Source.RetrievedAt)
+	assert.Equal(t, "https://example.com/1", info.Source.FieldURL("title"))
+	assert.Equal(t, "https://example.com/1/sample", info.Source.FieldURL("preview_video_url"))
+	assert.Equal(t, "Data from Example, retrieved on 2024-03-05", info.Source.String())
+
+	assert.False(t, info.Source.Archived())
+	info.SetArchiveURL("https://web.archive.org/web/20240101000000id_/https://example.com/1")
+	assert.True(t, info.Source.Archived())
+	assert.Equal(t, "Data from Example (archived), retrieved on 2024-03-05", info.Source.String())
+
+	assert.False(t, info.Source.Placeholder("cover_url"))
+	info.SetPlaceholder("cover_url")
+	info.SetPlaceholder("cover_url")
+	assert.True(t, info.Source.Placeholder("cover_url"))
+	assert.Equal(t, []string{"cover_url"}, info.Source.Placeholders)
+
+	var source *Source
+	assert.Empty(t, source.FieldURL("title"))
+	assert.Empty(t, source.String())
+	assert.False(t, source.Archived())
+	assert.False(t, source.Placeholder("cover_url"))
+
+	// re-attribution is not a change of metadata.
+	old := *info
+	old.Source = &Source{Provider: "Example"}
+	assert.Empty(t, DiffMovieInfo(&old, info))
+}
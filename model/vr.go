@@ -0,0 +1,96 @@
+package model
+
+import (
+	"regexp"
+	"strconv"
+)
+
+// Projections of VR movies.
+const (
+	EquirectangularProjection = "equirectangular"
+	FisheyeProjection         = "fisheye"
+)
+
+// Stereo modes of VR movies.
+const (
+	SideBySideStereo = "sbs"
+	TopBottomStereo  = "tb"
+	MonoStereo       = "mono"
+)
+
+// VRInfo is the VR-specific metadata of VR movies, which is required by
+// VR players, e.g., DeoVR and HereSphere.
+type VRInfo struct {
+	// Projection is the projection type, e.g., `equirectangular`.
+	Projection string `json:"projection"`
+	// StereoMode is the stereo mode, e.g., `sbs`.
+	StereoMode string `json:"stereo_mode"`
+	// FOV is the horizontal field of view in degrees, e.g., 180.
+	FOV int `json:"fov"`
+}
+
+// DefaultVRInfo returns the most common format of VR movies, i.e., the
+// side-by-side 180° equirectangular, e.g., of FANZA VR.
+func DefaultVRInfo() *VRInfo {
+	return &VRInfo{
+		Projection: EquirectangularProjection,
+		StereoMode: SideBySideStereo,
+		FOV:        180,
+	}
+}
+
+var (
+	vrFOVRe        = regexp.MustCompile(`(?i)\b(1[89]0|2[0-2]0|360)\s*(?:°|度|deg(?:rees?)?\b|vr\b)`)
+	vrFisheyeRe    = regexp.MustCompile(`(?i)fish[-\s]?eye|魚眼|\bvrca\s?220\b|\brf52\b|\b(mkx\s?200)\b`)
+	vrEquirectRe   = regexp.MustCompile(`(?i)equirect|正距円筒`)
+	vrSideBySideRe = regexp.MustCompile(`(?i)\bsbs\b|side[-\s]?by[-\s]?side|サイドバイサイド|\blr\b|\b3d\b`)
+	vrTopBottomRe  = regexp.MustCompile(`(?i)\btb\b|top[-\s]?(?:and[-\s]?)?bottom|トップ[・･]?(?:アンド[・･]?)?ボトム|over[-\s]?under|\bou\b`)
+	vrMonoRe       = regexp.MustCompile(`(?i)\bmono(?:scopic)?\b|\b2d\b`)
+)
+
+// ParseVRInfo parses the VR info from texts of pages, e.g., `魚眼` of
+// FANZA VR pages, or labels in the style of VR studios, e.g., `180° 3D
+// SBS` and `Fisheye 190°`, which are not scraped by any provider yet.
+// Formats not given are of DefaultVRInfo, except that fisheye lenses are
+// 190° by default. It returns nil if none is found.
+func ParseVRInfo(texts ...string) *VRInfo {
+	var (
+		vr       = DefaultVRInfo()
+		found    bool
+		fovFound bool
+	)
+	for _, text := range texts {
+		if ss := vrFOVRe.FindStringSubmatch(text); ss != nil {
+			vr.FOV, _ = strconv.Atoi(ss[1])
+			found, fovFound = true, true
+		}
+		if ss := vrFisheyeRe.FindStringSubmatch(text); ss != nil {
+			vr.Projection = FisheyeProjection
+			if !fovFound {
+				vr.FOV = 190
+				if ss[1] != "" {
+					vr.FOV = 200 // MKX200 lenses.
+				}
+			}
+			found = true
+		} else if vrEquirectRe.MatchString(text) {
+			vr.Projection = EquirectangularProjection
+			found = true
+		}
+		switch {
+		case vrTopBottomRe.MatchString(text):
+			vr.StereoMode = TopBottomStereo
+		case vrSideBySideRe.MatchString(text):
+			vr.StereoMode = SideBySideStereo
+		case vrMonoRe.MatchString(text):
+			vr.StereoMode = MonoStereo
+		default:
+			continue
+		}
+		found = true
+	}
+	if !found {
+		return nil
+	}
+	return vr
+}
@@ -0,0 +1,26 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseVRInfo(t *testing.T) {
+	for _, unit := range []struct {
+		texts []string
+		want  *VRInfo
+	}{
+		{[]string{"Some Title"}, nil},
+		{[]string{"180° 3D SBS"}, &VRInfo{EquirectangularProjection, SideBySideStereo, 180}},
+		{[]string{"360 degrees", "Top-Bottom"}, &VRInfo{EquirectangularProjection, TopBottomStereo, 360}},
+		{[]string{"Fisheye"}, &VRInfo{FisheyeProjection, SideBySideStereo, 190}},
+		{[]string{"MKX200"}, &VRInfo{FisheyeProjection, SideBySideStereo, 200}},
+		{[]string{"Fisheye 220°"}, &VRInfo{FisheyeProjection, SideBySideStereo, 220}},
+		{[]string{"2D 180VR"}, &VRInfo{EquirectangularProjection, MonoStereo, 180}},
+		{[]string{"【VR】魚眼レンズで撮影", "200度"}, &VRInfo{FisheyeProjection, SideBySideStereo, 200}},
+		{[]string{"トップアンドボトム形式の360度VR"}, &VRInfo{EquirectangularProjection, TopBottomStereo, 360}},
+	} {
+		assert.Equal(t, unit.want, ParseVRInfo(unit.texts...), unit.texts)
+	}
+}
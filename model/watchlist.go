@@ -0,0 +1,39 @@
+package model
+
+import (
+	"time"
+)
+
+const WatchlistTableName = "watchlist"
+
+// WatchlistEntry is a watched movie number or actor name, which is checked
+// periodically for newly available metadata and preview media. The states
+// are of the last check.
+type WatchlistEntry struct {
+	Kind string `json:"kind" gorm:"primaryKey"`
+	// Name is the movie number or actor name.
+	Name string `json:"name" gorm:"primaryKey"`
+	// Provider and ID are of the info found.
+	Provider string `json:"provider,omitempty"`
+	ID       string `json:"id,omitempty"`
+	// Released is whether the movie has been released.
+	Released bool `json:"released"`
+	// HasPreview is whether the movie has preview video or images, or the
+	// actor has images.
+	HasPreview  bool      `json:"has_preview"`
+	CheckedAt   time.Time `json:"checked_at"`
+	TimeTracker `json:"-"`
+}
+
+func (*WatchlistEntry) TableName() string {
+	return WatchlistTableName
+}
+
+func (w *WatchlistEntry) Valid() bool {
+	return (w.Kind == MovieKind || w.Kind == ActorKind) && w.Name != ""
+}
+
+// Available reports whether the info has been found.
+func (w *WatchlistEntry) Available() bool {
+	return w.Provider != "" && w.ID != ""
+}
@@ -0,0 +1,48 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+)
+
+// Discord sends events to a channel by the webhook, as embeds.
+type Discord struct {
+	webhookURL string
+	fetcher    *fetch.Fetcher
+}
+
+func NewDiscord(webhookURL string) (*Discord, error) {
+	if webhookURL == "" {
+		return nil, errors.New("discord: webhook url required")
+	}
+	return &Discord{webhookURL: webhookURL, fetcher: fetch.Default(nil)}, nil
+}
+
+type discordEmbed struct {
+	Title       string        `json:"title"`
+	Description string        `json:"description,omitempty"`
+	URL         string        `json:"url,omitempty"`
+	Image       *discordImage `json:"image,omitempty"`
+}
+
+type discordImage struct {
+	URL string `json:"url"`
+}
+
+func (d *Discord) Notify(e *Event) error {
+	embed := &discordEmbed{Title: e.Title, Description: e.Message, URL: e.URL}
+	if e.ImageURL != "" {
+		embed.Image = &discordImage{URL: e.ImageURL}
+	}
+	// webhooks respond 204 without ?wait=true.
+	resp, err := d.fetcher.Post(d.webhookURL,
+		fetch.WithJSONBody(map[string]any{"embeds": []*discordEmbed{embed}}),
+		fetch.WithHeader("Content-Type", "application/json"),
+		fetch.WithQuery("wait", "true"))
+	if err != nil {
+		return fmt.Errorf("discord: %w", err)
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,53 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+)
+
+// Gotify priorities of events, outages are raised to notify on phones.
+const (
+	gotifyDefaultPriority = 5
+	gotifyOutagePriority  = 8
+)
+
+// Gotify sends events to a Gotify server by the application token.
+type Gotify struct {
+	serverURL string
+	token     string
+	fetcher   *fetch.Fetcher
+}
+
+func NewGotify(serverURL, token string) (*Gotify, error) {
+	if serverURL == "" || token == "" {
+		return nil, errors.New("gotify: server url and app token required")
+	}
+	return &Gotify{serverURL: serverURL, token: token, fetcher: fetch.Default(nil)}, nil
+}
+
+func (g *Gotify) Notify(e *Event) error {
+	priority := gotifyDefaultPriority
+	if e.Kind == ProviderOutage {
+		priority = gotifyOutagePriority
+	}
+	body := map[string]any{
+		"title":    e.Title,
+		"message":  e.Message,
+		"priority": priority,
+	}
+	if e.URL != "" {
+		body["extras"] = map[string]any{
+			"client::notification": map[string]any{"click": map[string]string{"url": e.URL}},
+		}
+	}
+	resp, err := g.fetcher.Post(g.serverURL+"/message",
+		fetch.WithJSONBody(body),
+		fetch.WithHeader("Content-Type", "application/json"),
+		fetch.WithHeader("X-Gotify-Key", g.token))
+	if err != nil {
+		return fmt.Errorf("gotify: %w", err)
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,139 @@
+// Package notify announces events, e.g., completed scrapes and provider
+// outages, to chat services like Telegram, Discord and Gotify.
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+)
+
+// Kind is the kind of events.
+type Kind string
+
+const (
+	// ScrapeCompleted is sent when a new movie is scraped.
+	ScrapeCompleted Kind = "scrape"
+	// IdentifyFailed is sent when a file can't be identified.
+	IdentifyFailed Kind = "identify"
+	// ProviderOutage is sent when a provider is throttled or down.
+	ProviderOutage Kind = "outage"
+	// SeriesUpdated is sent when new entries of a tracked series are found.
+	SeriesUpdated Kind = "series"
+	// WatchlistUpdated is sent when watched movies or actors are changed.
+	WatchlistUpdated Kind = "watchlist"
+	// MetadataChanged is sent when a stored movie is changed by refreshes.
+	MetadataChanged Kind = "changed"
+)
+
+// Event is a notification.
+type Event struct {
+	Kind     Kind   `json:"kind"`
+	Title    string `json:"title"`
+	Message  string `json:"message"`
+	URL      string `json:"url,omitempty"`
+	ImageURL string `json:"image_url,omitempty"`
+}
+
+// Notifier sends events to a service.
+type Notifier interface {
+	Notify(e *Event) error
+}
+
+// Multi sends events to all the notifiers.
+type Multi []Notifier
+
+func (m Multi) Notify(e *Event) error {
+	var errs []error
+	for _, n := range m {
+		if err := n.Notify(e); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+type filter struct {
+	Notifier
+	kinds []Kind
+}
+
+func (f *filter) Notify(e *Event) error {
+	if !slices.Contains(f.kinds, e.Kind) {
+		return nil
+	}
+	return f.Notifier.Notify(e)
+}
+
+// Filter returns the notifier which only sends events of the kinds.
+func Filter(n Notifier, kinds ...Kind) Notifier {
+	return &filter{Notifier: n, kinds: kinds}
+}
+
+// Parse parses the notifier from the URL of the service:
+//
+//	telegram://<bot-token>@<chat-id>
+//	discord://<webhook-id>/<webhook-token>
+//	gotify://<host>/<app-token>  use gotify+http for plain HTTP
+//
+// Events can be filtered by kinds with the query, e.g., `?events=outage`.
+func Parse(rawURL string) (Notifier, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	var n Notifier
+	switch u.Scheme {
+	case "telegram":
+		// bot tokens are in the form of `id:secret`.
+		token := u.User.Username()
+		if secret, ok := u.User.Password(); ok {
+			token += ":" + secret
+		}
+		n, err = NewTelegram(token, u.Host)
+	case "discord":
+		n, err = NewDiscord(fmt.Sprintf("https://discord.com/api/webhooks/%s%s", u.Host, u.Path))
+	case "gotify", "gotify+http":
+		scheme := "https"
+		if u.Scheme == "gotify+http" {
+			scheme = "http"
+		}
+		dir, token := splitLast(u.Path)
+		n, err = NewGotify(fmt.Sprintf("%s://%s%s", scheme, u.Host, dir), token)
+	default:
+		return nil, fmt.Errorf("unsupported notifier: %s", u.Scheme)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if events := u.Query().Get("events"); events != "" {
+		var kinds []Kind
+		for _, kind := range strings.Split(events, ",") {
+			kinds = append(kinds, Kind(strings.TrimSpace(kind)))
+		}
+		n = Filter(n, kinds...)
+	}
+	return n, nil
+}
+
+func splitLast(p string) (dir, last string) {
+	p = strings.TrimSuffix(p, "/")
+	if i := strings.LastIndex(p, "/"); i >= 0 {
+		return p[:i], p[i+1:]
+	}
+	return "", p
+}
+
+// text formats the event as plain text.
+func text(e *Event) string {
+	s := e.Title
+	if e.Message != "" {
+		s += "\n" + e.Message
+	}
+	if e.URL != "" {
+		s += "\n" + e.URL
+	}
+	return s
+}
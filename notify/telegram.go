@@ -0,0 +1,50 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/redact"
+)
+
+const telegramAPIURL = "https://api.telegram.org"
+
+// Telegram sends events by a bot to a chat, with the image as a photo.
+type Telegram struct {
+	// APIURL is the root of the Bot API, for self-hosted servers.
+	APIURL  string
+	token   string
+	chatID  string
+	fetcher *fetch.Fetcher
+}
+
+func NewTelegram(token, chatID string) (*Telegram, error) {
+	if token == "" || chatID == "" {
+		return nil, errors.New("telegram: bot token and chat id required")
+	}
+	return &Telegram{
+		APIURL:  telegramAPIURL,
+		token:   token,
+		chatID:  chatID,
+		fetcher: fetch.Default(nil),
+	}, nil
+}
+
+func (t *Telegram) Notify(e *Event) error {
+	var (
+		method = "sendMessage"
+		body   = map[string]string{"chat_id": t.chatID, "text": text(e)}
+	)
+	if e.ImageURL != "" {
+		method = "sendPhoto"
+		body = map[string]string{"chat_id": t.chatID, "photo": e.ImageURL, "caption": text(e)}
+	}
+	resp, err := t.fetcher.Post(fmt.Sprintf("%s/bot%s/%s", t.APIURL, t.token, method),
+		fetch.WithJSONBody(body), fetch.WithHeader("Content-Type", "application/json"))
+	if err != nil {
+		// the request URL in errors has the token.
+		return fmt.Errorf("telegram: %w", redact.Error(err, t.token))
+	}
+	return resp.Body.Close()
+}
@@ -0,0 +1,124 @@
+package organize
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/metatube-community/metatube-sdk-go/common/safepath"
+)
+
+// entry is an applied operation in the journal, with what's needed to
+// undo it.
+type entry struct {
+	Kind   OpKind `json:"kind"`
+	Src    string `json:"src,omitempty"`
+	Dst    string `json:"dst"`
+	Backup string `json:"backup,omitempty"`
+}
+
+// journal is an append-only file of entries in JSON lines, each entry is
+// synced before the next operation, so it survives crashes.
+type journal struct {
+	mu sync.Mutex
+	f  *os.File
+}
+
+func createJournal(name string) (*journal, error) {
+	f, err := os.OpenFile(name, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return &journal{f: f}, nil
+}
+
+func (j *journal) record(e *entry) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if _, err = j.f.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return j.f.Sync()
+}
+
+func (j *journal) close() error { return j.f.Close() }
+
+// Rollback undoes the operations journaled in the file in reverse order,
+// and removes the journal if all of them are undone.
+func Rollback(name string) error {
+	f, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	var entries []*entry
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		e := &entry{}
+		if err = json.Unmarshal(scanner.Bytes(), e); err != nil {
+			break // ignore the torn last line.
+		}
+		entries = append(entries, e)
+	}
+	_ = f.Close()
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	var errs []error
+	for i := len(entries) - 1; i >= 0; i-- {
+		if err = undo(entries[i]); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.Join(errs...)
+	}
+	return os.Remove(name)
+}
+
+func undo(e *entry) error {
+	switch e.Kind {
+	case MkdirOp:
+		return removeEmptyDirs(e.Dst)
+	case RenameOp:
+		if err := os.Rename(safepath.LongPath(e.Dst), safepath.LongPath(e.Src)); err != nil {
+			return err
+		}
+	case SymlinkOp, HardlinkOp, DownloadOp, WriteOp:
+		if err := os.Remove(safepath.LongPath(e.Dst)); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	if e.Backup != "" {
+		return os.Rename(safepath.LongPath(e.Backup), safepath.LongPath(e.Dst))
+	}
+	return nil
+}
+
+// removeEmptyDirs removes dir and its sub-directories if they are empty,
+// files created by others since then are never removed.
+func removeEmptyDirs(dir string) error {
+	entries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		if e.IsDir() {
+			_ = removeEmptyDirs(filepath.Join(dir, e.Name()))
+		}
+	}
+	if entries, err = os.ReadDir(dir); err == nil && len(entries) > 0 {
+		return nil // keep non-empty.
+	}
+	return os.Remove(dir)
+}
@@ -0,0 +1,64 @@
+package organize
+
+import (
+	"sync"
+)
+
+// Stage is a stage of a pipeline run by concurrent workers.
+type Stage[T any] struct {
+	Name    string
+	Workers int
+	// Do processes the item, items failed are dropped from the pipeline.
+	Do func(item T) error
+}
+
+// RunPipeline passes items through stages in order, each stage is run by
+// its workers concurrently, and stages are connected by queues of size
+// bounded, so a slow stage blocks the upstream ones instead of piling up
+// items, e.g., downloads throttled by providers hold back scrapes. It
+// returns items passed all stages, in the order of completion, onError is
+// called with the stage name for items failed.
+func RunPipeline[T any](items []T, bounded int, onError func(item T, stage string, err error), stages ...*Stage[T]) []T {
+	in := make(chan T, bounded)
+	go func() {
+		defer close(in)
+		for _, item := range items {
+			in <- item
+		}
+	}()
+	var (
+		mu sync.Mutex
+		ch = in
+	)
+	for _, stage := range stages {
+		out := make(chan T, bounded)
+		var wg sync.WaitGroup
+		for i := 0; i < max(stage.Workers, 1); i++ {
+			wg.Add(1)
+			go func(stage *Stage[T], in <-chan T) {
+				defer wg.Done()
+				for item := range in {
+					if err := stage.Do(item); err != nil {
+						if onError != nil {
+							mu.Lock()
+							onError(item, stage.Name, err)
+							mu.Unlock()
+						}
+						continue
+					}
+					out <- item
+				}
+			}(stage, ch)
+		}
+		go func() {
+			wg.Wait()
+			close(out)
+		}()
+		ch = out
+	}
+	var done []T
+	for item := range ch {
+		done = append(done, item)
+	}
+	return done
+}
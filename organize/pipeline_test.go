@@ -0,0 +1,56 @@
+package organize
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunPipeline(t *testing.T) {
+	type item struct{ n, doubled int }
+	var items []*item
+	for i := 0; i < 100; i++ {
+		items = append(items, &item{n: i})
+	}
+
+	var (
+		inFlight, maxInFlight atomic.Int32
+		failed                []int
+	)
+	done := RunPipeline(items, 2, func(it *item, stage string, err error) {
+		assert.Equal(t, "double", stage)
+		failed = append(failed, it.n)
+	}, &Stage[*item]{
+		Name:    "double",
+		Workers: 4,
+		Do: func(it *item) error {
+			if it.n%10 == 0 {
+				return errors.New("bad item")
+			}
+			it.doubled = it.n * 2
+			return nil
+		},
+	}, &Stage[*item]{
+		Name:    "slow",
+		Workers: 1,
+		Do: func(it *item) error {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			if n > maxInFlight.Load() {
+				maxInFlight.Store(n)
+			}
+			time.Sleep(time.Millisecond)
+			return nil
+		},
+	})
+
+	assert.Len(t, done, 90)
+	assert.Len(t, failed, 10)
+	assert.EqualValues(t, 1, maxInFlight.Load())
+	for _, it := range done {
+		assert.Equal(t, it.n*2, it.doubled)
+	}
+}
@@ -0,0 +1,321 @@
+// Package organize plans and applies operations of organizing a media
+// library, e.g., renaming videos and downloading artwork. Plans are made
+// without touching files, so they can be reviewed before applied, and all
+// applied operations are journaled to be rolled back.
+package organize
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/common/safepath"
+)
+
+// OpKind is the kind of operations.
+type OpKind string
+
+const (
+	MkdirOp    OpKind = "mkdir"
+	RenameOp   OpKind = "rename"
+	SymlinkOp  OpKind = "symlink"
+	HardlinkOp OpKind = "hardlink"
+	DownloadOp OpKind = "download"
+	WriteOp    OpKind = "write"
+)
+
+// Op is an intended file operation.
+type Op struct {
+	Kind OpKind `json:"kind"`
+	// Group is the group of operations applied in order, e.g., of the same
+	// movie, groups are independent of each other.
+	Group string `json:"group,omitempty"`
+	// Src is the source file of rename and links.
+	Src string `json:"src,omitempty"`
+	// Dst is the target file or directory.
+	Dst string `json:"dst"`
+	// URL and Provider are the source of download.
+	URL      string `json:"url,omitempty"`
+	Provider string `json:"provider,omitempty"`
+	// Data is the content of write.
+	Data []byte `json:"data,omitempty"`
+}
+
+// Plan is a list of operations applied in order.
+type Plan struct {
+	CreatedAt time.Time `json:"created_at"`
+	Ops       []*Op     `json:"ops"`
+	// group of operations planned next.
+	group string
+}
+
+// NewPlan returns an empty plan.
+func NewPlan() *Plan {
+	return &Plan{CreatedAt: time.Now(), Ops: []*Op{}}
+}
+
+// Group sets the group of operations planned next.
+func (p *Plan) Group(name string) {
+	p.group = name
+}
+
+func (p *Plan) add(op *Op) {
+	op.Group = p.group
+	p.Ops = append(p.Ops, op)
+}
+
+// Mkdir plans to create the directory and its parents.
+func (p *Plan) Mkdir(dir string) {
+	p.add(&Op{Kind: MkdirOp, Dst: dir})
+}
+
+// Rename plans to move the file src to dst, it's a no-op and dropped if
+// src is dst already.
+func (p *Plan) Rename(src, dst string) {
+	if samePath(src, dst) {
+		return
+	}
+	p.add(&Op{Kind: RenameOp, Src: src, Dst: dst})
+}
+
+// Symlink plans to create dst as a symbolic link to src, src is made
+// absolute so the link is valid wherever dst is. It's dropped if src is
+// dst already.
+func (p *Plan) Symlink(src, dst string) error {
+	abs, err := filepath.Abs(src)
+	if err != nil {
+		return err
+	}
+	if samePath(abs, dst) {
+		return nil
+	}
+	p.add(&Op{Kind: SymlinkOp, Src: abs, Dst: dst})
+	return nil
+}
+
+// Hardlink plans to create dst as a hard link to src, which must be on
+// the same file system. It's dropped if src is dst already.
+func (p *Plan) Hardlink(src, dst string) {
+	if samePath(src, dst) {
+		return
+	}
+	p.add(&Op{Kind: HardlinkOp, Src: src, Dst: dst})
+}
+
+// samePath reports whether paths a and b are the same file.
+func samePath(a, b string) bool {
+	if filepath.Clean(a) == filepath.Clean(b) {
+		return true
+	}
+	absA, errA := filepath.Abs(a)
+	absB, errB := filepath.Abs(b)
+	return errA == nil && errB == nil && absA == absB
+}
+
+// Download plans to download the url of the provider to dst.
+func (p *Plan) Download(provider, url, dst string) {
+	p.add(&Op{Kind: DownloadOp, Provider: provider, URL: url, Dst: dst})
+}
+
+// Write plans to write the data to dst.
+func (p *Plan) Write(dst string, data []byte) {
+	p.add(&Op{Kind: WriteOp, Dst: dst, Data: data})
+}
+
+// Save writes the plan in JSON to the file name.
+func (p *Plan) Save(name string) error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(name, data, 0o644)
+}
+
+// LoadPlan reads the plan from the file name.
+func LoadPlan(name string) (*Plan, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	p := &Plan{}
+	return p, json.Unmarshal(data, p)
+}
+
+// DownloadFunc downloads the url of the provider to the file name.
+type DownloadFunc func(provider, url, name string) error
+
+// Apply applies operations of the plan in order, and journals them into
+// the file journal, which is kept for rollback even if an operation fails.
+// Existing files overwritten are backed up beside and restored on rollback.
+func (p *Plan) Apply(download DownloadFunc, journal string) error {
+	return p.ApplyParallel(download, journal, 1)
+}
+
+// ApplyParallel applies groups of operations by workers concurrently like
+// Apply, operations of the same group are still applied in order. Groups
+// failed are stopped at the failed operation, while others go on.
+func (p *Plan) ApplyParallel(download DownloadFunc, journal string, workers int) error {
+	a, err := NewApplier(download, journal)
+	if err != nil {
+		return err
+	}
+	defer a.Close()
+
+	if workers <= 1 {
+		for i, op := range p.Ops {
+			if err = apply(op, download, a.j); err != nil {
+				return fmt.Errorf("op %d (%s %s): %w", i, op.Kind, op.Dst, err)
+			}
+		}
+		return nil
+	}
+
+	type group struct{ ops []*Op }
+	var (
+		groups []*group
+		byName = make(map[string]*group)
+	)
+	for _, op := range p.Ops {
+		g, ok := byName[op.Group]
+		if !ok || op.Group == "" /* ungrouped ops are kept in order */ {
+			g = &group{}
+			groups = append(groups, g)
+			byName[op.Group] = g
+		}
+		g.ops = append(g.ops, op)
+	}
+
+	var errs []error
+	RunPipeline(groups, workers, func(_ *group, _ string, err error) {
+		errs = append(errs, err)
+	}, ApplyStages(a, workers, func(g *group) []*Op { return g.ops })...)
+	return errors.Join(errs...)
+}
+
+// Applier applies operations, and journals them into the same journal,
+// e.g., by stages of a pipeline, see ApplyStages.
+type Applier struct {
+	download DownloadFunc
+	j        *journal
+}
+
+// NewApplier returns an applier journaling into the file journal.
+func NewApplier(download DownloadFunc, journal string) (*Applier, error) {
+	j, err := createJournal(journal)
+	if err != nil {
+		return nil, err
+	}
+	return &Applier{download: download, j: j}, nil
+}
+
+// Apply applies the operations in order, and stops at the failed one.
+func (a *Applier) Apply(ops ...*Op) error {
+	for _, op := range ops {
+		if err := apply(op, a.download, a.j); err != nil {
+			return fmt.Errorf("%s %s: %w", op.Kind, op.Dst, err)
+		}
+	}
+	return nil
+}
+
+// Close closes the journal.
+func (a *Applier) Close() error { return a.j.close() }
+
+// ApplyStages returns stages of a pipeline applying operations of items:
+// placing videos, downloading artwork and writing files, each run by its
+// own workers, so that downloads throttled by providers hold back the
+// other stages by the bounded queues instead of piling up items. ops
+// returns the operations of the item in the order planned, which are
+// still applied in order, and items failed are stopped at the stage.
+func ApplyStages[T any](a *Applier, workers int, ops func(item T) []*Op) []*Stage[T] {
+	stage := func(name string, match func(kind OpKind) bool) *Stage[T] {
+		return &Stage[T]{
+			Name:    name,
+			Workers: workers,
+			Do: func(item T) error {
+				var matched []*Op
+				for _, op := range ops(item) {
+					if match(op.Kind) {
+						matched = append(matched, op)
+					}
+				}
+				return a.Apply(matched...)
+			},
+		}
+	}
+	return []*Stage[T]{
+		stage("place", func(kind OpKind) bool { return kind != DownloadOp && kind != WriteOp }),
+		stage("download", func(kind OpKind) bool { return kind == DownloadOp }),
+		stage("write", func(kind OpKind) bool { return kind == WriteOp }),
+	}
+}
+
+func apply(op *Op, download DownloadFunc, j *journal) error {
+	if op.Src != "" && samePath(op.Src, op.Dst) {
+		return nil // no-op, e.g., of plans saved by older versions.
+	}
+	var do func() error
+	switch op.Kind {
+	case MkdirOp:
+		// journal the topmost missing directory only, so that all the
+		// directories created are removed on rollback.
+		top := missingTop(op.Dst)
+		if err := os.MkdirAll(safepath.LongPath(op.Dst), 0o755); err != nil {
+			return err
+		}
+		if top != "" {
+			return j.record(&entry{Kind: MkdirOp, Dst: top})
+		}
+		return nil
+	case RenameOp:
+		do = func() error { return os.Rename(safepath.LongPath(op.Src), safepath.LongPath(op.Dst)) }
+	case SymlinkOp:
+		do = func() error { return os.Symlink(op.Src, safepath.LongPath(op.Dst)) }
+	case HardlinkOp:
+		do = func() error { return os.Link(safepath.LongPath(op.Src), safepath.LongPath(op.Dst)) }
+	case DownloadOp:
+		do = func() error { return download(op.Provider, op.URL, safepath.LongPath(op.Dst)) }
+	case WriteOp:
+		do = func() error { return os.WriteFile(safepath.LongPath(op.Dst), op.Data, 0o644) }
+	default:
+		return fmt.Errorf("unknown op kind: %s", op.Kind)
+	}
+	backup, err := backupExisting(op.Dst)
+	if err != nil {
+		return err
+	}
+	if err = do(); err != nil {
+		if backup != "" {
+			_ = os.Rename(safepath.LongPath(backup), safepath.LongPath(op.Dst))
+		}
+		return err
+	}
+	return j.record(&entry{Kind: op.Kind, Src: op.Src, Dst: op.Dst, Backup: backup})
+}
+
+// missingTop returns the topmost directory of dir that doesn't exist, or
+// empty if dir exists.
+func missingTop(dir string) (top string) {
+	for d := filepath.Clean(dir); ; d = filepath.Dir(d) {
+		if _, err := os.Stat(d); err == nil {
+			return
+		}
+		top = d
+		if parent := filepath.Dir(d); parent == d {
+			return
+		}
+	}
+}
+
+// backupExisting moves the existing file of name aside, and returns the
+// backup name, or empty if the file doesn't exist.
+func backupExisting(name string) (string, error) {
+	if _, err := os.Lstat(safepath.LongPath(name)); os.IsNotExist(err) {
+		return "", nil
+	}
+	backup := fmt.Sprintf("%s.bak-%d", name, time.Now().UnixNano())
+	return backup, os.Rename(safepath.LongPath(name), safepath.LongPath(backup))
+}
@@ -0,0 +1,228 @@
+package organize
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPlan_ApplyAndRollback(t *testing.T) {
+	var (
+		root    = t.TempDir()
+		src     = filepath.Join(root, "abp-030.mp4")
+		dir     = filepath.Join(root, "library", "ABP-030")
+		video   = filepath.Join(dir, "ABP-030.mp4")
+		poster  = filepath.Join(dir, "poster.jpg")
+		info    = filepath.Join(dir, "movie.json")
+		journal = filepath.Join(root, "journal.jsonl")
+	)
+	assert.NoError(t, os.WriteFile(src, []byte("video"), 0o644))
+
+	p := NewPlan()
+	p.Mkdir(dir)
+	p.Rename(src, video)
+	p.Download("JavBus", "https://example.com/poster.jpg", poster)
+	p.Write(info, []byte("{}"))
+
+	// plans survive round trips, nothing is touched before applied.
+	name := filepath.Join(root, "plan.json")
+	assert.NoError(t, p.Save(name))
+	p, err := LoadPlan(name)
+	if !assert.NoError(t, err) || !assert.Len(t, p.Ops, 4) {
+		return
+	}
+	assert.NoDirExists(t, dir)
+
+	download := func(provider, url, name string) error {
+		return os.WriteFile(name, []byte(provider+" "+url), 0o644)
+	}
+	assert.NoError(t, p.Apply(download, journal))
+	assert.NoFileExists(t, src)
+	assert.FileExists(t, video)
+	assert.FileExists(t, poster)
+	assert.FileExists(t, info)
+
+	assert.NoError(t, Rollback(journal))
+	assert.FileExists(t, src)
+	assert.NoDirExists(t, filepath.Join(root, "library"))
+	assert.NoFileExists(t, journal)
+}
+
+func TestPlan_RollbackRestoresBackups(t *testing.T) {
+	var (
+		root    = t.TempDir()
+		name    = filepath.Join(root, "movie.json")
+		journal = filepath.Join(root, "journal.jsonl")
+	)
+	assert.NoError(t, os.WriteFile(name, []byte("old"), 0o644))
+
+	p := NewPlan()
+	p.Write(name, []byte("new"))
+	p.Download("JavBus", "https://example.com/404.jpg", filepath.Join(root, "poster.jpg"))
+	err := p.Apply(func(_, _, _ string) error { return os.ErrNotExist }, journal)
+	assert.Error(t, err)
+
+	data, _ := os.ReadFile(name)
+	assert.Equal(t, "new", string(data))
+	assert.NoError(t, Rollback(journal))
+	data, _ = os.ReadFile(name)
+	assert.Equal(t, "old", string(data))
+}
+
+func TestPlan_Links(t *testing.T) {
+	var (
+		root    = t.TempDir()
+		src     = filepath.Join(root, "seeding", "abp-030.mp4")
+		dir     = filepath.Join(root, "library")
+		journal = filepath.Join(root, "journal.jsonl")
+	)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(src), 0o755))
+	assert.NoError(t, os.WriteFile(src, []byte("video"), 0o644))
+
+	p := NewPlan()
+	p.Mkdir(dir)
+	assert.NoError(t, p.Symlink(src, filepath.Join(dir, "symlink.mp4")))
+	p.Hardlink(src, filepath.Join(dir, "hardlink.mp4"))
+	assert.NoError(t, p.Apply(nil, journal))
+
+	for _, name := range []string{"symlink.mp4", "hardlink.mp4"} {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		assert.NoError(t, err)
+		assert.Equal(t, "video", string(data))
+	}
+	target, err := os.Readlink(filepath.Join(dir, "symlink.mp4"))
+	assert.NoError(t, err)
+	assert.Equal(t, src, target)
+
+	// originals are untouched by rollback.
+	assert.NoError(t, Rollback(journal))
+	assert.FileExists(t, src)
+	assert.NoDirExists(t, dir)
+}
+
+func TestPlan_ApplyParallel(t *testing.T) {
+	var (
+		root    = t.TempDir()
+		journal = filepath.Join(root, "journal.jsonl")
+		p       = NewPlan()
+	)
+	for _, number := range []string{"ABP-001", "ABP-002", "ABP-003", "ABP-004"} {
+		dir := filepath.Join(root, "library", number)
+		p.Group(number)
+		p.Mkdir(dir)
+		p.Download("JavBus", "https://example.com/"+number+".jpg", filepath.Join(dir, "poster.jpg"))
+		p.Write(filepath.Join(dir, number+".json"), []byte(number))
+	}
+	download := func(provider, url, name string) error {
+		if strings.Contains(url, "ABP-003") {
+			return os.ErrNotExist
+		}
+		return os.WriteFile(name, []byte(url), 0o644)
+	}
+	assert.ErrorIs(t, p.ApplyParallel(download, journal, 3), os.ErrNotExist)
+	for _, number := range []string{"ABP-001", "ABP-002", "ABP-004"} {
+		assert.FileExists(t, filepath.Join(root, "library", number, number+".json"))
+	}
+	// the failed group is stopped.
+	assert.NoFileExists(t, filepath.Join(root, "library", "ABP-003", "ABP-003.json"))
+
+	assert.NoError(t, Rollback(journal))
+	assert.NoDirExists(t, filepath.Join(root, "library"))
+}
+
+func TestPlan_NoOps(t *testing.T) {
+	var (
+		root    = t.TempDir()
+		video   = filepath.Join(root, "ABP-030", "ABP-030.mp4")
+		journal = filepath.Join(root, "journal.jsonl")
+	)
+	assert.NoError(t, os.MkdirAll(filepath.Dir(video), 0o755))
+	assert.NoError(t, os.WriteFile(video, []byte("video"), 0o644))
+
+	// videos placed already are neither moved nor backed up.
+	p := NewPlan()
+	p.Rename(video, video)
+	p.Hardlink(video, filepath.Join(root, "ABP-030", ".", "ABP-030.mp4"))
+	assert.NoError(t, p.Symlink(video, video))
+	assert.Empty(t, p.Ops)
+
+	// nor of plans saved with no-ops.
+	p.Ops = append(p.Ops, &Op{Kind: RenameOp, Src: video, Dst: video})
+	assert.NoError(t, p.Apply(nil, journal))
+	data, err := os.ReadFile(video)
+	assert.NoError(t, err)
+	assert.Equal(t, "video", string(data))
+	entries, err := os.ReadDir(filepath.Dir(video))
+	assert.NoError(t, err)
+	assert.Len(t, entries, 1)
+}
+
+func TestApplyStages(t *testing.T) {
+	var (
+		root    = t.TempDir()
+		journal = filepath.Join(root, "journal.jsonl")
+	)
+	type item struct {
+		number string
+		ops    []*Op
+	}
+	var (
+		items                []*item
+		running, maxDownload atomic.Int32
+	)
+	for _, number := range []string{"ABP-001", "ABP-002", "ABP-003", "ABP-004", "ABP-005"} {
+		items = append(items, &item{number: number})
+	}
+	a, err := NewApplier(func(provider, url, name string) error {
+		n := running.Add(1)
+		defer running.Add(-1)
+		if n > maxDownload.Load() {
+			maxDownload.Store(n)
+		}
+		time.Sleep(time.Millisecond)
+		if strings.Contains(url, "ABP-003") {
+			return os.ErrNotExist
+		}
+		return os.WriteFile(name, []byte(url), 0o644)
+	}, journal)
+	require.NoError(t, err)
+
+	var failed []string
+	done := RunPipeline(items, 1, func(it *item, stage string, err error) {
+		assert.Equal(t, "download", stage)
+		assert.ErrorIs(t, err, os.ErrNotExist)
+		failed = append(failed, it.number)
+	}, append([]*Stage[*item]{{
+		Name:    "plan",
+		Workers: 2,
+		Do: func(it *item) error {
+			dir := filepath.Join(root, "library", it.number)
+			p := NewPlan()
+			p.Group(it.number)
+			p.Mkdir(dir)
+			p.Download("JavBus", "https://example.com/"+it.number+".jpg", filepath.Join(dir, "poster.jpg"))
+			p.Write(filepath.Join(dir, it.number+".json"), []byte(it.number))
+			it.ops = p.Ops
+			return nil
+		},
+	}}, ApplyStages(a, 1, func(it *item) []*Op { return it.ops })...)...)
+	require.NoError(t, a.Close())
+
+	assert.Len(t, done, 4)
+	assert.Equal(t, []string{"ABP-003"}, failed)
+	assert.EqualValues(t, 1, maxDownload.Load())
+	for _, it := range done {
+		assert.FileExists(t, filepath.Join(root, "library", it.number, it.number+".json"))
+	}
+	// the failed item is stopped before writing.
+	assert.NoFileExists(t, filepath.Join(root, "library", "ABP-003", "ABP-003.json"))
+
+	assert.NoError(t, Rollback(journal))
+	assert.NoDirExists(t, filepath.Join(root, "library"))
+}
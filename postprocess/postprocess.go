@@ -0,0 +1,162 @@
+// Package postprocess builds the common munging of infos from a config,
+// e.g., title cleanup, actor name aliases and genre blocklist, which are
+// applied by the engine to infos returned.
+package postprocess
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/metatube-community/metatube-sdk-go/common/studio"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// Config is the user config of post-processors, zero values disable them.
+type Config struct {
+	// TitleReplacements are regexp replacements of titles in order.
+	TitleReplacements []*Replacement `json:"title_replacements,omitempty"`
+	// StripStudioPrefix strips the maker or label prefixed to titles,
+	// e.g., `【S1】Title`.
+	StripStudioPrefix bool `json:"strip_studio_prefix,omitempty"`
+	// StudioLanguage canonicalizes makers and labels of known studios to
+	// their names in the language, e.g., `en`, see package studio.
+	StudioLanguage string `json:"studio_language,omitempty"`
+	// ActorAliases maps actor names to the canonical ones, the replaced
+	// names of actor infos are kept as aliases.
+	ActorAliases map[string]string `json:"actor_aliases,omitempty"`
+	// GenreBlocklist removes the genres, case-insensitive.
+	GenreBlocklist []string `json:"genre_blocklist,omitempty"`
+}
+
+// Replacement replaces matches of Pattern with Replace, which supports
+// `$1` style expansions.
+type Replacement struct {
+	Pattern string `json:"pattern"`
+	Replace string `json:"replace"`
+}
+
+// Load loads the JSON config file.
+func Load(name string) (*Config, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &Config{}
+	if err = json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("invalid post-process config: %w", err)
+	}
+	return cfg, nil
+}
+
+// MovieProcessors returns the processors of movie infos in the order of
+// title replacements, studio prefix, studio names, actor aliases and genre
+// blocklist.
+func (cfg *Config) MovieProcessors() ([]func(*model.MovieInfo), error) {
+	var processors []func(*model.MovieInfo)
+	for _, r := range cfg.TitleReplacements {
+		re, err := regexp.Compile(r.Pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid title pattern: %w", err)
+		}
+		replace := r.Replace
+		processors = append(processors, func(info *model.MovieInfo) {
+			info.Title = strings.TrimSpace(re.ReplaceAllString(info.Title, replace))
+		})
+	}
+	if cfg.StripStudioPrefix {
+		processors = append(processors, stripStudioPrefix)
+	}
+	if lang := cfg.StudioLanguage; lang != "" {
+		processors = append(processors, func(info *model.MovieInfo) {
+			info.Maker = studio.Canonicalize(info.Maker, lang)
+			info.Label = studio.Canonicalize(info.Label, lang)
+		})
+	}
+	if aliases := cfg.actorAliases(); len(aliases) > 0 {
+		processors = append(processors, func(info *model.MovieInfo) {
+			var actors []string
+			for _, actor := range info.Actors {
+				if name, ok := aliases[actor]; ok {
+					actor = name
+				}
+				if !slices.Contains(actors, actor) {
+					actors = append(actors, actor)
+				}
+			}
+			info.Actors = actors
+		})
+	}
+	if len(cfg.GenreBlocklist) > 0 {
+		blocked := make(map[string]struct{}, len(cfg.GenreBlocklist))
+		for _, genre := range cfg.GenreBlocklist {
+			blocked[strings.ToLower(genre)] = struct{}{}
+		}
+		processors = append(processors, func(info *model.MovieInfo) {
+			info.Genres = slices.DeleteFunc(info.Genres, func(genre string) bool {
+				_, ok := blocked[strings.ToLower(genre)]
+				return ok
+			})
+		})
+	}
+	return processors, nil
+}
+
+// ActorProcessors returns the processors of actor infos.
+func (cfg *Config) ActorProcessors() []func(*model.ActorInfo) {
+	aliases := cfg.actorAliases()
+	if len(aliases) == 0 {
+		return nil
+	}
+	return []func(*model.ActorInfo){
+		func(info *model.ActorInfo) {
+			name, ok := aliases[info.Name]
+			if !ok || name == info.Name {
+				return
+			}
+			if !slices.Contains(info.Aliases, info.Name) {
+				info.Aliases = append(info.Aliases, info.Name)
+			}
+			info.Name = name
+		},
+	}
+}
+
+// actorAliases returns the aliases with names trimmed.
+func (cfg *Config) actorAliases() map[string]string {
+	aliases := make(map[string]string, len(cfg.ActorAliases))
+	for alias, name := range cfg.ActorAliases {
+		aliases[strings.TrimSpace(alias)] = strings.TrimSpace(name)
+	}
+	return aliases
+}
+
+// studioBrackets are brackets around studio prefixes of titles.
+var studioBrackets = [][2]string{{"【", "】"}, {"[", "]"}, {"(", ")"}, {"（", "）"}, {"", ""}}
+
+// studioSeparators separate studio prefixes from titles.
+const studioSeparators = " 　-_:：|"
+
+func stripStudioPrefix(info *model.MovieInfo) {
+	for _, studio := range []string{info.Maker, info.Label} {
+		if studio = strings.TrimSpace(studio); studio == "" {
+			continue
+		}
+		for _, b := range studioBrackets {
+			prefix := b[0] + studio + b[1]
+			if len(info.Title) <= len(prefix) || !strings.EqualFold(info.Title[:len(prefix)], prefix) {
+				continue
+			}
+			rest := info.Title[len(prefix):]
+			if r, _ := utf8.DecodeRuneInString(rest); b[0] == "" && !strings.ContainsRune(studioSeparators, r) {
+				continue // part of a word.
+			}
+			info.Title = strings.TrimLeft(rest, studioSeparators)
+			return
+		}
+	}
+}
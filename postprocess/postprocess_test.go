@@ -0,0 +1,75 @@
+package postprocess
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+func TestConfig_MovieProcessors(t *testing.T) {
+	cfg := &Config{
+		TitleReplacements: []*Replacement{
+			{Pattern: `\s*【期間限定】\s*`, Replace: " "},
+			{Pattern: `(?i)\(blu-ray\)$`},
+		},
+		StripStudioPrefix: true,
+		StudioLanguage:    "en",
+		ActorAliases:      map[string]string{"Old Name": "New Name"},
+		GenreBlocklist:    []string{"sample", "HD"},
+	}
+	processors, err := cfg.MovieProcessors()
+	if !assert.NoError(t, err) {
+		return
+	}
+	assert.Len(t, processors, 6)
+
+	info := &model.MovieInfo{
+		Title:  "【S1】【期間限定】Title (Blu-ray)",
+		Maker:  "S1",
+		Label:  "Unknown",
+		Actors: []string{"Old Name", "New Name", "Other"},
+		Genres: []string{"Drama", "hd", "Sample"},
+	}
+	for _, process := range processors {
+		process(info)
+	}
+	assert.Equal(t, "Title", info.Title)
+	assert.Equal(t, "S1 NO.1 STYLE", info.Maker)
+	assert.Equal(t, "Unknown", info.Label)
+	assert.Equal(t, []string{"New Name", "Other"}, []string(info.Actors))
+	assert.Equal(t, []string{"Drama"}, []string(info.Genres))
+
+	_, err = (&Config{TitleReplacements: []*Replacement{{Pattern: `(`}}}).MovieProcessors()
+	assert.Error(t, err)
+}
+
+func TestStripStudioPrefix(t *testing.T) {
+	for _, unit := range []struct {
+		title, maker, label, want string
+	}{
+		{"【S1】Title", "S1", "", "Title"},
+		{"[MOODYZ] Title", "moodyz", "", "Title"},
+		{"MOODYZ - Title", "", "MOODYZ", "Title"},
+		{"S1Title", "S1", "", "S1Title"},
+		{"Title", "", "", "Title"},
+		{"S1", "S1", "", "S1"},
+	} {
+		info := &model.MovieInfo{Title: unit.title, Maker: unit.maker, Label: unit.label}
+		stripStudioPrefix(info)
+		assert.Equal(t, unit.want, info.Title, unit.title)
+	}
+}
+
+func TestConfig_ActorProcessors(t *testing.T) {
+	assert.Nil(t, (&Config{}).ActorProcessors())
+
+	processors := (&Config{ActorAliases: map[string]string{"Old Name": "New Name"}}).ActorProcessors()
+	info := &model.ActorInfo{Name: "Old Name"}
+	for _, process := range processors {
+		process(info)
+	}
+	assert.Equal(t, "New Name", info.Name)
+	assert.Equal(t, []string{"Old Name"}, []string(info.Aliases))
+}
@@ -10,6 +10,7 @@ import (
 var (
 	_ provider.MovieProvider = (*TenMusume)(nil)
 	_ provider.MovieReviewer = (*TenMusume)(nil)
+	_ provider.MovieEnricher = (*TenMusume)(nil)
 )
 
 const (
@@ -61,4 +62,5 @@ func (mse *TenMusume) NormalizeMovieID(id string) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.10musume.com", Path: "/movies/"})
 }
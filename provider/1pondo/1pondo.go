@@ -10,6 +10,7 @@ import (
 var (
 	_ provider.MovieProvider = (*OnePondo)(nil)
 	_ provider.MovieReviewer = (*OnePondo)(nil)
+	_ provider.MovieEnricher = (*OnePondo)(nil)
 	_ provider.Fetcher       = (*OnePondo)(nil)
 )
 
@@ -62,4 +63,5 @@ func (opd *OnePondo) NormalizeMovieID(id string) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.1pondo.tv", Path: "/movies/"})
 }
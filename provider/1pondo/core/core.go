@@ -15,6 +15,8 @@ import (
 
 	"github.com/metatube-community/metatube-sdk-go/common/parser"
 	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/internal/d2pass"
 	"github.com/metatube-community/metatube-sdk-go/provider/internal/scraper"
 )
 
@@ -57,6 +59,10 @@ func (core *Core) Init() *Core {
 		}),
 		scraper.WithDisableCookies(),
 		scraper.WithTransport(t), // Set custom HTTP transport.
+		scraper.WithLogin(d2pass.Login),
+		// API responses are far smaller than pages.
+		scraper.WithMaxResponseSize(2<<20),
+		scraper.WithContentTypes("application/json", "text/*"),
 	)
 	return core
 }
@@ -64,6 +70,7 @@ func (core *Core) Init() *Core {
 func (core *Core) Fetch(url string) (resp *http.Response, err error) {
 	return (&http.Client{
 		Transport: http.DefaultTransport.(*http.Transport).Clone(),
+		Jar:       core.MemberCookieJar(),
 		Timeout:   15 * time.Second,
 	}).Get(url)
 }
@@ -229,58 +236,16 @@ func (core *Core) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 			// this.hasGallery = !0 : this.movieDetail.HasGallery && (this.hasGallery = !0, this.legacyGallery = !0),
 			// e.getMovieGallery(this.movieDetail.MovieID, this.legacyGallery);
 			// Preview Images
-			if data.Gallery && core.GalleryPath != "" {
-				d := c.Clone()
-				d.OnResponse(func(r *colly.Response) {
-					galleries := struct {
-						Rows []struct {
-							Img       string
-							Protected bool
-						}
-					}{}
-					if json.Unmarshal(r.Body, &galleries) == nil {
-						//for (var c = 0; c < this.gallery.Rows.length; c += 1) {
-						//   this.$set(this.gallery.Rows[c], "idx", c);
-						//   var u = !1;
-						//   (!t || t && i) && (u = !0);
-						//   var d = u || !this.gallery.Rows[c].Protected;
-						//   this.$set(this.gallery.Rows[c], "canViewFull", d);
-						//   var v = "/dyn/dla/images/".concat(this.gallery.Rows[c].Img)
-						//	 , f = "".concat(a, "/dyn/dla/images/").concat(this.gallery.Rows[c].Img);
-						//   this.$set(this.gallery.Rows[c], "PreviewURL", v.replace("member", "sample").replace(/\.jpg/, "__@120.jpg")),
-						//   this.$set(this.gallery.Rows[c], "FullsizeURL", f),
-						//   this.gallery.Rows[c].Protected && d && i && this.$set(this.gallery.Rows[c], "FullsizeURL", f += "?m=".concat(i))
-						//}
-						for _, row := range galleries.Rows {
-							if !row.Protected {
-								info.PreviewImages = append(info.PreviewImages,
-									r.Request.AbsoluteURL(fmt.Sprintf(core.GalleryPath, row.Img)))
-							}
-						}
-					}
-				})
-				d.Visit(r.Request.AbsoluteURL(fmt.Sprintf(movieGalleryPath, id)))
+			if core.IsLazy(provider.PreviewImagesField) {
+				// resolved by EnrichMovieInfo.
+			} else if data.Gallery && core.GalleryPath != "" {
+				d, cancel := core.SubCollector(c)
+				defer cancel()
+				core.visitGallery(d, r.Request.AbsoluteURL(fmt.Sprintf(movieGalleryPath, id)), info)
 			} else if data.HasGallery /* Legacy Gallery */ && core.LegacyGalleryPath != "" {
-				d := c.Clone()
-				d.OnResponse(func(r *colly.Response) {
-					galleries := struct {
-						Rows []struct {
-							MovieID   string
-							Filename  string
-							Protected bool
-						}
-					}{}
-					if json.Unmarshal(r.Body, &galleries) == nil {
-						for _, row := range galleries.Rows {
-							if !row.Protected {
-								info.PreviewImages = append(info.PreviewImages,
-									r.Request.AbsoluteURL(fmt.Sprintf(core.LegacyGalleryPath,
-										row.MovieID, row.Filename)))
-							}
-						}
-					}
-				})
-				d.Visit(r.Request.AbsoluteURL(fmt.Sprintf(movieLegacyGalleryPath, id)))
+				d, cancel := core.SubCollector(c)
+				defer cancel()
+				core.visitLegacyGallery(d, r.Request.AbsoluteURL(fmt.Sprintf(movieLegacyGalleryPath, id)), info)
 			}
 		}
 	})
@@ -291,6 +256,79 @@ func (core *Core) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 	return
 }
 
+// EnrichMovieInfo resolves the preview images of the gallery, which are
+// skipped by getting movie infos if lazy. The gallery is looked up first,
+// and then the legacy one.
+func (core *Core) EnrichMovieInfo(info *model.MovieInfo, fields ...provider.Field) error {
+	if !scraper.EnrichesField(fields, provider.PreviewImagesField) || len(info.PreviewImages) > 0 {
+		return nil
+	}
+	c := core.ClonedCollector()
+	if core.GalleryPath != "" {
+		_ = core.visitGallery(c.Clone(), urlJoin(info.Homepage, fmt.Sprintf(movieGalleryPath, info.ID)), info)
+	}
+	if len(info.PreviewImages) == 0 && core.LegacyGalleryPath != "" {
+		_ = core.visitLegacyGallery(c.Clone(), urlJoin(info.Homepage, fmt.Sprintf(movieLegacyGalleryPath, info.ID)), info)
+	}
+	return nil
+}
+
+// visitGallery appends the gallery images to the preview images.
+func (core *Core) visitGallery(d *colly.Collector, galleryURL string, info *model.MovieInfo) error {
+	d.OnResponse(func(r *colly.Response) {
+		galleries := struct {
+			Rows []struct {
+				Img       string
+				Protected bool
+			}
+		}{}
+		if json.Unmarshal(r.Body, &galleries) == nil {
+			//for (var c = 0; c < this.gallery.Rows.length; c += 1) {
+			//   this.$set(this.gallery.Rows[c], "idx", c);
+			//   var u = !1;
+			//   (!t || t && i) && (u = !0);
+			//   var d = u || !this.gallery.Rows[c].Protected;
+			//   this.$set(this.gallery.Rows[c], "canViewFull", d);
+			//   var v = "/dyn/dla/images/".concat(this.gallery.Rows[c].Img)
+			//	 , f = "".concat(a, "/dyn/dla/images/").concat(this.gallery.Rows[c].Img);
+			//   this.$set(this.gallery.Rows[c], "PreviewURL", v.replace("member", "sample").replace(/\.jpg/, "__@120.jpg")),
+			//   this.$set(this.gallery.Rows[c], "FullsizeURL", f),
+			//   this.gallery.Rows[c].Protected && d && i && this.$set(this.gallery.Rows[c], "FullsizeURL", f += "?m=".concat(i))
+			//}
+			for _, row := range galleries.Rows {
+				if !row.Protected || core.IsMember() /* full gallery */ {
+					info.PreviewImages = append(info.PreviewImages,
+						r.Request.AbsoluteURL(fmt.Sprintf(core.GalleryPath, row.Img)))
+				}
+			}
+		}
+	})
+	return d.Visit(galleryURL)
+}
+
+// visitLegacyGallery appends the legacy gallery images to the preview images.
+func (core *Core) visitLegacyGallery(d *colly.Collector, galleryURL string, info *model.MovieInfo) error {
+	d.OnResponse(func(r *colly.Response) {
+		galleries := struct {
+			Rows []struct {
+				MovieID   string
+				Filename  string
+				Protected bool
+			}
+		}{}
+		if json.Unmarshal(r.Body, &galleries) == nil {
+			for _, row := range galleries.Rows {
+				if !row.Protected || core.IsMember() /* full gallery */ {
+					info.PreviewImages = append(info.PreviewImages,
+						r.Request.AbsoluteURL(fmt.Sprintf(core.LegacyGalleryPath,
+							row.MovieID, row.Filename)))
+				}
+			}
+		}
+	})
+	return d.Visit(galleryURL)
+}
+
 var urlParser = url.NewParser(url.WithPercentEncodeSinglePercentSign())
 
 func urlJoin(url, path string) string {
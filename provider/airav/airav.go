@@ -43,7 +43,10 @@ func New() *AirAV {
 			scraper.WithHeaders(map[string]string{
 				"Origin":  baseURL,
 				"Referer": baseURL,
-			})),
+			}),
+			// API responses are far smaller than pages.
+			scraper.WithMaxResponseSize(2<<20),
+			scraper.WithContentTypes("application/json", "text/*")),
 	}
 }
 
@@ -132,7 +135,8 @@ func (air *AirAV) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 			if data.Result.VideoURL.URLCDN != "" {
 				info.PreviewVideoURL = data.Result.VideoURL.URLCDN
 			} else {
-				d := c.Clone()
+				d, cancel := air.SubCollector(c)
+				defer cancel()
 				d.OnResponse(func(r *colly.Response) {
 					videoData := struct {
 						Data struct {
@@ -230,4 +234,5 @@ func ParseNumber(s string) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.airav.wiki", Path: "/video/"})
 }
@@ -19,9 +19,10 @@ import (
 )
 
 var (
-	_ provider.MovieProvider = (*ARZON)(nil)
-	_ provider.MovieSearcher = (*ARZON)(nil)
-	_ provider.Fetcher       = (*ARZON)(nil)
+	_ provider.MovieProvider     = (*ARZON)(nil)
+	_ provider.MovieSearcher     = (*ARZON)(nil)
+	_ provider.Fetcher           = (*ARZON)(nil)
+	_ provider.FetchClientSetter = (*ARZON)(nil)
 )
 
 const (
@@ -48,6 +49,12 @@ func New() *ARZON {
 	}
 }
 
+// SetFetchClient sets the client of both pages and media resources.
+func (az *ARZON) SetFetchClient(client fetch.Client) {
+	az.Fetcher.SetClient(client)
+	az.Scraper.SetFetchClient(client)
+}
+
 func (az *ARZON) GetMovieInfoByID(id string) (info *model.MovieInfo, err error) {
 	return az.GetMovieInfoByURL(fmt.Sprintf(movieURL, id))
 }
@@ -166,6 +173,8 @@ func (az *ARZON) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err er
 		}
 	})
 
+	az.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
@@ -221,4 +230,5 @@ func (az *ARZON) SearchMovie(keyword string) (results []*model.MovieSearchResult
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.arzon.jp", Path: "/item_"})
 }
@@ -385,4 +385,5 @@ type Actor struct {
 func init() {
 	// The stability of this provider is still unknown.
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.avbase.net", Path: "/works/"})
 }
@@ -38,7 +38,8 @@ type AVE struct {
 }
 
 func New() *AVE {
-	return &AVE{scraper.NewDefaultScraper(Name, baseURL, Priority)}
+	return &AVE{scraper.NewDefaultScraper(Name, baseURL, Priority,
+		scraper.WithContentTypes(scraper.HTMLContentTypes...))}
 }
 
 func (ave *AVE) GetMovieInfoByID(id string) (info *model.MovieInfo, err error) {
@@ -156,6 +157,8 @@ func (ave *AVE) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err err
 		}
 	})
 
+	ave.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
@@ -211,4 +214,5 @@ func parseSummary(e *colly.XMLElement) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.aventertainments.com"})
 }
@@ -45,4 +45,5 @@ func (h *C0930) NormalizeMovieID(id string) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.c0930.com", Path: "/moviepages/"})
 }
@@ -47,4 +47,5 @@ func (carib *Caribbeancom) NormalizeMovieID(id string) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.caribbeancom.com"})
 }
@@ -13,10 +13,10 @@ import (
 	"github.com/antchfx/htmlquery"
 	"github.com/gocolly/colly/v2"
 	"golang.org/x/net/html"
-	dt "gorm.io/datatypes"
 
 	"github.com/metatube-community/metatube-sdk-go/common/parser"
 	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/provider/internal/d2pass"
 	"github.com/metatube-community/metatube-sdk-go/provider/internal/scraper"
 )
 
@@ -38,7 +38,8 @@ func (core *Core) Init() *Core {
 		core.DefaultName,
 		core.BaseURL,
 		core.DefaultPriority,
-		scraper.WithDetectCharset())
+		scraper.WithDetectCharset(),
+		scraper.WithLogin(d2pass.Login))
 	return core
 }
 
@@ -190,9 +191,12 @@ func (core *Core) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 					SampleMFlashURL string `json:"sample_m_flash_url"`
 				}{}
 				if json.Unmarshal([]byte(ss[1]), &data) == nil {
-					for _, sample := range []string{
-						data.SampleFlashURL, data.SampleMFlashURL,
-					} {
+					samples := []string{data.SampleFlashURL, data.SampleMFlashURL}
+					if core.IsMember() {
+						// the member sample is longer than the public one.
+						samples[0], samples[1] = samples[1], samples[0]
+					}
+					for _, sample := range samples {
 						if sample != "" {
 							info.PreviewVideoURL = e.Request.AbsoluteURL(sample)
 							break
@@ -205,7 +209,7 @@ func (core *Core) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 
 	// Preview Images
 	c.OnXML(`//div[@class="gallery-ratio"]/a`, func(e *colly.XMLElement) {
-		if href := e.Attr("href"); !strings.Contains(href, "member") {
+		if href := e.Attr("href"); !strings.Contains(href, "member") || core.IsMember() /* full gallery */ {
 			info.PreviewImages = append(info.PreviewImages, e.Request.AbsoluteURL(href))
 		}
 	})
@@ -216,7 +220,7 @@ func (core *Core) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 			if ss := regexp.MustCompile(`(\d{6})[-_]\d+`).
 				FindStringSubmatch(info.ID); len(ss) > 1 {
 				date, _ := time.Parse(`010206`, ss[1])
-				info.ReleaseDate = dt.Date(date)
+				info.ReleaseDate = model.DateOf(date)
 			}
 		}
 	})
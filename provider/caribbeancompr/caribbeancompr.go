@@ -47,4 +47,5 @@ func (carib *CaribbeancomPremium) NormalizeMovieID(id string) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.caribbeancompr.com"})
 }
@@ -33,9 +33,12 @@ func (core *Core) Init() *Core {
 		core.DefaultName,
 		core.BaseURL,
 		core.DefaultPriority,
-		scraper.WithCookies(core.BaseURL, []*http.Cookie{
-			{Name: "modal", Value: "off"},
-		}))
+		scraper.WithAgeGate(&scraper.AgeGate{
+			Cookies: []*http.Cookie{
+				{Name: "modal", Value: "off"},
+			},
+		}),
+		scraper.WithContentTypes(scraper.HTMLContentTypes...))
 	return core
 }
 
@@ -39,4 +39,5 @@ func New() *DAHLIA {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.dahlia-av.jp", Path: "/works/"})
 }
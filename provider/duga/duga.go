@@ -40,7 +40,8 @@ type DUGA struct {
 
 func New() *DUGA {
 	return &DUGA{
-		Scraper: scraper.NewDefaultScraper(Name, baseURL, Priority),
+		Scraper: scraper.NewDefaultScraper(Name, baseURL, Priority,
+			scraper.WithContentTypes(scraper.HTMLContentTypes...)),
 	}
 }
 
@@ -209,6 +210,8 @@ func (duga *DUGA) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 		}
 	})
 
+	duga.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
@@ -255,4 +258,5 @@ func (duga *DUGA) SearchMovie(keyword string) (results []*model.MovieSearchResul
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.duga.jp", Path: "/ppv/"})
 }
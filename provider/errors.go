@@ -7,11 +7,16 @@ import (
 )
 
 var (
-	ErrInvalidID          = errors.New(http.StatusBadRequest, "invalid id")
-	ErrInvalidURL         = errors.New(http.StatusBadRequest, "invalid url")
-	ErrInvalidKeyword     = errors.New(http.StatusBadRequest, "invalid keyword")
-	ErrInfoNotFound       = errors.New(http.StatusNotFound, "info not found")
-	ErrImageNotFound      = errors.New(http.StatusNotFound, "image not found")
-	ErrProviderNotFound   = errors.New(http.StatusNotFound, "provider not found")
-	ErrIncompleteMetadata = errors.New(http.StatusInternalServerError, "incomplete metadata")
+	ErrInvalidID            = errors.New(http.StatusBadRequest, "invalid id")
+	ErrInvalidURL           = errors.New(http.StatusBadRequest, "invalid url")
+	ErrInvalidKeyword       = errors.New(http.StatusBadRequest, "invalid keyword")
+	ErrInvalidLanguage      = errors.New(http.StatusBadRequest, "invalid language")
+	ErrInvalidField         = errors.New(http.StatusBadRequest, "invalid field")
+	ErrInfoNotFound         = errors.New(http.StatusNotFound, "info not found")
+	ErrImageNotFound        = errors.New(http.StatusNotFound, "image not found")
+	ErrProviderNotFound     = errors.New(http.StatusNotFound, "provider not found")
+	ErrIncompleteMetadata   = errors.New(http.StatusInternalServerError, "incomplete metadata")
+	ErrSnapshotNotSupported = errors.New(http.StatusNotImplemented, "snapshot not supported")
+	ErrOfflineNotSupported  = errors.New(http.StatusNotImplemented, "offline parsing not supported")
+	ErrProviderTimeout      = errors.New(http.StatusGatewayTimeout, "provider timeout")
 )
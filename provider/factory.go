@@ -4,6 +4,8 @@ import (
 	"sync"
 )
 
+// Factories must return a new provider instance on each call, and the
+// registries are safe for concurrent use.
 type (
 	MovieFactory = func() MovieProvider
 	ActorFactory = func() ActorProvider
@@ -0,0 +1,373 @@
+// Package fake implements a deterministic provider of movies and actors
+// without network access, for integration tests of applications built on
+// the engine or the server. Import it to register the provider:
+//
+//	import _ "github.com/metatube-community/metatube-sdk-go/provider/fake"
+//
+// Infos of seed data are served as is, and the others are generated from
+// their IDs, i.e., movies of `FAKE-<n>` and actors of `<n>`. Images are
+// generated by Fetch, so URLs of the provider never hit the network.
+package fake
+
+import (
+	"bytes"
+	"fmt"
+	"hash/fnv"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+var (
+	_ provider.MovieProvider = (*Fake)(nil)
+	_ provider.MovieSearcher = (*Fake)(nil)
+	_ provider.ActorProvider = (*Fake)(nil)
+	_ provider.ActorSearcher = (*Fake)(nil)
+	_ provider.Fetcher       = (*Fake)(nil)
+)
+
+const (
+	Name     = "Fake"
+	Priority = 1
+)
+
+const (
+	baseURL        = "https://fake.metatube.invalid/"
+	movieURL       = baseURL + "movies/%s"
+	actorURL       = baseURL + "actors/%s"
+	movieImageURL  = baseURL + "images/movies/%s/%s.jpg"
+	actorImageURL  = baseURL + "images/actors/%s.jpg"
+	actorName      = "Fake Actor %d"
+	generatedCount = 999
+)
+
+var (
+	_baseURL      = mustParse(baseURL)
+	movieIDRegex  = regexp.MustCompile(`^(?i)FAKE-?(\d{1,3})$`)
+	actorNameExpr = regexp.MustCompile(`^(?i)Fake Actor (\d{1,3})$`)
+	releaseEpoch  = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// Seed is the data served by the provider, infos must have their IDs set
+// and the other fields are served as is.
+type Seed struct {
+	Movies []*model.MovieInfo `json:"movies"`
+	Actors []*model.ActorInfo `json:"actors"`
+}
+
+var (
+	seedMu sync.RWMutex
+	seed   = &Seed{}
+)
+
+// SetSeed sets the seed data of providers created afterward, it must be
+// called before the engine is created.
+func SetSeed(s *Seed) {
+	seedMu.Lock()
+	defer seedMu.Unlock()
+	seed = s
+}
+
+type Fake struct {
+	movies map[string]*model.MovieInfo
+	actors map[string]*model.ActorInfo
+}
+
+func New() *Fake {
+	seedMu.RLock()
+	defer seedMu.RUnlock()
+	f := &Fake{
+		movies: make(map[string]*model.MovieInfo),
+		actors: make(map[string]*model.ActorInfo),
+	}
+	for _, info := range seed.Movies {
+		f.movies[strings.ToUpper(info.ID)] = f.completeMovieInfo(cloneMovieInfo(info))
+	}
+	for _, info := range seed.Actors {
+		f.actors[strings.ToUpper(info.ID)] = f.completeActorInfo(cloneActorInfo(info))
+	}
+	return f
+}
+
+func (f *Fake) Name() string { return Name }
+
+func (f *Fake) Priority() int { return Priority }
+
+func (f *Fake) URL() *url.URL { return _baseURL }
+
+func (f *Fake) NormalizeMovieID(id string) string {
+	if ss := movieIDRegex.FindStringSubmatch(id); len(ss) == 2 {
+		n, _ := strconv.Atoi(ss[1])
+		return fmt.Sprintf("FAKE-%03d", n)
+	}
+	return strings.ToUpper(id)
+}
+
+func (f *Fake) ParseMovieIDFromURL(rawURL string) (string, error) {
+	return parseIDFromURL(rawURL, "/movies/")
+}
+
+func (f *Fake) GetMovieInfoByID(id string) (*model.MovieInfo, error) {
+	id = f.NormalizeMovieID(id)
+	if info, ok := f.movies[id]; ok {
+		return cloneMovieInfo(info), nil
+	}
+	n, ok := movieNumber(id)
+	if !ok {
+		return nil, provider.ErrInfoNotFound
+	}
+	h := hash(id)
+	info := &model.MovieInfo{
+		ID:            id,
+		Number:        id,
+		Title:         fmt.Sprintf("Fake Movie %03d", n),
+		Summary:       fmt.Sprintf("The deterministic fake movie %s for testing.", id),
+		Director:      fmt.Sprintf("Fake Director %d", h%7+1),
+		Actors:        []string{fmt.Sprintf(actorName, n%50+1), fmt.Sprintf(actorName, (n+h)%50+1)},
+		PreviewImages: []string{fmt.Sprintf(movieImageURL, id, "preview-1"), fmt.Sprintf(movieImageURL, id, "preview-2")},
+		Maker:         "Fake Studio",
+		Label:         fmt.Sprintf("Fake Label %d", h%3+1),
+		Series:        fmt.Sprintf("Fake Series %d", n%10+1),
+		Genres:        []string{"Fake", fmt.Sprintf("Genre %d", h%5+1)},
+		Score:         float64(h%50) / 10,
+		Runtime:       60 + n%90,
+		ReleaseDate:   datatypes.Date(releaseEpoch.AddDate(0, 0, n)),
+	}
+	if info.Actors[0] == info.Actors[1] {
+		info.Actors = info.Actors[:1]
+	}
+	for i, url := range info.PreviewImages {
+		info.SetImageCaption(url, fmt.Sprintf("Fake Scene %d", i+1))
+	}
+	return f.completeMovieInfo(info), nil
+}
+
+// completeMovieInfo fills the provider, homepage and images if missing.
+func (f *Fake) completeMovieInfo(info *model.MovieInfo) *model.MovieInfo {
+	info.Provider = Name
+	if info.Number == "" {
+		info.Number = info.ID
+	}
+	if info.Homepage == "" {
+		info.Homepage = fmt.Sprintf(movieURL, info.ID)
+	}
+	if info.CoverURL == "" {
+		info.CoverURL = fmt.Sprintf(movieImageURL, info.ID, "cover")
+	}
+	if info.ThumbURL == "" {
+		info.ThumbURL = fmt.Sprintf(movieImageURL, info.ID, "thumb")
+	}
+	return info
+}
+
+func (f *Fake) GetMovieInfoByURL(rawURL string) (*model.MovieInfo, error) {
+	id, err := f.ParseMovieIDFromURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return f.GetMovieInfoByID(id)
+}
+
+func (f *Fake) NormalizeMovieKeyword(keyword string) string {
+	return strings.ToUpper(strings.TrimSpace(keyword))
+}
+
+func (f *Fake) SearchMovie(keyword string) (results []*model.MovieSearchResult, err error) {
+	keyword = f.NormalizeMovieKeyword(keyword)
+	for _, info := range f.movies {
+		if strings.Contains(strings.ToUpper(info.Number), keyword) ||
+			strings.Contains(strings.ToUpper(info.Title), keyword) {
+			results = append(results, cloneMovieInfo(info).ToSearchResult())
+		}
+	}
+	// deterministic order of seed data.
+	slices.SortFunc(results, func(a, b *model.MovieSearchResult) int { return strings.Compare(a.ID, b.ID) })
+	if id := f.NormalizeMovieID(keyword); f.movies[id] == nil {
+		if info, err := f.GetMovieInfoByID(id); err == nil {
+			results = append(results, info.ToSearchResult())
+		}
+	}
+	if len(results) == 0 {
+		return nil, provider.ErrInfoNotFound
+	}
+	return
+}
+
+func (f *Fake) NormalizeActorID(id string) string { return strings.ToUpper(id) }
+
+func (f *Fake) ParseActorIDFromURL(rawURL string) (string, error) {
+	return parseIDFromURL(rawURL, "/actors/")
+}
+
+func (f *Fake) GetActorInfoByID(id string) (*model.ActorInfo, error) {
+	id = f.NormalizeActorID(id)
+	if info, ok := f.actors[id]; ok {
+		return cloneActorInfo(info), nil
+	}
+	n, err := strconv.Atoi(id)
+	if err != nil || n < 1 || n > generatedCount {
+		return nil, provider.ErrInfoNotFound
+	}
+	h := hash(id)
+	return f.completeActorInfo(&model.ActorInfo{
+		ID:          id,
+		Name:        fmt.Sprintf(actorName, n),
+		Summary:     fmt.Sprintf("The deterministic fake actor %d for testing.", n),
+		Nationality: "Fakeland",
+		Height:      150 + h%30,
+		Aliases:     []string{fmt.Sprintf("Actor %d", n)},
+		Birthday:    datatypes.Date(releaseEpoch.AddDate(-20-h%10, 0, n)),
+		DebutDate:   datatypes.Date(releaseEpoch.AddDate(0, 0, -n)),
+	}), nil
+}
+
+// completeActorInfo fills the provider, homepage and images if missing.
+func (f *Fake) completeActorInfo(info *model.ActorInfo) *model.ActorInfo {
+	info.Provider = Name
+	if info.Homepage == "" {
+		info.Homepage = fmt.Sprintf(actorURL, info.ID)
+	}
+	if len(info.Images) == 0 {
+		info.Images = []string{fmt.Sprintf(actorImageURL, info.ID)}
+	}
+	return info
+}
+
+func (f *Fake) GetActorInfoByURL(rawURL string) (*model.ActorInfo, error) {
+	id, err := f.ParseActorIDFromURL(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return f.GetActorInfoByID(id)
+}
+
+func (f *Fake) SearchActor(keyword string) (results []*model.ActorSearchResult, err error) {
+	keyword = strings.TrimSpace(keyword)
+	for _, info := range f.actors {
+		if strings.EqualFold(info.Name, keyword) {
+			results = append(results, cloneActorInfo(info).ToSearchResult())
+		}
+	}
+	slices.SortFunc(results, func(a, b *model.ActorSearchResult) int { return strings.Compare(a.ID, b.ID) })
+	if ss := actorNameExpr.FindStringSubmatch(keyword); len(ss) == 2 {
+		if info, err := f.GetActorInfoByID(ss[1]); err == nil && f.actors[info.ID] == nil {
+			results = append(results, info.ToSearchResult())
+		}
+	}
+	if len(results) == 0 {
+		return nil, provider.ErrInfoNotFound
+	}
+	return
+}
+
+// Fetch generates the image of the URL, whose color is derived from the
+// URL, so the same URL always gets the same image.
+func (f *Fake) Fetch(rawURL string) (*http.Response, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host != _baseURL.Host || path.Ext(u.Path) != ".jpg" {
+		return nil, provider.ErrImageNotFound
+	}
+	width, height := 800, 538 // landscape covers.
+	switch base := path.Base(u.Path); {
+	case strings.HasPrefix(u.Path, "/images/actors/"):
+		width, height = 300, 400
+	case base == "thumb.jpg":
+		width, height = 378, 538
+	}
+	h := hash(u.Path)
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	c := color.RGBA{R: uint8(h), G: uint8(h >> 8), B: uint8(h >> 16), A: 0xff}
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, c)
+		}
+	}
+	var buf bytes.Buffer
+	if err = jpeg.Encode(&buf, img, nil); err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        "200 OK",
+		StatusCode:    http.StatusOK,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"image/jpeg"}},
+		Body:          io.NopCloser(&buf),
+		ContentLength: int64(buf.Len()),
+		Request:       &http.Request{Method: http.MethodGet, URL: u},
+	}, nil
+}
+
+// cloneMovieInfo copies the info, so that seed data is never modified by
+// callers.
+func cloneMovieInfo(info *model.MovieInfo) *model.MovieInfo {
+	c := *info
+	c.Actors = slices.Clone(info.Actors)
+	c.PreviewImages = slices.Clone(info.PreviewImages)
+	c.Genres = slices.Clone(info.Genres)
+	c.ImageCaptions = maps.Clone(info.ImageCaptions)
+	return &c
+}
+
+func cloneActorInfo(info *model.ActorInfo) *model.ActorInfo {
+	c := *info
+	c.Aliases = slices.Clone(info.Aliases)
+	c.Images = slices.Clone(info.Images)
+	return &c
+}
+
+func movieNumber(id string) (int, bool) {
+	ss := movieIDRegex.FindStringSubmatch(id)
+	if len(ss) != 2 {
+		return 0, false
+	}
+	n, _ := strconv.Atoi(ss[1])
+	return n, n >= 1 && n <= generatedCount
+}
+
+func parseIDFromURL(rawURL, prefix string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if u.Host != _baseURL.Host || !strings.HasPrefix(u.Path, prefix) {
+		return "", provider.ErrInvalidURL
+	}
+	return path.Base(u.Path), nil
+}
+
+func hash(s string) int {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return int(h.Sum32() & 0x7fffffff)
+}
+
+func mustParse(rawURL string) *url.URL {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+func init() {
+	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterActorFactory(Name, New)
+}
@@ -0,0 +1,86 @@
+package fake
+
+import (
+	"image"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+func TestFake_GetMovieInfoByID(t *testing.T) {
+	f := New()
+	info, err := f.GetMovieInfoByID("fake-7")
+	if assert.NoError(t, err) {
+		assert.True(t, info.Valid())
+		assert.Equal(t, "FAKE-007", info.Number)
+		// generated infos are deterministic.
+		again, _ := New().GetMovieInfoByID("FAKE-007")
+		assert.Equal(t, info, again)
+	}
+	_, err = f.GetMovieInfoByID("ABC-123")
+	assert.ErrorIs(t, err, provider.ErrInfoNotFound)
+
+	info, err = f.GetMovieInfoByURL("https://fake.metatube.invalid/movies/FAKE-007")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "FAKE-007", info.ID)
+	}
+}
+
+func TestFake_Seed(t *testing.T) {
+	SetSeed(&Seed{
+		Movies: []*model.MovieInfo{{ID: "SEED-001", Title: "Seeded", Actors: []string{"Seed Actor"}}},
+		Actors: []*model.ActorInfo{{ID: "seed", Name: "Seed Actor"}},
+	})
+	defer SetSeed(&Seed{})
+
+	f := New()
+	info, err := f.GetMovieInfoByID("SEED-001")
+	if assert.NoError(t, err) {
+		assert.True(t, info.Valid())
+		assert.Equal(t, "Seeded", info.Title)
+		info.Actors[0] = "modified"
+	}
+	// seed data is never modified by callers.
+	info, _ = f.GetMovieInfoByID("SEED-001")
+	assert.Equal(t, "Seed Actor", info.Actors[0])
+
+	results, err := f.SearchMovie("seeded")
+	if assert.NoError(t, err) && assert.Len(t, results, 1) {
+		assert.Equal(t, "SEED-001", results[0].ID)
+	}
+	actors, err := f.SearchActor("Seed Actor")
+	if assert.NoError(t, err) && assert.Len(t, actors, 1) {
+		assert.Equal(t, "seed", actors[0].ID)
+	}
+}
+
+func TestFake_Actor(t *testing.T) {
+	f := New()
+	results, err := f.SearchActor("Fake Actor 3")
+	if assert.NoError(t, err) && assert.Len(t, results, 1) {
+		info, err := f.GetActorInfoByID(results[0].ID)
+		assert.NoError(t, err)
+		assert.True(t, info.Valid())
+		assert.Equal(t, "Fake Actor 3", info.Name)
+	}
+	_, err = f.GetActorInfoByID("unknown")
+	assert.ErrorIs(t, err, provider.ErrInfoNotFound)
+}
+
+func TestFake_Fetch(t *testing.T) {
+	f := New()
+	info, _ := f.GetMovieInfoByID("FAKE-001")
+	resp, err := f.Fetch(info.ThumbURL)
+	if assert.NoError(t, err) {
+		defer resp.Body.Close()
+		cfg, format, err := image.DecodeConfig(resp.Body)
+		assert.NoError(t, err)
+		assert.Equal(t, "jpeg", format)
+		assert.Equal(t, 378, cfg.Width)
+	}
+	_, err = f.Fetch("https://example.com/a.jpg")
+	assert.ErrorIs(t, err, provider.ErrImageNotFound)
+}
@@ -37,4 +37,5 @@ func New() *FALENO {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.faleno.jp", Path: "/top/works/"})
 }
@@ -52,6 +52,12 @@ const (
 	movieMonoAnimeURL       = "https://www.dmm.co.jp/mono/anime/-/detail/=/cid=%s/"
 )
 
+// Extra fields of movie infos.
+const (
+	ExtraPrice         = "fanza_price"
+	ExtraPriceCurrency = "fanza_price_currency"
+)
+
 const regionNotAvailable = "not-available-in-your-region"
 
 var ErrRegionNotAvailable = errors.New(regionNotAvailable)
@@ -63,9 +69,13 @@ type FANZA struct {
 func New() *FANZA {
 	return &FANZA{
 		Scraper: scraper.NewDefaultScraper(Name, baseURL, Priority,
-			scraper.WithCookies(baseURL, []*http.Cookie{
-				{Name: "age_check_done", Value: "1"},
-			})),
+			scraper.WithAgeGate(&scraper.AgeGate{
+				Cookies: []*http.Cookie{
+					{Name: "age_check_done", Value: "1"},
+				},
+			}),
+			// DMM serves busy pages when requested too frequently.
+			scraper.WithThrottleDetector(scraper.ThrottleMarkers(30*time.Second, "アクセスが集中"))),
 	}
 }
 
@@ -201,6 +211,7 @@ func (fz *FANZA) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err er
 			AggregateRating struct {
 				RatingValue string `json:"ratingValue"`
 			} `json:"aggregateRating"`
+			Offers json.RawMessage `json:"offers"`
 		}{ /* assign default values */
 			Name:        info.Title,
 			Image:       info.ThumbURL,
@@ -222,6 +233,17 @@ func (fz *FANZA) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err er
 			if data.SubjectOf.ContentUrl != "" {
 				info.PreviewVideoURL = data.SubjectOf.ContentUrl
 			}
+			// the current price, i.e., campaign prices included.
+			offer := struct {
+				Price         json.Number `json:"price"`
+				PriceCurrency string      `json:"priceCurrency"`
+			}{}
+			if json.Unmarshal(data.Offers, &offer) == nil {
+				if price, err := offer.Price.Float64(); err == nil && price > 0 {
+					info.Extra.Set(ExtraPrice, price)
+					info.Extra.Set(ExtraPriceCurrency, offer.PriceCurrency)
+				}
+			}
 		}
 	})
 
@@ -288,7 +310,8 @@ func (fz *FANZA) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err er
 
 	// Preview Video
 	c.OnXML(`//*[@id="detail-sample-movie"]/div/a`, func(e *colly.XMLElement) {
-		d := c.Clone()
+		d, cancel := fz.SubCollector(c)
+		defer cancel()
 		d.OnXML(`//iframe`, func(e *colly.XMLElement) {
 			d.OnResponse(func(r *colly.Response) {
 				if resp := regexp.MustCompile(`const args = (\{.+});`).FindSubmatch(r.Body); len(resp) == 2 {
@@ -300,6 +323,7 @@ func (fz *FANZA) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err er
 					}{}
 					if json.Unmarshal(resp[1], &data) == nil && len(data.Bitrates) > 0 {
 						info.PreviewVideoURL = e.Request.AbsoluteURL(data.Bitrates[0].Src)
+						info.SetFieldSource("preview_video_url", r.Request.URL.String())
 					}
 				}
 			})
@@ -311,17 +335,29 @@ func (fz *FANZA) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err er
 
 	// Preview Video (VR)
 	c.OnXML(`//*[@id="detail-sample-vr-movie"]/div/a`, func(e *colly.XMLElement) {
-		d := c.Clone()
+		info.VR = model.DefaultVRInfo() // side-by-side 180° of FANZA VR.
+		d, cancel := fz.SubCollector(c)
+		defer cancel()
 		d.OnResponse(func(r *colly.Response) {
 			sub := regexp.MustCompile(`var sampleUrl = "(.+?)";`).FindSubmatch(r.Body)
 			if len(sub) == 2 {
 				info.PreviewVideoURL = e.Request.AbsoluteURL(string(sub[1]))
+				info.SetFieldSource("preview_video_url", r.Request.URL.String())
 			}
 		})
 		d.Visit(e.Request.AbsoluteURL(regexp.MustCompile(`/(.+)/`).
 			FindString(e.Attr("onclick"))))
 	})
 
+	// Final (VR)
+	c.OnScraped(func(_ *colly.Response) {
+		if info.VR == nil {
+			return // not VR.
+		}
+		// formats stated in pages, e.g., fisheye or 200°.
+		info.VR = parseVRInfo(append([]string{info.Title, info.Summary}, info.Genres...)...)
+	})
+
 	// In case of any duplication
 	previewImageSet := orderedmap.New()
 
@@ -391,7 +427,8 @@ func (fz *FANZA) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err er
 
 		if ss := regexp.MustCompile(`url:\s*'(.+?)',`).
 			FindStringSubmatch(n.Data); len(ss) == 2 && strings.TrimSpace(ss[1]) != "" {
-			d := c.Clone()
+			d, cancel := fz.SubCollector(c)
+			defer cancel()
 			d.OnXML(`/` /* root */, func(e *colly.XMLElement) {
 				var actors []string
 				parseActors(e.DOM.(*html.Node), &actors)
@@ -403,6 +440,8 @@ func (fz *FANZA) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err er
 		}
 	})
 
+	fz.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
@@ -592,6 +631,15 @@ func ParseNumber(s string) string {
 	return s
 }
 
+// parseVRInfo parses the VR info from texts of VR pages, or the default
+// side-by-side 180° of FANZA VR if not stated.
+func parseVRInfo(texts ...string) *model.VRInfo {
+	if vr := model.ParseVRInfo(texts...); vr != nil {
+		return vr
+	}
+	return model.DefaultVRInfo()
+}
+
 // PreviewSrc maximize the preview image.
 // Ref: https://digstatic.dmm.com/js/digital/preview_jquery.js#652
 // JS Code:
@@ -631,4 +679,8 @@ func PreviewSrc(s string) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name,
+		provider.URLPattern{Host: "*.dmm.co.jp", Path: "/digital/"},
+		provider.URLPattern{Host: "*.dmm.co.jp", Path: "/mono/"},
+	)
 }
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
 )
 
 func TestFANZA_GetMovieInfoByID(t *testing.T) {
@@ -120,6 +122,15 @@ func TestParseNumber(t *testing.T) {
 	}
 }
 
+func TestParseVRInfo(t *testing.T) {
+	assert.Equal(t, model.DefaultVRInfo(), parseVRInfo("【VR】長尺", "ハイクオリティVR"))
+	assert.Equal(t, &model.VRInfo{
+		Projection: model.FisheyeProjection,
+		StereoMode: model.SideBySideStereo,
+		FOV:        200,
+	}, parseVRInfo("【VR】魚眼レンズ 200度", "VR専用"))
+}
+
 func TestPreviewSrc(t *testing.T) {
 	for _, unit := range []struct {
 		src, want string
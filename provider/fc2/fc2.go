@@ -33,7 +33,9 @@ type FC2 struct {
 }
 
 func New() *FC2 {
-	return &FC2{scraper.NewDefaultScraper(Name, baseURL, Priority)}
+	return &FC2{scraper.NewDefaultScraper(Name, baseURL, Priority,
+		// removed articles are served with 200.
+		scraper.WithSoftNotFound("お探しの商品が見つかりません"))}
 }
 
 func (fc2 *FC2) NormalizeMovieID(id string) string {
@@ -87,7 +89,8 @@ func (fc2 *FC2) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err err
 
 	// Summary
 	c.OnXML(`//section[@class="items_article_Contents"]/iframe`, func(e *colly.XMLElement) {
-		d := c.Clone()
+		d, cancel := fc2.SubCollector(c)
+		defer cancel()
 		d.OnXML(`//html/body/div`, func(e *colly.XMLElement) {
 			info.Summary = strings.TrimSpace(e.Text)
 		})
@@ -101,7 +104,10 @@ func (fc2 *FC2) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err err
 
 	// Preview Images
 	c.OnXML(`//section[@class="items_article_SampleImages"]/ul/li`, func(e *colly.XMLElement) {
-		info.PreviewImages = append(info.PreviewImages, e.Request.AbsoluteURL(e.ChildAttr(`.//a`, "href")))
+		url := e.Request.AbsoluteURL(e.ChildAttr(`.//a`, "href"))
+		info.PreviewImages = append(info.PreviewImages, url)
+		// captions of sample images, if given by sellers.
+		info.SetImageCaption(url, e.ChildAttr(`.//img`, "alt"))
 	})
 
 	// Cover (fallbacks)
@@ -130,6 +136,8 @@ func (fc2 *FC2) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err err
 	//	d.Visit(fmt.Sprintf(sampleURL, info.ID))
 	//})
 
+	fc2.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
@@ -143,4 +151,5 @@ func ParseNumber(s string) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "adult.contents.fc2.com", Path: "/article/"})
 }
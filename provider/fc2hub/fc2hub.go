@@ -204,6 +204,8 @@ func (fc2hub *FC2HUB) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, e
 		}
 	})
 
+	fc2hub.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
@@ -252,4 +254,5 @@ func removeEmpty(in []string) (out []string) {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.fc2hub.com", Path: "/video/"})
 }
@@ -0,0 +1,12 @@
+package provider
+
+// Field is an expensive field of movie infos, which is resolved by extra
+// requests, e.g., of nested playlists or gallery listings.
+type Field string
+
+const (
+	// PreviewVideoField is PreviewVideoURL and PreviewVideoHLSURL.
+	PreviewVideoField Field = "preview_video"
+	// PreviewImagesField is PreviewImages.
+	PreviewImagesField Field = "preview_images"
+)
@@ -143,7 +143,8 @@ func (gcl *Gcolle) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err
 
 	// Score
 	c.OnScraped(func(_ *colly.Response) {
-		d := c.Clone()
+		d, cancel := gcl.SubCollector(c)
+		defer cancel()
 		d.OnResponse(func(r *colly.Response) {
 			data := struct {
 				Rating float64 `json:"rating"`
@@ -155,10 +156,13 @@ func (gcl *Gcolle) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err
 		d.Visit(fmt.Sprintf(scoreURL, id))
 	})
 
+	gcl.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.gcolle.net", Path: "/product_info.php/"})
 }
@@ -34,7 +34,8 @@ type Getchu struct {
 }
 
 func New() *Getchu {
-	return &Getchu{scraper.NewDefaultScraper(Name, baseURL, Priority)}
+	return &Getchu{scraper.NewDefaultScraper(Name, baseURL, Priority,
+		scraper.WithContentTypes(scraper.HTMLContentTypes...))}
 }
 
 func (gcu *Getchu) NormalizeMovieID(id string) string {
@@ -132,10 +133,13 @@ func (gcu *Getchu) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err
 		}
 	})
 
+	gcu.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "dl.getchu.com", Path: "/i/item"})
 }
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/url"
 	"path"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/iancoleman/orderedmap"
@@ -29,9 +31,14 @@ const (
 const gFriendsID = "gfriends-id"
 
 const (
-	baseURL    = "https://github.com/gfriends/gfriends"
-	contentURL = "https://raw.githubusercontent.com/gfriends/gfriends/master/Content/%s/%s"
-	jsonURL    = "https://raw.githubusercontent.com/gfriends/gfriends/master/Filetree.json"
+	baseURL = "https://github.com/gfriends/gfriends"
+	packURL = "https://raw.githubusercontent.com/gfriends/gfriends/master/"
+)
+
+// Relative paths of the image pack.
+const (
+	contentPath = "Content/%s/%s"
+	jsonPath    = "Filetree.json"
 )
 
 var (
@@ -39,9 +46,21 @@ var (
 	_fetcher = fetch.Default(nil)
 )
 
-type GFriends struct{}
+type GFriends struct {
+	tree *fileTree
+}
+
+func New() *GFriends { return &GFriends{tree: getFileTree(packURL)} }
 
-func New() *GFriends { return &GFriends{} }
+// SetImagePackURL sets the root URL of the image pack, which must share
+// the same layout with the gfriends repository, i.e., `Filetree.json` and
+// `Content/<dir>/<file>` under the root. It must be called before use.
+func (gf *GFriends) SetImagePackURL(rawURL string) {
+	if !strings.HasSuffix(rawURL, "/") {
+		rawURL += "/"
+	}
+	gf.tree = getFileTree(rawURL)
+}
 
 func (gf *GFriends) Name() string { return Name }
 
@@ -52,7 +71,7 @@ func (gf *GFriends) URL() *url.URL { return _baseURL }
 func (gf *GFriends) NormalizeActorID(id string) string { return id /* AS IS */ }
 
 func (gf *GFriends) GetActorInfoByID(id string) (*model.ActorInfo, error) {
-	images, err := defaultFileTree.query(id)
+	images, err := gf.tree.query(id)
 	if len(images) == 0 {
 		if err != nil {
 			return nil, err
@@ -101,9 +120,27 @@ func (gf *GFriends) SearchActor(keyword string) (results []*model.ActorSearchRes
 	return
 }
 
-var defaultFileTree = newFileTree(2 * time.Hour)
+var (
+	fileTreesMu sync.Mutex
+	fileTrees   = make(map[string]*fileTree)
+)
+
+// getFileTree returns the file tree of the image pack, file trees are
+// shared by all instances with the same image pack.
+func getFileTree(rawURL string) *fileTree {
+	fileTreesMu.Lock()
+	defer fileTreesMu.Unlock()
+	ft, ok := fileTrees[rawURL]
+	if !ok {
+		ft = newFileTree(rawURL, 2*time.Hour)
+		fileTrees[rawURL] = ft
+	}
+	return ft
+}
 
 type fileTree struct {
+	url    string
+	mu     sync.RWMutex
 	single *singledo.Single
 
 	// `Content`
@@ -117,8 +154,9 @@ type fileTree struct {
 	//} `json:"Information"`
 }
 
-func newFileTree(wait time.Duration) *fileTree {
+func newFileTree(rawURL string, wait time.Duration) *fileTree {
 	return &fileTree{
+		url:     rawURL,
 		single:  singledo.NewSingle(wait),
 		Content: orderedmap.New(),
 	}
@@ -131,13 +169,15 @@ func (ft *fileTree) query(s string) (images []string, err error) {
 		return nil, nil
 	})
 	// query
+	ft.mu.RLock()
+	defer ft.mu.RUnlock()
 	for _, c := range ft.Content.Keys() {
 		if o, ok := ft.Content.Get(c); ok {
 			am := o.(orderedmap.OrderedMap)
 			for _, n := range am.Keys() {
 				if n[:len(n)-len(path.Ext(n))] == s /* exact match */ {
 					p, _ := am.Get(n)
-					if u, e := url.Parse(fmt.Sprintf(contentURL, c, p.(string))); e == nil {
+					if u, e := url.Parse(ft.url + fmt.Sprintf(contentPath, c, p.(string))); e == nil {
 						images = append(images, u.String())
 					}
 				}
@@ -149,12 +189,23 @@ func (ft *fileTree) query(s string) (images []string, err error) {
 }
 
 func (ft *fileTree) update() error {
-	resp, err := _fetcher.Fetch(jsonURL)
+	resp, err := _fetcher.Fetch(ft.url + jsonPath)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
-	return json.NewDecoder(resp.Body).Decode(ft)
+	var tree struct {
+		Content *orderedmap.OrderedMap `json:"Content"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&tree); err != nil {
+		return err
+	}
+	ft.mu.Lock()
+	defer ft.mu.Unlock()
+	if tree.Content != nil {
+		ft.Content = tree.Content
+	}
+	return nil
 }
 
 func mustParse(rawURL string) *url.URL {
@@ -2,6 +2,8 @@ package gfriends
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -50,3 +52,21 @@ func TestGFriends_SearchActor(t *testing.T) {
 		t.Logf("%s", data)
 	}
 }
+
+func TestGFriends_SetImagePackURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/pack/Filetree.json" {
+			w.Write([]byte(`{"Content":{"z-Custom":{"test.jpg":"test.jpg?t=1"}}}`))
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	provider := New()
+	provider.SetImagePackURL(srv.URL + "/pack")
+	info, err := provider.GetActorInfoByID("test")
+	if assert.NoError(t, err) {
+		assert.Equal(t, []string{srv.URL + "/pack/Content/z-Custom/test.jpg?t=1"}, []string(info.Images))
+	}
+}
@@ -34,7 +34,8 @@ func (core *Core) Init() *Core {
 		core.DefaultName,
 		core.BaseURL,
 		core.DefaultPriority,
-		scraper.WithDetectCharset())
+		scraper.WithDetectCharset(),
+		scraper.WithContentTypes(scraper.HTMLContentTypes...))
 	return core
 }
 
@@ -45,4 +45,5 @@ func (h *H0930) NormalizeMovieID(id string) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.h0930.com", Path: "/moviepages/"})
 }
@@ -45,4 +45,5 @@ func (h *H4610) NormalizeMovieID(id string) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.h4610.com", Path: "/moviepages/"})
 }
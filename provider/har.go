@@ -0,0 +1,285 @@
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/metatube-community/metatube-sdk-go/internal/version"
+)
+
+// redactedHARHeaders are headers of credentials, which are redacted from
+// HAR files as they're shared.
+var redactedHARHeaders = []string{"Authorization", "Cookie", "Proxy-Authorization", "Set-Cookie"}
+
+// redactedHARParams are names of query params and fields of request bodies
+// holding credentials, which are matched case-insensitively, and the ones
+// containing redactedHARParamParts are redacted as well, e.g., "passwd".
+var (
+	redactedHARParams     = []string{"apikey", "api_key", "auth", "code", "key", "otp", "session", "sessionid", "sid", "sig", "signature"}
+	redactedHARParamParts = []string{"credential", "pass", "secret", "token"}
+)
+
+// redactedHARPaths are parts of the paths of login pages, whose request
+// bodies are redacted as a whole.
+var redactedHARPaths = []string{"auth", "login", "logon", "signin", "sign_in", "sign-in"}
+
+const harRedacted = "REDACTED"
+
+// HAR types in the subset of HAR 1.2 written, see
+// http://www.softwareishard.com/blog/har-12-spec/.
+type (
+	harLog struct {
+		Version string      `json:"version"`
+		Creator harCreator  `json:"creator"`
+		Entries []*harEntry `json:"entries"`
+	}
+	harCreator struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+	}
+	harEntry struct {
+		StartedDateTime string      `json:"startedDateTime"`
+		Time            float64     `json:"time"`
+		Request         harRequest  `json:"request"`
+		Response        harResponse `json:"response"`
+		Cache           struct{}    `json:"cache"`
+		Timings         harTimings  `json:"timings"`
+		Error           string      `json:"_error,omitempty"`
+	}
+	harRequest struct {
+		Method      string       `json:"method"`
+		URL         string       `json:"url"`
+		HTTPVersion string       `json:"httpVersion"`
+		Cookies     []struct{}   `json:"cookies"`
+		Headers     []harNVP     `json:"headers"`
+		QueryString []harNVP     `json:"queryString"`
+		PostData    *harPostData `json:"postData,omitempty"`
+		HeadersSize int          `json:"headersSize"`
+		BodySize    int          `json:"bodySize"`
+	}
+	harResponse struct {
+		Status      int        `json:"status"`
+		StatusText  string     `json:"statusText"`
+		HTTPVersion string     `json:"httpVersion"`
+		Cookies     []struct{} `json:"cookies"`
+		Headers     []harNVP   `json:"headers"`
+		Content     harContent `json:"content"`
+		RedirectURL string     `json:"redirectURL"`
+		HeadersSize int        `json:"headersSize"`
+		BodySize    int        `json:"bodySize"`
+	}
+	harNVP struct {
+		Name  string `json:"name"`
+		Value string `json:"value"`
+	}
+	harPostData struct {
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text"`
+	}
+	harContent struct {
+		Size     int    `json:"size"`
+		MimeType string `json:"mimeType"`
+		Text     string `json:"text,omitempty"`
+		Encoding string `json:"encoding,omitempty"`
+	}
+	harTimings struct {
+		Send    float64 `json:"send"`
+		Wait    float64 `json:"wait"`
+		Receive float64 `json:"receive"`
+	}
+)
+
+// MarshalHAR encodes the responses of the snapshot as a HAR file, e.g., to
+// share reproductions of blocking or parsing issues, or to build fixtures.
+// Credentials in headers, query params and request bodies are redacted, and
+// binary bodies are encoded in base64.
+func (s *Snapshot) MarshalHAR() ([]byte, error) {
+	log := harLog{
+		Version: "1.2",
+		Creator: harCreator{Name: "metatube-sdk-go", Version: version.Version},
+		Entries: make([]*harEntry, 0, len(s.Responses)),
+	}
+	for _, r := range s.Responses {
+		elapsed := float64(r.Elapsed) / float64(time.Millisecond)
+		entry := &harEntry{
+			StartedDateTime: r.StartedAt.Format(time.RFC3339Nano),
+			Time:            elapsed,
+			Request: harRequest{
+				Method:      r.Method,
+				URL:         harURL(r.URL),
+				HTTPVersion: "HTTP/1.1",
+				Cookies:     []struct{}{},
+				Headers:     harHeaders(r.RequestHeader),
+				QueryString: harQuery(r.URL),
+				HeadersSize: -1,
+				BodySize:    len(r.RequestBody),
+			},
+			Response: harResponse{
+				Status:      r.StatusCode,
+				StatusText:  http.StatusText(r.StatusCode),
+				HTTPVersion: "HTTP/1.1",
+				Cookies:     []struct{}{},
+				Headers:     harHeaders(r.Header),
+				Content: harContent{
+					Size:     len(r.Body),
+					MimeType: r.ContentType,
+				},
+				RedirectURL: r.Header.Get("Location"),
+				HeadersSize: -1,
+				BodySize:    len(r.Body),
+			},
+			Timings: harTimings{Wait: elapsed},
+			Error:   r.Error,
+		}
+		if r.RequestBody != "" {
+			entry.Request.PostData = &harPostData{
+				MimeType: r.RequestHeader.Get("Content-Type"),
+				Text:     harRequestBody(r.URL, r.RequestHeader.Get("Content-Type"), r.RequestBody),
+			}
+		}
+		if utf8.ValidString(r.Body) {
+			entry.Response.Content.Text = r.Body
+		} else {
+			entry.Response.Content.Text = base64.StdEncoding.EncodeToString([]byte(r.Body))
+			entry.Response.Content.Encoding = "base64"
+		}
+		log.Entries = append(log.Entries, entry)
+	}
+	return json.MarshalIndent(struct {
+		Log harLog `json:"log"`
+	}{log}, "", "  ")
+}
+
+func harHeaders(header http.Header) []harNVP {
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	nvps := make([]harNVP, 0, len(header))
+	for _, name := range names {
+		for _, value := range header[name] {
+			if slices.Contains(redactedHARHeaders, http.CanonicalHeaderKey(name)) {
+				value = harRedacted
+			}
+			nvps = append(nvps, harNVP{Name: name, Value: value})
+		}
+	}
+	return nvps
+}
+
+func harQuery(rawURL string) []harNVP {
+	nvps := []harNVP{}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nvps
+	}
+	query := u.Query()
+	names := make([]string, 0, len(query))
+	for name := range query {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		for _, value := range query[name] {
+			if isHARSecret(name) {
+				value = harRedacted
+			}
+			nvps = append(nvps, harNVP{Name: name, Value: value})
+		}
+	}
+	return nvps
+}
+
+func isHARSecret(name string) bool {
+	name = strings.ToLower(name)
+	if slices.Contains(redactedHARParams, name) {
+		return true
+	}
+	return slices.ContainsFunc(redactedHARParamParts, func(part string) bool {
+		return strings.Contains(name, part)
+	})
+}
+
+// harURL redacts values of credential query params of the URL.
+func harURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.RawQuery == "" {
+		return rawURL
+	}
+	query := u.Query()
+	redacted := false
+	for name, values := range query {
+		if isHARSecret(name) {
+			for i := range values {
+				values[i] = harRedacted
+			}
+			redacted = true
+		}
+	}
+	if redacted {
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
+
+// harRequestBody redacts credential fields of form and JSON bodies, and
+// bodies sent to login pages as a whole.
+func harRequestBody(rawURL, contentType, body string) string {
+	if u, err := url.Parse(rawURL); err == nil {
+		path := strings.ToLower(u.Path)
+		if slices.ContainsFunc(redactedHARPaths, func(part string) bool {
+			return strings.Contains(path, part)
+		}) {
+			return harRedacted
+		}
+	}
+	switch {
+	case strings.Contains(contentType, "json"):
+		var v any
+		if err := json.Unmarshal([]byte(body), &v); err != nil {
+			return body
+		}
+		if data, err := json.Marshal(redactHARJSON(v)); err == nil {
+			return string(data)
+		}
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		form, err := url.ParseQuery(body)
+		if err != nil {
+			return body
+		}
+		for name, values := range form {
+			if isHARSecret(name) {
+				for i := range values {
+					values[i] = harRedacted
+				}
+			}
+		}
+		return form.Encode()
+	}
+	return body
+}
+
+func redactHARJSON(v any) any {
+	switch v := v.(type) {
+	case map[string]any:
+		for name, value := range v {
+			if isHARSecret(name) {
+				v[name] = harRedacted
+			} else {
+				v[name] = redactHARJSON(value)
+			}
+		}
+	case []any:
+		for i, value := range v {
+			v[i] = redactHARJSON(value)
+		}
+	}
+	return v
+}
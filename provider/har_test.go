@@ -0,0 +1,118 @@
+package provider
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSnapshot_MarshalHAR(t *testing.T) {
+	started := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	snapshot := &Snapshot{Responses: []*SnapshotResponse{
+		{
+			URL:           "https://example.com/search?q=ABC-123",
+			Method:        http.MethodGet,
+			RequestHeader: http.Header{"User-Agent": {"test"}, "Cookie": {"session=secret"}},
+			StatusCode:    http.StatusOK,
+			Header:        http.Header{"Content-Type": {"text/html"}, "Set-Cookie": {"session=secret"}},
+			ContentType:   "text/html",
+			Body:          "<html></html>",
+			StartedAt:     started,
+			Elapsed:       1500 * time.Millisecond,
+		},
+		{
+			URL:       "https://example.com/cover.jpg",
+			Method:    http.MethodGet,
+			Body:      "\xff\xd8\xff",
+			StartedAt: started,
+		},
+		{
+			URL:           "https://example.com/api/search?access_token=secret&page=2",
+			Method:        http.MethodPost,
+			RequestHeader: http.Header{"Content-Type": {"application/json"}},
+			RequestBody:   `{"keyword":"ABC-123","user":{"password":"secret"}}`,
+			StartedAt:     started,
+		},
+		{
+			URL:           "https://example.com/search",
+			Method:        http.MethodPost,
+			RequestHeader: http.Header{"Content-Type": {"application/x-www-form-urlencoded"}},
+			RequestBody:   "q=ABC-123&passwd=secret",
+			StartedAt:     started,
+		},
+		{
+			URL:           "https://example.com/login",
+			Method:        http.MethodPost,
+			RequestHeader: http.Header{"Content-Type": {"text/plain"}},
+			RequestBody:   "alice secret",
+			StartedAt:     started,
+		},
+		{
+			URL:       "https://example.com/blocked",
+			Method:    http.MethodGet,
+			Error:     "connection reset by peer",
+			StartedAt: started,
+		},
+	}}
+
+	data, err := snapshot.MarshalHAR()
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "secret", "credentials are redacted")
+
+	var har struct {
+		Log struct {
+			Version string `json:"version"`
+			Entries []struct {
+				StartedDateTime string  `json:"startedDateTime"`
+				Time            float64 `json:"time"`
+				Request         struct {
+					URL      string `json:"url"`
+					PostData struct {
+						Text string `json:"text"`
+					} `json:"postData"`
+					QueryString []struct {
+						Name  string `json:"name"`
+						Value string `json:"value"`
+					} `json:"queryString"`
+				} `json:"request"`
+				Response struct {
+					Status  int `json:"status"`
+					Content struct {
+						Text     string `json:"text"`
+						Encoding string `json:"encoding"`
+					} `json:"content"`
+				} `json:"response"`
+				Error string `json:"_error"`
+			} `json:"entries"`
+		} `json:"log"`
+	}
+	require.NoError(t, json.Unmarshal(data, &har))
+	assert.Equal(t, "1.2", har.Log.Version)
+	require.Len(t, har.Log.Entries, 6)
+
+	entry := har.Log.Entries[0]
+	assert.Equal(t, "2024-01-02T03:04:05Z", entry.StartedDateTime)
+	assert.Equal(t, 1500.0, entry.Time)
+	assert.Equal(t, http.StatusOK, entry.Response.Status)
+	assert.Equal(t, "<html></html>", entry.Response.Content.Text)
+	if assert.Len(t, entry.Request.QueryString, 1) {
+		assert.Equal(t, "ABC-123", entry.Request.QueryString[0].Value)
+	}
+
+	entry = har.Log.Entries[1]
+	assert.Equal(t, "base64", entry.Response.Content.Encoding)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("\xff\xd8\xff")), entry.Response.Content.Text)
+
+	entry = har.Log.Entries[2]
+	assert.Equal(t, "https://example.com/api/search?access_token=REDACTED&page=2", entry.Request.URL)
+	assert.JSONEq(t, `{"keyword":"ABC-123","user":{"password":"REDACTED"}}`, entry.Request.PostData.Text)
+	assert.Equal(t, "passwd=REDACTED&q=ABC-123", har.Log.Entries[3].Request.PostData.Text)
+	assert.Equal(t, "REDACTED", har.Log.Entries[4].Request.PostData.Text)
+
+	assert.Equal(t, "connection reset by peer", har.Log.Entries[5].Error)
+}
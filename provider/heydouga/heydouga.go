@@ -150,7 +150,8 @@ func (hey *HeyDouga) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, er
 				ratingURL = ss[1]
 			}
 			if ratingURL != "" {
-				d := c.Clone()
+				d, cancel := hey.SubCollector(c)
+				defer cancel()
 				d.OnResponse(func(r *colly.Response) {
 					data := struct {
 						MovieRatingAverage string `json:"movie_rating_average"`
@@ -181,7 +182,8 @@ func (hey *HeyDouga) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, er
 				movieSeq = ss[1]
 			}
 			if providerID != "" && movieSeq != "" {
-				d := c.Clone()
+				d, cancel := hey.SubCollector(c)
+				defer cancel()
 				d.OnResponse(func(r *colly.Response) {
 					data := struct {
 						Tag []struct {
@@ -206,10 +208,13 @@ func (hey *HeyDouga) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, er
 		wg.Wait()
 	})
 
+	hey.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.heydouga.com", Path: "/moviepages/"})
 }
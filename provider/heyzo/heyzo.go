@@ -8,21 +8,25 @@ import (
 	"net/url"
 	"path"
 	"regexp"
+	"strconv"
 	"strings"
 
 	"github.com/gocolly/colly/v2"
+	"golang.org/x/net/html"
 
 	"github.com/metatube-community/metatube-sdk-go/common/js"
 	"github.com/metatube-community/metatube-sdk-go/common/m3u8"
 	"github.com/metatube-community/metatube-sdk-go/common/parser"
 	"github.com/metatube-community/metatube-sdk-go/model"
 	"github.com/metatube-community/metatube-sdk-go/provider"
+	"github.com/metatube-community/metatube-sdk-go/provider/internal/d2pass"
 	"github.com/metatube-community/metatube-sdk-go/provider/internal/scraper"
 )
 
 var (
 	_ provider.MovieProvider = (*Heyzo)(nil)
 	_ provider.MovieReviewer = (*Heyzo)(nil)
+	_ provider.MovieEnricher = (*Heyzo)(nil)
 )
 
 const (
@@ -30,6 +34,9 @@ const (
 	Priority = 1000
 )
 
+// ExtraRatingCount is the extra field of the number of member ratings.
+const ExtraRatingCount = "heyzo_rating_count"
+
 const (
 	baseURL          = "https://www.heyzo.com/"
 	movieURL         = "https://www.heyzo.com/moviepages/%04s/index.html"
@@ -43,7 +50,8 @@ type Heyzo struct {
 }
 
 func New() *Heyzo {
-	return &Heyzo{scraper.NewDefaultScraper(Name, baseURL, Priority)}
+	return &Heyzo{scraper.NewDefaultScraper(Name, baseURL, Priority,
+		scraper.WithLogin(d2pass.Login))}
 }
 
 func (hzo *Heyzo) GetMovieReviewsByID(id string) (reviews []*model.MovieReviewDetail, err error) {
@@ -176,7 +184,8 @@ func (hzo *Heyzo) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 				Provider string `json:"provider"`
 			} `json:"video"`
 			AggregateRating struct {
-				RatingValue string `json:"ratingValue"`
+				RatingValue string          `json:"ratingValue"`
+				RatingCount json.RawMessage `json:"ratingCount"` // number or string.
 			} `json:"aggregateRating"`
 		}{}
 		if json.Unmarshal([]byte(e.Text), &data) == nil {
@@ -187,6 +196,9 @@ func (hzo *Heyzo) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 			info.ReleaseDate = parser.ParseDate(data.ReleasedEvent.StartDate)
 			info.Runtime = parser.ParseRuntime(data.Video.Duration)
 			info.Score = parser.ParseScore(data.AggregateRating.RatingValue)
+			if count, _ := strconv.Atoi(strings.Trim(string(data.AggregateRating.RatingCount), `"`)); count > 0 {
+				info.Extra.Set(ExtraRatingCount, count)
+			}
 			if data.Video.Provider != "" {
 				info.Maker = data.Video.Provider
 			}
@@ -219,16 +231,19 @@ func (hzo *Heyzo) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 	})
 
 	// Fields
-	c.OnXML(`//table[@class="movieInfo"]/tbody/tr`, func(e *colly.XMLElement) {
-		switch e.ChildText(`.//td[1]`) {
-		case "公開日":
-			info.ReleaseDate = parser.ParseDate(e.ChildText(`.//td[2]`))
-		case "出演":
-			info.Actors = e.ChildTexts(`.//td[2]/a/span`)
-		case "シリーズ":
-			info.Series = strings.Trim(e.ChildText(`.//td[2]`), "-")
-		case "評価":
-			info.Score = parser.ParseScore(e.ChildText(`.//span[@itemprop="ratingValue"]`))
+	c.OnXML(`//table[@class="movieInfo"]`, func(e *colly.XMLElement) {
+		kv := parser.ExtractKV(e.DOM.(*html.Node), `./tbody/tr`, `./td[1]`, `./td[2]`, nil)
+		for key := range kv {
+			switch key {
+			case "公開日":
+				info.ReleaseDate = parser.ParseDate(kv.Text(key))
+			case "出演":
+				info.Actors = kv.ChildTexts(key, `./a/span`)
+			case "シリーズ":
+				info.Series = strings.Trim(kv.Text(key), "-")
+			case "評価":
+				info.Score = parser.ParseScore(kv.ChildText(key, `.//span[@itemprop="ratingValue"]`))
+			}
 		}
 	})
 
@@ -275,21 +290,15 @@ func (hzo *Heyzo) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 		}
 		if sub := regexp.MustCompile(`stream\s*=\s*'(.+?)'\+siteID\+'(.+?)'\+movieId\+'(.+?)';`).
 			FindStringSubmatch(e.Text); len(sub) == 4 {
-			d := c.Clone()
-			d.OnResponse(func(r *colly.Response) {
-				defer func() {
-					// Sample HLS URL
-					info.PreviewVideoHLSURL = r.Request.URL.String()
-				}()
-				if uri, _, err := m3u8.ParseBestMediaURI(bytes.NewReader(r.Body)); err == nil {
-					if ss := regexp.MustCompile(`/sample/(\d+)/(\d+)/ts\.(.+?)\.m3u8`).
-						FindStringSubmatch(uri); len(ss) == 4 {
-						info.PreviewVideoURL = fmt.Sprintf(sampleURL, ss[1], ss[2], ss[3])
-					}
-				}
-			})
 			m3u8Link := e.Request.AbsoluteURL(fmt.Sprintf("%s%s%s%s%s", sub[1], siteID, sub[2], movieID, sub[3]))
-			d.Visit(m3u8Link)
+			if hzo.IsLazy(provider.PreviewVideoField) {
+				// resolved by EnrichMovieInfo.
+				info.PreviewVideoHLSURL = m3u8Link
+				return
+			}
+			d, cancel := hzo.SubCollector(c)
+			defer cancel()
+			hzo.resolvePreviewVideo(d, info, m3u8Link)
 		}
 	})
 
@@ -300,10 +309,89 @@ func (hzo *Heyzo) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 		}
 	})
 
-	err = c.Visit(info.Homepage)
+	hzo.PatchCollector(c, info)
+
+	if err = c.Visit(info.Homepage); err == nil {
+		info.PreviewImages = hzo.upgradePreviewImages(info.PreviewImages)
+	}
+	return
+}
+
+// EnrichMovieInfo resolves the preview video of the HLS playlist, which is
+// skipped by getting movie infos if lazy.
+func (hzo *Heyzo) EnrichMovieInfo(info *model.MovieInfo, fields ...provider.Field) error {
+	if !scraper.EnrichesField(fields, provider.PreviewVideoField) ||
+		info.PreviewVideoHLSURL == "" || info.PreviewVideoURL != "" /* resolved */ {
+		return nil
+	}
+	c, cancel := hzo.SubCollector(hzo.ClonedCollector())
+	defer cancel()
+	return hzo.resolvePreviewVideo(c, info, info.PreviewVideoHLSURL)
+}
+
+// resolvePreviewVideo finds the best sample video of the HLS playlist.
+func (hzo *Heyzo) resolvePreviewVideo(c *colly.Collector, info *model.MovieInfo, m3u8Link string) error {
+	c.OnResponse(func(r *colly.Response) {
+		defer func() {
+			// Sample HLS URL
+			info.PreviewVideoHLSURL = r.Request.URL.String()
+		}()
+		if uri, _, err := m3u8.ParseBestMediaURI(bytes.NewReader(r.Body)); err == nil {
+			if ss := regexp.MustCompile(`/sample/(\d+)/(\d+)/ts\.(.+?)\.m3u8`).
+				FindStringSubmatch(uri); len(ss) == 4 {
+				info.PreviewVideoURL = fmt.Sprintf(sampleURL, ss[1], ss[2], ss[3])
+			}
+		}
+	})
+	return c.Visit(m3u8Link)
+}
+
+// galleryRule rewrites thumbnail-sized gallery image URLs to full-size.
+type galleryRule struct {
+	re      *regexp.Regexp
+	replace string
+	member  bool // member-only variant.
+}
+
+var galleryRules = []galleryRule{
+	{regexp.MustCompile(`/thumbnail_(\d+\.\w+)$`), "/$1", false},
+	{regexp.MustCompile(`/gallery/(?:thumbnail_)?(\d+\.\w+)$`), "/gallery/member/$1", true},
+}
+
+// upgradePreviewImages replaces the thumbnail-sized preview images with
+// full-size variants. The first rule applicable to the first image whose
+// result exists is applied to all, otherwise thumbnails are kept as is.
+func (hzo *Heyzo) upgradePreviewImages(images []string) []string {
+	if len(images) == 0 {
+		return images
+	}
+	for _, rule := range galleryRules {
+		if rule.member && !hzo.IsMember() {
+			continue
+		}
+		first := rule.re.ReplaceAllString(images[0], rule.replace)
+		if first == images[0] || !hzo.imageExists(first) {
+			continue
+		}
+		upgraded := make([]string, len(images))
+		for i, image := range images {
+			upgraded[i] = rule.re.ReplaceAllString(image, rule.replace)
+		}
+		return upgraded
+	}
+	return images
+}
+
+func (hzo *Heyzo) imageExists(rawURL string) (ok bool) {
+	c := hzo.ClonedCollector()
+	c.OnResponse(func(r *colly.Response) {
+		ok = strings.HasPrefix(r.Headers.Get("Content-Type"), "image/")
+	})
+	_ = c.Head(rawURL)
 	return
 }
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.heyzo.com", Path: "/moviepages/"})
 }
@@ -2,6 +2,9 @@ package heyzo
 
 import (
 	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -38,3 +41,28 @@ func TestHeyzo_GetMovieReviewsByID(t *testing.T) {
 		t.Logf("%s", data)
 	}
 }
+
+func TestHeyzo_upgradePreviewImages(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "thumbnail_") || strings.HasSuffix(r.URL.Path, "/gallery/001.jpg") {
+			w.Header().Set("Content-Type", "image/jpeg")
+			return
+		}
+		http.NotFound(w, r)
+	}))
+	defer srv.Close()
+
+	provider := New()
+	images := []string{
+		srv.URL + "/contents/3000/0841/gallery/thumbnail_001.jpg",
+		srv.URL + "/contents/3000/0841/gallery/thumbnail_002.jpg",
+	}
+	assert.Equal(t, []string{
+		srv.URL + "/contents/3000/0841/gallery/001.jpg",
+		srv.URL + "/contents/3000/0841/gallery/002.jpg",
+	}, provider.upgradePreviewImages(images))
+
+	// fallback to thumbnails.
+	images = []string{srv.URL + "/contents/3000/0842/gallery/thumbnail_002.jpg"}
+	assert.Equal(t, images, provider.upgradePreviewImages(images))
+}
@@ -0,0 +1,97 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+
+	"golang.org/x/net/proxy"
+)
+
+// HostOverride pins a host of a provider to static IPs, e.g., when the
+// domain resolves to blocked IPs but the site works via alternate ones.
+// The TLS server name (SNI) is kept as the hostname.
+type HostOverride struct {
+	// Hostname to override, e.g., `www.example.com`.
+	Hostname string `json:"hostname"`
+	// IPs are dialed instead of resolving the hostname, in order.
+	IPs []string `json:"ips,omitempty"`
+	// Host overrides the `Host` header, the hostname is sent if empty.
+	Host string `json:"host,omitempty"`
+}
+
+// DialTransport returns the transport of base dialed by the dialer, which
+// replaces HTTP proxies from the environment, and pinned to IPs and Host
+// headers of the host overrides, either of them may be empty. Only the
+// transports based on *http.Transport can be dialed by others, which are
+// cloned rather than modified since they may be shared, the clone is also
+// returned to close idle connections, or nil if not cloned.
+func DialTransport(base http.RoundTripper, dialer proxy.ContextDialer, overrides ...*HostOverride) (http.RoundTripper, *http.Transport) {
+	hosts := make(map[string]*HostOverride, len(overrides))
+	for _, o := range overrides {
+		hosts[strings.ToLower(o.Hostname)] = o
+	}
+	var conns *http.Transport
+	if t, ok := base.(*http.Transport); ok && (dialer != nil || len(hosts) > 0) {
+		t = t.Clone()
+		dial := t.DialContext
+		if dialer != nil {
+			t.Proxy = nil // dialed by the dialer only.
+			dial = dialer.DialContext
+		}
+		if dial == nil {
+			dial = (&net.Dialer{}).DialContext
+		}
+		if len(hosts) > 0 {
+			dial = pinnedDialer(dial, hosts)
+		}
+		t.DialContext = dial
+		conns, base = t, t
+	}
+	if len(hosts) > 0 {
+		base = &hostTransport{base: base, hosts: hosts}
+	}
+	return base, conns
+}
+
+// pinnedDialer dials the IPs of overridden hosts in order, until one of
+// them is connected.
+func pinnedDialer(dial func(ctx context.Context, network, addr string) (net.Conn, error),
+	hosts map[string]*HostOverride,
+) func(ctx context.Context, network, addr string) (net.Conn, error) {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			return dial(ctx, network, addr)
+		}
+		o, ok := hosts[strings.ToLower(host)]
+		if !ok || len(o.IPs) == 0 {
+			return dial(ctx, network, addr)
+		}
+		var errs []error
+		for _, ip := range o.IPs {
+			conn, err := dial(ctx, network, net.JoinHostPort(ip, port))
+			if err == nil {
+				return conn, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, errors.Join(errs...)
+	}
+}
+
+// hostTransport overrides `Host` headers of requests.
+type hostTransport struct {
+	base  http.RoundTripper
+	hosts map[string]*HostOverride
+}
+
+func (t *hostTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if o, ok := t.hosts[strings.ToLower(req.URL.Hostname())]; ok && o.Host != "" {
+		req = req.Clone(req.Context())
+		req.Host = o.Host
+	}
+	return t.base.RoundTrip(req)
+}
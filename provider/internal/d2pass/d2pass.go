@@ -0,0 +1,39 @@
+// Package d2pass provides the D2Pass single sign-on shared by member
+// sites, e.g., Caribbeancom, 1Pondo and HEYZO.
+package d2pass
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/metatube-community/metatube-sdk-go/provider/internal/scraper"
+)
+
+// Realm is the credential realm of D2Pass accounts.
+const Realm = "d2pass"
+
+const loginURL = "https://www.d2pass.com/login"
+
+// Login is the login rule of D2Pass, the session cookies are shared by
+// member sites through the single sign-on.
+var Login = &scraper.Login{
+	Realm: Realm,
+	URL:   loginURL,
+	Form: func(username, password string) map[string]string {
+		return map[string]string{
+			"login_id": username,
+			"password": password,
+		}
+	},
+	Verify: func(r *colly.Response) bool {
+		// failed logins are redirected back to the login page.
+		return r.StatusCode == http.StatusOK &&
+			!strings.HasPrefix(r.Request.URL.Path, "/login")
+	},
+	Detect: func(r *colly.Response) bool {
+		return r.StatusCode == http.StatusUnauthorized ||
+			strings.HasPrefix(r.Request.URL.Path, "/login")
+	},
+}
@@ -0,0 +1,111 @@
+package d2pass_test
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	tenmusume "github.com/metatube-community/metatube-sdk-go/provider/10musume"
+	onepondo "github.com/metatube-community/metatube-sdk-go/provider/1pondo"
+	"github.com/metatube-community/metatube-sdk-go/provider/caribbeancom"
+	"github.com/metatube-community/metatube-sdk-go/provider/pacopacomama"
+)
+
+// member is a site of the D2Pass single sign-on.
+type member interface {
+	SetFetchClient(client fetch.Client)
+	SetCredentials(username, password string)
+	ClonedCollector() *colly.Collector
+	IsMember() bool
+	MemberCookieJar() http.CookieJar
+}
+
+// recorded returns the client of responses recorded from D2Pass, logins
+// with the password other than `secret` are redirected back to the login
+// page, and counts the logins posted.
+func recorded(t *testing.T, logins *atomic.Int32) fetch.Client {
+	mypage, err := os.ReadFile("testdata/mypage.html")
+	require.NoError(t, err)
+	login, err := os.ReadFile("testdata/login.html")
+	require.NoError(t, err)
+	fixtures := fetch.NewFixtureClient(&fetch.Fixture{
+		URL:    "https://www.d2pass.com/mypage",
+		Header: http.Header{"Content-Type": []string{"text/html; charset=UTF-8"}},
+		Body:   mypage,
+	}, &fetch.Fixture{
+		URL:    "https://www.d2pass.com/login?error=1",
+		Header: http.Header{"Content-Type": []string{"text/html; charset=UTF-8"}},
+		Body:   login,
+	})
+	return fetch.ClientFunc(func(req *http.Request) (*http.Response, error) {
+		if req.Method != http.MethodPost || req.URL.String() != "https://www.d2pass.com/login" {
+			return fixtures.Do(req)
+		}
+		logins.Add(1)
+		_ = req.ParseForm()
+		header := http.Header{"Location": []string{"/login?error=1"}}
+		if req.PostForm.Get("login_id") == "user" && req.PostForm.Get("password") == "secret" {
+			header = http.Header{
+				"Location":   []string{"/mypage"},
+				"Set-Cookie": []string{"D2PSESSID=0123456789abcdef; Path=/; Domain=.d2pass.com; Secure; HttpOnly"},
+			}
+		}
+		return &http.Response{
+			StatusCode: http.StatusFound,
+			Header:     header,
+			Body:       http.NoBody,
+			Request:    req,
+		}, nil
+	})
+}
+
+func TestLogin_SharedSession(t *testing.T) {
+	var logins atomic.Int32
+	client := recorded(t, &logins)
+	members := []member{onepondo.New(), tenmusume.New(), caribbeancom.New(), pacopacomama.New()}
+	for _, m := range members {
+		m.SetFetchClient(client)
+		m.SetCredentials("user", "secret")
+	}
+
+	for _, m := range members {
+		m.ClonedCollector()
+		assert.True(t, m.IsMember())
+	}
+	// logged in once for all sites of the single sign-on.
+	assert.Equal(t, int32(1), logins.Load())
+	jar := members[0].MemberCookieJar()
+	require.NotNil(t, jar)
+	for _, m := range members[1:] {
+		assert.Equal(t, jar, m.MemberCookieJar())
+	}
+	u, _ := url.Parse("https://www.d2pass.com/")
+	if cookies := jar.Cookies(u); assert.Len(t, cookies, 1) {
+		assert.Equal(t, "D2PSESSID", cookies[0].Name)
+	}
+}
+
+func TestLogin_Failed(t *testing.T) {
+	var logins atomic.Int32
+	client := recorded(t, &logins)
+	members := []member{onepondo.New(), caribbeancom.New()}
+	for _, m := range members {
+		m.SetFetchClient(client)
+		m.SetCredentials("user", "wrong")
+	}
+
+	for _, m := range members {
+		m.ClonedCollector()
+		assert.False(t, m.IsMember())
+		assert.Nil(t, m.MemberCookieJar())
+	}
+	// failed logins are not retried by other sites either.
+	assert.Equal(t, int32(1), logins.Load())
+}
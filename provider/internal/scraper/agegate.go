@@ -0,0 +1,71 @@
+package scraper
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// AgeGate is a declarative rule to pass age checks or cookie consents
+// of gated sites, which is negotiated once per session.
+type AgeGate struct {
+	// Cookies are set to the base URL before any request.
+	Cookies []*http.Cookie
+	// FormURL is requested once per session before the first scrape,
+	// the resulting cookies are kept by the shared cookie jar.
+	FormURL string
+	// Form is posted to FormURL, it is a GET request if nil.
+	Form map[string]string
+	// Detect reports whether the response is still gated, which starts
+	// a new session so that the gate is negotiated again next time.
+	Detect func(r *colly.Response) bool
+}
+
+type ageGateState struct {
+	gate   *AgeGate
+	mu     sync.Mutex
+	passed bool
+}
+
+// pass negotiates the gate with the collector if not passed yet.
+func (s *ageGateState) pass(c *colly.Collector) error {
+	if s.gate.FormURL == "" {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.passed {
+		return nil
+	}
+	var err error
+	if s.gate.Form != nil {
+		err = c.Post(s.gate.FormURL, s.gate.Form)
+	} else {
+		err = c.Visit(s.gate.FormURL)
+	}
+	if err != nil {
+		return err
+	}
+	s.passed = true
+	return nil
+}
+
+func (s *ageGateState) reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.passed = false
+}
+
+// WithAgeGate passes the age gate of the site with the rule.
+func WithAgeGate(gate *AgeGate) Option {
+	return func(s *Scraper) error {
+		if len(gate.Cookies) > 0 {
+			if err := s.c.SetCookies(s.baseURL.String(), gate.Cookies); err != nil {
+				return err
+			}
+		}
+		s.gate = &ageGateState{gate: gate}
+		return nil
+	}
+}
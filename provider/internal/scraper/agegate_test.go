@@ -0,0 +1,54 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScraper_AgeGate(t *testing.T) {
+	var posts atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/agree":
+			if r.Method == http.MethodPost && r.FormValue("yes") == "1" {
+				posts.Add(1)
+				http.SetCookie(w, &http.Cookie{Name: "agreed", Value: "1", Path: "/"})
+			}
+		default:
+			if c, err := r.Cookie("lang"); err != nil || c.Value != "ja" {
+				w.Write([]byte("no lang"))
+				return
+			}
+			if _, err := r.Cookie("agreed"); err != nil {
+				w.Write([]byte("gated"))
+				return
+			}
+			w.Write([]byte("content"))
+		}
+	}))
+	defer srv.Close()
+
+	s := NewDefaultScraper("test", srv.URL, 0, WithAgeGate(&AgeGate{
+		Cookies: []*http.Cookie{{Name: "lang", Value: "ja"}},
+		FormURL: srv.URL + "/agree",
+		Form:    map[string]string{"yes": "1"},
+		Detect: func(r *colly.Response) bool {
+			return strings.Contains(string(r.Body), "gated")
+		},
+	}))
+
+	for i := 0; i < 3; i++ {
+		var body string
+		c := s.ClonedCollector()
+		c.OnResponse(func(r *colly.Response) { body = string(r.Body) })
+		assert.NoError(t, c.Visit(srv.URL+"/movie"))
+		assert.Equal(t, "content", body)
+	}
+	assert.Equal(t, int32(1), posts.Load())
+}
@@ -0,0 +1,129 @@
+package scraper
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// waybackURL is the prefix of snapshots of the Internet Archive, which is
+// replaced in tests.
+var waybackURL = "https://web.archive.org/web/"
+
+const (
+	// max number of redirects to the closest snapshot.
+	maxArchiveRedirects = 5
+	// max number of archived pages recorded and not yet looked up.
+	maxArchiveRecords = 256
+)
+
+// archiveState records pages fetched from the Internet Archive, i.e.,
+// snapshot URLs by the original ones.
+type archiveState struct {
+	mu   sync.Mutex
+	urls map[string]string
+}
+
+func (a *archiveState) record(rawURL, snapshot string) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.urls == nil || len(a.urls) >= maxArchiveRecords {
+		a.urls = make(map[string]string) // records never looked up are dropped.
+	}
+	a.urls[rawURL] = snapshot
+}
+
+func (a *archiveState) lookup(rawURL string) (string, bool) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	snapshot, ok := a.urls[rawURL]
+	delete(a.urls, rawURL)
+	return snapshot, ok
+}
+
+// SetArchiveFallback enables fetching removed pages, i.e., of 404 or 410,
+// from the closest snapshots of the Internet Archive, which are parsed by
+// the same selectors. It must be called before the Scraper is used.
+func (s *Scraper) SetArchiveFallback(enabled bool) {
+	if !enabled {
+		s.archive = nil
+	} else if s.archive == nil {
+		s.archive = &archiveState{}
+	}
+}
+
+// ArchivedURL returns the snapshot URL which the page of the URL has been
+// fetched from, if it's removed, so that infos of the page are flagged as
+// archived. The record is consumed by the lookup.
+func (s *Scraper) ArchivedURL(rawURL string) (string, bool) {
+	if s.archive == nil {
+		return "", false
+	}
+	return s.archive.lookup(rawURL)
+}
+
+// archiveTransport falls back to snapshots of removed pages, responses of
+// snapshots are returned as those of the original requests.
+type archiveTransport struct {
+	base http.RoundTripper
+	s    *Scraper
+}
+
+func (t *archiveTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	archive := t.s.archive
+	// soft 404 pages are removed as well.
+	soft := errors.Is(err, provider.ErrInfoNotFound)
+	if (err != nil && !soft) || archive == nil || req.Method != http.MethodGet ||
+		(!soft && resp.StatusCode != http.StatusNotFound && resp.StatusCode != http.StatusGone) ||
+		strings.HasPrefix(req.URL.String(), waybackURL) {
+		return resp, err
+	}
+	snapshot, archived := t.fetchSnapshot(req)
+	if archived == nil {
+		return resp, err // not archived, the original response is kept.
+	}
+	if resp != nil {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+	}
+	archive.record(req.URL.String(), snapshot)
+	archived.Request = req
+	return archived, nil
+}
+
+// fetchSnapshot fetches the closest snapshot of the page, the `id_` flag
+// asks for the original contents without links rewritten.
+func (t *archiveTransport) fetchSnapshot(req *http.Request) (string, *http.Response) {
+	snapshot := waybackURL + time.Now().UTC().Format("20060102150405") + "id_/" + req.URL.String()
+	for i := 0; i <= maxArchiveRedirects; i++ {
+		areq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, snapshot, nil)
+		if err != nil {
+			return "", nil
+		}
+		areq.Header = req.Header.Clone()
+		// sessions of the site are never sent to the archive.
+		areq.Header.Del("Cookie")
+		areq.Header.Del("Authorization")
+		resp, err := t.base.RoundTrip(areq)
+		if err != nil {
+			return "", nil
+		}
+		if resp.StatusCode == http.StatusOK {
+			return snapshot, resp
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		_ = resp.Body.Close()
+		location, err := resp.Location()
+		if err != nil || resp.StatusCode < 300 || resp.StatusCode >= 400 {
+			return "", nil
+		}
+		snapshot = location.String()
+	}
+	return "", nil
+}
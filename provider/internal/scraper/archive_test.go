@@ -0,0 +1,93 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScraper_ArchiveFallback(t *testing.T) {
+	site := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/removed" || r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		if r.URL.Path == "/soft" {
+			fmt.Fprint(w, `<html><body><h1>Content not found</h1></body></html>`)
+			return
+		}
+		fmt.Fprint(w, `<html><body><h1>Live</h1></body></html>`)
+	}))
+	defer site.Close()
+
+	var cookie string
+	archive := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cookie = r.Header.Get("Cookie")
+		const snapshot = "/web/20200102030405id_/"
+		if !strings.HasPrefix(r.URL.Path, snapshot) {
+			// redirected to the closest snapshot.
+			_, original, _ := strings.Cut(r.URL.Path, "id_/")
+			w.Header().Set("Location", snapshot+original)
+			w.WriteHeader(http.StatusFound)
+			return
+		}
+		if strings.HasSuffix(r.URL.Path, "/missing") {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><h1>Archived</h1></body></html>`)
+	}))
+	defer archive.Close()
+
+	defer func(u string) { waybackURL = u }(waybackURL)
+	waybackURL = archive.URL + "/web/"
+
+	scrape := func(s *Scraper, path string) (title string, err error) {
+		c := s.ClonedCollector()
+		c.OnRequest(func(r *colly.Request) { r.Headers.Set("Cookie", "session=secret") })
+		c.OnXML(`//h1`, func(e *colly.XMLElement) { title = e.Text })
+		err = c.Visit(site.URL + path)
+		return
+	}
+
+	s := NewDefaultScraper("TEST", site.URL, 0, WithArchiveFallback())
+	title, err := scrape(s, "/removed")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Archived", title)
+	}
+	assert.Empty(t, cookie, "sessions are never sent to the archive")
+	snapshot, ok := s.ArchivedURL(site.URL + "/removed")
+	assert.True(t, ok)
+	assert.Equal(t, archive.URL+"/web/20200102030405id_/"+site.URL+"/removed", snapshot)
+	_, ok = s.ArchivedURL(site.URL + "/removed")
+	assert.False(t, ok, "consumed by the lookup")
+
+	// live pages are not archived.
+	title, err = scrape(s, "/live")
+	assert.NoError(t, err)
+	assert.Equal(t, "Live", title)
+	_, ok = s.ArchivedURL(site.URL + "/live")
+	assert.False(t, ok)
+
+	// pages never archived fail as is.
+	_, err = scrape(s, "/missing")
+	assert.Error(t, err)
+
+	// soft 404 pages are removed as well.
+	title, err = scrape(NewDefaultScraper("TEST", site.URL, 0,
+		WithArchiveFallback(), WithSoftNotFound("content not found")), "/soft")
+	if assert.NoError(t, err) {
+		assert.Equal(t, "Archived", title)
+	}
+
+	// disabled by default.
+	_, err = scrape(NewDefaultScraper("TEST", site.URL, 0), "/removed")
+	assert.Error(t, err)
+}
@@ -0,0 +1,26 @@
+package scraper
+
+import (
+	"bytes"
+	"io"
+)
+
+// Size hints larger than this are not trusted to pre-allocate buffers, so
+// that bogus Content-Length headers won't allocate huge buffers upfront.
+const maxSizeHint = 4 << 20
+
+// readBody reads all from r into a buffer pre-allocated by the size hint,
+// e.g., Content-Length, which avoids the repeated allocations of growing
+// slices as io.ReadAll does. Bodies are kept by callers, so buffers are
+// never reused.
+func readBody(r io.Reader, sizeHint int64) ([]byte, error) {
+	if sizeHint <= 0 || sizeHint > maxSizeHint {
+		return io.ReadAll(r)
+	}
+	// room of bytes.MinRead to read EOF without growing.
+	buf := bytes.NewBuffer(make([]byte, 0, sizeHint+bytes.MinRead))
+	if _, err := buf.ReadFrom(r); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,40 @@
+package scraper
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadBody(t *testing.T) {
+	for _, data := range [][]byte{
+		{},
+		[]byte("hello"),
+		bytes.Repeat([]byte("x"), 1<<16),
+	} {
+		for _, hint := range []int64{int64(len(data)), -1, 1, maxSizeHint + 1} {
+			body, err := readBody(bytes.NewReader(data), hint)
+			if assert.NoError(t, err) {
+				assert.Equal(t, data, body)
+			}
+		}
+	}
+}
+
+func BenchmarkReadBody(b *testing.B) {
+	data := bytes.Repeat([]byte("x"), 1<<18)
+	b.Run("Sized", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = readBody(bytes.NewReader(data), int64(len(data)))
+		}
+	})
+	b.Run("ReadAll", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			_, _ = io.ReadAll(bytes.NewReader(data))
+		}
+	})
+}
@@ -0,0 +1,51 @@
+package scraper
+
+import (
+	"net/http"
+
+	"golang.org/x/net/proxy"
+
+	"github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// renewer renews proxy circuits, e.g., of Tor.
+type renewer interface{ Renew() }
+
+// SetHostOverrides sets the static IPs and Host headers of hostnames. It
+// must be called before the Scraper is used. Only the transports based on
+// *http.Transport can be pinned to IPs, and requests sent by HTTP proxies
+// are resolved by the proxies.
+func (s *Scraper) SetHostOverrides(overrides ...*provider.HostOverride) {
+	s.hosts = overrides
+	s.applyTransport()
+}
+
+// SetDialer sets the dialer of connections, which replaces HTTP proxies
+// from the environment. It must be called before the Scraper is used.
+// Circuits of the dialer, e.g., of Tor, are renewed when the scraper is
+// throttled or banned. Only the transports based on *http.Transport can
+// be dialed by others.
+func (s *Scraper) SetDialer(dialer proxy.ContextDialer) {
+	s.dialer = dialer
+	if r, ok := dialer.(renewer); ok {
+		s.throttle.mu.Lock()
+		s.throttle.renew = func() {
+			r.Renew()
+			// kept-alive connections are on previous circuits.
+			if s.conns != nil {
+				s.conns.CloseIdleConnections()
+			}
+		}
+		s.throttle.mu.Unlock()
+	}
+	s.applyTransport()
+}
+
+// dialTransport returns the underlying transport dialed by the dialer and
+// pinned to IPs and Host headers of the host overrides, see
+// provider.DialTransport.
+func (s *Scraper) dialTransport() http.RoundTripper {
+	var base http.RoundTripper
+	base, s.conns = provider.DialTransport(s.transport, s.dialer, s.hosts...)
+	return base
+}
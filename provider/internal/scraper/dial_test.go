@@ -0,0 +1,72 @@
+package scraper
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+func TestScraper_HostOverrides(t *testing.T) {
+	var host string
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host = r.Host
+	}))
+	defer srv.Close()
+
+	u, _ := url.Parse(srv.URL)
+	// the certificate of the test server is valid for example.com, which
+	// verifies the SNI is kept while dialing the pinned IPs.
+	rawURL := "https://example.com:" + u.Port()
+
+	transport := &http.Transport{TLSClientConfig: srv.Client().Transport.(*http.Transport).TLSClientConfig}
+	s := NewDefaultScraper("TEST", rawURL, 0, WithTransport(transport))
+	s.SetHostOverrides(&provider.HostOverride{
+		Hostname: "EXAMPLE.com",
+		IPs:      []string{"127.0.0.2", u.Hostname()},
+		Host:     "alt.example.com",
+	})
+	assert.NoError(t, s.ClonedCollector().Visit(rawURL))
+	assert.Equal(t, "alt.example.com", host)
+
+	// the shared transport is never modified.
+	assert.Nil(t, transport.DialContext)
+}
+
+type renewDialer struct {
+	dials, renewals atomic.Int32
+}
+
+func (d *renewDialer) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	d.dials.Add(1)
+	return (&net.Dialer{}).DialContext(ctx, network, addr)
+}
+
+func (d *renewDialer) Renew() { d.renewals.Add(1) }
+
+func TestScraper_SetDialer(t *testing.T) {
+	var n atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if n.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}
+	}))
+	defer srv.Close()
+
+	d := &renewDialer{}
+	s := NewDefaultScraper("TEST", srv.URL, 0)
+	s.SetDialer(d)
+	assert.NoError(t, s.ClonedCollector().Visit(srv.URL))
+	assert.Equal(t, int32(2), n.Load())
+	assert.Positive(t, d.dials.Load())
+	// throttled once, renewed once.
+	assert.Equal(t, int32(1), d.renewals.Load())
+}
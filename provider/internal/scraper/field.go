@@ -0,0 +1,25 @@
+package scraper
+
+import (
+	"slices"
+
+	"github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// SetLazyFields sets the expensive fields skipped by getting movie infos,
+// providers resolve them by EnrichMovieInfo instead. It must be called
+// before the Scraper is used.
+func (s *Scraper) SetLazyFields(fields ...provider.Field) {
+	s.lazyFields = fields
+}
+
+// IsLazy reports whether the field is skipped by getting movie infos.
+func (s *Scraper) IsLazy(field provider.Field) bool {
+	return slices.Contains(s.lazyFields, field)
+}
+
+// EnrichesField reports whether the field is requested to be enriched,
+// all fields are requested if none.
+func EnrichesField(fields []provider.Field, field provider.Field) bool {
+	return len(fields) == 0 || slices.Contains(fields, field)
+}
@@ -0,0 +1,118 @@
+package scraper
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/metatube-community/metatube-sdk-go/errors"
+)
+
+// DefaultMaxResponseSize is the default max size of decoded responses,
+// which is the default body size limit of colly.
+const DefaultMaxResponseSize = 10 << 20
+
+// HTMLContentTypes are the media types of web pages, for scrapers that
+// only parse pages.
+var HTMLContentTypes = []string{"text/html", "application/xhtml+xml"}
+
+var (
+	ErrResponseTooLarge      = errors.New(http.StatusBadGateway, "response too large")
+	ErrUnexpectedContentType = errors.New(http.StatusBadGateway, "unexpected content type")
+)
+
+// responseGuard rejects responses which are too large or of unexpected
+// content types, before they are buffered and parsed.
+type responseGuard struct {
+	// max size of decoded responses, zero means unlimited.
+	maxSize int64
+	// accepted media types, e.g., `text/html` or `text/*`, empty means
+	// any. Responses without content types are always accepted.
+	contentTypes []string
+}
+
+func (g *responseGuard) accept(contentType string) bool {
+	if len(g.contentTypes) == 0 || contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range g.contentTypes {
+		if prefix, ok := strings.CutSuffix(t, "/*"); ok {
+			if strings.HasPrefix(mediaType, prefix+"/") {
+				return true
+			}
+		} else if mediaType == t {
+			return true
+		}
+	}
+	return false
+}
+
+// WithMaxResponseSize limits the size of decoded responses, responses
+// exceeding it fail with ErrResponseTooLarge instead of being truncated.
+func WithMaxResponseSize(size int64) Option {
+	return func(s *Scraper) error {
+		s.guard.maxSize = size
+		return nil
+	}
+}
+
+// WithContentTypes limits the media types of responses, e.g., `text/html`
+// or `text/*`, other responses fail with ErrUnexpectedContentType.
+func WithContentTypes(types ...string) Option {
+	return func(s *Scraper) error {
+		s.guard.contentTypes = types
+		return nil
+	}
+}
+
+// guardTransport applies the response guard of the scraper, sizes are
+// checked by `Content-Length` first, and then by the bytes read.
+type guardTransport struct {
+	base  http.RoundTripper
+	guard *responseGuard
+}
+
+func (t *guardTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	if !t.guard.accept(resp.Header.Get("Content-Type")) {
+		_ = resp.Body.Close()
+		return nil, ErrUnexpectedContentType
+	}
+	if maxSize := t.guard.maxSize; maxSize > 0 {
+		if resp.ContentLength > maxSize {
+			_ = resp.Body.Close()
+			return nil, ErrResponseTooLarge
+		}
+		resp.Body = &limitedBody{ReadCloser: resp.Body, remaining: maxSize + 1}
+	}
+	return resp, nil
+}
+
+// limitedBody fails reads with ErrResponseTooLarge once more bytes than
+// the limit are read.
+type limitedBody struct {
+	io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (n int, err error) {
+	if b.remaining <= 0 {
+		return 0, ErrResponseTooLarge
+	}
+	if int64(len(p)) > b.remaining {
+		p = p[:b.remaining]
+	}
+	n, err = b.ReadCloser.Read(p)
+	if b.remaining -= int64(n); b.remaining <= 0 {
+		return n - 1, ErrResponseTooLarge
+	}
+	return n, err
+}
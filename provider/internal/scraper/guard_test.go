@@ -0,0 +1,70 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResponseGuard(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/binary":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			_, _ = w.Write(make([]byte, 64))
+		case "/huge":
+			w.Header().Set("Content-Type", "text/html")
+			w.Header().Set("Content-Length", "4096")
+			_, _ = w.Write(make([]byte, 4096))
+		case "/chunked":
+			w.Header().Set("Content-Type", "text/html")
+			for i := 0; i < 4; i++ {
+				_, _ = w.Write([]byte(strings.Repeat("x", 512)))
+				w.(http.Flusher).Flush()
+			}
+		default:
+			w.Header().Set("Content-Type", "text/html; charset=utf-8")
+			_, _ = w.Write([]byte("<html><title>ok</title></html>"))
+		}
+	}))
+	defer srv.Close()
+
+	s := NewDefaultScraper("test", srv.URL, 0,
+		WithMaxResponseSize(1024),
+		WithContentTypes("text/*", "application/json"))
+
+	var title string
+	c := s.ClonedCollector()
+	c.OnXML("//title", func(e *colly.XMLElement) { title = e.Text })
+	assert.NoError(t, c.Visit(srv.URL))
+	assert.Equal(t, "ok", title)
+
+	for path, want := range map[string]error{
+		"/binary":  ErrUnexpectedContentType,
+		"/huge":    ErrResponseTooLarge,
+		"/chunked": ErrResponseTooLarge,
+	} {
+		err := s.ClonedCollector().Visit(srv.URL + path)
+		assert.ErrorIs(t, err, want, path)
+	}
+}
+
+func TestResponseGuardAccept(t *testing.T) {
+	g := &responseGuard{contentTypes: []string{"text/*", "application/json"}}
+	assert.True(t, g.accept("text/html; charset=utf-8"))
+	assert.True(t, g.accept("application/json"))
+	assert.True(t, g.accept(""))
+	assert.False(t, g.accept("application/octet-stream"))
+	assert.False(t, g.accept("image/jpeg"))
+	assert.True(t, (&responseGuard{}).accept("image/jpeg"))
+
+	html := &responseGuard{contentTypes: HTMLContentTypes}
+	assert.True(t, html.accept("text/html; charset=Shift_JIS"))
+	assert.True(t, html.accept("application/xhtml+xml"))
+	assert.False(t, html.accept("application/json"))
+	assert.False(t, html.accept("video/mp4"))
+}
@@ -0,0 +1,76 @@
+package scraper
+
+import (
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// LocaleStrategy declares the languages of a multilingual site and how
+// to locate the localized pages.
+type LocaleStrategy struct {
+	// Default is the native language of the site.
+	Default string
+	// Supported are all languages of the site, including the default.
+	Supported []string
+	// Localize returns the URL of the page in the language, it's optional
+	// if the site negotiates languages with `Accept-Language` only.
+	Localize func(rawURL, lang string) string
+}
+
+// negotiate returns the first supported language of the preferred ones,
+// or empty if none is supported. Languages are matched by base tags,
+// e.g., `zh-CN` matches `zh`.
+func (l *LocaleStrategy) negotiate(preferred []string) string {
+	for _, p := range preferred {
+		base, _, _ := strings.Cut(strings.ToLower(strings.TrimSpace(p)), "-")
+		for _, s := range l.Supported {
+			if sb, _, _ := strings.Cut(strings.ToLower(s), "-"); sb == base {
+				return s
+			}
+		}
+	}
+	return ""
+}
+
+// WithLocales enables language negotiation of the site.
+func WithLocales(strategy *LocaleStrategy) Option {
+	return func(s *Scraper) error {
+		s.locales = strategy
+		return nil
+	}
+}
+
+// NegotiateLanguage returns the first language of the preferred ones
+// supported by the site, or empty if none is supported or the site is
+// not multilingual. Languages are negotiated per call, e.g., by request.
+func (s *Scraper) NegotiateLanguage(langs ...string) string {
+	if s.locales == nil {
+		return ""
+	}
+	return s.locales.negotiate(langs)
+}
+
+// NativeLanguage returns the native language of the site, or empty if the
+// site is not multilingual.
+func (s *Scraper) NativeLanguage() string {
+	if s.locales == nil {
+		return ""
+	}
+	return s.locales.Default
+}
+
+// LocalizedCollector returns a cloned collector requesting pages in the
+// language by `Accept-Language`, and the URL of the page in the language.
+func (s *Scraper) LocalizedCollector(rawURL, lang string) (*colly.Collector, string) {
+	c := s.ClonedCollector()
+	c.OnRequest(func(r *colly.Request) {
+		if r.Headers.Get("Accept-Language") == "" {
+			r.Headers.Set("Accept-Language", lang)
+		}
+	})
+	if s.locales != nil && s.locales.Localize != nil {
+		rawURL = s.locales.Localize(rawURL, lang)
+	}
+	return c, rawURL
+}
@@ -0,0 +1,52 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocaleStrategy_Negotiate(t *testing.T) {
+	l := &LocaleStrategy{Default: "ja", Supported: []string{"ja", "en", "zh-TW"}}
+	for _, unit := range []struct {
+		preferred []string
+		want      string
+	}{
+		{nil, ""},
+		{[]string{"fr"}, ""},
+		{[]string{"en-US"}, "en"},
+		{[]string{"fr", "zh-CN", "en"}, "zh-TW"},
+		{[]string{"EN"}, "en"},
+	} {
+		assert.Equal(t, unit.want, l.negotiate(unit.preferred), unit.preferred)
+	}
+}
+
+func TestScraper_Language(t *testing.T) {
+	var header string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Get("Accept-Language")
+	}))
+	defer srv.Close()
+
+	s := NewDefaultScraper("test", srv.URL, 0, WithLocales(&LocaleStrategy{
+		Default:   "ja",
+		Supported: []string{"ja", "en"},
+		Localize:  func(rawURL, lang string) string { return rawURL + "?lang=" + lang },
+	}))
+	assert.Equal(t, "en", s.NegotiateLanguage("en-GB", "ja"))
+	assert.Equal(t, "", s.NegotiateLanguage("fr"))
+	assert.Equal(t, "ja", s.NativeLanguage())
+
+	c, rawURL := s.LocalizedCollector(srv.URL, "en")
+	assert.Equal(t, srv.URL+"?lang=en", rawURL)
+	assert.NoError(t, c.Visit(rawURL))
+	assert.Equal(t, "en", header)
+
+	// other requests are not localized.
+	header = ""
+	assert.NoError(t, s.ClonedCollector().Visit(srv.URL))
+	assert.Equal(t, "", header)
+}
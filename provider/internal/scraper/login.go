@@ -0,0 +1,228 @@
+package scraper
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/cookiejar"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+
+	"github.com/metatube-community/metatube-sdk-go/common/storage"
+	"github.com/metatube-community/metatube-sdk-go/errors"
+)
+
+// loginRetryInterval is the interval to retry after a failed login, so
+// that bad credentials won't hammer the site on every scrape.
+const loginRetryInterval = 10 * time.Minute
+
+var ErrLoginFailed = errors.New(http.StatusUnauthorized, "login failed")
+
+// Login is a declarative rule to log in member-only contents, which is
+// disabled until credentials are set, and then performed once per session
+// like the age gate.
+type Login struct {
+	// Realm identifies accounts shared by sites, e.g., single sign-on.
+	Realm string
+	// URL is posted with the login form.
+	URL string
+	// Form builds the login form from the credentials.
+	Form func(username, password string) map[string]string
+	// Verify reports whether the login succeeded from the response of URL.
+	Verify func(r *colly.Response) bool
+	// Detect reports whether the response is logged out, which starts a
+	// new session so that it logs in again next time.
+	Detect func(r *colly.Response) bool
+}
+
+type loginState struct {
+	login    *Login
+	username string
+	password string
+	// session is shared by scrapers of the same account.
+	session *loginSession
+	// store persists sessions across restarts, nil if disabled.
+	store storage.KV
+}
+
+// loginSession is the session of an account, which is shared by scrapers
+// of the same realm, e.g., sites of a single sign-on, so that it logs in
+// once for all of them with the same cookie jar.
+type loginSession struct {
+	mu       sync.Mutex
+	loggedIn bool
+	failedAt time.Time
+	jar      http.CookieJar
+}
+
+var (
+	sessionsMu sync.Mutex
+	sessions   = make(map[string]*loginSession)
+)
+
+// sharedSession returns the session of the account of the login rule,
+// which is created on first use.
+func sharedSession(login *Login, username, password string) *loginSession {
+	key := login.Realm + "\x00" + login.URL + "\x00" + username + "\x00" + password
+	sessionsMu.Lock()
+	defer sessionsMu.Unlock()
+	if session, ok := sessions[key]; ok {
+		return session
+	}
+	jar, _ := cookiejar.New(nil)
+	session := &loginSession{jar: jar}
+	sessions[key] = session
+	return session
+}
+
+// sessionKey returns the key of the stored session of the account.
+func (s *loginState) sessionKey() string {
+	return "session:" + s.login.Realm + ":" + s.username
+}
+
+// restore restores the stored session into the cookie jar, sessions
+// expired are detected by Detect later.
+func (s *loginState) restore() bool {
+	u, err := url.Parse(s.login.URL)
+	if s.store == nil || err != nil {
+		return false
+	}
+	data, ok := s.store.Get(s.sessionKey())
+	if !ok {
+		return false
+	}
+	var cookies []*http.Cookie
+	if json.Unmarshal(data, &cookies) != nil || len(cookies) == 0 {
+		return false
+	}
+	s.session.jar.SetCookies(u, cookies)
+	return true
+}
+
+// save stores the session cookies of the login URL, errors are ignored.
+func (s *loginState) save() {
+	u, err := url.Parse(s.login.URL)
+	if s.store == nil || err != nil {
+		return
+	}
+	if data, err := json.Marshal(s.session.jar.Cookies(u)); err == nil {
+		_ = s.store.Set(s.sessionKey(), data)
+	}
+}
+
+// pass logs in with the collector if the session is not logged in yet.
+func (s *loginState) pass(c *colly.Collector) error {
+	if s.username == "" {
+		return nil
+	}
+	s.session.mu.Lock()
+	defer s.session.mu.Unlock()
+	if s.session.loggedIn {
+		return nil
+	}
+	if time.Since(s.session.failedAt) < loginRetryInterval {
+		return ErrLoginFailed
+	}
+	if s.restore() {
+		s.session.loggedIn = true
+		return nil
+	}
+	verified := s.login.Verify == nil
+	c.OnRequest(func(r *colly.Request) {
+		// the form is always posted, even if scrapers request JSON.
+		r.Headers.Set("Content-Type", "application/x-www-form-urlencoded")
+	})
+	c.OnResponse(func(r *colly.Response) {
+		if s.login.Verify != nil && s.login.Verify(r) {
+			verified = true
+		}
+	})
+	if err := c.Post(s.login.URL, s.login.Form(s.username, s.password)); err != nil || !verified {
+		s.session.failedAt = time.Now()
+		return ErrLoginFailed
+	}
+	s.session.loggedIn = true
+	s.save()
+	return nil
+}
+
+// verified reports whether it's logged in, or the stored session is
+// restored, which holds until it's detected logged out.
+func (s *loginState) verified() bool {
+	s.session.mu.Lock()
+	defer s.session.mu.Unlock()
+	return s.session.loggedIn
+}
+
+func (s *loginState) reset() {
+	s.session.mu.Lock()
+	defer s.session.mu.Unlock()
+	s.session.loggedIn = false
+	if s.store != nil {
+		_ = s.store.Delete(s.sessionKey()) // log in again next time.
+	}
+}
+
+// WithLogin registers the login rule of member-only contents.
+func WithLogin(login *Login) Option {
+	return func(s *Scraper) error {
+		s.login = &loginState{login: login}
+		return nil
+	}
+}
+
+// CredentialRealm returns the realm of the login rule, or empty if the
+// scraper has no login rule.
+func (s *Scraper) CredentialRealm() string {
+	if s.login == nil {
+		return ""
+	}
+	return s.login.login.Realm
+}
+
+// SetCredentials enables the login rule with the account, a cookie jar is
+// always used to keep the session, which is shared by scrapers of the same
+// realm and account, so sites of a single sign-on log in only once. It
+// must be called before the Scraper is used.
+func (s *Scraper) SetCredentials(username, password string) {
+	if s.login == nil || username == "" {
+		return
+	}
+	s.login.username, s.login.password = username, password
+	s.login.session = sharedSession(s.login.login, username, password)
+	s.c.SetCookieJar(s.login.session.jar)
+}
+
+// SetSessionStore persists member sessions in the store, so that they
+// survive restarts without logging in again. It must be called before the
+// Scraper is used.
+func (s *Scraper) SetSessionStore(store storage.KV) {
+	if s.login == nil {
+		return
+	}
+	s.login.store = store
+}
+
+// MemberCookieJar returns the cookie jar of the member session, which
+// should be used by other HTTP clients of member-only resources, or nil
+// if it's not logged in.
+func (s *Scraper) MemberCookieJar() http.CookieJar {
+	if !s.IsMember() {
+		return nil
+	}
+	return s.login.session.jar
+}
+
+// IsMember reports whether it's logged in with the credentials, so
+// member-only contents can be scraped. Logins are performed by collectors
+// returned by ClonedCollector.
+func (s *Scraper) IsMember() bool {
+	return s.hasCredentials() && s.login.verified()
+}
+
+// hasCredentials reports whether the credentials are set.
+func (s *Scraper) hasCredentials() bool {
+	return s.login != nil && s.login.username != ""
+}
@@ -0,0 +1,131 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/common/storage"
+)
+
+func TestScraper_Login(t *testing.T) {
+	var logins atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			logins.Add(1)
+			if r.FormValue("user") != "u" || r.FormValue("pass") != "p" {
+				w.WriteHeader(http.StatusForbidden)
+				return
+			}
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "1", Path: "/"})
+		default:
+			if _, err := r.Cookie("session"); err != nil {
+				w.Write([]byte("public"))
+				return
+			}
+			w.Write([]byte("member"))
+		}
+	}))
+	defer srv.Close()
+
+	newScraper := func() *Scraper {
+		return NewDefaultScraper("test", srv.URL, 0, WithDisableCookies(), WithLogin(&Login{
+			Realm: "test",
+			URL:   srv.URL + "/login",
+			Form: func(username, password string) map[string]string {
+				return map[string]string{"user": username, "pass": password}
+			},
+			Verify: func(r *colly.Response) bool { return r.StatusCode == http.StatusOK },
+		}))
+	}
+	visit := func(s *Scraper) (body string) {
+		c := s.ClonedCollector()
+		c.OnResponse(func(r *colly.Response) { body = string(r.Body) })
+		_ = c.Visit(srv.URL + "/movie")
+		return
+	}
+
+	// disabled without credentials.
+	s := newScraper()
+	assert.Equal(t, "test", s.CredentialRealm())
+	assert.False(t, s.IsMember())
+	assert.Equal(t, "public", visit(s))
+	assert.Equal(t, int32(0), logins.Load())
+
+	s.SetCredentials("u", "p")
+	assert.False(t, s.IsMember(), "not logged in yet")
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "member", visit(s))
+		assert.True(t, s.IsMember())
+	}
+	assert.Equal(t, int32(1), logins.Load())
+
+	// failed login is not retried immediately.
+	s = newScraper()
+	s.SetCredentials("u", "wrong")
+	for i := 0; i < 3; i++ {
+		assert.Equal(t, "public", visit(s))
+		assert.False(t, s.IsMember(), "login failed")
+		assert.Nil(t, s.MemberCookieJar())
+	}
+	assert.Equal(t, int32(2), logins.Load())
+}
+
+func TestScraper_SessionStore(t *testing.T) {
+	var logins atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			logins.Add(1)
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "1", Path: "/"})
+		default:
+			if _, err := r.Cookie("session"); err != nil {
+				w.Write([]byte("public"))
+				return
+			}
+			w.Write([]byte("member"))
+		}
+	}))
+	defer srv.Close()
+
+	store := storage.NewMemory(0)
+	newScraper := func() *Scraper {
+		s := NewDefaultScraper("test", srv.URL, 0, WithDisableCookies(), WithLogin(&Login{
+			Realm: "test",
+			URL:   srv.URL + "/login",
+			Form: func(username, password string) map[string]string {
+				return map[string]string{"user": username, "pass": password}
+			},
+		}))
+		s.SetCredentials("u", "p")
+		s.SetSessionStore(store)
+		return s
+	}
+	visit := func(s *Scraper) (body string) {
+		c := s.ClonedCollector()
+		c.OnResponse(func(r *colly.Response) { body = string(r.Body) })
+		_ = c.Visit(srv.URL + "/movie")
+		return
+	}
+
+	assert.Equal(t, "member", visit(newScraper()))
+	assert.Equal(t, int32(1), logins.Load())
+
+	// restored after restarts.
+	s := newScraper()
+	assert.Equal(t, "member", visit(s))
+	assert.Equal(t, int32(1), logins.Load())
+
+	assert.True(t, s.IsMember())
+
+	// logged out sessions are dropped.
+	s.login.reset()
+	assert.False(t, s.IsMember())
+	_, ok := store.Get(s.login.sessionKey())
+	assert.False(t, ok)
+}
@@ -7,11 +7,21 @@ import (
 	"github.com/gocolly/colly/v2"
 	"github.com/gocolly/colly/v2/debug"
 
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
 	"github.com/metatube-community/metatube-sdk-go/common/random"
 )
 
 type Option func(*Scraper) error
 
+// WithDisplayName sets the human-readable name of the provider, which is
+// credited as the source of infos.
+func WithDisplayName(name string) Option {
+	return func(s *Scraper) error {
+		s.displayName = name
+		return nil
+	}
+}
+
 func WithAllowURLRevisit() Option {
 	return func(s *Scraper) error {
 		colly.AllowURLRevisit()(s.c)
@@ -40,9 +50,16 @@ func WithIgnoreRobotsTxt() Option {
 	}
 }
 
+// WithHeaders sets headers of requests, which are merged with headers
+// set before, e.g., of the browser profile.
 func WithHeaders(headers map[string]string) Option {
 	return func(s *Scraper) error {
-		colly.Headers(headers)(s.c)
+		if s.c.Headers == nil {
+			s.c.Headers = &http.Header{}
+		}
+		for key, value := range headers {
+			s.c.Headers.Set(key, value)
+		}
 		return nil
 	}
 }
@@ -54,8 +71,21 @@ func WithUserAgent(ua string) Option {
 	}
 }
 
-func WithRandomUserAgent() Option {
-	return WithUserAgent(random.UserAgent())
+// WithBrowserProfile sets the User-Agent and navigation headers of the
+// browser profile, which are kept for the lifetime of the Scraper.
+func WithBrowserProfile(p *random.Profile) Option {
+	return func(s *Scraper) error {
+		colly.UserAgent(p.UserAgent)(s.c)
+		return WithHeaders(p.NavigationHeaders())(s)
+	}
+}
+
+// WithRandomBrowserProfile sets a random browser profile, see
+// WithBrowserProfile.
+func WithRandomBrowserProfile() Option {
+	return func(s *Scraper) error {
+		return WithBrowserProfile(random.BrowserProfile())(s)
+	}
 }
 
 func WithCookies(url string, cookies []*http.Cookie) Option {
@@ -71,6 +101,15 @@ func WithRequestTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithSubRequestTimeout sets the timeout of nested sub-requests, see
+// SetSubRequestTimeout.
+func WithSubRequestTimeout(timeout time.Duration) Option {
+	return func(s *Scraper) error {
+		s.SetSubRequestTimeout(timeout)
+		return nil
+	}
+}
+
 func WithDisableCookies() Option {
 	return func(s *Scraper) error {
 		s.c.DisableCookies()
@@ -90,7 +129,24 @@ func WithDisableRedirects() Option {
 
 func WithTransport(transport http.RoundTripper) Option {
 	return func(s *Scraper) error {
-		s.c.WithTransport(transport)
+		s.transport = transport
+		s.applyTransport()
+		return nil
+	}
+}
+
+// WithArchiveFallback enables the archive fallback, see SetArchiveFallback.
+func WithArchiveFallback() Option {
+	return func(s *Scraper) error {
+		s.SetArchiveFallback(true)
+		return nil
+	}
+}
+
+// WithClient sets the HTTP backend of the scraper, see SetFetchClient.
+func WithClient(client fetch.Client) Option {
+	return func(s *Scraper) error {
+		s.SetFetchClient(client)
 		return nil
 	}
 }
@@ -100,3 +156,12 @@ func WithLimit(rule *colly.LimitRule) Option {
 		return s.c.Limit(rule)
 	}
 }
+
+// WithThrottleDetector detects provider-specific throttle pages, which
+// pause the scraper as `Retry-After` does.
+func WithThrottleDetector(detector ThrottleDetector) Option {
+	return func(s *Scraper) error {
+		s.throttle.detectors = append(s.throttle.detectors, detector)
+		return nil
+	}
+}
@@ -0,0 +1,239 @@
+package scraper
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/gocolly/colly/v2"
+	"golang.org/x/net/html"
+)
+
+// DefaultMaxPages is the number of pages crawled from each start URL if
+// the listing doesn't limit it.
+const DefaultMaxPages = 3
+
+// Listing describes paginated listings, e.g., of search results, the
+// filmography or series entries.
+type Listing[T any] struct {
+	// ItemSelector is the XPath of items in each page.
+	ItemSelector string
+	// NextSelector is the XPath of the link to the next page, the first
+	// matched href is followed.
+	NextSelector string
+	// MaxPages limits pages crawled from each start URL, DefaultMaxPages
+	// if zero.
+	MaxPages int
+	// Parse parses the item, which is skipped if not ok.
+	Parse func(e *colly.XMLElement) (item T, ok bool)
+	// Key returns the key of the item to dedupe by, items with empty keys
+	// are kept. Items are not deduped if nil.
+	Key func(item T) string
+	// Stream tokenizes pages instead of parsing their whole DOMs, each
+	// element of the item or next selector is parsed into a DOM of its
+	// own, so that only one element is held at a time, e.g., for large
+	// listings. Selectors must be of the form `//tag[...]`, of which the
+	// predicates refer to the attributes of the element only, and table
+	// rows are not supported. Pages are parsed as a whole if
+	// the selectors are not of the form.
+	Stream bool
+}
+
+// Crawl crawls the listing from the start URLs concurrently, items are
+// returned in the order of start URLs and pages, deduped by the keys.
+// Pages are followed until no next link is found or MaxPages reached.
+// Partial listings are returned as long as any of the start URLs is
+// crawled, otherwise the first error is returned.
+func Crawl[T any](s *Scraper, l *Listing[T], urls ...string) ([]T, error) {
+	pages := make([][]T, len(urls))
+	errs := make([]error, len(urls))
+
+	var wg sync.WaitGroup
+	for i, u := range urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pages[i], errs[i] = crawl(s, l, u)
+		}()
+	}
+	wg.Wait()
+
+	var (
+		items   []T
+		crawled bool
+	)
+	seen := make(map[string]struct{})
+	for i := range urls {
+		if errs[i] != nil {
+			continue
+		}
+		crawled = true
+		for _, item := range pages[i] {
+			if l.Key != nil {
+				if key := l.Key(item); key != "" {
+					if _, ok := seen[key]; ok {
+						continue
+					}
+					seen[key] = struct{}{}
+				}
+			}
+			items = append(items, item)
+		}
+	}
+	if !crawled && len(errs) > 0 {
+		return nil, errs[0]
+	}
+	return items, nil
+}
+
+// crawl crawls the pages of the listing from the URL sequentially.
+func crawl[T any](s *Scraper, l *Listing[T], rawURL string) (items []T, err error) {
+	maxPages := l.MaxPages
+	if maxPages <= 0 {
+		maxPages = DefaultMaxPages
+	}
+
+	var next string
+	c := s.ClonedCollector()
+	itemTag, nextTag, stream := streamTags(l)
+	if stream {
+		c.OnResponse(func(r *colly.Response) {
+			streamPage(r, l, itemTag, nextTag, &items, &next)
+		})
+	} else {
+		c.OnXML(l.ItemSelector, func(e *colly.XMLElement) {
+			if item, ok := l.Parse(e); ok {
+				items = append(items, item)
+			}
+		})
+		if l.NextSelector != "" {
+			c.OnXML(l.NextSelector, func(e *colly.XMLElement) {
+				if href := e.Attr("href"); next == "" && href != "" {
+					next = e.Request.AbsoluteURL(href)
+				}
+			})
+		}
+	}
+
+	// revisits are allowed by default, so loops are guarded here.
+	visited := make(map[string]struct{}, maxPages)
+	for page := 0; rawURL != "" && page < maxPages; page++ {
+		if _, ok := visited[rawURL]; ok {
+			break
+		}
+		visited[rawURL] = struct{}{}
+		next = ""
+		if err = c.Visit(rawURL); err != nil {
+			if page == 0 {
+				return nil, err
+			}
+			return items, nil // keep pages crawled.
+		}
+		rawURL = next
+	}
+	return items, nil
+}
+
+var streamSelectorRegexp = regexp.MustCompile(`^//([a-zA-Z][a-zA-Z\d]*)(?:\[.*])?$`)
+
+// streamTags returns the tags of the item and next selectors, and whether
+// the listing is crawled by streaming, see Listing.Stream.
+func streamTags[T any](l *Listing[T]) (itemTag, nextTag string, ok bool) {
+	if !l.Stream {
+		return
+	}
+	m := streamSelectorRegexp.FindStringSubmatch(l.ItemSelector)
+	if m == nil {
+		return
+	}
+	itemTag = strings.ToLower(m[1])
+	if l.NextSelector != "" {
+		if m = streamSelectorRegexp.FindStringSubmatch(l.NextSelector); m == nil {
+			return
+		}
+		nextTag = strings.ToLower(m[1])
+	}
+	return itemTag, nextTag, true
+}
+
+// streamPage tokenizes the page, and parses the elements of the item or
+// next selectors one by one. Elements not closed are parsed at the end.
+func streamPage[T any](r *colly.Response, l *Listing[T], itemTag, nextTag string, items *[]T, next *string) {
+	var (
+		z     = html.NewTokenizer(bytes.NewReader(r.Body))
+		buf   bytes.Buffer
+		tag   string
+		depth int
+	)
+	for {
+		tt := z.Next()
+		if tt == html.ErrorToken {
+			if depth > 0 {
+				streamElement(r, l, buf.Bytes(), items, next)
+			}
+			return
+		}
+		raw := z.Raw()
+		name, _ := z.TagName()
+		if depth == 0 {
+			if tt == html.StartTagToken && (string(name) == itemTag || string(name) == nextTag) &&
+				streamMatch(raw, string(name), l.ItemSelector, l.NextSelector) {
+				tag, depth = string(name), 1
+				buf.Reset()
+				buf.Write(raw)
+			}
+			continue
+		}
+		buf.Write(raw)
+		if string(name) == tag {
+			switch tt {
+			case html.StartTagToken:
+				depth++
+			case html.EndTagToken:
+				depth--
+			}
+		}
+		if depth == 0 {
+			streamElement(r, l, buf.Bytes(), items, next)
+		}
+	}
+}
+
+// streamMatch reports whether the element of the start tag matches any of
+// the selectors by its attributes.
+func streamMatch(startTag []byte, tag string, selectors ...string) bool {
+	doc, err := htmlquery.Parse(bytes.NewReader(append(append([]byte{}, startTag...), "</"+tag+">"...)))
+	if err != nil {
+		return false
+	}
+	for _, selector := range selectors {
+		if selector != "" && htmlquery.FindOne(doc, selector) != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// streamElement parses the element into its own DOM, and matches it with
+// the item and next selectors.
+func streamElement[T any](r *colly.Response, l *Listing[T], raw []byte, items *[]T, next *string) {
+	doc, err := htmlquery.Parse(bytes.NewReader(raw))
+	if err != nil {
+		return
+	}
+	for _, n := range htmlquery.Find(doc, l.ItemSelector) {
+		if item, ok := l.Parse(colly.NewXMLElementFromHTMLNode(r, n)); ok {
+			*items = append(*items, item)
+		}
+	}
+	if l.NextSelector != "" && *next == "" {
+		for _, n := range htmlquery.Find(doc, l.NextSelector) {
+			if href := htmlquery.SelectAttr(n, "href"); href != "" {
+				*next = r.Request.AbsoluteURL(href)
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func newListingServer() *httptest.Server {
+	mux := http.NewServeMux()
+	// pages of 2 items, `b` overlaps `a` from the 2nd item.
+	mux.HandleFunc("/{list}/{page}", func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.PathValue("page"))
+		offset := 0
+		if r.PathValue("list") == "b" {
+			offset = 1
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body>`)
+		for i := 0; i < 2; i++ {
+			fmt.Fprintf(w, `<p class="item">%d</p>`, offset+page*2+i)
+		}
+		fmt.Fprintf(w, `<a class="next" href="%d">next</a></body></html>`, page+1)
+	})
+	mux.HandleFunc("/loop", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><p class="item">0</p><a class="next" href="/loop">next</a></body></html>`)
+	})
+	return httptest.NewServer(mux)
+}
+
+func newTestListing(maxPages int) *Listing[string] {
+	return &Listing[string]{
+		ItemSelector: `//p[@class="item"]`,
+		NextSelector: `//a[@class="next"]`,
+		MaxPages:     maxPages,
+		Parse:        func(e *colly.XMLElement) (string, bool) { return e.Text, e.Text != "" },
+		Key:          func(item string) string { return item },
+	}
+}
+
+func TestCrawl(t *testing.T) {
+	srv := newListingServer()
+	defer srv.Close()
+
+	s := NewDefaultScraper("TEST", srv.URL, 0)
+
+	for _, unit := range []struct {
+		name     string
+		maxPages int
+		urls     []string
+		want     []string
+		wantErr  bool
+	}{
+		{"single page", 1, []string{srv.URL + "/a/0"}, []string{"0", "1"}, false},
+		{"max pages", 3, []string{srv.URL + "/a/0"}, []string{"0", "1", "2", "3", "4", "5"}, false},
+		{"default max pages", 0, []string{srv.URL + "/a/0"}, []string{"0", "1", "2", "3", "4", "5"}, false},
+		{"deduped", 2, []string{srv.URL + "/a/0", srv.URL + "/b/0"}, []string{"0", "1", "2", "3", "4"}, false},
+		{"loop", 5, []string{srv.URL + "/loop"}, []string{"0"}, false},
+		{"partial", 1, []string{srv.URL + "/missing", srv.URL + "/a/1"}, []string{"2", "3"}, false},
+		{"failed", 1, []string{srv.URL + "/missing"}, nil, true},
+	} {
+		for _, stream := range []bool{false, true} {
+			t.Run(fmt.Sprintf("%s/stream=%t", unit.name, stream), func(t *testing.T) {
+				l := newTestListing(unit.maxPages)
+				l.Stream = stream
+				items, err := Crawl(s, l, unit.urls...)
+				if unit.wantErr {
+					assert.Error(t, err)
+					return
+				}
+				if assert.NoError(t, err) {
+					assert.Equal(t, unit.want, items)
+				}
+			})
+		}
+	}
+}
+
+func TestCrawl_Stream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><div class="list">`)
+		for i := 0; i < 1000; i++ {
+			// items of nested elements of the same tag.
+			fmt.Fprintf(w, `<div class="item"><div><b>%d</b></div></div>`, i)
+		}
+		// the next link of the same tag as items.
+		fmt.Fprint(w, `</div><div class="item"><a href="/next">next</a>`) // not closed.
+	}))
+	defer srv.Close()
+
+	s := NewDefaultScraper("TEST", srv.URL, 0)
+	l := &Listing[string]{
+		ItemSelector: `//div[@class="item"]`,
+		NextSelector: `//a`,
+		MaxPages:     1,
+		Parse: func(e *colly.XMLElement) (string, bool) {
+			text := e.ChildText(`.//b`)
+			return text, text != ""
+		},
+		Stream: true,
+	}
+	itemTag, nextTag, ok := streamTags(l)
+	assert.True(t, ok)
+	assert.Equal(t, "div", itemTag)
+	assert.Equal(t, "a", nextTag)
+	// containers of items are not parsed as a whole.
+	assert.False(t, streamMatch([]byte(`<div class="list">`), "div", l.ItemSelector, l.NextSelector))
+	assert.True(t, streamMatch([]byte(`<div class="item">`), "div", l.ItemSelector, l.NextSelector))
+
+	items, err := Crawl(s, l, srv.URL)
+	if assert.NoError(t, err) && assert.Len(t, items, 1000) {
+		assert.Equal(t, "0", items[0])
+		assert.Equal(t, "999", items[999])
+	}
+
+	// parsed as a whole if selectors refer to ancestors.
+	l.ItemSelector = `//div[@class="list"]/div`
+	_, _, ok = streamTags(l)
+	assert.False(t, ok)
+	items, err = Crawl(s, l, srv.URL)
+	if assert.NoError(t, err) {
+		assert.Len(t, items, 1000)
+	}
+}
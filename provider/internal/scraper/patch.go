@@ -0,0 +1,88 @@
+package scraper
+
+import (
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/common/parser"
+	"github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// SetSelectorPatches sets the patches of info fields, which are applied
+// by PatchCollector. It must be called before the Scraper is used.
+func (s *Scraper) SetSelectorPatches(patches ...*provider.SelectorPatch) {
+	s.patches = patches
+}
+
+// PatchCollector registers the selector patches of the info fields on the
+// collector, it must be called after the built-in callbacks are registered,
+// so that the patches are applied to what they have parsed.
+func (s *Scraper) PatchCollector(c *colly.Collector, info any) {
+	v := reflect.ValueOf(info).Elem()
+	for _, patch := range s.patches {
+		f, ok := fieldByJSONName(v, patch.Field)
+		if !ok || patch.XPath == "" {
+			continue // unknown fields are ignored.
+		}
+		var first, skip bool
+		c.OnResponse(func(*colly.Response) { first = true })
+		c.OnXML(patch.XPath, func(e *colly.XMLElement) {
+			if first {
+				first = false
+				skip = patch.Supplement && !f.IsZero()
+				if !skip && f.Type() == reflect.TypeOf(pq.StringArray{}) {
+					f.Set(reflect.ValueOf(pq.StringArray{})) // overridden.
+				}
+			} else if f.Type() != reflect.TypeOf(pq.StringArray{}) {
+				return // only the first node of scalar fields.
+			}
+			if skip {
+				return
+			}
+			value := e.Text
+			if patch.Attr != "" {
+				value = e.Attr(patch.Attr)
+			}
+			setField(f, patch.Field, strings.TrimSpace(value))
+		})
+	}
+}
+
+func fieldByJSONName(v reflect.Value, name string) (reflect.Value, bool) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if tag == name && name != "" && name != "-" && t.Field(i).IsExported() {
+			return v.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+var digitsRegexp = regexp.MustCompile(`\d+`)
+
+func setField(f reflect.Value, name, value string) {
+	switch f.Interface().(type) {
+	case string:
+		f.SetString(value)
+	case pq.StringArray:
+		if value != "" {
+			f.Set(reflect.Append(f, reflect.ValueOf(value)))
+		}
+	case int:
+		if name == "runtime" {
+			f.SetInt(int64(parser.ParseRuntime(value)))
+		} else {
+			f.SetInt(int64(parser.ParseInt(digitsRegexp.FindString(value))))
+		}
+	case float64:
+		f.SetFloat(parser.ParseScore(value))
+	case datatypes.Date:
+		f.Set(reflect.ValueOf(parser.ParseDate(value)))
+	}
+}
@@ -0,0 +1,51 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+	"github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+func TestScraper_PatchCollector(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><h1>Title</h1><div class="memo">Summary</div>
+<span id="date">2024-01-02</span><span id="runtime">120分</span>
+<ul><li>A</li><li>B</li></ul><img src="cover.jpg"></body></html>`)
+	}))
+	defer srv.Close()
+
+	s := NewDefaultScraper("TEST", srv.URL, 0)
+	s.SetSelectorPatches(
+		&provider.SelectorPatch{Field: "summary", XPath: `//div[@class="memo"]`},
+		&provider.SelectorPatch{Field: "title", XPath: `//h1`, Supplement: true},
+		&provider.SelectorPatch{Field: "genres", XPath: `//ul/li`},
+		&provider.SelectorPatch{Field: "release_date", XPath: `//span[@id="date"]`},
+		&provider.SelectorPatch{Field: "runtime", XPath: `//span[@id="runtime"]`},
+		&provider.SelectorPatch{Field: "cover_url", XPath: `//img`, Attr: "src"},
+		&provider.SelectorPatch{Field: "unknown", XPath: `//h1`},
+	)
+
+	info := &model.MovieInfo{}
+	c := s.ClonedCollector()
+	c.OnXML(`//h1`, func(e *colly.XMLElement) { info.Title = "Built-in" })
+	c.OnXML(`//p[@class="memo"]` /* outdated */, func(e *colly.XMLElement) { info.Summary = e.Text })
+	c.OnXML(`//ul/li`, func(e *colly.XMLElement) { info.Genres = append(info.Genres, "Built-in") })
+	s.PatchCollector(c, info)
+	assert.NoError(t, c.Visit(srv.URL))
+
+	assert.Equal(t, "Built-in", info.Title)
+	assert.Equal(t, "Summary", info.Summary)
+	assert.Equal(t, []string{"A", "B"}, []string(info.Genres))
+	assert.Equal(t, "2024-01-02", time.Time(info.ReleaseDate).Format(time.DateOnly))
+	assert.Equal(t, 120, info.Runtime)
+	assert.Equal(t, "cover.jpg", info.CoverURL)
+}
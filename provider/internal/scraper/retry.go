@@ -0,0 +1,157 @@
+package scraper
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// max number of retries of throttled requests.
+	maxThrottleRetries = 3
+	// max wait of a throttled request, longer waits fail fast.
+	maxThrottleWait = time.Minute
+	// default wait if the server doesn't tell.
+	defaultThrottleWait = 5 * time.Second
+)
+
+// ThrottleDetector detects provider-specific throttle pages, it returns
+// the time to wait and whether the response is throttled.
+type ThrottleDetector func(resp *http.Response, body []byte) (time.Duration, bool)
+
+// ThrottleMarkers detects throttle pages containing any of the markers,
+// which are matched case-insensitively, and waits for the given time.
+func ThrottleMarkers(wait time.Duration, markers ...string) ThrottleDetector {
+	return func(_ *http.Response, body []byte) (time.Duration, bool) {
+		body = bytes.ToLower(body)
+		for _, marker := range markers {
+			if bytes.Contains(body, bytes.ToLower([]byte(marker))) {
+				return wait, true
+			}
+		}
+		return 0, false
+	}
+}
+
+// throttleState pauses all requests of a scraper until the time set.
+type throttleState struct {
+	mu        sync.Mutex
+	until     time.Time
+	detectors []ThrottleDetector
+	handler   func(wait time.Duration)
+	// renew renews proxy circuits, nil if not renewable.
+	renew func()
+}
+
+func (s *throttleState) pause(wait time.Duration) {
+	s.mu.Lock()
+	if until := time.Now().Add(wait); until.After(s.until) {
+		s.until = until
+	}
+	handler, renew := s.handler, s.renew
+	s.mu.Unlock()
+	if renew != nil {
+		renew()
+	}
+	if handler != nil {
+		handler(wait)
+	}
+}
+
+func (s *throttleState) remaining() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return time.Until(s.until)
+}
+
+// retryAfterTransport complies with `Retry-After` of 429/503 responses
+// and throttle pages, it pauses the scraper and retries instead of failing.
+type retryAfterTransport struct {
+	base  http.RoundTripper
+	state *throttleState
+}
+
+func (t *retryAfterTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if wait := t.state.remaining(); wait > 0 {
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-req.Context().Done():
+				timer.Stop()
+				return nil, req.Context().Err()
+			}
+		}
+		resp, err := t.base.RoundTrip(req)
+		if err != nil {
+			return nil, err
+		}
+		wait, throttled, err := t.throttled(resp)
+		if err != nil {
+			return nil, err
+		}
+		if !throttled || wait > maxThrottleWait || attempt >= maxThrottleRetries ||
+			(req.Body != nil && req.GetBody == nil) /* not replayable */ {
+			return resp, nil
+		}
+		if deadline, ok := req.Context().Deadline(); ok && time.Now().Add(wait).After(deadline) {
+			return resp, nil // no time to wait.
+		}
+		_, _ = io.Copy(io.Discard, resp.Body)
+		resp.Body.Close()
+		if req.GetBody != nil {
+			if req.Body, err = req.GetBody(); err != nil {
+				return nil, err
+			}
+		}
+		t.state.pause(wait)
+	}
+}
+
+func (t *retryAfterTransport) throttled(resp *http.Response) (time.Duration, bool, error) {
+	if resp.StatusCode == http.StatusTooManyRequests ||
+		(resp.StatusCode == http.StatusServiceUnavailable && resp.Header.Get("Retry-After") != "") {
+		wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		if !ok {
+			wait = defaultThrottleWait
+		}
+		return wait, true, nil
+	}
+	t.state.mu.Lock()
+	detectors := t.state.detectors
+	t.state.mu.Unlock()
+	if len(detectors) == 0 {
+		return 0, false, nil
+	}
+	body, err := readBody(resp.Body, resp.ContentLength)
+	resp.Body.Close()
+	if err != nil {
+		return 0, false, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	for _, detect := range detectors {
+		if wait, ok := detect(resp, body); ok {
+			return wait, true, nil
+		}
+	}
+	return 0, false, nil
+}
+
+// parseRetryAfter parses the value of `Retry-After` header, which is
+// either delay seconds or an HTTP date.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v = strings.TrimSpace(v); v == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(v); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second, true
+	}
+	if date, err := http.ParseTime(v); err == nil {
+		return max(date.Sub(now), 0), true
+	}
+	return 0, false
+}
@@ -0,0 +1,81 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, unit := range []struct {
+		value string
+		wait  time.Duration
+		ok    bool
+	}{
+		{"", 0, false},
+		{"3", 3 * time.Second, true},
+		{"-1", 0, true},
+		{"Mon, 01 Jan 2024 00:00:10 GMT", 10 * time.Second, true},
+		{"Sun, 31 Dec 2023 00:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	} {
+		wait, ok := parseRetryAfter(unit.value, now)
+		assert.Equal(t, unit.ok, ok, unit.value)
+		assert.Equal(t, unit.wait, wait, unit.value)
+	}
+}
+
+func TestRetryAfterTransport(t *testing.T) {
+	var hits atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch hits.Add(1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.Write([]byte("slow down"))
+		default:
+			w.Write([]byte("ok"))
+		}
+	}))
+	defer srv.Close()
+
+	var waits atomic.Int32
+	s := NewDefaultScraper("test", srv.URL, 0,
+		WithThrottleDetector(func(_ *http.Response, body []byte) (time.Duration, bool) {
+			return 10 * time.Millisecond, string(body) == "slow down"
+		}))
+	s.SetThrottleHandler(func(time.Duration) { waits.Add(1) })
+
+	var body string
+	c := s.ClonedCollector()
+	c.OnResponse(func(r *colly.Response) { body = string(r.Body) })
+	assert.NoError(t, c.Visit(srv.URL))
+	assert.Equal(t, "ok", body)
+	assert.Equal(t, int32(3), hits.Load())
+	assert.Equal(t, int32(2), waits.Load())
+}
+
+func TestThrottleMarkers(t *testing.T) {
+	detect := ThrottleMarkers(time.Second, "アクセスが集中", "Too Many Requests")
+	for _, unit := range []struct {
+		body string
+		want bool
+	}{
+		{"<p>ただいまアクセスが集中しております</p>", true},
+		{"too many requests", true},
+		{"<title>Movie</title>", false},
+	} {
+		wait, ok := detect(nil, []byte(unit.body))
+		assert.Equal(t, unit.want, ok, unit.body)
+		if ok {
+			assert.Equal(t, time.Second, wait)
+		}
+	}
+}
@@ -1,15 +1,35 @@
 package scraper
 
 import (
+	"context"
+	"net/http"
 	"net/url"
 	"time"
 
 	"github.com/gocolly/colly/v2"
+	"golang.org/x/net/proxy"
 
+	"github.com/metatube-community/metatube-sdk-go/common/compress"
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
 	"github.com/metatube-community/metatube-sdk-go/provider"
 )
 
-var _ provider.Provider = (*Scraper)(nil)
+var (
+	_ provider.Provider                = (*Scraper)(nil)
+	_ provider.Snapshotter             = (*Scraper)(nil)
+	_ provider.TransportWrapper        = (*Scraper)(nil)
+	_ provider.ThrottleNotifier        = (*Scraper)(nil)
+	_ provider.CredentialSetter        = (*Scraper)(nil)
+	_ provider.SelectorPatcher         = (*Scraper)(nil)
+	_ provider.HostOverrider           = (*Scraper)(nil)
+	_ provider.DialerSetter            = (*Scraper)(nil)
+	_ provider.FetchClientSetter       = (*Scraper)(nil)
+	_ provider.DisplayNamer            = (*Scraper)(nil)
+	_ provider.SubRequestTimeoutSetter = (*Scraper)(nil)
+	_ provider.ArchiveFallback         = (*Scraper)(nil)
+	_ provider.SoftNotFoundSetter      = (*Scraper)(nil)
+	_ provider.ContextSetter           = (*Scraper)(nil)
+)
 
 // Scraper implements basic Provider interface.
 type Scraper struct {
@@ -17,6 +37,42 @@ type Scraper struct {
 	priority int
 	baseURL  *url.URL
 	c        *colly.Collector
+	// underlying HTTP transport.
+	transport http.RoundTripper
+	// wrappers of the underlying transport.
+	wrappers []func(http.RoundTripper) http.RoundTripper
+	// snapshot recorder, nil if disabled.
+	recorder *snapshotRecorder
+	// throttle state of the scraper.
+	throttle *throttleState
+	// guard of response sizes and content types.
+	guard *responseGuard
+	// age gate state, nil if not gated.
+	gate *ageGateState
+	// login state, nil if no member-only contents.
+	login *loginState
+	// locale strategy, nil if not multilingual.
+	locales *LocaleStrategy
+	// selector patches of info fields.
+	patches []*provider.SelectorPatch
+	// expensive fields skipped by getting movie infos.
+	lazyFields []provider.Field
+	// host overrides of hostnames.
+	hosts []*provider.HostOverride
+	// dialer of connections, nil for the default.
+	dialer proxy.ContextDialer
+	// transport dialed by the dialer or pinned, nil if not cloned.
+	conns *http.Transport
+	// display name of the provider, the name if empty.
+	displayName string
+	// timeout of nested sub-requests, disabled if zero.
+	subTimeout time.Duration
+	// context of requests of cloned collectors, nil for the default.
+	ctx context.Context
+	// archive fallback state, nil if disabled.
+	archive *archiveState
+	// lower-case markers of pages of missing contents.
+	softMarkers [][]byte
 }
 
 // NewScraper returns Provider implemented *Scraper.
@@ -26,11 +82,17 @@ func NewScraper(name, baseURL string, priority int, opts ...Option) *Scraper {
 		panic(err)
 	}
 	s := &Scraper{
-		name:     name,
-		priority: priority,
-		baseURL:  u,
-		c:        colly.NewCollector(),
+		name:      name,
+		priority:  priority,
+		baseURL:   u,
+		c:         colly.NewCollector(),
+		transport: http.DefaultTransport,
+		throttle:  &throttleState{},
+		guard:     &responseGuard{maxSize: DefaultMaxResponseSize},
 	}
+	// sizes are limited by the guard instead of being truncated.
+	s.c.MaxBodySize = 0
+	s.applyTransport()
 	for _, opt := range opts {
 		// Apply options.
 		if err := opt(s); err != nil {
@@ -45,12 +107,19 @@ func NewDefaultScraper(name, baseURL string, priority int, opts ...Option) *Scra
 	return NewScraper(name, baseURL, priority, append([]Option{
 		WithAllowURLRevisit(),
 		WithIgnoreRobotsTxt(),
-		WithRandomUserAgent(),
+		WithRandomBrowserProfile(),
 	}, opts...)...)
 }
 
 func (s *Scraper) Name() string { return s.name }
 
+func (s *Scraper) DisplayName() string {
+	if s.displayName != "" {
+		return s.displayName
+	}
+	return s.name
+}
+
 func (s *Scraper) URL() *url.URL { return s.baseURL }
 
 func (s *Scraper) Priority() int { return s.priority }
@@ -63,8 +132,111 @@ func (s *Scraper) NormalizeActorID(id string) string { return id /* AS IS */ }
 
 func (s *Scraper) ParseActorIDFromURL(string) (string, error) { panic("unimplemented") }
 
-// ClonedCollector returns cloned internal collector.
-func (s *Scraper) ClonedCollector() *colly.Collector { return s.c.Clone() }
+// ClonedCollector returns cloned internal collector. Each scrape should
+// use its own cloned collector to register callbacks, which makes the
+// Scraper safe for concurrent use. Cloning is cheap as the HTTP backend,
+// storage and limit rules are shared with the internal collector, but
+// cloned collectors can't be reused since callbacks can't be cleared.
+// The age gate and login, if any, are negotiated before the collector is
+// returned.
+func (s *Scraper) ClonedCollector() *colly.Collector {
+	c := s.c.Clone()
+	if s.ctx != nil {
+		c.Context = s.ctx
+	}
+	if s.hasCredentials() {
+		_ = s.login.pass(s.c.Clone()) // ignore error, fallback to public contents.
+		if s.login.login.Detect != nil {
+			c.OnResponse(func(r *colly.Response) {
+				if s.login.login.Detect(r) {
+					s.login.reset()
+				}
+			})
+		}
+	}
+	if s.gate != nil {
+		_ = s.gate.pass(s.c.Clone()) // ignore error, the scrape fails anyway.
+		if s.gate.gate.Detect != nil {
+			c.OnResponse(func(r *colly.Response) {
+				if s.gate.gate.Detect(r) {
+					s.gate.reset()
+				}
+			})
+		}
+	}
+	return c
+}
 
-// SetRequestTimeout sets timeout for HTTP requests.
+// SetRequestTimeout sets timeout for HTTP requests. It is shared by all
+// cloned collectors, so it must be called before the Scraper is used.
 func (s *Scraper) SetRequestTimeout(timeout time.Duration) { s.c.SetRequestTimeout(timeout) }
+
+// SetContext sets the context of requests of collectors cloned later, so
+// that they're canceled along with it, e.g., by search deadlines. It must
+// only be called on scrapers used exclusively, nil to unset.
+func (s *Scraper) SetContext(ctx context.Context) { s.ctx = ctx }
+
+// EnableSnapshot starts recording HTTP responses and matched selectors.
+func (s *Scraper) EnableSnapshot() {
+	if s.recorder != nil {
+		return // already enabled.
+	}
+	s.recorder = newSnapshotRecorder()
+	s.c.SetDebugger(s.recorder)
+	s.WrapTransport(func(base http.RoundTripper) http.RoundTripper {
+		return &snapshotTransport{
+			base:     base,
+			recorder: s.recorder,
+		}
+	})
+}
+
+// Snapshot returns what has been recorded since the snapshot enabled.
+func (s *Scraper) Snapshot() *provider.Snapshot {
+	if s.recorder == nil {
+		return &provider.Snapshot{}
+	}
+	return s.recorder.snapshot()
+}
+
+// SetFetchClient replaces the underlying HTTP transport by the client,
+// the guards, throttles and wrappers still apply to its responses. Host
+// overrides and dialers don't apply to clients other than net/http ones.
+// It must be called before the Scraper is used.
+func (s *Scraper) SetFetchClient(client fetch.Client) {
+	s.transport = &fetch.Transport{Client: client}
+	s.applyTransport()
+}
+
+// SetThrottleHandler sets the handler called when the scraper is paused
+// by the server throttling, with the time to wait.
+func (s *Scraper) SetThrottleHandler(handler func(wait time.Duration)) {
+	s.throttle.mu.Lock()
+	defer s.throttle.mu.Unlock()
+	s.throttle.handler = handler
+}
+
+// WrapTransport wraps the underlying HTTP transport, the latest wrapper
+// becomes the outermost one. It must be called before the Scraper is used.
+func (s *Scraper) WrapTransport(wrapper func(http.RoundTripper) http.RoundTripper) {
+	s.wrappers = append(s.wrappers, wrapper)
+	s.applyTransport()
+}
+
+func (s *Scraper) applyTransport() {
+	// domain limits apply to actual requests only, and responses of
+	// all encodings, e.g., br and zstd, are decoded and guarded.
+	var transport http.RoundTripper = &retryAfterTransport{
+		base: &throttleTransport{base: &guardTransport{
+			base:  &compress.Transport{Base: s.dialTransport()},
+			guard: s.guard,
+		}},
+		state: s.throttle,
+	}
+	transport = &softNotFoundTransport{base: transport, s: s}
+	transport = &archiveTransport{base: transport, s: s}
+	for _, wrap := range s.wrappers {
+		transport = wrap(transport)
+	}
+	s.c.WithTransport(transport)
+}
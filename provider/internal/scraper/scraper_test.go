@@ -0,0 +1,186 @@
+package scraper
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/random"
+	"github.com/metatube-community/metatube-sdk-go/internal/race"
+)
+
+func TestScraper_Concurrency(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprintf(w, `<html><body><h1>%s</h1></body></html>`, r.URL.Query().Get("id"))
+	}))
+	defer srv.Close()
+
+	s := NewDefaultScraper("TEST", srv.URL, 0)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 32; i++ {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			var title string
+			c := s.ClonedCollector()
+			c.OnXML(`//h1`, func(e *colly.XMLElement) { title = e.Text })
+			assert.NoError(t, c.Visit(fmt.Sprintf("%s/?id=%s", srv.URL, id)))
+			assert.Equal(t, id, title)
+		}(fmt.Sprint(i))
+	}
+	wg.Wait()
+}
+
+func BenchmarkScraper_ClonedCollector(b *testing.B) {
+	s := NewDefaultScraper("TEST", "https://example.com/", 0)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = s.ClonedCollector()
+	}
+}
+
+func BenchmarkScraper_Visit(b *testing.B) {
+	page := fmt.Sprintf(`<html><body>%s</body></html>`, strings.Repeat(`<p>content</p>`, 1024))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, page)
+	}))
+	defer srv.Close()
+
+	s := NewDefaultScraper("TEST", srv.URL, 0)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		c := s.ClonedCollector()
+		c.OnXML(`//p[1]`, func(e *colly.XMLElement) {})
+		if err := c.Visit(srv.URL); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// Allocation budgets of hot paths, raise them only with a reason. Visits
+// are dominated by parsing, ~4 allocs per element of the page.
+const (
+	clonedCollectorAllocBudget = 12   // ~8 by cloning the collector.
+	visitAllocBudget           = 5500 // ~4300 by a page of 1024 elements.
+	readBodyAllocBudget        = 2    // ~2 by the buffer sized by Content-Length.
+)
+
+func TestScraper_BrowserProfile(t *testing.T) {
+	var headers []http.Header
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = append(headers, r.Header.Clone())
+	}))
+	defer srv.Close()
+
+	profile := &random.Profile{
+		UserAgent:   "Test/1.0",
+		ClientHints: map[string]string{"Sec-CH-UA-Mobile": "?0"},
+		Headers:     map[string]string{"Accept": "text/html", "Sec-Fetch-Mode": "navigate"},
+	}
+	s := NewScraper("TEST", srv.URL, 0,
+		WithAllowURLRevisit(),
+		WithBrowserProfile(profile),
+		WithHeaders(map[string]string{"Accept": "application/json"}))
+	for i := 0; i < 2; i++ {
+		assert.NoError(t, s.ClonedCollector().Visit(srv.URL))
+	}
+	// the profile is kept for the session, and merged with headers.
+	if assert.Len(t, headers, 2) {
+		for _, header := range headers {
+			assert.Equal(t, "Test/1.0", header.Get("User-Agent"))
+			assert.Equal(t, "?0", header.Get("Sec-CH-UA-Mobile"))
+			assert.Equal(t, "navigate", header.Get("Sec-Fetch-Mode"))
+			assert.Equal(t, "application/json", header.Get("Accept"))
+		}
+	}
+}
+
+func TestAllocBudgets(t *testing.T) {
+	if race.Enabled {
+		t.Skip("allocations are inflated by the race detector")
+	}
+	page := fmt.Sprintf(`<html><body>%s</body></html>`, strings.Repeat(`<p>content</p>`, 1024))
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, page)
+	}))
+	defer srv.Close()
+
+	s := NewDefaultScraper("TEST", srv.URL, 0)
+	data := bytes.Repeat([]byte("x"), 1<<18)
+	for _, unit := range []struct {
+		name   string
+		budget int
+		run    func()
+	}{
+		{"ClonedCollector", clonedCollectorAllocBudget, func() { _ = s.ClonedCollector() }},
+		{"Visit", visitAllocBudget, func() {
+			c := s.ClonedCollector()
+			c.OnXML(`//p[1]`, func(e *colly.XMLElement) {})
+			_ = c.Visit(srv.URL)
+		}},
+		{"readBody", readBodyAllocBudget, func() { _, _ = readBody(bytes.NewReader(data), int64(len(data))) }},
+	} {
+		if n := testing.AllocsPerRun(20, unit.run); n > float64(unit.budget) {
+			t.Errorf("%s allocs %.0f > budget %d", unit.name, n, unit.budget)
+		}
+	}
+}
+
+func TestScraper_SetFetchClient(t *testing.T) {
+	const pageURL = "https://example.com/movie/1"
+	client := fetch.NewFixtureClient(&fetch.Fixture{
+		URL:    pageURL,
+		Header: http.Header{"Content-Type": {"text/html"}},
+		Body:   []byte(`<html><body><h1>Fixture</h1></body></html>`),
+	})
+
+	s := NewDefaultScraper("TEST", "https://example.com/", 0, WithClient(client))
+
+	var title string
+	c := s.ClonedCollector()
+	c.OnXML(`//h1`, func(e *colly.XMLElement) { title = e.Text })
+	if assert.NoError(t, c.Visit(pageURL)) {
+		assert.Equal(t, "Fixture", title)
+	}
+	// requests not recorded fail instead of reaching the network.
+	assert.Error(t, s.ClonedCollector().Visit("https://example.com/movie/2"))
+}
+
+func TestScraper_SetContext(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-done:
+		}
+	}))
+	defer srv.Close()
+	defer close(done)
+
+	s := NewDefaultScraper("TEST", srv.URL, 0, WithAllowURLRevisit())
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	s.SetContext(ctx)
+	start := time.Now()
+	assert.ErrorIs(t, s.ClonedCollector().Visit(srv.URL), context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	// unset.
+	s.SetContext(nil)
+	assert.Equal(t, context.Background(), s.ClonedCollector().Context)
+}
@@ -0,0 +1,143 @@
+package scraper
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/antchfx/htmlquery"
+	"github.com/gocolly/colly/v2/debug"
+
+	"github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+var (
+	_ debug.Debugger    = (*snapshotRecorder)(nil)
+	_ http.RoundTripper = (*snapshotTransport)(nil)
+)
+
+type snapshotSelector struct {
+	url      string
+	selector string
+}
+
+type snapshotRecorder struct {
+	mu        sync.Mutex
+	responses []*provider.SnapshotResponse
+	selectors []snapshotSelector
+	visited   map[snapshotSelector]struct{}
+}
+
+func newSnapshotRecorder() *snapshotRecorder {
+	return &snapshotRecorder{visited: make(map[snapshotSelector]struct{})}
+}
+
+func (r *snapshotRecorder) Init() error { return nil }
+
+func (r *snapshotRecorder) Event(e *debug.Event) {
+	if e.Type != "xml" {
+		return
+	}
+	sel := snapshotSelector{
+		url:      e.Values["url"],
+		selector: e.Values["selector"],
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.visited[sel]; ok {
+		return // one record per selector.
+	}
+	r.visited[sel] = struct{}{}
+	r.selectors = append(r.selectors, sel)
+}
+
+func (r *snapshotRecorder) addResponse(resp *provider.SnapshotResponse) {
+	r.mu.Lock()
+	r.responses = append(r.responses, resp)
+	r.mu.Unlock()
+}
+
+func (r *snapshotRecorder) snapshot() *provider.Snapshot {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	snapshot := &provider.Snapshot{
+		Responses: append([]*provider.SnapshotResponse(nil), r.responses...),
+		Selectors: make([]*provider.SnapshotSelector, 0, len(r.selectors)),
+	}
+	for _, sel := range r.selectors {
+		snapshot.Selectors = append(snapshot.Selectors, &provider.SnapshotSelector{
+			URL:      sel.url,
+			Selector: sel.selector,
+			Values:   r.evaluate(sel),
+		})
+	}
+	return snapshot
+}
+
+// evaluate re-evaluates the selector against the last response of the URL.
+func (r *snapshotRecorder) evaluate(sel snapshotSelector) (values []string) {
+	for i := len(r.responses) - 1; i >= 0; i-- {
+		if r.responses[i].URL != sel.url {
+			continue
+		}
+		doc, err := htmlquery.Parse(strings.NewReader(r.responses[i].Body))
+		if err != nil {
+			return
+		}
+		nodes, err := htmlquery.QueryAll(doc, sel.selector)
+		if err != nil {
+			return
+		}
+		for _, n := range nodes {
+			values = append(values, strings.TrimSpace(htmlquery.InnerText(n)))
+		}
+		return
+	}
+	return
+}
+
+type snapshotTransport struct {
+	base     http.RoundTripper
+	recorder *snapshotRecorder
+}
+
+func (t *snapshotTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	record := &provider.SnapshotResponse{
+		URL:           req.URL.String(),
+		Method:        req.Method,
+		RequestHeader: req.Header.Clone(),
+		StartedAt:     time.Now(),
+	}
+	if req.GetBody != nil {
+		if body, err := req.GetBody(); err == nil {
+			data, _ := io.ReadAll(body)
+			body.Close()
+			record.RequestBody = string(data)
+		}
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		record.Error, record.Elapsed = err.Error(), time.Since(record.StartedAt)
+		t.recorder.addResponse(record)
+		return nil, err
+	}
+	body, err := readBody(resp.Body, resp.ContentLength)
+	resp.Body.Close()
+	record.Elapsed = time.Since(record.StartedAt)
+	if err != nil {
+		record.Error = err.Error()
+		t.recorder.addResponse(record)
+		return nil, err
+	}
+	// restore the consumed body.
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	record.StatusCode = resp.StatusCode
+	record.Header = resp.Header.Clone()
+	record.ContentType = resp.Header.Get("Content-Type")
+	record.Body = string(body)
+	t.recorder.addResponse(record)
+	return resp, nil
+}
@@ -0,0 +1,42 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScraper_Snapshot(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><h1>Title</h1><ul><li>A</li><li>B</li></ul></body></html>`)
+	}))
+	defer srv.Close()
+
+	s := NewDefaultScraper("TEST", srv.URL, 0)
+	s.EnableSnapshot()
+
+	var title string
+	c := s.ClonedCollector()
+	c.OnXML(`//h1`, func(e *colly.XMLElement) { title = e.Text })
+	c.OnXML(`//ul/li`, func(e *colly.XMLElement) {})
+	assert.NoError(t, c.Visit(srv.URL))
+	assert.Equal(t, "Title", title)
+
+	snapshot := s.Snapshot()
+	if assert.Len(t, snapshot.Responses, 1) {
+		assert.Equal(t, http.StatusOK, snapshot.Responses[0].StatusCode)
+		assert.Contains(t, snapshot.Responses[0].Body, "<h1>Title</h1>")
+		assert.Equal(t, "text/html", snapshot.Responses[0].Header.Get("Content-Type"))
+		assert.NotEmpty(t, snapshot.Responses[0].RequestHeader.Get("User-Agent"))
+		assert.False(t, snapshot.Responses[0].StartedAt.IsZero())
+	}
+	if assert.Len(t, snapshot.Selectors, 2) {
+		assert.Equal(t, []string{"Title"}, snapshot.Selectors[0].Values)
+		assert.Equal(t, []string{"A", "B"}, snapshot.Selectors[1].Values)
+	}
+}
@@ -0,0 +1,75 @@
+package scraper
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+// WithSoftNotFound detects pages of missing contents served with 200 by
+// the markers in them, which are matched case-insensitively.
+func WithSoftNotFound(markers ...string) Option {
+	return func(s *Scraper) error {
+		s.addSoftNotFoundMarkers(markers...)
+		return nil
+	}
+}
+
+// SetSoftNotFound adds the markers of pages of missing contents to the
+// built-in ones. Image hashes are checked by the engine. It must be called
+// before the Scraper is used.
+func (s *Scraper) SetSoftNotFound(d *provider.SoftNotFound) {
+	if d != nil {
+		s.addSoftNotFoundMarkers(d.Markers...)
+	}
+}
+
+func (s *Scraper) addSoftNotFoundMarkers(markers ...string) {
+	for _, marker := range markers {
+		if marker = strings.TrimSpace(marker); marker != "" {
+			s.softMarkers = append(s.softMarkers, bytes.ToLower([]byte(marker)))
+		}
+	}
+}
+
+// softNotFoundTransport fails pages of missing contents with
+// provider.ErrInfoNotFound, which are fallen back to archives as well.
+type softNotFoundTransport struct {
+	base http.RoundTripper
+	s    *Scraper
+}
+
+func (t *softNotFoundTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.base.RoundTrip(req)
+	markers := t.s.softMarkers
+	if err != nil || len(markers) == 0 || req.Method != http.MethodGet ||
+		resp.StatusCode != http.StatusOK || !isTextContent(resp.Header.Get("Content-Type")) {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	lower := bytes.ToLower(body)
+	for _, marker := range markers {
+		if bytes.Contains(lower, marker) {
+			return nil, provider.ErrInfoNotFound
+		}
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	return resp, nil
+}
+
+// isTextContent reports whether the content type is of pages, responses
+// without content types are treated as pages.
+func isTextContent(contentType string) bool {
+	return contentType == "" ||
+		strings.HasPrefix(contentType, "text/") ||
+		strings.Contains(contentType, "html") ||
+		strings.Contains(contentType, "json") ||
+		strings.Contains(contentType, "xml")
+}
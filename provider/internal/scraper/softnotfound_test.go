@@ -0,0 +1,48 @@
+package scraper
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+func TestScraper_SoftNotFound(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		if r.URL.Path == "/removed" {
+			fmt.Fprint(w, `<html><body><p>Sorry, CONTENT NOT FOUND.</p></body></html>`)
+			return
+		}
+		fmt.Fprint(w, `<html><body><h1>Movie</h1></body></html>`)
+	}))
+	defer srv.Close()
+
+	scrape := func(s *Scraper, path string) (title string, err error) {
+		c := s.ClonedCollector()
+		c.OnXML(`//h1`, func(e *colly.XMLElement) { title = e.Text })
+		err = c.Visit(srv.URL + path)
+		return
+	}
+
+	s := NewDefaultScraper("TEST", srv.URL, 0, WithSoftNotFound("content not found"))
+	_, err := scrape(s, "/removed")
+	assert.True(t, errors.Is(err, provider.ErrInfoNotFound), err)
+	title, err := scrape(s, "/movie")
+	assert.NoError(t, err)
+	assert.Equal(t, "Movie", title)
+
+	// configured markers are added.
+	s = NewDefaultScraper("TEST", srv.URL, 0)
+	_, err = scrape(s, "/removed")
+	assert.NoError(t, err)
+	s.SetSoftNotFound(&provider.SoftNotFound{Markers: []string{"Content Not Found"}})
+	_, err = scrape(s, "/removed")
+	assert.True(t, errors.Is(err, provider.ErrInfoNotFound), err)
+}
@@ -0,0 +1,31 @@
+package scraper
+
+import (
+	"context"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+)
+
+// SetSubRequestTimeout sets the timeout of nested sub-requests, e.g., of
+// HLS playlists or sample video iframes, so that a slow CDN can't consume
+// the whole budget of the scrape. It's disabled if zero. It must be called
+// before the Scraper is used.
+func (s *Scraper) SetSubRequestTimeout(timeout time.Duration) { s.subTimeout = timeout }
+
+// SubCollector returns a clone of the collector for nested sub-requests,
+// which are canceled after the sub-request timeout if set. The cancel
+// func must be called after visits are done.
+func (s *Scraper) SubCollector(c *colly.Collector) (*colly.Collector, context.CancelFunc) {
+	d := c.Clone()
+	if s.subTimeout <= 0 {
+		return d, func() {}
+	}
+	parent := c.Context
+	if parent == nil {
+		parent = context.Background()
+	}
+	ctx, cancel := context.WithTimeout(parent, s.subTimeout)
+	d.Context = ctx
+	return d, cancel
+}
@@ -0,0 +1,47 @@
+package scraper
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gocolly/colly/v2"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScraper_SubCollector(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-time.After(5 * time.Second):
+			case <-r.Context().Done():
+			}
+			return
+		}
+		w.Header().Set("Content-Type", "text/html")
+		fmt.Fprint(w, `<html><body><a href="/slow">sample</a></body></html>`)
+	}))
+	defer srv.Close()
+
+	s := NewDefaultScraper("TEST", srv.URL, 0, WithSubRequestTimeout(100*time.Millisecond))
+
+	var subErr error
+	c := s.ClonedCollector()
+	c.OnXML(`//a`, func(e *colly.XMLElement) {
+		d, cancel := s.SubCollector(c)
+		defer cancel()
+		subErr = d.Visit(e.Request.AbsoluteURL(e.Attr("href")))
+	})
+	start := time.Now()
+	assert.NoError(t, c.Visit(srv.URL), "the scrape survives slow sub-requests")
+	assert.Error(t, subErr)
+	assert.Less(t, time.Since(start), 2*time.Second)
+
+	// disabled by default.
+	s = NewDefaultScraper("TEST", srv.URL, 0)
+	d, cancel := s.SubCollector(s.ClonedCollector())
+	defer cancel()
+	assert.NoError(t, d.Visit(srv.URL))
+}
@@ -0,0 +1,148 @@
+package scraper
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DomainLimit limits requests to a group of domains, which is shared
+// by all scrapers, so that providers on the same infrastructure don't
+// collectively trip bans.
+type DomainLimit struct {
+	// Parallelism is the max number of in-flight requests, 0 means unlimited.
+	Parallelism int
+	// Delay is the min interval between requests.
+	Delay time.Duration
+}
+
+var (
+	domainLimitersMu sync.RWMutex
+	domainLimiters   = make(map[string]*domainLimiter)
+)
+
+// RegisterDomainGroup registers domains into a group sharing the same
+// limit, subdomains of the domains are also included.
+func RegisterDomainGroup(limit DomainLimit, domains ...string) {
+	l := newDomainLimiter(limit)
+	domainLimitersMu.Lock()
+	defer domainLimitersMu.Unlock()
+	for _, domain := range domains {
+		domainLimiters[strings.ToLower(domain)] = l
+	}
+}
+
+func lookupDomainLimiter(host string) *domainLimiter {
+	domainLimitersMu.RLock()
+	defer domainLimitersMu.RUnlock()
+	for host = strings.ToLower(host); host != ""; {
+		if l, ok := domainLimiters[host]; ok {
+			return l
+		}
+		_, parent, found := strings.Cut(host, ".")
+		if !found {
+			break
+		}
+		host = parent
+	}
+	return nil
+}
+
+type domainLimiter struct {
+	sem   chan struct{}
+	delay time.Duration
+	mu    sync.Mutex
+	next  time.Time
+}
+
+func newDomainLimiter(limit DomainLimit) *domainLimiter {
+	l := &domainLimiter{delay: limit.Delay}
+	if limit.Parallelism > 0 {
+		l.sem = make(chan struct{}, limit.Parallelism)
+	}
+	return l
+}
+
+func (l *domainLimiter) acquire(ctx context.Context) error {
+	if l.sem != nil {
+		select {
+		case l.sem <- struct{}{}:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if l.delay > 0 {
+		l.mu.Lock()
+		now := time.Now()
+		wait := l.next.Sub(now)
+		l.next = now.Add(max(wait, 0) + l.delay)
+		l.mu.Unlock()
+		if wait > 0 {
+			t := time.NewTimer(wait)
+			defer t.Stop()
+			select {
+			case <-t.C:
+			case <-ctx.Done():
+				l.release()
+				return ctx.Err()
+			}
+		}
+	}
+	return nil
+}
+
+func (l *domainLimiter) release() {
+	if l.sem != nil {
+		<-l.sem
+	}
+}
+
+// throttleTransport applies domain limits to requests, the slot of a
+// request is held until its response body is closed.
+type throttleTransport struct {
+	base http.RoundTripper
+}
+
+func (t *throttleTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	l := lookupDomainLimiter(req.URL.Hostname())
+	if l == nil {
+		return t.base.RoundTrip(req)
+	}
+	if err := l.acquire(req.Context()); err != nil {
+		return nil, err
+	}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		l.release()
+		return nil, err
+	}
+	resp.Body = &releaseOnClose{ReadCloser: resp.Body, release: l.release}
+	return resp, nil
+}
+
+type releaseOnClose struct {
+	io.ReadCloser
+	once    sync.Once
+	release func()
+}
+
+func (r *releaseOnClose) Close() error {
+	err := r.ReadCloser.Close()
+	r.once.Do(r.release)
+	return err
+}
+
+func init() {
+	// D2Pass family shares the same backend and CDN.
+	RegisterDomainGroup(DomainLimit{Parallelism: 4, Delay: 100 * time.Millisecond},
+		"1pondo.tv",
+		"10musume.com",
+		"caribbeancom.com",
+		"caribbeancompr.com",
+		"pacopacomama.com",
+		"muramura.tv",
+	)
+}
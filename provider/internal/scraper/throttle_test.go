@@ -0,0 +1,50 @@
+package scraper
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupDomainLimiter(t *testing.T) {
+	assert.NotNil(t, lookupDomainLimiter("www.1pondo.tv"))
+	assert.NotNil(t, lookupDomainLimiter("fms.1pondo.tv"))
+	assert.Same(t, lookupDomainLimiter("www.1pondo.tv"), lookupDomainLimiter("www.10musume.com"))
+	assert.Nil(t, lookupDomainLimiter("example.com"))
+}
+
+func TestThrottleTransport(t *testing.T) {
+	var cur, peak atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := cur.Add(1)
+		defer cur.Add(-1)
+		for {
+			if p := peak.Load(); n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	// httptest serves on 127.0.0.1, which is registered as a group here.
+	RegisterDomainGroup(DomainLimit{Parallelism: 2}, "127.0.0.1")
+	defer RegisterDomainGroup(DomainLimit{}, "127.0.0.1")
+
+	s := NewDefaultScraper("test", srv.URL, 0)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, s.ClonedCollector().Visit(srv.URL))
+		}()
+	}
+	wg.Wait()
+	assert.LessOrEqual(t, peak.Load(), int32(2))
+}
@@ -207,6 +207,8 @@ func (jav *JAV321) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err
 		}
 	})
 
+	jav.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
@@ -250,4 +252,5 @@ func (jav *JAV321) SearchMovie(keyword string) (results []*model.MovieSearchResu
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.jav321.com", Path: "/video/"})
 }
@@ -35,6 +35,10 @@ const (
 	searchUncensoredURL = "https://www.javbus.com/ja/uncensored/search/%s"
 )
 
+// searchMaxPages limits pages of each search listing, best matches are
+// on the first pages.
+const searchMaxPages = 2
+
 type JavBus struct {
 	*scraper.Scraper
 }
@@ -46,7 +50,8 @@ func New() *JavBus {
 			scraper.WithCookies(baseURL, []*http.Cookie{
 				// existmag=all
 				{Name: "existmag", Value: "all"},
-			})),
+			}),
+			scraper.WithContentTypes(scraper.HTMLContentTypes...)),
 	}
 }
 
@@ -136,7 +141,8 @@ func (bus *JavBus) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err
 				thumbs = re.ReplaceAllString(info.CoverURL, "/thumbs/${1}.${2}")
 			)
 			var mu sync.Mutex
-			d := c.Clone()
+			d, cancel := bus.SubCollector(c)
+			defer cancel()
 			d.Async = true
 			d.OnScraped(func(r *colly.Response) {
 				mu.Lock()
@@ -150,6 +156,8 @@ func (bus *JavBus) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err
 		}
 	})
 
+	bus.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
@@ -161,47 +169,41 @@ func (bus *JavBus) NormalizeMovieKeyword(keyword string) string {
 	return strings.ToUpper(keyword)
 }
 
-func (bus *JavBus) SearchMovie(keyword string) (results []*model.MovieSearchResult, err error) {
-	c := bus.ClonedCollector()
-	c.Async = true /* ASYNC */
-
-	var mu sync.Mutex
-	c.OnXML(`//a[@class="movie-box"]`, func(e *colly.XMLElement) {
-		mu.Lock()
-		defer mu.Unlock()
-
-		var thumb, cover string
-		thumb = e.Request.AbsoluteURL(e.ChildAttr(`.//div[1]/img`, "src"))
-		if re := regexp.MustCompile(`(?i)/thumbs?/([a-z\d]+)(?:_b)?\.(jpg|png)`); re.MatchString(thumb) {
-			cover = re.ReplaceAllString(thumb, "/cover/${1}_b.${2}") // guess
-		}
-
-		homepage := e.Request.AbsoluteURL(e.Attr("href"))
-		id, _ := bus.ParseMovieIDFromURL(homepage)
-		results = append(results, &model.MovieSearchResult{
-			ID:          id,
-			Number:      e.ChildText(`.//div[2]/span/date[1]`),
-			Title:       strings.SplitN(e.ChildText(`.//div[2]/span`), "\n", 2)[0],
-			Provider:    bus.Name(),
-			Homepage:    homepage,
-			ThumbURL:    thumb,
-			CoverURL:    cover,
-			ReleaseDate: parser.ParseDate(e.ChildText(`.//div[2]/span/date[2]`)),
-		})
-	})
-
-	for _, u := range []string{
-		fmt.Sprintf(searchURL, keyword),
-		fmt.Sprintf(searchUncensoredURL, keyword),
-	} {
-		if err = c.Visit(u); err != nil {
-			return nil, err
-		}
+func (bus *JavBus) SearchMovie(keyword string) ([]*model.MovieSearchResult, error) {
+	listing := &scraper.Listing[*model.MovieSearchResult]{
+		ItemSelector: `//a[@class="movie-box"]`,
+		NextSelector: `//a[@id="next"]`,
+		MaxPages:     searchMaxPages,
+		Parse: func(e *colly.XMLElement) (*model.MovieSearchResult, bool) {
+			var thumb, cover string
+			thumb = e.Request.AbsoluteURL(e.ChildAttr(`.//div[1]/img`, "src"))
+			if re := regexp.MustCompile(`(?i)/thumbs?/([a-z\d]+)(?:_b)?\.(jpg|png)`); re.MatchString(thumb) {
+				cover = re.ReplaceAllString(thumb, "/cover/${1}_b.${2}") // guess
+			}
+
+			homepage := e.Request.AbsoluteURL(e.Attr("href"))
+			id, _ := bus.ParseMovieIDFromURL(homepage)
+			return &model.MovieSearchResult{
+				ID:          id,
+				Number:      e.ChildText(`.//div[2]/span/date[1]`),
+				Title:       strings.SplitN(e.ChildText(`.//div[2]/span`), "\n", 2)[0],
+				Provider:    bus.Name(),
+				Homepage:    homepage,
+				ThumbURL:    thumb,
+				CoverURL:    cover,
+				ReleaseDate: parser.ParseDate(e.ChildText(`.//div[2]/span/date[2]`)),
+			}, true
+		},
+		Key: func(result *model.MovieSearchResult) string { return result.ID },
+		// search pages are parsed element by element.
+		Stream: true,
 	}
-	c.Wait()
-	return
+	return scraper.Crawl(bus.Scraper, listing,
+		fmt.Sprintf(searchURL, keyword),
+		fmt.Sprintf(searchUncensoredURL, keyword))
 }
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.javbus.com"})
 }
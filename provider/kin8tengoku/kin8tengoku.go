@@ -191,7 +191,8 @@ func (k8 *KIN8) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err err
 		siteId := e.Attr("data-d2p_site_id")
 		movieSeq := e.Attr("data-movie_seq")
 		jqTimestamp := strconv.Itoa(int(time.Now().UnixMilli()))
-		d := c.Clone()
+		d, cancel := k8.SubCollector(c)
+		defer cancel()
 		d.OnResponse(func(r *colly.Response) {
 			if ss := regexp.MustCompile(`(?s)\w+\((.+?)\);`).FindSubmatch(r.Body); len(ss) == 2 {
 				var data []struct {
@@ -215,10 +216,13 @@ func (k8 *KIN8) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err err
 		d.Visit(fmt.Sprintf(reviewURL, q.Encode()))
 	})
 
+	k8.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.kin8tengoku.com", Path: "/moviepages/"})
 }
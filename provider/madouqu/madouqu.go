@@ -38,7 +38,8 @@ type MadouQu struct {
 }
 
 func New() *MadouQu {
-	return &MadouQu{scraper.NewDefaultScraper(Name, baseURL, Priority)}
+	return &MadouQu{scraper.NewDefaultScraper(Name, baseURL, Priority,
+		scraper.WithContentTypes(scraper.HTMLContentTypes...))}
 }
 
 func (mdq *MadouQu) SetRequestTimeout(_ time.Duration) {
@@ -127,6 +128,8 @@ func (mdq *MadouQu) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err
 		}
 	})
 
+	mdq.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
@@ -189,4 +192,5 @@ func ExtractImgSrc(src string) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.madouqu.com"})
 }
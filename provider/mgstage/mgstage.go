@@ -46,9 +46,13 @@ type MGS struct {
 func New() *MGS {
 	return &MGS{
 		Scraper: scraper.NewDefaultScraper(Name, baseURL, Priority,
-			scraper.WithCookies(baseURL, []*http.Cookie{
-				{Name: "adc", Value: "1"},
-			})),
+			scraper.WithDisplayName("MGS動画"),
+			scraper.WithAgeGate(&scraper.AgeGate{
+				Cookies: []*http.Cookie{
+					{Name: "adc", Value: "1"},
+				},
+			}),
+			scraper.WithThrottleDetector(scraper.ThrottleMarkers(30*time.Second, "アクセスが集中"))),
 	}
 }
 
@@ -152,7 +156,8 @@ func (mgs *MGS) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err err
 	// Preview Video
 	c.OnXML(`//div[@class="detail_data"]//p[@class="sample_movie_btn"]`, func(e *colly.XMLElement) {
 		if pid := path.Base(e.ChildAttr(`.//a`, "href")); pid != "" {
-			d := c.Clone()
+			d, cancel := mgs.SubCollector(c)
+			defer cancel()
 			d.OnResponse(func(r *colly.Response) {
 				data := make(map[string]string)
 				if json.Unmarshal(r.Body, &data) == nil {
@@ -198,6 +203,8 @@ func (mgs *MGS) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err err
 		}
 	})
 
+	mgs.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
@@ -243,4 +250,5 @@ func imageSrc(s string, thumb bool) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.mgstage.com", Path: "/product/product_detail/"})
 }
@@ -11,6 +11,7 @@ import (
 var (
 	_ provider.MovieProvider = (*MuraMura)(nil)
 	_ provider.MovieReviewer = (*MuraMura)(nil)
+	_ provider.MovieEnricher = (*MuraMura)(nil)
 )
 
 const (
@@ -56,4 +57,5 @@ func (ppm *MuraMura) NormalizeMovieID(id string) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.muramura.tv", Path: "/movies/"})
 }
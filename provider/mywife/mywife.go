@@ -31,7 +31,8 @@ type MyWife struct {
 }
 
 func New() *MyWife {
-	return &MyWife{scraper.NewDefaultScraper(Name, baseURL, Priority)}
+	return &MyWife{scraper.NewDefaultScraper(Name, baseURL, Priority,
+		scraper.WithContentTypes(scraper.HTMLContentTypes...))}
 }
 
 func (mw *MyWife) NormalizeMovieID(id string) string {
@@ -115,7 +116,8 @@ func (mw *MyWife) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 			return
 		}
 
-		d := c.Clone()
+		d, cancel := mw.SubCollector(c)
+		defer cancel()
 		d.OnScraped(func(r *colly.Response) {
 			info.ThumbURL = r.Request.URL.String()
 			info.BigThumbURL = info.ThumbURL /* thumb is usually quality */
@@ -123,10 +125,13 @@ func (mw *MyWife) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err e
 		d.Head(strings.ReplaceAll(info.CoverURL, "topview.jpg", "thumb.jpg"))
 	})
 
+	mw.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.mywife.cc", Path: "/teigaku/model/no/"})
 }
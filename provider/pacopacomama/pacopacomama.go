@@ -10,6 +10,7 @@ import (
 var (
 	_ provider.MovieProvider = (*Pacopacomama)(nil)
 	_ provider.MovieReviewer = (*Pacopacomama)(nil)
+	_ provider.MovieEnricher = (*Pacopacomama)(nil)
 )
 
 const (
@@ -61,4 +62,5 @@ func (ppm *Pacopacomama) NormalizeMovieID(id string) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.pacopacomama.com", Path: "/movies/"})
 }
@@ -0,0 +1,16 @@
+package provider
+
+// SelectorPatch overrides or supplements how a provider parses a field of
+// infos, so that site layout changes can be hotfixed by configuration.
+type SelectorPatch struct {
+	// Field is the JSON name of the info field, e.g., `summary`.
+	Field string `json:"field"`
+	// XPath selects the nodes of the field value.
+	XPath string `json:"xpath"`
+	// Attr is the attribute of the nodes as the value, the inner text is
+	// used if empty.
+	Attr string `json:"attr,omitempty"`
+	// Supplement only fills the field if the provider leaves it empty,
+	// otherwise the parsed value is overridden.
+	Supplement bool `json:"supplement,omitempty"`
+}
@@ -36,9 +36,12 @@ type Pcolle struct {
 func New() *Pcolle {
 	return &Pcolle{
 		Scraper: scraper.NewDefaultScraper(Name, baseURL, Priority,
-			scraper.WithCookies(baseURL, []*http.Cookie{
-				{Name: "AGE_CONF", Value: "1"},
-			})),
+			scraper.WithAgeGate(&scraper.AgeGate{
+				Cookies: []*http.Cookie{
+					{Name: "AGE_CONF", Value: "1"},
+				},
+			}),
+			scraper.WithContentTypes(scraper.HTMLContentTypes...)),
 	}
 }
 
@@ -143,10 +146,13 @@ func (pcl *Pcolle) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err
 		}
 	})
 
+	pcl.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.pcolle.com", Path: "/product/detail/"})
 }
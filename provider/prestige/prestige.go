@@ -42,10 +42,13 @@ type PRESTIGE struct {
 func New() *PRESTIGE {
 	return &PRESTIGE{
 		Scraper: scraper.NewDefaultScraper(Name, baseURL, Priority,
-			scraper.WithCookies(baseURL, []*http.Cookie{
-				{Name: "coc", Value: "1"},
-				{Name: "age_auth", Value: "1"},
-			})),
+			scraper.WithAgeGate(&scraper.AgeGate{
+				Cookies: []*http.Cookie{
+					{Name: "coc", Value: "1"},
+					{Name: "age_auth", Value: "1"},
+				},
+			}),
+			scraper.WithContentTypes(scraper.HTMLContentTypes...)),
 	}
 }
 
@@ -166,7 +169,8 @@ func (pst *PRESTIGE) GetMovieInfoByURL(u string) (info *model.MovieInfo, err err
 			wg.Add(1)
 			go func(i int) {
 				defer wg.Done()
-				d := c.Clone()
+				d, cancel := pst.SubCollector(c)
+				defer cancel()
 				// existence check.
 				switch i {
 				case 0: // thumb
@@ -185,6 +189,8 @@ func (pst *PRESTIGE) GetMovieInfoByURL(u string) (info *model.MovieInfo, err err
 		wg.Wait()
 	})
 
+	pst.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
@@ -249,4 +255,5 @@ func imageSrc(s string, thumb bool) string {
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.prestige-av.com", Path: "/goods/"})
 }
@@ -1,13 +1,24 @@
 package provider
 
 import (
+	"context"
 	"net/http"
 	"net/url"
 	"time"
 
+	"golang.org/x/net/proxy"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/common/storage"
 	"github.com/metatube-community/metatube-sdk-go/model"
 )
 
+// Provider is the basic interface of all providers.
+//
+// A single provider instance is shared by all the callers, so all its
+// methods must be safe for concurrent use by multiple goroutines, except
+// for setters, e.g. SetRequestTimeout and EnableSnapshot, which must only
+// be called before the instance is used.
 type Provider interface {
 	// Name returns the name of the provider.
 	Name() string
@@ -19,6 +30,12 @@ type Provider interface {
 	URL() *url.URL
 }
 
+type DisplayNamer interface {
+	// DisplayName returns the human-readable name of the provider, e.g.,
+	// of the site, which is credited as the source of infos.
+	DisplayName() string
+}
+
 type MovieSearcher interface {
 	// SearchMovie searches matched movies.
 	SearchMovie(keyword string) ([]*model.MovieSearchResult, error)
@@ -74,6 +91,18 @@ type ActorProvider interface {
 	GetActorInfoByURL(url string) (*model.ActorInfo, error)
 }
 
+type MovieLocalizer interface {
+	// GetLocalizedMovieInfoByID gets movie's info by id with its texts,
+	// e.g., title and summary, localized in the given language.
+	GetLocalizedMovieInfoByID(id, lang string) (*model.MovieInfo, error)
+}
+
+type ActorLocalizer interface {
+	// GetLocalizedActorInfoByID gets actor's info by id with its name and
+	// aliases localized in the given language, the original name is kept.
+	GetLocalizedActorInfoByID(id, lang string) (*model.ActorInfo, error)
+}
+
 type Fetcher interface {
 	// Fetch fetches media resources from url.
 	Fetch(url string) (*http.Response, error)
@@ -83,3 +112,101 @@ type RequestTimeoutSetter interface {
 	// SetRequestTimeout sets timeout for HTTP requests.
 	SetRequestTimeout(timeout time.Duration)
 }
+
+type ContextSetter interface {
+	// SetContext sets the context of HTTP requests, which are canceled
+	// along with it, e.g., by search deadlines. It must only be called on
+	// instances used exclusively, e.g., pooled ones, nil to unset.
+	SetContext(ctx context.Context)
+}
+
+type SubRequestTimeoutSetter interface {
+	// SetSubRequestTimeout sets timeout for nested sub-requests of scrapes,
+	// e.g., of HLS playlists, which is disabled if zero.
+	SetSubRequestTimeout(timeout time.Duration)
+}
+
+type ArchiveFallback interface {
+	// SetArchiveFallback enables fetching removed pages, e.g., of dead
+	// studios, from snapshots of the Internet Archive. It must be called
+	// before use.
+	SetArchiveFallback(enabled bool)
+
+	// ArchivedURL returns the snapshot URL which the page of the URL has
+	// been fetched from, if it's removed.
+	ArchivedURL(rawURL string) (string, bool)
+}
+
+type TransportWrapper interface {
+	// WrapTransport wraps the underlying transport for HTTP requests.
+	WrapTransport(wrapper func(http.RoundTripper) http.RoundTripper)
+}
+
+type ThrottleNotifier interface {
+	// SetThrottleHandler sets the handler called when requests are paused
+	// by the server throttling, e.g., `Retry-After` of 429 responses.
+	SetThrottleHandler(handler func(wait time.Duration))
+}
+
+type CredentialSetter interface {
+	// CredentialRealm returns the realm of accounts to log in member-only
+	// contents, e.g., `d2pass`, or empty if not supported.
+	CredentialRealm() string
+
+	// SetCredentials sets the account of the realm.
+	SetCredentials(username, password string)
+}
+
+type SessionStorer interface {
+	// SetSessionStore sets the store persisting member sessions, which is
+	// shared by providers. It must be called before use.
+	SetSessionStore(store storage.KV)
+}
+
+type SelectorPatcher interface {
+	// SetSelectorPatches sets the patches of info fields, which are applied
+	// after the built-in selectors. It must be called before use.
+	SetSelectorPatches(patches ...*SelectorPatch)
+}
+
+type SoftNotFoundSetter interface {
+	// SetSoftNotFound adds the markers of pages of missing contents served
+	// with 200 to the built-in ones. It must be called before use.
+	SetSoftNotFound(d *SoftNotFound)
+}
+
+type HostOverrider interface {
+	// SetHostOverrides sets the static IPs and Host headers of hostnames
+	// requested. It must be called before use.
+	SetHostOverrides(overrides ...*HostOverride)
+}
+
+type DialerSetter interface {
+	// SetDialer sets the dialer of HTTP connections, e.g., via SOCKS5
+	// proxies. It must be called before use.
+	SetDialer(dialer proxy.ContextDialer)
+}
+
+type FetchClientSetter interface {
+	// SetFetchClient replaces the HTTP backend of the provider, e.g., by
+	// headless browsers or recorded fixtures. It must be called before use.
+	SetFetchClient(client fetch.Client)
+}
+
+type MovieEnricher interface {
+	// SetLazyFields sets the expensive fields skipped by getting movie
+	// infos, which are resolved by EnrichMovieInfo on demand.
+	SetLazyFields(fields ...Field)
+
+	// EnrichMovieInfo resolves the expensive fields of the movie info got
+	// by the provider, other fields are kept as is.
+	EnrichMovieInfo(info *model.MovieInfo, fields ...Field) error
+}
+
+type Snapshotter interface {
+	// EnableSnapshot starts recording HTTP responses and matched selectors.
+	EnableSnapshot()
+
+	// Snapshot returns what has been recorded since the snapshot enabled.
+	Snapshot() *Snapshot
+}
@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// URLPattern recognizes page URLs of a provider by host and path prefix.
+type URLPattern struct {
+	// Host is the host name, a leading `*.` matches the domain itself and
+	// all its sub domains, e.g., `*.dmm.co.jp`.
+	Host string
+	// Path is the path prefix, empty matches all.
+	Path string
+}
+
+// Match reports whether the URL matches the pattern.
+func (p URLPattern) Match(u *url.URL) bool {
+	host := strings.ToLower(u.Hostname())
+	if domain, ok := strings.CutPrefix(p.Host, "*."); ok {
+		if host != domain && !strings.HasSuffix(host, "."+domain) {
+			return false
+		}
+	} else if host != p.Host {
+		return false
+	}
+	return strings.HasPrefix(u.Path, p.Path)
+}
+
+// specificity orders patterns, exact hosts and longer paths go first.
+func (p URLPattern) specificity() int {
+	s := len(p.Path) * 2
+	if !strings.HasPrefix(p.Host, "*.") {
+		s++
+	}
+	return s
+}
+
+var (
+	routeMu          sync.RWMutex
+	movieURLPatterns = make(map[string][]URLPattern)
+	actorURLPatterns = make(map[string][]URLPattern)
+)
+
+// RegisterMovieURLPatterns registers page URL patterns of the movie
+// provider, which is usually called in init along with the factory.
+func RegisterMovieURLPatterns(name string, patterns ...URLPattern) {
+	routeMu.Lock()
+	movieURLPatterns[name] = append(movieURLPatterns[name], patterns...)
+	routeMu.Unlock()
+}
+
+// RegisterActorURLPatterns registers page URL patterns of the actor
+// provider, which is usually called in init along with the factory.
+func RegisterActorURLPatterns(name string, patterns ...URLPattern) {
+	routeMu.Lock()
+	actorURLPatterns[name] = append(actorURLPatterns[name], patterns...)
+	routeMu.Unlock()
+}
+
+// Router routes page URLs to provider instances and their IDs.
+//
+// Providers are matched by their registered patterns in the order of
+// specificity, and then by the host and path of their base URLs, so that
+// providers without patterns are still routable. The first provider that
+// parses a non-empty ID from the URL wins.
+type Router struct {
+	movies *routeTable[MovieProvider]
+	actors *routeTable[ActorProvider]
+}
+
+type route[T Provider] struct {
+	pattern  URLPattern
+	provider T
+}
+
+type routeTable[T Provider] struct {
+	routes    []*route[T]
+	providers []T
+}
+
+// NewRouter returns an empty Router, it must not be modified after use.
+func NewRouter() *Router {
+	return &Router{
+		movies: &routeTable[MovieProvider]{},
+		actors: &routeTable[ActorProvider]{},
+	}
+}
+
+// AddMovieProvider adds the movie provider with its registered patterns.
+func (r *Router) AddMovieProvider(p MovieProvider) {
+	routeMu.RLock()
+	patterns := movieURLPatterns[p.Name()]
+	routeMu.RUnlock()
+	r.movies.add(p, patterns)
+}
+
+// AddActorProvider adds the actor provider with its registered patterns.
+func (r *Router) AddActorProvider(p ActorProvider) {
+	routeMu.RLock()
+	patterns := actorURLPatterns[p.Name()]
+	routeMu.RUnlock()
+	r.actors.add(p, patterns)
+}
+
+// RouteMovieURL returns the movie provider owning the URL, and the movie
+// ID parsed from it.
+func (r *Router) RouteMovieURL(rawURL string) (MovieProvider, string, error) {
+	return r.movies.route(rawURL, MovieProvider.ParseMovieIDFromURL)
+}
+
+// RouteActorURL returns the actor provider owning the URL, and the actor
+// ID parsed from it.
+func (r *Router) RouteActorURL(rawURL string) (ActorProvider, string, error) {
+	return r.actors.route(rawURL, ActorProvider.ParseActorIDFromURL)
+}
+
+func (t *routeTable[T]) add(p T, patterns []URLPattern) {
+	for _, pattern := range patterns {
+		t.routes = append(t.routes, &route[T]{pattern: pattern, provider: p})
+	}
+	t.providers = append(t.providers, p)
+	less := func(a, b T) bool {
+		if a.Priority() != b.Priority() {
+			return a.Priority() > b.Priority()
+		}
+		return a.Name() < b.Name()
+	}
+	sort.SliceStable(t.routes, func(i, j int) bool {
+		si, sj := t.routes[i].pattern.specificity(), t.routes[j].pattern.specificity()
+		if si != sj {
+			return si > sj
+		}
+		return less(t.routes[i].provider, t.routes[j].provider)
+	})
+	sort.SliceStable(t.providers, func(i, j int) bool {
+		return less(t.providers[i], t.providers[j])
+	})
+}
+
+func (t *routeTable[T]) route(rawURL string, parse func(T, string) (string, error)) (p T, id string, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return p, "", ErrInvalidURL
+	}
+	try := func(provider T) bool {
+		if id, err = parse(provider, rawURL); err == nil && id != "" {
+			p = provider
+			return true
+		}
+		return false
+	}
+	for _, route := range t.routes {
+		if route.pattern.Match(u) && try(route.provider) {
+			return p, id, nil
+		}
+	}
+	// fallback to base URLs of providers.
+	for _, provider := range t.providers {
+		base := provider.URL()
+		if (URLPattern{Host: base.Hostname(), Path: base.Path}).Match(u) && try(provider) {
+			return p, id, nil
+		}
+	}
+	return p, "", ErrProviderNotFound
+}
+
+var (
+	defaultRouterOnce sync.Once
+	defaultRouter     *Router
+)
+
+// RouteURL routes the page URL to the registered movie provider owning
+// it, and returns the movie ID parsed from it. Only the providers with
+// registered patterns are routable, and their instances are created on
+// first use and shared by all the callers.
+func RouteURL(rawURL string) (MovieProvider, string, error) {
+	defaultRouterOnce.Do(func() {
+		defaultRouter = NewRouter()
+		RangeMovieFactory(func(name string, factory MovieFactory) {
+			routeMu.RLock()
+			patterns := movieURLPatterns[name]
+			routeMu.RUnlock()
+			if len(patterns) > 0 {
+				defaultRouter.movies.add(factory(), patterns)
+			}
+		})
+	})
+	return defaultRouter.RouteMovieURL(rawURL)
+}
@@ -0,0 +1,94 @@
+package provider
+
+import (
+	"net/url"
+	"path"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+type fakeMovieProvider struct {
+	name string
+	url  string
+}
+
+func (p *fakeMovieProvider) Name() string { return p.name }
+
+func (p *fakeMovieProvider) Priority() int { return 0 }
+
+func (p *fakeMovieProvider) URL() *url.URL {
+	u, _ := url.Parse(p.url)
+	return u
+}
+
+func (p *fakeMovieProvider) NormalizeMovieID(id string) string { return id }
+
+func (p *fakeMovieProvider) ParseMovieIDFromURL(rawURL string) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	if id := path.Base(u.Path); id != "/" && id != "." {
+		return id, nil
+	}
+	return "", nil
+}
+
+func (p *fakeMovieProvider) GetMovieInfoByID(string) (*model.MovieInfo, error) { return nil, nil }
+
+func (p *fakeMovieProvider) GetMovieInfoByURL(string) (*model.MovieInfo, error) { return nil, nil }
+
+func TestURLPatternMatch(t *testing.T) {
+	for _, unit := range []struct {
+		pattern URLPattern
+		url     string
+		match   bool
+	}{
+		{URLPattern{Host: "*.example.com"}, "https://example.com/a", true},
+		{URLPattern{Host: "*.example.com"}, "https://www.example.com/a", true},
+		{URLPattern{Host: "*.example.com"}, "https://notexample.com/a", false},
+		{URLPattern{Host: "www.example.com"}, "https://example.com/a", false},
+		{URLPattern{Host: "www.example.com", Path: "/movies/"}, "https://WWW.example.com/movies/a", true},
+		{URLPattern{Host: "www.example.com", Path: "/movies/"}, "https://www.example.com/actors/a", false},
+	} {
+		u, _ := url.Parse(unit.url)
+		assert.Equal(t, unit.match, unit.pattern.Match(u), unit.url)
+	}
+}
+
+func TestRouter(t *testing.T) {
+	RegisterMovieURLPatterns("RouterTestDigital", URLPattern{Host: "*.example.com", Path: "/digital/"})
+	RegisterMovieURLPatterns("RouterTestAny", URLPattern{Host: "*.example.com"})
+
+	r := NewRouter()
+	r.AddMovieProvider(&fakeMovieProvider{name: "RouterTestAny", url: "https://www.example.com/"})
+	r.AddMovieProvider(&fakeMovieProvider{name: "RouterTestDigital", url: "https://www.example.com/digital/"})
+	r.AddMovieProvider(&fakeMovieProvider{name: "RouterTestBase", url: "https://www.example.org/"})
+
+	for _, unit := range []struct {
+		url      string
+		provider string
+		id       string
+		err      error
+	}{
+		{"https://www.example.com/digital/abc", "RouterTestDigital", "abc", nil},
+		{"https://m.example.com/mono/abc", "RouterTestAny", "abc", nil},
+		{"https://www.example.org/abc", "RouterTestBase", "abc", nil},
+		{"https://m.example.org/abc", "", "", ErrProviderNotFound},
+		{"https://www.example.org/", "", "", ErrProviderNotFound},
+		{"abc", "", "", ErrInvalidURL},
+	} {
+		p, id, err := r.RouteMovieURL(unit.url)
+		if unit.err != nil {
+			assert.Equal(t, unit.err, err, unit.url)
+			continue
+		}
+		if assert.NoError(t, err, unit.url) {
+			assert.Equal(t, unit.provider, p.Name(), unit.url)
+			assert.Equal(t, unit.id, id, unit.url)
+		}
+	}
+}
@@ -0,0 +1,37 @@
+package provider
+
+import (
+	"net/http"
+	"time"
+)
+
+// Snapshot holds the raw responses and the matched selector values
+// of a scrape, which is useful for diagnosing parsing issues.
+type Snapshot struct {
+	Responses []*SnapshotResponse `json:"responses"`
+	Selectors []*SnapshotSelector `json:"selectors"`
+}
+
+// SnapshotResponse is a raw HTTP response fetched during a scrape, along
+// with its request. Failed requests are recorded with the error.
+type SnapshotResponse struct {
+	URL           string        `json:"url"`
+	Method        string        `json:"method"`
+	RequestHeader http.Header   `json:"request_header,omitempty"`
+	RequestBody   string        `json:"request_body,omitempty"`
+	StatusCode    int           `json:"status_code"`
+	Header        http.Header   `json:"header,omitempty"`
+	ContentType   string        `json:"content_type"`
+	Body          string        `json:"body"`
+	Error         string        `json:"error,omitempty"`
+	StartedAt     time.Time     `json:"started_at"`
+	Elapsed       time.Duration `json:"elapsed"`
+}
+
+// SnapshotSelector is a selector matched during a scrape, and the
+// text values of the matched nodes.
+type SnapshotSelector struct {
+	URL      string   `json:"url"`
+	Selector string   `json:"selector"`
+	Values   []string `json:"values"`
+}
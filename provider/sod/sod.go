@@ -5,7 +5,6 @@ import (
 	"net/url"
 	"strings"
 
-	"github.com/antchfx/htmlquery"
 	"github.com/gocolly/colly/v2"
 	"golang.org/x/net/html"
 
@@ -18,9 +17,10 @@ import (
 )
 
 var (
-	_ provider.MovieProvider = (*SOD)(nil)
-	_ provider.MovieSearcher = (*SOD)(nil)
-	_ provider.Fetcher       = (*SOD)(nil)
+	_ provider.MovieProvider     = (*SOD)(nil)
+	_ provider.MovieSearcher     = (*SOD)(nil)
+	_ provider.Fetcher           = (*SOD)(nil)
+	_ provider.FetchClientSetter = (*SOD)(nil)
 )
 
 const (
@@ -48,6 +48,12 @@ func New() *SOD {
 	}
 }
 
+// SetFetchClient sets the client of both pages and media resources.
+func (sod *SOD) SetFetchClient(client fetch.Client) {
+	sod.Fetcher.SetClient(client)
+	sod.Scraper.SetFetchClient(client)
+}
+
 func (sod *SOD) NormalizeMovieID(id string) string {
 	return strings.ToUpper(id) /* SOD requires uppercase ID */
 }
@@ -96,28 +102,29 @@ func (sod *SOD) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err err
 	})
 
 	// Fields
-	c.OnXML(`//*[@id="v_introduction"]/tbody/tr`, func(e *colly.XMLElement) {
-		switch e.ChildText(`.//td[1]`) {
-		case "品番":
-			info.Number = strings.TrimSpace(e.ChildText(`.//td[2]`))
-		case "発売年月日":
-			info.ReleaseDate = parser.ParseDate(e.ChildText(`.//td[2]`))
-		case "シリーズ名":
-			info.Series = strings.TrimSpace(e.ChildText(`.//td[2]`))
-		case "出演者":
-			parser.ParseTexts(htmlquery.FindOne(e.DOM.(*html.Node), `.//td[2]`),
-				(*[]string)(&info.Actors))
-		case "再生時間":
-			info.Runtime = parser.ParseRuntime(e.ChildText(`.//td[2]`))
-		case "監督":
-			info.Director = strings.TrimSpace(e.ChildText(`.//td[2]`))
-		case "メーカー":
-			info.Maker = strings.TrimSpace(e.ChildText(`.//td[2]`))
-		case "レーベル":
-			info.Label = strings.TrimSpace(e.ChildText(`.//td[2]`))
-		case "ジャンル":
-			parser.ParseTexts(htmlquery.FindOne(e.DOM.(*html.Node), `.//td[2]`),
-				(*[]string)(&info.Genres))
+	c.OnXML(`//*[@id="v_introduction"]`, func(e *colly.XMLElement) {
+		kv := parser.ExtractKV(e.DOM.(*html.Node), `./tbody/tr`, `./td[1]`, `./td[2]`, nil)
+		for key := range kv {
+			switch key {
+			case "品番":
+				info.Number = kv.Text(key)
+			case "発売年月日":
+				info.ReleaseDate = parser.ParseDate(kv.Text(key))
+			case "シリーズ名":
+				info.Series = kv.Text(key)
+			case "出演者":
+				info.Actors = kv.Texts(key)
+			case "再生時間":
+				info.Runtime = parser.ParseRuntime(kv.Text(key))
+			case "監督":
+				info.Director = kv.Text(key)
+			case "メーカー":
+				info.Maker = kv.Text(key)
+			case "レーベル":
+				info.Label = kv.Text(key)
+			case "ジャンル":
+				info.Genres = kv.Texts(key)
+			}
 		}
 	})
 
@@ -133,7 +140,8 @@ func (sod *SOD) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err err
 
 	// Preview Video
 	c.OnXML(`//div[@class="videos_textli"]//div[@class="videos_sampb"]/a`, func(e *colly.XMLElement) {
-		d := c.Clone()
+		d, cancel := sod.SubCollector(c)
+		defer cancel()
 		d.OnXML(`//*[@id="moviebox"]/video/source`, func(e *colly.XMLElement) {
 			info.PreviewVideoURL = e.Request.AbsoluteURL(e.Attr("src"))
 		})
@@ -156,6 +164,8 @@ func (sod *SOD) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err err
 		info.Score = parser.ParseScore(e.Text)
 	})
 
+	sod.PatchCollector(c, info)
+
 	err = c.Visit(composedMovieURL)
 	return
 }
@@ -205,4 +215,5 @@ func (sod *SOD) SearchMovie(keyword string) (results []*model.MovieSearchResult,
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "ec.sod.co.jp", Path: "/prime/videos/"})
 }
@@ -0,0 +1,13 @@
+package provider
+
+// SoftNotFound detects pages of missing contents served with 200, e.g.,
+// "content not found" templates or placeholder covers, which are reported
+// as ErrInfoNotFound rather than parsed as infos.
+type SoftNotFound struct {
+	// Markers are texts in pages of missing contents, which are matched
+	// case-insensitively, e.g., `お探しの商品が見つかりません`.
+	Markers []string `json:"markers,omitempty"`
+	// ImageHashes are SHA-256 hashes in hex of placeholder images, infos
+	// with placeholder covers or primary images are not found.
+	ImageHashes []string `json:"image_hashes,omitempty"`
+}
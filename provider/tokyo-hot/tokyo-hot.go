@@ -20,8 +20,9 @@ import (
 )
 
 var (
-	_ provider.MovieProvider = (*TokyoHot)(nil)
-	_ provider.MovieSearcher = (*TokyoHot)(nil)
+	_ provider.MovieProvider  = (*TokyoHot)(nil)
+	_ provider.MovieSearcher  = (*TokyoHot)(nil)
+	_ provider.MovieLocalizer = (*TokyoHot)(nil)
 )
 
 const (
@@ -51,7 +52,13 @@ func New() *TokyoHot {
 			TLSHandshakeTimeout:   http.DefaultTransport.(*http.Transport).TLSHandshakeTimeout,
 			ExpectContinueTimeout: http.DefaultTransport.(*http.Transport).ExpectContinueTimeout,
 			TLSClientConfig:       &tls.Config{InsecureSkipVerify: true},
-		}))}
+		}),
+		scraper.WithLocales(&scraper.LocaleStrategy{
+			Default:   "ja",
+			Supported: []string{"ja", "en", "zh-TW"},
+			Localize:  localizeURL,
+		}),
+		scraper.WithContentTypes(scraper.HTMLContentTypes...))}
 }
 
 func (tht *TokyoHot) NormalizeMovieID(id string) string {
@@ -95,15 +102,8 @@ func (tht *TokyoHot) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, er
 
 	// Summary
 	c.OnXML(`//*[@id="main"]//div[@class="sentence"]`, func(e *colly.XMLElement) {
-		var sentences []string
-		for n := e.DOM.(*html.Node).FirstChild; n != nil; n = n.NextSibling {
-			if n.Type != html.TextNode {
-				continue
-			}
-			sentences = append(sentences, strings.TrimSpace(n.Data))
-		}
-		if len(sentences) > 0 {
-			info.Summary = strings.TrimSpace(strings.Join(sentences, "\n"))
+		if summary := parseSummary(e); summary != "" {
+			info.Summary = summary
 		}
 	})
 
@@ -184,10 +184,75 @@ func (tht *TokyoHot) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, er
 		}
 	})
 
+	tht.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
 
+// GetLocalizedMovieInfoByID gets movie's info by id with the title and
+// summary localized in ja, en or zh-TW. Only texts are localized, other
+// fields are parsed from the native page.
+func (tht *TokyoHot) GetLocalizedMovieInfoByID(id, lang string) (info *model.MovieInfo, err error) {
+	if lang = tht.NegotiateLanguage(lang); lang == "" {
+		return nil, provider.ErrInvalidLanguage
+	}
+	if info, err = tht.GetMovieInfoByID(id); err != nil || lang == tht.NativeLanguage() {
+		return
+	}
+	if err = tht.localizeMovieInfo(info, lang); err != nil {
+		return nil, err
+	}
+	return
+}
+
+// localizeMovieInfo replaces title and summary with the localized ones.
+func (tht *TokyoHot) localizeMovieInfo(info *model.MovieInfo, lang string) error {
+	c, rawURL := tht.LocalizedCollector(info.Homepage, lang)
+
+	c.OnXML(`//*[@id="main"]//div[@class="contents"]/h2`, func(e *colly.XMLElement) {
+		if title := strings.TrimSpace(e.Text); title != "" {
+			info.Title = title
+			info.FieldLanguages["title"] = lang
+		}
+	})
+
+	c.OnXML(`//*[@id="main"]//div[@class="sentence"]`, func(e *colly.XMLElement) {
+		if summary := parseSummary(e); summary != "" {
+			info.Summary = summary
+			info.FieldLanguages["summary"] = lang
+		}
+	})
+
+	info.FieldLanguages = map[string]string{
+		"title":   tht.NativeLanguage(),
+		"summary": tht.NativeLanguage(),
+	}
+	return c.Visit(rawURL)
+}
+
+func parseSummary(e *colly.XMLElement) string {
+	var sentences []string
+	for n := e.DOM.(*html.Node).FirstChild; n != nil; n = n.NextSibling {
+		if n.Type != html.TextNode {
+			continue
+		}
+		sentences = append(sentences, strings.TrimSpace(n.Data))
+	}
+	return strings.TrimSpace(strings.Join(sentences, "\n"))
+}
+
+func localizeURL(rawURL, lang string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	q := u.Query()
+	q.Set("lang", lang)
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
 func (tht *TokyoHot) NormalizeMovieKeyword(keyword string) string {
 	if regexp.MustCompile(`^(?i)[a-z_]*\d+$`).MatchString(keyword) {
 		return strings.ToLower(keyword)
@@ -237,4 +302,5 @@ func (tht *TokyoHot) SearchMovie(keyword string) (results []*model.MovieSearchRe
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "my.tokyo-hot.com", Path: "/product/"})
 }
@@ -5,6 +5,8 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
 )
 
 func TestTokyoHot_GetMovieInfoByID(t *testing.T) {
@@ -38,3 +40,15 @@ func TestTokyoHot_SearchMovie(t *testing.T) {
 		t.Logf("%s", data)
 	}
 }
+
+func TestTokyoHot_GetLocalizedMovieInfoByID(t *testing.T) {
+	provider := New()
+	for _, lang := range []string{"ja", "en", "zh-TW"} {
+		info, err := provider.GetLocalizedMovieInfoByID("n1633", lang)
+		data, _ := json.MarshalIndent(info, "", "\t")
+		assert.True(t, assert.NoError(t, err) && assert.True(t, info.Valid()))
+		t.Logf("%s", data)
+	}
+	_, err := provider.GetLocalizedMovieInfoByID("n1633", "fr")
+	assert.Equal(t, mt.ErrInvalidLanguage, err)
+}
@@ -19,8 +19,9 @@ import (
 )
 
 var (
-	_ provider.ActorProvider = (*XsList)(nil)
-	_ provider.ActorSearcher = (*XsList)(nil)
+	_ provider.ActorProvider  = (*XsList)(nil)
+	_ provider.ActorSearcher  = (*XsList)(nil)
+	_ provider.ActorLocalizer = (*XsList)(nil)
 )
 
 const (
@@ -31,6 +32,7 @@ const (
 const (
 	baseURL   = "https://xslist.org/"
 	actorURL  = "https://xslist.org/zh/model/%s.html"
+	langURL   = "https://xslist.org/%s/model/%s.html"
 	searchURL = "https://xslist.org/search?query=%s&lg=zh"
 )
 
@@ -39,13 +41,59 @@ type XsList struct {
 }
 
 func New() *XsList {
-	return &XsList{scraper.NewDefaultScraper(Name, baseURL, Priority, scraper.WithDisableCookies())}
+	return &XsList{scraper.NewDefaultScraper(Name, baseURL, Priority,
+		scraper.WithDisplayName("X/sList"),
+		scraper.WithDisableCookies(),
+		scraper.WithContentTypes(scraper.HTMLContentTypes...))}
 }
 
 func (xsl *XsList) GetActorInfoByID(id string) (info *model.ActorInfo, err error) {
 	return xsl.GetActorInfoByURL(fmt.Sprintf(actorURL, id))
 }
 
+// GetLocalizedActorInfoByID gets actor's info by id with the name and aliases
+// localized in zh, ja or en, the original name is always the Japanese one.
+func (xsl *XsList) GetLocalizedActorInfoByID(id, lang string) (info *model.ActorInfo, err error) {
+	lang = strings.ToLower(lang)
+	switch lang {
+	case "zh", "ja", "en":
+	default:
+		return nil, provider.ErrInvalidLanguage
+	}
+	if info, err = xsl.GetActorInfoByID(id); err != nil {
+		return
+	}
+	if lang != "zh" {
+		name, aliases, err := xsl.getActorNames(id, lang)
+		if err != nil {
+			return nil, err
+		}
+		if name != "" {
+			info.Name, info.Aliases = name, aliases
+		}
+	}
+	if lang == "ja" {
+		info.OriginalName = info.Name
+	}
+	return
+}
+
+// getActorNames gets actor's name and aliases from the page of lang.
+func (xsl *XsList) getActorNames(id, lang string) (name string, aliases []string, err error) {
+	c := xsl.ClonedCollector()
+
+	c.OnXML(`//*[@id="sss1"]/header/h1/span`, func(e *colly.XMLElement) {
+		name = e.Text
+	})
+
+	c.OnXML(`//*[@id="sss1"]/p/span`, func(e *colly.XMLElement) {
+		aliases = append(aliases, e.Text)
+	})
+
+	err = c.Visit(fmt.Sprintf(langURL, lang, id))
+	return
+}
+
 func (xsl *XsList) ParseActorIDFromURL(rawURL string) (id string, err error) {
 	homepage, err := url.Parse(rawURL)
 	if err != nil {
@@ -110,30 +158,31 @@ func (xsl *XsList) GetActorInfoByURL(rawURL string) (info *model.ActorInfo, err
 
 	// Fields
 	c.OnXML(`//*[@id="layout"]/div/p[1]`, func(e *colly.XMLElement) {
+		var lines []string
 		for n := e.DOM.(*html.Node).FirstChild; n != nil; n = n.NextSibling {
-			if n.Type != html.TextNode {
+			if n.Type == html.TextNode {
+				lines = append(lines, n.Data)
+			}
+		}
+		for key, value := range parser.SplitKV(lines, ":", nil) {
+			if value == "n/a" {
 				continue
 			}
-			if ss := strings.SplitN(strings.TrimSpace(n.Data), ":", 2); len(ss) == 2 {
-				if ss[1] = strings.TrimSpace(ss[1]); ss[1] == "" || ss[1] == "n/a" {
-					continue
-				}
-				switch ss[0] {
-				case "出生":
-					info.Birthday = parser.ParseDate(ss[1])
-				case "三围":
-					info.Measurements = strings.ReplaceAll(ss[1], " ", "")
-				case "罩杯":
-					info.CupSize = strings.TrimSpace(strings.TrimSuffix(ss[1], "Cup"))
-				case "出道日期":
-					info.DebutDate = parseDebutDate(ss[1])
-				case "血型":
-					info.BloodType = ss[1]
-				case "身高":
-					info.Height = parser.ParseInt(strings.TrimRight(ss[1], "cm"))
-				case "国籍":
-					info.Nationality = ss[1]
-				}
+			switch key {
+			case "出生":
+				info.Birthday = parser.ParseDate(value)
+			case "三围":
+				info.Measurements = strings.ReplaceAll(value, " ", "")
+			case "罩杯":
+				info.CupSize = strings.TrimSpace(strings.TrimSuffix(value, "Cup"))
+			case "出道日期":
+				info.DebutDate = parseDebutDate(value)
+			case "血型":
+				info.BloodType = value
+			case "身高":
+				info.Height = parser.ParseInt(strings.TrimRight(value, "cm"))
+			case "国籍":
+				info.Nationality = value
 			}
 		}
 	})
@@ -148,7 +197,61 @@ func (xsl *XsList) GetActorInfoByURL(rawURL string) (info *model.ActorInfo, err
 		info.Nationality = strings.ReplaceAll(e.Text, "n/a", "")
 	})
 
-	err = c.Visit(info.Homepage)
+	// Filmography, which is listed in full on the profile page, so it's
+	// parsed along with the profile rather than crawled by scraper.Crawl,
+	// which would request the same page again.
+	c.OnXML(`//table[@id="movices"]/tbody/tr`, func(e *colly.XMLElement) {
+		number := strings.TrimSpace(e.ChildText(`.//td[1]`))
+		if number == "" {
+			return // header or empty row.
+		}
+		info.Filmography = append(info.Filmography, &model.FilmographyEntry{
+			Number:      number,
+			Title:       strings.TrimSpace(e.ChildText(`.//td[2]`)),
+			ReleaseDate: parser.ParseDate(strings.TrimSpace(e.ChildText(`.//td[3]`))),
+		})
+	})
+
+	// Social Links
+	c.OnXML(`//*[@id="layout"]//a[@href]`, func(e *colly.XMLElement) {
+		href := e.Attr("href")
+		if network := parseSocialNetwork(href); network != "" {
+			if info.SocialLinks == nil {
+				info.SocialLinks = make(map[string]string)
+			}
+			if _, ok := info.SocialLinks[network]; !ok {
+				info.SocialLinks[network] = href
+			}
+		}
+	})
+
+	// Related (similar models)
+	c.OnXML(`//*[self::h2 or self::h3][contains(., "相似")]/following-sibling::*[1]//a[contains(@href, "/model/")]`,
+		func(e *colly.XMLElement) {
+			relatedID, _ := xsl.ParseActorIDFromURL(e.Request.AbsoluteURL(e.Attr("href")))
+			if relatedID == "" || relatedID == id {
+				return
+			}
+			for _, r := range info.Related {
+				if r.ID == relatedID {
+					return // duplicated.
+				}
+			}
+			name := strings.TrimSpace(e.Attr("title"))
+			if name == "" {
+				name = strings.TrimSpace(e.Text)
+			}
+			info.Related = append(info.Related, &model.ActorRef{ID: relatedID, Name: name})
+		})
+
+	xsl.PatchCollector(c, info)
+
+	if err = c.Visit(info.Homepage); err != nil {
+		return
+	}
+	// the original name is the Japanese one, which is kept along with the
+	// default Chinese one, so that spellings in both are reconciled.
+	info.OriginalName, _, _ = xsl.getActorNames(id, "ja") // ignore error
 	return
 }
 
@@ -193,6 +296,23 @@ func parseDebutDate(s string) dt.Date {
 	return parser.ParseDate(s)
 }
 
+// parseSocialNetwork returns the social network of the profile link, or
+// empty if it's not a supported one.
+func parseSocialNetwork(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Path == "" || u.Path == "/" {
+		return ""
+	}
+	switch strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.") {
+	case "twitter.com", "x.com", "mobile.twitter.com":
+		return "twitter"
+	case "instagram.com":
+		return "instagram"
+	}
+	return ""
+}
+
 func init() {
 	provider.RegisterActorFactory(Name, New)
+	provider.RegisterActorURLPatterns(Name, provider.URLPattern{Host: "*.xslist.org"})
 }
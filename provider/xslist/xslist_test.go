@@ -2,9 +2,18 @@ package xslist
 
 import (
 	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/common/fetch"
+	"github.com/metatube-community/metatube-sdk-go/model"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
 )
 
 func TestXsList_GetActorInfoByID(t *testing.T) {
@@ -26,6 +35,20 @@ func TestXsList_GetActorInfoByID(t *testing.T) {
 	}
 }
 
+func TestXsList_GetLocalizedActorInfoByID(t *testing.T) {
+	provider := New()
+	for _, lang := range []string{"zh", "ja", "en"} {
+		info, err := provider.GetLocalizedActorInfoByID("107", lang)
+		data, _ := json.MarshalIndent(info, "", "\t")
+		if assert.NoError(t, err) && assert.True(t, info.Valid()) {
+			assert.NotEmpty(t, info.OriginalName)
+		}
+		t.Logf("%s", data)
+	}
+	_, err := provider.GetLocalizedActorInfoByID("107", "fr")
+	assert.Equal(t, mt.ErrInvalidLanguage, err)
+}
+
 func TestXsList_SearchActor(t *testing.T) {
 	provider := New()
 	for _, item := range []string{
@@ -44,3 +67,78 @@ func TestXsList_SearchActor(t *testing.T) {
 		t.Logf("%s", data)
 	}
 }
+
+func TestParseSocialNetwork(t *testing.T) {
+	for _, unit := range []struct {
+		url, want string
+	}{
+		{"https://twitter.com/yua_mikami", "twitter"},
+		{"https://x.com/yua_mikami", "twitter"},
+		{"https://www.instagram.com/yua_mikami/", "instagram"},
+		{"https://twitter.com/", ""},
+		{"https://xslist.org/zh/model/107.html", ""},
+	} {
+		assert.Equal(t, unit.want, parseSocialNetwork(unit.url), unit.url)
+	}
+}
+
+// fixtures returns the client replaying the pages of testdata in place of
+// the network.
+func fixtures(t *testing.T) fetch.Client {
+	page := func(url, name string) *fetch.Fixture {
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		require.NoError(t, err)
+		return &fetch.Fixture{
+			URL:    url,
+			Header: http.Header{"Content-Type": {"text/html; charset=utf-8"}},
+			Body:   data,
+		}
+	}
+	return fetch.NewFixtureClient(
+		page("https://xslist.org/zh/model/107.html", "model_107_zh.html"),
+		page("https://xslist.org/ja/model/107.html", "model_107_ja.html"),
+		page("https://xslist.org/search?query=%E4%B8%89%E4%B8%8A&lg=zh", "search.html"),
+	)
+}
+
+func TestXsList_Fixtures(t *testing.T) {
+	provider := New()
+	provider.SetFetchClient(fixtures(t))
+
+	info, err := provider.GetActorInfoByID("107")
+	require.NoError(t, err)
+	assert.True(t, info.Valid())
+	assert.Equal(t, "三上悠亚", info.Name)
+	assert.Equal(t, "三上悠亜", info.OriginalName)
+	assert.Equal(t, []string{"Yua Mikami", "鬼头桃菜"}, []string(info.Aliases))
+	assert.Equal(t, []string{"https://xslist.org/photos/1.jpg", "https://xslist.org/photos/profile.jpg"}, []string(info.Images))
+	assert.Equal(t, "1993-08-16", time.Time(info.Birthday).Format(time.DateOnly))
+	assert.Equal(t, "2015-06-01", time.Time(info.DebutDate).Format(time.DateOnly))
+	assert.Equal(t, "B83W53H84", info.Measurements)
+	assert.Equal(t, "G", info.CupSize)
+	assert.Equal(t, 159, info.Height)
+	assert.Empty(t, info.Nationality)
+	if assert.Len(t, info.Filmography, 2) {
+		assert.Equal(t, "SSIS-001", info.Filmography[0].Number)
+		assert.Equal(t, "新人", info.Filmography[0].Title)
+		assert.Equal(t, "2021-02-19", time.Time(info.Filmography[0].ReleaseDate).Format(time.DateOnly))
+	}
+	assert.Equal(t, map[string]string{
+		"twitter":   "https://twitter.com/yua_mikami",
+		"instagram": "https://www.instagram.com/yua_mikami/",
+	}, info.SocialLinks)
+	assert.Equal(t, []*model.ActorRef{{ID: "24490", Name: "河北彩花"}, {ID: "5085", Name: "桥本有菜"}}, info.Related)
+
+	results, err := provider.SearchActor("三上")
+	require.NoError(t, err)
+	if assert.Len(t, results, 2) {
+		assert.Equal(t, "107", results[0].ID)
+		assert.Equal(t, "三上悠亚", results[0].Name)
+		assert.Equal(t, []string{"https://xslist.org/photos/107.jpg"}, []string(results[0].Images))
+		assert.Empty(t, results[1].Images)
+	}
+
+	// nothing but the fixtures is requested.
+	_, err = provider.GetActorInfoByID("8")
+	assert.Error(t, err)
+}
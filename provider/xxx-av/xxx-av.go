@@ -39,7 +39,8 @@ func New() *TripleX {
 		Scraper: scraper.NewDefaultScraper(Name, baseURL, Priority,
 			scraper.WithCookies(baseURL, []*http.Cookie{
 				{Name: "acc_accept_lang", Value: "japanese"},
-			})),
+			}),
+			scraper.WithContentTypes(scraper.HTMLContentTypes...)),
 	}
 }
 
@@ -121,10 +122,13 @@ func (xav *TripleX) GetMovieInfoByURL(rawURL string) (info *model.MovieInfo, err
 		}
 	})
 
+	xav.PatchCollector(c, info)
+
 	err = c.Visit(info.Homepage)
 	return
 }
 
 func init() {
 	provider.RegisterMovieFactory(Name, New)
+	provider.RegisterMovieURLPatterns(Name, provider.URLPattern{Host: "*.xxx-av.com", Path: "/mov/movie/"})
 }
@@ -0,0 +1,27 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+func getAuditLogs(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &engine.AuditQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		logs, err := app.GetAuditLogs(query)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: logs})
+	}
+}
@@ -1,15 +1,38 @@
 package route
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"net/http"
 	"strings"
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/metatube-community/metatube-sdk-go/engine"
 	"github.com/metatube-community/metatube-sdk-go/errors"
 	"github.com/metatube-community/metatube-sdk-go/route/auth"
 )
 
+const (
+	auditActorKey       = "audit.actor"
+	anonymousAuditActor = "anonymous"
+)
+
+// tokenLabel identifies the token in audit logs without revealing it.
+func tokenLabel(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return "token:" + hex.EncodeToString(sum[:4])
+}
+
+// auditor returns the auditor of the authenticated actor.
+func auditor(app *engine.Engine, c *gin.Context) *engine.Auditor {
+	actor := c.GetString(auditActorKey)
+	if actor == "" /* auth disabled */ {
+		actor = anonymousAuditActor
+	}
+	return app.Auditor(actor)
+}
+
 func authentication(v auth.Validator) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		if v != nil /* auth enabled */ {
@@ -22,7 +45,26 @@ func authentication(v auth.Validator) gin.HandlerFunc {
 				abortWithError(c, errors.FromCode(http.StatusUnauthorized))
 				return
 			}
+			c.Set(auditActorKey, tokenLabel(token))
 		}
 		c.Next()
 	}
 }
+
+// apiKeyAuthentication accepts the `ApiKey` header used by stash-box
+// clients, as well as the standard bearer token.
+func apiKeyAuthentication(v auth.Validator) gin.HandlerFunc {
+	bearer := authentication(v)
+	return func(c *gin.Context) {
+		if key := c.GetHeader("ApiKey"); v != nil && key != "" {
+			if !v.Valid(key) {
+				abortWithError(c, errors.FromCode(http.StatusUnauthorized))
+				return
+			}
+			c.Set(auditActorKey, tokenLabel(key))
+			c.Next()
+			return
+		}
+		bearer(c)
+	}
+}
@@ -0,0 +1,61 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+type blocklistQuery struct {
+	Provider string `form:"provider"`
+	Number   string `form:"number"`
+}
+
+func getBlocklist(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entries, err := app.GetBlocklist()
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: entries})
+	}
+}
+
+func postBlocklist(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entry := &model.BlocklistEntry{}
+		if err := c.ShouldBindJSON(entry); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := auditor(app, c).AddBlocklistEntry(entry); err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: entry})
+	}
+}
+
+func deleteBlocklist(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &blocklistQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := auditor(app, c).RemoveBlocklistEntry(query.Provider, query.Number); err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: query})
+	}
+}
@@ -0,0 +1,31 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+type compareQuery struct {
+	Q string `form:"q" binding:"required"`
+}
+
+func getCompare(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &compareQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		comparison, err := app.CompareMovieInfos(query.Q)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: comparison})
+	}
+}
@@ -0,0 +1,87 @@
+package route
+
+import (
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/common/compress"
+)
+
+// compression compresses responses of compressible types by the
+// encoding negotiated with `Accept-Encoding`, images are served as is.
+func compression() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Writer.Header().Add("Vary", "Accept-Encoding")
+		encoding := compress.Negotiate(c.GetHeader("Accept-Encoding"))
+		if encoding == "" || c.Request.Method == http.MethodHead {
+			c.Next()
+			return
+		}
+		w := &compressWriter{ResponseWriter: c.Writer, encoding: encoding}
+		c.Writer = w
+		defer func() {
+			w.close()
+			c.Writer = w.ResponseWriter
+		}()
+		c.Next()
+	}
+}
+
+// compressWriter decides whether to compress on the first write, when
+// the status and content type are known.
+type compressWriter struct {
+	gin.ResponseWriter
+	encoding string
+	decided  bool
+	// encoder of the response, nil if not compressed.
+	encoder io.WriteCloser
+}
+
+func (w *compressWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	header := w.Header()
+	if w.Status() < http.StatusOK ||
+		w.Status() == http.StatusNoContent ||
+		w.Status() == http.StatusNotModified ||
+		header.Get("Content-Encoding") != "" ||
+		!compress.Compressible(header.Get("Content-Type")) {
+		return
+	}
+	encoder, err := compress.NewWriter(w.ResponseWriter, w.encoding)
+	if err != nil {
+		return // serve as is.
+	}
+	header.Set("Content-Encoding", w.encoding)
+	header.Del("Content-Length")
+	w.encoder = encoder
+}
+
+func (w *compressWriter) Write(data []byte) (int, error) {
+	w.decide()
+	if w.encoder == nil {
+		return w.ResponseWriter.Write(data)
+	}
+	return w.encoder.Write(data)
+}
+
+func (w *compressWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+func (w *compressWriter) Flush() {
+	if f, ok := w.encoder.(interface{ Flush() error }); ok {
+		_ = f.Flush()
+	}
+	w.ResponseWriter.Flush()
+}
+
+func (w *compressWriter) close() {
+	if w.encoder != nil {
+		_ = w.encoder.Close()
+	}
+}
@@ -0,0 +1,30 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/export"
+)
+
+// getDeoVRMovie returns the DeoVR compatible video JSON of the movie,
+// which is also consumed by HereSphere.
+func getDeoVRMovie(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &infoUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		info, err := app.GetMovieInfoByProviderID(uri.Provider, uri.ID, true)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, export.DeoVR(info))
+	}
+}
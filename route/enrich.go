@@ -0,0 +1,41 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	mt "github.com/metatube-community/metatube-sdk-go/provider"
+)
+
+type enrichQuery struct {
+	Fields []string `form:"fields"`
+}
+
+func postEnrich(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &infoUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		query := &enrichQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		fields := make([]mt.Field, 0, len(query.Fields))
+		for _, field := range query.Fields {
+			fields = append(fields, mt.Field(field))
+		}
+		info, err := app.EnrichMovieInfo(uri.Provider, uri.ID, fields...)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: info})
+	}
+}
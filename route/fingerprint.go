@@ -0,0 +1,49 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+func postFingerprintMatch(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		fp := &engine.Fingerprints{}
+		if err := c.ShouldBindJSON(fp); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		results, err := app.MatchMovieFingerprints(fp)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: results})
+	}
+}
+
+func postFingerprints(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &infoUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+		fp := &engine.Fingerprints{}
+		if err := c.ShouldBindJSON(fp); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := app.SubmitMovieFingerprints(uri.Provider, uri.ID, fp); err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: fp})
+	}
+}
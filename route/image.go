@@ -3,7 +3,6 @@ package route
 import (
 	"bytes"
 	"image"
-	"image/jpeg"
 	"net/http"
 	"strconv"
 
@@ -12,6 +11,7 @@ import (
 
 	R "github.com/metatube-community/metatube-sdk-go/constant"
 	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/imageutil"
 	"github.com/metatube-community/metatube-sdk-go/imageutil/badge"
 	mt "github.com/metatube-community/metatube-sdk-go/provider"
 )
@@ -22,6 +22,7 @@ const (
 	primaryImageType imageType = iota
 	thumbImageType
 	backdropImageType
+	screenshotImageType
 )
 
 type imageUri struct {
@@ -33,8 +34,11 @@ type imageQuery struct {
 	Ratio    float64 `form:"ratio"`
 	Position float64 `form:"pos"`
 	Auto     bool    `form:"auto"`
+	Safe     bool    `form:"safe"`
 	Badge    string  `form:"badge"`
 	Quality  int     `form:"quality"`
+	Format   string  `form:"format"`
+	Index    int     `form:"index"`
 }
 
 func getImage(app *engine.Engine, typ imageType) gin.HandlerFunc {
@@ -44,7 +48,7 @@ func getImage(app *engine.Engine, typ imageType) gin.HandlerFunc {
 		ratio = R.PrimaryImageRatio
 	case thumbImageType:
 		ratio = R.ThumbImageRatio
-	case backdropImageType:
+	case backdropImageType, screenshotImageType:
 		ratio = R.BackdropImageRatio
 	default:
 		panic("invalid image type")
@@ -59,13 +63,20 @@ func getImage(app *engine.Engine, typ imageType) gin.HandlerFunc {
 		query := &imageQuery{
 			Ratio:    -1,
 			Position: -1,
-			Quality:  90,
+			Quality:  imageutil.DefaultQuality,
+			Index:    1, // the first screenshot by default.
 		}
 		if err := c.ShouldBindQuery(query); err != nil {
 			abortWithStatusMessage(c, http.StatusBadRequest, err)
 			return
 		}
 
+		format, err := imageutil.ParseFormat(query.Format)
+		if err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
 		var isActorProvider bool
 		switch {
 		case app.IsActorProvider(uri.Provider):
@@ -77,10 +88,7 @@ func getImage(app *engine.Engine, typ imageType) gin.HandlerFunc {
 			return
 		}
 
-		var (
-			img image.Image
-			err error
-		)
+		var img image.Image
 		if query.URL != "" /* specified URL */ {
 			var provider mt.Provider
 			if isActorProvider {
@@ -92,23 +100,33 @@ func getImage(app *engine.Engine, typ imageType) gin.HandlerFunc {
 			if typ != primaryImageType || query.Ratio < 0 {
 				query.Ratio = ratio
 			}
-			img, err = app.GetImageByURL(provider, query.URL, query.Ratio, query.Position, query.Auto)
+			if query.Safe && typ == primaryImageType {
+				img, err = app.GetSafeImageByURL(provider, query.URL, query.Ratio)
+			} else {
+				img, err = app.GetImageByURL(provider, query.URL, query.Ratio, query.Position, query.Auto)
+			}
 		} else if isActorProvider /* actor */ {
 			switch typ {
 			case primaryImageType:
 				img, err = app.GetActorPrimaryImage(uri.Provider, uri.ID)
-			case thumbImageType, backdropImageType:
+			case thumbImageType, backdropImageType, screenshotImageType:
 				abortWithStatusMessage(c, http.StatusBadRequest, "unsupported image type")
 				return
 			}
 		} else /* movie */ {
 			switch typ {
 			case primaryImageType:
+				if query.Safe {
+					img, err = app.GetMovieSafePrimaryImage(uri.Provider, uri.ID, query.Ratio)
+					break
+				}
 				img, err = app.GetMoviePrimaryImage(uri.Provider, uri.ID, query.Ratio, query.Position)
 			case thumbImageType:
 				img, err = app.GetMovieThumbImage(uri.Provider, uri.ID)
 			case backdropImageType:
 				img, err = app.GetMovieBackdropImage(uri.Provider, uri.ID)
+			case screenshotImageType:
+				img, err = app.GetMovieScreenshotImage(uri.Provider, uri.ID, query.Index)
 			}
 		}
 		if err != nil {
@@ -127,12 +145,12 @@ func getImage(app *engine.Engine, typ imageType) gin.HandlerFunc {
 		c.Header("X-MetaTube-Image-Height", strconv.Itoa(img.Bounds().Dy()))
 
 		buf := &bytes.Buffer{}
-		if err = jpeg.Encode(buf, img, &jpeg.Options{Quality: query.Quality}); err != nil {
+		if err = imageutil.Encode(buf, img, format, query.Quality); err != nil {
 			panic(err)
 		}
 
 		c.Render(http.StatusOK, render.Reader{
-			ContentType:   jpegImageMIMEType,
+			ContentType:   format.MIMEType(),
 			ContentLength: int64(buf.Len()),
 			Reader:        buf,
 			Headers: map[string]string{
@@ -143,4 +161,20 @@ func getImage(app *engine.Engine, typ imageType) gin.HandlerFunc {
 	}
 }
 
-const jpegImageMIMEType = "image/jpeg"
+func getArtworks(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &infoUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		artworks, err := app.GetMovieArtworks(uri.Provider, uri.ID)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: artworks})
+	}
+}
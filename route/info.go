@@ -21,7 +21,8 @@ type infoUri struct {
 }
 
 type infoQuery struct {
-	Lazy bool `form:"lazy"`
+	Lazy bool   `form:"lazy"`
+	Lang string `form:"lang"`
 }
 
 func getInfo(app *engine.Engine, typ infoType) gin.HandlerFunc {
@@ -45,9 +46,9 @@ func getInfo(app *engine.Engine, typ infoType) gin.HandlerFunc {
 		)
 		switch typ {
 		case actorInfoType:
-			info, err = app.GetActorInfoByProviderID(uri.Provider, uri.ID, query.Lazy)
+			info, err = auditor(app, c).GetLocalizedActorInfoByProviderID(uri.Provider, uri.ID, query.Lang, query.Lazy)
 		case movieInfoType:
-			info, err = app.GetMovieInfoByProviderID(uri.Provider, uri.ID, query.Lazy)
+			info, err = auditor(app, c).GetLocalizedMovieInfoByProviderID(uri.Provider, uri.ID, query.Lang, query.Lazy)
 		default:
 			panic("invalid info/metadata type")
 		}
@@ -59,3 +60,51 @@ func getInfo(app *engine.Engine, typ infoType) gin.HandlerFunc {
 		c.JSON(http.StatusOK, &responseMessage{Data: info})
 	}
 }
+
+func deleteInfo(app *engine.Engine, typ infoType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &infoUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		var err error
+		switch typ {
+		case actorInfoType:
+			err = auditor(app, c).DeleteActorInfo(uri.Provider, uri.ID)
+		case movieInfoType:
+			err = auditor(app, c).DeleteMovieInfo(uri.Provider, uri.ID)
+		default:
+			panic("invalid info/metadata type")
+		}
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: uri})
+	}
+}
+
+type actorImagesQuery struct {
+	Name string `form:"name" binding:"required"`
+}
+
+func getActorImages(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &actorImagesQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		images, err := app.GetActorImages(query.Name)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: images})
+	}
+}
@@ -0,0 +1,149 @@
+package route
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/export"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// Jellyfin compatible endpoints return bare payloads without the
+// responseMessage wrapper, which can be consumed by plugins directly.
+
+type jellyfinSearchQuery struct {
+	Q        string `form:"q" binding:"required"`
+	Provider string `form:"provider"`
+}
+
+func getJellyfinSearch(app *engine.Engine, typ searchType) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &jellyfinSearchQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		results := make([]*export.JellyfinRemoteSearchResult, 0)
+		switch typ {
+		case actorSearchType:
+			var (
+				actors []*model.ActorSearchResult
+				err    error
+			)
+			if query.Provider != "" {
+				actors, err = app.SearchActor(query.Q, query.Provider, true)
+			} else {
+				actors, err = app.SearchActorAll(query.Q, true)
+			}
+			if err != nil {
+				abortWithError(c, err)
+				return
+			}
+			for _, actor := range actors {
+				results = append(results, export.JellyfinActorSearchResult(actor))
+			}
+		case movieSearchType:
+			var (
+				movies []*model.MovieSearchResult
+				err    error
+			)
+			if query.Provider != "" {
+				movies, err = app.SearchMovie(query.Q, query.Provider, true)
+			} else {
+				movies, err = app.SearchMovieAll(query.Q, true)
+			}
+			if err != nil {
+				abortWithError(c, err)
+				return
+			}
+			for _, movie := range movies {
+				results = append(results, export.JellyfinMovieSearchResult(movie))
+			}
+		default:
+			panic("invalid search type")
+		}
+
+		c.JSON(http.StatusOK, results)
+	}
+}
+
+func getJellyfinMovie(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &infoUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		info, err := app.GetMovieInfoByProviderID(uri.Provider, uri.ID, true)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, export.Jellyfin(info, export.WithRatings(app.ContentRatings())))
+	}
+}
+
+func getJellyfinMovieImages(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &infoUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		// make sure the movie exists.
+		info, err := app.GetMovieInfoByProviderID(uri.Provider, uri.ID, true)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		images := make([]*export.JellyfinRemoteImageInfo, 0, 3+len(info.PreviewImages))
+		for _, image := range []struct {
+			typ, path string
+		}{
+			{"Primary", "primary"},
+			{"Thumb", "thumb"},
+			{"Backdrop", "backdrop"},
+		} {
+			images = append(images, &export.JellyfinRemoteImageInfo{
+				ProviderName: export.JellyfinProviderName,
+				URL: fmt.Sprintf("%s/v1/images/%s/%s/%s", requestBaseURL(c), image.path,
+					url.PathEscape(uri.Provider), url.PathEscape(uri.ID)),
+				Type: image.typ,
+			})
+		}
+		for _, artwork := range info.Artworks() {
+			if artwork.Type != model.ScreenshotArtwork {
+				continue
+			}
+			images = append(images, &export.JellyfinRemoteImageInfo{
+				ProviderName: export.JellyfinProviderName,
+				URL: fmt.Sprintf("%s/v1/images/screenshot/%s/%s?index=%d", requestBaseURL(c),
+					url.PathEscape(uri.Provider), url.PathEscape(uri.ID), artwork.Index),
+				Type: "Screenshot",
+			})
+		}
+
+		c.JSON(http.StatusOK, images)
+	}
+}
+
+// requestBaseURL returns the base URL of the server seen by the client.
+func requestBaseURL(c *gin.Context) string {
+	scheme := "http"
+	if c.Request.TLS != nil {
+		scheme = "https"
+	}
+	if proto := c.GetHeader("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return scheme + "://" + c.Request.Host
+}
@@ -0,0 +1,67 @@
+package route
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/metatube-community/metatube-sdk-go/export"
+)
+
+func TestJellyfinMovie(t *testing.T) {
+	r := newTestRouter(t)
+
+	var item export.JellyfinItem
+	if assert.Equal(t, http.StatusOK, serveJSON(t, r, "/v1/jellyfin/movies/FAKE/FAKE-001", &item)) {
+		assert.Equal(t, "Movie", item.Type)
+		assert.True(t, strings.HasPrefix(item.Name, "FAKE-001 "))
+		assert.Equal(t, "Fake:FAKE-001", item.ProviderIds[export.JellyfinProviderName])
+		assert.NotEmpty(t, item.People)
+	}
+	assert.Equal(t, http.StatusNotFound, serveJSON(t, r, "/v1/jellyfin/movies/FAKE/FAKE-0000", nil))
+	assert.Equal(t, http.StatusNotFound, serveJSON(t, r, "/v1/jellyfin/movies/UNKNOWN/FAKE-001", nil))
+
+	// authentication is required.
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/v1/jellyfin/movies/FAKE/FAKE-001", nil))
+	assert.Equal(t, http.StatusUnauthorized, w.Code)
+}
+
+func TestJellyfinMovieImages(t *testing.T) {
+	r := newTestRouter(t)
+
+	var images []*export.JellyfinRemoteImageInfo
+	if assert.Equal(t, http.StatusOK, serveJSON(t, r, "/v1/jellyfin/movies/FAKE/FAKE-001/images", &images)) {
+		var types []string
+		for _, image := range images {
+			types = append(types, image.Type)
+			assert.Equal(t, export.JellyfinProviderName, image.ProviderName)
+		}
+		assert.Equal(t, []string{"Primary", "Thumb", "Backdrop", "Screenshot", "Screenshot"}, types)
+		assert.Equal(t, "http://example.com/v1/images/primary/FAKE/FAKE-001", images[0].URL)
+		assert.Equal(t, "http://example.com/v1/images/screenshot/FAKE/FAKE-001?index=2", images[4].URL)
+	}
+	assert.Equal(t, http.StatusNotFound, serveJSON(t, r, "/v1/jellyfin/movies/FAKE/FAKE-0000/images", nil))
+}
+
+func TestJellyfinSearch(t *testing.T) {
+	r := newTestRouter(t)
+
+	var results []*export.JellyfinRemoteSearchResult
+	if assert.Equal(t, http.StatusOK, serveJSON(t, r, "/v1/jellyfin/movies/search?q=FAKE-001&provider=FAKE", &results)) &&
+		assert.NotEmpty(t, results) {
+		assert.Equal(t, "Fake:FAKE-001", results[0].ProviderIds[export.JellyfinProviderName])
+		assert.Equal(t, export.JellyfinProviderName, results[0].SearchProviderName)
+	}
+
+	results = nil
+	if assert.Equal(t, http.StatusOK, serveJSON(t, r, "/v1/jellyfin/actors/search?q=Fake+Actor+1&provider=FAKE", &results)) &&
+		assert.Len(t, results, 1) {
+		assert.Equal(t, "Fake Actor 1", results[0].Name)
+	}
+
+	assert.Equal(t, http.StatusBadRequest, serveJSON(t, r, "/v1/jellyfin/movies/search?provider=FAKE", nil))
+}
@@ -0,0 +1,31 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+type numberUri struct {
+	Number string `uri:"number" binding:"required"`
+}
+
+func getMovieByNumber(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &numberUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		match, err := app.GetMovieInfoByNumber(c.Request.Context(), uri.Number)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: match})
+	}
+}
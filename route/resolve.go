@@ -0,0 +1,34 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+type resolveQuery struct {
+	URL  string `form:"url" binding:"required"`
+	Lazy bool   `form:"lazy"`
+}
+
+func getResolve(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &resolveQuery{
+			Lazy: true, // enable lazy by default.
+		}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		r, err := auditor(app, c).Resolve(query.URL, query.Lazy)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: r})
+	}
+}
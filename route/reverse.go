@@ -0,0 +1,97 @@
+package route
+
+import (
+	"bytes"
+	goerr "errors"
+	"image"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+// Max size of uploaded images.
+const maxUploadImageSize = 16 << 20
+
+type reverseSearchQuery struct {
+	Limit int `form:"limit"`
+}
+
+func postReverseSearch(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &reverseSearchQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		// accept either multipart form or raw image body, both are capped
+		// by the size of the whole request body.
+		c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadImageSize)
+		var r io.Reader = c.Request.Body
+		if strings.HasPrefix(c.ContentType(), "multipart/") {
+			file, err := c.FormFile("image")
+			if err != nil {
+				abortWithUploadError(c, err)
+				return
+			}
+			f, err := file.Open()
+			if err != nil {
+				abortWithStatusMessage(c, http.StatusBadRequest, err)
+				return
+			}
+			defer f.Close()
+			r = f
+		}
+		data, err := io.ReadAll(r)
+		if err != nil {
+			abortWithUploadError(c, err)
+			return
+		}
+		img, _, err := image.Decode(bytes.NewReader(data))
+		if err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		results, err := app.ReverseSearchMovieImage(img, query.Limit)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: results})
+	}
+}
+
+func postIndexImages(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &infoUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		n, err := app.IndexMovieImages(uri.Provider, uri.ID)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: gin.H{"indexed": n}})
+	}
+}
+
+// abortWithUploadError aborts with status 413 if the upload is too large,
+// or with status 400 otherwise.
+func abortWithUploadError(c *gin.Context, err error) {
+	code := http.StatusBadRequest
+	var e *http.MaxBytesError
+	if goerr.As(err, &e) {
+		code = http.StatusRequestEntityTooLarge
+	}
+	abortWithStatusMessage(c, code, err)
+}
@@ -0,0 +1,106 @@
+package route
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/metatube-community/metatube-sdk-go/provider/fake"
+)
+
+func TestReverseSearch(t *testing.T) {
+	r := newTestRouter(t)
+
+	w := serve(r, httptest.NewRequest(http.MethodPost, "/v1/movies/FAKE/FAKE-001/images/index", nil))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.JSONEq(t, `{"data":{"indexed":3}}`, w.Body.String())
+
+	// the cover image of the indexed movie.
+	f := fake.New()
+	info, err := f.GetMovieInfoByID("FAKE-001")
+	require.NoError(t, err)
+	resp, err := f.Fetch(info.CoverURL)
+	require.NoError(t, err)
+	cover, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+
+	multipartBody := func(field string, data []byte) (*bytes.Buffer, string) {
+		body := &bytes.Buffer{}
+		mw := multipart.NewWriter(body)
+		fw, err := mw.CreateFormFile(field, "frame.jpg")
+		require.NoError(t, err)
+		_, err = fw.Write(data)
+		require.NoError(t, err)
+		require.NoError(t, mw.Close())
+		return body, mw.FormDataContentType()
+	}
+
+	tooLarge := make([]byte, maxUploadImageSize+1)
+	for _, unit := range []struct {
+		name        string
+		body        io.Reader
+		contentType string
+		code        int
+	}{
+		{"raw", bytes.NewReader(cover), "image/jpeg", http.StatusOK},
+		{"invalid raw", bytes.NewReader([]byte("not an image")), "image/jpeg", http.StatusBadRequest},
+		{"raw too large", bytes.NewReader(tooLarge), "image/jpeg", http.StatusRequestEntityTooLarge},
+	} {
+		t.Run(unit.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/v1/movies/reverse-search?limit=1", unit.body)
+			req.Header.Set("Content-Type", unit.contentType)
+			w := serve(r, req)
+			require.Equal(t, unit.code, w.Code, w.Body.String())
+			if unit.code == http.StatusOK {
+				assertReverseSearchResult(t, w.Body.Bytes())
+			}
+		})
+	}
+
+	for _, unit := range []struct {
+		name  string
+		field string
+		data  []byte
+		code  int
+	}{
+		{"multipart", "image", cover, http.StatusOK},
+		{"multipart without image", "file", cover, http.StatusBadRequest},
+		{"multipart too large", "image", tooLarge, http.StatusRequestEntityTooLarge},
+	} {
+		t.Run(unit.name, func(t *testing.T) {
+			body, contentType := multipartBody(unit.field, unit.data)
+			req := httptest.NewRequest(http.MethodPost, "/v1/movies/reverse-search", body)
+			req.Header.Set("Content-Type", contentType)
+			w := serve(r, req)
+			require.Equal(t, unit.code, w.Code, w.Body.String())
+			if unit.code == http.StatusOK {
+				assertReverseSearchResult(t, w.Body.Bytes())
+			}
+		})
+	}
+}
+
+func assertReverseSearchResult(t *testing.T, data []byte) {
+	var resp struct {
+		Data []struct {
+			ID       string `json:"id"`
+			Provider string `json:"provider"`
+			Title    string `json:"title"`
+			Distance int    `json:"distance"`
+		} `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(data, &resp))
+	if assert.Len(t, resp.Data, 1) {
+		assert.Equal(t, "FAKE-001", resp.Data[0].ID)
+		assert.Equal(t, fake.Name, resp.Data[0].Provider)
+		assert.NotEmpty(t, resp.Data[0].Title)
+		assert.Zero(t, resp.Data[0].Distance)
+	}
+}
@@ -7,17 +7,20 @@ import (
 
 	"github.com/gin-gonic/gin"
 
+	"github.com/metatube-community/metatube-sdk-go/common/redact"
 	"github.com/metatube-community/metatube-sdk-go/engine"
 	"github.com/metatube-community/metatube-sdk-go/errors"
 	V "github.com/metatube-community/metatube-sdk-go/internal/version"
+	"github.com/metatube-community/metatube-sdk-go/model"
 	"github.com/metatube-community/metatube-sdk-go/route/auth"
+	"github.com/metatube-community/metatube-sdk-go/route/stashbox"
 )
 
 func New(app *engine.Engine, v auth.Validator) *gin.Engine {
 	r := gin.New()
 	{
 		// register middleware
-		r.Use(logger(), recovery())
+		r.Use(logger(app.Redactor()), recovery(), compression(), schemaVersion())
 		// fallback behavior
 		r.NoRoute(notFound())
 		r.NoMethod(notAllowed())
@@ -35,11 +38,14 @@ func New(app *engine.Engine, v auth.Validator) *gin.Engine {
 
 		public.GET("/providers", getProviders(app))
 
+		public.GET("/schema", getSchema())
+
 		images := public.Group("/images")
 		{
 			images.GET("/primary/:provider/:id", getImage(app, primaryImageType))
 			images.GET("/thumb/:provider/:id", getImage(app, thumbImageType))
 			images.GET("/backdrop/:provider/:id", getImage(app, backdropImageType))
+			images.GET("/screenshot/:provider/:id", getImage(app, screenshotImageType))
 		}
 	}
 
@@ -48,26 +54,125 @@ func New(app *engine.Engine, v auth.Validator) *gin.Engine {
 		actors := private.Group("/actors")
 		{
 			actors.GET("/:provider/:id", getInfo(app, actorInfoType))
+			actors.DELETE("/:provider/:id", deleteInfo(app, actorInfoType))
+			actors.GET("/:provider/:id/override", getOverride(app, model.ActorKind))
+			actors.PUT("/:provider/:id/override", putOverride(app, model.ActorKind))
+			actors.DELETE("/:provider/:id/override", deleteOverride(app, model.ActorKind))
 			actors.GET("/search", getSearch(app, actorSearchType))
+			actors.GET("/images", getActorImages(app))
 		}
 
 		movies := private.Group("/movies")
 		{
 			movies.GET("/:provider/:id", getInfo(app, movieInfoType))
+			movies.DELETE("/:provider/:id", deleteInfo(app, movieInfoType))
+			movies.GET("/:provider/:id/override", getOverride(app, model.MovieKind))
+			movies.PUT("/:provider/:id/override", putOverride(app, model.MovieKind))
+			movies.DELETE("/:provider/:id/override", deleteOverride(app, model.MovieKind))
 			movies.GET("/search", getSearch(app, movieSearchType))
+			movies.GET("/compare", getCompare(app))
+			movies.GET("/number/:number", getMovieByNumber(app))
+			movies.POST("/reverse-search", postReverseSearch(app))
+			movies.POST("/:provider/:id/images/index", postIndexImages(app))
+			movies.GET("/:provider/:id/artworks", getArtworks(app))
+			movies.GET("/:provider/:id/trailer", getTrailer(app))
+			movies.POST("/:provider/:id/enrich", postEnrich(app))
 		}
 
 		reviews := private.Group("/reviews")
 		{
 			reviews.GET("/:provider/:id", getReview(app))
 		}
+
+		private.GET("/providers/throttle", getThrottleStats(app))
+		private.GET("/providers/bandwidth", getBandwidthUsage(app))
+		private.GET("/providers/priorities", getEffectivePriorities(app))
+
+		fingerprints := private.Group("/fingerprints")
+		{
+			fingerprints.POST("/match", postFingerprintMatch(app))
+			fingerprints.POST("/:provider/:id", postFingerprints(app))
+		}
+
+		blocklist := private.Group("/blocklist")
+		{
+			blocklist.GET("", getBlocklist(app))
+			blocklist.POST("", postBlocklist(app))
+			blocklist.DELETE("", deleteBlocklist(app))
+		}
+
+		series := private.Group("/series")
+		{
+			series.GET("", getSeries(app))
+			series.POST("", postSeries(app))
+			series.DELETE("", deleteSeries(app))
+			series.GET("/status", getSeriesStatus(app))
+		}
+
+		watchlist := private.Group("/watchlist")
+		{
+			watchlist.GET("", getWatchlist(app))
+			watchlist.POST("", postWatchlist(app))
+			watchlist.DELETE("", deleteWatchlist(app))
+		}
+
+		private.GET("/stats", getStats(app))
+		private.GET("/audit", getAuditLogs(app))
+		private.GET("/resolve", getResolve(app))
+
+		jellyfin := private.Group("/jellyfin")
+		{
+			jellyfin.GET("/actors/search", getJellyfinSearch(app, actorSearchType))
+			jellyfin.GET("/movies/search", getJellyfinSearch(app, movieSearchType))
+			jellyfin.GET("/movies/:provider/:id", getJellyfinMovie(app))
+			jellyfin.GET("/movies/:provider/:id/images", getJellyfinMovieImages(app))
+		}
+
+		deovr := private.Group("/deovr")
+		{
+			deovr.GET("/movies/:provider/:id", getDeoVRMovie(app))
+		}
 	}
 
+	// stash-box compatible GraphQL endpoint.
+	r.POST("/v1/stashbox/graphql", apiKeyAuthentication(v), gin.WrapH(stashbox.NewHandler(app)))
+
 	return r
 }
 
-func logger() gin.HandlerFunc {
-	return gin.LoggerWithConfig(gin.LoggerConfig{})
+const routePathKey = "route.path"
+
+// logger logs requests with route templates and hashed query values in
+// place of the request paths if the redactor is enabled.
+func logger(redactor *redact.Redactor) gin.HandlerFunc {
+	if !redactor.Enabled() {
+		return gin.LoggerWithConfig(gin.LoggerConfig{})
+	}
+	handler := gin.LoggerWithConfig(gin.LoggerConfig{
+		Formatter: func(param gin.LogFormatterParams) string {
+			path, _ := param.Keys[routePathKey].(string)
+			if path == "" /* no route matched */ {
+				path = redactor.URL(param.Request.URL.Path)
+			}
+			if query := redactor.Query(param.Request.URL.RawQuery); query != "" {
+				path += "?" + query
+			}
+			param.Path = path
+			return fmt.Sprintf("[GIN] %v | %3d | %13v | %15s | %-7s %#v\n%s",
+				param.TimeStamp.Format("2006/01/02 - 15:04:05"),
+				param.StatusCode,
+				param.Latency,
+				param.ClientIP,
+				param.Method,
+				param.Path,
+				param.ErrorMessage,
+			)
+		},
+	})
+	return func(c *gin.Context) {
+		c.Set(routePathKey, c.FullPath())
+		handler(c)
+	}
 }
 
 func recovery() gin.HandlerFunc {
@@ -94,9 +199,10 @@ func getIndex() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		c.JSON(http.StatusOK, &responseMessage{
 			Data: gin.H{
-				"app":     "metatube",
-				"commit":  V.GitCommit,
-				"version": V.Version,
+				"app":            "metatube",
+				"commit":         V.GitCommit,
+				"version":        V.Version,
+				"schema_version": model.SchemaVersion,
 			},
 		})
 	}
@@ -121,6 +227,29 @@ func getProviders(app *engine.Engine) gin.HandlerFunc {
 	}
 }
 
+func getThrottleStats(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, &responseMessage{Data: app.GetThrottleStats()})
+	}
+}
+
+func getEffectivePriorities(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, &responseMessage{Data: app.GetEffectivePriorities()})
+	}
+}
+
+func getBandwidthUsage(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		usage := app.BandwidthUsage()
+		if usage == nil {
+			abortWithStatusMessage(c, http.StatusNotFound, "bandwidth accounting disabled")
+			return
+		}
+		c.JSON(http.StatusOK, &responseMessage{Data: usage})
+	}
+}
+
 func abortWithError(c *gin.Context, err error) {
 	var e *errors.HTTPError
 	if goerr.As(err, &e) {
@@ -0,0 +1,53 @@
+package route
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+	gormlogger "gorm.io/gorm/logger"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	_ "github.com/metatube-community/metatube-sdk-go/provider/fake"
+	"github.com/metatube-community/metatube-sdk-go/route/auth"
+)
+
+const testToken = "token"
+
+// newTestRouter returns the router of an engine of a temporary DB, only
+// requests of the fake provider are served without network access.
+func newTestRouter(t *testing.T, opts ...engine.Option) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "metatube.db")), &gorm.Config{Logger: gormlogger.Discard})
+	require.NoError(t, err)
+	app := engine.New(db, time.Minute, opts...)
+	require.NoError(t, app.AutoMigrate(true))
+	return New(app, auth.NewTokenStore(testToken))
+}
+
+// serve serves the request authenticated by the test token.
+func serve(r http.Handler, req *http.Request) *httptest.ResponseRecorder {
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// serveJSON serves the GET request, and decodes the response into v.
+func serveJSON(t *testing.T, r http.Handler, target string, v any) int {
+	w := serve(r, httptest.NewRequest(http.MethodGet, target, nil))
+	data, err := io.ReadAll(w.Body)
+	require.NoError(t, err)
+	if v != nil && w.Code == http.StatusOK {
+		require.NoError(t, json.Unmarshal(data, v), string(data))
+	}
+	return w.Code
+}
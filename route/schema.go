@@ -0,0 +1,35 @@
+package route
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// schemaVersionHeader is the version of models of responses, see
+// model.SchemaVersion.
+const schemaVersionHeader = "X-Metatube-Schema-Version"
+
+func schemaVersion() gin.HandlerFunc {
+	version := strconv.Itoa(model.SchemaVersion)
+	return func(c *gin.Context) {
+		c.Header(schemaVersionHeader, version)
+		c.Next()
+	}
+}
+
+// getSchema serves the JSON schema of models as is, rather than wrapped
+// in the data field, so that it is referred to by validators directly.
+func getSchema() gin.HandlerFunc {
+	schema, err := model.JSONSchema()
+	return func(c *gin.Context) {
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+		c.Data(http.StatusOK, "application/schema+json", schema)
+	}
+}
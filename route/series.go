@@ -0,0 +1,79 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+type seriesQuery struct {
+	Provider string `form:"provider" binding:"required"`
+	Name     string `form:"name" binding:"required"`
+}
+
+func getSeries(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		series, err := app.GetTrackedSeries()
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: series})
+	}
+}
+
+func postSeries(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		series := &model.Series{}
+		if err := c.ShouldBindJSON(series); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := app.TrackSeries(series); err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: series})
+	}
+}
+
+func deleteSeries(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &seriesQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := app.UntrackSeries(query.Provider, query.Name); err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: query})
+	}
+}
+
+func getSeriesStatus(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &seriesQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		status, err := app.GetSeriesStatus(query.Provider, query.Name)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: status})
+	}
+}
@@ -0,0 +1,153 @@
+// Package stashbox implements a stash-box compatible GraphQL endpoint,
+// which lets Stash use the engine as a metadata source. Only the subset
+// of the schema used for scraping is implemented, and fingerprints are
+// matched against the ones submitted to the engine, MD5 is not supported.
+package stashbox
+
+import (
+	_ "embed"
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/graph-gophers/graphql-go/relay"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+//go:embed schema.graphql
+var schema string
+
+// Default max number of search results.
+const defaultSearchLimit = 20
+
+// NewHandler returns the GraphQL handler backed by the engine.
+func NewHandler(app *engine.Engine) http.Handler {
+	return &relay.Handler{
+		Schema: graphql.MustParseSchema(schema, &resolver{app: app},
+			graphql.UseFieldResolvers()),
+	}
+}
+
+type resolver struct {
+	app *engine.Engine
+}
+
+func (r *resolver) FindScene(args struct{ ID graphql.ID }) (*Scene, error) {
+	provider, id, ok := parseID(args.ID)
+	if !ok {
+		return nil, nil
+	}
+	info, err := r.app.GetMovieInfoByProviderID(provider, id, true)
+	if err != nil {
+		return nil, err
+	}
+	return newScene(info), nil
+}
+
+func (r *resolver) FindPerformer(args struct{ ID graphql.ID }) (*Performer, error) {
+	provider, id, ok := parseID(args.ID)
+	if !ok /* performers of scenes are known by names only */ {
+		performers, err := r.SearchPerformer(searchArgs{Term: string(args.ID)})
+		if err != nil || len(performers) == 0 {
+			return nil, err
+		}
+		provider, id, _ = parseID(performers[0].ID)
+	}
+	info, err := r.app.GetActorInfoByProviderID(provider, id, true)
+	if err != nil {
+		return nil, err
+	}
+	return newPerformer(info), nil
+}
+
+type searchArgs struct {
+	Term  string
+	Limit *int32
+}
+
+func (args searchArgs) limit() int {
+	if args.Limit == nil || *args.Limit <= 0 {
+		return defaultSearchLimit
+	}
+	return int(*args.Limit)
+}
+
+func (r *resolver) SearchScene(args searchArgs) ([]*Scene, error) {
+	results, err := r.app.SearchMovieAll(args.Term, true)
+	if err != nil {
+		return nil, err
+	}
+	scenes := make([]*Scene, 0, len(results))
+	for _, result := range results {
+		if len(scenes) >= args.limit() {
+			break
+		}
+		scenes = append(scenes, newSceneFromSearchResult(result))
+	}
+	return scenes, nil
+}
+
+func (r *resolver) SearchPerformer(args searchArgs) ([]*Performer, error) {
+	results, err := r.app.SearchActorAll(args.Term, true)
+	if err != nil {
+		return nil, err
+	}
+	performers := make([]*Performer, 0, len(results))
+	for _, result := range results {
+		if len(performers) >= args.limit() {
+			break
+		}
+		performers = append(performers, newPerformerFromSearchResult(result))
+	}
+	return performers, nil
+}
+
+type fingerprintQueryInput struct {
+	Hash      string
+	Algorithm string
+}
+
+func (r *resolver) matchFingerprints(inputs []*fingerprintQueryInput) ([]*Scene, error) {
+	fp := &engine.Fingerprints{}
+	for _, input := range inputs {
+		switch input.Algorithm {
+		case model.OSHashAlgorithm:
+			fp.OSHash = input.Hash
+		case model.PHashAlgorithm:
+			fp.PHash = input.Hash
+		}
+	}
+	scenes := []*Scene{}
+	if fp.OSHash == "" && fp.PHash == "" {
+		return scenes, nil // MD5 is not supported.
+	}
+	matches, err := r.app.MatchMovieFingerprints(fp)
+	if err != nil {
+		return nil, err
+	}
+	for _, m := range matches {
+		scenes = append(scenes, newSceneFromSearchResult(m.MovieSearchResult))
+	}
+	return scenes, nil
+}
+
+func (r *resolver) FindScenesByFullFingerprints(args struct {
+	Fingerprints []*fingerprintQueryInput
+}) ([]*Scene, error) {
+	return r.matchFingerprints(args.Fingerprints)
+}
+
+func (r *resolver) FindScenesBySceneFingerprints(args struct {
+	Fingerprints [][]*fingerprintQueryInput
+}) ([][]*Scene, error) {
+	results := make([][]*Scene, len(args.Fingerprints))
+	for i, inputs := range args.Fingerprints {
+		scenes, err := r.matchFingerprints(inputs)
+		if err != nil {
+			return nil, err
+		}
+		results[i] = scenes
+	}
+	return results, nil
+}
@@ -0,0 +1,22 @@
+package stashbox
+
+import (
+	"testing"
+
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchema(t *testing.T) {
+	_, err := graphql.ParseSchema(schema, &resolver{}, graphql.UseFieldResolvers())
+	assert.NoError(t, err)
+}
+
+func TestParseID(t *testing.T) {
+	provider, id, ok := parseID(formatID("FANZA", "abc00123"))
+	assert.True(t, ok)
+	assert.Equal(t, "FANZA", provider)
+	assert.Equal(t, "abc00123", id)
+	_, _, ok = parseID("Actor Name")
+	assert.False(t, ok)
+}
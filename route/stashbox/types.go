@@ -0,0 +1,253 @@
+package stashbox
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/graph-gophers/graphql-go"
+	"gorm.io/datatypes"
+
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+// Field names are matched with the schema case-insensitively, with
+// underscores ignored, e.g., `ReleaseDate` resolves `release_date`.
+
+type Fingerprint struct {
+	Hash      string
+	Algorithm string
+	Duration  int32
+}
+
+type Site struct {
+	ID   graphql.ID
+	Name string
+}
+
+type URL struct {
+	URL  string
+	Type string
+	Site *Site
+}
+
+type Image struct {
+	ID     graphql.ID
+	URL    string
+	Width  int32
+	Height int32
+}
+
+type Studio struct {
+	ID     graphql.ID
+	Name   string
+	URLs   []*URL
+	Images []*Image
+	Parent *Studio
+}
+
+type Tag struct {
+	ID          graphql.ID
+	Name        string
+	Description *string
+}
+
+type PerformerAppearance struct {
+	Performer *Performer
+	As        *string
+}
+
+type Scene struct {
+	ID           graphql.ID
+	Title        *string
+	Details      *string
+	Date         *string
+	ReleaseDate  *string
+	Code         *string
+	Director     *string
+	Duration     *int32
+	URLs         []*URL
+	Images       []*Image
+	Studio       *Studio
+	Tags         []*Tag
+	Performers   []*PerformerAppearance
+	Fingerprints []*Fingerprint
+}
+
+type Measurements struct {
+	CupSize  *string
+	BandSize *int32
+	Waist    *int32
+	Hip      *int32
+}
+
+type Performer struct {
+	ID              graphql.ID
+	Name            string
+	Disambiguation  *string
+	Aliases         []string
+	Gender          *string
+	URLs            []*URL
+	Images          []*Image
+	BirthDate       *string
+	Height          *int32
+	Measurements    *Measurements
+	Country         *string
+	CareerStartYear *int32
+}
+
+// formatID formats global ID in the form of `provider:id`.
+func formatID(provider, id string) graphql.ID {
+	return graphql.ID(provider + ":" + id)
+}
+
+// parseID parses global ID formatted by formatID.
+func parseID(id graphql.ID) (provider, pid string, ok bool) {
+	return strings.Cut(string(id), ":")
+}
+
+func newURL(provider, rawURL string) *URL {
+	return &URL{
+		URL:  rawURL,
+		Type: "HOME",
+		Site: &Site{ID: graphql.ID(provider), Name: provider},
+	}
+}
+
+func newImages(urls ...string) []*Image {
+	images := make([]*Image, 0, len(urls))
+	for _, url := range urls {
+		if url != "" {
+			images = append(images, &Image{ID: graphql.ID(url), URL: url})
+		}
+	}
+	return images
+}
+
+func newScene(info *model.MovieInfo) *Scene {
+	scene := &Scene{
+		ID:           formatID(info.Provider, info.ID),
+		Title:        optional(info.Title),
+		Details:      optional(info.Summary),
+		Date:         optionalDate(info.ReleaseDate),
+		ReleaseDate:  optionalDate(info.ReleaseDate),
+		Code:         optional(info.Number),
+		Director:     optional(info.Director),
+		URLs:         []*URL{newURL(info.Provider, info.Homepage)},
+		Images:       newImages(info.BigCoverURL, info.CoverURL),
+		Tags:         make([]*Tag, 0, len(info.Genres)),
+		Performers:   make([]*PerformerAppearance, 0, len(info.Actors)),
+		Fingerprints: []*Fingerprint{},
+	}
+	if info.Runtime > 0 {
+		duration := int32(info.Runtime * 60)
+		scene.Duration = &duration
+	}
+	if info.Maker != "" {
+		scene.Studio = &Studio{
+			ID:     graphql.ID(info.Maker),
+			Name:   info.Maker,
+			URLs:   []*URL{},
+			Images: []*Image{},
+		}
+	}
+	for _, genre := range info.Genres {
+		scene.Tags = append(scene.Tags, &Tag{ID: graphql.ID(genre), Name: genre})
+	}
+	for _, actor := range info.Actors {
+		scene.Performers = append(scene.Performers, &PerformerAppearance{
+			// performers of scenes are known by names only.
+			Performer: &Performer{
+				ID:           graphql.ID(actor),
+				Name:         actor,
+				Aliases:      []string{},
+				URLs:         []*URL{},
+				Images:       []*Image{},
+				Measurements: &Measurements{},
+			},
+		})
+	}
+	return scene
+}
+
+func newSceneFromSearchResult(result *model.MovieSearchResult) *Scene {
+	info := &model.MovieInfo{
+		ID:          result.ID,
+		Number:      result.Number,
+		Title:       result.Title,
+		Provider:    result.Provider,
+		Homepage:    result.Homepage,
+		CoverURL:    result.CoverURL,
+		Actors:      result.Actors,
+		ReleaseDate: result.ReleaseDate,
+	}
+	return newScene(info)
+}
+
+var measurementsRegexp = regexp.MustCompile(`(?i)B\s*(\d+).*?W\s*(\d+).*?H\s*(\d+)`)
+
+func newPerformer(info *model.ActorInfo) *Performer {
+	performer := &Performer{
+		ID:           formatID(info.Provider, info.ID),
+		Name:         info.Name,
+		Aliases:      info.Aliases,
+		Gender:       optional("FEMALE"),
+		URLs:         []*URL{newURL(info.Provider, info.Homepage)},
+		Images:       newImages(info.Images...),
+		BirthDate:    optionalDate(info.Birthday),
+		Country:      optional(info.Nationality),
+		Measurements: &Measurements{CupSize: optional(info.CupSize)},
+	}
+	if performer.Aliases == nil {
+		performer.Aliases = []string{}
+	}
+	if info.Height > 0 {
+		height := int32(info.Height)
+		performer.Height = &height
+	}
+	if ss := measurementsRegexp.FindStringSubmatch(info.Measurements); len(ss) == 4 {
+		performer.Measurements.BandSize = optionalInt(ss[1])
+		performer.Measurements.Waist = optionalInt(ss[2])
+		performer.Measurements.Hip = optionalInt(ss[3])
+	}
+	if debut := time.Time(info.DebutDate); !debut.IsZero() {
+		year := int32(debut.Year())
+		performer.CareerStartYear = &year
+	}
+	return performer
+}
+
+func newPerformerFromSearchResult(result *model.ActorSearchResult) *Performer {
+	return newPerformer(&model.ActorInfo{
+		ID:       result.ID,
+		Name:     result.Name,
+		Provider: result.Provider,
+		Homepage: result.Homepage,
+		Aliases:  result.Aliases,
+		Images:   result.Images,
+	})
+}
+
+func optional(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func optionalInt(s string) *int32 {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	i := int32(n)
+	return &i
+}
+
+func optionalDate(date datatypes.Date) *string {
+	if t := time.Time(date); !t.IsZero() {
+		return optional(t.Format(time.DateOnly))
+	}
+	return nil
+}
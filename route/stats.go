@@ -0,0 +1,31 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+type statsQuery struct {
+	Limit int `form:"limit"`
+}
+
+func getStats(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &statsQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		stats, err := app.GetStats(query.Limit)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: stats})
+	}
+}
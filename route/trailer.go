@@ -0,0 +1,37 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+)
+
+// getTrailer streams the trailer of the movie as an MP4 video, HLS trailers
+// are remuxed if enabled, see engine.WithTrailerRemuxer.
+func getTrailer(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		uri := &infoUri{}
+		if err := c.ShouldBindUri(uri); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		trailer, err := app.GetMovieTrailer(uri.Provider, uri.ID)
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.Header("Content-Type", "video/mp4")
+		if err = app.WriteMovieTrailer(c.Request.Context(), uri.Provider, trailer, c.Writer); err != nil {
+			if c.Writer.Written() {
+				_ = c.Error(err) // streamed partially, only to be logged.
+				return
+			}
+			c.Writer.Header().Del("Content-Type")
+			abortWithError(c, err)
+		}
+	}
+}
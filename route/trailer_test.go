@@ -0,0 +1,16 @@
+package route
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrailer(t *testing.T) {
+	r := newTestRouter(t)
+
+	// fake movies have no preview videos.
+	assert.Equal(t, http.StatusNotFound, serveJSON(t, r, "/v1/movies/FAKE/FAKE-001/trailer", nil))
+	assert.Equal(t, http.StatusNotFound, serveJSON(t, r, "/v1/movies/UNKNOWN/FAKE-001/trailer", nil))
+}
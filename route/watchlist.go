@@ -0,0 +1,61 @@
+package route
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+
+	"github.com/metatube-community/metatube-sdk-go/engine"
+	"github.com/metatube-community/metatube-sdk-go/model"
+)
+
+type watchlistQuery struct {
+	Kind string `form:"kind" binding:"required"`
+	Name string `form:"name" binding:"required"`
+}
+
+func getWatchlist(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entries, err := app.GetWatchlist()
+		if err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: entries})
+	}
+}
+
+func postWatchlist(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		entry := &model.WatchlistEntry{}
+		if err := c.ShouldBindJSON(entry); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := app.AddWatchlistEntry(entry); err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: entry})
+	}
+}
+
+func deleteWatchlist(app *engine.Engine) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		query := &watchlistQuery{}
+		if err := c.ShouldBindQuery(query); err != nil {
+			abortWithStatusMessage(c, http.StatusBadRequest, err)
+			return
+		}
+
+		if err := app.RemoveWatchlistEntry(query.Kind, query.Name); err != nil {
+			abortWithError(c, err)
+			return
+		}
+
+		c.JSON(http.StatusOK, &responseMessage{Data: query})
+	}
+}